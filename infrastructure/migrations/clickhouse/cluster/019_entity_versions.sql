@@ -0,0 +1,106 @@
+-- Миграция 019: Хранение версий карточек компаний, ИП и состава учредителей (КЛАСТЕР)
+-- Описание: Основные таблицы companies/entrepreneurs/founders — ReplacingMergeTree,
+--           поэтому при слиянии старые версии схлопываются. Для просмотра карточки
+--           "на дату" (asOf) каждая вставка дублируется в таблицы версий, где ключ
+--           сортировки включает дату выписки/версии и версии не вытесняют друг друга.
+
+-- ============================================================================
+-- ТАБЛИЦА: companies_versions (Версии карточек ЮЛ)
+-- ============================================================================
+
+-- Структура колонок совпадает с companies_local, чтобы чтение шло тем же кодом
+CREATE TABLE IF NOT EXISTS egrul.companies_versions_local ON CLUSTER egrul_cluster
+AS egrul.companies_local
+ENGINE = ReplicatedReplacingMergeTree(
+    '/clickhouse/tables/{cluster}/{shard}/companies_versions_local',
+    '{replica}',
+    updated_at
+)
+PARTITION BY toYYYYMM(extract_date)
+ORDER BY (ogrn, extract_date)
+SETTINGS index_granularity = 8192;
+
+CREATE TABLE IF NOT EXISTS egrul.companies_versions ON CLUSTER egrul_cluster
+AS egrul.companies_versions_local
+ENGINE = Distributed(
+    egrul_cluster,
+    egrul,
+    companies_versions_local,
+    cityHash64(ogrn)
+);
+
+-- MV на каждой ноде: вставка в companies_local (шардирована по ogrn) попадает
+-- в companies_versions_local того же шарда
+CREATE MATERIALIZED VIEW IF NOT EXISTS egrul.companies_versions_mv ON CLUSTER egrul_cluster
+TO egrul.companies_versions_local
+AS SELECT * FROM egrul.companies_local;
+
+-- ============================================================================
+-- ТАБЛИЦА: entrepreneurs_versions (Версии карточек ИП)
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS egrul.entrepreneurs_versions_local ON CLUSTER egrul_cluster
+AS egrul.entrepreneurs_local
+ENGINE = ReplicatedReplacingMergeTree(
+    '/clickhouse/tables/{cluster}/{shard}/entrepreneurs_versions_local',
+    '{replica}',
+    updated_at
+)
+PARTITION BY toYYYYMM(extract_date)
+ORDER BY (ogrnip, extract_date)
+SETTINGS index_granularity = 8192;
+
+CREATE TABLE IF NOT EXISTS egrul.entrepreneurs_versions ON CLUSTER egrul_cluster
+AS egrul.entrepreneurs_versions_local
+ENGINE = Distributed(
+    egrul_cluster,
+    egrul,
+    entrepreneurs_versions_local,
+    cityHash64(ogrnip)
+);
+
+CREATE MATERIALIZED VIEW IF NOT EXISTS egrul.entrepreneurs_versions_mv ON CLUSTER egrul_cluster
+TO egrul.entrepreneurs_versions_local
+AS SELECT * FROM egrul.entrepreneurs_local;
+
+-- ============================================================================
+-- ТАБЛИЦА: founders_versions (Состав учредителей по версиям)
+-- ============================================================================
+
+-- Учредители импортируются полным составом вместе с карточкой компании и с той же
+-- version_date, поэтому состав на дату — это все строки с максимальной
+-- version_date, не превышающей version_date выбранной версии компании
+CREATE TABLE IF NOT EXISTS egrul.founders_versions_local ON CLUSTER egrul_cluster
+AS egrul.founders_local
+ENGINE = ReplicatedReplacingMergeTree(
+    '/clickhouse/tables/{cluster}/{shard}/founders_versions_local',
+    '{replica}',
+    updated_at
+)
+PARTITION BY toYYYYMM(version_date)
+ORDER BY (company_ogrn, version_date, founder_type, founder_inn, founder_name)
+SETTINGS index_granularity = 8192;
+
+CREATE TABLE IF NOT EXISTS egrul.founders_versions ON CLUSTER egrul_cluster
+AS egrul.founders_versions_local
+ENGINE = Distributed(
+    egrul_cluster,
+    egrul,
+    founders_versions_local,
+    cityHash64(company_ogrn)
+);
+
+CREATE MATERIALIZED VIEW IF NOT EXISTS egrul.founders_versions_mv ON CLUSTER egrul_cluster
+TO egrul.founders_versions_local
+AS SELECT * FROM egrul.founders_local;
+
+-- ============================================================================
+-- Начальное заполнение текущим состоянием
+-- ============================================================================
+
+-- Более ранние версии к моменту миграции уже схлопнуты, поэтому история
+-- начинается с текущих данных. Повторный запуск безопасен: дубликаты
+-- по ключу сортировки схлопнутся ReplacingMergeTree.
+INSERT INTO egrul.companies_versions SELECT * FROM egrul.companies FINAL;
+INSERT INTO egrul.entrepreneurs_versions SELECT * FROM egrul.entrepreneurs FINAL;
+INSERT INTO egrul.founders_versions SELECT * FROM egrul.founders FINAL;
@@ -0,0 +1,69 @@
+-- Миграция 020: Хранение версий лицензий и филиалов (КЛАСТЕР)
+-- Описание: Для сравнения двух версий карточки компании (companyDiff) нужны составы
+--           лицензий и филиалов на дату каждой версии. Как и в миграции 019,
+--           вставки в основные таблицы дублируются в таблицы версий, где ключ
+--           сортировки включает version_date.
+
+-- ============================================================================
+-- ТАБЛИЦА: licenses_versions (Лицензии по версиям)
+-- ============================================================================
+
+-- Лицензии импортируются полным составом вместе с карточкой и с той же version_date
+CREATE TABLE IF NOT EXISTS egrul.licenses_versions_local ON CLUSTER egrul_cluster
+AS egrul.licenses_local
+ENGINE = ReplicatedReplacingMergeTree(
+    '/clickhouse/tables/{cluster}/{shard}/licenses_versions_local',
+    '{replica}',
+    updated_at
+)
+PARTITION BY toYYYYMM(version_date)
+ORDER BY (entity_ogrn, version_date, entity_type, license_number, activity)
+SETTINGS index_granularity = 8192;
+
+CREATE TABLE IF NOT EXISTS egrul.licenses_versions ON CLUSTER egrul_cluster
+AS egrul.licenses_versions_local
+ENGINE = Distributed(
+    egrul_cluster,
+    egrul,
+    licenses_versions_local,
+    cityHash64(entity_ogrn)
+);
+
+CREATE MATERIALIZED VIEW IF NOT EXISTS egrul.licenses_versions_mv ON CLUSTER egrul_cluster
+TO egrul.licenses_versions_local
+AS SELECT * FROM egrul.licenses_local;
+
+-- ============================================================================
+-- ТАБЛИЦА: branches_versions (Филиалы и представительства по версиям)
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS egrul.branches_versions_local ON CLUSTER egrul_cluster
+AS egrul.branches_local
+ENGINE = ReplicatedReplacingMergeTree(
+    '/clickhouse/tables/{cluster}/{shard}/branches_versions_local',
+    '{replica}',
+    updated_at
+)
+PARTITION BY toYYYYMM(version_date)
+ORDER BY (company_ogrn, version_date, branch_type, branch_kpp, full_address)
+SETTINGS index_granularity = 8192;
+
+CREATE TABLE IF NOT EXISTS egrul.branches_versions ON CLUSTER egrul_cluster
+AS egrul.branches_versions_local
+ENGINE = Distributed(
+    egrul_cluster,
+    egrul,
+    branches_versions_local,
+    cityHash64(company_ogrn)
+);
+
+CREATE MATERIALIZED VIEW IF NOT EXISTS egrul.branches_versions_mv ON CLUSTER egrul_cluster
+TO egrul.branches_versions_local
+AS SELECT * FROM egrul.branches_local;
+
+-- ============================================================================
+-- Начальное заполнение текущим состоянием
+-- ============================================================================
+
+INSERT INTO egrul.licenses_versions SELECT * FROM egrul.licenses FINAL;
+INSERT INTO egrul.branches_versions SELECT * FROM egrul.branches FINAL;
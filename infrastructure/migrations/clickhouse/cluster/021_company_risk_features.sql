@@ -0,0 +1,34 @@
+-- Миграция 021: Предрассчитанные признаки для оценки риска контрагента (КЛАСТЕР)
+-- Описание: Признаки, требующие агрегации по всей базе (сколько компаний по тому же
+--           адресу и у того же руководителя, частота смен руководителя и адреса,
+--           иностранные учредители), считаются заранее, чтобы поле
+--           Company.riskAssessment читало одну строку по ОГРН.
+--           Таблица заполняется скриптом infrastructure/scripts/refresh-risk-features.sh
+--           (make cluster-risk-features) после импорта и детектирования изменений.
+
+CREATE TABLE IF NOT EXISTS egrul.company_risk_features_local ON CLUSTER egrul_cluster
+(
+    ogrn                    String COMMENT 'ОГРН',
+    address_companies       UInt32 DEFAULT 0 COMMENT 'Компаний с тем же адресом регистрации (включая эту)',
+    director_companies      UInt32 DEFAULT 0 COMMENT 'Компаний с тем же ИНН руководителя (включая эту)',
+    director_changes_12m    UInt16 DEFAULT 0 COMMENT 'Смен руководителя за 12 месяцев (company_changes)',
+    address_changes_12m     UInt16 DEFAULT 0 COMMENT 'Смен адреса за 12 месяцев (company_changes)',
+    foreign_founders        UInt16 DEFAULT 0 COMMENT 'Иностранных учредителей',
+    updated_at              DateTime DEFAULT now() COMMENT 'Время расчета'
+)
+ENGINE = ReplicatedReplacingMergeTree(
+    '/clickhouse/tables/{cluster}/{shard}/company_risk_features_local',
+    '{replica}',
+    updated_at
+)
+ORDER BY ogrn
+SETTINGS index_granularity = 8192;
+
+CREATE TABLE IF NOT EXISTS egrul.company_risk_features ON CLUSTER egrul_cluster
+AS egrul.company_risk_features_local
+ENGINE = Distributed(
+    egrul_cluster,
+    egrul,
+    company_risk_features_local,
+    cityHash64(ogrn)
+);
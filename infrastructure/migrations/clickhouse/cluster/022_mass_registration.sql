@@ -0,0 +1,77 @@
+-- Миграция 022: Агрегаты массовых адресов регистрации и массовых руководителей (КЛАСТЕР)
+-- Описание: Сколько компаний (и сколько из них действующих) зарегистрировано по одному
+--           адресу и под одним руководителем. Адрес идентифицируется кодом ФИАС,
+--           а при его отсутствии — нормализованной строкой адреса.
+--           Таблицы полностью пересчитываются скриптом
+--           infrastructure/scripts/refresh-risk-features.sh (make cluster-risk-features).
+--
+-- Ключ адреса (address_key), то же выражение используется в API Gateway
+-- (repository/clickhouse/mass_registration.go):
+--   if(ifNull(fias_id, '') != '', concat('fias:', fias_id),
+--      concat('addr:', trimBoth(replaceRegexpAll(lowerUTF8(ifNull(full_address, '')), '[^0-9a-zа-яё]+', ' '))))
+
+-- ============================================================================
+-- ТАБЛИЦА: mass_addresses (Компаний по адресу регистрации)
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS egrul.mass_addresses_local ON CLUSTER egrul_cluster
+(
+    address_key             String COMMENT 'fias:<ФИАС> или addr:<нормализованный адрес>',
+    fias_id                 Nullable(String) COMMENT 'ФИАС код',
+    full_address            String COMMENT 'Адрес одной строкой (пример написания)',
+    region_code             String DEFAULT '' COMMENT 'Код региона',
+    companies_count         UInt32 COMMENT 'Компаний по адресу',
+    active_count            UInt32 COMMENT 'Действующих компаний по адресу',
+    updated_at              DateTime DEFAULT now() COMMENT 'Время расчета'
+)
+ENGINE = ReplicatedReplacingMergeTree(
+    '/clickhouse/tables/{cluster}/{shard}/mass_addresses_local',
+    '{replica}',
+    updated_at
+)
+ORDER BY address_key
+SETTINGS index_granularity = 8192;
+
+CREATE TABLE IF NOT EXISTS egrul.mass_addresses ON CLUSTER egrul_cluster
+AS egrul.mass_addresses_local
+ENGINE = Distributed(
+    egrul_cluster,
+    egrul,
+    mass_addresses_local,
+    cityHash64(address_key)
+);
+
+ALTER TABLE egrul.mass_addresses_local ON CLUSTER egrul_cluster
+    ADD INDEX IF NOT EXISTS idx_mass_addresses_count companies_count TYPE minmax GRANULARITY 4;
+
+-- ============================================================================
+-- ТАБЛИЦА: mass_directors (Компаний под одним руководителем)
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS egrul.mass_directors_local ON CLUSTER egrul_cluster
+(
+    director_inn            String COMMENT 'ИНН руководителя',
+    director_name           String COMMENT 'ФИО руководителя',
+    companies_count         UInt32 COMMENT 'Компаний под руководством',
+    active_count            UInt32 COMMENT 'Действующих компаний под руководством',
+    updated_at              DateTime DEFAULT now() COMMENT 'Время расчета'
+)
+ENGINE = ReplicatedReplacingMergeTree(
+    '/clickhouse/tables/{cluster}/{shard}/mass_directors_local',
+    '{replica}',
+    updated_at
+)
+ORDER BY director_inn
+SETTINGS index_granularity = 8192;
+
+CREATE TABLE IF NOT EXISTS egrul.mass_directors ON CLUSTER egrul_cluster
+AS egrul.mass_directors_local
+ENGINE = Distributed(
+    egrul_cluster,
+    egrul,
+    mass_directors_local,
+    cityHash64(director_inn)
+);
+
+ALTER TABLE egrul.mass_directors_local ON CLUSTER egrul_cluster
+    ADD INDEX IF NOT EXISTS idx_mass_directors_count companies_count TYPE minmax GRANULARITY 4;
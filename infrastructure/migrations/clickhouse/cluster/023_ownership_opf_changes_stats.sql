@@ -0,0 +1,159 @@
+-- Миграция 023: Агрегаты владельцев, ОПФ и изменений реестра для дашборда (КЛАСТЕР)
+-- Описание: Перенос stats_top_owners, stats_ownership_by_type, stats_companies_by_opf
+--           и stats_changes_by_month из single-node/003 на ReplicatedAggregatingMergeTree
+--           (по образцу миграции 017).
+--
+-- Отличия от single-node/003:
+--   - Владельцы считаются по founders_local: ownership_graph в кластере импортом не заполняется.
+--     В founders хранится только актуальный состав, поэтому is_active не используется.
+--   - В stats_companies_by_opf добавлен region_code для фильтра по региону.
+--   - В stats_changes_by_month сохраняется описание причины (reason_description).
+--
+-- ВАЖНО: после применения заполнить агрегаты через make cluster-fill-mv
+
+-- ============================================================
+-- 1. ТОП ВЛАДЕЛЬЦЕВ ПО КОЛИЧЕСТВУ КОМПАНИЙ
+-- ============================================================
+
+CREATE TABLE IF NOT EXISTS egrul.stats_top_owners_local ON CLUSTER egrul_cluster
+(
+    owner_type              LowCardinality(String),
+    owner_id                String DEFAULT '',
+    owner_inn               String DEFAULT '',
+    owner_name              String,
+    owned_companies_count   AggregateFunction(count),
+    total_share_percent     AggregateFunction(sum, Nullable(Decimal(10, 4))),
+    avg_share_percent       AggregateFunction(avg, Nullable(Decimal(10, 4))),
+    updated_at              DateTime64(3) DEFAULT now64(3)
+)
+ENGINE = ReplicatedAggregatingMergeTree('/clickhouse/tables/{shard}/stats_top_owners', '{replica}')
+PARTITION BY tuple()
+ORDER BY (owner_type, owner_inn, owner_name)
+SETTINGS index_granularity = 8192;
+
+CREATE TABLE IF NOT EXISTS egrul.stats_top_owners ON CLUSTER egrul_cluster AS egrul.stats_top_owners_local
+ENGINE = Distributed('egrul_cluster', 'egrul', 'stats_top_owners_local', rand());
+
+CREATE MATERIALIZED VIEW IF NOT EXISTS egrul.mv_stats_top_owners_local ON CLUSTER egrul_cluster
+TO egrul.stats_top_owners_local
+AS
+SELECT
+    founder_type as owner_type,
+    ifNull(founder_ogrn, '') as owner_id,
+    founder_inn as owner_inn,
+    founder_name as owner_name,
+    countState() as owned_companies_count,
+    sumState(share_percent) as total_share_percent,
+    avgState(share_percent) as avg_share_percent,
+    now64(3) as updated_at
+FROM egrul.founders_local
+GROUP BY owner_type, owner_id, owner_inn, owner_name;
+
+-- ============================================================
+-- 2. СТРУКТУРА ВЛАДЕНИЯ ПО ТИПАМ УЧРЕДИТЕЛЕЙ
+-- ============================================================
+
+CREATE TABLE IF NOT EXISTS egrul.stats_ownership_by_type_local ON CLUSTER egrul_cluster
+(
+    owner_type              LowCardinality(String),
+    count                   AggregateFunction(count),
+    companies               AggregateFunction(uniq, String),
+    avg_share_percent       AggregateFunction(avg, Nullable(Decimal(10, 4))),
+    updated_at              DateTime64(3) DEFAULT now64(3)
+)
+ENGINE = ReplicatedAggregatingMergeTree('/clickhouse/tables/{shard}/stats_ownership_by_type', '{replica}')
+PARTITION BY tuple()
+ORDER BY owner_type
+SETTINGS index_granularity = 8192;
+
+CREATE TABLE IF NOT EXISTS egrul.stats_ownership_by_type ON CLUSTER egrul_cluster AS egrul.stats_ownership_by_type_local
+ENGINE = Distributed('egrul_cluster', 'egrul', 'stats_ownership_by_type_local', rand());
+
+CREATE MATERIALIZED VIEW IF NOT EXISTS egrul.mv_stats_ownership_by_type_local ON CLUSTER egrul_cluster
+TO egrul.stats_ownership_by_type_local
+AS
+SELECT
+    founder_type as owner_type,
+    countState() as count,
+    uniqState(company_ogrn) as companies,
+    avgState(share_percent) as avg_share_percent,
+    now64(3) as updated_at
+FROM egrul.founders_local
+GROUP BY owner_type;
+
+-- ============================================================
+-- 3. СТАТИСТИКА КОМПАНИЙ ПО ОПФ
+-- ============================================================
+
+CREATE TABLE IF NOT EXISTS egrul.stats_companies_by_opf_local ON CLUSTER egrul_cluster
+(
+    opf_code                String,
+    opf_name                SimpleAggregateFunction(any, String),
+    region_code             LowCardinality(String),
+    status                  LowCardinality(String),
+    count                   AggregateFunction(count),
+    total_capital           AggregateFunction(sum, Nullable(Decimal(18, 2))),
+    avg_capital             AggregateFunction(avg, Nullable(Decimal(18, 2))),
+    updated_at              DateTime64(3) DEFAULT now64(3)
+)
+ENGINE = ReplicatedAggregatingMergeTree('/clickhouse/tables/{shard}/stats_companies_by_opf', '{replica}')
+PARTITION BY tuple()
+ORDER BY (opf_code, region_code, status)
+SETTINGS index_granularity = 8192;
+
+CREATE TABLE IF NOT EXISTS egrul.stats_companies_by_opf ON CLUSTER egrul_cluster AS egrul.stats_companies_by_opf_local
+ENGINE = Distributed('egrul_cluster', 'egrul', 'stats_companies_by_opf_local', rand());
+
+CREATE MATERIALIZED VIEW IF NOT EXISTS egrul.mv_stats_companies_by_opf_local ON CLUSTER egrul_cluster
+TO egrul.stats_companies_by_opf_local
+AS
+SELECT
+    ifNull(opf_code, '') as opf_code,
+    any(ifNull(opf_name, '')) as opf_name,
+    ifNull(region_code, '') as region_code,
+    multiIf(
+        status_code IN ('113', '114', '115', '116', '117'), 'bankrupt',
+        termination_date IS NOT NULL OR status_code IN ('101', '105', '106', '107', '113', '114', '115', '116', '117', '701', '702', '801', '802'), 'liquidated',
+        'active'
+    ) as status,
+    countState() as count,
+    sumState(capital_amount) as total_capital,
+    avgState(capital_amount) as avg_capital,
+    now64(3) as updated_at
+FROM egrul.companies_local
+WHERE opf_code IS NOT NULL AND opf_code != ''
+GROUP BY opf_code, region_code, status;
+
+-- ============================================================
+-- 4. ИЗМЕНЕНИЯ РЕЕСТРА (ЗАПИСИ ГРН) ПО МЕСЯЦАМ
+-- ============================================================
+
+CREATE TABLE IF NOT EXISTS egrul.stats_changes_by_month_local ON CLUSTER egrul_cluster
+(
+    entity_type             LowCardinality(String),
+    change_month            Date,
+    reason_code             String DEFAULT '',
+    reason_description      SimpleAggregateFunction(any, String),
+    count                   AggregateFunction(count),
+    updated_at              DateTime64(3) DEFAULT now64(3)
+)
+ENGINE = ReplicatedAggregatingMergeTree('/clickhouse/tables/{shard}/stats_changes_by_month', '{replica}')
+PARTITION BY toYear(change_month)
+ORDER BY (entity_type, change_month, reason_code)
+SETTINGS index_granularity = 8192;
+
+CREATE TABLE IF NOT EXISTS egrul.stats_changes_by_month ON CLUSTER egrul_cluster AS egrul.stats_changes_by_month_local
+ENGINE = Distributed('egrul_cluster', 'egrul', 'stats_changes_by_month_local', rand());
+
+CREATE MATERIALIZED VIEW IF NOT EXISTS egrul.mv_stats_changes_by_month_local ON CLUSTER egrul_cluster
+TO egrul.stats_changes_by_month_local
+AS
+SELECT
+    entity_type,
+    toStartOfMonth(grn_date) as change_month,
+    ifNull(reason_code, '') as reason_code,
+    any(ifNull(reason_description, '')) as reason_description,
+    countState() as count,
+    now64(3) as updated_at
+FROM egrul.company_history_local
+GROUP BY entity_type, change_month, reason_code;
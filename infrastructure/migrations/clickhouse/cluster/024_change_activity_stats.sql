@@ -0,0 +1,145 @@
+-- Миграция 024: Аналитика активности изменений (КЛАСТЕР)
+-- Описание: Дополняет таблицы отслеживания изменений из миграции 012:
+--   - region_code в company_changes / entrepreneur_changes (change-detection-service
+--     уже передает его при вставке, но колонки в кластерной схеме не было);
+--   - company_most_changes_mv: ТОП компаний по числу изменений за месяц
+--     (в single-node/012 есть, в кластерную версию не был перенесен);
+--   - changes_by_region_mv: значимые изменения по регионам, месяцам и типам
+--     (например, смены руководителей по регионам).
+--
+-- Таблицы создаются в базе default рядом с таблицами миграции 012.
+
+-- ============================================================================
+-- 1. region_code в таблицах изменений
+-- ============================================================================
+
+ALTER TABLE company_changes_local ON CLUSTER egrul_cluster
+    ADD COLUMN IF NOT EXISTS region_code LowCardinality(String) DEFAULT '' AFTER company_name;
+
+ALTER TABLE company_changes ON CLUSTER egrul_cluster
+    ADD COLUMN IF NOT EXISTS region_code LowCardinality(String) DEFAULT '' AFTER company_name;
+
+ALTER TABLE entrepreneur_changes_local ON CLUSTER egrul_cluster
+    ADD COLUMN IF NOT EXISTS region_code LowCardinality(String) DEFAULT '' AFTER full_name;
+
+ALTER TABLE entrepreneur_changes ON CLUSTER egrul_cluster
+    ADD COLUMN IF NOT EXISTS region_code LowCardinality(String) DEFAULT '' AFTER full_name;
+
+-- ============================================================================
+-- 2. ТОП компаний с наибольшим количеством изменений (по месяцам)
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS company_most_changes_mv_local ON CLUSTER egrul_cluster
+(
+    detected_month Date,
+    ogrn String,
+    company_name String,
+    total_changes UInt64,
+    significant_changes_count UInt64
+)
+ENGINE = ReplicatedSummingMergeTree('/clickhouse/tables/{shard}/company_most_changes_mv_local', '{replica}')
+PARTITION BY toYYYYMM(detected_month)
+ORDER BY (detected_month, ogrn);
+
+CREATE MATERIALIZED VIEW IF NOT EXISTS company_most_changes_mv_insert ON CLUSTER egrul_cluster
+TO company_most_changes_mv_local
+AS
+SELECT
+    toStartOfMonth(detected_at) AS detected_month,
+    ogrn,
+    any(company_name) AS company_name,
+    count() AS total_changes,
+    sum(is_significant) AS significant_changes_count
+FROM company_changes_local
+GROUP BY detected_month, ogrn;
+
+CREATE TABLE IF NOT EXISTS company_most_changes_mv ON CLUSTER egrul_cluster AS company_most_changes_mv_local
+ENGINE = Distributed(egrul_cluster, default, company_most_changes_mv_local, rand());
+
+-- ============================================================================
+-- 3. Значимые изменения по регионам
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS changes_by_region_mv_local ON CLUSTER egrul_cluster
+(
+    change_month Date,
+    region_code LowCardinality(String),
+    entity_type LowCardinality(String),
+    change_type String,
+    changes_count UInt64,
+    affected_entities_count AggregateFunction(uniq, String)
+)
+ENGINE = ReplicatedSummingMergeTree('/clickhouse/tables/{shard}/changes_by_region_mv_local', '{replica}')
+PARTITION BY toYYYYMM(change_month)
+ORDER BY (change_month, region_code, entity_type, change_type);
+
+CREATE MATERIALIZED VIEW IF NOT EXISTS company_changes_by_region_mv_insert ON CLUSTER egrul_cluster
+TO changes_by_region_mv_local
+AS
+SELECT
+    toStartOfMonth(detected_at) AS change_month,
+    region_code,
+    'company' AS entity_type,
+    change_type,
+    count() AS changes_count,
+    uniqState(ogrn) AS affected_entities_count
+FROM company_changes_local
+WHERE is_significant = 1
+GROUP BY change_month, region_code, change_type;
+
+CREATE MATERIALIZED VIEW IF NOT EXISTS entrepreneur_changes_by_region_mv_insert ON CLUSTER egrul_cluster
+TO changes_by_region_mv_local
+AS
+SELECT
+    toStartOfMonth(detected_at) AS change_month,
+    region_code,
+    'entrepreneur' AS entity_type,
+    change_type,
+    count() AS changes_count,
+    uniqState(ogrnip) AS affected_entities_count
+FROM entrepreneur_changes_local
+WHERE is_significant = 1
+GROUP BY change_month, region_code, change_type;
+
+CREATE TABLE IF NOT EXISTS changes_by_region_mv ON CLUSTER egrul_cluster AS changes_by_region_mv_local
+ENGINE = Distributed(egrul_cluster, default, changes_by_region_mv_local, rand());
+
+-- ============================================================================
+-- 4. Заполнение по уже накопленным изменениям
+-- ============================================================================
+-- region_code для ранее сохраненных изменений пуст; такие изменения попадают
+-- в регион '' и отбрасываются в региональной разбивке.
+
+INSERT INTO company_most_changes_mv
+SELECT
+    toStartOfMonth(detected_at) AS detected_month,
+    ogrn,
+    any(company_name) AS company_name,
+    count() AS total_changes,
+    sum(is_significant) AS significant_changes_count
+FROM company_changes
+GROUP BY detected_month, ogrn;
+
+INSERT INTO changes_by_region_mv
+SELECT
+    toStartOfMonth(detected_at) AS change_month,
+    region_code,
+    'company' AS entity_type,
+    change_type,
+    count() AS changes_count,
+    uniqState(ogrn) AS affected_entities_count
+FROM company_changes
+WHERE is_significant = 1
+GROUP BY change_month, region_code, change_type;
+
+INSERT INTO changes_by_region_mv
+SELECT
+    toStartOfMonth(detected_at) AS change_month,
+    region_code,
+    'entrepreneur' AS entity_type,
+    change_type,
+    count() AS changes_count,
+    uniqState(ogrnip) AS affected_entities_count
+FROM entrepreneur_changes
+WHERE is_significant = 1
+GROUP BY change_month, region_code, change_type;
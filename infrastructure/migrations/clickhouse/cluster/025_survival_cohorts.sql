@@ -0,0 +1,30 @@
+-- Миграция 025: Когорты выживаемости компаний и ИП (КЛАСТЕР)
+-- Описание: Предрасчитанное распределение сроков жизни по когортам регистрации
+--           (квартал регистрации x регион x основной ОКВЭД) для запроса survivalCohorts.
+--
+-- Для прекращенных субъектов хранится число полных лет от регистрации до прекращения
+-- (не более 10), для действующих - только признак. Доля выживших через N лет
+-- считается при запросе: действующие + прекращенные не раньше чем через N лет.
+--
+-- Materialized View не используется: companies/entrepreneurs - ReplacingMergeTree,
+-- и прекращение приходит новой версией записи, поэтому MV посчитал бы субъект дважды.
+-- Таблица пересчитывается из FINAL в make cluster-fill-mv.
+
+CREATE TABLE IF NOT EXISTS egrul.stats_survival_cohorts_local ON CLUSTER egrul_cluster
+(
+    entity_type             LowCardinality(String),
+    cohort_quarter          Date,
+    region_code             LowCardinality(String),
+    okved_main_code         String,
+    is_terminated           UInt8,
+    lifetime_years          UInt8,
+    entities_count          UInt64,
+    updated_at              DateTime64(3) DEFAULT now64(3)
+)
+ENGINE = ReplicatedSummingMergeTree('/clickhouse/tables/{shard}/stats_survival_cohorts', '{replica}', entities_count)
+PARTITION BY toYear(cohort_quarter)
+ORDER BY (entity_type, cohort_quarter, region_code, okved_main_code, is_terminated, lifetime_years)
+SETTINGS index_granularity = 8192;
+
+CREATE TABLE IF NOT EXISTS egrul.stats_survival_cohorts ON CLUSTER egrul_cluster AS egrul.stats_survival_cohorts_local
+ENGINE = Distributed('egrul_cluster', 'egrul', 'stats_survival_cohorts_local', rand());
@@ -0,0 +1,70 @@
+-- Миграция 026: Справочник ОКВЭД2 (КЛАСТЕР)
+-- Описание: Иерархический классификатор ОКВЭД2 (раздел -> класс -> подкласс -> группа ->
+--           подгруппа -> вид) для запроса okvedTree и сводной статистики по уровням.
+--
+-- Справочник небольшой, поэтому он не шардируется: путь в ZooKeeper без {shard},
+-- и каждая нода хранит полную копию. Читать его нужно из okved_classifier_local
+-- (Distributed-таблица вернула бы строки каждого шарда).
+--
+-- Заполнение:
+--   - разделы A-U добавляются этой миграцией;
+--   - коды, встречающиеся в данных, выводятся скриптом import-okved-classifier.sh
+--     (make cluster-import-okved) вместе со всеми предками;
+--   - официальный классификатор (CSV "код;наименование") загружается тем же скриптом
+--     через OKVED_CLASSIFIER_CSV и имеет приоритет над выведенными наименованиями.
+--
+-- Уровни по форме кода: 62 - CLASS, 62.0 - SUBCLASS, 62.01 - GROUP,
+-- 62.02.1 - SUBGROUP, 62.02.11 - KIND.
+
+CREATE TABLE IF NOT EXISTS egrul.okved_classifier_local ON CLUSTER egrul_cluster
+(
+    code            String COMMENT 'Код ОКВЭД2 или буква раздела',
+    name            String COMMENT 'Наименование',
+    parent_code     String COMMENT 'Код родителя (пусто для разделов)',
+    level           LowCardinality(String) COMMENT 'SECTION, CLASS, SUBCLASS, GROUP, SUBGROUP, KIND',
+    section         LowCardinality(String) COMMENT 'Буква раздела',
+    source          UInt8 COMMENT '1 - выведено из данных ЕГРЮЛ/ЕГРИП, 2 - официальный классификатор',
+    updated_at      DateTime64(3) DEFAULT now64(3)
+)
+ENGINE = ReplicatedReplacingMergeTree('/clickhouse/tables/okved_classifier', '{replica}', source)
+ORDER BY code
+SETTINGS index_granularity = 8192;
+
+INSERT INTO egrul.okved_classifier_local (code, name, parent_code, level, section, source) VALUES
+    ('A', 'Сельское, лесное хозяйство, охота, рыболовство и рыбоводство', '', 'SECTION', 'A', 2),
+    ('B', 'Добыча полезных ископаемых', '', 'SECTION', 'B', 2),
+    ('C', 'Обрабатывающие производства', '', 'SECTION', 'C', 2),
+    ('D', 'Обеспечение электрической энергией, газом и паром; кондиционирование воздуха', '', 'SECTION', 'D', 2),
+    ('E', 'Водоснабжение; водоотведение, организация сбора и утилизации отходов, деятельность по ликвидации загрязнений', '', 'SECTION', 'E', 2),
+    ('F', 'Строительство', '', 'SECTION', 'F', 2),
+    ('G', 'Торговля оптовая и розничная; ремонт автотранспортных средств и мотоциклов', '', 'SECTION', 'G', 2),
+    ('H', 'Транспортировка и хранение', '', 'SECTION', 'H', 2),
+    ('I', 'Деятельность гостиниц и предприятий общественного питания', '', 'SECTION', 'I', 2),
+    ('J', 'Деятельность в области информации и связи', '', 'SECTION', 'J', 2),
+    ('K', 'Деятельность финансовая и страховая', '', 'SECTION', 'K', 2),
+    ('L', 'Деятельность по операциям с недвижимым имуществом', '', 'SECTION', 'L', 2),
+    ('M', 'Деятельность профессиональная, научная и техническая', '', 'SECTION', 'M', 2),
+    ('N', 'Деятельность административная и сопутствующие дополнительные услуги', '', 'SECTION', 'N', 2),
+    ('O', 'Государственное управление и обеспечение военной безопасности; социальное обеспечение', '', 'SECTION', 'O', 2),
+    ('P', 'Образование', '', 'SECTION', 'P', 2),
+    ('Q', 'Деятельность в области здравоохранения и социальных услуг', '', 'SECTION', 'Q', 2),
+    ('R', 'Деятельность в области культуры, спорта, организации досуга и развлечений', '', 'SECTION', 'R', 2),
+    ('S', 'Предоставление прочих видов услуг', '', 'SECTION', 'S', 2),
+    ('T', 'Деятельность домашних хозяйств как работодателей; недифференцированная деятельность частных домашних хозяйств', '', 'SECTION', 'T', 2),
+    ('U', 'Деятельность экстерриториальных организаций и органов', '', 'SECTION', 'U', 2);
+
+-- Словарь наименований для dictGet в сводной статистике (на каждой ноде)
+CREATE DICTIONARY IF NOT EXISTS egrul.okved_dict ON CLUSTER egrul_cluster
+(
+    code        String,
+    name        String,
+    parent_code String,
+    level       String,
+    section     String
+)
+PRIMARY KEY code
+SOURCE(CLICKHOUSE(
+    QUERY 'SELECT code, name, parent_code, level, section FROM egrul.okved_classifier_local FINAL'
+))
+LAYOUT(COMPLEX_KEY_HASHED())
+LIFETIME(MIN 300 MAX 600);
@@ -0,0 +1,39 @@
+-- Миграция 027: Журнал аудита действий пользователей (КЛАСТЕР)
+-- Описание: Append-only журнал входов, просмотров карточек, выгрузок, изменений
+--           подписок и избранного, использования API ключей. Пишется API Gateway
+--           пачками (service/audit.go) в Distributed таблицу, читается
+--           администраторами (query auditLog).
+--
+-- Срок хранения задается в API Gateway (AUDIT_RETENTION_DAYS) и записывается в каждую
+-- строку (retention_days): изменение настройки не требует ALTER TABLE и действует
+-- на новые записи.
+
+CREATE TABLE IF NOT EXISTS egrul.audit_log_local ON CLUSTER egrul_cluster
+(
+    event_id                UUID DEFAULT generateUUIDv4() COMMENT 'ID события',
+    event_time              DateTime64(3) DEFAULT now64(3) COMMENT 'Время события',
+    user_id                 String DEFAULT '' COMMENT 'ID пользователя (пусто для анонимных действий)',
+    user_email              String DEFAULT '' COMMENT 'Email пользователя; для LOGIN_FAILED - введенный при входе',
+    api_key_id              String DEFAULT '' COMMENT 'ID API ключа (пусто для запросов с JWT)',
+    ip_address              String DEFAULT '' COMMENT 'IP клиента',
+    user_agent              String DEFAULT '' COMMENT 'User-Agent клиента',
+    action                  LowCardinality(String) COMMENT 'Действие пользователя (enum AuditAction в GraphQL схеме)',
+    entity_type             LowCardinality(String) DEFAULT '' COMMENT 'Тип объекта: company, entrepreneur, subscription, favorite, api_key',
+    entity_id               String DEFAULT '' COMMENT 'ID объекта (ОГРН, ID подписки и т.д.)',
+    success                 UInt8 DEFAULT 1 COMMENT 'Флаг успеха (1 = выполнено, 0 = отказ)',
+    details                 String DEFAULT '{}' COMMENT 'Дополнительные данные события в JSON формате',
+    retention_days          UInt16 DEFAULT 365 COMMENT 'Срок хранения записи в днях (TTL)'
+)
+ENGINE = ReplicatedMergeTree('/clickhouse/tables/{shard}/audit_log', '{replica}')
+PARTITION BY toYYYYMM(event_time)
+ORDER BY (event_time, user_id, action)
+TTL toDateTime(event_time) + toIntervalDay(retention_days)
+SETTINGS index_granularity = 8192;
+
+CREATE TABLE IF NOT EXISTS egrul.audit_log ON CLUSTER egrul_cluster AS egrul.audit_log_local
+ENGINE = Distributed('egrul_cluster', 'egrul', 'audit_log_local', rand());
+
+ALTER TABLE egrul.audit_log_local ON CLUSTER egrul_cluster
+    ADD INDEX IF NOT EXISTS idx_audit_log_user user_id TYPE bloom_filter GRANULARITY 4;
+ALTER TABLE egrul.audit_log_local ON CLUSTER egrul_cluster
+    ADD INDEX IF NOT EXISTS idx_audit_log_action action TYPE set(32) GRANULARITY 4;
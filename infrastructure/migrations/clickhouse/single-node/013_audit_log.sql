@@ -0,0 +1,50 @@
+-- Миграция 013: Журнал аудита действий пользователей
+-- Описание: Append-only журнал входов, просмотров карточек, выгрузок, изменений
+--           подписок и избранного, использования API ключей. Пишется API Gateway
+--           пачками (service/audit.go), читается администраторами (query auditLog).
+--
+-- Срок хранения задается в API Gateway (AUDIT_RETENTION_DAYS) и записывается в каждую
+-- строку (retention_days): изменение настройки не требует ALTER TABLE и действует
+-- на новые записи.
+
+-- ============================================================================
+-- Таблица: audit_log
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS audit_log (
+    event_id UUID DEFAULT generateUUIDv4(),         -- ID события
+    event_time DateTime64(3) DEFAULT now64(3),      -- Время события
+
+    -- Кто
+    user_id String DEFAULT '',                      -- ID пользователя (пусто для анонимных действий)
+    user_email String DEFAULT '',                   -- Email пользователя (для неудачных входов - введенный)
+    api_key_id String DEFAULT '',                   -- ID API ключа, если запрос выполнен по ключу
+    ip_address String DEFAULT '',                   -- IP клиента
+    user_agent String DEFAULT '',                   -- User-Agent клиента
+
+    -- Что
+    action LowCardinality(String),                  -- Действие: LOGIN, LOGIN_FAILED, COMPANY_VIEW, EXPORT, ...
+    entity_type LowCardinality(String) DEFAULT '',  -- Тип объекта: company, entrepreneur, subscription, favorite, api_key
+    entity_id String DEFAULT '',                    -- ID объекта (ОГРН, ID подписки и т.д.)
+    success UInt8 DEFAULT 1,                        -- 1 - действие выполнено, 0 - отказ
+    details String DEFAULT '{}',                    -- Дополнительные данные (JSON)
+
+    -- Срок хранения записи
+    retention_days UInt16 DEFAULT 365
+)
+ENGINE = MergeTree()
+PARTITION BY toYYYYMM(event_time)
+ORDER BY (event_time, user_id, action)
+TTL toDateTime(event_time) + toIntervalDay(retention_days)
+SETTINGS index_granularity = 8192;
+
+ALTER TABLE audit_log ADD INDEX IF NOT EXISTS idx_audit_log_user user_id TYPE bloom_filter GRANULARITY 4;
+ALTER TABLE audit_log ADD INDEX IF NOT EXISTS idx_audit_log_action action TYPE set(32) GRANULARITY 4;
+
+-- Комментарии к таблице
+ALTER TABLE audit_log COMMENT COLUMN action 'Действие пользователя (enum AuditAction в GraphQL схеме)';
+ALTER TABLE audit_log COMMENT COLUMN user_email 'Email пользователя; для LOGIN_FAILED - введенный при входе';
+ALTER TABLE audit_log COMMENT COLUMN api_key_id 'ID API ключа (пусто для запросов с JWT)';
+ALTER TABLE audit_log COMMENT COLUMN success 'Флаг успеха (1 = выполнено, 0 = отказ)';
+ALTER TABLE audit_log COMMENT COLUMN details 'Дополнительные данные события в JSON формате';
+ALTER TABLE audit_log COMMENT COLUMN retention_days 'Срок хранения записи в днях (TTL)';
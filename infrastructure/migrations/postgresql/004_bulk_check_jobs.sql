@@ -0,0 +1,38 @@
+-- Миграция 004: Задания пакетной проверки контрагентов
+-- Описание: Пользователь загружает CSV/XLSX со списком ИНН/ОГРН, задание
+--           обрабатывается асинхронно пулом воркеров API Gateway, результат
+--           сохраняется в JSONB и выгружается в CSV/XLSX по запросу
+
+CREATE TABLE subscriptions.bulk_check_jobs (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES subscriptions.users(id) ON DELETE CASCADE,
+
+    -- Статус выполнения
+    status VARCHAR(20) DEFAULT 'pending' NOT NULL
+        CHECK (status IN ('pending', 'running', 'completed', 'failed')),
+    file_name TEXT,
+    output_format VARCHAR(10) DEFAULT 'csv' NOT NULL CHECK (output_format IN ('csv', 'xlsx')),
+
+    -- Входные идентификаторы и результаты проверки
+    identifiers TEXT[] NOT NULL,
+    results JSONB,
+    total_rows INT NOT NULL,
+    processed_rows INT DEFAULT 0 NOT NULL,
+    found_rows INT DEFAULT 0 NOT NULL,
+    error_message TEXT,
+
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW() NOT NULL,
+    started_at TIMESTAMP WITH TIME ZONE,
+    finished_at TIMESTAMP WITH TIME ZONE
+);
+
+-- Индексы
+CREATE INDEX idx_bulk_check_jobs_user_id ON subscriptions.bulk_check_jobs(user_id, created_at DESC);
+CREATE INDEX idx_bulk_check_jobs_unfinished ON subscriptions.bulk_check_jobs(created_at)
+    WHERE status IN ('pending', 'running');
+
+-- Комментарии
+COMMENT ON TABLE subscriptions.bulk_check_jobs IS 'Асинхронные задания пакетной проверки контрагентов';
+COMMENT ON COLUMN subscriptions.bulk_check_jobs.identifiers IS 'Нормализованные ИНН/ОГРН/ОГРНИП из загруженного файла';
+COMMENT ON COLUMN subscriptions.bulk_check_jobs.results IS 'Результаты проверки по каждой строке (массив JSON объектов)';
+COMMENT ON COLUMN subscriptions.bulk_check_jobs.processed_rows IS 'Количество обработанных строк (для отображения прогресса)';
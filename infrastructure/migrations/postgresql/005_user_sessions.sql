@@ -0,0 +1,42 @@
+-- Миграция 005: Сессии пользователей и refresh токены
+-- Описание: Вход создает сессию с короткоживущим access JWT и refresh токеном.
+--           Refresh токен одноразовый: при обновлении он помечается использованным
+--           и выдается новый. Повторное предъявление использованного токена
+--           означает его кражу - сессия отзывается целиком.
+
+CREATE TABLE subscriptions.user_sessions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES subscriptions.users(id) ON DELETE CASCADE,
+
+    -- Устройство, с которого выполнен вход / последнее обновление
+    user_agent TEXT,
+    ip_address VARCHAR(45),
+
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW() NOT NULL,
+    last_used_at TIMESTAMP WITH TIME ZONE DEFAULT NOW() NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+
+    -- Отзыв сессии (logout, revokeSession, повторное использование токена)
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    revoked_reason VARCHAR(50)
+);
+
+CREATE TABLE subscriptions.refresh_tokens (
+    token_hash VARCHAR(64) PRIMARY KEY,
+    session_id UUID NOT NULL REFERENCES subscriptions.user_sessions(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW() NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    used_at TIMESTAMP WITH TIME ZONE
+);
+
+-- Индексы
+CREATE INDEX idx_user_sessions_user_id ON subscriptions.user_sessions(user_id, last_used_at DESC)
+    WHERE revoked_at IS NULL;
+CREATE INDEX idx_refresh_tokens_session_id ON subscriptions.refresh_tokens(session_id);
+
+-- Комментарии
+COMMENT ON TABLE subscriptions.user_sessions IS 'Сессии пользователей (устройства, с которых выполнен вход)';
+COMMENT ON COLUMN subscriptions.user_sessions.expires_at IS 'Срок действия текущего refresh токена сессии';
+COMMENT ON COLUMN subscriptions.user_sessions.revoked_reason IS 'Причина отзыва: logout, revoked, refresh_token_reuse, password_changed';
+COMMENT ON TABLE subscriptions.refresh_tokens IS 'Выданные refresh токены (хранится SHA-256 хеш)';
+COMMENT ON COLUMN subscriptions.refresh_tokens.used_at IS 'Время обмена на новый токен; повторное предъявление - признак кражи';
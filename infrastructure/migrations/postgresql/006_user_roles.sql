@@ -0,0 +1,17 @@
+-- Миграция 006: Роли пользователей
+-- Описание: Роль хранится в users и передается в JWT. Роли упорядочены:
+--           user < analyst < admin, старшая роль включает права младших.
+--           Первого администратора назначают вручную:
+--           UPDATE subscriptions.users SET role = 'admin' WHERE email = '...';
+
+ALTER TABLE subscriptions.users
+    ADD COLUMN role VARCHAR(20) DEFAULT 'user' NOT NULL
+        CHECK (role IN ('user', 'analyst', 'admin'));
+
+CREATE INDEX idx_users_role ON subscriptions.users(role)
+    WHERE role <> 'user';
+
+COMMENT ON COLUMN subscriptions.users.role IS 'Роль пользователя: user, analyst, admin';
+
+COMMENT ON COLUMN subscriptions.user_sessions.revoked_reason IS
+    'Причина отзыва: logout, revoked, refresh_token_reuse, password_changed, role_changed, user_deactivated';
@@ -0,0 +1,34 @@
+-- Миграция 007: API ключи для машинных клиентов
+-- Описание: Пользователь выпускает именованные ключи с набором scopes, сроком
+--           действия и списком разрешенных IP. Ключ передается в заголовке
+--           X-API-Key; в БД хранится только SHA-256 хеш, для отображения -
+--           префикс ключа.
+
+CREATE TABLE subscriptions.api_keys (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES subscriptions.users(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+
+    key_prefix VARCHAR(20) NOT NULL,
+    key_hash VARCHAR(64) NOT NULL UNIQUE,
+
+    -- Разрешения: read:companies, read:stats, manage:subscriptions
+    scopes TEXT[] NOT NULL,
+    -- IP адреса и CIDR подсети; пустой список - без ограничений
+    ip_allowlist TEXT[] DEFAULT '{}' NOT NULL,
+    -- Лимит запросов в сутки (UTC); NULL - без лимита
+    daily_quota INTEGER CHECK (daily_quota IS NULL OR daily_quota > 0),
+
+    expires_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW() NOT NULL,
+    last_used_at TIMESTAMP WITH TIME ZONE,
+    request_count BIGINT DEFAULT 0 NOT NULL,
+    revoked_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_api_keys_user ON subscriptions.api_keys(user_id, created_at DESC)
+    WHERE revoked_at IS NULL;
+
+COMMENT ON TABLE subscriptions.api_keys IS 'API ключи пользователей для машинных клиентов';
+COMMENT ON COLUMN subscriptions.api_keys.key_hash IS 'SHA-256 хеш ключа (hex); сам ключ показывается один раз при создании';
+COMMENT ON COLUMN subscriptions.api_keys.request_count IS 'Число запросов с ключом (обновляется пачками)';
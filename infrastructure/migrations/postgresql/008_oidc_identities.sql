@@ -0,0 +1,18 @@
+-- Миграция 008: Вход через корпоративный OpenID Connect провайдер (SSO)
+-- Описание: Пользователь SSO связан с учетной записью провайдера парой
+--           (issuer, subject) и создается при первом входе. У пользователей,
+--           созданных через SSO, нет пароля: password_hash пустой, вход по
+--           паролю для них невозможен.
+
+ALTER TABLE subscriptions.users
+    ADD COLUMN oidc_issuer VARCHAR(255),
+    ADD COLUMN oidc_subject VARCHAR(255),
+    ADD CONSTRAINT users_oidc_identity_check
+        CHECK ((oidc_issuer IS NULL) = (oidc_subject IS NULL));
+
+CREATE UNIQUE INDEX idx_users_oidc_identity ON subscriptions.users(oidc_issuer, oidc_subject)
+    WHERE oidc_subject IS NOT NULL;
+
+COMMENT ON COLUMN subscriptions.users.oidc_issuer IS 'Issuer OIDC провайдера, через который связана учетная запись';
+COMMENT ON COLUMN subscriptions.users.oidc_subject IS 'Идентификатор пользователя у OIDC провайдера (claim sub)';
+COMMENT ON COLUMN subscriptions.users.password_hash IS 'Bcrypt hash пароля; пустой у пользователей, созданных через SSO';
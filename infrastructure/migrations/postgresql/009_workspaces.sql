@@ -0,0 +1,101 @@
+-- Миграция 009: Рабочие пространства команд
+-- Описание: Пространство объединяет пользователей с ролями owner > editor >
+--           viewer. Избранное и подписки могут принадлежать пространству
+--           (workspace_id), user_id в них - автор записи. Заметки о компаниях
+--           и ИП видны всем участникам. Уведомления по подписке пространства
+--           получает каждый участник по своим каналам (workspace_members.notification_channels).
+
+CREATE TABLE subscriptions.workspaces (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(100) NOT NULL,
+    created_by UUID REFERENCES subscriptions.users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW() NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW() NOT NULL
+);
+
+CREATE TRIGGER trigger_update_workspaces_updated_at
+    BEFORE UPDATE ON subscriptions.workspaces
+    FOR EACH ROW
+    EXECUTE FUNCTION subscriptions.update_updated_at_column();
+
+CREATE TABLE subscriptions.workspace_members (
+    workspace_id UUID NOT NULL REFERENCES subscriptions.workspaces(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES subscriptions.users(id) ON DELETE CASCADE,
+    role VARCHAR(20) NOT NULL CHECK (role IN ('owner', 'editor', 'viewer')),
+    notification_channels JSONB DEFAULT '{"email": true}'::jsonb NOT NULL,
+    joined_at TIMESTAMP WITH TIME ZONE DEFAULT NOW() NOT NULL,
+
+    PRIMARY KEY (workspace_id, user_id)
+);
+
+CREATE INDEX idx_workspace_members_user ON subscriptions.workspace_members(user_id);
+
+COMMENT ON TABLE subscriptions.workspaces IS 'Рабочие пространства команд с общим избранным, подписками и заметками';
+COMMENT ON TABLE subscriptions.workspace_members IS 'Участники рабочих пространств';
+COMMENT ON COLUMN subscriptions.workspace_members.role IS 'Роль участника: owner (управляет участниками), editor (изменяет данные), viewer (только просмотр)';
+COMMENT ON COLUMN subscriptions.workspace_members.notification_channels IS 'Каналы, по которым участник получает уведомления по подпискам пространства';
+
+-- ============================================================================
+-- Избранное и подписки пространства
+-- ============================================================================
+
+ALTER TABLE subscriptions.favorites
+    ADD COLUMN workspace_id UUID REFERENCES subscriptions.workspaces(id) ON DELETE CASCADE;
+
+-- Личное избранное уникально для пользователя, общее - для пространства
+ALTER TABLE subscriptions.favorites DROP CONSTRAINT unique_favorite_entity;
+CREATE UNIQUE INDEX idx_favorites_personal_entity
+    ON subscriptions.favorites(user_id, entity_type, entity_id)
+    WHERE workspace_id IS NULL;
+CREATE UNIQUE INDEX idx_favorites_workspace_entity
+    ON subscriptions.favorites(workspace_id, entity_type, entity_id)
+    WHERE workspace_id IS NOT NULL;
+
+COMMENT ON COLUMN subscriptions.favorites.workspace_id IS 'Пространство, которому принадлежит запись; NULL - личное избранное';
+
+ALTER TABLE subscriptions.entity_subscriptions
+    ADD COLUMN workspace_id UUID REFERENCES subscriptions.workspaces(id) ON DELETE CASCADE;
+
+ALTER TABLE subscriptions.entity_subscriptions DROP CONSTRAINT unique_user_entity;
+CREATE UNIQUE INDEX idx_subscriptions_personal_entity
+    ON subscriptions.entity_subscriptions(user_id, entity_type, entity_id)
+    WHERE workspace_id IS NULL;
+CREATE UNIQUE INDEX idx_subscriptions_workspace_entity
+    ON subscriptions.entity_subscriptions(workspace_id, entity_type, entity_id)
+    WHERE workspace_id IS NOT NULL;
+
+COMMENT ON COLUMN subscriptions.entity_subscriptions.workspace_id IS 'Пространство, которому принадлежит подписка; NULL - личная подписка';
+
+-- Подписка пространства уведомляет нескольких получателей: идемпотентность
+-- отправки - по получателю и каналу
+ALTER TABLE subscriptions.notification_log DROP CONSTRAINT unique_notification_per_change;
+ALTER TABLE subscriptions.notification_log
+    ADD CONSTRAINT unique_notification_per_recipient
+        UNIQUE (subscription_id, change_event_id, channel, recipient);
+
+-- ============================================================================
+-- Заметки о компаниях и ИП
+-- ============================================================================
+
+CREATE TABLE subscriptions.entity_notes (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    workspace_id UUID NOT NULL REFERENCES subscriptions.workspaces(id) ON DELETE CASCADE,
+    entity_type VARCHAR(20) NOT NULL CHECK (entity_type IN ('company', 'entrepreneur')),
+    entity_id VARCHAR(15) NOT NULL,
+    -- Заметка остается в пространстве после удаления автора
+    author_id UUID REFERENCES subscriptions.users(id) ON DELETE SET NULL,
+    body TEXT NOT NULL CHECK (length(body) > 0),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW() NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW() NOT NULL
+);
+
+CREATE INDEX idx_entity_notes_entity
+    ON subscriptions.entity_notes(workspace_id, entity_type, entity_id, created_at DESC);
+
+CREATE TRIGGER trigger_update_entity_notes_updated_at
+    BEFORE UPDATE ON subscriptions.entity_notes
+    FOR EACH ROW
+    EXECUTE FUNCTION subscriptions.update_updated_at_column();
+
+COMMENT ON TABLE subscriptions.entity_notes IS 'Заметки участников пространства о компаниях и ИП';
+COMMENT ON COLUMN subscriptions.entity_notes.author_id IS 'Автор заметки; NULL - учетная запись автора удалена';
@@ -0,0 +1,17 @@
+-- Миграция 010: Папки и теги избранного
+-- Описание: Пользователь раскладывает личное избранное по папкам (одна папка
+--           на запись) и помечает тегами. Папка хранится строкой: она
+--           существует, пока в ней есть записи.
+
+ALTER TABLE subscriptions.favorites
+    ADD COLUMN folder VARCHAR(100),
+    ADD COLUMN tags TEXT[] DEFAULT '{}' NOT NULL;
+
+CREATE INDEX idx_favorites_user_folder
+    ON subscriptions.favorites(user_id, folder)
+    WHERE workspace_id IS NULL;
+
+CREATE INDEX idx_favorites_tags ON subscriptions.favorites USING GIN (tags);
+
+COMMENT ON COLUMN subscriptions.favorites.folder IS 'Папка записи; NULL - без папки';
+COMMENT ON COLUMN subscriptions.favorites.tags IS 'Теги записи в нижнем регистре';
@@ -0,0 +1,17 @@
+-- Миграция 011: Удаление учетной записи по запросу пользователя
+-- Описание: Пользователь запрашивает удаление (deleteMyAccount), учетная запись
+--           удаляется после льготного периода, в течение которого запрос можно
+--           отменить. Удаление выполняет API Gateway (service/personal_data.go):
+--           личные данные удаляются каскадно, общие записи пространств
+--           передаются другому участнику, авторство заметок обезличивается.
+
+ALTER TABLE subscriptions.users
+    ADD COLUMN deletion_requested_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN deletion_scheduled_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX idx_users_deletion_scheduled
+    ON subscriptions.users(deletion_scheduled_at)
+    WHERE deletion_scheduled_at IS NOT NULL;
+
+COMMENT ON COLUMN subscriptions.users.deletion_requested_at IS 'Когда пользователь запросил удаление учетной записи';
+COMMENT ON COLUMN subscriptions.users.deletion_scheduled_at IS 'Когда учетная запись будет удалена; NULL - удаление не запрошено';
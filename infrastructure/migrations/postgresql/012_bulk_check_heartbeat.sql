@@ -0,0 +1,11 @@
+-- Миграция 012: Отметка прогресса заданий пакетной проверки
+-- Описание: Воркер API Gateway обновляет heartbeat_at при захвате задания и
+--           при каждом сохранении прогресса. При запуске gateway задания в
+--           статусе running, heartbeat_at которых старше
+--           BULK_CHECK_STALE_JOB_TIMEOUT, возвращаются в pending: их воркер
+--           остановлен без освобождения задания (падение или SIGKILL).
+
+ALTER TABLE subscriptions.bulk_check_jobs
+    ADD COLUMN heartbeat_at TIMESTAMP WITH TIME ZONE;
+
+COMMENT ON COLUMN subscriptions.bulk_check_jobs.heartbeat_at IS 'Когда воркер последний раз отметил прогресс задания в статусе running';
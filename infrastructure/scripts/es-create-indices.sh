@@ -34,6 +34,21 @@ else
     echo "⚠ Index egrul_entrepreneurs might already exist or creation failed"
 fi
 
+# Поля автодополнения и фасетов для индексов, созданных до их появления в маппинге.
+# После добавления поля нужна переиндексация (initial sync в sync-service).
+# Анализаторы (нечеткий поиск, подполя .plain, founder_names) так не добавить:
+# для них индексы пересоздаются через es-reindex.sh.
+for index in egrul_companies egrul_entrepreneurs; do
+  echo "Updating mapping: $index (name_suggest, okved_section)..."
+  if curl -s -X PUT "$ELASTICSEARCH_URL/$index/_mapping" \
+    -H 'Content-Type: application/json' \
+    -d '{"properties":{"name_suggest":{"type":"search_as_you_type","max_shingle_size":3},"okved_section":{"type":"keyword"}}}' | grep -q '"acknowledged":true'; then
+      echo "✓ Mapping of $index is up to date"
+  else
+      echo "⚠ Failed to update mapping of $index"
+  fi
+done
+
 echo ""
 echo "Indices created. Checking status..."
 curl -s "$ELASTICSEARCH_URL/_cat/indices/egrul_*?v"
@@ -19,6 +19,9 @@ CLICKHOUSE_USER="${CLICKHOUSE_USER:-egrul_import}"
 CLICKHOUSE_PASSWORD="${CLICKHOUSE_PASSWORD:-123}"
 CLICKHOUSE_DATABASE="${CLICKHOUSE_DATABASE:-egrul}"
 
+# Сервисный токен change-detection-service (SERVICE_TOKEN сервиса)
+CHANGE_DETECTION_SERVICE_TOKEN="${CHANGE_DETECTION_SERVICE_TOKEN:-dev-change-detection-token}"
+
 # Путь к debug-логу для отладки (NDJSON)
 DEBUG_LOG_PATH="/Users/konstantin/cursor/egrul/.cursor/debug.log"
 
@@ -796,7 +799,7 @@ trigger_change_detection() {
     # Проверяем доступность сервиса
     if ! curl -s -f http://localhost:8082/health > /dev/null 2>&1; then
         log_warning "Change-detection-service недоступен, пропускаем автоматическое детектирование"
-        log_warning "Запустите детектирование вручную: curl -X POST http://localhost:8082/detect -d '{\"entity_type\":\"company\",\"entity_ids\":[...]}'"
+        log_warning "Запустите детектирование вручную: curl -X POST http://localhost:8082/detect -H \"X-Service-Token: \$CHANGE_DETECTION_SERVICE_TOKEN\" -d '{\"entity_type\":\"company\",\"entity_ids\":[...]}'"
         return 0
     fi
 
@@ -823,6 +826,7 @@ trigger_change_detection() {
         log_info "Запуск детектирования изменений для компаний..."
         local response=$(curl -s -X POST http://localhost:8082/detect \
             -H 'Content-Type: application/json' \
+            -H "X-Service-Token: ${CHANGE_DETECTION_SERVICE_TOKEN}" \
             -d "{\"entity_type\": \"company\", \"entity_ids\": $changed_ogrns}" 2>&1)
 
         if [ $? -eq 0 ]; then
@@ -864,6 +868,7 @@ trigger_change_detection() {
         log_info "Запуск детектирования изменений для ИП..."
         local response=$(curl -s -X POST http://localhost:8082/detect \
             -H 'Content-Type: application/json' \
+            -H "X-Service-Token: ${CHANGE_DETECTION_SERVICE_TOKEN}" \
             -d "{\"entity_type\": \"entrepreneur\", \"entity_ids\": $changed_ogrnips}" 2>&1)
 
         if [ $? -eq 0 ]; then
@@ -0,0 +1,118 @@
+#!/bin/bash
+# ==============================================================================
+# Заполнение справочника ОКВЭД2 egrul.okved_classifier_local (миграция 026).
+#
+# 1. Выводит коды из данных: основные ОКВЭД компаний и ИП и таблицы
+#    *_okved_additional, добавляя всех предков кода (62.02.1 -> 62.02 -> 62.0 -> 62).
+# 2. Если задан OKVED_CLASSIFIER_CSV (файл "код;наименование" без заголовка),
+#    загружает официальный классификатор - его наименования имеют приоритет.
+#
+# Справочник реплицируется на все ноды, поэтому достаточно вставки на одну ноду.
+# ==============================================================================
+
+set -e
+
+CLICKHOUSE_HOST="${CLICKHOUSE_HOST:-localhost}"
+CLICKHOUSE_PORT="${CLICKHOUSE_PORT:-8123}"
+CLICKHOUSE_USER="${CLICKHOUSE_USER:-egrul_import}"
+CLICKHOUSE_PASSWORD="${CLICKHOUSE_PASSWORD:-123}"
+CLICKHOUSE_DATABASE="${CLICKHOUSE_DATABASE:-egrul}"
+OKVED_CLASSIFIER_CSV="${OKVED_CLASSIFIER_CSV:-}"
+
+clickhouse_query() {
+    curl -s -f "http://${CLICKHOUSE_HOST}:${CLICKHOUSE_PORT}/" \
+        --user "${CLICKHOUSE_USER}:${CLICKHOUSE_PASSWORD}" \
+        --data-binary "$1"
+}
+
+# Общие выражения уровня, родителя и раздела по коду
+LEVEL_EXPR="multiIf(length(code) = 2, 'CLASS', length(code) = 4, 'SUBCLASS', length(code) = 5, 'GROUP', length(code) = 7, 'SUBGROUP', 'KIND')"
+SECTION_EXPR="multiIf(
+    toUInt8OrZero(substring(code, 1, 2)) BETWEEN 1 AND 3, 'A',
+    toUInt8OrZero(substring(code, 1, 2)) BETWEEN 5 AND 9, 'B',
+    toUInt8OrZero(substring(code, 1, 2)) BETWEEN 10 AND 33, 'C',
+    toUInt8OrZero(substring(code, 1, 2)) = 35, 'D',
+    toUInt8OrZero(substring(code, 1, 2)) BETWEEN 36 AND 39, 'E',
+    toUInt8OrZero(substring(code, 1, 2)) BETWEEN 41 AND 43, 'F',
+    toUInt8OrZero(substring(code, 1, 2)) BETWEEN 45 AND 47, 'G',
+    toUInt8OrZero(substring(code, 1, 2)) BETWEEN 49 AND 53, 'H',
+    toUInt8OrZero(substring(code, 1, 2)) BETWEEN 55 AND 56, 'I',
+    toUInt8OrZero(substring(code, 1, 2)) BETWEEN 58 AND 63, 'J',
+    toUInt8OrZero(substring(code, 1, 2)) BETWEEN 64 AND 66, 'K',
+    toUInt8OrZero(substring(code, 1, 2)) = 68, 'L',
+    toUInt8OrZero(substring(code, 1, 2)) BETWEEN 69 AND 75, 'M',
+    toUInt8OrZero(substring(code, 1, 2)) BETWEEN 77 AND 82, 'N',
+    toUInt8OrZero(substring(code, 1, 2)) = 84, 'O',
+    toUInt8OrZero(substring(code, 1, 2)) = 85, 'P',
+    toUInt8OrZero(substring(code, 1, 2)) BETWEEN 86 AND 88, 'Q',
+    toUInt8OrZero(substring(code, 1, 2)) BETWEEN 90 AND 93, 'R',
+    toUInt8OrZero(substring(code, 1, 2)) BETWEEN 94 AND 96, 'S',
+    toUInt8OrZero(substring(code, 1, 2)) BETWEEN 97 AND 98, 'T',
+    toUInt8OrZero(substring(code, 1, 2)) = 99, 'U',
+    '')"
+PARENT_EXPR="if(length(code) = 2, ${SECTION_EXPR}, replaceRegexpOne(substring(code, 1, length(code) - 1), '\\\\.\$', ''))"
+CODE_PATTERN="'^[0-9]{2}(\\\\.[0-9]{1,2}){0,2}\$'"
+
+echo "📚 Заполнение справочника ОКВЭД2..."
+
+echo "  → Коды из данных ЕГРЮЛ/ЕГРИП"
+clickhouse_query "
+INSERT INTO ${CLICKHOUSE_DATABASE}.okved_classifier_local (code, name, parent_code, level, section, source)
+SELECT
+    code,
+    name,
+    ${PARENT_EXPR} AS parent_code,
+    ${LEVEL_EXPR} AS level,
+    ${SECTION_EXPR} AS section,
+    1 AS source
+FROM
+(
+    SELECT
+        prefix AS code,
+        anyIf(name, full_code = prefix AND name != '') AS name
+    FROM
+    (
+        SELECT
+            full_code,
+            name,
+            arrayJoin(arrayFilter(p -> NOT endsWith(p, '.'),
+                arrayMap(i -> substring(full_code, 1, i), range(2, length(full_code) + 1)))) AS prefix
+        FROM
+        (
+            SELECT okved_main_code AS full_code, ifNull(okved_main_name, '') AS name
+            FROM ${CLICKHOUSE_DATABASE}.companies WHERE okved_main_code IS NOT NULL
+            UNION ALL
+            SELECT okved_main_code, ifNull(okved_main_name, '')
+            FROM ${CLICKHOUSE_DATABASE}.entrepreneurs WHERE okved_main_code IS NOT NULL
+            UNION ALL
+            SELECT okved_code, ifNull(okved_name, '') FROM ${CLICKHOUSE_DATABASE}.companies_okved_additional
+            UNION ALL
+            SELECT okved_code, ifNull(okved_name, '') FROM ${CLICKHOUSE_DATABASE}.entrepreneurs_okved_additional
+        )
+        WHERE match(full_code, ${CODE_PATTERN})
+    )
+    GROUP BY prefix
+)
+"
+
+if [ -n "${OKVED_CLASSIFIER_CSV}" ]; then
+  if [ ! -f "${OKVED_CLASSIFIER_CSV}" ]; then
+    echo "❌ Файл классификатора не найден: ${OKVED_CLASSIFIER_CSV}"
+    exit 1
+  fi
+
+  echo "  → Официальный классификатор из ${OKVED_CLASSIFIER_CSV}"
+  curl -s -f "http://${CLICKHOUSE_HOST}:${CLICKHOUSE_PORT}/?query=$(printf '%s' "
+INSERT INTO ${CLICKHOUSE_DATABASE}.okved_classifier_local (code, name, parent_code, level, section, source)
+SELECT code, name, ${PARENT_EXPR}, ${LEVEL_EXPR}, ${SECTION_EXPR}, 2
+FROM input('code String, name String')
+WHERE match(code, ${CODE_PATTERN})
+SETTINGS format_csv_delimiter = ';'
+FORMAT CSV" | jq -sRr @uri)" \
+      --user "${CLICKHOUSE_USER}:${CLICKHOUSE_PASSWORD}" \
+      --data-binary "@${OKVED_CLASSIFIER_CSV}"
+fi
+
+clickhouse_query "SYSTEM RELOAD DICTIONARY ${CLICKHOUSE_DATABASE}.okved_dict"
+
+echo "✅ Справочник ОКВЭД2 заполнен: $(clickhouse_query "SELECT count() FROM ${CLICKHOUSE_DATABASE}.okved_classifier_local FINAL") записей"
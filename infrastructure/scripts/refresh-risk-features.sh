@@ -0,0 +1,153 @@
+#!/bin/bash
+# ==============================================================================
+# Пересчет агрегатов массовой регистрации (egrul.mass_addresses, egrul.mass_directors)
+# и признаков оценки риска контрагентов (egrul.company_risk_features)
+# ==============================================================================
+# Запускать после импорта и детектирования изменений: признаки смен руководителя
+# и адреса берутся из default.company_changes за последние 12 месяцев.
+# Агрегаты массовой регистрации пересчитываются полностью (TRUNCATE + INSERT),
+# в признаках риска старые строки вытесняются новыми по updated_at (ReplacingMergeTree).
+# ==============================================================================
+
+set -e
+
+RED='\033[0;31m'
+GREEN='\033[0;32m'
+BLUE='\033[0;34m'
+NC='\033[0m' # No Color
+
+CLICKHOUSE_HOST="${CLICKHOUSE_HOST:-localhost}"
+CLICKHOUSE_PORT="${CLICKHOUSE_PORT:-8123}"
+CLICKHOUSE_USER="${CLICKHOUSE_USER:-egrul_import}"
+CLICKHOUSE_PASSWORD="${CLICKHOUSE_PASSWORD:-123}"
+
+log_info() {
+    echo -e "${BLUE}[INFO]${NC} $1"
+}
+
+log_success() {
+    echo -e "${GREEN}[SUCCESS]${NC} $1"
+}
+
+log_error() {
+    echo -e "${RED}[ERROR]${NC} $1"
+}
+
+clickhouse_query() {
+    local query="$1"
+    curl -sS --fail-with-body "http://${CLICKHOUSE_HOST}:${CLICKHOUSE_PORT}/" \
+        --user "${CLICKHOUSE_USER}:${CLICKHOUSE_PASSWORD}" \
+        --data-binary "$query"
+}
+
+# Ключ адреса: код ФИАС, а при его отсутствии - адрес в нижнем регистре без
+# пунктуации и лишних пробелов. Выражение совпадает с massAddressKeyExpr в
+# services/api-gateway/internal/repository/clickhouse/mass_registration.go
+ADDRESS_KEY_EXPR="if(ifNull(fias_id, '') != '', concat('fias:', fias_id), concat('addr:', trimBoth(replaceRegexpAll(lowerUTF8(ifNull(full_address, '')), '[^0-9a-zа-яё]+', ' '))))"
+
+refresh_mass_addresses() {
+    log_info "Пересчет egrul.mass_addresses..."
+
+    clickhouse_query "TRUNCATE TABLE IF EXISTS egrul.mass_addresses_local ON CLUSTER egrul_cluster"
+    clickhouse_query "
+        INSERT INTO egrul.mass_addresses
+            (address_key, fias_id, full_address, region_code, companies_count, active_count, updated_at)
+        SELECT
+            ${ADDRESS_KEY_EXPR} AS address_key,
+            any(fias_id),
+            any(ifNull(full_address, '')),
+            any(ifNull(region_code, '')),
+            uniqExact(ogrn),
+            uniqExactIf(ogrn, status = 'active'),
+            now()
+        FROM egrul.companies FINAL
+        WHERE ifNull(fias_id, '') != ''
+           OR (full_address IS NOT NULL AND length(full_address) > 10)
+        GROUP BY address_key
+    "
+
+    local count
+    count=$(clickhouse_query "SELECT count() FROM egrul.mass_addresses")
+    log_success "Адреса регистрации пересчитаны: ${count} адресов"
+}
+
+refresh_mass_directors() {
+    log_info "Пересчет egrul.mass_directors..."
+
+    clickhouse_query "TRUNCATE TABLE IF EXISTS egrul.mass_directors_local ON CLUSTER egrul_cluster"
+    clickhouse_query "
+        INSERT INTO egrul.mass_directors
+            (director_inn, director_name, companies_count, active_count, updated_at)
+        SELECT
+            head_inn,
+            any(trimBoth(concat(ifNull(head_last_name, ''), ' ', ifNull(head_first_name, ''), ' ', ifNull(head_middle_name, '')))),
+            uniqExact(ogrn),
+            uniqExactIf(ogrn, status = 'active'),
+            now()
+        FROM egrul.companies FINAL
+        WHERE head_inn IS NOT NULL AND head_inn != ''
+        GROUP BY head_inn
+    "
+
+    local count
+    count=$(clickhouse_query "SELECT count() FROM egrul.mass_directors")
+    log_success "Руководители пересчитаны: ${count} руководителей"
+}
+
+refresh_company_risk_features() {
+    log_info "Пересчет egrul.company_risk_features..."
+
+    clickhouse_query "
+        INSERT INTO egrul.company_risk_features
+            (ogrn, address_companies, director_companies, director_changes_12m,
+             address_changes_12m, foreign_founders, updated_at)
+        SELECT
+            c.ogrn,
+            ifNull(a.companies, 0),
+            ifNull(d.companies, 0),
+            ifNull(ch.director_changes, 0),
+            ifNull(ch.address_changes, 0),
+            ifNull(f.foreign_founders, 0),
+            now()
+        FROM (
+            SELECT ogrn, ${ADDRESS_KEY_EXPR} AS address_key, head_inn FROM egrul.companies FINAL
+        ) AS c
+        GLOBAL LEFT JOIN (
+            SELECT address_key, companies_count AS companies
+            FROM egrul.mass_addresses
+        ) AS a ON c.address_key = a.address_key
+        GLOBAL LEFT JOIN (
+            SELECT director_inn, companies_count AS companies
+            FROM egrul.mass_directors
+        ) AS d ON c.head_inn = d.director_inn
+        GLOBAL LEFT JOIN (
+            SELECT
+                ogrn,
+                uniqExactIf(change_id, change_type = 'director') AS director_changes,
+                uniqExactIf(change_id, change_type = 'address') AS address_changes
+            FROM default.company_changes
+            WHERE detected_at >= now() - INTERVAL 12 MONTH
+            GROUP BY ogrn
+        ) AS ch ON c.ogrn = ch.ogrn
+        GLOBAL LEFT JOIN (
+            SELECT company_ogrn, uniqExact(founder_name) AS foreign_founders
+            FROM egrul.founders FINAL
+            WHERE founder_type = 'foreign_company'
+               OR (founder_country IS NOT NULL AND founder_country != '')
+            GROUP BY company_ogrn
+        ) AS f ON c.ogrn = f.company_ogrn
+        SETTINGS join_use_nulls = 1
+    "
+
+    local count
+    count=$(clickhouse_query "SELECT count() FROM egrul.company_risk_features FINAL")
+    log_success "Признаки риска пересчитаны: ${count} компаний"
+}
+
+main() {
+    refresh_mass_addresses
+    refresh_mass_directors
+    refresh_company_risk_features
+}
+
+main "$@"
@@ -0,0 +1,66 @@
+// Mock OpenID Connect провайдер для локальной проверки входа через SSO.
+// Вход подтверждается автоматически от имени пользователя из флагов.
+//
+//	go run ./cmd/mock-oidc -groups egrul-analysts
+//
+// Gateway: OIDC_ENABLED=true OIDC_ISSUER_URL=http://localhost:9099
+// OIDC_CLIENT_ID=egrul-gateway OIDC_CLIENT_SECRET=mock-secret
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/egrul-system/services/api-gateway/internal/auth/oidctest"
+)
+
+func main() {
+	addr := flag.String("addr", ":9099", "адрес сервера")
+	issuer := flag.String("issuer", "http://localhost:9099", "внешний адрес провайдера (issuer)")
+	clientID := flag.String("client-id", "egrul-gateway", "client_id")
+	clientSecret := flag.String("client-secret", "mock-secret", "client_secret")
+	redirectURIs := flag.String("redirect-uris", "", "разрешенные redirect_uri через запятую (пусто - любые)")
+	subject := flag.String("sub", "mock-user-1", "subject пользователя")
+	email := flag.String("email", "sso.user@example.com", "email пользователя")
+	givenName := flag.String("given-name", "Тест", "имя пользователя")
+	familyName := flag.String("family-name", "SSO", "фамилия пользователя")
+	groups := flag.String("groups", "", "группы пользователя через запятую")
+	groupsClaim := flag.String("groups-claim", "groups", "claim со списком групп")
+	flag.Parse()
+
+	server, err := oidctest.NewServer(oidctest.Config{
+		Issuer:       *issuer,
+		ClientID:     *clientID,
+		ClientSecret: *clientSecret,
+		RedirectURIs: splitList(*redirectURIs),
+		GroupsClaim:  *groupsClaim,
+		User: oidctest.User{
+			Subject:       *subject,
+			Email:         *email,
+			EmailVerified: true,
+			GivenName:     *givenName,
+			FamilyName:    *familyName,
+			Groups:        splitList(*groups),
+		},
+	})
+	if err != nil {
+		log.Fatalf("failed to create mock oidc server: %v", err)
+	}
+
+	log.Printf("mock OIDC provider %s listening on %s (user %s)", *issuer, *addr, *email)
+	if err := http.ListenAndServe(*addr, server); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func splitList(s string) []string {
+	var result []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
@@ -22,10 +22,13 @@ import (
 	"github.com/egrul-system/services/api-gateway/internal/cache"
 	"github.com/egrul-system/services/api-gateway/internal/config"
 	"github.com/egrul-system/services/api-gateway/internal/graph"
+	"github.com/egrul-system/services/api-gateway/internal/graph/model"
 	"github.com/egrul-system/services/api-gateway/internal/middleware"
 	"github.com/egrul-system/services/api-gateway/internal/notifications"
 	"github.com/egrul-system/services/api-gateway/internal/repository/clickhouse"
+	esrepo "github.com/egrul-system/services/api-gateway/internal/repository/elasticsearch"
 	pgrepo "github.com/egrul-system/services/api-gateway/internal/repository/postgresql"
+	"github.com/egrul-system/services/api-gateway/internal/rest"
 	"github.com/egrul-system/services/api-gateway/internal/service"
 	"go.uber.org/zap"
 
@@ -129,6 +132,8 @@ func main() {
 	branchRepo := clickhouse.NewBranchRepository(chClient, logger)
 	historyRepo := clickhouse.NewHistoryRepository(chClient, logger)
 	statsRepo := clickhouse.NewStatisticsRepository(chClient, logger)
+	riskRepo := clickhouse.NewRiskRepository(chClient, logger)
+	personRepo := clickhouse.NewPersonRepository(chClient, logger)
 
 	// Инициализация Redis кэша
 	redisCache := cache.NewRedisCache(cfg.Redis, logger)
@@ -168,6 +173,10 @@ func main() {
 	subscriptionRepo := pgrepo.NewSubscriptionRepository(pgDB, cfg.PostgreSQL.Schema, logger)
 	userRepo := pgrepo.NewUserRepository(pgDB, cfg.PostgreSQL.Schema, logger)
 	favoriteRepo := pgrepo.NewFavoriteRepository(pgDB, cfg.PostgreSQL.Schema, logger)
+	bulkCheckRepo := pgrepo.NewBulkCheckRepository(pgDB, cfg.PostgreSQL.Schema, logger)
+	sessionRepo := pgrepo.NewSessionRepository(pgDB, cfg.PostgreSQL.Schema, logger)
+	apiKeyRepo := pgrepo.NewAPIKeyRepository(pgDB, cfg.PostgreSQL.Schema, logger)
+	workspaceRepo := pgrepo.NewWorkspaceRepository(pgDB, cfg.PostgreSQL.Schema, logger)
 
 	// Инициализация сервисов
 	companyService := service.NewCompanyService(
@@ -185,10 +194,70 @@ func main() {
 		logger,
 	)
 	statsService := service.NewStatisticsService(statsRepo, logger)
-	searchService := service.NewSearchService(companyService, entrepreneurService, logger)
+	// Подсказки и поиск с фасетами работают через Elasticsearch; подсказки без него
+	// переходят на обычный поиск
+	var suggestRepo *esrepo.ESSuggestRepository
+	var esCompanyRepo *esrepo.ESCompanyRepository
+	var esEntrepreneurRepo *esrepo.ESEntrepreneurRepository
+	if esClient != nil {
+		suggestRepo = esrepo.NewESSuggestRepository(esClient, logger)
+		esCompanyRepo = esrepo.NewESCompanyRepository(esClient, logger)
+		esEntrepreneurRepo = esrepo.NewESEntrepreneurRepository(esClient, logger)
+	}
+	searchService := service.NewSearchService(companyService, entrepreneurService, suggestRepo, esCompanyRepo, esEntrepreneurRepo, logger)
+	riskService := service.NewRiskService(riskRepo, companyRepo, cfg.Risk, logger)
+	personService := service.NewPersonService(personRepo, entrepreneurRepo, logger)
+	exportService := service.NewExportService(companyRepo, entrepreneurRepo, redisCache, cfg.Export, logger)
+	accountEmailProducer := notifications.NewAccountEmailProducer(cfg.Kafka.Brokers, cfg.Kafka.AccountEmailsTopic, logger)
+	defer accountEmailProducer.Close()
+	sessionService := service.NewSessionService(sessionRepo, userRepo, jwtManager, redisCache, cfg.Auth, logger)
+	jwtManager.UseDenylist(sessionService)
+	apiKeyService := service.NewAPIKeyService(apiKeyRepo, redisCache, logger)
+	jwtManager.UseAPIKeys(apiKeyService)
+
+	// Журнал аудита (ClickHouse); события пишутся пачками, при остановке дописываются
+	var auditService *service.AuditService
+	auditCtx, stopAudit := context.WithCancel(context.Background())
+	auditDone := make(chan struct{})
+	if cfg.Audit.Enabled {
+		auditService = service.NewAuditService(clickhouse.NewAuditRepository(chClient, logger), cfg.Audit, logger)
+		apiKeyService.UseAuditLog(auditService)
+		go func() {
+			auditService.Run(auditCtx)
+			close(auditDone)
+		}()
+		logger.Info("Audit log enabled", zap.Int("retention_days", cfg.Audit.RetentionDays))
+	} else {
+		close(auditDone)
+	}
+	accountService := service.NewAccountService(userRepo, sessionService, accountEmailProducer, cfg.Auth, logger)
+	personalDataService := service.NewPersonalDataService(userRepo, favoriteRepo, subscriptionRepo, workspaceRepo, sessionService, cfg.Auth, logger)
+	personalDataService.UseAuditLog(auditService)
+	bulkCheckService := service.NewBulkCheckService(bulkCheckRepo, companyService, entrepreneurService, riskService, cfg.BulkCheck, logger)
+
+	// Пул воркеров пакетной проверки контрагентов
+	// (при остановке прерванные задания возвращаются в очередь)
+	bulkCheckCtx, stopBulkCheck := context.WithCancel(context.Background())
+	bulkCheckDone := make(chan struct{})
+	go func() {
+		bulkCheckService.Run(bulkCheckCtx)
+		close(bulkCheckDone)
+	}()
+	logger.Info("Bulk check workers started", zap.Int("workers", cfg.BulkCheck.Workers))
 
-	// Инициализация GraphQL резолвера
-	resolver := graph.NewResolver(companyService, entrepreneurService, statsService, searchService, subscriptionRepo, favoriteRepo, userRepo, jwtManager, redisCache, logger)
+	// Удаление учетных записей, льготный период которых истек
+	accountPurgeCtx, stopAccountPurge := context.WithCancel(context.Background())
+	defer stopAccountPurge()
+	go personalDataService.Run(accountPurgeCtx)
+	logger.Info("Account deletion job started", zap.Duration("grace_period", cfg.Auth.AccountDeletionGracePeriod))
+
+	// Запись счетчиков запросов API ключей; при остановке счетчики дописываются в БД
+	apiKeyUsageCtx, stopAPIKeyUsage := context.WithCancel(context.Background())
+	apiKeyUsageDone := make(chan struct{})
+	go func() {
+		apiKeyService.Run(apiKeyUsageCtx)
+		close(apiKeyUsageDone)
+	}()
 
 	// Создание и запуск Notification Hub (если включен)
 	var notificationHub *notifications.Hub
@@ -206,6 +275,24 @@ func main() {
 		logger.Info("Notification Hub disabled")
 	}
 
+	adminService := service.NewAdminService(userRepo, subscriptionRepo, sessionService, notificationHub, logger)
+	workspaceService := service.NewWorkspaceService(workspaceRepo, favoriteRepo, subscriptionRepo, userRepo, logger)
+	favoriteService := service.NewFavoriteService(favoriteRepo, subscriptionRepo, userRepo, companyService, entrepreneurService, logger)
+
+	// Вход через корпоративный OIDC провайдер (если включен)
+	var ssoService *service.SSOService
+	if cfg.OIDC.Enabled {
+		oidcProvider := auth.NewOIDCProvider(cfg.OIDC.IssuerURL, cfg.OIDC.ClientID, cfg.OIDC.ClientSecret, cfg.OIDC.Scopes, nil)
+		ssoService, err = service.NewSSOService(oidcProvider, userRepo, sessionService, redisCache, cfg.OIDC, logger)
+		if err != nil {
+			logger.Fatal("invalid OIDC configuration", zap.Error(err))
+		}
+		logger.Info("OIDC single sign-on enabled", zap.String("issuer", cfg.OIDC.IssuerURL))
+	}
+
+	// Инициализация GraphQL резолвера
+	resolver := graph.NewResolver(companyService, entrepreneurService, statsService, searchService, riskService, personService, accountService, sessionService, adminService, apiKeyService, ssoService, workspaceService, favoriteService, auditService, personalDataService, subscriptionRepo, favoriteRepo, userRepo, jwtManager, redisCache, logger)
+
 	// Создание роутера
 	r := chi.NewRouter()
 
@@ -223,8 +310,8 @@ func main() {
 	r.Use(cors.Handler(cors.Options{
 		AllowedOrigins:   []string{"*"},
 		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-Request-ID"},
-		ExposedHeaders:   []string{"Link", "X-Request-ID"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-Request-ID", auth.APIKeyHeader},
+		ExposedHeaders:   []string{"Link", "X-Request-ID", "Retry-After"},
 		AllowCredentials: true,
 		MaxAge:           300,
 	}))
@@ -233,8 +320,13 @@ func main() {
 	r.Get("/health", healthHandler(chClient))
 	r.Get("/ready", readyHandler(chClient))
 
+	// Ограничение частоты запросов (token bucket в Redis)
+	rateLimiter := middleware.NewRateLimiter(redisCache, cfg.RateLimit, logger)
+	logger.Info("Rate limiting", zap.Bool("enabled", cfg.RateLimit.Enabled))
+
 	// GraphQL endpoint с JWT middleware
 	graphqlHandler := graph.NewManualHandler(resolver)
+	graphqlHandler.UseRateLimiter(rateLimiter)
 	r.Group(func(r chi.Router) {
 		// JWT middleware для проверки токена (опциональная авторизация)
 		r.Use(jwtManager.Middleware)
@@ -253,23 +345,43 @@ func main() {
 
 	// REST API compatibility endpoints
 	r.Route("/api/v1", func(r chi.Router) {
-		r.Get("/companies/{ogrn}", restCompanyHandler(companyService))
-		r.Get("/entrepreneurs/{ogrnip}", restEntrepreneurHandler(entrepreneurService))
-		r.Get("/search", restSearchHandler(searchService))
+		r.With(rateLimiter.Middleware(middleware.OperationQuery)).Get("/companies/{ogrn}", restCompanyHandler(companyService, auditService))
+		r.With(rateLimiter.Middleware(middleware.OperationQuery)).Get("/entrepreneurs/{ogrnip}", restEntrepreneurHandler(entrepreneurService, auditService))
+		r.With(rateLimiter.Middleware(middleware.OperationSearch)).Get("/search", restSearchHandler(searchService))
+		rest.NewSuggestHandler(searchService, logger).Routes(r.With(rateLimiter.Middleware(middleware.OperationSearch)))
+
+		// Эндпоинты, требующие авторизации
+		r.Group(func(r chi.Router) {
+			r.Use(jwtManager.Middleware)
+			r.Use(auth.RequireScope(auth.ScopeReadCompanies))
+			rest.NewBulkCheckHandler(bulkCheckService, cfg.BulkCheck.MaxFileSize, logger).Routes(r.With(rateLimiter.Middleware(middleware.OperationQuery)))
+			rest.NewExportHandler(exportService, auditService, logger).Routes(r.With(rateLimiter.Middleware(middleware.OperationExport)))
+		})
+
+		// Импорт списков в избранное
+		r.Group(func(r chi.Router) {
+			r.Use(jwtManager.Middleware)
+			r.Use(auth.RequireScope(auth.ScopeManageSubscriptions))
+			rest.NewFavoriteImportHandler(favoriteService, auditService, cfg.BulkCheck.MaxFileSize, logger).Routes(r.With(rateLimiter.Middleware(middleware.OperationMutation)))
+		})
+
+		// Выгрузка пользователем своих данных (только по JWT, API ключи отклоняет обработчик)
+		r.Group(func(r chi.Router) {
+			r.Use(jwtManager.Middleware)
+			rest.NewPersonalDataHandler(personalDataService, auditService, logger).Routes(r.With(rateLimiter.Middleware(middleware.OperationExport)))
+		})
 	})
 
 	// Notification endpoints
 	if cfg.NotificationHub.Enabled && notificationHub != nil {
 		r.Group(func(r chi.Router) {
 			r.Use(jwtManager.Middleware)
+			r.Use(auth.RequireScope(auth.ScopeManageSubscriptions))
 			r.Get("/notifications/stream", notificationHub.ServeSSE)
 			r.Get("/notifications/history", notificationHub.ServeHistory)
 			r.Post("/notifications/{id}/read", notificationHub.MarkAsRead)
 			r.Post("/notifications/read-all", notificationHub.MarkAllAsRead)
-			// Debug endpoint (опционально)
-			if cfg.Log.Level == "debug" {
-				r.Get("/notifications/stats", notificationHub.StatsHandler)
-			}
+			r.With(auth.RequireRole(auth.RoleAdmin)).Get("/notifications/stats", notificationHub.StatsHandler)
 		})
 		logger.Info("Notification endpoints registered with JWT authentication")
 	}
@@ -311,6 +423,13 @@ func main() {
 		logger.Error("server forced to shutdown", zap.Error(err))
 	}
 
+	stopBulkCheck()
+	<-bulkCheckDone
+	stopAPIKeyUsage()
+	<-apiKeyUsageDone
+	stopAudit()
+	<-auditDone
+
 	logger.Info("Server stopped")
 }
 
@@ -343,7 +462,9 @@ func readyHandler(chClient *clickhouse.Client) http.HandlerFunc {
 
 // REST API handlers for backward compatibility
 
-func restCompanyHandler(svc *service.CompanyService) http.HandlerFunc {
+// restCompanyHandler отдает карточку компании; просмотр записывается в журнал
+// аудита так же, как в резолвере company (audit может быть nil)
+func restCompanyHandler(svc *service.CompanyService, audit *service.AuditService) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		ogrn := chi.URLParam(r, "ogrn")
 		company, err := svc.GetByOGRN(r.Context(), ogrn)
@@ -355,6 +476,12 @@ func restCompanyHandler(svc *service.CompanyService) http.HandlerFunc {
 			http.Error(w, "company not found", http.StatusNotFound)
 			return
 		}
+		audit.Record(r.Context(), service.AuditEvent{
+			Action:     model.AuditActionCompanyView,
+			EntityType: service.AuditEntityCompany,
+			EntityID:   company.Ogrn,
+			Success:    true,
+		})
 
 		w.Header().Set("Content-Type", "application/json")
 		// Simple JSON response - in production use encoding/json
@@ -363,7 +490,9 @@ func restCompanyHandler(svc *service.CompanyService) http.HandlerFunc {
 	}
 }
 
-func restEntrepreneurHandler(svc *service.EntrepreneurService) http.HandlerFunc {
+// restEntrepreneurHandler отдает карточку ИП; просмотр записывается в журнал
+// аудита так же, как в резолвере entrepreneur (audit может быть nil)
+func restEntrepreneurHandler(svc *service.EntrepreneurService, audit *service.AuditService) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		ogrnip := chi.URLParam(r, "ogrnip")
 		entrepreneur, err := svc.GetByOGRNIP(r.Context(), ogrnip)
@@ -375,6 +504,12 @@ func restEntrepreneurHandler(svc *service.EntrepreneurService) http.HandlerFunc
 			http.Error(w, "entrepreneur not found", http.StatusNotFound)
 			return
 		}
+		audit.Record(r.Context(), service.AuditEvent{
+			Action:     model.AuditActionEntrepreneurView,
+			EntityType: service.AuditEntityEntrepreneur,
+			EntityID:   entrepreneur.Ogrnip,
+			Success:    true,
+		})
 
 		w.Header().Set("Content-Type", "application/json")
 		fmt.Fprintf(w, `{"ogrnip":"%s","inn":"%s","lastName":"%s","firstName":"%s","status":"%s"}`,
@@ -0,0 +1,114 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// Разрешения (scopes) API ключей. Запросы с JWT не ограничены scopes.
+const (
+	ScopeReadCompanies       = "read:companies"
+	ScopeReadStats           = "read:stats"
+	ScopeManageSubscriptions = "manage:subscriptions"
+)
+
+// AllScopes все известные scopes
+var AllScopes = []string{
+	ScopeReadCompanies,
+	ScopeReadStats,
+	ScopeManageSubscriptions,
+}
+
+// APIKeyHeader заголовок, в котором передается API ключ
+const APIKeyHeader = "X-API-Key"
+
+var (
+	// ErrInvalidAPIKey ключ не найден, отозван, истек или владелец отключен
+	ErrInvalidAPIKey = errors.New("invalid or expired API key")
+	// ErrAPIKeyIPNotAllowed запрос с IP, которого нет в списке разрешенных для ключа
+	ErrAPIKeyIPNotAllowed = errors.New("API key is not allowed from this IP address")
+	// ErrAPIKeyQuotaExceeded исчерпан суточный лимит запросов ключа
+	ErrAPIKeyQuotaExceeded = errors.New("API key daily quota exceeded")
+)
+
+// APIKeyIdentity пользователь и разрешения, полученные по API ключу
+type APIKeyIdentity struct {
+	KeyID  string
+	UserID string
+	Email  string
+	Role   string
+	Scopes []string
+}
+
+// APIKeyAuthenticator проверяет API ключи из заголовка X-API-Key
+type APIKeyAuthenticator interface {
+	AuthenticateAPIKey(ctx context.Context, key string, client ClientInfo) (*APIKeyIdentity, error)
+}
+
+// IsValidScope проверяет, что scope известен
+func IsValidScope(scope string) bool {
+	for _, s := range AllScopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// HasScope проверяет разрешение запроса. Запросы без API ключа (JWT или
+// анонимные) scopes не ограничены.
+func HasScope(ctx context.Context, scope string) bool {
+	if GetAPIKeyIDFromContext(ctx) == "" {
+		return true
+	}
+	scopes, _ := ctx.Value(ScopesKey).([]string)
+	for _, s := range scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// RequireScope создает HTTP middleware, требующий scope у запросов с API ключом
+func RequireScope(scope string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !HasScope(r.Context(), scope) {
+				http.Error(w, "API key does not have scope "+scope, http.StatusForbidden)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// GetAPIKeyIDFromContext извлекает ID API ключа из контекста; пусто для запросов без ключа
+func GetAPIKeyIDFromContext(ctx context.Context) string {
+	keyID, _ := ctx.Value(APIKeyIDKey).(string)
+	return keyID
+}
+
+// authenticateAPIKey проверяет ключ и возвращает контекст с данными владельца
+// либо HTTP статус ошибки
+func (m *JWTManager) authenticateAPIKey(r *http.Request, key string) (context.Context, int, string) {
+	identity, err := m.apiKeys.AuthenticateAPIKey(r.Context(), key, GetClientInfoFromContext(r.Context()))
+	switch {
+	case errors.Is(err, ErrAPIKeyIPNotAllowed):
+		return nil, http.StatusForbidden, err.Error()
+	case errors.Is(err, ErrAPIKeyQuotaExceeded):
+		return nil, http.StatusTooManyRequests, err.Error()
+	case errors.Is(err, ErrInvalidAPIKey):
+		return nil, http.StatusUnauthorized, err.Error()
+	case err != nil:
+		return nil, http.StatusServiceUnavailable, "Failed to verify API key"
+	}
+
+	ctx := context.WithValue(r.Context(), UserIDKey, identity.UserID)
+	ctx = context.WithValue(ctx, EmailKey, identity.Email)
+	ctx = context.WithValue(ctx, RoleKey, identity.Role)
+	ctx = context.WithValue(ctx, APIKeyIDKey, identity.KeyID)
+	ctx = context.WithValue(ctx, ScopesKey, identity.Scopes)
+	return ctx, http.StatusOK, ""
+}
@@ -1,22 +1,32 @@
 package auth
 
 import (
+	"context"
 	"errors"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 )
 
+// SessionDenylist список отозванных сессий, access токены которых еще не истекли
+type SessionDenylist interface {
+	IsRevoked(ctx context.Context, sessionID string) (bool, error)
+}
+
 // JWTManager управляет созданием и валидацией JWT токенов
 type JWTManager struct {
 	secretKey     string
 	tokenDuration time.Duration
+	denylist      SessionDenylist
+	apiKeys       APIKeyAuthenticator
 }
 
 // Claims содержит кастомные claims для JWT токена
 type Claims struct {
-	UserID string `json:"user_id"`
-	Email  string `json:"email"`
+	UserID    string `json:"user_id"`
+	Email     string `json:"email"`
+	Role      string `json:"role,omitempty"`
+	SessionID string `json:"sid,omitempty"`
 	jwt.RegisteredClaims
 }
 
@@ -28,20 +38,44 @@ func NewJWTManager(secretKey string, tokenDuration time.Duration) *JWTManager {
 	}
 }
 
-// Generate создает новый JWT токен для пользователя
-func (m *JWTManager) Generate(userID, email string) (string, error) {
+// UseDenylist включает проверку отозванных сессий в Middleware
+func (m *JWTManager) UseDenylist(denylist SessionDenylist) {
+	m.denylist = denylist
+}
+
+// UseAPIKeys включает авторизацию по заголовку X-API-Key в Middleware
+func (m *JWTManager) UseAPIKeys(apiKeys APIKeyAuthenticator) {
+	m.apiKeys = apiKeys
+}
+
+// TokenDuration время жизни access токена
+func (m *JWTManager) TokenDuration() time.Duration {
+	return m.tokenDuration
+}
+
+// Generate создает новый access токен для сессии пользователя и возвращает
+// его вместе со сроком действия
+func (m *JWTManager) Generate(userID, email, role, sessionID string) (string, time.Time, error) {
+	now := time.Now()
+	expiresAt := now.Add(m.tokenDuration)
 	claims := Claims{
-		UserID: userID,
-		Email:  email,
+		UserID:    userID,
+		Email:     email,
+		Role:      role,
+		SessionID: sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(m.tokenDuration)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-			NotBefore: jwt.NewNumericDate(time.Now()),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
 		},
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(m.secretKey))
+	signed, err := token.SignedString([]byte(m.secretKey))
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return signed, expiresAt, nil
 }
 
 // Verify валидирует JWT токен и возвращает claims
@@ -2,6 +2,7 @@ package auth
 
 import (
 	"context"
+	"net"
 	"net/http"
 	"strings"
 )
@@ -13,13 +14,43 @@ const (
 	UserIDKey contextKey = "user_id"
 	// EmailKey ключ для Email в context
 	EmailKey contextKey = "email"
+	// RoleKey ключ для роли пользователя в context
+	RoleKey contextKey = "role"
+	// SessionIDKey ключ для ID сессии в context
+	SessionIDKey contextKey = "session_id"
+	// ClientInfoKey ключ для данных клиента (User-Agent, IP) в context
+	ClientInfoKey contextKey = "client_info"
+	// APIKeyIDKey ключ для ID API ключа в context (запросы с X-API-Key)
+	APIKeyIDKey contextKey = "api_key_id"
+	// ScopesKey ключ для scopes API ключа в context
+	ScopesKey contextKey = "scopes"
 )
 
+// ClientInfo данные клиента, сохраняемые в сессии
+type ClientInfo struct {
+	UserAgent string
+	IPAddress string
+}
+
 // Middleware создает HTTP middleware для проверки JWT токена
 func (m *JWTManager) Middleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Данные клиента нужны при входе и обновлении токена, еще без авторизации
+		r = r.WithContext(context.WithValue(r.Context(), ClientInfoKey, clientInfo(r)))
+
 		authHeader := r.Header.Get("Authorization")
 
+		// API ключ машинного клиента; при наличии JWT используется JWT
+		if apiKey := r.Header.Get(APIKeyHeader); authHeader == "" && apiKey != "" && m.apiKeys != nil {
+			ctx, status, message := m.authenticateAPIKey(r, apiKey)
+			if ctx == nil {
+				http.Error(w, message, status)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(ctx))
+			return
+		}
+
 		// Токен не обязателен, пропускаем запросы без токена
 		if authHeader == "" {
 			next.ServeHTTP(w, r)
@@ -40,9 +71,21 @@ func (m *JWTManager) Middleware(next http.Handler) http.Handler {
 			return
 		}
 
+		// Отозванная сессия: access токен еще не истек, но пользоваться им нельзя.
+		// При недоступности Redis пропускаем (ошибку логирует сам denylist) -
+		// токен все равно короткоживущий.
+		if claims.SessionID != "" && m.denylist != nil {
+			if revoked, err := m.denylist.IsRevoked(r.Context(), claims.SessionID); err == nil && revoked {
+				http.Error(w, "Session has been revoked", http.StatusUnauthorized)
+				return
+			}
+		}
+
 		// Добавляем данные пользователя в контекст
 		ctx := context.WithValue(r.Context(), UserIDKey, claims.UserID)
 		ctx = context.WithValue(ctx, EmailKey, claims.Email)
+		ctx = context.WithValue(ctx, RoleKey, claims.Role)
+		ctx = context.WithValue(ctx, SessionIDKey, claims.SessionID)
 
 		// Передаем запрос дальше с обновленным контекстом
 		next.ServeHTTP(w, r.WithContext(ctx))
@@ -60,3 +103,38 @@ func GetEmailFromContext(ctx context.Context) string {
 	email, _ := ctx.Value(EmailKey).(string)
 	return email
 }
+
+// GetRoleFromContext извлекает роль пользователя из контекста
+func GetRoleFromContext(ctx context.Context) string {
+	role, _ := ctx.Value(RoleKey).(string)
+	return role
+}
+
+// GetSessionIDFromContext извлекает ID сессии из контекста
+func GetSessionIDFromContext(ctx context.Context) string {
+	sessionID, _ := ctx.Value(SessionIDKey).(string)
+	return sessionID
+}
+
+// GetClientInfoFromContext извлекает данные клиента из контекста
+func GetClientInfoFromContext(ctx context.Context) ClientInfo {
+	info, _ := ctx.Value(ClientInfoKey).(ClientInfo)
+	return info
+}
+
+// clientInfo User-Agent и IP клиента
+func clientInfo(r *http.Request) ClientInfo {
+	return ClientInfo{
+		UserAgent: r.UserAgent(),
+		IPAddress: ClientIP(r),
+	}
+}
+
+// ClientIP IP клиента; RemoteAddr уже учитывает X-Real-IP (chi RealIP middleware)
+func ClientIP(r *http.Request) string {
+	ip := r.RemoteAddr
+	if host, _, err := net.SplitHostPort(ip); err == nil {
+		ip = host
+	}
+	return ip
+}
@@ -0,0 +1,156 @@
+package auth_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/egrul-system/services/api-gateway/internal/auth"
+	"github.com/egrul-system/services/api-gateway/internal/auth/oidctest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testRedirectURI = "http://localhost:3000/auth/sso/callback"
+
+func newMockOIDC(t *testing.T) (*oidctest.Server, string) {
+	t.Helper()
+
+	mock, err := oidctest.NewServer(oidctest.Config{
+		ClientID:     "egrul-gateway",
+		ClientSecret: "secret",
+		RedirectURIs: []string{testRedirectURI},
+		User: oidctest.User{
+			Subject:       "user-1",
+			Email:         "analyst@example.com",
+			EmailVerified: true,
+			GivenName:     "Анна",
+			FamilyName:    "Иванова",
+			Groups:        []string{"egrul-analysts"},
+		},
+	})
+	require.NoError(t, err)
+
+	srv := httptest.NewServer(mock)
+	t.Cleanup(srv.Close)
+	mock.SetIssuer(srv.URL)
+	return mock, srv.URL
+}
+
+// authorize проходит страницу входа mock провайдера и возвращает code и state
+func authorize(t *testing.T, authURL string) (code, state string) {
+	t.Helper()
+
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}}
+	resp, err := client.Get(authURL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusFound, resp.StatusCode)
+
+	location, err := url.Parse(resp.Header.Get("Location"))
+	require.NoError(t, err)
+	return location.Query().Get("code"), location.Query().Get("state")
+}
+
+func TestOIDCProvider_AuthorizationCodeFlow(t *testing.T) {
+	_, issuer := newMockOIDC(t)
+	ctx := context.Background()
+	provider := auth.NewOIDCProvider(issuer, "egrul-gateway", "secret", []string{"openid", "email", "groups"}, nil)
+
+	verifier, err := auth.NewPKCEVerifier()
+	require.NoError(t, err)
+	authURL, err := provider.AuthCodeURL(ctx, testRedirectURI, "state-1", "nonce-1", verifier)
+	require.NoError(t, err)
+
+	code, state := authorize(t, authURL)
+	assert.Equal(t, "state-1", state)
+
+	idToken, err := provider.Exchange(ctx, code, testRedirectURI, verifier)
+	require.NoError(t, err)
+
+	identity, err := provider.VerifyIDToken(ctx, idToken, "nonce-1")
+	require.NoError(t, err)
+	assert.Equal(t, issuer, identity.Issuer)
+	assert.Equal(t, "user-1", identity.Subject)
+	assert.Equal(t, "analyst@example.com", identity.Email)
+	assert.True(t, identity.EmailVerified)
+	assert.Equal(t, "Анна", identity.GivenName)
+	assert.Equal(t, []string{"egrul-analysts"}, identity.StringList("groups"))
+
+	// Код одноразовый
+	_, err = provider.Exchange(ctx, code, testRedirectURI, verifier)
+	assert.ErrorIs(t, err, auth.ErrOIDCProvider)
+}
+
+func TestOIDCProvider_RejectsWrongVerifier(t *testing.T) {
+	_, issuer := newMockOIDC(t)
+	ctx := context.Background()
+	provider := auth.NewOIDCProvider(issuer, "egrul-gateway", "secret", nil, nil)
+
+	verifier, err := auth.NewPKCEVerifier()
+	require.NoError(t, err)
+	authURL, err := provider.AuthCodeURL(ctx, testRedirectURI, "state", "nonce", verifier)
+	require.NoError(t, err)
+	code, _ := authorize(t, authURL)
+
+	otherVerifier, err := auth.NewPKCEVerifier()
+	require.NoError(t, err)
+	_, err = provider.Exchange(ctx, code, testRedirectURI, otherVerifier)
+	assert.ErrorIs(t, err, auth.ErrOIDCProvider)
+}
+
+func TestOIDCProvider_VerifyIDToken(t *testing.T) {
+	mock, issuer := newMockOIDC(t)
+	ctx := context.Background()
+	user := oidctest.User{Subject: "user-2", Email: "user@example.com"}
+
+	idToken, err := mock.IDToken(user, "nonce")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		clientID string
+		nonce    string
+		wantErr  bool
+	}{
+		{name: "valid", clientID: "egrul-gateway", nonce: "nonce"},
+		{name: "nonce mismatch", clientID: "egrul-gateway", nonce: "other", wantErr: true},
+		{name: "wrong audience", clientID: "other-client", nonce: "nonce", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := auth.NewOIDCProvider(issuer, tt.clientID, "secret", nil, nil)
+			identity, err := provider.VerifyIDToken(ctx, idToken, tt.nonce)
+			if tt.wantErr {
+				assert.True(t, errors.Is(err, auth.ErrInvalidIDToken), "unexpected error: %v", err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "user-2", identity.Subject)
+			assert.False(t, identity.EmailVerified)
+		})
+	}
+
+	t.Run("tampered signature", func(t *testing.T) {
+		provider := auth.NewOIDCProvider(issuer, "egrul-gateway", "secret", nil, nil)
+		tampered := idToken[:len(idToken)-4] + "AAAA"
+		_, err := provider.VerifyIDToken(ctx, tampered, "nonce")
+		assert.ErrorIs(t, err, auth.ErrInvalidIDToken)
+	})
+}
+
+func TestOIDCProvider_IssuerMismatch(t *testing.T) {
+	mock, issuer := newMockOIDC(t)
+	// Discovery отдает другой issuer, чем настроен в клиенте
+	mock.SetIssuer("https://idp.example.com")
+	provider := auth.NewOIDCProvider(issuer, "egrul-gateway", "secret", nil, nil)
+
+	_, err := provider.Issuer(context.Background())
+	assert.ErrorIs(t, err, auth.ErrOIDCProvider)
+}
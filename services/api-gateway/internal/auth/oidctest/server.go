@@ -0,0 +1,305 @@
+// Package oidctest содержит mock OpenID Connect провайдера для тестов и
+// локальной разработки: discovery, JWKS, authorization code + PKCE (S256).
+// Вход подтверждается автоматически от имени пользователя User.
+package oidctest
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// User пользователь, от имени которого mock подтверждает вход
+type User struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	GivenName     string
+	FamilyName    string
+	Groups        []string
+}
+
+// Config параметры mock провайдера
+type Config struct {
+	// Issuer внешний адрес провайдера, например http://localhost:9099
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	// RedirectURIs разрешенные redirect_uri; пустой список разрешает любой
+	RedirectURIs []string
+	// GroupsClaim имя claim со списком групп (по умолчанию groups)
+	GroupsClaim string
+	User        User
+}
+
+type authCode struct {
+	redirectURI   string
+	codeChallenge string
+	nonce         string
+	user          User
+	expiresAt     time.Time
+}
+
+// Server mock провайдер; реализует http.Handler
+type Server struct {
+	cfg Config
+	key *rsa.PrivateKey
+	kid string
+	mux *http.ServeMux
+
+	mu    sync.Mutex
+	codes map[string]*authCode
+}
+
+// NewServer создает mock провайдер с новым RSA ключом подписи
+func NewServer(cfg Config) (*Server, error) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.GroupsClaim == "" {
+		cfg.GroupsClaim = "groups"
+	}
+	cfg.Issuer = strings.TrimSuffix(cfg.Issuer, "/")
+
+	s := &Server{
+		cfg:   cfg,
+		key:   key,
+		kid:   randomString(8),
+		mux:   http.NewServeMux(),
+		codes: make(map[string]*authCode),
+	}
+	s.mux.HandleFunc("/.well-known/openid-configuration", s.handleDiscovery)
+	s.mux.HandleFunc("/jwks", s.handleJWKS)
+	s.mux.HandleFunc("/authorize", s.handleAuthorize)
+	s.mux.HandleFunc("/token", s.handleToken)
+	return s, nil
+}
+
+// SetIssuer меняет issuer (адрес httptest сервера известен после запуска)
+func (s *Server) SetIssuer(issuer string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cfg.Issuer = strings.TrimSuffix(issuer, "/")
+}
+
+// SetUser меняет пользователя для следующих входов
+func (s *Server) SetUser(user User) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cfg.User = user
+}
+
+// ServeHTTP реализует http.Handler
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+func (s *Server) issuer() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.cfg.Issuer
+}
+
+func (s *Server) handleDiscovery(w http.ResponseWriter, r *http.Request) {
+	issuer := s.issuer()
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"issuer":                                issuer,
+		"authorization_endpoint":                issuer + "/authorize",
+		"token_endpoint":                        issuer + "/token",
+		"jwks_uri":                              issuer + "/jwks",
+		"response_types_supported":              []string{"code"},
+		"subject_types_supported":               []string{"public"},
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+		"code_challenge_methods_supported":      []string{"S256"},
+		"scopes_supported":                      []string{"openid", "email", "profile", "groups"},
+	})
+}
+
+func (s *Server) handleJWKS(w http.ResponseWriter, r *http.Request) {
+	pub := s.key.PublicKey
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": s.kid,
+			"use": "sig",
+			"alg": "RS256",
+			"n":   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+		}},
+	})
+}
+
+// handleAuthorize сразу подтверждает вход и возвращает код на redirect_uri
+func (s *Server) handleAuthorize(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	redirectURI := q.Get("redirect_uri")
+
+	switch {
+	case q.Get("client_id") != s.cfg.ClientID:
+		http.Error(w, "unknown client_id", http.StatusBadRequest)
+		return
+	case !s.redirectAllowed(redirectURI):
+		http.Error(w, "redirect_uri is not registered", http.StatusBadRequest)
+		return
+	case q.Get("response_type") != "code":
+		http.Error(w, "unsupported response_type", http.StatusBadRequest)
+		return
+	case q.Get("code_challenge") == "" || q.Get("code_challenge_method") != "S256":
+		http.Error(w, "PKCE with S256 is required", http.StatusBadRequest)
+		return
+	}
+
+	code := randomString(24)
+	s.mu.Lock()
+	s.codes[code] = &authCode{
+		redirectURI:   redirectURI,
+		codeChallenge: q.Get("code_challenge"),
+		nonce:         q.Get("nonce"),
+		user:          s.cfg.User,
+		expiresAt:     time.Now().Add(time.Minute),
+	}
+	s.mu.Unlock()
+
+	target, err := url.Parse(redirectURI)
+	if err != nil {
+		http.Error(w, "invalid redirect_uri", http.StatusBadRequest)
+		return
+	}
+	params := target.Query()
+	params.Set("code", code)
+	params.Set("state", q.Get("state"))
+	target.RawQuery = params.Encode()
+	http.Redirect(w, r, target.String(), http.StatusFound)
+}
+
+func (s *Server) handleToken(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		tokenError(w, "invalid_request", "malformed form")
+		return
+	}
+
+	clientID, clientSecret, ok := r.BasicAuth()
+	if ok {
+		clientID, _ = url.QueryUnescape(clientID)
+		clientSecret, _ = url.QueryUnescape(clientSecret)
+	} else {
+		clientID = r.PostForm.Get("client_id")
+		clientSecret = r.PostForm.Get("client_secret")
+	}
+	if clientID != s.cfg.ClientID || subtle.ConstantTimeCompare([]byte(clientSecret), []byte(s.cfg.ClientSecret)) != 1 {
+		tokenError(w, "invalid_client", "client authentication failed")
+		return
+	}
+	if r.PostForm.Get("grant_type") != "authorization_code" {
+		tokenError(w, "unsupported_grant_type", "")
+		return
+	}
+
+	s.mu.Lock()
+	code, found := s.codes[r.PostForm.Get("code")]
+	delete(s.codes, r.PostForm.Get("code"))
+	s.mu.Unlock()
+
+	switch {
+	case !found || time.Now().After(code.expiresAt):
+		tokenError(w, "invalid_grant", "unknown or expired code")
+		return
+	case code.redirectURI != r.PostForm.Get("redirect_uri"):
+		tokenError(w, "invalid_grant", "redirect_uri mismatch")
+		return
+	case pkceChallenge(r.PostForm.Get("code_verifier")) != code.codeChallenge:
+		tokenError(w, "invalid_grant", "code_verifier mismatch")
+		return
+	}
+
+	idToken, err := s.IDToken(code.user, code.nonce)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"access_token": randomString(24),
+		"token_type":   "Bearer",
+		"expires_in":   3600,
+		"id_token":     idToken,
+	})
+}
+
+// IDToken подписанный ID токен пользователя
+func (s *Server) IDToken(user User, nonce string) (string, error) {
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"iss":             s.issuer(),
+		"sub":             user.Subject,
+		"aud":             s.cfg.ClientID,
+		"iat":             now.Unix(),
+		"exp":             now.Add(5 * time.Minute).Unix(),
+		"email":           user.Email,
+		"email_verified":  user.EmailVerified,
+		"given_name":      user.GivenName,
+		"family_name":     user.FamilyName,
+		s.cfg.GroupsClaim: user.Groups,
+	}
+	if nonce != "" {
+		claims["nonce"] = nonce
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = s.kid
+	return token.SignedString(s.key)
+}
+
+func (s *Server) redirectAllowed(redirectURI string) bool {
+	if redirectURI == "" {
+		return false
+	}
+	if len(s.cfg.RedirectURIs) == 0 {
+		return true
+	}
+	for _, allowed := range s.cfg.RedirectURIs {
+		if allowed == redirectURI {
+			return true
+		}
+	}
+	return false
+}
+
+func pkceChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func randomString(n int) string {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func tokenError(w http.ResponseWriter, code, description string) {
+	writeJSON(w, http.StatusBadRequest, map[string]string{
+		"error":             code,
+		"error_description": description,
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
@@ -1,11 +1,37 @@
 package auth
 
 import (
+	"errors"
+
 	"golang.org/x/crypto/bcrypt"
 )
 
 const bcryptCost = 12
 
+// Ограничения длины пароля; bcrypt учитывает только первые 72 байта
+const (
+	MinPasswordLength = 8
+	MaxPasswordLength = 72
+)
+
+var (
+	// ErrPasswordTooShort пароль короче MinPasswordLength
+	ErrPasswordTooShort = errors.New("password must be at least 8 characters long")
+	// ErrPasswordTooLong пароль длиннее MaxPasswordLength байт
+	ErrPasswordTooLong = errors.New("password must be at most 72 bytes long")
+)
+
+// ValidatePassword проверяет длину нового пароля
+func ValidatePassword(password string) error {
+	if len([]rune(password)) < MinPasswordLength {
+		return ErrPasswordTooShort
+	}
+	if len(password) > MaxPasswordLength {
+		return ErrPasswordTooLong
+	}
+	return nil
+}
+
 // HashPassword хеширует пароль с использованием bcrypt
 func HashPassword(password string) (string, error) {
 	bytes, err := bcrypt.GenerateFromPassword([]byte(password), bcryptCost)
@@ -0,0 +1,44 @@
+package auth
+
+import (
+	"net/http"
+
+	"github.com/egrul-system/services/shared/pkg/roles"
+)
+
+// Роли пользователей. Роли упорядочены: старшая включает права младших;
+// иерархия общая с другими сервисами (shared/pkg/roles).
+const (
+	RoleUser    = roles.User
+	RoleAnalyst = roles.Analyst
+	RoleAdmin   = roles.Admin
+)
+
+// IsValidRole проверяет, что роль известна
+func IsValidRole(role string) bool {
+	return roles.IsValid(role)
+}
+
+// HasRole проверяет, что роль не ниже требуемой. Пустая роль (токены,
+// выданные до появления ролей) считается ролью user.
+func HasRole(role, required string) bool {
+	return roles.HasRole(role, required)
+}
+
+// RequireRole создает HTTP middleware, пропускающий только пользователей с ролью
+// не ниже required. Должен стоять после JWTManager.Middleware.
+func RequireRole(required string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if GetUserIDFromContext(r.Context()) == "" {
+				http.Error(w, "Authentication required", http.StatusUnauthorized)
+				return
+			}
+			if !HasRole(GetRoleFromContext(r.Context()), required) {
+				http.Error(w, "Forbidden", http.StatusForbidden)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -20,6 +20,15 @@ type Cache interface {
 	Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error
 	// Delete удаляет ключ.
 	Delete(ctx context.Context, key string) error
+	// Incr увеличивает счетчик на 1 и возвращает новое значение; TTL
+	// выставляется при создании счетчика.
+	Incr(ctx context.Context, key string, ttl time.Duration) (int64, error)
+	// IncrBy атомарно увеличивает счетчик на n, как Incr.
+	IncrBy(ctx context.Context, key string, n int64, ttl time.Duration) (int64, error)
+	// TakeToken забирает токен из корзины key (token bucket): корзина вмещает
+	// burst токенов и пополняется со скоростью ratePerSecond. Если токенов нет,
+	// возвращает allowed=false и время до появления следующего токена.
+	TakeToken(ctx context.Context, key string, ratePerSecond float64, burst int) (allowed bool, retryAfter time.Duration, err error)
 	// Close закрывает клиент.
 	Close() error
 }
@@ -106,6 +115,77 @@ func (c *RedisCache) Delete(ctx context.Context, key string) error {
 	return nil
 }
 
+// Incr реализует Cache.Incr.
+func (c *RedisCache) Incr(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	return c.IncrBy(ctx, key, 1, ttl)
+}
+
+// IncrBy реализует Cache.IncrBy.
+func (c *RedisCache) IncrBy(ctx context.Context, key string, n int64, ttl time.Duration) (int64, error) {
+	if c == nil || c.client == nil {
+		return 0, nil
+	}
+
+	pipe := c.client.TxPipeline()
+	incr := pipe.IncrBy(ctx, key, n)
+	pipe.ExpireNX(ctx, key, ttl)
+	if _, err := pipe.Exec(ctx); err != nil {
+		c.logger.Warn("redis incr failed", zap.String("key", key), zap.Error(err))
+		return 0, err
+	}
+	return incr.Val(), nil
+}
+
+// tokenBucketScript атомарно пополняет корзину по прошедшему времени и забирает
+// токен. Время берется у Redis, чтобы экземпляры gateway не зависели от
+// расхождения своих часов. Возвращает {allowed, retry_after_ms}.
+var tokenBucketScript = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000 + math.floor(tonumber(t[2]) / 1000)
+
+local bucket = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(bucket[1])
+local ts = tonumber(bucket[2])
+if tokens == nil or ts == nil then
+	tokens = burst
+	ts = now
+end
+
+tokens = math.min(burst, tokens + math.max(0, now - ts) * rate / 1000)
+
+local allowed = 0
+local retry = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+else
+	retry = math.ceil((1 - tokens) * 1000 / rate)
+end
+
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'ts', now)
+redis.call('PEXPIRE', KEYS[1], math.ceil(burst * 1000 / rate) + 1000)
+return {allowed, retry}
+`)
+
+// TakeToken реализует Cache.TakeToken.
+func (c *RedisCache) TakeToken(ctx context.Context, key string, ratePerSecond float64, burst int) (bool, time.Duration, error) {
+	if c == nil || c.client == nil || ratePerSecond <= 0 || burst <= 0 {
+		return true, 0, nil
+	}
+
+	result, err := tokenBucketScript.Run(ctx, c.client, []string{key}, ratePerSecond, burst).Int64Slice()
+	if err != nil {
+		c.logger.Warn("redis token bucket failed", zap.String("key", key), zap.Error(err))
+		return false, 0, err
+	}
+	if len(result) != 2 {
+		return false, 0, fmt.Errorf("unexpected token bucket result: %v", result)
+	}
+	return result[0] == 1, time.Duration(result[1]) * time.Millisecond, nil
+}
+
 // Close закрывает соединение с Redis.
 func (c *RedisCache) Close() error {
 	if c == nil || c.client == nil {
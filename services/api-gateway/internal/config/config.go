@@ -21,6 +21,12 @@ type Config struct {
 	Log             LogConfig             `mapstructure:"log"`
 	GraphQL         GraphQLConfig         `mapstructure:"graphql"`
 	Auth            AuthConfig            `mapstructure:"auth"`
+	BulkCheck       BulkCheckConfig       `mapstructure:"bulk_check"`
+	Export          ExportConfig          `mapstructure:"export"`
+	Risk            RiskConfig            `mapstructure:"risk"`
+	RateLimit       RateLimitConfig       `mapstructure:"rate_limit"`
+	OIDC            OIDCConfig            `mapstructure:"oidc"`
+	Audit           AuditConfig           `mapstructure:"audit"`
 }
 
 // ServerConfig - конфигурация HTTP сервера
@@ -90,8 +96,122 @@ type GraphQLConfig struct {
 
 // AuthConfig - конфигурация аутентификации
 type AuthConfig struct {
-	JWTSecretKey     string        `mapstructure:"jwt_secret_key"`
-	JWTTokenDuration time.Duration `mapstructure:"jwt_token_duration"`
+	JWTSecretKey         string        `mapstructure:"jwt_secret_key"`
+	JWTTokenDuration     time.Duration `mapstructure:"jwt_token_duration"`
+	RefreshTokenTTL      time.Duration `mapstructure:"refresh_token_ttl"`
+	EmailVerificationTTL time.Duration `mapstructure:"email_verification_ttl"`
+	PasswordResetTTL     time.Duration `mapstructure:"password_reset_ttl"`
+	// AccountDeletionGracePeriod срок, в течение которого запрошенное
+	// пользователем удаление учетной записи можно отменить
+	AccountDeletionGracePeriod time.Duration `mapstructure:"account_deletion_grace_period"`
+	// AccountDeletionReauthWindow сколько после входа пользователь SSO без
+	// пароля может запросить удаление учетной записи
+	AccountDeletionReauthWindow time.Duration `mapstructure:"account_deletion_reauth_window"`
+}
+
+// OIDCConfig - вход через корпоративный OpenID Connect провайдер (SSO)
+type OIDCConfig struct {
+	Enabled      bool   `mapstructure:"enabled"`
+	IssuerURL    string `mapstructure:"issuer_url"`
+	ClientID     string `mapstructure:"client_id"`
+	ClientSecret string `mapstructure:"client_secret"`
+	// RedirectURLs разрешенные адреса возврата после входа (страница фронтенда,
+	// завершающая вход); первый используется по умолчанию
+	RedirectURLs []string `mapstructure:"redirect_urls"`
+	Scopes       []string `mapstructure:"scopes"`
+	// GroupsClaim claim ID токена со списком групп
+	GroupsClaim string `mapstructure:"groups_claim"`
+	// GroupRoles соответствие групп ролям в формате "group=role;group=role".
+	// Если задано, роль пользователя SSO обновляется по группам при каждом входе.
+	GroupRoles string `mapstructure:"group_roles"`
+	// LinkByEmail связывать первый вход с существующим пользователем по email,
+	// если провайдер подтвердил email
+	LinkByEmail bool          `mapstructure:"link_by_email"`
+	StateTTL    time.Duration `mapstructure:"state_ttl"`
+}
+
+// AuditConfig - журнал аудита действий пользователей (ClickHouse egrul.audit_log).
+// События копятся в памяти и записываются пачками; при переполнении буфера
+// новые события отбрасываются, чтобы не задерживать запросы.
+type AuditConfig struct {
+	Enabled       bool          `mapstructure:"enabled"`
+	BufferSize    int           `mapstructure:"buffer_size"`
+	BatchSize     int           `mapstructure:"batch_size"`
+	FlushInterval time.Duration `mapstructure:"flush_interval"`
+	// RetentionDays срок хранения записи; сохраняется в каждой строке (TTL таблицы)
+	RetentionDays int `mapstructure:"retention_days"`
+}
+
+// BulkCheckConfig - конфигурация пакетной проверки контрагентов
+type BulkCheckConfig struct {
+	Workers              int   `mapstructure:"workers"`
+	QueueSize            int   `mapstructure:"queue_size"`
+	MaxRows              int   `mapstructure:"max_rows"`
+	MaxFileSize          int64 `mapstructure:"max_file_size"`
+	MassAddressThreshold int   `mapstructure:"mass_address_threshold"`
+	// StaleJobTimeout через сколько без отметки прогресса задание в статусе
+	// running считается брошенным и при запуске возвращается в очередь
+	StaleJobTimeout time.Duration `mapstructure:"stale_job_timeout"`
+}
+
+// ExportConfig - конфигурация выгрузки списков в CSV/XLSX
+type ExportConfig struct {
+	MaxRows       int `mapstructure:"max_rows"`
+	DailyRowQuota int `mapstructure:"daily_row_quota"`
+}
+
+// RiskConfig - конфигурация оценки риска контрагента
+type RiskConfig struct {
+	Weights                  RiskWeightsConfig `mapstructure:"weights"`
+	YoungCompanyMonths       int               `mapstructure:"young_company_months"`
+	MinimalCapital           float64           `mapstructure:"minimal_capital"`
+	MassAddressThreshold     int               `mapstructure:"mass_address_threshold"`
+	MassDirectorThreshold    int               `mapstructure:"mass_director_threshold"`
+	FrequentChangesThreshold int               `mapstructure:"frequent_changes_threshold"`
+	MediumLevelScore         int               `mapstructure:"medium_level_score"`
+	HighLevelScore           int               `mapstructure:"high_level_score"`
+}
+
+// RiskWeightsConfig - веса факторов риска (0 отключает фактор)
+type RiskWeightsConfig struct {
+	Liquidating             int `mapstructure:"liquidating"`
+	Bankrupt                int `mapstructure:"bankrupt"`
+	Reorganizing            int `mapstructure:"reorganizing"`
+	YoungCompany            int `mapstructure:"young_company"`
+	MinimalCapital          int `mapstructure:"minimal_capital"`
+	MassAddress             int `mapstructure:"mass_address"`
+	MassDirector            int `mapstructure:"mass_director"`
+	FrequentDirectorChanges int `mapstructure:"frequent_director_changes"`
+	FrequentAddressChanges  int `mapstructure:"frequent_address_changes"`
+	ForeignFounders         int `mapstructure:"foreign_founders"`
+	NoDirector              int `mapstructure:"no_director"`
+}
+
+// RateLimitConfig - ограничение частоты запросов (token bucket в Redis).
+// Бюджеты задаются по ролям; Anonymous - для запросов без авторизации.
+type RateLimitConfig struct {
+	Enabled   bool             `mapstructure:"enabled"`
+	Anonymous RateLimitBudgets `mapstructure:"anonymous"`
+	User      RateLimitBudgets `mapstructure:"user"`
+	Analyst   RateLimitBudgets `mapstructure:"analyst"`
+	Admin     RateLimitBudgets `mapstructure:"admin"`
+}
+
+// RateLimitBudgets - бюджеты одной роли по типам операций
+type RateLimitBudgets struct {
+	Search   RateLimitBucket `mapstructure:"search"`
+	List     RateLimitBucket `mapstructure:"list"`
+	Export   RateLimitBucket `mapstructure:"export"`
+	Mutation RateLimitBucket `mapstructure:"mutation"`
+	// Query - остальные запросы (карточки компаний, статистика, профиль)
+	Query RateLimitBucket `mapstructure:"query"`
+}
+
+// RateLimitBucket - параметры корзины: пополнение в минуту и емкость
+// (0 в PerMinute отключает ограничение)
+type RateLimitBucket struct {
+	PerMinute int `mapstructure:"per_minute"`
+	Burst     int `mapstructure:"burst"`
 }
 
 // KafkaConfig - конфигурация Kafka
@@ -100,6 +220,7 @@ type KafkaConfig struct {
 	CompanyTopic         string   `mapstructure:"company_topic"`
 	EntrepreneurTopic    string   `mapstructure:"entrepreneur_topic"`
 	ConsumerGroup        string   `mapstructure:"consumer_group"`
+	AccountEmailsTopic   string   `mapstructure:"account_emails_topic"`
 }
 
 // NotificationHubConfig - конфигурация Notification Hub
@@ -207,13 +328,86 @@ func setDefaults(v *viper.Viper) {
 
 	// Auth
 	v.SetDefault("auth.jwt_secret_key", "CHANGE_ME_IN_PRODUCTION_MIN_32_CHARS")
-	v.SetDefault("auth.jwt_token_duration", 24*time.Hour)
+	v.SetDefault("auth.jwt_token_duration", 15*time.Minute)
+	v.SetDefault("auth.refresh_token_ttl", 30*24*time.Hour)
+	v.SetDefault("auth.email_verification_ttl", 24*time.Hour)
+	v.SetDefault("auth.password_reset_ttl", time.Hour)
+	v.SetDefault("auth.account_deletion_grace_period", 30*24*time.Hour)
+	v.SetDefault("auth.account_deletion_reauth_window", 10*time.Minute)
+
+	// OIDC SSO
+	v.SetDefault("oidc.enabled", false)
+	v.SetDefault("oidc.issuer_url", "")
+	v.SetDefault("oidc.client_id", "")
+	v.SetDefault("oidc.client_secret", "")
+	v.SetDefault("oidc.redirect_urls", []string{"http://localhost:3000/auth/sso/callback"})
+	v.SetDefault("oidc.scopes", []string{"openid", "email", "profile"})
+	v.SetDefault("oidc.groups_claim", "groups")
+	v.SetDefault("oidc.group_roles", "")
+	v.SetDefault("oidc.link_by_email", true)
+	v.SetDefault("oidc.state_ttl", 10*time.Minute)
+
+	// Audit log
+	v.SetDefault("audit.enabled", true)
+	v.SetDefault("audit.buffer_size", 10000)
+	v.SetDefault("audit.batch_size", 500)
+	v.SetDefault("audit.flush_interval", 5*time.Second)
+	v.SetDefault("audit.retention_days", 365)
+
+	// Bulk check
+	v.SetDefault("bulk_check.workers", 4)
+	v.SetDefault("bulk_check.queue_size", 100)
+	v.SetDefault("bulk_check.max_rows", 10000)
+	v.SetDefault("bulk_check.max_file_size", 10<<20)
+	v.SetDefault("bulk_check.mass_address_threshold", 10)
+	v.SetDefault("bulk_check.stale_job_timeout", 30*time.Minute)
+
+	// Export
+	v.SetDefault("export.max_rows", 100000)
+	v.SetDefault("export.daily_row_quota", 1000000)
+
+	// Risk assessment
+	v.SetDefault("risk.weights.liquidating", 40)
+	v.SetDefault("risk.weights.bankrupt", 50)
+	v.SetDefault("risk.weights.reorganizing", 20)
+	v.SetDefault("risk.weights.young_company", 15)
+	v.SetDefault("risk.weights.minimal_capital", 10)
+	v.SetDefault("risk.weights.mass_address", 25)
+	v.SetDefault("risk.weights.mass_director", 25)
+	v.SetDefault("risk.weights.frequent_director_changes", 15)
+	v.SetDefault("risk.weights.frequent_address_changes", 10)
+	v.SetDefault("risk.weights.foreign_founders", 10)
+	v.SetDefault("risk.weights.no_director", 20)
+	v.SetDefault("risk.young_company_months", 6)
+	v.SetDefault("risk.minimal_capital", 10000)
+	v.SetDefault("risk.mass_address_threshold", 10)
+	v.SetDefault("risk.mass_director_threshold", 10)
+	v.SetDefault("risk.frequent_changes_threshold", 2)
+	v.SetDefault("risk.medium_level_score", 30)
+	v.SetDefault("risk.high_level_score", 60)
+
+	// Rate limiting: {per_minute, burst} для search, list, export, mutation, query
+	v.SetDefault("rate_limit.enabled", true)
+	rateLimitDefaults := map[string][5][2]int{
+		"anonymous": {{30, 10}, {30, 10}, {5, 2}, {20, 10}, {120, 30}},
+		"user":      {{120, 30}, {60, 20}, {10, 3}, {60, 20}, {300, 60}},
+		"analyst":   {{300, 60}, {180, 40}, {30, 10}, {120, 30}, {600, 120}},
+		"admin":     {{600, 120}, {300, 60}, {60, 20}, {300, 60}, {1200, 240}},
+	}
+	for role, buckets := range rateLimitDefaults {
+		for i, operation := range []string{"search", "list", "export", "mutation", "query"} {
+			prefix := "rate_limit." + role + "." + operation
+			v.SetDefault(prefix+".per_minute", buckets[i][0])
+			v.SetDefault(prefix+".burst", buckets[i][1])
+		}
+	}
 
 	// Kafka
 	v.SetDefault("kafka.brokers", []string{"localhost:9092"})
 	v.SetDefault("kafka.company_topic", "company-changes")
 	v.SetDefault("kafka.entrepreneur_topic", "entrepreneur-changes")
 	v.SetDefault("kafka.consumer_group", "api-gateway-notifications")
+	v.SetDefault("kafka.account_emails_topic", "account-emails")
 
 	// Notification Hub
 	v.SetDefault("notification_hub.enabled", true)
@@ -258,12 +452,48 @@ func bindEnvVariables(v *viper.Viper) {
 	// Auth
 	_ = v.BindEnv("auth.jwt_secret_key", "JWT_SECRET_KEY")
 	_ = v.BindEnv("auth.jwt_token_duration", "JWT_TOKEN_DURATION")
+	_ = v.BindEnv("auth.refresh_token_ttl", "REFRESH_TOKEN_TTL")
+	_ = v.BindEnv("auth.email_verification_ttl", "EMAIL_VERIFICATION_TTL")
+	_ = v.BindEnv("auth.password_reset_ttl", "PASSWORD_RESET_TTL")
+	_ = v.BindEnv("auth.account_deletion_grace_period", "ACCOUNT_DELETION_GRACE_PERIOD")
+	_ = v.BindEnv("auth.account_deletion_reauth_window", "ACCOUNT_DELETION_REAUTH_WINDOW")
+
+	// OIDC SSO (списки - через запятую)
+	_ = v.BindEnv("oidc.enabled", "OIDC_ENABLED")
+	_ = v.BindEnv("oidc.issuer_url", "OIDC_ISSUER_URL")
+	_ = v.BindEnv("oidc.client_id", "OIDC_CLIENT_ID")
+	_ = v.BindEnv("oidc.client_secret", "OIDC_CLIENT_SECRET")
+	_ = v.BindEnv("oidc.redirect_urls", "OIDC_REDIRECT_URLS")
+	_ = v.BindEnv("oidc.scopes", "OIDC_SCOPES")
+	_ = v.BindEnv("oidc.groups_claim", "OIDC_GROUPS_CLAIM")
+	_ = v.BindEnv("oidc.group_roles", "OIDC_GROUP_ROLES")
+	_ = v.BindEnv("oidc.link_by_email", "OIDC_LINK_BY_EMAIL")
+
+	// Audit log
+	_ = v.BindEnv("audit.enabled", "AUDIT_ENABLED")
+	_ = v.BindEnv("audit.buffer_size", "AUDIT_BUFFER_SIZE")
+	_ = v.BindEnv("audit.batch_size", "AUDIT_BATCH_SIZE")
+	_ = v.BindEnv("audit.flush_interval", "AUDIT_FLUSH_INTERVAL")
+	_ = v.BindEnv("audit.retention_days", "AUDIT_RETENTION_DAYS")
+
+	// Bulk check
+	_ = v.BindEnv("bulk_check.workers", "BULK_CHECK_WORKERS")
+	_ = v.BindEnv("bulk_check.max_rows", "BULK_CHECK_MAX_ROWS")
+	_ = v.BindEnv("bulk_check.stale_job_timeout", "BULK_CHECK_STALE_JOB_TIMEOUT")
+
+	// Export
+	_ = v.BindEnv("export.max_rows", "EXPORT_MAX_ROWS")
+	_ = v.BindEnv("export.daily_row_quota", "EXPORT_DAILY_ROW_QUOTA")
+
+	// Rate limiting
+	_ = v.BindEnv("rate_limit.enabled", "RATE_LIMIT_ENABLED")
 
 	// Kafka
 	_ = v.BindEnv("kafka.brokers", "KAFKA_BROKERS")
 	_ = v.BindEnv("kafka.company_topic", "KAFKA_COMPANY_CHANGES_TOPIC")
 	_ = v.BindEnv("kafka.entrepreneur_topic", "KAFKA_ENTREPRENEUR_CHANGES_TOPIC")
 	_ = v.BindEnv("kafka.consumer_group", "NOTIFICATION_HUB_KAFKA_GROUP")
+	_ = v.BindEnv("kafka.account_emails_topic", "KAFKA_ACCOUNT_EMAILS_TOPIC")
 
 	// Notification Hub
 	_ = v.BindEnv("notification_hub.enabled", "NOTIFICATION_HUB_ENABLED")
@@ -298,4 +528,3 @@ func (c *ElasticConfig) URL() string {
 	}
 	return ""
 }
-
@@ -28,6 +28,16 @@ func (r *companyResolver) Founders(ctx context.Context, obj *model.Company, limi
 		o = *offset
 	}
 
+	// Карточка на дату: состав учредителей берем из той же версии выписки
+	if obj.VersionInfo != nil {
+		founders, err := r.CompanyService.GetFoundersAsOf(ctx, obj, l, o)
+		if err != nil {
+			r.Logger.Error("failed to get founders as of date", zap.String("ogrn", obj.Ogrn), zap.Error(err))
+			return nil, err
+		}
+		return founders, nil
+	}
+
 	// Сначала пробуем использовать DataLoader (per-request cache)
 	if loader := foundersLoaderFromContext(ctx); loader != nil {
 		founders, err := loader.Load(ctx, obj.Ogrn, l, o)
@@ -69,6 +79,36 @@ func (r *companyResolver) Branches(ctx context.Context, obj *model.Company) ([]*
 	return branches, nil
 }
 
+// RiskAssessment is the resolver for the riskAssessment field on Company.
+func (r *companyResolver) RiskAssessment(ctx context.Context, obj *model.Company) (*model.RiskAssessment, error) {
+	risk, err := r.RiskService.Assess(ctx, obj)
+	if err != nil {
+		r.Logger.Error("failed to assess company risk", zap.String("ogrn", obj.Ogrn), zap.Error(err))
+		return nil, err
+	}
+	return risk, nil
+}
+
+// MassAddress is the resolver for the massAddress field on Company.
+func (r *companyResolver) MassAddress(ctx context.Context, obj *model.Company) (*model.MassAddress, error) {
+	address, err := r.RiskService.MassAddress(ctx, obj)
+	if err != nil {
+		r.Logger.Error("failed to get mass address", zap.String("ogrn", obj.Ogrn), zap.Error(err))
+		return nil, err
+	}
+	return address, nil
+}
+
+// MassDirector is the resolver for the massDirector field on Person.
+func (r *personResolver) MassDirector(ctx context.Context, obj *model.Person) (*model.MassDirector, error) {
+	director, err := r.RiskService.MassDirector(ctx, obj)
+	if err != nil {
+		r.Logger.Error("failed to get mass director", zap.Error(err))
+		return nil, err
+	}
+	return director, nil
+}
+
 // History is the resolver for the history field on Company.
 func (r *companyResolver) History(ctx context.Context, obj *model.Company, limit *int, offset *int) ([]*model.HistoryRecord, error) {
 	// Попробуем извлечь параметры из GraphQL контекста
@@ -494,3 +534,8 @@ func (r *Resolver) Company() CompanyResolver { return &companyResolver{r} }
 
 type companyResolver struct{ *Resolver }
 
+// Person returns PersonResolver implementation.
+func (r *Resolver) Person() PersonResolver { return &personResolver{r} }
+
+type personResolver struct{ *Resolver }
+
@@ -0,0 +1,133 @@
+package graph
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.56
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/egrul-system/services/api-gateway/internal/auth"
+	"github.com/egrul-system/services/api-gateway/internal/graph/model"
+	"github.com/egrul-system/services/api-gateway/internal/repository/postgresql"
+	"go.uber.org/zap"
+)
+
+// SetUserRole is the resolver for the setUserRole field.
+func (r *mutationResolver) SetUserRole(ctx context.Context, id string, role model.Role) (*model.User, error) {
+	if r.AdminService == nil {
+		return nil, fmt.Errorf("admin service not configured")
+	}
+
+	user, err := r.AdminService.SetUserRole(ctx, auth.GetUserIDFromContext(ctx), id, role.DBValue())
+	if err != nil {
+		return nil, err
+	}
+	return toGraphQLUser(user), nil
+}
+
+// DeactivateUser is the resolver for the deactivateUser field.
+func (r *mutationResolver) DeactivateUser(ctx context.Context, id string) (*model.User, error) {
+	if r.AdminService == nil {
+		return nil, fmt.Errorf("admin service not configured")
+	}
+
+	user, err := r.AdminService.DeactivateUser(ctx, auth.GetUserIDFromContext(ctx), id)
+	if err != nil {
+		return nil, err
+	}
+	return toGraphQLUser(user), nil
+}
+
+// Users is the resolver for the users field.
+func (r *queryResolver) Users(ctx context.Context, filter *model.UserFilter, limit *int, offset *int) (*model.UserList, error) {
+	if r.AdminService == nil {
+		return nil, fmt.Errorf("admin service not configured")
+	}
+
+	var listFilter postgresql.UserListFilter
+	if filter != nil {
+		if filter.Query != nil {
+			listFilter.Query = strings.TrimSpace(*filter.Query)
+		}
+		if filter.Role != nil {
+			listFilter.Role = filter.Role.DBValue()
+		}
+		listFilter.IsActive = filter.IsActive
+	}
+
+	l, o := 0, 0
+	if limit != nil {
+		l = *limit
+	}
+	if offset != nil {
+		o = *offset
+	}
+
+	users, total, err := r.AdminService.ListUsers(ctx, listFilter, l, o)
+	if err != nil {
+		r.Logger.Error("failed to list users", zap.Error(err))
+		return nil, fmt.Errorf("failed to list users: %w", err)
+	}
+
+	items := make([]*model.User, len(users))
+	for i, user := range users {
+		items[i] = toGraphQLUser(user)
+	}
+	return &model.UserList{Items: items, Total: total}, nil
+}
+
+// SystemStats is the resolver for the systemStats field.
+func (r *queryResolver) SystemStats(ctx context.Context) (*model.SystemStats, error) {
+	if r.AdminService == nil {
+		return nil, fmt.Errorf("admin service not configured")
+	}
+
+	stats, err := r.AdminService.SystemStats(ctx)
+	if err != nil {
+		r.Logger.Error("failed to get system stats", zap.Error(err))
+		return nil, fmt.Errorf("failed to get system stats: %w", err)
+	}
+
+	result := &model.SystemStats{
+		UsersByRole:        make([]*model.RoleCount, 0, len(model.AllRoles)),
+		Subscriptions:      make([]*model.SubscriptionStatsEntry, len(stats.Subscriptions)),
+		Notifications:      make([]*model.NotificationStatsEntry, len(stats.Notifications)),
+		NotificationsSince: stats.NotificationsSince,
+	}
+	for _, role := range model.AllRoles {
+		result.UsersByRole = append(result.UsersByRole, &model.RoleCount{
+			Role:  role,
+			Count: stats.UsersByRole[role.DBValue()],
+		})
+	}
+	for i, s := range stats.Subscriptions {
+		result.Subscriptions[i] = &model.SubscriptionStatsEntry{
+			EntityType:  s.EntityType,
+			Total:       s.TotalSubscriptions,
+			Active:      s.ActiveSubscriptions,
+			Inactive:    s.InactiveSubscriptions,
+			UniqueUsers: s.UniqueUsers,
+		}
+	}
+	for i, s := range stats.Notifications {
+		result.Notifications[i] = &model.NotificationStatsEntry{
+			Channel:            s.Channel,
+			Status:             s.Status,
+			Count:              s.TotalCount,
+			Retried:            s.RetriedCount,
+			AvgDeliverySeconds: s.AvgDeliveryTimeSeconds,
+		}
+	}
+	if stats.NotificationHub != nil {
+		result.NotificationHub = &model.NotificationHubStats{
+			ConnectedClients: stats.NotificationHub.TotalClients,
+			MaxClients:       stats.NotificationHub.MaxClients,
+			BroadcastQueue:   stats.NotificationHub.BroadcastQueue,
+		}
+	}
+
+	return result, nil
+}
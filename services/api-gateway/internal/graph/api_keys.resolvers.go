@@ -0,0 +1,128 @@
+package graph
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.56
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/egrul-system/services/api-gateway/internal/auth"
+	"github.com/egrul-system/services/api-gateway/internal/graph/model"
+	"github.com/egrul-system/services/api-gateway/internal/repository/postgresql"
+	"github.com/egrul-system/services/api-gateway/internal/service"
+	"go.uber.org/zap"
+)
+
+// CreateAPIKey is the resolver for the createApiKey field.
+func (r *mutationResolver) CreateAPIKey(ctx context.Context, input model.CreateAPIKeyInput) (*model.CreateAPIKeyPayload, error) {
+	userID, err := r.apiKeyOwnerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	scopes := make([]string, len(input.Scopes))
+	for i, scope := range input.Scopes {
+		scopes[i] = scope.Value()
+	}
+
+	rawKey, key, err := r.APIKeyService.Create(ctx, userID, service.CreateAPIKeyParams{
+		Name:        input.Name,
+		Scopes:      scopes,
+		IPAllowlist: input.IPAllowlist,
+		DailyQuota:  input.DailyQuota,
+		ExpiresAt:   input.ExpiresAt,
+	})
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidAPIKeyParams) || errors.Is(err, service.ErrTooManyAPIKeys) {
+			return nil, err
+		}
+		r.Logger.Error("failed to create api key", zap.Error(err), zap.String("user_id", userID))
+		return nil, fmt.Errorf("failed to create api key: %w", err)
+	}
+
+	return &model.CreateAPIKeyPayload{
+		APIKey: toGraphQLAPIKey(key),
+		Key:    rawKey,
+	}, nil
+}
+
+// RevokeAPIKey is the resolver for the revokeApiKey field.
+func (r *mutationResolver) RevokeAPIKey(ctx context.Context, id string) (bool, error) {
+	userID, err := r.apiKeyOwnerID(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	if err := r.APIKeyService.Revoke(ctx, userID, id); err != nil {
+		if errors.Is(err, service.ErrAPIKeyNotFound) {
+			return false, err
+		}
+		r.Logger.Error("failed to revoke api key", zap.Error(err), zap.String("id", id))
+		return false, fmt.Errorf("failed to revoke api key: %w", err)
+	}
+	return true, nil
+}
+
+// MyAPIKeys is the resolver for the myApiKeys field.
+func (r *queryResolver) MyAPIKeys(ctx context.Context) ([]*model.APIKey, error) {
+	userID, err := r.apiKeyOwnerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := r.APIKeyService.List(ctx, userID)
+	if err != nil {
+		r.Logger.Error("failed to list api keys", zap.Error(err), zap.String("user_id", userID))
+		return nil, fmt.Errorf("failed to list api keys: %w", err)
+	}
+
+	result := make([]*model.APIKey, len(keys))
+	for i, key := range keys {
+		result[i] = toGraphQLAPIKey(key)
+	}
+	return result, nil
+}
+
+// apiKeyOwnerID пользователь, управляющий ключами. Управлять ключами можно
+// только по JWT: ключ не может выпускать или отзывать другие ключи.
+func (r *Resolver) apiKeyOwnerID(ctx context.Context) (string, error) {
+	if r.APIKeyService == nil {
+		return "", fmt.Errorf("api key service not configured")
+	}
+	userID := auth.GetUserIDFromContext(ctx)
+	if userID == "" {
+		return "", errAuthenticationRequired
+	}
+	if auth.GetAPIKeyIDFromContext(ctx) != "" {
+		return "", errors.New("api keys cannot be managed with an api key")
+	}
+	return userID, nil
+}
+
+// toGraphQLAPIKey конвертирует postgresql.APIKey в model.APIKey
+func toGraphQLAPIKey(key *postgresql.APIKey) *model.APIKey {
+	scopes := make([]model.APIKeyScope, len(key.Scopes))
+	for i, scope := range key.Scopes {
+		scopes[i] = model.ParseAPIKeyScope(scope)
+	}
+	ipAllowlist := key.IPAllowlist
+	if ipAllowlist == nil {
+		ipAllowlist = []string{}
+	}
+
+	return &model.APIKey{
+		ID:           key.ID,
+		Name:         key.Name,
+		Prefix:       key.KeyPrefix,
+		Scopes:       scopes,
+		IPAllowlist:  ipAllowlist,
+		DailyQuota:   key.DailyQuota,
+		ExpiresAt:    key.ExpiresAt,
+		CreatedAt:    key.CreatedAt,
+		LastUsedAt:   key.LastUsedAt,
+		RequestCount: int(key.RequestCount),
+	}
+}
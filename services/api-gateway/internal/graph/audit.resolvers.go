@@ -0,0 +1,122 @@
+package graph
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.56
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/egrul-system/services/api-gateway/internal/graph/model"
+	"github.com/egrul-system/services/api-gateway/internal/service"
+	"go.uber.org/zap"
+)
+
+// AuditLog is the resolver for the auditLog field.
+func (r *queryResolver) AuditLog(ctx context.Context, filter *model.AuditLogFilter, limit *int, offset *int) (*model.AuditLogList, error) {
+	if r.AuditService == nil {
+		return nil, fmt.Errorf("audit log is not enabled")
+	}
+
+	l, o := 0, 0
+	if limit != nil {
+		l = *limit
+	}
+	if offset != nil {
+		o = *offset
+	}
+
+	list, err := r.AuditService.List(ctx, filter, l, o)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidAuditFilter) {
+			return nil, err
+		}
+		r.Logger.Error("failed to list audit log", zap.Error(err))
+		return nil, fmt.Errorf("failed to list audit log: %w", err)
+	}
+	return list, nil
+}
+
+// recordLoginFailed записывает неудачную попытку входа по паролю
+func (r *Resolver) recordLoginFailed(ctx context.Context, userID, email, reason string) {
+	r.AuditService.Record(ctx, service.AuditEvent{
+		Action:    model.AuditActionLoginFailed,
+		UserID:    userID,
+		UserEmail: email,
+		Details:   map[string]interface{}{"method": "password", "reason": reason},
+	})
+}
+
+// recordCompanyView записывает просмотр карточки компании
+func (r *Resolver) recordCompanyView(ctx context.Context, company *model.Company, asOf *model.Date) {
+	if company == nil {
+		return
+	}
+	event := service.AuditEvent{
+		Action:     model.AuditActionCompanyView,
+		EntityType: service.AuditEntityCompany,
+		EntityID:   company.Ogrn,
+		Success:    true,
+	}
+	if asOf != nil {
+		event.Details = map[string]interface{}{"asOf": asOf.Format("2006-01-02")}
+	}
+	r.AuditService.Record(ctx, event)
+}
+
+// recordEntrepreneurView записывает просмотр карточки ИП
+func (r *Resolver) recordEntrepreneurView(ctx context.Context, entrepreneur *model.Entrepreneur, asOf *model.Date) {
+	if entrepreneur == nil {
+		return
+	}
+	event := service.AuditEvent{
+		Action:     model.AuditActionEntrepreneurView,
+		EntityType: service.AuditEntityEntrepreneur,
+		EntityID:   entrepreneur.Ogrnip,
+		Success:    true,
+	}
+	if asOf != nil {
+		event.Details = map[string]interface{}{"asOf": asOf.Format("2006-01-02")}
+	}
+	r.AuditService.Record(ctx, event)
+}
+
+// recordSubscriptionChange записывает изменение подписки
+func (r *Resolver) recordSubscriptionChange(ctx context.Context, action model.AuditAction, sub *model.EntitySubscription, details map[string]interface{}) {
+	if details == nil {
+		details = make(map[string]interface{}, 3)
+	}
+	details["entityType"] = sub.EntityType
+	details["entityId"] = sub.EntityID
+	if sub.WorkspaceID != nil {
+		details["workspaceId"] = *sub.WorkspaceID
+	}
+	r.AuditService.Record(ctx, service.AuditEvent{
+		Action:     action,
+		EntityType: service.AuditEntitySubscription,
+		EntityID:   sub.ID,
+		Success:    true,
+		Details:    details,
+	})
+}
+
+// recordFavoriteChange записывает изменение записи избранного
+func (r *Resolver) recordFavoriteChange(ctx context.Context, action model.AuditAction, fav *model.Favorite, details map[string]interface{}) {
+	if details == nil {
+		details = make(map[string]interface{}, 3)
+	}
+	details["entityType"] = fav.EntityType
+	details["entityId"] = fav.EntityID
+	if fav.WorkspaceID != nil {
+		details["workspaceId"] = *fav.WorkspaceID
+	}
+	r.AuditService.Record(ctx, service.AuditEvent{
+		Action:     action,
+		EntityType: service.AuditEntityFavorite,
+		EntityID:   fav.ID,
+		Success:    true,
+		Details:    details,
+	})
+}
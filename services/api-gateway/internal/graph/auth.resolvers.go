@@ -8,11 +8,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"time"
 
 	"github.com/egrul-system/services/api-gateway/internal/auth"
 	"github.com/egrul-system/services/api-gateway/internal/graph/model"
 	"github.com/egrul-system/services/api-gateway/internal/repository/postgresql"
+	"github.com/egrul-system/services/api-gateway/internal/service"
 	"go.uber.org/zap"
 )
 
@@ -21,8 +21,8 @@ func (r *mutationResolver) Register(ctx context.Context, input model.RegisterInp
 	if r.UserRepo == nil {
 		return nil, fmt.Errorf("user repository not configured")
 	}
-	if r.JWTManager == nil {
-		return nil, fmt.Errorf("JWT manager not configured")
+	if r.SessionService == nil {
+		return nil, fmt.Errorf("session service not configured")
 	}
 
 	// Проверяем, существует ли пользователь с таким email
@@ -36,6 +36,10 @@ func (r *mutationResolver) Register(ctx context.Context, input model.RegisterInp
 		return nil, errors.New("user with this email already exists")
 	}
 
+	if err := auth.ValidatePassword(input.Password); err != nil {
+		return nil, err
+	}
+
 	// Хешируем пароль
 	passwordHash, err := auth.HashPassword(input.Password)
 	if err != nil {
@@ -49,6 +53,7 @@ func (r *mutationResolver) Register(ctx context.Context, input model.RegisterInp
 		PasswordHash:  passwordHash,
 		FirstName:     input.FirstName,
 		LastName:      input.LastName,
+		Role:          auth.RoleUser,
 		IsActive:      true,
 		EmailVerified: false, // Требуется email verification
 	}
@@ -58,38 +63,26 @@ func (r *mutationResolver) Register(ctx context.Context, input model.RegisterInp
 		return nil, fmt.Errorf("failed to create user: %w", err)
 	}
 
-	// Генерируем JWT токен
-	token, err := r.JWTManager.Generate(user.ID, user.Email)
+	// Открываем сессию
+	tokens, err := r.SessionService.CreateSession(ctx, user, auth.GetClientInfoFromContext(ctx))
 	if err != nil {
-		r.Logger.Error("failed to generate JWT token", zap.Error(err), zap.String("user_id", user.ID))
-		return nil, fmt.Errorf("failed to generate token: %w", err)
+		r.Logger.Error("failed to create session", zap.Error(err), zap.String("user_id", user.ID))
+		return nil, fmt.Errorf("failed to create session: %w", err)
 	}
 
-	expiresAt := time.Now().Add(24 * time.Hour) // TODO: получать из конфига
+	// Письмо с подтверждением; при ошибке пользователь может запросить его повторно
+	if r.AccountService != nil {
+		if err := r.AccountService.RequestEmailVerification(ctx, user.ID); err != nil {
+			r.Logger.Warn("failed to send verification email", zap.Error(err), zap.String("user_id", user.ID))
+		}
+	}
 
 	r.Logger.Info("user registered successfully",
 		zap.String("user_id", user.ID),
 		zap.String("email", user.Email),
 	)
 
-	// Преобразуем в GraphQL модель
-	gqlUser := &model.User{
-		ID:            user.ID,
-		Email:         user.Email,
-		FirstName:     user.FirstName,
-		LastName:      user.LastName,
-		IsActive:      user.IsActive,
-		EmailVerified: user.EmailVerified,
-		CreatedAt:     user.CreatedAt,
-		UpdatedAt:     user.UpdatedAt,
-		LastLoginAt:   user.LastLoginAt,
-	}
-
-	return &model.AuthResponse{
-		User:      gqlUser,
-		Token:     token,
-		ExpiresAt: expiresAt,
-	}, nil
+	return toAuthResponse(user, tokens), nil
 }
 
 // Login is the resolver for the login field.
@@ -97,8 +90,8 @@ func (r *mutationResolver) Login(ctx context.Context, input model.LoginInput) (*
 	if r.UserRepo == nil {
 		return nil, fmt.Errorf("user repository not configured")
 	}
-	if r.JWTManager == nil {
-		return nil, fmt.Errorf("JWT manager not configured")
+	if r.SessionService == nil {
+		return nil, fmt.Errorf("session service not configured")
 	}
 
 	// Получаем пользователя по email
@@ -109,17 +102,20 @@ func (r *mutationResolver) Login(ctx context.Context, input model.LoginInput) (*
 	}
 
 	if user == nil {
+		r.recordLoginFailed(ctx, "", input.Email, "unknown_email")
 		return nil, errors.New("invalid email or password")
 	}
 
 	// Проверяем пароль
 	if !auth.CheckPassword(input.Password, user.PasswordHash) {
 		r.Logger.Warn("invalid password attempt", zap.String("email", input.Email))
+		r.recordLoginFailed(ctx, user.ID, input.Email, "invalid_password")
 		return nil, errors.New("invalid email or password")
 	}
 
 	// Проверяем активность аккаунта
 	if !user.IsActive {
+		r.recordLoginFailed(ctx, user.ID, input.Email, "account_disabled")
 		return nil, errors.New("user account is disabled")
 	}
 
@@ -129,45 +125,149 @@ func (r *mutationResolver) Login(ctx context.Context, input model.LoginInput) (*
 		// Не прерываем процесс входа из-за ошибки обновления
 	}
 
-	// Генерируем JWT токен
-	token, err := r.JWTManager.Generate(user.ID, user.Email)
+	// Открываем сессию
+	tokens, err := r.SessionService.CreateSession(ctx, user, auth.GetClientInfoFromContext(ctx))
 	if err != nil {
-		r.Logger.Error("failed to generate JWT token", zap.Error(err), zap.String("user_id", user.ID))
-		return nil, fmt.Errorf("failed to generate token: %w", err)
+		r.Logger.Error("failed to create session", zap.Error(err), zap.String("user_id", user.ID))
+		return nil, fmt.Errorf("failed to create session: %w", err)
 	}
 
-	expiresAt := time.Now().Add(24 * time.Hour) // TODO: получать из конфига
-
 	r.Logger.Info("user logged in successfully",
 		zap.String("user_id", user.ID),
 		zap.String("email", user.Email),
 	)
+	r.AuditService.Record(ctx, service.AuditEvent{
+		Action:    model.AuditActionLogin,
+		UserID:    user.ID,
+		UserEmail: user.Email,
+		Success:   true,
+		Details:   map[string]interface{}{"method": "password"},
+	})
+
+	return toAuthResponse(user, tokens), nil
+}
 
-	// Преобразуем в GraphQL модель
-	gqlUser := &model.User{
-		ID:            user.ID,
-		Email:         user.Email,
-		FirstName:     user.FirstName,
-		LastName:      user.LastName,
-		IsActive:      user.IsActive,
-		EmailVerified: user.EmailVerified,
-		CreatedAt:     user.CreatedAt,
-		UpdatedAt:     user.UpdatedAt,
-		LastLoginAt:   user.LastLoginAt,
+// RefreshToken is the resolver for the refreshToken field.
+func (r *mutationResolver) RefreshToken(ctx context.Context, refreshToken string) (*model.AuthResponse, error) {
+	if r.SessionService == nil {
+		return nil, fmt.Errorf("session service not configured")
 	}
 
-	return &model.AuthResponse{
-		User:      gqlUser,
-		Token:     token,
-		ExpiresAt: expiresAt,
-	}, nil
+	tokens, user, err := r.SessionService.Refresh(ctx, refreshToken, auth.GetClientInfoFromContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	return toAuthResponse(user, tokens), nil
 }
 
 // Logout is the resolver for the logout field.
 func (r *mutationResolver) Logout(ctx context.Context) (bool, error) {
-	// В случае stateless JWT токенов, logout происходит на клиенте
-	// (удаление токена из localStorage)
-	// Здесь можно добавить blacklist токенов при необходимости
+	if r.SessionService == nil {
+		return false, fmt.Errorf("session service not configured")
+	}
+
+	// Токены без сессии (выданные до появления сессий) отзывать нечего:
+	// клиент просто удаляет их
+	userID := auth.GetUserIDFromContext(ctx)
+	sessionID := auth.GetSessionIDFromContext(ctx)
+	if userID == "" || sessionID == "" {
+		return true, nil
+	}
+
+	if err := r.SessionService.Revoke(ctx, userID, sessionID, postgresql.SessionRevokedLogout); err != nil {
+		r.Logger.Error("failed to revoke session", zap.Error(err), zap.String("session_id", sessionID))
+		return false, fmt.Errorf("failed to logout: %w", err)
+	}
+	return true, nil
+}
+
+// RevokeSession is the resolver for the revokeSession field.
+func (r *mutationResolver) RevokeSession(ctx context.Context, id string) (bool, error) {
+	if r.SessionService == nil {
+		return false, fmt.Errorf("session service not configured")
+	}
+
+	userID := auth.GetUserIDFromContext(ctx)
+	if userID == "" {
+		return false, errors.New("authentication required")
+	}
+
+	if err := r.SessionService.Revoke(ctx, userID, id, postgresql.SessionRevokedByUser); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// RequestEmailVerification is the resolver for the requestEmailVerification field.
+func (r *mutationResolver) RequestEmailVerification(ctx context.Context) (bool, error) {
+	if r.AccountService == nil {
+		return false, fmt.Errorf("account service not configured")
+	}
+
+	userID := auth.GetUserIDFromContext(ctx)
+	if userID == "" {
+		return false, errors.New("authentication required")
+	}
+
+	if err := r.AccountService.RequestEmailVerification(ctx, userID); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// VerifyEmail is the resolver for the verifyEmail field.
+func (r *mutationResolver) VerifyEmail(ctx context.Context, token string) (bool, error) {
+	if r.AccountService == nil {
+		return false, fmt.Errorf("account service not configured")
+	}
+
+	if err := r.AccountService.VerifyEmail(ctx, token); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// RequestPasswordReset is the resolver for the requestPasswordReset field.
+func (r *mutationResolver) RequestPasswordReset(ctx context.Context, email string) (bool, error) {
+	if r.AccountService == nil {
+		return false, fmt.Errorf("account service not configured")
+	}
+
+	if err := r.AccountService.RequestPasswordReset(ctx, email); err != nil {
+		r.Logger.Error("failed to request password reset", zap.Error(err))
+		return false, errors.New("failed to request password reset")
+	}
+	return true, nil
+}
+
+// ResetPassword is the resolver for the resetPassword field.
+func (r *mutationResolver) ResetPassword(ctx context.Context, token string, newPassword string) (bool, error) {
+	if r.AccountService == nil {
+		return false, fmt.Errorf("account service not configured")
+	}
+
+	if err := r.AccountService.ResetPassword(ctx, token, newPassword); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// ChangePassword is the resolver for the changePassword field.
+func (r *mutationResolver) ChangePassword(ctx context.Context, currentPassword string, newPassword string) (bool, error) {
+	if r.AccountService == nil {
+		return false, fmt.Errorf("account service not configured")
+	}
+
+	userID := auth.GetUserIDFromContext(ctx)
+	if userID == "" {
+		return false, errors.New("authentication required")
+	}
+
+	sessionID := auth.GetSessionIDFromContext(ctx)
+	if err := r.AccountService.ChangePassword(ctx, userID, sessionID, currentPassword, newPassword); err != nil {
+		return false, err
+	}
 	return true, nil
 }
 
@@ -191,18 +291,73 @@ func (r *queryResolver) Me(ctx context.Context) (*model.User, error) {
 		return nil, errors.New("user not found")
 	}
 
-	// Преобразуем в GraphQL модель
-	gqlUser := &model.User{
-		ID:            user.ID,
-		Email:         user.Email,
-		FirstName:     user.FirstName,
-		LastName:      user.LastName,
-		IsActive:      user.IsActive,
-		EmailVerified: user.EmailVerified,
-		CreatedAt:     user.CreatedAt,
-		UpdatedAt:     user.UpdatedAt,
-		LastLoginAt:   user.LastLoginAt,
+	return toGraphQLUser(user), nil
+}
+
+// MySessions is the resolver for the mySessions field.
+func (r *queryResolver) MySessions(ctx context.Context) ([]*model.Session, error) {
+	if r.SessionService == nil {
+		return nil, fmt.Errorf("session service not configured")
+	}
+
+	userID := auth.GetUserIDFromContext(ctx)
+	if userID == "" {
+		return nil, errors.New("authentication required")
+	}
+
+	sessions, err := r.SessionService.ListSessions(ctx, userID)
+	if err != nil {
+		r.Logger.Error("failed to list sessions", zap.Error(err), zap.String("user_id", userID))
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+
+	currentID := auth.GetSessionIDFromContext(ctx)
+	result := make([]*model.Session, len(sessions))
+	for i, session := range sessions {
+		result[i] = &model.Session{
+			ID:         session.ID,
+			UserAgent:  optionalString(session.UserAgent),
+			IPAddress:  optionalString(session.IPAddress),
+			CreatedAt:  session.CreatedAt,
+			LastUsedAt: session.LastUsedAt,
+			ExpiresAt:  session.ExpiresAt,
+			Current:    session.ID == currentID,
+		}
+	}
+	return result, nil
+}
+
+// toGraphQLUser преобразует пользователя в GraphQL модель
+func toGraphQLUser(user *postgresql.User) *model.User {
+	return &model.User{
+		ID:                  user.ID,
+		Email:               user.Email,
+		FirstName:           user.FirstName,
+		LastName:            user.LastName,
+		Role:                model.ParseRole(user.Role),
+		IsActive:            user.IsActive,
+		EmailVerified:       user.EmailVerified,
+		CreatedAt:           user.CreatedAt,
+		UpdatedAt:           user.UpdatedAt,
+		LastLoginAt:         user.LastLoginAt,
+		DeletionScheduledAt: user.DeletionScheduledAt,
+	}
+}
+
+// toAuthResponse ответ с пользователем и токенами сессии
+func toAuthResponse(user *postgresql.User, tokens *service.AuthTokens) *model.AuthResponse {
+	return &model.AuthResponse{
+		User:             toGraphQLUser(user),
+		Token:            tokens.AccessToken,
+		ExpiresAt:        tokens.AccessExpiresAt,
+		RefreshToken:     tokens.RefreshToken,
+		RefreshExpiresAt: tokens.RefreshExpiresAt,
 	}
+}
 
-	return gqlUser, nil
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
 }
@@ -27,10 +27,10 @@ func (r *dashboardStatisticsResolver) RegistrationsByMonth(ctx context.Context,
 }
 
 // RegionHeatmap is the resolver for the regionHeatmap field on DashboardStatistics.
-func (r *dashboardStatisticsResolver) RegionHeatmap(ctx context.Context, obj *model.DashboardStatistics) ([]*model.RegionStatistics, error) {
-	r.Logger.Info("getting region heatmap")
+func (r *dashboardStatisticsResolver) RegionHeatmap(ctx context.Context, obj *model.DashboardStatistics, filter *model.StatsFilter) ([]*model.RegionStatistics, error) {
+	r.Logger.Info("getting region heatmap", zap.Any("filter", filter))
 
-	regions, err := r.StatisticsService.GetRegionHeatmap(ctx)
+	regions, err := r.StatisticsService.GetRegionHeatmap(ctx, filter)
 	if err != nil {
 		r.Logger.Error("failed to get region heatmap", zap.Error(err))
 		return nil, err
@@ -39,6 +39,119 @@ func (r *dashboardStatisticsResolver) RegionHeatmap(ctx context.Context, obj *mo
 	return regions, nil
 }
 
+// TopOwners is the resolver for the topOwners field on DashboardStatistics.
+func (r *dashboardStatisticsResolver) TopOwners(ctx context.Context, obj *model.DashboardStatistics, ownerType *model.FounderType, sortBy *model.TopOwnerSortField, order *model.SortOrder, limit *int, filter *model.StatsFilter) ([]*model.TopOwner, error) {
+	r.Logger.Info("getting top owners",
+		zap.Any("ownerType", ownerType),
+		zap.Any("sortBy", sortBy),
+		zap.Any("order", order),
+		zap.Any("limit", limit),
+		zap.Any("filter", filter))
+
+	owners, err := r.StatisticsService.GetTopOwners(ctx, filter, ownerType, sortBy, order, limit)
+	if err != nil {
+		r.Logger.Error("failed to get top owners", zap.Error(err))
+		return nil, err
+	}
+
+	return owners, nil
+}
+
+// OwnershipByType is the resolver for the ownershipByType field on DashboardStatistics.
+func (r *dashboardStatisticsResolver) OwnershipByType(ctx context.Context, obj *model.DashboardStatistics, filter *model.StatsFilter) ([]*model.OwnershipTypeStatistics, error) {
+	r.Logger.Info("getting ownership by type", zap.Any("filter", filter))
+
+	ownership, err := r.StatisticsService.GetOwnershipByType(ctx, filter)
+	if err != nil {
+		r.Logger.Error("failed to get ownership by type", zap.Error(err))
+		return nil, err
+	}
+
+	return ownership, nil
+}
+
+// LegalForms is the resolver for the legalForms field on DashboardStatistics.
+func (r *dashboardStatisticsResolver) LegalForms(ctx context.Context, obj *model.DashboardStatistics, limit *int, filter *model.StatsFilter) ([]*model.LegalFormStatistics, error) {
+	r.Logger.Info("getting legal forms",
+		zap.Any("limit", limit),
+		zap.Any("filter", filter))
+
+	forms, err := r.StatisticsService.GetLegalForms(ctx, filter, limit)
+	if err != nil {
+		r.Logger.Error("failed to get legal forms", zap.Error(err))
+		return nil, err
+	}
+
+	return forms, nil
+}
+
+// ChangesByMonth is the resolver for the changesByMonth field on DashboardStatistics.
+func (r *dashboardStatisticsResolver) ChangesByMonth(ctx context.Context, obj *model.DashboardStatistics, entityType *model.EntityType, reasonCode *string, filter *model.StatsFilter) ([]*model.ChangesByMonthPoint, error) {
+	r.Logger.Info("getting changes by month",
+		zap.Any("entityType", entityType),
+		zap.Any("reasonCode", reasonCode),
+		zap.Any("filter", filter))
+
+	changes, err := r.StatisticsService.GetChangesByMonth(ctx, filter, entityType, reasonCode)
+	if err != nil {
+		r.Logger.Error("failed to get changes by month", zap.Error(err))
+		return nil, err
+	}
+
+	return changes, nil
+}
+
+// ChangesByDay is the resolver for the changesByDay field on DashboardStatistics.
+func (r *dashboardStatisticsResolver) ChangesByDay(ctx context.Context, obj *model.DashboardStatistics, types []string, significantOnly *bool, dateFrom, dateTo *string, filter *model.StatsFilter) ([]*model.ChangesByDayPoint, error) {
+	r.Logger.Info("getting changes by day",
+		zap.Strings("types", types),
+		zap.Any("significantOnly", significantOnly),
+		zap.Any("dateFrom", dateFrom),
+		zap.Any("dateTo", dateTo),
+		zap.Any("filter", filter))
+
+	changes, err := r.StatisticsService.GetChangesByDay(ctx, filter, types, significantOnly, dateFrom, dateTo)
+	if err != nil {
+		r.Logger.Error("failed to get changes by day", zap.Error(err))
+		return nil, err
+	}
+
+	return changes, nil
+}
+
+// MostChangedCompanies is the resolver for the mostChangedCompanies field on DashboardStatistics.
+func (r *dashboardStatisticsResolver) MostChangedCompanies(ctx context.Context, obj *model.DashboardStatistics, month *string, limit *int, filter *model.StatsFilter) ([]*model.MostChangedCompany, error) {
+	r.Logger.Info("getting most changed companies",
+		zap.Any("month", month),
+		zap.Any("limit", limit),
+		zap.Any("filter", filter))
+
+	companies, err := r.StatisticsService.GetMostChangedCompanies(ctx, filter, month, limit)
+	if err != nil {
+		r.Logger.Error("failed to get most changed companies", zap.Error(err))
+		return nil, err
+	}
+
+	return companies, nil
+}
+
+// SignificantChangesByRegion is the resolver for the significantChangesByRegion field on DashboardStatistics.
+func (r *dashboardStatisticsResolver) SignificantChangesByRegion(ctx context.Context, obj *model.DashboardStatistics, types []string, dateFrom, dateTo *string, filter *model.StatsFilter) ([]*model.RegionChangesStatistics, error) {
+	r.Logger.Info("getting significant changes by region",
+		zap.Strings("types", types),
+		zap.Any("dateFrom", dateFrom),
+		zap.Any("dateTo", dateTo),
+		zap.Any("filter", filter))
+
+	changes, err := r.StatisticsService.GetSignificantChangesByRegion(ctx, types, dateFrom, dateTo, filter)
+	if err != nil {
+		r.Logger.Error("failed to get significant changes by region", zap.Error(err))
+		return nil, err
+	}
+
+	return changes, nil
+}
+
 // DashboardStatistics returns DashboardStatisticsResolver implementation.
 func (r *Resolver) DashboardStatistics() DashboardStatisticsResolver {
 	return &dashboardStatisticsResolver{r}
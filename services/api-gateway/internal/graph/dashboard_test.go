@@ -0,0 +1,99 @@
+package graph
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/egrul-system/services/api-gateway/internal/graph/model"
+	"github.com/egrul-system/services/api-gateway/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// changeStatsStore запоминает период и лимит запроса к витринам изменений;
+// остальные методы хранилища тестам не нужны
+type changeStatsStore struct {
+	service.StatisticsStore
+	from, to time.Time
+	limit    int
+	calls    int
+}
+
+func (s *changeStatsStore) GetChangesByDay(_ context.Context, _ *model.StatsFilter, _ []string, _ bool, from, to time.Time) ([]*model.ChangesByDayPoint, error) {
+	s.calls++
+	s.from, s.to = from, to
+	return nil, nil
+}
+
+func (s *changeStatsStore) GetMostChangedCompanies(_ context.Context, _ *model.StatsFilter, _ time.Time, limit int) ([]*model.MostChangedCompany, error) {
+	s.calls++
+	s.limit = limit
+	return nil, nil
+}
+
+func (s *changeStatsStore) GetSignificantChangesByRegion(_ context.Context, _ *model.StatsFilter, _ []string, from, to time.Time) ([]*model.RegionChangesStatistics, error) {
+	s.calls++
+	s.from, s.to = from, to
+	return nil, nil
+}
+
+func newDashboardResolver(store service.StatisticsStore) DashboardStatisticsResolver {
+	r := &Resolver{
+		StatisticsService: service.NewStatisticsService(store, zap.NewNop()),
+		Logger:            zap.NewNop(),
+	}
+	return r.DashboardStatistics()
+}
+
+func TestDashboardStatisticsResolver_ChangeActivityPeriod(t *testing.T) {
+	dateFrom, dateTo := "2024-03-01", "2024-03-31"
+
+	tests := []struct {
+		name string
+		call func(r DashboardStatisticsResolver, from, to *string) error
+	}{
+		{"changes by day", func(r DashboardStatisticsResolver, from, to *string) error {
+			_, err := r.ChangesByDay(context.Background(), &model.DashboardStatistics{}, nil, nil, from, to, nil)
+			return err
+		}},
+		{"significant changes by region", func(r DashboardStatisticsResolver, from, to *string) error {
+			_, err := r.SignificantChangesByRegion(context.Background(), &model.DashboardStatistics{}, nil, from, to, nil)
+			return err
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			store := &changeStatsStore{}
+			r := newDashboardResolver(store)
+
+			// Act
+			err := tt.call(r, &dateFrom, &dateTo)
+			invertedErr := tt.call(r, &dateTo, &dateFrom)
+
+			// Assert
+			require.NoError(t, err)
+			assert.Equal(t, "2024-03-01", store.from.Format("2006-01-02"))
+			assert.Equal(t, "2024-03-31", store.to.Format("2006-01-02"))
+			assert.Error(t, invertedErr)
+			assert.Equal(t, 1, store.calls)
+		})
+	}
+}
+
+func TestDashboardStatisticsResolver_MostChangedCompaniesLimit(t *testing.T) {
+	// Arrange
+	store := &changeStatsStore{}
+	r := newDashboardResolver(store)
+	limit := 500
+
+	// Act
+	_, err := r.MostChangedCompanies(context.Background(), &model.DashboardStatistics{}, nil, &limit, nil)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, 100, store.limit)
+}
@@ -0,0 +1,35 @@
+package graph
+
+import (
+	"context"
+	"errors"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/egrul-system/services/api-gateway/internal/auth"
+	"github.com/egrul-system/services/api-gateway/internal/graph/model"
+)
+
+var (
+	errAuthenticationRequired = errors.New("authentication required")
+	errForbidden              = errors.New("forbidden: insufficient role")
+)
+
+// HasRoleDirective реализация директивы @hasRole (DirectiveRoot.HasRole для gqlgen)
+func HasRoleDirective(ctx context.Context, obj interface{}, next graphql.Resolver, role model.Role) (interface{}, error) {
+	if err := requireRole(ctx, role); err != nil {
+		return nil, err
+	}
+	return next(ctx)
+}
+
+// requireRole проверяет роль текущего пользователя. ManualHandler не исполняет
+// директивы схемы, поэтому обработчики полей с @hasRole вызывают ее сами.
+func requireRole(ctx context.Context, role model.Role) error {
+	if auth.GetUserIDFromContext(ctx) == "" {
+		return errAuthenticationRequired
+	}
+	if !auth.HasRole(auth.GetRoleFromContext(ctx), role.DBValue()) {
+		return errForbidden
+	}
+	return nil
+}
@@ -12,13 +12,16 @@ import (
 	"strings"
 	"time"
 
+	"github.com/egrul-system/services/api-gateway/internal/auth"
 	"github.com/egrul-system/services/api-gateway/internal/graph/model"
+	"github.com/egrul-system/services/api-gateway/internal/middleware"
 	"go.uber.org/zap"
 )
 
 // ManualHandler обрабатывает GraphQL запросы без генерации
 type ManualHandler struct {
 	resolver *Resolver
+	limiter  *middleware.RateLimiter
 }
 
 // NewManualHandler создает новый обработчик
@@ -26,6 +29,11 @@ func NewManualHandler(resolver *Resolver) *ManualHandler {
 	return &ManualHandler{resolver: resolver}
 }
 
+// UseRateLimiter включает ограничение частоты запросов по типам операций
+func (h *ManualHandler) UseRateLimiter(limiter *middleware.RateLimiter) {
+	h.limiter = limiter
+}
+
 // GraphQLRequest структура запроса
 type GraphQLRequest struct {
 	Query         string                 `json:"query"`
@@ -41,8 +49,9 @@ type GraphQLResponse struct {
 
 // GraphQLError ошибка GraphQL
 type GraphQLError struct {
-	Message string   `json:"message"`
-	Path    []string `json:"path,omitempty"`
+	Message    string                 `json:"message"`
+	Path       []string               `json:"path,omitempty"`
+	Extensions map[string]interface{} `json:"extensions,omitempty"`
 }
 
 // ServeHTTP обрабатывает HTTP запросы
@@ -69,7 +78,24 @@ func (h *ManualHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	result, err := h.execute(r.Context(), &req)
+	// Запрос разбирается до ограничения частоты: корзина выбирается по тем же
+	// полям, по которым execute выбирает обработчик
+	fields, err := parseTopLevelFields(req.Query)
+	if err != nil {
+		h.writeError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if h.limiter != nil {
+		for _, op := range rateLimitOperations(fields) {
+			if allowed, retryAfter := h.limiter.Allow(r.Context(), op, auth.ClientIP(r)); !allowed {
+				h.writeRateLimited(w, op, retryAfter)
+				return
+			}
+		}
+	}
+
+	result, err := h.execute(r.Context(), &req, fields)
 	if err != nil {
 		h.writeError(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -85,7 +111,23 @@ func (h *ManualHandler) writeError(w http.ResponseWriter, msg string, status int
 	})
 }
 
-func (h *ManualHandler) execute(ctx context.Context, req *GraphQLRequest) (*GraphQLResponse, error) {
+// writeRateLimited отвечает 429 с ошибкой RATE_LIMITED и заголовком Retry-After
+func (h *ManualHandler) writeRateLimited(w http.ResponseWriter, op middleware.Operation, retryAfter time.Duration) {
+	middleware.SetRetryAfter(w, retryAfter)
+	w.WriteHeader(http.StatusTooManyRequests)
+	json.NewEncoder(w).Encode(GraphQLResponse{
+		Errors: []GraphQLError{{
+			Message: "rate limit exceeded",
+			Extensions: map[string]interface{}{
+				"code":       "RATE_LIMITED",
+				"operation":  string(op),
+				"retryAfter": middleware.RetryAfterSeconds(retryAfter),
+			},
+		}},
+	})
+}
+
+func (h *ManualHandler) execute(ctx context.Context, req *GraphQLRequest, fields []topLevelField) (*GraphQLResponse, error) {
 	query := strings.TrimSpace(req.Query)
 
 	// DEBUG: Simple printf to verify execution
@@ -98,6 +140,13 @@ func (h *ManualHandler) execute(ctx context.Context, req *GraphQLRequest) (*Grap
 		return h.handleIntrospection(ctx, query)
 	}
 
+	// Запросы с API ключом ограничены его scopes
+	if err := checkAPIKeyScopes(ctx, fields); err != nil {
+		return &GraphQLResponse{
+			Errors: []GraphQLError{{Message: err.Error()}},
+		}, nil
+	}
+
 	// Определяем операцию
 	queryLower := strings.ToLower(query)
 	opName := strings.ToLower(req.OperationName)
@@ -136,63 +185,248 @@ func (h *ManualHandler) execute(ctx context.Context, req *GraphQLRequest) (*Grap
 	// Auth mutations and queries - проверяем по имени операции или по query
 	if opName == "register" || queryName == "register" || (strings.Contains(queryLower, "mutation") && strings.Contains(queryLower, "register(")) {
 		h.resolver.Logger.Info("→ Routing to handleRegisterMutation")
-		return h.handleRegisterMutation(ctx, req)
+		return h.dispatch(ctx, req, fields, "register", h.handleRegisterMutation)
+	}
+	// SSO проверяется до login: "completeOidcLogin(" тоже содержит "login("
+	if opName == "beginoidclogin" || queryName == "beginoidclogin" || strings.Contains(query, "beginOidcLogin") {
+		h.resolver.Logger.Info("→ Routing to handleBeginOidcLoginMutation")
+		return h.dispatch(ctx, req, fields, "beginOidcLogin", h.handleBeginOidcLoginMutation)
+	}
+	if opName == "completeoidclogin" || queryName == "completeoidclogin" || strings.Contains(query, "completeOidcLogin(") {
+		h.resolver.Logger.Info("→ Routing to handleCompleteOidcLoginMutation")
+		return h.dispatch(ctx, req, fields, "completeOidcLogin", h.handleCompleteOidcLoginMutation)
 	}
 	if opName == "login" || queryName == "login" || (strings.Contains(queryLower, "mutation") && strings.Contains(queryLower, "login(")) {
 		h.resolver.Logger.Info("→ Routing to handleLoginMutation")
-		return h.handleLoginMutation(ctx, req)
+		return h.dispatch(ctx, req, fields, "login", h.handleLoginMutation)
 	}
 	if opName == "me" || queryName == "me" {
 		h.resolver.Logger.Info("→ Routing to handleMeQuery")
-		return h.handleMeQuery(ctx, req)
+		return h.dispatch(ctx, req, fields, "me", h.handleMeQuery)
+	}
+	if opName == "refreshtoken" || queryName == "refreshtoken" || strings.Contains(query, "refreshToken(") {
+		h.resolver.Logger.Info("→ Routing to handleRefreshTokenMutation")
+		return h.dispatch(ctx, req, fields, "refreshToken", h.handleRefreshTokenMutation)
+	}
+	if opName == "logout" || queryName == "logout" {
+		h.resolver.Logger.Info("→ Routing to handleLogoutMutation")
+		return h.dispatch(ctx, req, fields, "logout", h.handleLogoutMutation)
+	}
+	if opName == "mysessions" || queryName == "mysessions" {
+		h.resolver.Logger.Info("→ Routing to handleMySessionsQuery")
+		return h.dispatch(ctx, req, fields, "mySessions", h.handleMySessionsQuery)
+	}
+	if opName == "revokesession" || queryName == "revokesession" || strings.Contains(query, "revokeSession(") {
+		h.resolver.Logger.Info("→ Routing to handleRevokeSessionMutation")
+		return h.dispatch(ctx, req, fields, "revokeSession", h.handleRevokeSessionMutation)
+	}
+	if opName == "requestemailverification" || queryName == "requestemailverification" || strings.Contains(query, "requestEmailVerification") {
+		h.resolver.Logger.Info("→ Routing to handleRequestEmailVerificationMutation")
+		return h.dispatch(ctx, req, fields, "requestEmailVerification", h.handleRequestEmailVerificationMutation)
+	}
+	if opName == "verifyemail" || queryName == "verifyemail" || strings.Contains(query, "verifyEmail(") {
+		h.resolver.Logger.Info("→ Routing to handleVerifyEmailMutation")
+		return h.dispatch(ctx, req, fields, "verifyEmail", h.handleVerifyEmailMutation)
+	}
+	if opName == "requestpasswordreset" || queryName == "requestpasswordreset" || strings.Contains(query, "requestPasswordReset(") {
+		h.resolver.Logger.Info("→ Routing to handleRequestPasswordResetMutation")
+		return h.dispatch(ctx, req, fields, "requestPasswordReset", h.handleRequestPasswordResetMutation)
+	}
+	if opName == "resetpassword" || queryName == "resetpassword" || strings.Contains(query, "resetPassword(") {
+		h.resolver.Logger.Info("→ Routing to handleResetPasswordMutation")
+		return h.dispatch(ctx, req, fields, "resetPassword", h.handleResetPasswordMutation)
+	}
+	if opName == "changepassword" || queryName == "changepassword" || strings.Contains(query, "changePassword(") {
+		h.resolver.Logger.Info("→ Routing to handleChangePasswordMutation")
+		return h.dispatch(ctx, req, fields, "changePassword", h.handleChangePasswordMutation)
+	}
+
+	// Personal data
+	if opName == "exportmydata" || queryName == "exportmydata" || strings.Contains(query, "exportMyData") {
+		h.resolver.Logger.Info("→ Routing to handleExportMyDataQuery")
+		return h.dispatch(ctx, req, fields, "exportMyData", h.handleExportMyDataQuery)
+	}
+	if opName == "deletemyaccount" || queryName == "deletemyaccount" || strings.Contains(query, "deleteMyAccount(") {
+		h.resolver.Logger.Info("→ Routing to handleDeleteMyAccountMutation")
+		return h.dispatch(ctx, req, fields, "deleteMyAccount", h.handleDeleteMyAccountMutation)
+	}
+	if opName == "cancelaccountdeletion" || queryName == "cancelaccountdeletion" || strings.Contains(query, "cancelAccountDeletion") {
+		h.resolver.Logger.Info("→ Routing to handleCancelAccountDeletionMutation")
+		return h.dispatch(ctx, req, fields, "cancelAccountDeletion", h.handleCancelAccountDeletionMutation)
+	}
+
+	// API keys
+	if opName == "myapikeys" || queryName == "myapikeys" || strings.Contains(query, "myApiKeys") {
+		h.resolver.Logger.Info("→ Routing to handleMyAPIKeysQuery")
+		return h.dispatch(ctx, req, fields, "myApiKeys", h.handleMyAPIKeysQuery)
+	}
+	if opName == "createapikey" || queryName == "createapikey" || strings.Contains(query, "createApiKey(") {
+		h.resolver.Logger.Info("→ Routing to handleCreateAPIKeyMutation")
+		return h.dispatch(ctx, req, fields, "createApiKey", h.handleCreateAPIKeyMutation)
+	}
+	if opName == "revokeapikey" || queryName == "revokeapikey" || strings.Contains(query, "revokeApiKey(") {
+		h.resolver.Logger.Info("→ Routing to handleRevokeAPIKeyMutation")
+		return h.dispatch(ctx, req, fields, "revokeApiKey", h.handleRevokeAPIKeyMutation)
+	}
+
+	// Workspaces
+	if opName == "myworkspaces" || queryName == "myworkspaces" || strings.Contains(query, "myWorkspaces") {
+		h.resolver.Logger.Info("→ Routing to handleMyWorkspacesQuery")
+		return h.dispatch(ctx, req, fields, "myWorkspaces", h.handleMyWorkspacesQuery)
+	}
+	if opName == "workspacemembers" || queryName == "workspacemembers" || strings.Contains(query, "workspaceMembers(") {
+		h.resolver.Logger.Info("→ Routing to handleWorkspaceMembersQuery")
+		return h.dispatch(ctx, req, fields, "workspaceMembers", h.handleWorkspaceMembersQuery)
+	}
+	if opName == "workspacefavorites" || queryName == "workspacefavorites" || strings.Contains(query, "workspaceFavorites(") {
+		h.resolver.Logger.Info("→ Routing to handleWorkspaceFavoritesQuery")
+		return h.dispatch(ctx, req, fields, "workspaceFavorites", h.handleWorkspaceFavoritesQuery)
+	}
+	if opName == "workspacesubscriptions" || queryName == "workspacesubscriptions" || strings.Contains(query, "workspaceSubscriptions(") {
+		h.resolver.Logger.Info("→ Routing to handleWorkspaceSubscriptionsQuery")
+		return h.dispatch(ctx, req, fields, "workspaceSubscriptions", h.handleWorkspaceSubscriptionsQuery)
+	}
+	if opName == "entitynotes" || queryName == "entitynotes" || strings.Contains(query, "entityNotes(") {
+		h.resolver.Logger.Info("→ Routing to handleEntityNotesQuery")
+		return h.dispatch(ctx, req, fields, "entityNotes", h.handleEntityNotesQuery)
+	}
+	if opName == "createworkspace" || queryName == "createworkspace" || strings.Contains(query, "createWorkspace(") {
+		h.resolver.Logger.Info("→ Routing to handleCreateWorkspaceMutation")
+		return h.dispatch(ctx, req, fields, "createWorkspace", h.handleCreateWorkspaceMutation)
+	}
+	if opName == "renameworkspace" || queryName == "renameworkspace" || strings.Contains(query, "renameWorkspace(") {
+		h.resolver.Logger.Info("→ Routing to handleRenameWorkspaceMutation")
+		return h.dispatch(ctx, req, fields, "renameWorkspace", h.handleRenameWorkspaceMutation)
+	}
+	if opName == "deleteworkspace" || queryName == "deleteworkspace" || strings.Contains(query, "deleteWorkspace(") {
+		h.resolver.Logger.Info("→ Routing to handleDeleteWorkspaceMutation")
+		return h.dispatch(ctx, req, fields, "deleteWorkspace", h.handleDeleteWorkspaceMutation)
+	}
+	if opName == "addworkspacemember" || queryName == "addworkspacemember" || strings.Contains(query, "addWorkspaceMember(") {
+		h.resolver.Logger.Info("→ Routing to handleAddWorkspaceMemberMutation")
+		return h.dispatch(ctx, req, fields, "addWorkspaceMember", h.handleAddWorkspaceMemberMutation)
+	}
+	if opName == "setworkspacememberrole" || queryName == "setworkspacememberrole" || strings.Contains(query, "setWorkspaceMemberRole(") {
+		h.resolver.Logger.Info("→ Routing to handleSetWorkspaceMemberRoleMutation")
+		return h.dispatch(ctx, req, fields, "setWorkspaceMemberRole", h.handleSetWorkspaceMemberRoleMutation)
+	}
+	if opName == "removeworkspacemember" || queryName == "removeworkspacemember" || strings.Contains(query, "removeWorkspaceMember(") {
+		h.resolver.Logger.Info("→ Routing to handleRemoveWorkspaceMemberMutation")
+		return h.dispatch(ctx, req, fields, "removeWorkspaceMember", h.handleRemoveWorkspaceMemberMutation)
+	}
+	if opName == "updateworkspacenotificationchannels" || queryName == "updateworkspacenotificationchannels" || strings.Contains(query, "updateWorkspaceNotificationChannels(") {
+		h.resolver.Logger.Info("→ Routing to handleUpdateWorkspaceNotificationChannelsMutation")
+		return h.dispatch(ctx, req, fields, "updateWorkspaceNotificationChannels", h.handleUpdateWorkspaceNotificationChannelsMutation)
+	}
+	if opName == "createentitynote" || queryName == "createentitynote" || strings.Contains(query, "createEntityNote(") {
+		h.resolver.Logger.Info("→ Routing to handleCreateEntityNoteMutation")
+		return h.dispatch(ctx, req, fields, "createEntityNote", h.handleCreateEntityNoteMutation)
+	}
+	if opName == "updateentitynote" || queryName == "updateentitynote" || strings.Contains(query, "updateEntityNote(") {
+		h.resolver.Logger.Info("→ Routing to handleUpdateEntityNoteMutation")
+		return h.dispatch(ctx, req, fields, "updateEntityNote", h.handleUpdateEntityNoteMutation)
+	}
+	if opName == "deleteentitynote" || queryName == "deleteentitynote" || strings.Contains(query, "deleteEntityNote(") {
+		h.resolver.Logger.Info("→ Routing to handleDeleteEntityNoteMutation")
+		return h.dispatch(ctx, req, fields, "deleteEntityNote", h.handleDeleteEntityNoteMutation)
+	}
+	if opName == "workspace" || queryName == "workspace" || strings.Contains(query, "workspace(") || strings.Contains(query, "workspace (") {
+		h.resolver.Logger.Info("→ Routing to handleWorkspaceQuery")
+		return h.dispatch(ctx, req, fields, "workspace", h.handleWorkspaceQuery)
+	}
+
+	// Admin operations (@hasRole(role: ADMIN))
+	if opName == "auditlog" || queryName == "auditlog" || strings.Contains(query, "auditLog") {
+		h.resolver.Logger.Info("→ Routing to handleAuditLogQuery")
+		return h.dispatch(ctx, req, fields, "auditLog", h.handleAuditLogQuery)
+	}
+	if opName == "setuserrole" || queryName == "setuserrole" || strings.Contains(query, "setUserRole(") {
+		h.resolver.Logger.Info("→ Routing to handleSetUserRoleMutation")
+		return h.dispatch(ctx, req, fields, "setUserRole", h.handleSetUserRoleMutation)
+	}
+	if opName == "deactivateuser" || queryName == "deactivateuser" || strings.Contains(query, "deactivateUser(") {
+		h.resolver.Logger.Info("→ Routing to handleDeactivateUserMutation")
+		return h.dispatch(ctx, req, fields, "deactivateUser", h.handleDeactivateUserMutation)
+	}
+	if opName == "systemstats" || queryName == "systemstats" || strings.Contains(query, "systemStats") {
+		h.resolver.Logger.Info("→ Routing to handleSystemStatsQuery")
+		return h.dispatch(ctx, req, fields, "systemStats", h.handleSystemStatsQuery)
+	}
+	if opName == "users" || queryName == "users" || strings.Contains(query, "users(") || strings.Contains(query, "users {") {
+		h.resolver.Logger.Info("→ Routing to handleUsersQuery")
+		return h.dispatch(ctx, req, fields, "users", h.handleUsersQuery)
 	}
 
 	// Subscription operations - проверяем ПЕРЕД companies/entrepreneurs
 	if opName == "mysubscriptions" || queryName == "mysubscriptions" {
 		h.resolver.Logger.Info("→ Routing to handleMySubscriptionsQuery")
-		return h.handleMySubscriptionsQuery(ctx, req)
+		return h.dispatch(ctx, req, fields, "mySubscriptions", h.handleMySubscriptionsQuery)
 	}
 	if opName == "hassubscription" || queryName == "hassubscription" {
 		h.resolver.Logger.Info("→ Routing to handleHasSubscriptionQuery")
-		return h.handleHasSubscriptionQuery(ctx, req)
+		return h.dispatch(ctx, req, fields, "hasSubscription", h.handleHasSubscriptionQuery)
 	}
 	if opName == "createsubscription" || queryName == "createsubscription" || strings.Contains(query, "createSubscription(") {
 		h.resolver.Logger.Info("→ Routing to handleCreateSubscriptionMutation")
-		return h.handleCreateSubscriptionMutation(ctx, req)
+		return h.dispatch(ctx, req, fields, "createSubscription", h.handleCreateSubscriptionMutation)
 	}
 	if opName == "updatesubscriptionfilters" || queryName == "updatesubscriptionfilters" || strings.Contains(query, "updateSubscriptionFilters(") {
 		h.resolver.Logger.Info("→ Routing to handleUpdateSubscriptionFiltersMutation")
-		return h.handleUpdateSubscriptionFiltersMutation(ctx, req)
+		return h.dispatch(ctx, req, fields, "updateSubscriptionFilters", h.handleUpdateSubscriptionFiltersMutation)
 	}
 
 	// Favorites operations
-	if opName == "myfavorites" || queryName == "myfavorites" {
+	if opName == "myfavoritefolders" || queryName == "myfavoritefolders" || strings.Contains(query, "myFavoriteFolders") {
+		h.resolver.Logger.Info("→ Routing to handleMyFavoriteFoldersQuery")
+		return h.dispatch(ctx, req, fields, "myFavoriteFolders", h.handleMyFavoriteFoldersQuery)
+	}
+	if opName == "myfavoritetags" || queryName == "myfavoritetags" || strings.Contains(query, "myFavoriteTags") {
+		h.resolver.Logger.Info("→ Routing to handleMyFavoriteTagsQuery")
+		return h.dispatch(ctx, req, fields, "myFavoriteTags", h.handleMyFavoriteTagsQuery)
+	}
+	if opName == "setfavoritetags" || queryName == "setfavoritetags" || strings.Contains(query, "setFavoriteTags(") {
+		h.resolver.Logger.Info("→ Routing to handleSetFavoriteTagsMutation")
+		return h.dispatch(ctx, req, fields, "setFavoriteTags", h.handleSetFavoriteTagsMutation)
+	}
+	if opName == "addfavorites" || queryName == "addfavorites" || strings.Contains(query, "addFavorites(") {
+		h.resolver.Logger.Info("→ Routing to handleAddFavoritesMutation")
+		return h.dispatch(ctx, req, fields, "addFavorites", h.handleAddFavoritesMutation)
+	}
+	if opName == "movefavorites" || queryName == "movefavorites" || strings.Contains(query, "moveFavorites(") {
+		h.resolver.Logger.Info("→ Routing to handleMoveFavoritesMutation")
+		return h.dispatch(ctx, req, fields, "moveFavorites", h.handleMoveFavoritesMutation)
+	}
+	if opName == "deletefavorites" || queryName == "deletefavorites" || strings.Contains(query, "deleteFavorites(") {
+		h.resolver.Logger.Info("→ Routing to handleDeleteFavoritesMutation")
+		return h.dispatch(ctx, req, fields, "deleteFavorites", h.handleDeleteFavoritesMutation)
+	}
+	if opName == "myfavorites" || queryName == "myfavorites" || strings.Contains(query, "myFavorites(") {
 		h.resolver.Logger.Info("→ Routing to handleMyFavoritesQuery")
-		return h.handleMyFavoritesQuery(ctx, req)
+		return h.dispatch(ctx, req, fields, "myFavorites", h.handleMyFavoritesQuery)
 	}
 	if opName == "hasfavorite" || queryName == "hasfavorite" {
 		h.resolver.Logger.Info("→ Routing to handleHasFavoriteQuery")
-		return h.handleHasFavoriteQuery(ctx, req)
+		return h.dispatch(ctx, req, fields, "hasFavorite", h.handleHasFavoriteQuery)
 	}
 	if opName == "createfavorite" || queryName == "createfavorite" || strings.Contains(query, "createFavorite(") {
 		h.resolver.Logger.Info("→ Routing to handleCreateFavoriteMutation")
-		return h.handleCreateFavoriteMutation(ctx, req)
+		return h.dispatch(ctx, req, fields, "createFavorite", h.handleCreateFavoriteMutation)
 	}
 	if opName == "updatefavoritenotes" || queryName == "updatefavoritenotes" || strings.Contains(query, "updateFavoriteNotes(") {
 		h.resolver.Logger.Info("→ Routing to handleUpdateFavoriteNotesMutation")
-		return h.handleUpdateFavoriteNotesMutation(ctx, req)
+		return h.dispatch(ctx, req, fields, "updateFavoriteNotes", h.handleUpdateFavoriteNotesMutation)
 	}
 	if opName == "deletefavorite" || queryName == "deletefavorite" || strings.Contains(query, "deleteFavorite(") {
 		h.resolver.Logger.Info("→ Routing to handleDeleteFavoriteMutation")
-		return h.handleDeleteFavoriteMutation(ctx, req)
+		return h.dispatch(ctx, req, fields, "deleteFavorite", h.handleDeleteFavoriteMutation)
 	}
 	if opName == "togglesubscription" || queryName == "togglesubscription" || strings.Contains(query, "toggleSubscription(") {
 		h.resolver.Logger.Info("→ Routing to handleToggleSubscriptionMutation")
-		return h.handleToggleSubscriptionMutation(ctx, req)
+		return h.dispatch(ctx, req, fields, "toggleSubscription", h.handleToggleSubscriptionMutation)
 	}
 	if opName == "deletesubscription" || queryName == "deletesubscription" || strings.Contains(query, "deleteSubscription(") {
 		h.resolver.Logger.Info("→ Routing to handleDeleteSubscriptionMutation")
-		return h.handleDeleteSubscriptionMutation(ctx, req)
+		return h.dispatch(ctx, req, fields, "deleteSubscription", h.handleDeleteSubscriptionMutation)
 	}
 
 	// Company/Entrepreneur queries с поддержкой queryName
@@ -202,58 +436,109 @@ func (h *ManualHandler) execute(ctx context.Context, req *GraphQLRequest) (*Grap
 		// Если в query есть "companies(" (а не "searchCompanies("), это обычный companies query
 		if strings.Contains(query, "companies(") || strings.Contains(query, "companies {") {
 			h.resolver.Logger.Info("→ Routing to handleCompaniesQuery (from searchcompanies opName/queryName)")
-			return h.handleCompaniesQuery(ctx, req)
+			return h.dispatch(ctx, req, fields, "companies", h.handleCompaniesQuery)
 		}
 		h.resolver.Logger.Info("→ Routing to handleSearchCompaniesQuery")
-		return h.handleSearchCompaniesQuery(ctx, req)
+		return h.dispatch(ctx, req, fields, "searchCompanies", h.handleSearchCompaniesQuery)
 	}
 	if opName == "searchentrepreneurs" || queryName == "searchentrepreneurs" {
 		// Аналогично для entrepreneurs
 		if strings.Contains(query, "entrepreneurs(") || strings.Contains(query, "entrepreneurs {") {
 			h.resolver.Logger.Info("→ Routing to handleEntrepreneursQuery (from searchentrepreneurs opName/queryName)")
-			return h.handleEntrepreneursQuery(ctx, req)
+			return h.dispatch(ctx, req, fields, "entrepreneurs", h.handleEntrepreneursQuery)
 		}
 		// Нет отдельного handleSearchEntrepreneursQuery, используем handleEntrepreneursQuery
 		h.resolver.Logger.Info("→ Routing to handleEntrepreneursQuery (fallback)")
-		return h.handleEntrepreneursQuery(ctx, req)
+		return h.dispatch(ctx, req, fields, "searchEntrepreneurs", h.handleEntrepreneursQuery)
+	}
+
+	// Поиск с фасетами: выбор содержит "companies {"/"entrepreneurs {", поэтому
+	// маршрутизируется до запросов списков
+	if strings.Contains(query, "companySearch") {
+		h.resolver.Logger.Info("→ Routing to handleCompanySearchQuery")
+		return h.dispatch(ctx, req, fields, "companySearch", h.handleCompanySearchQuery)
+	}
+	if strings.Contains(query, "entrepreneurSearch") {
+		h.resolver.Logger.Info("→ Routing to handleEntrepreneurSearchQuery")
+		return h.dispatch(ctx, req, fields, "entrepreneurSearch", h.handleEntrepreneurSearchQuery)
 	}
 
 	// Company queries
+	if strings.Contains(query, "riskAssessments(") {
+		h.resolver.Logger.Info("→ Routing to handleRiskAssessmentsQuery")
+		return h.dispatch(ctx, req, fields, "riskAssessments", h.handleRiskAssessmentsQuery)
+	}
+	if strings.Contains(query, "personCandidates(") {
+		h.resolver.Logger.Info("→ Routing to handlePersonCandidatesQuery")
+		return h.dispatch(ctx, req, fields, "personCandidates", h.handlePersonCandidatesQuery)
+	}
+	if strings.Contains(query, "person(") || strings.Contains(query, "person (") {
+		h.resolver.Logger.Info("→ Routing to handlePersonQuery")
+		return h.dispatch(ctx, req, fields, "person", h.handlePersonQuery)
+	}
+	if strings.Contains(query, "massAddresses") {
+		h.resolver.Logger.Info("→ Routing to handleMassAddressesQuery")
+		return h.dispatch(ctx, req, fields, "massAddresses", h.handleMassAddressesQuery)
+	}
+	if strings.Contains(query, "massDirectors") {
+		h.resolver.Logger.Info("→ Routing to handleMassDirectorsQuery")
+		return h.dispatch(ctx, req, fields, "massDirectors", h.handleMassDirectorsQuery)
+	}
+	if strings.Contains(query, "companyDiff(") {
+		h.resolver.Logger.Info("→ Routing to handleCompanyDiffQuery")
+		return h.dispatch(ctx, req, fields, "companyDiff", h.handleCompanyDiffQuery)
+	}
 	if strings.Contains(query, "company(") || strings.Contains(query, "company (") {
 		h.resolver.Logger.Info("→ Routing to handleCompanyQuery")
-		return h.handleCompanyQuery(ctx, req)
+		return h.dispatch(ctx, req, fields, "company", h.handleCompanyQuery)
 	}
 	if strings.Contains(query, "companyByInn") {
 		h.resolver.Logger.Info("→ Routing to handleCompanyByInnQuery")
-		return h.handleCompanyByInnQuery(ctx, req)
+		return h.dispatch(ctx, req, fields, "companyByInn", h.handleCompanyByInnQuery)
 	}
 	if strings.Contains(query, "companies(") || strings.Contains(query, "companies {") {
 		h.resolver.Logger.Info("→ Routing to handleCompaniesQuery")
-		return h.handleCompaniesQuery(ctx, req)
+		return h.dispatch(ctx, req, fields, "companies", h.handleCompaniesQuery)
 	}
 	if strings.Contains(query, "searchCompanies(") {
 		h.resolver.Logger.Info("→ Routing to handleSearchCompaniesQuery")
-		return h.handleSearchCompaniesQuery(ctx, req)
+		return h.dispatch(ctx, req, fields, "searchCompanies", h.handleSearchCompaniesQuery)
 	}
 	if strings.Contains(query, "entrepreneur(") || strings.Contains(query, "entrepreneur (") {
 		h.resolver.Logger.Info("→ Routing to handleEntrepreneurQuery")
-		return h.handleEntrepreneurQuery(ctx, req)
+		return h.dispatch(ctx, req, fields, "entrepreneur", h.handleEntrepreneurQuery)
 	}
 	if strings.Contains(query, "entrepreneurs(") || strings.Contains(query, "entrepreneurs {") {
 		h.resolver.Logger.Info("→ Routing to handleEntrepreneursQuery")
-		return h.handleEntrepreneursQuery(ctx, req)
+		return h.dispatch(ctx, req, fields, "entrepreneurs", h.handleEntrepreneursQuery)
+	}
+	if strings.Contains(query, "suggest(") {
+		h.resolver.Logger.Info("→ Routing to handleSuggestQuery")
+		return h.dispatch(ctx, req, fields, "suggest", h.handleSuggestQuery)
 	}
 	if strings.Contains(query, "search(") || strings.Contains(query, "search {") {
 		h.resolver.Logger.Info("→ Routing to handleSearchQuery")
-		return h.handleSearchQuery(ctx, req)
+		return h.dispatch(ctx, req, fields, "search", h.handleSearchQuery)
+	}
+	if strings.Contains(query, "okvedTree") {
+		h.resolver.Logger.Info("→ Routing to handleOkvedTreeQuery")
+		return h.dispatch(ctx, req, fields, "okvedTree", h.handleOkvedTreeQuery)
+	}
+	if strings.Contains(query, "survivalCohorts") {
+		h.resolver.Logger.Info("→ Routing to handleSurvivalCohortsQuery")
+		return h.dispatch(ctx, req, fields, "survivalCohorts", h.handleSurvivalCohortsQuery)
+	}
+	if strings.Contains(query, "timeSeries(") {
+		h.resolver.Logger.Info("→ Routing to handleTimeSeriesQuery")
+		return h.dispatch(ctx, req, fields, "timeSeries", h.handleTimeSeriesQuery)
 	}
 	if strings.Contains(query, "dashboardStatistics") {
 		h.resolver.Logger.Info("→ Routing to handleDashboardStatisticsQuery")
-		return h.handleDashboardStatisticsQuery(ctx, req)
+		return h.dispatch(ctx, req, fields, "dashboardStatistics", h.handleDashboardStatisticsQuery)
 	}
 	if strings.Contains(query, "statistics") {
 		h.resolver.Logger.Info("→ Routing to handleStatisticsQuery")
-		return h.handleStatisticsQuery(ctx, req)
+		return h.dispatch(ctx, req, fields, "statistics", h.handleStatisticsQuery)
 	}
 
 	h.resolver.Logger.Warn("❌ Unsupported query - no routing match found",
@@ -272,6 +557,117 @@ func (h *ManualHandler) execute(ctx context.Context, req *GraphQLRequest) (*Grap
 	}, nil
 }
 
+func (h *ManualHandler) handleCompanyDiffQuery(ctx context.Context, req *GraphQLRequest) (*GraphQLResponse, error) {
+	ogrn, ok := req.Variables["ogrn"].(string)
+	if !ok {
+		ogrn = extractArgFromQuery(req.Query, "ogrn")
+	}
+	if ogrn == "" {
+		return &GraphQLResponse{Errors: []GraphQLError{{Message: "ogrn is required"}}}, nil
+	}
+
+	from, err := parseDateArg(req, "from")
+	if err != nil {
+		return &GraphQLResponse{Errors: []GraphQLError{{Message: err.Error()}}}, nil
+	}
+	to, err := parseDateArg(req, "to")
+	if err != nil {
+		return &GraphQLResponse{Errors: []GraphQLError{{Message: err.Error()}}}, nil
+	}
+	if from == nil || to == nil {
+		return &GraphQLResponse{Errors: []GraphQLError{{Message: "from and to are required"}}}, nil
+	}
+
+	diff, err := h.resolver.Query().CompanyDiff(ctx, ogrn, *from, *to)
+	if err != nil {
+		return &GraphQLResponse{Errors: []GraphQLError{{Message: err.Error()}}}, nil
+	}
+
+	return &GraphQLResponse{Data: map[string]interface{}{"companyDiff": diff}}, nil
+}
+
+func (h *ManualHandler) handleRiskAssessmentsQuery(ctx context.Context, req *GraphQLRequest) (*GraphQLResponse, error) {
+	var ogrns []string
+	if list, ok := req.Variables["ogrns"].([]interface{}); ok {
+		for _, v := range list {
+			if ogrn, ok := v.(string); ok && ogrn != "" {
+				ogrns = append(ogrns, ogrn)
+			}
+		}
+	}
+	if len(ogrns) == 0 {
+		return &GraphQLResponse{Errors: []GraphQLError{{Message: "ogrns variable is required"}}}, nil
+	}
+
+	assessments, err := h.resolver.Query().RiskAssessments(ctx, ogrns)
+	if err != nil {
+		return &GraphQLResponse{Errors: []GraphQLError{{Message: err.Error()}}}, nil
+	}
+
+	return &GraphQLResponse{Data: map[string]interface{}{"riskAssessments": assessments}}, nil
+}
+
+func (h *ManualHandler) handlePersonQuery(ctx context.Context, req *GraphQLRequest) (*GraphQLResponse, error) {
+	inn, ok := req.Variables["inn"].(string)
+	if !ok {
+		inn = extractArgFromQuery(req.Query, "inn")
+	}
+	if inn == "" {
+		return &GraphQLResponse{Errors: []GraphQLError{{Message: "inn is required"}}}, nil
+	}
+
+	profile, err := h.resolver.Query().Person(ctx, inn)
+	if err != nil {
+		return &GraphQLResponse{Errors: []GraphQLError{{Message: err.Error()}}}, nil
+	}
+
+	return &GraphQLResponse{Data: map[string]interface{}{"person": profile}}, nil
+}
+
+func (h *ManualHandler) handlePersonCandidatesQuery(ctx context.Context, req *GraphQLRequest) (*GraphQLResponse, error) {
+	name, ok := req.Variables["name"].(string)
+	if !ok {
+		name = extractArgFromQuery(req.Query, "name")
+	}
+	if name == "" {
+		return &GraphQLResponse{Errors: []GraphQLError{{Message: "name is required"}}}, nil
+	}
+
+	candidates, err := h.resolver.Query().PersonCandidates(ctx, name, intVariable(req, "limit"))
+	if err != nil {
+		return &GraphQLResponse{Errors: []GraphQLError{{Message: err.Error()}}}, nil
+	}
+
+	return &GraphQLResponse{Data: map[string]interface{}{"personCandidates": candidates}}, nil
+}
+
+func (h *ManualHandler) handleMassAddressesQuery(ctx context.Context, req *GraphQLRequest) (*GraphQLResponse, error) {
+	var regionCode *string
+	if v, ok := req.Variables["regionCode"].(string); ok && v != "" {
+		regionCode = &v
+	} else if v := extractArgFromQuery(req.Query, "regionCode"); v != "" {
+		regionCode = &v
+	}
+
+	addresses, err := h.resolver.Query().MassAddresses(ctx, regionCode,
+		intVariable(req, "minCount"), intVariable(req, "limit"), intVariable(req, "offset"))
+	if err != nil {
+		return &GraphQLResponse{Errors: []GraphQLError{{Message: err.Error()}}}, nil
+	}
+
+	return &GraphQLResponse{Data: map[string]interface{}{"massAddresses": addresses}}, nil
+}
+
+func (h *ManualHandler) handleMassDirectorsQuery(ctx context.Context, req *GraphQLRequest) (*GraphQLResponse, error) {
+	directors, err := h.resolver.Query().MassDirectors(ctx,
+		intVariable(req, "minCount"), intVariable(req, "limit"), intVariable(req, "offset"))
+	if err != nil {
+		return &GraphQLResponse{Errors: []GraphQLError{{Message: err.Error()}}}, nil
+	}
+
+	return &GraphQLResponse{Data: map[string]interface{}{"massDirectors": directors}}, nil
+}
+
 func (h *ManualHandler) handleIntrospection(ctx context.Context, query string) (*GraphQLResponse, error) {
 	// Базовая схема для интроспекции
 	schema := map[string]interface{}{
@@ -307,7 +703,12 @@ func (h *ManualHandler) handleCompanyQuery(ctx context.Context, req *GraphQLRequ
 		return &GraphQLResponse{Errors: []GraphQLError{{Message: "ogrn is required"}}}, nil
 	}
 
-	company, err := h.resolver.Query().Company(ctx, ogrn)
+	asOf, err := parseAsOfArg(req)
+	if err != nil {
+		return &GraphQLResponse{Errors: []GraphQLError{{Message: err.Error()}}}, nil
+	}
+
+	company, err := h.resolver.Query().Company(ctx, ogrn, asOf)
 	if err != nil {
 		return &GraphQLResponse{Errors: []GraphQLError{{Message: err.Error()}}}, nil
 	}
@@ -318,6 +719,9 @@ func (h *ManualHandler) handleCompanyQuery(ctx context.Context, req *GraphQLRequ
 	hasRelatedCompanies := strings.Contains(req.Query, "relatedCompanies")
 	hasLicenses := strings.Contains(req.Query, "licenses")
 	hasBranches := strings.Contains(req.Query, "branches")
+	hasRiskAssessment := strings.Contains(req.Query, "riskAssessment")
+	hasMassAddress := strings.Contains(req.Query, "massAddress")
+	hasMassDirector := strings.Contains(req.Query, "massDirector")
 
 	// TEMPORARY DEBUG LOGGING
 	fmt.Printf("=== COMPANY QUERY DEBUG ===\n")
@@ -342,7 +746,7 @@ func (h *ManualHandler) handleCompanyQuery(ctx context.Context, req *GraphQLRequ
 	})
 	// #endregion
 
-	if (hasFounders || hasHistory || hasRelatedCompanies || hasLicenses || hasBranches) && company != nil {
+	if (hasFounders || hasHistory || hasRelatedCompanies || hasLicenses || hasBranches || hasRiskAssessment || hasMassAddress || hasMassDirector) && company != nil {
 		var founders []*model.Founder
 		var history []*model.HistoryRecord
 		var relatedCompanies []*model.RelatedCompany
@@ -519,6 +923,7 @@ func (h *ManualHandler) handleCompanyQuery(ctx context.Context, req *GraphQLRequ
 			"lastGrnDate":   company.LastGrnDate,
 			"sourceFile":    company.SourceFile,
 			"versionDate":   company.VersionDate,
+			"versionInfo":   company.VersionInfo,
 			"createdAt":     company.CreatedAt,
 			"updatedAt":     company.UpdatedAt,
 		}
@@ -566,6 +971,34 @@ func (h *ManualHandler) handleCompanyQuery(ctx context.Context, req *GraphQLRequ
 			companyData["branches"] = branches
 		}
 
+		// Оценка риска (признаки предрассчитаны в ClickHouse)
+		if hasRiskAssessment {
+			risk, err := h.resolver.Company().RiskAssessment(ctx, company)
+			if err != nil {
+				return &GraphQLResponse{Errors: []GraphQLError{{Message: err.Error()}}}, nil
+			}
+			companyData["riskAssessment"] = risk
+		}
+
+		// Агрегаты массовой регистрации (предрассчитаны в ClickHouse)
+		if hasMassAddress {
+			massAddress, err := h.resolver.Company().MassAddress(ctx, company)
+			if err != nil {
+				return &GraphQLResponse{Errors: []GraphQLError{{Message: err.Error()}}}, nil
+			}
+			companyData["massAddress"] = massAddress
+		}
+		if hasMassDirector && company.Director != nil {
+			massDirector, err := h.resolver.Person().MassDirector(ctx, company.Director)
+			if err != nil {
+				return &GraphQLResponse{Errors: []GraphQLError{{Message: err.Error()}}}, nil
+			}
+			// Копия, чтобы не менять закэшированную карточку
+			director := *company.Director
+			director.MassDirector = massDirector
+			companyData["director"] = &director
+		}
+
 		result := map[string]interface{}{
 			"company": companyData,
 		}
@@ -684,7 +1117,12 @@ func (h *ManualHandler) handleEntrepreneurQuery(ctx context.Context, req *GraphQ
 		return &GraphQLResponse{Errors: []GraphQLError{{Message: "ogrnip is required"}}}, nil
 	}
 
-	entrepreneur, err := h.resolver.Query().Entrepreneur(ctx, ogrnip)
+	asOf, err := parseAsOfArg(req)
+	if err != nil {
+		return &GraphQLResponse{Errors: []GraphQLError{{Message: err.Error()}}}, nil
+	}
+
+	entrepreneur, err := h.resolver.Query().Entrepreneur(ctx, ogrnip, asOf)
 	if err != nil {
 		return &GraphQLResponse{Errors: []GraphQLError{{Message: err.Error()}}}, nil
 	}
@@ -808,6 +1246,7 @@ func (h *ManualHandler) handleEntrepreneurQuery(ctx context.Context, req *GraphQ
 			"lastGrnDate":            entrepreneur.LastGrnDate,
 			"sourceFile":             entrepreneur.SourceFile,
 			"versionDate":            entrepreneur.VersionDate,
+			"versionInfo":            entrepreneur.VersionInfo,
 			"createdAt":              entrepreneur.CreatedAt,
 			"updatedAt":              entrepreneur.UpdatedAt,
 		}
@@ -915,6 +1354,65 @@ func (h *ManualHandler) handleSearchQuery(ctx context.Context, req *GraphQLReque
 	return &GraphQLResponse{Data: map[string]interface{}{"search": result}}, nil
 }
 
+func (h *ManualHandler) handleSuggestQuery(ctx context.Context, req *GraphQLRequest) (*GraphQLResponse, error) {
+	prefix, _ := req.Variables["prefix"].(string)
+	if prefix == "" {
+		prefix = extractArgFromQuery(req.Query, "prefix")
+	}
+
+	var types []model.EntityType
+	if raw, ok := req.Variables["types"].([]interface{}); ok {
+		for _, v := range raw {
+			if t, ok := v.(string); ok {
+				types = append(types, model.EntityType(t))
+			}
+		}
+	}
+
+	suggestions, err := h.resolver.Query().Suggest(ctx, prefix, types, intVariable(req, "limit"))
+	if err != nil {
+		return &GraphQLResponse{Errors: []GraphQLError{{Message: err.Error()}}}, nil
+	}
+
+	return &GraphQLResponse{Data: map[string]interface{}{"suggest": suggestions}}, nil
+}
+
+func (h *ManualHandler) handleCompanySearchQuery(ctx context.Context, req *GraphQLRequest) (*GraphQLResponse, error) {
+	var query *string
+	if q, ok := req.Variables["query"].(string); ok {
+		query = &q
+	}
+	var filter *model.CompanyFilter
+	if filterVar, ok := req.Variables["filter"].(map[string]interface{}); ok {
+		filter = parseCompanyFilter(filterVar)
+	}
+
+	result, err := h.resolver.Query().CompanySearch(ctx, query, filter, intVariable(req, "limit"), intVariable(req, "offset"))
+	if err != nil {
+		return &GraphQLResponse{Errors: []GraphQLError{{Message: err.Error()}}}, nil
+	}
+
+	return &GraphQLResponse{Data: map[string]interface{}{"companySearch": result}}, nil
+}
+
+func (h *ManualHandler) handleEntrepreneurSearchQuery(ctx context.Context, req *GraphQLRequest) (*GraphQLResponse, error) {
+	var query *string
+	if q, ok := req.Variables["query"].(string); ok {
+		query = &q
+	}
+	var filter *model.EntrepreneurFilter
+	if filterVar, ok := req.Variables["filter"].(map[string]interface{}); ok {
+		filter = parseEntrepreneurFilter(filterVar)
+	}
+
+	result, err := h.resolver.Query().EntrepreneurSearch(ctx, query, filter, intVariable(req, "limit"), intVariable(req, "offset"))
+	if err != nil {
+		return &GraphQLResponse{Errors: []GraphQLError{{Message: err.Error()}}}, nil
+	}
+
+	return &GraphQLResponse{Data: map[string]interface{}{"entrepreneurSearch": result}}, nil
+}
+
 func (h *ManualHandler) handleStatisticsQuery(ctx context.Context, req *GraphQLRequest) (*GraphQLResponse, error) {
 	var filter *model.StatsFilter
 
@@ -927,9 +1425,94 @@ func (h *ManualHandler) handleStatisticsQuery(ctx context.Context, req *GraphQLR
 		return &GraphQLResponse{Errors: []GraphQLError{{Message: err.Error()}}}, nil
 	}
 
+	if strings.Contains(req.Query, "byActivity") {
+		var level *model.OkvedLevel
+		if v, ok := req.Variables["activityLevel"].(string); ok && v != "" {
+			l := model.OkvedLevel(v)
+			level = &l
+		}
+		activity, err := h.resolver.Statistics().ByActivity(ctx, stats, intVariable(req, "activityLimit"), level, filter)
+		if err != nil {
+			return &GraphQLResponse{Errors: []GraphQLError{{Message: err.Error()}}}, nil
+		}
+		stats.ByActivity = activity
+	}
+
 	return &GraphQLResponse{Data: map[string]interface{}{"statistics": stats}}, nil
 }
 
+func (h *ManualHandler) handleOkvedTreeQuery(ctx context.Context, req *GraphQLRequest) (*GraphQLResponse, error) {
+	var parent *string
+	if v, ok := req.Variables["parent"].(string); ok && v != "" {
+		parent = &v
+	}
+
+	nodes, err := h.resolver.Query().OkvedTree(ctx, parent)
+	if err != nil {
+		return &GraphQLResponse{Errors: []GraphQLError{{Message: err.Error()}}}, nil
+	}
+
+	return &GraphQLResponse{Data: map[string]interface{}{"okvedTree": nodes}}, nil
+}
+
+func (h *ManualHandler) handleSurvivalCohortsQuery(ctx context.Context, req *GraphQLRequest) (*GraphQLResponse, error) {
+	var filter *model.StatsFilter
+	if filterVar, ok := req.Variables["filter"].(map[string]interface{}); ok {
+		filter = parseStatsFilter(filterVar)
+	}
+	var granularity *model.CohortGranularity
+	if v, ok := req.Variables["cohortGranularity"].(string); ok && v != "" {
+		g := model.CohortGranularity(v)
+		granularity = &g
+	}
+	var entityType *model.EntityType
+	if v, ok := req.Variables["entityType"].(string); ok && v != "" {
+		et := model.EntityType(v)
+		entityType = &et
+	}
+
+	cohorts, err := h.resolver.Query().SurvivalCohorts(ctx, granularity, entityType, filter)
+	if err != nil {
+		return &GraphQLResponse{Errors: []GraphQLError{{Message: err.Error()}}}, nil
+	}
+
+	return &GraphQLResponse{Data: map[string]interface{}{"survivalCohorts": cohorts}}, nil
+}
+
+func (h *ManualHandler) handleTimeSeriesQuery(ctx context.Context, req *GraphQLRequest) (*GraphQLResponse, error) {
+	metric, _ := req.Variables["metric"].(string)
+	if metric == "" {
+		return &GraphQLResponse{Errors: []GraphQLError{{Message: "metric is required"}}}, nil
+	}
+
+	var filter *model.StatsFilter
+	if filterVar, ok := req.Variables["filter"].(map[string]interface{}); ok {
+		filter = parseStatsFilter(filterVar)
+	}
+	var granularity *model.TimeGranularity
+	if v, ok := req.Variables["granularity"].(string); ok && v != "" {
+		g := model.TimeGranularity(v)
+		granularity = &g
+	}
+	var groupBy *model.TimeSeriesGroupBy
+	if v, ok := req.Variables["groupBy"].(string); ok && v != "" {
+		g := model.TimeSeriesGroupBy(v)
+		groupBy = &g
+	}
+	var entityType *model.EntityType
+	if v, ok := req.Variables["entityType"].(string); ok && v != "" {
+		et := model.EntityType(v)
+		entityType = &et
+	}
+
+	points, err := h.resolver.Query().TimeSeries(ctx, model.TimeSeriesMetric(metric), granularity, groupBy, entityType, filter)
+	if err != nil {
+		return &GraphQLResponse{Errors: []GraphQLError{{Message: err.Error()}}}, nil
+	}
+
+	return &GraphQLResponse{Data: map[string]interface{}{"timeSeries": points}}, nil
+}
+
 func (h *ManualHandler) handleDashboardStatisticsQuery(ctx context.Context, req *GraphQLRequest) (*GraphQLResponse, error) {
 	var filter *model.StatsFilter
 	var dateFrom, dateTo *string
@@ -978,7 +1561,7 @@ func (h *ManualHandler) handleDashboardStatisticsQuery(ctx context.Context, req
 		return &GraphQLResponse{Errors: []GraphQLError{{Message: err.Error()}}}, nil
 	}
 
-	heatmap, err := h.resolver.DashboardStatistics().RegionHeatmap(ctx, dashboard)
+	heatmap, err := h.resolver.DashboardStatistics().RegionHeatmap(ctx, dashboard, filter)
 	if err != nil {
 		return &GraphQLResponse{Errors: []GraphQLError{{Message: err.Error()}}}, nil
 	}
@@ -988,9 +1571,107 @@ func (h *ManualHandler) handleDashboardStatisticsQuery(ctx context.Context, req
 		"regionHeatmap":        heatmap,
 	}
 
-	responseData["dashboardStatistics"] = dashboardResult
+	// Дополнительные разрезы считаем только если они запрошены
+	if strings.Contains(query, "topOwners") {
+		var ownerType *model.FounderType
+		if v, ok := req.Variables["ownerType"].(string); ok && v != "" {
+			ft := model.FounderType(v)
+			ownerType = &ft
+		}
+		var sortBy *model.TopOwnerSortField
+		if v, ok := req.Variables["sortBy"].(string); ok && v != "" {
+			sf := model.TopOwnerSortField(v)
+			sortBy = &sf
+		}
+		var order *model.SortOrder
+		if v, ok := req.Variables["order"].(string); ok && v != "" {
+			so := model.SortOrder(v)
+			order = &so
+		}
 
-	return &GraphQLResponse{Data: responseData}, nil
+		owners, err := h.resolver.DashboardStatistics().TopOwners(ctx, dashboard, ownerType, sortBy, order, intVariable(req, "limit"), filter)
+		if err != nil {
+			return &GraphQLResponse{Errors: []GraphQLError{{Message: err.Error()}}}, nil
+		}
+		dashboardResult["topOwners"] = owners
+	}
+
+	if strings.Contains(query, "ownershipByType") {
+		ownership, err := h.resolver.DashboardStatistics().OwnershipByType(ctx, dashboard, filter)
+		if err != nil {
+			return &GraphQLResponse{Errors: []GraphQLError{{Message: err.Error()}}}, nil
+		}
+		dashboardResult["ownershipByType"] = ownership
+	}
+
+	if strings.Contains(query, "legalForms") {
+		forms, err := h.resolver.DashboardStatistics().LegalForms(ctx, dashboard, intVariable(req, "legalFormsLimit"), filter)
+		if err != nil {
+			return &GraphQLResponse{Errors: []GraphQLError{{Message: err.Error()}}}, nil
+		}
+		dashboardResult["legalForms"] = forms
+	}
+
+	if strings.Contains(query, "changesByMonth") {
+		var reasonCode *string
+		if v, ok := req.Variables["reasonCode"].(string); ok && v != "" {
+			reasonCode = &v
+		}
+
+		changes, err := h.resolver.DashboardStatistics().ChangesByMonth(ctx, dashboard, entityType, reasonCode, filter)
+		if err != nil {
+			return &GraphQLResponse{Errors: []GraphQLError{{Message: err.Error()}}}, nil
+		}
+		dashboardResult["changesByMonth"] = changes
+	}
+
+	// Параметры changesByDay и significantChangesByRegion
+	var changeTypes []string
+	if list, ok := req.Variables["changeTypes"].([]interface{}); ok {
+		for _, v := range list {
+			if t, ok := v.(string); ok && t != "" {
+				changeTypes = append(changeTypes, t)
+			}
+		}
+	}
+
+	if strings.Contains(query, "changesByDay") {
+		var significantOnly *bool
+		if v, ok := req.Variables["significantOnly"].(bool); ok {
+			significantOnly = &v
+		}
+
+		changes, err := h.resolver.DashboardStatistics().ChangesByDay(ctx, dashboard, changeTypes, significantOnly, dateFrom, dateTo, filter)
+		if err != nil {
+			return &GraphQLResponse{Errors: []GraphQLError{{Message: err.Error()}}}, nil
+		}
+		dashboardResult["changesByDay"] = changes
+	}
+
+	if strings.Contains(query, "mostChangedCompanies") {
+		var month *string
+		if v, ok := req.Variables["month"].(string); ok && v != "" {
+			month = &v
+		}
+
+		companies, err := h.resolver.DashboardStatistics().MostChangedCompanies(ctx, dashboard, month, intVariable(req, "mostChangedLimit"), filter)
+		if err != nil {
+			return &GraphQLResponse{Errors: []GraphQLError{{Message: err.Error()}}}, nil
+		}
+		dashboardResult["mostChangedCompanies"] = companies
+	}
+
+	if strings.Contains(query, "significantChangesByRegion") {
+		changes, err := h.resolver.DashboardStatistics().SignificantChangesByRegion(ctx, dashboard, changeTypes, dateFrom, dateTo, filter)
+		if err != nil {
+			return &GraphQLResponse{Errors: []GraphQLError{{Message: err.Error()}}}, nil
+		}
+		dashboardResult["significantChangesByRegion"] = changes
+	}
+
+	responseData["dashboardStatistics"] = dashboardResult
+
+	return &GraphQLResponse{Data: responseData}, nil
 }
 
 // Вспомогательные функции парсинга
@@ -1026,6 +1707,37 @@ func extractArgFromQuery(query, argName string) string {
 	return ""
 }
 
+// intVariable извлекает необязательный целочисленный аргумент из variables
+func intVariable(req *GraphQLRequest, name string) *int {
+	if v, ok := req.Variables[name].(float64); ok {
+		i := int(v)
+		return &i
+	}
+	return nil
+}
+
+// parseAsOfArg извлекает необязательный аргумент asOf (YYYY-MM-DD) из variables или строки запроса
+func parseAsOfArg(req *GraphQLRequest) (*model.Date, error) {
+	return parseDateArg(req, "asOf")
+}
+
+// parseDateArg извлекает необязательный аргумент-дату (YYYY-MM-DD) из variables или строки запроса
+func parseDateArg(req *GraphQLRequest, name string) (*model.Date, error) {
+	value, _ := req.Variables[name].(string)
+	if value == "" {
+		value = extractArgFromQuery(req.Query, name)
+	}
+	if value == "" {
+		return nil, nil
+	}
+
+	var date model.Date
+	if err := date.UnmarshalGQL(value); err != nil {
+		return nil, fmt.Errorf("invalid %s date (expected YYYY-MM-DD): %s", name, value)
+	}
+	return &date, nil
+}
+
 // agentLog пишет отладочную информацию в NDJSON-файл для debug-сессии
 func agentLog(runID, location, message string, data map[string]interface{}) {
 	entry := map[string]interface{}{
@@ -1147,6 +1859,22 @@ func parseCompanyFilter(data map[string]interface{}) *model.CompanyFilter {
 			})
 		}
 	}
+	// Поля фасетной навигации: ОПФ, диапазон капитала и даты регистрации по именам из схемы
+	if v, ok := data["opfCode"].(string); ok && strings.TrimSpace(v) != "" {
+		filter.OpfCode = &v
+	}
+	if v, ok := data["capitalMin"].(float64); ok {
+		filter.CapitalMin = &v
+	}
+	if v, ok := data["capitalMax"].(float64); ok {
+		filter.CapitalMax = &v
+	}
+	if filter.RegisteredAfter == nil {
+		filter.RegisteredAfter = parseFilterDate(data, "registeredAfter")
+	}
+	if filter.RegisteredBefore == nil {
+		filter.RegisteredBefore = parseFilterDate(data, "registeredBefore")
+	}
 	agentLog("run-filters", "exec.go:parseCompanyFilter", "parsed company filter", map[string]interface{}{
 		"hasRegionCode": filter.RegionCode != nil,
 		"regionCode": func() string {
@@ -1169,6 +1897,19 @@ func parseCompanyFilter(data map[string]interface{}) *model.CompanyFilter {
 	return filter
 }
 
+// parseFilterDate разбирает необязательную дату фильтра в формате YYYY-MM-DD
+func parseFilterDate(data map[string]interface{}, name string) *model.Date {
+	v, ok := data[name].(string)
+	if !ok || strings.TrimSpace(v) == "" {
+		return nil
+	}
+	t, err := time.Parse("2006-01-02", strings.TrimSpace(v))
+	if err != nil {
+		return nil
+	}
+	return model.NewDate(t)
+}
+
 func parseCompanySort(data map[string]interface{}) *model.CompanySort {
 	sort := &model.CompanySort{}
 	if v, ok := data["field"].(string); ok {
@@ -1257,6 +1998,12 @@ func parseEntrepreneurFilter(data map[string]interface{}) *model.EntrepreneurFil
 			})
 		}
 	}
+	if filter.RegisteredAfter == nil {
+		filter.RegisteredAfter = parseFilterDate(data, "registeredAfter")
+	}
+	if filter.RegisteredBefore == nil {
+		filter.RegisteredBefore = parseFilterDate(data, "registeredBefore")
+	}
 	agentLog("run-filters", "exec.go:parseEntrepreneurFilter", "parsed entrepreneur filter", map[string]interface{}{
 		"hasRegionCode": filter.RegionCode != nil,
 		"regionCode": func() string {
@@ -1364,6 +2111,19 @@ func parseStatsFilter(data map[string]interface{}) *model.StatsFilter {
 	if v, ok := data["okved"].(string); ok {
 		filter.Okved = &v
 	}
+	// Некорректные даты игнорируются, как и прочие нераспознанные поля фильтра
+	if v, ok := data["dateFrom"].(string); ok && v != "" {
+		var date model.Date
+		if err := date.UnmarshalGQL(v); err == nil {
+			filter.DateFrom = &date
+		}
+	}
+	if v, ok := data["dateTo"].(string); ok && v != "" {
+		var date model.Date
+		if err := date.UnmarshalGQL(v); err == nil {
+			filter.DateTo = &date
+		}
+	}
 	return filter
 }
 
@@ -1394,21 +2154,7 @@ func (h *ManualHandler) handleRegisterMutation(ctx context.Context, req *GraphQL
 
 	return &GraphQLResponse{
 		Data: map[string]interface{}{
-			"register": map[string]interface{}{
-				"user": map[string]interface{}{
-					"id":            authResponse.User.ID,
-					"email":         authResponse.User.Email,
-					"firstName":     authResponse.User.FirstName,
-					"lastName":      authResponse.User.LastName,
-					"isActive":      authResponse.User.IsActive,
-					"emailVerified": authResponse.User.EmailVerified,
-					"createdAt":     authResponse.User.CreatedAt,
-					"updatedAt":     authResponse.User.UpdatedAt,
-					"lastLoginAt":   authResponse.User.LastLoginAt,
-				},
-				"token":     authResponse.Token,
-				"expiresAt": authResponse.ExpiresAt,
-			},
+			"register": authResponseData(authResponse),
 		},
 	}, nil
 }
@@ -1438,21 +2184,7 @@ func (h *ManualHandler) handleLoginMutation(ctx context.Context, req *GraphQLReq
 
 	return &GraphQLResponse{
 		Data: map[string]interface{}{
-			"login": map[string]interface{}{
-				"user": map[string]interface{}{
-					"id":            authResponse.User.ID,
-					"email":         authResponse.User.Email,
-					"firstName":     authResponse.User.FirstName,
-					"lastName":      authResponse.User.LastName,
-					"isActive":      authResponse.User.IsActive,
-					"emailVerified": authResponse.User.EmailVerified,
-					"createdAt":     authResponse.User.CreatedAt,
-					"updatedAt":     authResponse.User.UpdatedAt,
-					"lastLoginAt":   authResponse.User.LastLoginAt,
-				},
-				"token":     authResponse.Token,
-				"expiresAt": authResponse.ExpiresAt,
-			},
+			"login": authResponseData(authResponse),
 		},
 	}, nil
 }
@@ -1470,82 +2202,22 @@ func (h *ManualHandler) handleMeQuery(ctx context.Context, req *GraphQLRequest)
 
 	return &GraphQLResponse{
 		Data: map[string]interface{}{
-			"me": map[string]interface{}{
-				"id":            user.ID,
-				"email":         user.Email,
-				"firstName":     user.FirstName,
-				"lastName":      user.LastName,
-				"isActive":      user.IsActive,
-				"emailVerified": user.EmailVerified,
-				"createdAt":     user.CreatedAt,
-				"updatedAt":     user.UpdatedAt,
-				"lastLoginAt":   user.LastLoginAt,
-			},
+			"me": userData(user),
 		},
 	}, nil
 }
 
-// Subscription handlers
-
-// handleMySubscriptionsQuery обрабатывает mySubscriptions query
-func (h *ManualHandler) handleMySubscriptionsQuery(ctx context.Context, req *GraphQLRequest) (*GraphQLResponse, error) {
-	queryResolver := &queryResolver{h.resolver}
-	subscriptions, err := queryResolver.MySubscriptions(ctx)
-	if err != nil {
+// handleRefreshTokenMutation обрабатывает refreshToken mutation
+func (h *ManualHandler) handleRefreshTokenMutation(ctx context.Context, req *GraphQLRequest) (*GraphQLResponse, error) {
+	refreshToken, _ := req.Variables["refreshToken"].(string)
+	if refreshToken == "" {
 		return &GraphQLResponse{
-			Errors: []GraphQLError{{Message: err.Error()}},
+			Errors: []GraphQLError{{Message: "refreshToken is required"}},
 		}, nil
 	}
 
-	subscriptionsData := make([]map[string]interface{}, len(subscriptions))
-	for i, sub := range subscriptions {
-		subscriptionsData[i] = map[string]interface{}{
-			"id":             sub.ID,
-			"userId":         sub.UserID,
-			"entityType":     sub.EntityType,
-			"entityId":       sub.EntityID,
-			"entityName":     sub.EntityName,
-			"changeFilters": map[string]interface{}{
-				"status":     sub.ChangeFilters.Status,
-				"director":   sub.ChangeFilters.Director,
-				"founders":   sub.ChangeFilters.Founders,
-				"address":    sub.ChangeFilters.Address,
-				"capital":    sub.ChangeFilters.Capital,
-				"activities": sub.ChangeFilters.Activities,
-			},
-			"notificationChannels": map[string]interface{}{
-				"email": sub.NotificationChannels.Email,
-			},
-			"isActive":       sub.IsActive,
-			"createdAt":      sub.CreatedAt,
-			"updatedAt":      sub.UpdatedAt,
-			"lastNotifiedAt": sub.LastNotifiedAt,
-		}
-	}
-
-	return &GraphQLResponse{
-		Data: map[string]interface{}{
-			"mySubscriptions": subscriptionsData,
-		},
-	}, nil
-}
-
-// handleHasSubscriptionQuery обрабатывает hasSubscription query
-func (h *ManualHandler) handleHasSubscriptionQuery(ctx context.Context, req *GraphQLRequest) (*GraphQLResponse, error) {
-	var input struct {
-		EntityType string `json:"entityType"`
-		EntityID   string `json:"entityId"`
-	}
-
-	if et, ok := req.Variables["entityType"].(string); ok {
-		input.EntityType = et
-	}
-	if eid, ok := req.Variables["entityId"].(string); ok {
-		input.EntityID = eid
-	}
-
-	queryResolver := &queryResolver{h.resolver}
-	hasSubscription, err := queryResolver.HasSubscription(ctx, model.EntityType(input.EntityType), input.EntityID)
+	mutationResolver := &mutationResolver{h.resolver}
+	authResponse, err := mutationResolver.RefreshToken(ctx, refreshToken)
 	if err != nil {
 		return &GraphQLResponse{
 			Errors: []GraphQLError{{Message: err.Error()}},
@@ -1554,62 +2226,20 @@ func (h *ManualHandler) handleHasSubscriptionQuery(ctx context.Context, req *Gra
 
 	return &GraphQLResponse{
 		Data: map[string]interface{}{
-			"hasSubscription": hasSubscription,
+			"refreshToken": authResponseData(authResponse),
 		},
 	}, nil
 }
 
-// handleCreateSubscriptionMutation обрабатывает createSubscription mutation
-func (h *ManualHandler) handleCreateSubscriptionMutation(ctx context.Context, req *GraphQLRequest) (*GraphQLResponse, error) {
-	var input model.CreateSubscriptionInput
-
-	if inputData, ok := req.Variables["input"].(map[string]interface{}); ok {
-		if et, ok := inputData["entityType"].(string); ok {
-			input.EntityType = model.EntityType(et)
-		}
-		if eid, ok := inputData["entityId"].(string); ok {
-			input.EntityID = eid
-		}
-		if en, ok := inputData["entityName"].(string); ok {
-			input.EntityName = en
-		}
-
-		// Парсинг changeFilters
-		if cfData, ok := inputData["changeFilters"].(map[string]interface{}); ok {
-			filters := &model.ChangeFiltersInput{}
-			if v, ok := cfData["status"].(bool); ok {
-				filters.Status = &v
-			}
-			if v, ok := cfData["director"].(bool); ok {
-				filters.Director = &v
-			}
-			if v, ok := cfData["founders"].(bool); ok {
-				filters.Founders = &v
-			}
-			if v, ok := cfData["address"].(bool); ok {
-				filters.Address = &v
-			}
-			if v, ok := cfData["capital"].(bool); ok {
-				filters.Capital = &v
-			}
-			if v, ok := cfData["activities"].(bool); ok {
-				filters.Activities = &v
-			}
-			input.ChangeFilters = filters
-		}
-
-		// Парсинг notificationChannels
-		if ncData, ok := inputData["notificationChannels"].(map[string]interface{}); ok {
-			channels := &model.NotificationChannelsInput{}
-			if v, ok := ncData["email"].(bool); ok {
-				channels.Email = &v
-			}
-			input.NotificationChannels = channels
-		}
+// handleBeginOidcLoginMutation обрабатывает beginOidcLogin mutation
+func (h *ManualHandler) handleBeginOidcLoginMutation(ctx context.Context, req *GraphQLRequest) (*GraphQLResponse, error) {
+	var redirectURI *string
+	if uri, ok := req.Variables["redirectUri"].(string); ok && uri != "" {
+		redirectURI = &uri
 	}
 
 	mutationResolver := &mutationResolver{h.resolver}
-	subscription, err := mutationResolver.CreateSubscription(ctx, input)
+	start, err := mutationResolver.BeginOidcLogin(ctx, redirectURI)
 	if err != nil {
 		return &GraphQLResponse{
 			Errors: []GraphQLError{{Message: err.Error()}},
@@ -1618,34 +2248,26 @@ func (h *ManualHandler) handleCreateSubscriptionMutation(ctx context.Context, re
 
 	return &GraphQLResponse{
 		Data: map[string]interface{}{
-			"createSubscription": map[string]interface{}{
-				"id":          subscription.ID,
-				"userId":      subscription.UserID,
-				"entityType":  subscription.EntityType,
-				"entityId":    subscription.EntityID,
-				"entityName":  subscription.EntityName,
-				"isActive":    subscription.IsActive,
-				"createdAt":   subscription.CreatedAt,
+			"beginOidcLogin": map[string]interface{}{
+				"authorizationUrl": start.AuthorizationURL,
+				"state":            start.State,
 			},
 		},
 	}, nil
 }
 
-// handleToggleSubscriptionMutation обрабатывает toggleSubscription mutation
-func (h *ManualHandler) handleToggleSubscriptionMutation(ctx context.Context, req *GraphQLRequest) (*GraphQLResponse, error) {
-	var input model.ToggleSubscriptionInput
-
-	if inputData, ok := req.Variables["input"].(map[string]interface{}); ok {
-		if id, ok := inputData["id"].(string); ok {
-			input.ID = id
-		}
-		if isActive, ok := inputData["isActive"].(bool); ok {
-			input.IsActive = isActive
-		}
+// handleCompleteOidcLoginMutation обрабатывает completeOidcLogin mutation
+func (h *ManualHandler) handleCompleteOidcLoginMutation(ctx context.Context, req *GraphQLRequest) (*GraphQLResponse, error) {
+	code, _ := req.Variables["code"].(string)
+	state, _ := req.Variables["state"].(string)
+	if code == "" || state == "" {
+		return &GraphQLResponse{
+			Errors: []GraphQLError{{Message: "code and state are required"}},
+		}, nil
 	}
 
 	mutationResolver := &mutationResolver{h.resolver}
-	subscription, err := mutationResolver.ToggleSubscription(ctx, input)
+	authResponse, err := mutationResolver.CompleteOidcLogin(ctx, code, state)
 	if err != nil {
 		return &GraphQLResponse{
 			Errors: []GraphQLError{{Message: err.Error()}},
@@ -1654,203 +2276,494 @@ func (h *ManualHandler) handleToggleSubscriptionMutation(ctx context.Context, re
 
 	return &GraphQLResponse{
 		Data: map[string]interface{}{
-			"toggleSubscription": map[string]interface{}{
-				"id":        subscription.ID,
-				"isActive":  subscription.IsActive,
-				"updatedAt": subscription.UpdatedAt,
-			},
+			"completeOidcLogin": authResponseData(authResponse),
 		},
 	}, nil
 }
 
-// handleUpdateSubscriptionFiltersMutation обрабатывает updateSubscriptionFilters mutation
-func (h *ManualHandler) handleUpdateSubscriptionFiltersMutation(ctx context.Context, req *GraphQLRequest) (*GraphQLResponse, error) {
-	var input model.UpdateSubscriptionFiltersInput
-
-	if inputData, ok := req.Variables["input"].(map[string]interface{}); ok {
-		if id, ok := inputData["id"].(string); ok {
-			input.ID = id
-		}
-		if changeFilters, ok := inputData["changeFilters"].(map[string]interface{}); ok {
-			input.ChangeFilters = &model.ChangeFiltersInput{
-				Status:     getBoolPtr(changeFilters["status"]),
-				Director:   getBoolPtr(changeFilters["director"]),
-				Founders:   getBoolPtr(changeFilters["founders"]),
-				Address:    getBoolPtr(changeFilters["address"]),
-				Capital:    getBoolPtr(changeFilters["capital"]),
-				Activities: getBoolPtr(changeFilters["activities"]),
-			}
-		}
-	}
-
+// handleLogoutMutation обрабатывает logout mutation
+func (h *ManualHandler) handleLogoutMutation(ctx context.Context, req *GraphQLRequest) (*GraphQLResponse, error) {
 	mutationResolver := &mutationResolver{h.resolver}
-	subscription, err := mutationResolver.UpdateSubscriptionFilters(ctx, input)
+	ok, err := mutationResolver.Logout(ctx)
+	return booleanMutationResponse("logout", ok, err), nil
+}
+
+// handleMySessionsQuery обрабатывает mySessions query
+func (h *ManualHandler) handleMySessionsQuery(ctx context.Context, req *GraphQLRequest) (*GraphQLResponse, error) {
+	queryResolver := &queryResolver{h.resolver}
+	sessions, err := queryResolver.MySessions(ctx)
 	if err != nil {
 		return &GraphQLResponse{
 			Errors: []GraphQLError{{Message: err.Error()}},
 		}, nil
 	}
 
+	sessionsData := make([]map[string]interface{}, len(sessions))
+	for i, session := range sessions {
+		sessionsData[i] = map[string]interface{}{
+			"id":         session.ID,
+			"userAgent":  session.UserAgent,
+			"ipAddress":  session.IPAddress,
+			"createdAt":  session.CreatedAt,
+			"lastUsedAt": session.LastUsedAt,
+			"expiresAt":  session.ExpiresAt,
+			"current":    session.Current,
+		}
+	}
+
 	return &GraphQLResponse{
 		Data: map[string]interface{}{
-			"updateSubscriptionFilters": map[string]interface{}{
-				"id": subscription.ID,
-				"changeFilters": map[string]interface{}{
-					"status":     subscription.ChangeFilters.Status,
-					"director":   subscription.ChangeFilters.Director,
-					"founders":   subscription.ChangeFilters.Founders,
-					"address":    subscription.ChangeFilters.Address,
-					"capital":    subscription.ChangeFilters.Capital,
-					"activities": subscription.ChangeFilters.Activities,
-				},
-				"updatedAt": subscription.UpdatedAt,
-			},
+			"mySessions": sessionsData,
 		},
 	}, nil
 }
 
-// handleDeleteSubscriptionMutation обрабатывает deleteSubscription mutation
-func (h *ManualHandler) handleDeleteSubscriptionMutation(ctx context.Context, req *GraphQLRequest) (*GraphQLResponse, error) {
-	id, ok := req.Variables["id"].(string)
-	if !ok {
+// handleRevokeSessionMutation обрабатывает revokeSession mutation
+func (h *ManualHandler) handleRevokeSessionMutation(ctx context.Context, req *GraphQLRequest) (*GraphQLResponse, error) {
+	id, _ := req.Variables["id"].(string)
+	if id == "" {
 		return &GraphQLResponse{
 			Errors: []GraphQLError{{Message: "id is required"}},
 		}, nil
 	}
 
 	mutationResolver := &mutationResolver{h.resolver}
-	success, err := mutationResolver.DeleteSubscription(ctx, id)
-	if err != nil {
-		return &GraphQLResponse{
-			Errors: []GraphQLError{{Message: err.Error()}},
-		}, nil
+	ok, err := mutationResolver.RevokeSession(ctx, id)
+	return booleanMutationResponse("revokeSession", ok, err), nil
+}
+
+// authResponseData данные AuthResponse для ответа register/login/refreshToken
+func authResponseData(authResponse *model.AuthResponse) map[string]interface{} {
+	return map[string]interface{}{
+		"user":             userData(authResponse.User),
+		"token":            authResponse.Token,
+		"expiresAt":        authResponse.ExpiresAt,
+		"refreshToken":     authResponse.RefreshToken,
+		"refreshExpiresAt": authResponse.RefreshExpiresAt,
 	}
+}
 
-	return &GraphQLResponse{
-		Data: map[string]interface{}{
-			"deleteSubscription": success,
-		},
-	}, nil
+// userData данные пользователя для ответа
+func userData(user *model.User) map[string]interface{} {
+	return map[string]interface{}{
+		"id":                  user.ID,
+		"email":               user.Email,
+		"firstName":           user.FirstName,
+		"lastName":            user.LastName,
+		"role":                user.Role,
+		"isActive":            user.IsActive,
+		"emailVerified":       user.EmailVerified,
+		"createdAt":           user.CreatedAt,
+		"updatedAt":           user.UpdatedAt,
+		"lastLoginAt":         user.LastLoginAt,
+		"deletionScheduledAt": user.DeletionScheduledAt,
+	}
 }
 
-// Favorites handlers
+// handleRequestEmailVerificationMutation обрабатывает requestEmailVerification mutation
+func (h *ManualHandler) handleRequestEmailVerificationMutation(ctx context.Context, req *GraphQLRequest) (*GraphQLResponse, error) {
+	mutationResolver := &mutationResolver{h.resolver}
+	ok, err := mutationResolver.RequestEmailVerification(ctx)
+	return booleanMutationResponse("requestEmailVerification", ok, err), nil
+}
 
-// handleMyFavoritesQuery обрабатывает myFavorites query
-func (h *ManualHandler) handleMyFavoritesQuery(ctx context.Context, req *GraphQLRequest) (*GraphQLResponse, error) {
+// handleVerifyEmailMutation обрабатывает verifyEmail mutation
+func (h *ManualHandler) handleVerifyEmailMutation(ctx context.Context, req *GraphQLRequest) (*GraphQLResponse, error) {
+	token, _ := req.Variables["token"].(string)
+	if token == "" {
+		return &GraphQLResponse{
+			Errors: []GraphQLError{{Message: "token is required"}},
+		}, nil
+	}
+
+	mutationResolver := &mutationResolver{h.resolver}
+	ok, err := mutationResolver.VerifyEmail(ctx, token)
+	return booleanMutationResponse("verifyEmail", ok, err), nil
+}
+
+// handleRequestPasswordResetMutation обрабатывает requestPasswordReset mutation
+func (h *ManualHandler) handleRequestPasswordResetMutation(ctx context.Context, req *GraphQLRequest) (*GraphQLResponse, error) {
+	email, _ := req.Variables["email"].(string)
+	if email == "" {
+		return &GraphQLResponse{
+			Errors: []GraphQLError{{Message: "email is required"}},
+		}, nil
+	}
+
+	mutationResolver := &mutationResolver{h.resolver}
+	ok, err := mutationResolver.RequestPasswordReset(ctx, email)
+	return booleanMutationResponse("requestPasswordReset", ok, err), nil
+}
+
+// handleResetPasswordMutation обрабатывает resetPassword mutation
+func (h *ManualHandler) handleResetPasswordMutation(ctx context.Context, req *GraphQLRequest) (*GraphQLResponse, error) {
+	token, _ := req.Variables["token"].(string)
+	newPassword, _ := req.Variables["newPassword"].(string)
+	if token == "" || newPassword == "" {
+		return &GraphQLResponse{
+			Errors: []GraphQLError{{Message: "token and newPassword are required"}},
+		}, nil
+	}
+
+	mutationResolver := &mutationResolver{h.resolver}
+	ok, err := mutationResolver.ResetPassword(ctx, token, newPassword)
+	return booleanMutationResponse("resetPassword", ok, err), nil
+}
+
+// handleChangePasswordMutation обрабатывает changePassword mutation
+func (h *ManualHandler) handleChangePasswordMutation(ctx context.Context, req *GraphQLRequest) (*GraphQLResponse, error) {
+	currentPassword, _ := req.Variables["currentPassword"].(string)
+	newPassword, _ := req.Variables["newPassword"].(string)
+	if currentPassword == "" || newPassword == "" {
+		return &GraphQLResponse{
+			Errors: []GraphQLError{{Message: "currentPassword and newPassword are required"}},
+		}, nil
+	}
+
+	mutationResolver := &mutationResolver{h.resolver}
+	ok, err := mutationResolver.ChangePassword(ctx, currentPassword, newPassword)
+	return booleanMutationResponse("changePassword", ok, err), nil
+}
+
+// handleExportMyDataQuery обрабатывает exportMyData query
+func (h *ManualHandler) handleExportMyDataQuery(ctx context.Context, req *GraphQLRequest) (*GraphQLResponse, error) {
 	queryResolver := &queryResolver{h.resolver}
-	favorites, err := queryResolver.MyFavorites(ctx)
+	export, err := queryResolver.ExportMyData(ctx)
 	if err != nil {
 		return &GraphQLResponse{
 			Errors: []GraphQLError{{Message: err.Error()}},
 		}, nil
 	}
 
-	favoritesData := make([]map[string]interface{}, len(favorites))
-	for i, fav := range favorites {
-		favoritesData[i] = map[string]interface{}{
-			"id":         fav.ID,
-			"userId":     fav.UserID,
-			"entityType": fav.EntityType,
-			"entityId":   fav.EntityID,
-			"entityName": fav.EntityName,
-			"notes":      fav.Notes,
-			"createdAt":  fav.CreatedAt,
+	return &GraphQLResponse{
+		Data: map[string]interface{}{
+			"exportMyData": map[string]interface{}{
+				"generatedAt": export.GeneratedAt,
+				"data":        export.Data,
+				"downloadUrl": export.DownloadURL,
+			},
+		},
+	}, nil
+}
+
+// handleDeleteMyAccountMutation обрабатывает deleteMyAccount mutation
+func (h *ManualHandler) handleDeleteMyAccountMutation(ctx context.Context, req *GraphQLRequest) (*GraphQLResponse, error) {
+	// Пустой пароль допустим (пользователи SSO), но аргумент обязателен
+	password, ok := req.Variables["password"].(string)
+	if !ok {
+		return &GraphQLResponse{
+			Errors: []GraphQLError{{Message: "password is required"}},
+		}, nil
+	}
+
+	mutationResolver := &mutationResolver{h.resolver}
+	user, err := mutationResolver.DeleteMyAccount(ctx, password)
+	return userMutationResponse("deleteMyAccount", user, err), nil
+}
+
+// handleCancelAccountDeletionMutation обрабатывает cancelAccountDeletion mutation
+func (h *ManualHandler) handleCancelAccountDeletionMutation(ctx context.Context, req *GraphQLRequest) (*GraphQLResponse, error) {
+	mutationResolver := &mutationResolver{h.resolver}
+	user, err := mutationResolver.CancelAccountDeletion(ctx)
+	return userMutationResponse("cancelAccountDeletion", user, err), nil
+}
+
+// booleanMutationResponse ответ мутации, возвращающей Boolean!
+func booleanMutationResponse(field string, ok bool, err error) *GraphQLResponse {
+	if err != nil {
+		return &GraphQLResponse{
+			Errors: []GraphQLError{{Message: err.Error()}},
+		}
+	}
+	return &GraphQLResponse{
+		Data: map[string]interface{}{field: ok},
+	}
+}
+
+// Admin handlers
+
+// handleUsersQuery обрабатывает users query
+func (h *ManualHandler) handleUsersQuery(ctx context.Context, req *GraphQLRequest) (*GraphQLResponse, error) {
+	if err := requireRole(ctx, model.RoleAdmin); err != nil {
+		return &GraphQLResponse{
+			Errors: []GraphQLError{{Message: err.Error()}},
+		}, nil
+	}
+
+	var filter *model.UserFilter
+	if filterVar, ok := req.Variables["filter"].(map[string]interface{}); ok {
+		filter = &model.UserFilter{}
+		if q, ok := filterVar["query"].(string); ok {
+			filter.Query = &q
+		}
+		if r, ok := filterVar["role"].(string); ok {
+			role := model.Role(r)
+			if !role.IsValid() {
+				return &GraphQLResponse{
+					Errors: []GraphQLError{{Message: fmt.Sprintf("%s is not a valid Role", r)}},
+				}, nil
+			}
+			filter.Role = &role
 		}
+		if active, ok := filterVar["isActive"].(bool); ok {
+			filter.IsActive = &active
+		}
+	}
+
+	var limit, offset *int
+	if l, ok := req.Variables["limit"].(float64); ok {
+		v := int(l)
+		limit = &v
+	}
+	if o, ok := req.Variables["offset"].(float64); ok {
+		v := int(o)
+		offset = &v
+	}
+
+	queryResolver := &queryResolver{h.resolver}
+	list, err := queryResolver.Users(ctx, filter, limit, offset)
+	if err != nil {
+		return &GraphQLResponse{
+			Errors: []GraphQLError{{Message: err.Error()}},
+		}, nil
+	}
+
+	items := make([]map[string]interface{}, len(list.Items))
+	for i, user := range list.Items {
+		items[i] = userData(user)
 	}
 
 	return &GraphQLResponse{
 		Data: map[string]interface{}{
-			"myFavorites": favoritesData,
+			"users": map[string]interface{}{
+				"items": items,
+				"total": list.Total,
+			},
 		},
 	}, nil
 }
 
-// handleHasFavoriteQuery обрабатывает hasFavorite query
-func (h *ManualHandler) handleHasFavoriteQuery(ctx context.Context, req *GraphQLRequest) (*GraphQLResponse, error) {
-	var input struct {
-		EntityType string `json:"entityType"`
-		EntityID   string `json:"entityId"`
+// handleAuditLogQuery обрабатывает auditLog query
+func (h *ManualHandler) handleAuditLogQuery(ctx context.Context, req *GraphQLRequest) (*GraphQLResponse, error) {
+	if err := requireRole(ctx, model.RoleAdmin); err != nil {
+		return &GraphQLResponse{
+			Errors: []GraphQLError{{Message: err.Error()}},
+		}, nil
 	}
 
-	if et, ok := req.Variables["entityType"].(string); ok {
-		input.EntityType = et
+	var filter *model.AuditLogFilter
+	if filterVar, ok := req.Variables["filter"].(map[string]interface{}); ok {
+		filter = &model.AuditLogFilter{
+			DateFrom: parseFilterDate(filterVar, "dateFrom"),
+			DateTo:   parseFilterDate(filterVar, "dateTo"),
+		}
+		if userID, ok := filterVar["userId"].(string); ok && userID != "" {
+			filter.UserID = &userID
+		}
+		if a, ok := filterVar["action"].(string); ok {
+			action := model.AuditAction(a)
+			if !action.IsValid() {
+				return &GraphQLResponse{
+					Errors: []GraphQLError{{Message: fmt.Sprintf("%s is not a valid AuditAction", a)}},
+				}, nil
+			}
+			filter.Action = &action
+		}
 	}
-	if eid, ok := req.Variables["entityId"].(string); ok {
-		input.EntityID = eid
+
+	var limit, offset *int
+	if l, ok := req.Variables["limit"].(float64); ok {
+		v := int(l)
+		limit = &v
+	}
+	if o, ok := req.Variables["offset"].(float64); ok {
+		v := int(o)
+		offset = &v
 	}
 
 	queryResolver := &queryResolver{h.resolver}
-	hasFavorite, err := queryResolver.HasFavorite(ctx, model.EntityType(input.EntityType), input.EntityID)
+	list, err := queryResolver.AuditLog(ctx, filter, limit, offset)
 	if err != nil {
 		return &GraphQLResponse{
 			Errors: []GraphQLError{{Message: err.Error()}},
 		}, nil
 	}
 
+	items := make([]map[string]interface{}, len(list.Items))
+	for i, entry := range list.Items {
+		items[i] = map[string]interface{}{
+			"id":         entry.ID,
+			"time":       entry.Time,
+			"action":     entry.Action,
+			"success":    entry.Success,
+			"userId":     entry.UserID,
+			"userEmail":  entry.UserEmail,
+			"apiKeyId":   entry.APIKeyID,
+			"ipAddress":  entry.IPAddress,
+			"userAgent":  entry.UserAgent,
+			"entityType": entry.EntityType,
+			"entityId":   entry.EntityID,
+			"details":    entry.Details,
+		}
+	}
+
 	return &GraphQLResponse{
 		Data: map[string]interface{}{
-			"hasFavorite": hasFavorite,
+			"auditLog": map[string]interface{}{
+				"items": items,
+				"total": list.Total,
+			},
 		},
 	}, nil
 }
 
-// handleCreateFavoriteMutation обрабатывает createFavorite mutation
-func (h *ManualHandler) handleCreateFavoriteMutation(ctx context.Context, req *GraphQLRequest) (*GraphQLResponse, error) {
-	var input model.CreateFavoriteInput
+// handleSystemStatsQuery обрабатывает systemStats query
+func (h *ManualHandler) handleSystemStatsQuery(ctx context.Context, req *GraphQLRequest) (*GraphQLResponse, error) {
+	if err := requireRole(ctx, model.RoleAdmin); err != nil {
+		return &GraphQLResponse{
+			Errors: []GraphQLError{{Message: err.Error()}},
+		}, nil
+	}
 
-	if inputData, ok := req.Variables["input"].(map[string]interface{}); ok {
-		if et, ok := inputData["entityType"].(string); ok {
-			input.EntityType = model.EntityType(et)
-		}
-		if eid, ok := inputData["entityId"].(string); ok {
-			input.EntityID = eid
-		}
-		if en, ok := inputData["entityName"].(string); ok {
-			input.EntityName = en
-		}
-		if notes, ok := inputData["notes"].(string); ok {
-			input.Notes = &notes
-		}
+	queryResolver := &queryResolver{h.resolver}
+	stats, err := queryResolver.SystemStats(ctx)
+	if err != nil {
+		return &GraphQLResponse{
+			Errors: []GraphQLError{{Message: err.Error()}},
+		}, nil
+	}
+
+	return &GraphQLResponse{
+		Data: map[string]interface{}{
+			"systemStats": stats,
+		},
+	}, nil
+}
+
+// handleSetUserRoleMutation обрабатывает setUserRole mutation
+func (h *ManualHandler) handleSetUserRoleMutation(ctx context.Context, req *GraphQLRequest) (*GraphQLResponse, error) {
+	if err := requireRole(ctx, model.RoleAdmin); err != nil {
+		return &GraphQLResponse{
+			Errors: []GraphQLError{{Message: err.Error()}},
+		}, nil
+	}
+
+	id, _ := req.Variables["id"].(string)
+	roleVar, _ := req.Variables["role"].(string)
+	role := model.Role(roleVar)
+	if id == "" || !role.IsValid() {
+		return &GraphQLResponse{
+			Errors: []GraphQLError{{Message: "id and a valid role are required"}},
+		}, nil
 	}
 
 	mutationResolver := &mutationResolver{h.resolver}
-	favorite, err := mutationResolver.CreateFavorite(ctx, input)
+	user, err := mutationResolver.SetUserRole(ctx, id, role)
+	return userMutationResponse("setUserRole", user, err), nil
+}
+
+// handleDeactivateUserMutation обрабатывает deactivateUser mutation
+func (h *ManualHandler) handleDeactivateUserMutation(ctx context.Context, req *GraphQLRequest) (*GraphQLResponse, error) {
+	if err := requireRole(ctx, model.RoleAdmin); err != nil {
+		return &GraphQLResponse{
+			Errors: []GraphQLError{{Message: err.Error()}},
+		}, nil
+	}
+
+	id, _ := req.Variables["id"].(string)
+	if id == "" {
+		return &GraphQLResponse{
+			Errors: []GraphQLError{{Message: "id is required"}},
+		}, nil
+	}
+
+	mutationResolver := &mutationResolver{h.resolver}
+	user, err := mutationResolver.DeactivateUser(ctx, id)
+	return userMutationResponse("deactivateUser", user, err), nil
+}
+
+// userMutationResponse ответ мутации, возвращающей User!
+func userMutationResponse(field string, user *model.User, err error) *GraphQLResponse {
+	if err != nil {
+		return &GraphQLResponse{
+			Errors: []GraphQLError{{Message: err.Error()}},
+		}
+	}
+	return &GraphQLResponse{
+		Data: map[string]interface{}{field: userData(user)},
+	}
+}
+
+// API key handlers
+
+// handleMyAPIKeysQuery обрабатывает myApiKeys query
+func (h *ManualHandler) handleMyAPIKeysQuery(ctx context.Context, req *GraphQLRequest) (*GraphQLResponse, error) {
+	queryResolver := &queryResolver{h.resolver}
+	keys, err := queryResolver.MyAPIKeys(ctx)
 	if err != nil {
 		return &GraphQLResponse{
 			Errors: []GraphQLError{{Message: err.Error()}},
 		}, nil
 	}
 
+	keysData := make([]map[string]interface{}, len(keys))
+	for i, key := range keys {
+		keysData[i] = apiKeyData(key)
+	}
+
 	return &GraphQLResponse{
 		Data: map[string]interface{}{
-			"createFavorite": map[string]interface{}{
-				"id":         favorite.ID,
-				"userId":     favorite.UserID,
-				"entityType": favorite.EntityType,
-				"entityId":   favorite.EntityID,
-				"entityName": favorite.EntityName,
-				"notes":      favorite.Notes,
-				"createdAt":  favorite.CreatedAt,
-			},
+			"myApiKeys": keysData,
 		},
 	}, nil
 }
 
-// handleUpdateFavoriteNotesMutation обрабатывает updateFavoriteNotes mutation
-func (h *ManualHandler) handleUpdateFavoriteNotesMutation(ctx context.Context, req *GraphQLRequest) (*GraphQLResponse, error) {
-	var input model.UpdateFavoriteNotesInput
+// handleCreateAPIKeyMutation обрабатывает createApiKey mutation
+func (h *ManualHandler) handleCreateAPIKeyMutation(ctx context.Context, req *GraphQLRequest) (*GraphQLResponse, error) {
+	inputVar, ok := req.Variables["input"].(map[string]interface{})
+	if !ok {
+		return &GraphQLResponse{
+			Errors: []GraphQLError{{Message: "input is required"}},
+		}, nil
+	}
 
-	if inputData, ok := req.Variables["input"].(map[string]interface{}); ok {
-		if id, ok := inputData["id"].(string); ok {
-			input.ID = id
+	var input model.CreateAPIKeyInput
+	input.Name, _ = inputVar["name"].(string)
+	if scopes, ok := inputVar["scopes"].([]interface{}); ok {
+		for _, s := range scopes {
+			str, _ := s.(string)
+			scope := model.APIKeyScope(str)
+			if !scope.IsValid() {
+				return &GraphQLResponse{
+					Errors: []GraphQLError{{Message: fmt.Sprintf("%s is not a valid ApiKeyScope", str)}},
+				}, nil
+			}
+			input.Scopes = append(input.Scopes, scope)
 		}
-		if notes, ok := inputData["notes"].(string); ok {
-			input.Notes = &notes
+	}
+	if allowlist, ok := inputVar["ipAllowlist"].([]interface{}); ok {
+		for _, entry := range allowlist {
+			if str, ok := entry.(string); ok {
+				input.IPAllowlist = append(input.IPAllowlist, str)
+			}
+		}
+	}
+	if quota, ok := inputVar["dailyQuota"].(float64); ok {
+		v := int(quota)
+		input.DailyQuota = &v
+	}
+	if expiresAt, ok := inputVar["expiresAt"].(string); ok && expiresAt != "" {
+		t, err := time.Parse(time.RFC3339, expiresAt)
+		if err != nil {
+			return &GraphQLResponse{
+				Errors: []GraphQLError{{Message: "expiresAt must be an RFC 3339 date-time"}},
+			}, nil
 		}
+		input.ExpiresAt = &t
 	}
 
 	mutationResolver := &mutationResolver{h.resolver}
-	favorite, err := mutationResolver.UpdateFavoriteNotes(ctx, input)
+	payload, err := mutationResolver.CreateAPIKey(ctx, input)
 	if err != nil {
 		return &GraphQLResponse{
 			Errors: []GraphQLError{{Message: err.Error()}},
@@ -1859,46 +2772,1048 @@ func (h *ManualHandler) handleUpdateFavoriteNotesMutation(ctx context.Context, r
 
 	return &GraphQLResponse{
 		Data: map[string]interface{}{
-			"updateFavoriteNotes": map[string]interface{}{
-				"id":    favorite.ID,
-				"notes": favorite.Notes,
+			"createApiKey": map[string]interface{}{
+				"apiKey": apiKeyData(payload.APIKey),
+				"key":    payload.Key,
 			},
 		},
 	}, nil
 }
 
-// handleDeleteFavoriteMutation обрабатывает deleteFavorite mutation
-func (h *ManualHandler) handleDeleteFavoriteMutation(ctx context.Context, req *GraphQLRequest) (*GraphQLResponse, error) {
-	var id string
-
-	if idVar, ok := req.Variables["id"].(string); ok {
-		id = idVar
+// handleRevokeAPIKeyMutation обрабатывает revokeApiKey mutation
+func (h *ManualHandler) handleRevokeAPIKeyMutation(ctx context.Context, req *GraphQLRequest) (*GraphQLResponse, error) {
+	id, _ := req.Variables["id"].(string)
+	if id == "" {
+		return &GraphQLResponse{
+			Errors: []GraphQLError{{Message: "id is required"}},
+		}, nil
 	}
 
 	mutationResolver := &mutationResolver{h.resolver}
-	success, err := mutationResolver.DeleteFavorite(ctx, id)
+	ok, err := mutationResolver.RevokeAPIKey(ctx, id)
+	return booleanMutationResponse("revokeApiKey", ok, err), nil
+}
+
+// apiKeyData данные API ключа для ответа
+func apiKeyData(key *model.APIKey) map[string]interface{} {
+	return map[string]interface{}{
+		"id":           key.ID,
+		"name":         key.Name,
+		"prefix":       key.Prefix,
+		"scopes":       key.Scopes,
+		"ipAllowlist":  key.IPAllowlist,
+		"dailyQuota":   key.DailyQuota,
+		"expiresAt":    key.ExpiresAt,
+		"createdAt":    key.CreatedAt,
+		"lastUsedAt":   key.LastUsedAt,
+		"requestCount": key.RequestCount,
+	}
+}
+
+// Subscription handlers
+
+// handleMySubscriptionsQuery обрабатывает mySubscriptions query
+func (h *ManualHandler) handleMySubscriptionsQuery(ctx context.Context, req *GraphQLRequest) (*GraphQLResponse, error) {
+	queryResolver := &queryResolver{h.resolver}
+	subscriptions, err := queryResolver.MySubscriptions(ctx)
 	if err != nil {
 		return &GraphQLResponse{
 			Errors: []GraphQLError{{Message: err.Error()}},
 		}, nil
 	}
 
+	subscriptionsData := make([]map[string]interface{}, len(subscriptions))
+	for i, sub := range subscriptions {
+		subscriptionsData[i] = subscriptionData(sub)
+	}
+
 	return &GraphQLResponse{
 		Data: map[string]interface{}{
-			"deleteFavorite": success,
+			"mySubscriptions": subscriptionsData,
 		},
 	}, nil
 }
 
-// getBoolPtr преобразует interface{} в *bool
-func getBoolPtr(v interface{}) *bool {
-	if v == nil {
-		return nil
+// subscriptionData данные подписки для ответа
+func subscriptionData(sub *model.EntitySubscription) map[string]interface{} {
+	return map[string]interface{}{
+		"id":          sub.ID,
+		"userId":      sub.UserID,
+		"workspaceId": sub.WorkspaceID,
+		"entityType":  sub.EntityType,
+		"entityId":    sub.EntityID,
+		"entityName":  sub.EntityName,
+		"changeFilters": map[string]interface{}{
+			"status":     sub.ChangeFilters.Status,
+			"director":   sub.ChangeFilters.Director,
+			"founders":   sub.ChangeFilters.Founders,
+			"address":    sub.ChangeFilters.Address,
+			"capital":    sub.ChangeFilters.Capital,
+			"activities": sub.ChangeFilters.Activities,
+		},
+		"notificationChannels": map[string]interface{}{
+			"email": sub.NotificationChannels.Email,
+		},
+		"isActive":       sub.IsActive,
+		"createdAt":      sub.CreatedAt,
+		"updatedAt":      sub.UpdatedAt,
+		"lastNotifiedAt": sub.LastNotifiedAt,
 	}
-	if b, ok := v.(bool); ok {
-		return &b
+}
+
+// handleHasSubscriptionQuery обрабатывает hasSubscription query
+func (h *ManualHandler) handleHasSubscriptionQuery(ctx context.Context, req *GraphQLRequest) (*GraphQLResponse, error) {
+	var input struct {
+		EntityType string `json:"entityType"`
+		EntityID   string `json:"entityId"`
 	}
-	return nil
+
+	if et, ok := req.Variables["entityType"].(string); ok {
+		input.EntityType = et
+	}
+	if eid, ok := req.Variables["entityId"].(string); ok {
+		input.EntityID = eid
+	}
+
+	queryResolver := &queryResolver{h.resolver}
+	hasSubscription, err := queryResolver.HasSubscription(ctx, model.EntityType(input.EntityType), input.EntityID)
+	if err != nil {
+		return &GraphQLResponse{
+			Errors: []GraphQLError{{Message: err.Error()}},
+		}, nil
+	}
+
+	return &GraphQLResponse{
+		Data: map[string]interface{}{
+			"hasSubscription": hasSubscription,
+		},
+	}, nil
+}
+
+// handleCreateSubscriptionMutation обрабатывает createSubscription mutation
+func (h *ManualHandler) handleCreateSubscriptionMutation(ctx context.Context, req *GraphQLRequest) (*GraphQLResponse, error) {
+	var input model.CreateSubscriptionInput
+
+	if inputData, ok := req.Variables["input"].(map[string]interface{}); ok {
+		if et, ok := inputData["entityType"].(string); ok {
+			input.EntityType = model.EntityType(et)
+		}
+		if eid, ok := inputData["entityId"].(string); ok {
+			input.EntityID = eid
+		}
+		if en, ok := inputData["entityName"].(string); ok {
+			input.EntityName = en
+		}
+		if wid, ok := inputData["workspaceId"].(string); ok && wid != "" {
+			input.WorkspaceID = &wid
+		}
+
+		// Парсинг changeFilters
+		if cfData, ok := inputData["changeFilters"].(map[string]interface{}); ok {
+			filters := &model.ChangeFiltersInput{}
+			if v, ok := cfData["status"].(bool); ok {
+				filters.Status = &v
+			}
+			if v, ok := cfData["director"].(bool); ok {
+				filters.Director = &v
+			}
+			if v, ok := cfData["founders"].(bool); ok {
+				filters.Founders = &v
+			}
+			if v, ok := cfData["address"].(bool); ok {
+				filters.Address = &v
+			}
+			if v, ok := cfData["capital"].(bool); ok {
+				filters.Capital = &v
+			}
+			if v, ok := cfData["activities"].(bool); ok {
+				filters.Activities = &v
+			}
+			input.ChangeFilters = filters
+		}
+
+		// Парсинг notificationChannels
+		if ncData, ok := inputData["notificationChannels"].(map[string]interface{}); ok {
+			channels := &model.NotificationChannelsInput{}
+			if v, ok := ncData["email"].(bool); ok {
+				channels.Email = &v
+			}
+			input.NotificationChannels = channels
+		}
+	}
+
+	mutationResolver := &mutationResolver{h.resolver}
+	subscription, err := mutationResolver.CreateSubscription(ctx, input)
+	if err != nil {
+		return &GraphQLResponse{
+			Errors: []GraphQLError{{Message: err.Error()}},
+		}, nil
+	}
+
+	return &GraphQLResponse{
+		Data: map[string]interface{}{
+			"createSubscription": map[string]interface{}{
+				"id":          subscription.ID,
+				"userId":      subscription.UserID,
+				"workspaceId": subscription.WorkspaceID,
+				"entityType":  subscription.EntityType,
+				"entityId":    subscription.EntityID,
+				"entityName":  subscription.EntityName,
+				"isActive":    subscription.IsActive,
+				"createdAt":   subscription.CreatedAt,
+			},
+		},
+	}, nil
+}
+
+// handleToggleSubscriptionMutation обрабатывает toggleSubscription mutation
+func (h *ManualHandler) handleToggleSubscriptionMutation(ctx context.Context, req *GraphQLRequest) (*GraphQLResponse, error) {
+	var input model.ToggleSubscriptionInput
+
+	if inputData, ok := req.Variables["input"].(map[string]interface{}); ok {
+		if id, ok := inputData["id"].(string); ok {
+			input.ID = id
+		}
+		if isActive, ok := inputData["isActive"].(bool); ok {
+			input.IsActive = isActive
+		}
+	}
+
+	mutationResolver := &mutationResolver{h.resolver}
+	subscription, err := mutationResolver.ToggleSubscription(ctx, input)
+	if err != nil {
+		return &GraphQLResponse{
+			Errors: []GraphQLError{{Message: err.Error()}},
+		}, nil
+	}
+
+	return &GraphQLResponse{
+		Data: map[string]interface{}{
+			"toggleSubscription": map[string]interface{}{
+				"id":        subscription.ID,
+				"isActive":  subscription.IsActive,
+				"updatedAt": subscription.UpdatedAt,
+			},
+		},
+	}, nil
+}
+
+// handleUpdateSubscriptionFiltersMutation обрабатывает updateSubscriptionFilters mutation
+func (h *ManualHandler) handleUpdateSubscriptionFiltersMutation(ctx context.Context, req *GraphQLRequest) (*GraphQLResponse, error) {
+	var input model.UpdateSubscriptionFiltersInput
+
+	if inputData, ok := req.Variables["input"].(map[string]interface{}); ok {
+		if id, ok := inputData["id"].(string); ok {
+			input.ID = id
+		}
+		if changeFilters, ok := inputData["changeFilters"].(map[string]interface{}); ok {
+			input.ChangeFilters = &model.ChangeFiltersInput{
+				Status:     getBoolPtr(changeFilters["status"]),
+				Director:   getBoolPtr(changeFilters["director"]),
+				Founders:   getBoolPtr(changeFilters["founders"]),
+				Address:    getBoolPtr(changeFilters["address"]),
+				Capital:    getBoolPtr(changeFilters["capital"]),
+				Activities: getBoolPtr(changeFilters["activities"]),
+			}
+		}
+	}
+
+	mutationResolver := &mutationResolver{h.resolver}
+	subscription, err := mutationResolver.UpdateSubscriptionFilters(ctx, input)
+	if err != nil {
+		return &GraphQLResponse{
+			Errors: []GraphQLError{{Message: err.Error()}},
+		}, nil
+	}
+
+	return &GraphQLResponse{
+		Data: map[string]interface{}{
+			"updateSubscriptionFilters": map[string]interface{}{
+				"id": subscription.ID,
+				"changeFilters": map[string]interface{}{
+					"status":     subscription.ChangeFilters.Status,
+					"director":   subscription.ChangeFilters.Director,
+					"founders":   subscription.ChangeFilters.Founders,
+					"address":    subscription.ChangeFilters.Address,
+					"capital":    subscription.ChangeFilters.Capital,
+					"activities": subscription.ChangeFilters.Activities,
+				},
+				"updatedAt": subscription.UpdatedAt,
+			},
+		},
+	}, nil
+}
+
+// handleDeleteSubscriptionMutation обрабатывает deleteSubscription mutation
+func (h *ManualHandler) handleDeleteSubscriptionMutation(ctx context.Context, req *GraphQLRequest) (*GraphQLResponse, error) {
+	id, ok := req.Variables["id"].(string)
+	if !ok {
+		return &GraphQLResponse{
+			Errors: []GraphQLError{{Message: "id is required"}},
+		}, nil
+	}
+
+	mutationResolver := &mutationResolver{h.resolver}
+	success, err := mutationResolver.DeleteSubscription(ctx, id)
+	if err != nil {
+		return &GraphQLResponse{
+			Errors: []GraphQLError{{Message: err.Error()}},
+		}, nil
+	}
+
+	return &GraphQLResponse{
+		Data: map[string]interface{}{
+			"deleteSubscription": success,
+		},
+	}, nil
+}
+
+// Favorites handlers
+
+// handleMyFavoritesQuery обрабатывает myFavorites query
+func (h *ManualHandler) handleMyFavoritesQuery(ctx context.Context, req *GraphQLRequest) (*GraphQLResponse, error) {
+	var folder, tag *string
+	if v, ok := req.Variables["folder"].(string); ok {
+		folder = &v
+	}
+	if v, ok := req.Variables["tag"].(string); ok {
+		tag = &v
+	}
+
+	queryResolver := &queryResolver{h.resolver}
+	favorites, err := queryResolver.MyFavorites(ctx, folder, tag)
+	if err != nil {
+		return &GraphQLResponse{
+			Errors: []GraphQLError{{Message: err.Error()}},
+		}, nil
+	}
+
+	favoritesData := make([]map[string]interface{}, len(favorites))
+	for i, fav := range favorites {
+		favoritesData[i] = favoriteData(fav)
+	}
+
+	return &GraphQLResponse{
+		Data: map[string]interface{}{
+			"myFavorites": favoritesData,
+		},
+	}, nil
+}
+
+// favoriteData данные записи избранного для ответа
+func favoriteData(fav *model.Favorite) map[string]interface{} {
+	return map[string]interface{}{
+		"id":          fav.ID,
+		"userId":      fav.UserID,
+		"workspaceId": fav.WorkspaceID,
+		"entityType":  fav.EntityType,
+		"entityId":    fav.EntityID,
+		"entityName":  fav.EntityName,
+		"notes":       fav.Notes,
+		"folder":      fav.Folder,
+		"tags":        fav.Tags,
+		"createdAt":   fav.CreatedAt,
+	}
+}
+
+// handleHasFavoriteQuery обрабатывает hasFavorite query
+func (h *ManualHandler) handleHasFavoriteQuery(ctx context.Context, req *GraphQLRequest) (*GraphQLResponse, error) {
+	var input struct {
+		EntityType string `json:"entityType"`
+		EntityID   string `json:"entityId"`
+	}
+
+	if et, ok := req.Variables["entityType"].(string); ok {
+		input.EntityType = et
+	}
+	if eid, ok := req.Variables["entityId"].(string); ok {
+		input.EntityID = eid
+	}
+
+	queryResolver := &queryResolver{h.resolver}
+	hasFavorite, err := queryResolver.HasFavorite(ctx, model.EntityType(input.EntityType), input.EntityID)
+	if err != nil {
+		return &GraphQLResponse{
+			Errors: []GraphQLError{{Message: err.Error()}},
+		}, nil
+	}
+
+	return &GraphQLResponse{
+		Data: map[string]interface{}{
+			"hasFavorite": hasFavorite,
+		},
+	}, nil
+}
+
+// handleCreateFavoriteMutation обрабатывает createFavorite mutation
+func (h *ManualHandler) handleCreateFavoriteMutation(ctx context.Context, req *GraphQLRequest) (*GraphQLResponse, error) {
+	var input model.CreateFavoriteInput
+
+	if inputData, ok := req.Variables["input"].(map[string]interface{}); ok {
+		if et, ok := inputData["entityType"].(string); ok {
+			input.EntityType = model.EntityType(et)
+		}
+		if eid, ok := inputData["entityId"].(string); ok {
+			input.EntityID = eid
+		}
+		if en, ok := inputData["entityName"].(string); ok {
+			input.EntityName = en
+		}
+		if notes, ok := inputData["notes"].(string); ok {
+			input.Notes = &notes
+		}
+		if wid, ok := inputData["workspaceId"].(string); ok && wid != "" {
+			input.WorkspaceID = &wid
+		}
+		if folder, ok := inputData["folder"].(string); ok {
+			input.Folder = &folder
+		}
+		input.Tags = stringListVariable(inputData["tags"])
+	}
+
+	mutationResolver := &mutationResolver{h.resolver}
+	favorite, err := mutationResolver.CreateFavorite(ctx, input)
+	if err != nil {
+		return &GraphQLResponse{
+			Errors: []GraphQLError{{Message: err.Error()}},
+		}, nil
+	}
+
+	return &GraphQLResponse{
+		Data: map[string]interface{}{
+			"createFavorite": favoriteData(favorite),
+		},
+	}, nil
+}
+
+// handleUpdateFavoriteNotesMutation обрабатывает updateFavoriteNotes mutation
+func (h *ManualHandler) handleUpdateFavoriteNotesMutation(ctx context.Context, req *GraphQLRequest) (*GraphQLResponse, error) {
+	var input model.UpdateFavoriteNotesInput
+
+	if inputData, ok := req.Variables["input"].(map[string]interface{}); ok {
+		if id, ok := inputData["id"].(string); ok {
+			input.ID = id
+		}
+		if notes, ok := inputData["notes"].(string); ok {
+			input.Notes = &notes
+		}
+	}
+
+	mutationResolver := &mutationResolver{h.resolver}
+	favorite, err := mutationResolver.UpdateFavoriteNotes(ctx, input)
+	if err != nil {
+		return &GraphQLResponse{
+			Errors: []GraphQLError{{Message: err.Error()}},
+		}, nil
+	}
+
+	return &GraphQLResponse{
+		Data: map[string]interface{}{
+			"updateFavoriteNotes": map[string]interface{}{
+				"id":    favorite.ID,
+				"notes": favorite.Notes,
+			},
+		},
+	}, nil
+}
+
+// handleDeleteFavoriteMutation обрабатывает deleteFavorite mutation
+func (h *ManualHandler) handleDeleteFavoriteMutation(ctx context.Context, req *GraphQLRequest) (*GraphQLResponse, error) {
+	var id string
+
+	if idVar, ok := req.Variables["id"].(string); ok {
+		id = idVar
+	}
+
+	mutationResolver := &mutationResolver{h.resolver}
+	success, err := mutationResolver.DeleteFavorite(ctx, id)
+	if err != nil {
+		return &GraphQLResponse{
+			Errors: []GraphQLError{{Message: err.Error()}},
+		}, nil
+	}
+
+	return &GraphQLResponse{
+		Data: map[string]interface{}{
+			"deleteFavorite": success,
+		},
+	}, nil
+}
+
+// handleMyFavoriteFoldersQuery обрабатывает myFavoriteFolders query
+func (h *ManualHandler) handleMyFavoriteFoldersQuery(ctx context.Context, req *GraphQLRequest) (*GraphQLResponse, error) {
+	queryResolver := &queryResolver{h.resolver}
+	folders, err := queryResolver.MyFavoriteFolders(ctx)
+	if err != nil {
+		return &GraphQLResponse{
+			Errors: []GraphQLError{{Message: err.Error()}},
+		}, nil
+	}
+
+	foldersData := make([]map[string]interface{}, len(folders))
+	for i, folder := range folders {
+		foldersData[i] = map[string]interface{}{
+			"name":  folder.Name,
+			"count": folder.Count,
+		}
+	}
+
+	return &GraphQLResponse{
+		Data: map[string]interface{}{
+			"myFavoriteFolders": foldersData,
+		},
+	}, nil
+}
+
+// handleMyFavoriteTagsQuery обрабатывает myFavoriteTags query
+func (h *ManualHandler) handleMyFavoriteTagsQuery(ctx context.Context, req *GraphQLRequest) (*GraphQLResponse, error) {
+	queryResolver := &queryResolver{h.resolver}
+	tags, err := queryResolver.MyFavoriteTags(ctx)
+	if err != nil {
+		return &GraphQLResponse{
+			Errors: []GraphQLError{{Message: err.Error()}},
+		}, nil
+	}
+
+	return &GraphQLResponse{
+		Data: map[string]interface{}{
+			"myFavoriteTags": tags,
+		},
+	}, nil
+}
+
+// handleSetFavoriteTagsMutation обрабатывает setFavoriteTags mutation
+func (h *ManualHandler) handleSetFavoriteTagsMutation(ctx context.Context, req *GraphQLRequest) (*GraphQLResponse, error) {
+	id, _ := req.Variables["id"].(string)
+	if id == "" {
+		return &GraphQLResponse{
+			Errors: []GraphQLError{{Message: "id is required"}},
+		}, nil
+	}
+
+	mutationResolver := &mutationResolver{h.resolver}
+	favorite, err := mutationResolver.SetFavoriteTags(ctx, id, stringListVariable(req.Variables["tags"]))
+	if err != nil {
+		return &GraphQLResponse{
+			Errors: []GraphQLError{{Message: err.Error()}},
+		}, nil
+	}
+
+	return &GraphQLResponse{
+		Data: map[string]interface{}{
+			"setFavoriteTags": favoriteData(favorite),
+		},
+	}, nil
+}
+
+// handleAddFavoritesMutation обрабатывает addFavorites mutation
+func (h *ManualHandler) handleAddFavoritesMutation(ctx context.Context, req *GraphQLRequest) (*GraphQLResponse, error) {
+	inputVar, ok := req.Variables["input"].(map[string]interface{})
+	if !ok {
+		return &GraphQLResponse{
+			Errors: []GraphQLError{{Message: "input is required"}},
+		}, nil
+	}
+
+	var input model.AddFavoritesInput
+	input.Identifiers = stringListVariable(inputVar["identifiers"])
+	if folder, ok := inputVar["folder"].(string); ok {
+		input.Folder = &folder
+	}
+	input.Tags = stringListVariable(inputVar["tags"])
+	input.Subscribe = getBoolPtr(inputVar["subscribe"])
+
+	mutationResolver := &mutationResolver{h.resolver}
+	result, err := mutationResolver.AddFavorites(ctx, input)
+	if err != nil {
+		return &GraphQLResponse{
+			Errors: []GraphQLError{{Message: err.Error()}},
+		}, nil
+	}
+
+	return &GraphQLResponse{
+		Data: map[string]interface{}{
+			"addFavorites": result,
+		},
+	}, nil
+}
+
+// handleMoveFavoritesMutation обрабатывает moveFavorites mutation
+func (h *ManualHandler) handleMoveFavoritesMutation(ctx context.Context, req *GraphQLRequest) (*GraphQLResponse, error) {
+	var folder *string
+	if v, ok := req.Variables["folder"].(string); ok {
+		folder = &v
+	}
+
+	mutationResolver := &mutationResolver{h.resolver}
+	moved, err := mutationResolver.MoveFavorites(ctx, stringListVariable(req.Variables["ids"]), folder)
+	if err != nil {
+		return &GraphQLResponse{
+			Errors: []GraphQLError{{Message: err.Error()}},
+		}, nil
+	}
+
+	return &GraphQLResponse{
+		Data: map[string]interface{}{
+			"moveFavorites": moved,
+		},
+	}, nil
+}
+
+// handleDeleteFavoritesMutation обрабатывает deleteFavorites mutation
+func (h *ManualHandler) handleDeleteFavoritesMutation(ctx context.Context, req *GraphQLRequest) (*GraphQLResponse, error) {
+	mutationResolver := &mutationResolver{h.resolver}
+	deleted, err := mutationResolver.DeleteFavorites(ctx, stringListVariable(req.Variables["ids"]))
+	if err != nil {
+		return &GraphQLResponse{
+			Errors: []GraphQLError{{Message: err.Error()}},
+		}, nil
+	}
+
+	return &GraphQLResponse{
+		Data: map[string]interface{}{
+			"deleteFavorites": deleted,
+		},
+	}, nil
+}
+
+// Workspace handlers
+
+// handleMyWorkspacesQuery обрабатывает myWorkspaces query
+func (h *ManualHandler) handleMyWorkspacesQuery(ctx context.Context, req *GraphQLRequest) (*GraphQLResponse, error) {
+	queryResolver := &queryResolver{h.resolver}
+	workspaces, err := queryResolver.MyWorkspaces(ctx)
+	if err != nil {
+		return &GraphQLResponse{
+			Errors: []GraphQLError{{Message: err.Error()}},
+		}, nil
+	}
+
+	workspacesData := make([]map[string]interface{}, len(workspaces))
+	for i, workspace := range workspaces {
+		workspacesData[i] = workspaceData(workspace)
+	}
+
+	return &GraphQLResponse{
+		Data: map[string]interface{}{
+			"myWorkspaces": workspacesData,
+		},
+	}, nil
+}
+
+// handleWorkspaceQuery обрабатывает workspace query
+func (h *ManualHandler) handleWorkspaceQuery(ctx context.Context, req *GraphQLRequest) (*GraphQLResponse, error) {
+	id, _ := req.Variables["id"].(string)
+	if id == "" {
+		return &GraphQLResponse{
+			Errors: []GraphQLError{{Message: "id is required"}},
+		}, nil
+	}
+
+	queryResolver := &queryResolver{h.resolver}
+	workspace, err := queryResolver.Workspace(ctx, id)
+	if err != nil {
+		return &GraphQLResponse{
+			Errors: []GraphQLError{{Message: err.Error()}},
+		}, nil
+	}
+
+	var data interface{}
+	if workspace != nil {
+		data = workspaceData(workspace)
+	}
+
+	return &GraphQLResponse{
+		Data: map[string]interface{}{
+			"workspace": data,
+		},
+	}, nil
+}
+
+// handleWorkspaceMembersQuery обрабатывает workspaceMembers query
+func (h *ManualHandler) handleWorkspaceMembersQuery(ctx context.Context, req *GraphQLRequest) (*GraphQLResponse, error) {
+	workspaceID, _ := req.Variables["workspaceId"].(string)
+	if workspaceID == "" {
+		return &GraphQLResponse{
+			Errors: []GraphQLError{{Message: "workspaceId is required"}},
+		}, nil
+	}
+
+	queryResolver := &queryResolver{h.resolver}
+	members, err := queryResolver.WorkspaceMembers(ctx, workspaceID)
+	if err != nil {
+		return &GraphQLResponse{
+			Errors: []GraphQLError{{Message: err.Error()}},
+		}, nil
+	}
+
+	membersData := make([]map[string]interface{}, len(members))
+	for i, member := range members {
+		membersData[i] = workspaceMemberData(member)
+	}
+
+	return &GraphQLResponse{
+		Data: map[string]interface{}{
+			"workspaceMembers": membersData,
+		},
+	}, nil
+}
+
+// handleWorkspaceFavoritesQuery обрабатывает workspaceFavorites query
+func (h *ManualHandler) handleWorkspaceFavoritesQuery(ctx context.Context, req *GraphQLRequest) (*GraphQLResponse, error) {
+	workspaceID, _ := req.Variables["workspaceId"].(string)
+	if workspaceID == "" {
+		return &GraphQLResponse{
+			Errors: []GraphQLError{{Message: "workspaceId is required"}},
+		}, nil
+	}
+
+	queryResolver := &queryResolver{h.resolver}
+	favorites, err := queryResolver.WorkspaceFavorites(ctx, workspaceID)
+	if err != nil {
+		return &GraphQLResponse{
+			Errors: []GraphQLError{{Message: err.Error()}},
+		}, nil
+	}
+
+	favoritesData := make([]map[string]interface{}, len(favorites))
+	for i, fav := range favorites {
+		favoritesData[i] = favoriteData(fav)
+	}
+
+	return &GraphQLResponse{
+		Data: map[string]interface{}{
+			"workspaceFavorites": favoritesData,
+		},
+	}, nil
+}
+
+// handleWorkspaceSubscriptionsQuery обрабатывает workspaceSubscriptions query
+func (h *ManualHandler) handleWorkspaceSubscriptionsQuery(ctx context.Context, req *GraphQLRequest) (*GraphQLResponse, error) {
+	workspaceID, _ := req.Variables["workspaceId"].(string)
+	if workspaceID == "" {
+		return &GraphQLResponse{
+			Errors: []GraphQLError{{Message: "workspaceId is required"}},
+		}, nil
+	}
+
+	queryResolver := &queryResolver{h.resolver}
+	subscriptions, err := queryResolver.WorkspaceSubscriptions(ctx, workspaceID)
+	if err != nil {
+		return &GraphQLResponse{
+			Errors: []GraphQLError{{Message: err.Error()}},
+		}, nil
+	}
+
+	subscriptionsData := make([]map[string]interface{}, len(subscriptions))
+	for i, sub := range subscriptions {
+		subscriptionsData[i] = subscriptionData(sub)
+	}
+
+	return &GraphQLResponse{
+		Data: map[string]interface{}{
+			"workspaceSubscriptions": subscriptionsData,
+		},
+	}, nil
+}
+
+// handleEntityNotesQuery обрабатывает entityNotes query
+func (h *ManualHandler) handleEntityNotesQuery(ctx context.Context, req *GraphQLRequest) (*GraphQLResponse, error) {
+	workspaceID, _ := req.Variables["workspaceId"].(string)
+	entityType, _ := req.Variables["entityType"].(string)
+	entityID, _ := req.Variables["entityId"].(string)
+	if workspaceID == "" || entityType == "" || entityID == "" {
+		return &GraphQLResponse{
+			Errors: []GraphQLError{{Message: "workspaceId, entityType and entityId are required"}},
+		}, nil
+	}
+
+	queryResolver := &queryResolver{h.resolver}
+	notes, err := queryResolver.EntityNotes(ctx, workspaceID, model.EntityType(entityType), entityID)
+	if err != nil {
+		return &GraphQLResponse{
+			Errors: []GraphQLError{{Message: err.Error()}},
+		}, nil
+	}
+
+	notesData := make([]map[string]interface{}, len(notes))
+	for i, note := range notes {
+		notesData[i] = entityNoteData(note)
+	}
+
+	return &GraphQLResponse{
+		Data: map[string]interface{}{
+			"entityNotes": notesData,
+		},
+	}, nil
+}
+
+// handleCreateWorkspaceMutation обрабатывает createWorkspace mutation
+func (h *ManualHandler) handleCreateWorkspaceMutation(ctx context.Context, req *GraphQLRequest) (*GraphQLResponse, error) {
+	name, _ := req.Variables["name"].(string)
+
+	mutationResolver := &mutationResolver{h.resolver}
+	workspace, err := mutationResolver.CreateWorkspace(ctx, name)
+	return workspaceMutationResponse("createWorkspace", workspace, err), nil
+}
+
+// handleRenameWorkspaceMutation обрабатывает renameWorkspace mutation
+func (h *ManualHandler) handleRenameWorkspaceMutation(ctx context.Context, req *GraphQLRequest) (*GraphQLResponse, error) {
+	id, _ := req.Variables["id"].(string)
+	name, _ := req.Variables["name"].(string)
+	if id == "" {
+		return &GraphQLResponse{
+			Errors: []GraphQLError{{Message: "id is required"}},
+		}, nil
+	}
+
+	mutationResolver := &mutationResolver{h.resolver}
+	workspace, err := mutationResolver.RenameWorkspace(ctx, id, name)
+	return workspaceMutationResponse("renameWorkspace", workspace, err), nil
+}
+
+// handleDeleteWorkspaceMutation обрабатывает deleteWorkspace mutation
+func (h *ManualHandler) handleDeleteWorkspaceMutation(ctx context.Context, req *GraphQLRequest) (*GraphQLResponse, error) {
+	id, _ := req.Variables["id"].(string)
+	if id == "" {
+		return &GraphQLResponse{
+			Errors: []GraphQLError{{Message: "id is required"}},
+		}, nil
+	}
+
+	mutationResolver := &mutationResolver{h.resolver}
+	ok, err := mutationResolver.DeleteWorkspace(ctx, id)
+	return booleanMutationResponse("deleteWorkspace", ok, err), nil
+}
+
+// handleAddWorkspaceMemberMutation обрабатывает addWorkspaceMember mutation
+func (h *ManualHandler) handleAddWorkspaceMemberMutation(ctx context.Context, req *GraphQLRequest) (*GraphQLResponse, error) {
+	inputVar, ok := req.Variables["input"].(map[string]interface{})
+	if !ok {
+		return &GraphQLResponse{
+			Errors: []GraphQLError{{Message: "input is required"}},
+		}, nil
+	}
+
+	var input model.AddWorkspaceMemberInput
+	input.WorkspaceID, _ = inputVar["workspaceId"].(string)
+	input.Email, _ = inputVar["email"].(string)
+	roleVar, _ := inputVar["role"].(string)
+	input.Role = model.WorkspaceRole(roleVar)
+	if input.WorkspaceID == "" || !input.Role.IsValid() {
+		return &GraphQLResponse{
+			Errors: []GraphQLError{{Message: "workspaceId and a valid role are required"}},
+		}, nil
+	}
+
+	mutationResolver := &mutationResolver{h.resolver}
+	member, err := mutationResolver.AddWorkspaceMember(ctx, input)
+	return workspaceMemberMutationResponse("addWorkspaceMember", member, err), nil
+}
+
+// handleSetWorkspaceMemberRoleMutation обрабатывает setWorkspaceMemberRole mutation
+func (h *ManualHandler) handleSetWorkspaceMemberRoleMutation(ctx context.Context, req *GraphQLRequest) (*GraphQLResponse, error) {
+	workspaceID, _ := req.Variables["workspaceId"].(string)
+	userID, _ := req.Variables["userId"].(string)
+	roleVar, _ := req.Variables["role"].(string)
+	role := model.WorkspaceRole(roleVar)
+	if workspaceID == "" || userID == "" || !role.IsValid() {
+		return &GraphQLResponse{
+			Errors: []GraphQLError{{Message: "workspaceId, userId and a valid role are required"}},
+		}, nil
+	}
+
+	mutationResolver := &mutationResolver{h.resolver}
+	member, err := mutationResolver.SetWorkspaceMemberRole(ctx, workspaceID, userID, role)
+	return workspaceMemberMutationResponse("setWorkspaceMemberRole", member, err), nil
+}
+
+// handleRemoveWorkspaceMemberMutation обрабатывает removeWorkspaceMember mutation
+func (h *ManualHandler) handleRemoveWorkspaceMemberMutation(ctx context.Context, req *GraphQLRequest) (*GraphQLResponse, error) {
+	workspaceID, _ := req.Variables["workspaceId"].(string)
+	userID, _ := req.Variables["userId"].(string)
+	if workspaceID == "" || userID == "" {
+		return &GraphQLResponse{
+			Errors: []GraphQLError{{Message: "workspaceId and userId are required"}},
+		}, nil
+	}
+
+	mutationResolver := &mutationResolver{h.resolver}
+	ok, err := mutationResolver.RemoveWorkspaceMember(ctx, workspaceID, userID)
+	return booleanMutationResponse("removeWorkspaceMember", ok, err), nil
+}
+
+// handleUpdateWorkspaceNotificationChannelsMutation обрабатывает updateWorkspaceNotificationChannels mutation
+func (h *ManualHandler) handleUpdateWorkspaceNotificationChannelsMutation(ctx context.Context, req *GraphQLRequest) (*GraphQLResponse, error) {
+	workspaceID, _ := req.Variables["workspaceId"].(string)
+	if workspaceID == "" {
+		return &GraphQLResponse{
+			Errors: []GraphQLError{{Message: "workspaceId is required"}},
+		}, nil
+	}
+
+	var channels model.NotificationChannelsInput
+	if ncData, ok := req.Variables["notificationChannels"].(map[string]interface{}); ok {
+		channels.Email = getBoolPtr(ncData["email"])
+	}
+
+	mutationResolver := &mutationResolver{h.resolver}
+	member, err := mutationResolver.UpdateWorkspaceNotificationChannels(ctx, workspaceID, channels)
+	return workspaceMemberMutationResponse("updateWorkspaceNotificationChannels", member, err), nil
+}
+
+// handleCreateEntityNoteMutation обрабатывает createEntityNote mutation
+func (h *ManualHandler) handleCreateEntityNoteMutation(ctx context.Context, req *GraphQLRequest) (*GraphQLResponse, error) {
+	inputVar, ok := req.Variables["input"].(map[string]interface{})
+	if !ok {
+		return &GraphQLResponse{
+			Errors: []GraphQLError{{Message: "input is required"}},
+		}, nil
+	}
+
+	var input model.CreateEntityNoteInput
+	input.WorkspaceID, _ = inputVar["workspaceId"].(string)
+	entityType, _ := inputVar["entityType"].(string)
+	input.EntityType = model.EntityType(entityType)
+	input.EntityID, _ = inputVar["entityId"].(string)
+	input.Body, _ = inputVar["body"].(string)
+
+	mutationResolver := &mutationResolver{h.resolver}
+	note, err := mutationResolver.CreateEntityNote(ctx, input)
+	return entityNoteMutationResponse("createEntityNote", note, err), nil
+}
+
+// handleUpdateEntityNoteMutation обрабатывает updateEntityNote mutation
+func (h *ManualHandler) handleUpdateEntityNoteMutation(ctx context.Context, req *GraphQLRequest) (*GraphQLResponse, error) {
+	id, _ := req.Variables["id"].(string)
+	body, _ := req.Variables["body"].(string)
+	if id == "" {
+		return &GraphQLResponse{
+			Errors: []GraphQLError{{Message: "id is required"}},
+		}, nil
+	}
+
+	mutationResolver := &mutationResolver{h.resolver}
+	note, err := mutationResolver.UpdateEntityNote(ctx, id, body)
+	return entityNoteMutationResponse("updateEntityNote", note, err), nil
+}
+
+// handleDeleteEntityNoteMutation обрабатывает deleteEntityNote mutation
+func (h *ManualHandler) handleDeleteEntityNoteMutation(ctx context.Context, req *GraphQLRequest) (*GraphQLResponse, error) {
+	id, _ := req.Variables["id"].(string)
+	if id == "" {
+		return &GraphQLResponse{
+			Errors: []GraphQLError{{Message: "id is required"}},
+		}, nil
+	}
+
+	mutationResolver := &mutationResolver{h.resolver}
+	ok, err := mutationResolver.DeleteEntityNote(ctx, id)
+	return booleanMutationResponse("deleteEntityNote", ok, err), nil
+}
+
+// workspaceMutationResponse ответ мутации, возвращающей Workspace!
+func workspaceMutationResponse(field string, workspace *model.Workspace, err error) *GraphQLResponse {
+	if err != nil {
+		return &GraphQLResponse{
+			Errors: []GraphQLError{{Message: err.Error()}},
+		}
+	}
+	return &GraphQLResponse{
+		Data: map[string]interface{}{field: workspaceData(workspace)},
+	}
+}
+
+// workspaceMemberMutationResponse ответ мутации, возвращающей WorkspaceMember!
+func workspaceMemberMutationResponse(field string, member *model.WorkspaceMember, err error) *GraphQLResponse {
+	if err != nil {
+		return &GraphQLResponse{
+			Errors: []GraphQLError{{Message: err.Error()}},
+		}
+	}
+	return &GraphQLResponse{
+		Data: map[string]interface{}{field: workspaceMemberData(member)},
+	}
+}
+
+// entityNoteMutationResponse ответ мутации, возвращающей EntityNote!
+func entityNoteMutationResponse(field string, note *model.EntityNote, err error) *GraphQLResponse {
+	if err != nil {
+		return &GraphQLResponse{
+			Errors: []GraphQLError{{Message: err.Error()}},
+		}
+	}
+	return &GraphQLResponse{
+		Data: map[string]interface{}{field: entityNoteData(note)},
+	}
+}
+
+// workspaceData данные пространства для ответа
+func workspaceData(workspace *model.Workspace) map[string]interface{} {
+	return map[string]interface{}{
+		"id":          workspace.ID,
+		"name":        workspace.Name,
+		"myRole":      workspace.MyRole,
+		"memberCount": workspace.MemberCount,
+		"createdAt":   workspace.CreatedAt,
+		"updatedAt":   workspace.UpdatedAt,
+	}
+}
+
+// workspaceMemberData данные участника пространства для ответа
+func workspaceMemberData(member *model.WorkspaceMember) map[string]interface{} {
+	return map[string]interface{}{
+		"userId":    member.UserID,
+		"email":     member.Email,
+		"firstName": member.FirstName,
+		"lastName":  member.LastName,
+		"role":      member.Role,
+		"notificationChannels": map[string]interface{}{
+			"email": member.NotificationChannels.Email,
+		},
+		"joinedAt": member.JoinedAt,
+	}
+}
+
+// entityNoteData данные заметки для ответа
+func entityNoteData(note *model.EntityNote) map[string]interface{} {
+	return map[string]interface{}{
+		"id":          note.ID,
+		"workspaceId": note.WorkspaceID,
+		"entityType":  note.EntityType,
+		"entityId":    note.EntityID,
+		"authorId":    note.AuthorID,
+		"authorName":  note.AuthorName,
+		"body":        note.Body,
+		"createdAt":   note.CreatedAt,
+		"updatedAt":   note.UpdatedAt,
+	}
+}
+
+// getBoolPtr преобразует interface{} в *bool
+func getBoolPtr(v interface{}) *bool {
+	if v == nil {
+		return nil
+	}
+	if b, ok := v.(bool); ok {
+		return &b
+	}
+	return nil
+}
+
+// stringListVariable преобразует список из variables в []string, пропуская не строки
+func stringListVariable(v interface{}) []string {
+	list, ok := v.([]interface{})
+	if !ok {
+		return nil
+	}
+	result := make([]string, 0, len(list))
+	for _, item := range list {
+		if str, ok := item.(string); ok {
+			result = append(result, str)
+		}
+	}
+	return result
 }
 
 // NewDefaultExecutableSchema возвращает handler для совместимости
@@ -0,0 +1,122 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/egrul-system/services/api-gateway/internal/auth"
+	"github.com/egrul-system/services/api-gateway/internal/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func serveGraphQL(t *testing.T, ctx context.Context, req GraphQLRequest) (int, GraphQLResponse) {
+	t.Helper()
+
+	body, err := json.Marshal(req)
+	require.NoError(t, err)
+
+	h := NewManualHandler(&Resolver{Logger: zap.NewNop()})
+	r := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(string(body))).WithContext(ctx)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+
+	var resp GraphQLResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	return w.Code, resp
+}
+
+func apiKeyContext(scopes ...string) context.Context {
+	ctx := context.WithValue(context.Background(), auth.UserIDKey, "user-1")
+	ctx = context.WithValue(ctx, auth.RoleKey, auth.RoleAdmin)
+	ctx = context.WithValue(ctx, auth.APIKeyIDKey, "key-1")
+	return context.WithValue(ctx, auth.ScopesKey, scopes)
+}
+
+func TestManualHandler_RejectsOperationOutsideQueryFields(t *testing.T) {
+	tests := []struct {
+		name string
+		req  GraphQLRequest
+	}{
+		{
+			name: "operation name of another field",
+			req:  GraphQLRequest{Query: "{ me { id } }", OperationName: "deleteMyAccount"},
+		},
+		{
+			name: "admin mutation as operation name",
+			req:  GraphQLRequest{Query: "{ me { id } }", OperationName: "setUserRole"},
+		},
+		{
+			name: "list handler under a cheap field",
+			req:  GraphQLRequest{Query: "{ me { id } }", OperationName: "searchCompanies"},
+		},
+		{
+			name: "export handler under a cheap field",
+			req:  GraphQLRequest{Query: "{ me { id } }", OperationName: "exportMyData"},
+		},
+		{
+			name: "field name in a comment",
+			req:  GraphQLRequest{Query: "{ me { id } } # deleteMyAccount(password: \"\")"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			ctx := apiKeyContext(auth.ScopeReadCompanies)
+
+			// Act
+			_, resp := serveGraphQL(t, ctx, tt.req)
+
+			// Assert
+			require.Len(t, resp.Errors, 1)
+			assert.Contains(t, resp.Errors[0].Message, "does not match the fields of the query")
+			assert.Nil(t, resp.Data)
+		})
+	}
+}
+
+func TestManualHandler_RejectsUnparseableQueryBeforeDispatch(t *testing.T) {
+	// Arrange
+	req := GraphQLRequest{Query: "{ companies(offset: 100000) { edges { node { ogrn } } }"}
+
+	// Act
+	code, resp := serveGraphQL(t, context.Background(), req)
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, code)
+	require.Len(t, resp.Errors, 1)
+	assert.Contains(t, resp.Errors[0].Message, "invalid query")
+}
+
+func TestRateLimitOperations_ClassifiesByParsedFields(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  []middleware.Operation
+	}{
+		{"list", "{ companies(limit: 10) { totalCount } }", []middleware.Operation{middleware.OperationList}},
+		{"search with profile", "{ me { id } search(query: \"x\") { total } }", []middleware.Operation{middleware.OperationQuery, middleware.OperationSearch}},
+		{"export", "{ exportMyData(format: JSON) }", []middleware.Operation{middleware.OperationExport}},
+		{"mutation", "mutation { deleteMyAccount }", []middleware.Operation{middleware.OperationMutation}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			fields, err := parseTopLevelFields(tt.query)
+			require.NoError(t, err)
+
+			// Act
+			ops := rateLimitOperations(fields)
+
+			// Assert
+			assert.Equal(t, tt.want, ops)
+		})
+	}
+}
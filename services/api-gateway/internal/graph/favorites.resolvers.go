@@ -6,10 +6,12 @@ package graph
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/egrul-system/services/api-gateway/internal/auth"
 	"github.com/egrul-system/services/api-gateway/internal/graph/model"
+	"github.com/egrul-system/services/api-gateway/internal/service"
 	"go.uber.org/zap"
 )
 
@@ -22,6 +24,44 @@ func (r *mutationResolver) CreateFavorite(ctx context.Context, input model.Creat
 	// Получаем userID из JWT context
 	userID := auth.GetUserIDFromContext(ctx)
 
+	folder, err := service.NormalizeFavoriteFolder(input.Folder)
+	if err != nil {
+		return nil, err
+	}
+	tags, err := service.NormalizeFavoriteTags(input.Tags)
+	if err != nil {
+		return nil, err
+	}
+
+	// Общее избранное пространства создается через сервис пространств
+	if input.WorkspaceID != nil {
+		if r.WorkspaceService == nil {
+			return nil, fmt.Errorf("workspace service not configured")
+		}
+		if folder != nil {
+			return nil, fmt.Errorf("%w: folders are only available for personal favorites", service.ErrInvalidFavoriteParams)
+		}
+		favorite := &model.Favorite{
+			EntityType: input.EntityType,
+			EntityID:   input.EntityID,
+			EntityName: input.EntityName,
+			Notes:      input.Notes,
+			Tags:       tags,
+		}
+		if err := r.WorkspaceService.AddFavorite(ctx, userID, *input.WorkspaceID, favorite); err != nil {
+			return nil, r.workspaceError("failed to create workspace favorite", err)
+		}
+
+		r.Logger.Info("workspace favorite created",
+			zap.String("id", favorite.ID),
+			zap.String("workspace_id", *input.WorkspaceID),
+			zap.String("user_id", userID),
+			zap.String("entity_id", favorite.EntityID),
+		)
+		r.recordFavoriteChange(ctx, model.AuditActionFavoriteCreate, favorite, nil)
+		return favorite, nil
+	}
+
 	// Проверяем, нет ли уже в избранном
 	exists, err := r.FavoriteRepo.HasFavorite(ctx, userID, string(input.EntityType), input.EntityID)
 	if err != nil {
@@ -43,6 +83,8 @@ func (r *mutationResolver) CreateFavorite(ctx context.Context, input model.Creat
 		EntityID:   input.EntityID,
 		EntityName: input.EntityName,
 		Notes:      input.Notes,
+		Folder:     folder,
+		Tags:       tags,
 	}
 
 	if err := r.FavoriteRepo.Create(ctx, favorite); err != nil {
@@ -60,6 +102,7 @@ func (r *mutationResolver) CreateFavorite(ctx context.Context, input model.Creat
 		zap.String("entity_type", string(favorite.EntityType)),
 		zap.String("entity_id", favorite.EntityID),
 	)
+	r.recordFavoriteChange(ctx, model.AuditActionFavoriteCreate, favorite, nil)
 
 	return favorite, nil
 }
@@ -86,8 +129,12 @@ func (r *mutationResolver) UpdateFavoriteNotes(ctx context.Context, input model.
 		return nil, fmt.Errorf("favorite not found")
 	}
 
-	// Проверяем, что пользователь владелец
-	if favorite.UserID != userID {
+	// Проверяем, что пользователь владелец или редактор пространства
+	if favorite.WorkspaceID != nil {
+		if err := r.authorizeWorkspaceEntry(ctx, userID, *favorite.WorkspaceID, service.WorkspaceRoleEditor); err != nil {
+			return nil, err
+		}
+	} else if favorite.UserID != userID {
 		return nil, fmt.Errorf("access denied: you can only update your own favorites")
 	}
 
@@ -105,6 +152,7 @@ func (r *mutationResolver) UpdateFavoriteNotes(ctx context.Context, input model.
 	r.Logger.Info("favorite notes updated",
 		zap.String("id", favorite.ID),
 	)
+	r.recordFavoriteChange(ctx, model.AuditActionFavoriteUpdate, favorite, map[string]interface{}{"changed": "notes"})
 
 	return favorite, nil
 }
@@ -132,7 +180,11 @@ func (r *mutationResolver) DeleteFavorite(ctx context.Context, id string) (bool,
 		return false, fmt.Errorf("favorite not found")
 	}
 
-	if favorite.UserID != userID {
+	if favorite.WorkspaceID != nil {
+		if err := r.authorizeWorkspaceEntry(ctx, userID, *favorite.WorkspaceID, service.WorkspaceRoleEditor); err != nil {
+			return false, err
+		}
+	} else if favorite.UserID != userID {
 		return false, fmt.Errorf("access denied: you can only delete your own favorites")
 	}
 
@@ -147,20 +199,152 @@ func (r *mutationResolver) DeleteFavorite(ctx context.Context, id string) (bool,
 	r.Logger.Info("favorite deleted",
 		zap.String("id", id),
 	)
+	r.recordFavoriteChange(ctx, model.AuditActionFavoriteDelete, favorite, nil)
 
 	return true, nil
 }
 
+// SetFavoriteTags is the resolver for the setFavoriteTags field.
+func (r *mutationResolver) SetFavoriteTags(ctx context.Context, id string, tags []string) (*model.Favorite, error) {
+	if r.FavoriteService == nil {
+		return nil, fmt.Errorf("favorite service not configured")
+	}
+
+	// Получаем userID из JWT context
+	userID := auth.GetUserIDFromContext(ctx)
+
+	favorite, err := r.FavoriteRepo.GetByID(ctx, id)
+	if err != nil {
+		r.Logger.Error("failed to get favorite",
+			zap.String("id", id),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	if favorite == nil {
+		return nil, fmt.Errorf("favorite not found")
+	}
+
+	// Проверяем, что пользователь владелец или редактор пространства
+	if favorite.WorkspaceID != nil {
+		if err := r.authorizeWorkspaceEntry(ctx, userID, *favorite.WorkspaceID, service.WorkspaceRoleEditor); err != nil {
+			return nil, err
+		}
+	} else if favorite.UserID != userID {
+		return nil, fmt.Errorf("access denied: you can only update your own favorites")
+	}
+
+	if err := r.FavoriteService.SetTags(ctx, favorite, tags); err != nil {
+		if !errors.Is(err, service.ErrInvalidFavoriteParams) {
+			r.Logger.Error("failed to set favorite tags",
+				zap.String("id", id),
+				zap.Error(err),
+			)
+		}
+		return nil, err
+	}
+	r.recordFavoriteChange(ctx, model.AuditActionFavoriteUpdate, favorite, map[string]interface{}{"changed": "tags"})
+
+	return favorite, nil
+}
+
+// AddFavorites is the resolver for the addFavorites field.
+func (r *mutationResolver) AddFavorites(ctx context.Context, input model.AddFavoritesInput) (*model.FavoriteImportResult, error) {
+	if r.FavoriteService == nil {
+		return nil, fmt.Errorf("favorite service not configured")
+	}
+
+	userID := auth.GetUserIDFromContext(ctx)
+
+	opts := service.FavoriteImportOptions{
+		Folder:    input.Folder,
+		Tags:      input.Tags,
+		Subscribe: input.Subscribe != nil && *input.Subscribe,
+	}
+	result, err := r.FavoriteService.AddMany(ctx, userID, input.Identifiers, opts)
+	if err != nil {
+		if !errors.Is(err, service.ErrInvalidFavoriteParams) {
+			r.Logger.Error("failed to add favorites",
+				zap.String("user_id", userID),
+				zap.Error(err),
+			)
+		}
+		return nil, err
+	}
+	r.AuditService.Record(ctx, service.AuditFavoriteImport(result, "identifiers"))
+
+	return result, nil
+}
+
+// MoveFavorites is the resolver for the moveFavorites field.
+func (r *mutationResolver) MoveFavorites(ctx context.Context, ids []string, folder *string) (int, error) {
+	if r.FavoriteService == nil {
+		return 0, fmt.Errorf("favorite service not configured")
+	}
+
+	userID := auth.GetUserIDFromContext(ctx)
+
+	moved, err := r.FavoriteService.Move(ctx, userID, ids, folder)
+	if err != nil {
+		if !errors.Is(err, service.ErrInvalidFavoriteParams) {
+			r.Logger.Error("failed to move favorites",
+				zap.String("user_id", userID),
+				zap.Error(err),
+			)
+		}
+		return 0, err
+	}
+
+	r.AuditService.Record(ctx, service.AuditEvent{
+		Action:     model.AuditActionFavoriteUpdate,
+		EntityType: service.AuditEntityFavorite,
+		Success:    true,
+		Details:    map[string]interface{}{"changed": "folder", "ids": ids, "moved": moved},
+	})
+
+	return moved, nil
+}
+
+// DeleteFavorites is the resolver for the deleteFavorites field.
+func (r *mutationResolver) DeleteFavorites(ctx context.Context, ids []string) (int, error) {
+	if r.FavoriteService == nil {
+		return 0, fmt.Errorf("favorite service not configured")
+	}
+
+	userID := auth.GetUserIDFromContext(ctx)
+
+	deleted, err := r.FavoriteService.DeleteMany(ctx, userID, ids)
+	if err != nil {
+		if !errors.Is(err, service.ErrInvalidFavoriteParams) {
+			r.Logger.Error("failed to delete favorites",
+				zap.String("user_id", userID),
+				zap.Error(err),
+			)
+		}
+		return 0, err
+	}
+
+	r.AuditService.Record(ctx, service.AuditEvent{
+		Action:     model.AuditActionFavoriteDelete,
+		EntityType: service.AuditEntityFavorite,
+		Success:    true,
+		Details:    map[string]interface{}{"ids": ids, "deleted": deleted},
+	})
+
+	return deleted, nil
+}
+
 // MyFavorites is the resolver for the myFavorites field.
-func (r *queryResolver) MyFavorites(ctx context.Context) ([]*model.Favorite, error) {
-	if r.FavoriteRepo == nil {
-		return nil, fmt.Errorf("favorite repository not configured")
+func (r *queryResolver) MyFavorites(ctx context.Context, folder *string, tag *string) ([]*model.Favorite, error) {
+	if r.FavoriteService == nil {
+		return nil, fmt.Errorf("favorite service not configured")
 	}
 
 	// Получаем userID из JWT context
 	userID := auth.GetUserIDFromContext(ctx)
 
-	favorites, err := r.FavoriteRepo.GetByUserID(ctx, userID)
+	favorites, err := r.FavoriteService.List(ctx, userID, folder, tag)
 	if err != nil {
 		r.Logger.Error("failed to get favorites by user id",
 			zap.String("user_id", userID),
@@ -172,6 +356,46 @@ func (r *queryResolver) MyFavorites(ctx context.Context) ([]*model.Favorite, err
 	return favorites, nil
 }
 
+// MyFavoriteFolders is the resolver for the myFavoriteFolders field.
+func (r *queryResolver) MyFavoriteFolders(ctx context.Context) ([]*model.FavoriteFolder, error) {
+	if r.FavoriteService == nil {
+		return nil, fmt.Errorf("favorite service not configured")
+	}
+
+	userID := auth.GetUserIDFromContext(ctx)
+
+	folders, err := r.FavoriteService.Folders(ctx, userID)
+	if err != nil {
+		r.Logger.Error("failed to get favorite folders",
+			zap.String("user_id", userID),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	return folders, nil
+}
+
+// MyFavoriteTags is the resolver for the myFavoriteTags field.
+func (r *queryResolver) MyFavoriteTags(ctx context.Context) ([]string, error) {
+	if r.FavoriteService == nil {
+		return nil, fmt.Errorf("favorite service not configured")
+	}
+
+	userID := auth.GetUserIDFromContext(ctx)
+
+	tags, err := r.FavoriteService.Tags(ctx, userID)
+	if err != nil {
+		r.Logger.Error("failed to get favorite tags",
+			zap.String("user_id", userID),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	return tags, nil
+}
+
 // HasFavorite is the resolver for the hasFavorite field.
 func (r *queryResolver) HasFavorite(ctx context.Context, entityType model.EntityType, entityID string) (bool, error) {
 	if r.FavoriteRepo == nil {
@@ -10,17 +10,29 @@ import (
 
 // QueryResolver interface for Query resolvers
 type QueryResolver interface {
-	Company(ctx context.Context, ogrn string) (*model.Company, error)
+	Company(ctx context.Context, ogrn string, asOf *model.Date) (*model.Company, error)
 	CompanyByInn(ctx context.Context, inn string) (*model.Company, error)
+	CompanyDiff(ctx context.Context, ogrn string, from model.Date, to model.Date) (*model.CompanyDiff, error)
+	RiskAssessments(ctx context.Context, ogrns []string) ([]*model.CompanyRiskAssessment, error)
+	MassAddresses(ctx context.Context, regionCode *string, minCount *int, limit *int, offset *int) ([]*model.MassAddress, error)
+	MassDirectors(ctx context.Context, minCount *int, limit *int, offset *int) ([]*model.MassDirector, error)
+	Person(ctx context.Context, inn string) (*model.PersonProfile, error)
+	PersonCandidates(ctx context.Context, name string, limit *int) ([]*model.PersonCandidate, error)
 	Companies(ctx context.Context, filter *model.CompanyFilter, pagination *model.Pagination, sort *model.CompanySort) (*model.CompanyConnection, error)
 	SearchCompanies(ctx context.Context, query string, limit *int, offset *int) ([]*model.Company, error)
-	Entrepreneur(ctx context.Context, ogrnip string) (*model.Entrepreneur, error)
+	Entrepreneur(ctx context.Context, ogrnip string, asOf *model.Date) (*model.Entrepreneur, error)
 	EntrepreneurByInn(ctx context.Context, inn string) (*model.Entrepreneur, error)
 	Entrepreneurs(ctx context.Context, filter *model.EntrepreneurFilter, pagination *model.Pagination, sort *model.EntrepreneurSort) (*model.EntrepreneurConnection, error)
 	SearchEntrepreneurs(ctx context.Context, query string, limit *int, offset *int) ([]*model.Entrepreneur, error)
 	Search(ctx context.Context, query string, limit *int) (*model.SearchResult, error)
+	Suggest(ctx context.Context, prefix string, types []model.EntityType, limit *int) ([]*model.Suggestion, error)
+	CompanySearch(ctx context.Context, query *string, filter *model.CompanyFilter, limit *int, offset *int) (*model.CompanySearchResult, error)
+	EntrepreneurSearch(ctx context.Context, query *string, filter *model.EntrepreneurFilter, limit *int, offset *int) (*model.EntrepreneurSearchResult, error)
 	Statistics(ctx context.Context, filter *model.StatsFilter) (*model.Statistics, error)
 	DashboardStatistics(ctx context.Context, filter *model.StatsFilter) (*model.DashboardStatistics, error)
+	TimeSeries(ctx context.Context, metric model.TimeSeriesMetric, granularity *model.TimeGranularity, groupBy *model.TimeSeriesGroupBy, entityType *model.EntityType, filter *model.StatsFilter) ([]*model.GroupedTimeSeriesPoint, error)
+	SurvivalCohorts(ctx context.Context, cohortGranularity *model.CohortGranularity, entityType *model.EntityType, filter *model.StatsFilter) ([]*model.SurvivalCohort, error)
+	OkvedTree(ctx context.Context, parent *string) ([]*model.OkvedNode, error)
 	EntityHistory(ctx context.Context, entityType model.EntityType, entityID string, limit *int, offset *int) ([]*model.HistoryRecord, error)
 	EntityHistoryCount(ctx context.Context, entityType model.EntityType, entityID string) (int, error)
 	CompanyFounders(ctx context.Context, ogrn string, limit *int, offset *int) ([]*model.Founder, error)
@@ -32,11 +44,18 @@ type CompanyResolver interface {
 	Founders(ctx context.Context, obj *model.Company, limit *int, offset *int) ([]*model.Founder, error)
 	Licenses(ctx context.Context, obj *model.Company) ([]*model.License, error)
 	Branches(ctx context.Context, obj *model.Company) ([]*model.Branch, error)
+	RiskAssessment(ctx context.Context, obj *model.Company) (*model.RiskAssessment, error)
+	MassAddress(ctx context.Context, obj *model.Company) (*model.MassAddress, error)
 	History(ctx context.Context, obj *model.Company, limit *int, offset *int) ([]*model.HistoryRecord, error)
 	HistoryCount(ctx context.Context, obj *model.Company) (int, error)
 	RelatedCompanies(ctx context.Context, obj *model.Company, limit *int, offset *int) ([]*model.RelatedCompany, error)
 }
 
+// PersonResolver interface for Person field resolvers
+type PersonResolver interface {
+	MassDirector(ctx context.Context, obj *model.Person) (*model.MassDirector, error)
+}
+
 // EntrepreneurResolver interface for Entrepreneur field resolvers
 type EntrepreneurResolver interface {
 	Licenses(ctx context.Context, obj *model.Entrepreneur) ([]*model.License, error)
@@ -46,13 +65,20 @@ type EntrepreneurResolver interface {
 
 // StatisticsResolver interface for Statistics field resolvers
 type StatisticsResolver interface {
-	ByActivity(ctx context.Context, obj *model.Statistics, limit *int) ([]*model.ActivityStatistics, error)
+	ByActivity(ctx context.Context, obj *model.Statistics, limit *int, level *model.OkvedLevel, filter *model.StatsFilter) ([]*model.ActivityStatistics, error)
 }
 
 // DashboardStatisticsResolver interface for DashboardStatistics field resolvers
 type DashboardStatisticsResolver interface {
 	RegistrationsByMonth(ctx context.Context, obj *model.DashboardStatistics, dateFrom, dateTo *string, entityType *model.EntityType, filter *model.StatsFilter) ([]*model.TimeSeriesPoint, error)
-	RegionHeatmap(ctx context.Context, obj *model.DashboardStatistics) ([]*model.RegionStatistics, error)
+	RegionHeatmap(ctx context.Context, obj *model.DashboardStatistics, filter *model.StatsFilter) ([]*model.RegionStatistics, error)
+	TopOwners(ctx context.Context, obj *model.DashboardStatistics, ownerType *model.FounderType, sortBy *model.TopOwnerSortField, order *model.SortOrder, limit *int, filter *model.StatsFilter) ([]*model.TopOwner, error)
+	OwnershipByType(ctx context.Context, obj *model.DashboardStatistics, filter *model.StatsFilter) ([]*model.OwnershipTypeStatistics, error)
+	LegalForms(ctx context.Context, obj *model.DashboardStatistics, limit *int, filter *model.StatsFilter) ([]*model.LegalFormStatistics, error)
+	ChangesByMonth(ctx context.Context, obj *model.DashboardStatistics, entityType *model.EntityType, reasonCode *string, filter *model.StatsFilter) ([]*model.ChangesByMonthPoint, error)
+	ChangesByDay(ctx context.Context, obj *model.DashboardStatistics, types []string, significantOnly *bool, dateFrom, dateTo *string, filter *model.StatsFilter) ([]*model.ChangesByDayPoint, error)
+	MostChangedCompanies(ctx context.Context, obj *model.DashboardStatistics, month *string, limit *int, filter *model.StatsFilter) ([]*model.MostChangedCompany, error)
+	SignificantChangesByRegion(ctx context.Context, obj *model.DashboardStatistics, types []string, dateFrom, dateTo *string, filter *model.StatsFilter) ([]*model.RegionChangesStatistics, error)
 }
 
 // MutationResolver interface for Mutation resolvers
@@ -71,6 +97,7 @@ type MutationResolver interface {
 type ResolverRoot interface {
 	Query() QueryResolver
 	Company() CompanyResolver
+	Person() PersonResolver
 	Entrepreneur() EntrepreneurResolver
 	Statistics() StatisticsResolver
 	DashboardStatistics() DashboardStatisticsResolver
@@ -0,0 +1,99 @@
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+// Role роль пользователя
+type Role string
+
+const (
+	RoleUser    Role = "USER"
+	RoleAnalyst Role = "ANALYST"
+	RoleAdmin   Role = "ADMIN"
+)
+
+var AllRoles = []Role{
+	RoleUser,
+	RoleAnalyst,
+	RoleAdmin,
+}
+
+func (e Role) IsValid() bool {
+	switch e {
+	case RoleUser, RoleAnalyst, RoleAdmin:
+		return true
+	}
+	return false
+}
+
+func (e Role) String() string {
+	return string(e)
+}
+
+// ParseRole преобразует роль из БД (user, analyst, admin) в GraphQL enum
+func ParseRole(s string) Role {
+	if s == "" {
+		return RoleUser
+	}
+	return Role(strings.ToUpper(s))
+}
+
+// DBValue значение роли для хранения в БД и JWT
+func (e Role) DBValue() string {
+	return strings.ToLower(string(e))
+}
+
+// UserFilter фильтр списка пользователей
+type UserFilter struct {
+	Query    *string `json:"query,omitempty"`
+	Role     *Role   `json:"role,omitempty"`
+	IsActive *bool   `json:"isActive,omitempty"`
+}
+
+// UserList страница списка пользователей
+type UserList struct {
+	Items []*User `json:"items"`
+	Total int     `json:"total"`
+}
+
+// RoleCount число активных пользователей с ролью
+type RoleCount struct {
+	Role  Role `json:"role"`
+	Count int  `json:"count"`
+}
+
+// SubscriptionStatsEntry статистика подписок по типу сущности
+type SubscriptionStatsEntry struct {
+	EntityType  string `json:"entityType"`
+	Total       int    `json:"total"`
+	Active      int    `json:"active"`
+	Inactive    int    `json:"inactive"`
+	UniqueUsers int    `json:"uniqueUsers"`
+}
+
+// NotificationStatsEntry статистика уведомлений по каналу и статусу
+type NotificationStatsEntry struct {
+	Channel            string   `json:"channel"`
+	Status             string   `json:"status"`
+	Count              int      `json:"count"`
+	Retried            int      `json:"retried"`
+	AvgDeliverySeconds *float64 `json:"avgDeliverySeconds,omitempty"`
+}
+
+// NotificationHubStats состояние Notification Hub (SSE подключения)
+type NotificationHubStats struct {
+	ConnectedClients int `json:"connectedClients"`
+	MaxClients       int `json:"maxClients"`
+	BroadcastQueue   int `json:"broadcastQueue"`
+}
+
+// SystemStats статистика по всей системе
+type SystemStats struct {
+	UsersByRole        []*RoleCount              `json:"usersByRole"`
+	Subscriptions      []*SubscriptionStatsEntry `json:"subscriptions"`
+	Notifications      []*NotificationStatsEntry `json:"notifications"`
+	NotificationsSince time.Time                 `json:"notificationsSince"`
+	NotificationHub    *NotificationHubStats     `json:"notificationHub,omitempty"`
+}
@@ -0,0 +1,72 @@
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+// APIKeyScope разрешение API ключа
+type APIKeyScope string
+
+const (
+	APIKeyScopeReadCompanies       APIKeyScope = "READ_COMPANIES"
+	APIKeyScopeReadStats           APIKeyScope = "READ_STATS"
+	APIKeyScopeManageSubscriptions APIKeyScope = "MANAGE_SUBSCRIPTIONS"
+)
+
+var AllAPIKeyScope = []APIKeyScope{
+	APIKeyScopeReadCompanies,
+	APIKeyScopeReadStats,
+	APIKeyScopeManageSubscriptions,
+}
+
+func (e APIKeyScope) IsValid() bool {
+	switch e {
+	case APIKeyScopeReadCompanies, APIKeyScopeReadStats, APIKeyScopeManageSubscriptions:
+		return true
+	}
+	return false
+}
+
+func (e APIKeyScope) String() string {
+	return string(e)
+}
+
+// Value значение scope в БД и проверках доступа (READ_COMPANIES -> read:companies)
+func (e APIKeyScope) Value() string {
+	return strings.Replace(strings.ToLower(string(e)), "_", ":", 1)
+}
+
+// ParseAPIKeyScope преобразует scope из БД в GraphQL enum
+func ParseAPIKeyScope(s string) APIKeyScope {
+	return APIKeyScope(strings.ToUpper(strings.Replace(s, ":", "_", 1)))
+}
+
+// APIKey API ключ
+type APIKey struct {
+	ID           string        `json:"id"`
+	Name         string        `json:"name"`
+	Prefix       string        `json:"prefix"`
+	Scopes       []APIKeyScope `json:"scopes"`
+	IPAllowlist  []string      `json:"ipAllowlist"`
+	DailyQuota   *int          `json:"dailyQuota,omitempty"`
+	ExpiresAt    *time.Time    `json:"expiresAt,omitempty"`
+	CreatedAt    time.Time     `json:"createdAt"`
+	LastUsedAt   *time.Time    `json:"lastUsedAt,omitempty"`
+	RequestCount int           `json:"requestCount"`
+}
+
+// CreateAPIKeyInput параметры нового API ключа
+type CreateAPIKeyInput struct {
+	Name        string        `json:"name"`
+	Scopes      []APIKeyScope `json:"scopes"`
+	IPAllowlist []string      `json:"ipAllowlist,omitempty"`
+	DailyQuota  *int          `json:"dailyQuota,omitempty"`
+	ExpiresAt   *time.Time    `json:"expiresAt,omitempty"`
+}
+
+// CreateAPIKeyPayload созданный API ключ
+type CreateAPIKeyPayload struct {
+	APIKey *APIKey `json:"apiKey"`
+	Key    string  `json:"key"`
+}
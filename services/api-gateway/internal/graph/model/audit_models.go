@@ -0,0 +1,87 @@
+package model
+
+import "time"
+
+// AuditAction действие пользователя в журнале аудита
+type AuditAction string
+
+const (
+	AuditActionLogin              AuditAction = "LOGIN"
+	AuditActionLoginFailed        AuditAction = "LOGIN_FAILED"
+	AuditActionCompanyView        AuditAction = "COMPANY_VIEW"
+	AuditActionEntrepreneurView   AuditAction = "ENTREPRENEUR_VIEW"
+	AuditActionExport             AuditAction = "EXPORT"
+	AuditActionSubscriptionCreate AuditAction = "SUBSCRIPTION_CREATE"
+	AuditActionSubscriptionUpdate AuditAction = "SUBSCRIPTION_UPDATE"
+	AuditActionSubscriptionDelete AuditAction = "SUBSCRIPTION_DELETE"
+	AuditActionFavoriteCreate     AuditAction = "FAVORITE_CREATE"
+	AuditActionFavoriteUpdate     AuditAction = "FAVORITE_UPDATE"
+	AuditActionFavoriteDelete     AuditAction = "FAVORITE_DELETE"
+	AuditActionAPIKeyUse          AuditAction = "API_KEY_USE"
+	// Удаление учетной записи: запрос, отмена и фактическое удаление
+	AuditActionAccountDeletionRequest AuditAction = "ACCOUNT_DELETION_REQUEST"
+	AuditActionAccountDeletionCancel  AuditAction = "ACCOUNT_DELETION_CANCEL"
+	AuditActionAccountDelete          AuditAction = "ACCOUNT_DELETE"
+)
+
+var AllAuditAction = []AuditAction{
+	AuditActionLogin,
+	AuditActionLoginFailed,
+	AuditActionCompanyView,
+	AuditActionEntrepreneurView,
+	AuditActionExport,
+	AuditActionSubscriptionCreate,
+	AuditActionSubscriptionUpdate,
+	AuditActionSubscriptionDelete,
+	AuditActionFavoriteCreate,
+	AuditActionFavoriteUpdate,
+	AuditActionFavoriteDelete,
+	AuditActionAPIKeyUse,
+	AuditActionAccountDeletionRequest,
+	AuditActionAccountDeletionCancel,
+	AuditActionAccountDelete,
+}
+
+func (e AuditAction) IsValid() bool {
+	for _, action := range AllAuditAction {
+		if e == action {
+			return true
+		}
+	}
+	return false
+}
+
+func (e AuditAction) String() string {
+	return string(e)
+}
+
+// AuditLogEntry запись журнала аудита
+type AuditLogEntry struct {
+	ID         string      `json:"id"`
+	Time       time.Time   `json:"time"`
+	Action     AuditAction `json:"action"`
+	Success    bool        `json:"success"`
+	UserID     *string     `json:"userId,omitempty"`
+	UserEmail  *string     `json:"userEmail,omitempty"`
+	APIKeyID   *string     `json:"apiKeyId,omitempty"`
+	IPAddress  *string     `json:"ipAddress,omitempty"`
+	UserAgent  *string     `json:"userAgent,omitempty"`
+	EntityType *string     `json:"entityType,omitempty"`
+	EntityID   *string     `json:"entityId,omitempty"`
+	// Details дополнительные данные события (JSON)
+	Details *string `json:"details,omitempty"`
+}
+
+// AuditLogFilter фильтр журнала аудита
+type AuditLogFilter struct {
+	UserID   *string      `json:"userId,omitempty"`
+	Action   *AuditAction `json:"action,omitempty"`
+	DateFrom *Date        `json:"dateFrom,omitempty"`
+	DateTo   *Date        `json:"dateTo,omitempty"`
+}
+
+// AuditLogList страница журнала аудита
+type AuditLogList struct {
+	Items []*AuditLogEntry `json:"items"`
+	Total int              `json:"total"`
+}
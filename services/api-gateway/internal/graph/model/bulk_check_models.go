@@ -0,0 +1,57 @@
+package model
+
+import (
+	"time"
+)
+
+// BulkCheckStatus статус задания пакетной проверки
+type BulkCheckStatus string
+
+const (
+	BulkCheckStatusPending   BulkCheckStatus = "pending"
+	BulkCheckStatusRunning   BulkCheckStatus = "running"
+	BulkCheckStatusCompleted BulkCheckStatus = "completed"
+	BulkCheckStatusFailed    BulkCheckStatus = "failed"
+)
+
+// BulkCheckJob задание пакетной проверки контрагентов
+type BulkCheckJob struct {
+	ID            string          `json:"id"`
+	UserID        string          `json:"userId"`
+	Status        BulkCheckStatus `json:"status"`
+	FileName      *string         `json:"fileName,omitempty"`
+	OutputFormat  string          `json:"outputFormat"`
+	Identifiers   []string        `json:"-"`
+	TotalRows     int             `json:"totalRows"`
+	ProcessedRows int             `json:"processedRows"`
+	FoundRows     int             `json:"foundRows"`
+	ErrorMessage  *string         `json:"errorMessage,omitempty"`
+	CreatedAt     time.Time       `json:"createdAt"`
+	StartedAt     *time.Time      `json:"startedAt,omitempty"`
+	FinishedAt    *time.Time      `json:"finishedAt,omitempty"`
+}
+
+// BulkCheckResult результат проверки одного контрагента
+type BulkCheckResult struct {
+	Input               string     `json:"input"`
+	EntityType          string     `json:"entityType,omitempty"`
+	Found               bool       `json:"found"`
+	Ogrn                string     `json:"ogrn,omitempty"`
+	Inn                 string     `json:"inn,omitempty"`
+	Name                string     `json:"name,omitempty"`
+	Status              string     `json:"status,omitempty"`
+	StatusCode          string     `json:"statusCode,omitempty"`
+	IsLiquidating       bool       `json:"isLiquidating"`
+	IsBankrupt          bool       `json:"isBankrupt"`
+	IsReorganizing      bool       `json:"isReorganizing"`
+	Director            string     `json:"director,omitempty"`
+	RegistrationDate    *time.Time `json:"registrationDate,omitempty"`
+	RegistrationAgeDays int        `json:"registrationAgeDays"`
+	MassAddress         bool       `json:"massAddress"`
+	SameAddressCount    int        `json:"sameAddressCount"`
+	LatestChanges       []string   `json:"latestChanges,omitempty"`
+	RiskScore           *int       `json:"riskScore,omitempty"`
+	RiskLevel           string     `json:"riskLevel,omitempty"`
+	RiskFactors         []string   `json:"riskFactors,omitempty"`
+	Error               string     `json:"error,omitempty"`
+}
@@ -0,0 +1,60 @@
+package model
+
+// FounderChangeKind вид изменения учредителя между версиями
+type FounderChangeKind string
+
+const (
+	FounderChangeKindAdded        FounderChangeKind = "ADDED"
+	FounderChangeKindRemoved      FounderChangeKind = "REMOVED"
+	FounderChangeKindShareChanged FounderChangeKind = "SHARE_CHANGED"
+)
+
+// CompanyDiff сравнение двух сохраненных версий карточки компании
+type CompanyDiff struct {
+	Ogrn string `json:"ogrn"`
+	// From/To версии, фактически взятые для дат from и to
+	From       *VersionInfo `json:"from"`
+	To         *VersionInfo `json:"to"`
+	HasChanges bool         `json:"hasChanges"`
+	// Changes изменения скалярных полей (статус, руководитель, адрес, капитал, реквизиты)
+	Changes    []*DiffChange  `json:"changes"`
+	Founders   []*FounderDiff `json:"founders"`
+	Activities *SetDiff       `json:"activities"`
+	Licenses   *SetDiff       `json:"licenses"`
+	Branches   *SetDiff       `json:"branches"`
+}
+
+// DiffChange изменение одного поля карточки
+type DiffChange struct {
+	Field         string  `json:"field"`
+	ChangeType    string  `json:"changeType"`
+	OldValue      *string `json:"oldValue"`
+	NewValue      *string `json:"newValue"`
+	IsSignificant bool    `json:"isSignificant"`
+	Description   string  `json:"description"`
+}
+
+// FounderDiff изменение в составе учредителей
+type FounderDiff struct {
+	Name            string            `json:"name"`
+	Inn             *string           `json:"inn"`
+	Ogrn            *string           `json:"ogrn"`
+	Change          FounderChangeKind `json:"change"`
+	OldSharePercent *float64          `json:"oldSharePercent"`
+	NewSharePercent *float64          `json:"newSharePercent"`
+	ShareDelta      *float64          `json:"shareDelta"`
+	IsSignificant   bool              `json:"isSignificant"`
+}
+
+// SetDiff добавленные и удаленные элементы множества (ОКВЭД, лицензии, филиалы)
+type SetDiff struct {
+	Added   []*DiffItem `json:"added"`
+	Removed []*DiffItem `json:"removed"`
+}
+
+// DiffItem элемент множества в сравнении версий
+type DiffItem struct {
+	Key           string  `json:"key"`
+	Name          *string `json:"name"`
+	IsSignificant bool    `json:"isSignificant"`
+}
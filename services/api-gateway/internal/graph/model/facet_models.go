@@ -0,0 +1,37 @@
+package model
+
+// FacetBucket значение фасета с числом найденных записей. From/To - границы
+// диапазона для фасетов-диапазонов (уставный капитал)
+type FacetBucket struct {
+	Key   string   `json:"key"`
+	Label *string  `json:"label,omitempty"`
+	Count int      `json:"count"`
+	From  *float64 `json:"from,omitempty"`
+	To    *float64 `json:"to,omitempty"`
+}
+
+// SearchFacets фасеты поиска. Счетчики каждого фасета учитывают все активные
+// фильтры, кроме фильтра самого фасета. Opf и Capital есть только у компаний
+type SearchFacets struct {
+	Status           []*FacetBucket `json:"status"`
+	Region           []*FacetBucket `json:"region"`
+	OkvedSection     []*FacetBucket `json:"okvedSection"`
+	OkvedMain        []*FacetBucket `json:"okvedMain"`
+	Opf              []*FacetBucket `json:"opf"`
+	RegistrationYear []*FacetBucket `json:"registrationYear"`
+	Capital          []*FacetBucket `json:"capital"`
+}
+
+// CompanySearchResult результат поиска компаний с фасетами
+type CompanySearchResult struct {
+	Companies  []*Company    `json:"companies"`
+	TotalCount int           `json:"totalCount"`
+	Facets     *SearchFacets `json:"facets"`
+}
+
+// EntrepreneurSearchResult результат поиска ИП с фасетами
+type EntrepreneurSearchResult struct {
+	Entrepreneurs []*Entrepreneur `json:"entrepreneurs"`
+	TotalCount    int             `json:"totalCount"`
+	Facets        *SearchFacets   `json:"facets"`
+}
@@ -4,26 +4,95 @@ import (
 	"time"
 )
 
-// Favorite представляет избранную сущность пользователя
+// Favorite представляет избранную сущность пользователя или рабочего
+// пространства (WorkspaceID); для записи пространства UserID - автор
 type Favorite struct {
-	ID         string     `json:"id"`
-	UserID     string     `json:"userId"`
-	User       *User      `json:"user"`
-	EntityType EntityType `json:"entityType"`
-	EntityID   string     `json:"entityId"`
-	EntityName string     `json:"entityName"`
-	Notes      *string    `json:"notes,omitempty"`
-	CreatedAt  time.Time  `json:"createdAt"`
+	ID          string     `json:"id"`
+	UserID      string     `json:"userId"`
+	User        *User      `json:"user"`
+	WorkspaceID *string    `json:"workspaceId,omitempty"`
+	EntityType  EntityType `json:"entityType"`
+	EntityID    string     `json:"entityId"`
+	EntityName  string     `json:"entityName"`
+	Notes       *string    `json:"notes,omitempty"`
+	Folder      *string    `json:"folder,omitempty"`
+	Tags        []string   `json:"tags"`
+	CreatedAt   time.Time  `json:"createdAt"`
+}
+
+// FavoriteFolder папка личного избранного
+type FavoriteFolder struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// FavoriteImportStatus результат добавления одного идентификатора
+type FavoriteImportStatus string
+
+const (
+	FavoriteImportStatusAdded    FavoriteImportStatus = "ADDED"
+	FavoriteImportStatusExists   FavoriteImportStatus = "EXISTS"
+	FavoriteImportStatusNotFound FavoriteImportStatus = "NOT_FOUND"
+	FavoriteImportStatusInvalid  FavoriteImportStatus = "INVALID"
+	FavoriteImportStatusFailed   FavoriteImportStatus = "FAILED"
+)
+
+func (e FavoriteImportStatus) IsValid() bool {
+	switch e {
+	case FavoriteImportStatusAdded, FavoriteImportStatusExists, FavoriteImportStatusNotFound,
+		FavoriteImportStatusInvalid, FavoriteImportStatusFailed:
+		return true
+	}
+	return false
+}
+
+func (e FavoriteImportStatus) String() string {
+	return string(e)
+}
+
+// FavoriteImportItem результат по одному идентификатору из списка
+type FavoriteImportItem struct {
+	Input      string               `json:"input"`
+	Status     FavoriteImportStatus `json:"status"`
+	EntityType *EntityType          `json:"entityType,omitempty"`
+	EntityID   *string              `json:"entityId,omitempty"`
+	EntityName *string              `json:"entityName,omitempty"`
+	FavoriteID *string              `json:"favoriteId,omitempty"`
+	Subscribed bool                 `json:"subscribed"`
+	Error      *string              `json:"error,omitempty"`
+}
+
+// FavoriteImportResult итог пакетного добавления в избранное
+type FavoriteImportResult struct {
+	Total      int                   `json:"total"`
+	Added      int                   `json:"added"`
+	Existing   int                   `json:"existing"`
+	NotFound   int                   `json:"notFound"`
+	Invalid    int                   `json:"invalid"`
+	Failed     int                   `json:"failed"`
+	Subscribed int                   `json:"subscribed"`
+	Items      []*FavoriteImportItem `json:"items"`
 }
 
 // Input типы
 
 // CreateFavoriteInput входные данные для создания избранного
 type CreateFavoriteInput struct {
-	EntityType EntityType `json:"entityType"`
-	EntityID   string     `json:"entityId"`
-	EntityName string     `json:"entityName"`
-	Notes      *string    `json:"notes,omitempty"`
+	EntityType  EntityType `json:"entityType"`
+	EntityID    string     `json:"entityId"`
+	EntityName  string     `json:"entityName"`
+	Notes       *string    `json:"notes,omitempty"`
+	WorkspaceID *string    `json:"workspaceId,omitempty"`
+	Folder      *string    `json:"folder,omitempty"`
+	Tags        []string   `json:"tags,omitempty"`
+}
+
+// AddFavoritesInput пакетное добавление в личное избранное по ИНН/ОГРН/ОГРНИП
+type AddFavoritesInput struct {
+	Identifiers []string `json:"identifiers"`
+	Folder      *string  `json:"folder,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	Subscribe   *bool    `json:"subscribe,omitempty"`
 }
 
 // UpdateFavoriteNotesInput входные данные для обновления заметок
@@ -175,6 +175,8 @@ type Person struct {
 	Inn          *string `json:"inn"`
 	Position     *string `json:"position"`
 	PositionCode *string `json:"positionCode"`
+	// MassDirector заполняется только для руководителя компании по запросу поля
+	MassDirector *MassDirector `json:"massDirector,omitempty"`
 }
 
 // Activity вид деятельности
@@ -296,6 +298,8 @@ type Company struct {
 	VersionDate       Date          `json:"versionDate"`
 	CreatedAt         time.Time     `json:"createdAt"`
 	UpdatedAt         time.Time     `json:"updatedAt"`
+	// VersionInfo заполняется только для карточки на дату (asOf)
+	VersionInfo       *VersionInfo  `json:"versionInfo,omitempty"`
 }
 
 // Entrepreneur индивидуальный предприниматель
@@ -334,6 +338,8 @@ type Entrepreneur struct {
 	VersionDate            Date         `json:"versionDate"`
 	CreatedAt              time.Time    `json:"createdAt"`
 	UpdatedAt              time.Time    `json:"updatedAt"`
+	// VersionInfo заполняется только для карточки на дату (asOf)
+	VersionInfo            *VersionInfo `json:"versionInfo,omitempty"`
 }
 
 // Pagination пагинация
@@ -389,6 +395,7 @@ type CompanyFilter struct {
 	TerminatedBefore *Date           `json:"terminatedBefore"`
 	CapitalMin       *float64        `json:"capitalMin"`
 	CapitalMax       *float64        `json:"capitalMax"`
+	OpfCode          *string         `json:"opfCode"`
 	IsBankrupt       *bool           `json:"isBankrupt"`
 	IsLiquidating    *bool           `json:"isLiquidating"`
 	HasDirector      *bool           `json:"hasDirector"`
@@ -496,6 +503,8 @@ type Statistics struct {
 	RegisteredToday     int                   `json:"registeredToday"`
 	RegisteredThisMonth int                   `json:"registeredThisMonth"`
 	RegisteredThisYear  int                   `json:"registeredThisYear"`
+	// RegisteredInPeriod регистрации за период фильтра (nil, если период не задан)
+	RegisteredInPeriod  *int                  `json:"registeredInPeriod"`
 	ByRegion            []*RegionStatistics   `json:"byRegion"`
 	ByActivity          []*ActivityStatistics `json:"byActivity"`
 }
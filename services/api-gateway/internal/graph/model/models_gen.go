@@ -11,9 +11,11 @@ import (
 
 // Ответ при успешной аутентификации
 type AuthResponse struct {
-	User      *User     `json:"user"`
-	Token     string    `json:"token"`
-	ExpiresAt time.Time `json:"expiresAt"`
+	User             *User     `json:"user"`
+	Token            string    `json:"token"`
+	ExpiresAt        time.Time `json:"expiresAt"`
+	RefreshToken     string    `json:"refreshToken"`
+	RefreshExpiresAt time.Time `json:"refreshExpiresAt"`
 }
 
 // Сортировка предпринимателей
@@ -42,17 +44,30 @@ type RegisterInput struct {
 	LastName  string `json:"lastName"`
 }
 
+// Сессия пользователя (устройство, с которого выполнен вход)
+type Session struct {
+	ID         string    `json:"id"`
+	UserAgent  *string   `json:"userAgent,omitempty"`
+	IPAddress  *string   `json:"ipAddress,omitempty"`
+	CreatedAt  time.Time `json:"createdAt"`
+	LastUsedAt time.Time `json:"lastUsedAt"`
+	ExpiresAt  time.Time `json:"expiresAt"`
+	Current    bool      `json:"current"`
+}
+
 // Пользователь системы
 type User struct {
-	ID            string     `json:"id"`
-	Email         string     `json:"email"`
-	FirstName     string     `json:"firstName"`
-	LastName      string     `json:"lastName"`
-	IsActive      bool       `json:"isActive"`
-	EmailVerified bool       `json:"emailVerified"`
-	CreatedAt     time.Time  `json:"createdAt"`
-	UpdatedAt     time.Time  `json:"updatedAt"`
-	LastLoginAt   *time.Time `json:"lastLoginAt,omitempty"`
+	ID                  string     `json:"id"`
+	Email               string     `json:"email"`
+	FirstName           string     `json:"firstName"`
+	LastName            string     `json:"lastName"`
+	Role                Role       `json:"role"`
+	IsActive            bool       `json:"isActive"`
+	EmailVerified       bool       `json:"emailVerified"`
+	CreatedAt           time.Time  `json:"createdAt"`
+	UpdatedAt           time.Time  `json:"updatedAt"`
+	LastLoginAt         *time.Time `json:"lastLoginAt,omitempty"`
+	DeletionScheduledAt *time.Time `json:"deletionScheduledAt,omitempty"`
 }
 
 // Точка временного ряда (регистрации/ликвидации)
@@ -0,0 +1,88 @@
+package model
+
+// OkvedLevel уровень иерархии ОКВЭД2
+type OkvedLevel string
+
+const (
+	OkvedLevelSection  OkvedLevel = "SECTION"
+	OkvedLevelClass    OkvedLevel = "CLASS"
+	OkvedLevelSubclass OkvedLevel = "SUBCLASS"
+	OkvedLevelGroup    OkvedLevel = "GROUP"
+	OkvedLevelSubgroup OkvedLevel = "SUBGROUP"
+	OkvedLevelKind     OkvedLevel = "KIND"
+)
+
+func (e OkvedLevel) IsValid() bool {
+	switch e {
+	case OkvedLevelSection, OkvedLevelClass, OkvedLevelSubclass, OkvedLevelGroup, OkvedLevelSubgroup, OkvedLevelKind:
+		return true
+	}
+	return false
+}
+
+// CodeLength длина кода на уровне (62 - 2, 62.0 - 4, 62.01 - 5, 62.02.1 - 7, 62.02.11 - 8);
+// 0 для раздела, код которого - буква
+func (e OkvedLevel) CodeLength() int {
+	switch e {
+	case OkvedLevelClass:
+		return 2
+	case OkvedLevelSubclass:
+		return 4
+	case OkvedLevelGroup:
+		return 5
+	case OkvedLevelSubgroup:
+		return 7
+	case OkvedLevelKind:
+		return 8
+	}
+	return 0
+}
+
+// OkvedSections разделы ОКВЭД2 и диапазоны классов (первые две цифры кода)
+var OkvedSections = []struct {
+	Code     string
+	Name     string
+	From, To int
+}{
+	{"A", "Сельское, лесное хозяйство, охота, рыболовство и рыбоводство", 1, 3},
+	{"B", "Добыча полезных ископаемых", 5, 9},
+	{"C", "Обрабатывающие производства", 10, 33},
+	{"D", "Обеспечение электрической энергией, газом и паром; кондиционирование воздуха", 35, 35},
+	{"E", "Водоснабжение; водоотведение, организация сбора и утилизации отходов", 36, 39},
+	{"F", "Строительство", 41, 43},
+	{"G", "Торговля оптовая и розничная; ремонт автотранспортных средств и мотоциклов", 45, 47},
+	{"H", "Транспортировка и хранение", 49, 53},
+	{"I", "Деятельность гостиниц и предприятий общественного питания", 55, 56},
+	{"J", "Деятельность в области информации и связи", 58, 63},
+	{"K", "Деятельность финансовая и страховая", 64, 66},
+	{"L", "Деятельность по операциям с недвижимым имуществом", 68, 68},
+	{"M", "Деятельность профессиональная, научная и техническая", 69, 75},
+	{"N", "Деятельность административная и сопутствующие дополнительные услуги", 77, 82},
+	{"O", "Государственное управление и обеспечение военной безопасности; социальное обеспечение", 84, 84},
+	{"P", "Образование", 85, 85},
+	{"Q", "Деятельность в области здравоохранения и социальных услуг", 86, 88},
+	{"R", "Деятельность в области культуры, спорта, организации досуга и развлечений", 90, 93},
+	{"S", "Предоставление прочих видов услуг", 94, 96},
+	{"T", "Деятельность домашних хозяйств как работодателей", 97, 98},
+	{"U", "Деятельность экстерриториальных организаций и органов", 99, 99},
+}
+
+// OkvedSectionName название раздела ОКВЭД по букве; пусто, если это не раздел
+func OkvedSectionName(code string) string {
+	for _, s := range OkvedSections {
+		if s.Code == code {
+			return s.Name
+		}
+	}
+	return ""
+}
+
+// OkvedNode узел справочника ОКВЭД2
+type OkvedNode struct {
+	Code        string     `json:"code"`
+	Name        string     `json:"name"`
+	Level       OkvedLevel `json:"level"`
+	ParentCode  *string    `json:"parentCode,omitempty"`
+	Section     string     `json:"section"`
+	HasChildren bool       `json:"hasChildren"`
+}
@@ -0,0 +1,82 @@
+package model
+
+// PersonRoleType роль физического лица в компании
+type PersonRoleType string
+
+const (
+	PersonRoleTypeDirector PersonRoleType = "DIRECTOR"
+	PersonRoleTypeFounder  PersonRoleType = "FOUNDER"
+)
+
+// Источники исторических ролей
+const (
+	PersonRoleSourceVersions  = "versions"
+	PersonRoleSourceOwnership = "ownership_graph"
+)
+
+// PersonProfile все роли физического лица по ИНН в реестрах
+type PersonProfile struct {
+	Inn string `json:"inn"`
+	// Name ФИО по последним сведениям (nil, если лицо нигде не найдено)
+	Name          *string               `json:"name"`
+	Directorships []*PersonDirectorship `json:"directorships"`
+	Foundings     []*PersonFounding     `json:"foundings"`
+	Entrepreneur  *Entrepreneur         `json:"entrepreneur"`
+	// HistoricalRoles роли с периодами по сохраненным версиям карточек и графу владения
+	HistoricalRoles []*PersonRole `json:"historicalRoles"`
+}
+
+// PersonDirectorship компания, в которой лицо является руководителем
+type PersonDirectorship struct {
+	Ogrn         string       `json:"ogrn"`
+	Inn          *string      `json:"inn"`
+	CompanyName  string       `json:"companyName"`
+	Status       EntityStatus `json:"status"`
+	Position     *string      `json:"position"`
+	PositionCode *string      `json:"positionCode"`
+}
+
+// PersonFounding компания, в которой лицо является учредителем
+type PersonFounding struct {
+	Ogrn              string       `json:"ogrn"`
+	Inn               *string      `json:"inn"`
+	CompanyName       string       `json:"companyName"`
+	Status            EntityStatus `json:"status"`
+	SharePercent      *float64     `json:"sharePercent"`
+	ShareNominalValue *float64     `json:"shareNominalValue"`
+}
+
+// PersonRole роль лица в компании за период
+type PersonRole struct {
+	Role         PersonRoleType `json:"role"`
+	Ogrn         string         `json:"ogrn"`
+	CompanyName  string         `json:"companyName"`
+	Position     *string        `json:"position"`
+	SharePercent *float64       `json:"sharePercent"`
+	StartDate    *Date          `json:"startDate"`
+	// EndDate nil, если роль действует по последней версии
+	EndDate   *Date  `json:"endDate"`
+	IsCurrent bool   `json:"isCurrent"`
+	Source    string `json:"source"`
+}
+
+// PersonCandidate кандидат при поиске лица по ФИО, различаемый по ИНН
+type PersonCandidate struct {
+	Inn             string   `json:"inn"`
+	Name            string   `json:"name"`
+	DirectorCount   int      `json:"directorCount"`
+	FounderCount    int      `json:"founderCount"`
+	IsEntrepreneur  bool     `json:"isEntrepreneur"`
+	RegionCodes     []string `json:"regionCodes"`
+	SampleCompanies []string `json:"sampleCompanies"`
+}
+
+// PersonRolePoint состояние роли лица в одной версии карточки компании
+type PersonRolePoint struct {
+	Ogrn         string
+	CompanyName  string
+	Date         Date
+	Present      bool
+	Position     *string
+	SharePercent *float64
+}
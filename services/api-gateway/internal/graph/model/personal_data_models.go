@@ -0,0 +1,12 @@
+package model
+
+import "time"
+
+// PersonalDataExport выгрузка данных, которые система хранит о пользователе
+type PersonalDataExport struct {
+	GeneratedAt time.Time `json:"generatedAt"`
+	// Data выгрузка одним JSON документом
+	Data string `json:"data"`
+	// DownloadURL адрес той же выгрузки ZIP архивом
+	DownloadURL string `json:"downloadUrl"`
+}
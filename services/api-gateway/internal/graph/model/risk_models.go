@@ -0,0 +1,74 @@
+package model
+
+import "time"
+
+// RiskLevel уровень риска контрагента
+type RiskLevel string
+
+const (
+	RiskLevelLow    RiskLevel = "LOW"
+	RiskLevelMedium RiskLevel = "MEDIUM"
+	RiskLevelHigh   RiskLevel = "HIGH"
+)
+
+// RiskAssessment оценка риска контрагента с объяснением
+type RiskAssessment struct {
+	// Score сумма весов сработавших факторов, 0..100
+	Score   int           `json:"score"`
+	Level   RiskLevel     `json:"level"`
+	Factors []*RiskFactor `json:"factors"`
+	// FeaturesUpdatedAt время расчета признаков в ClickHouse (nil, если признаки еще не рассчитаны)
+	FeaturesUpdatedAt *time.Time `json:"featuresUpdatedAt"`
+}
+
+// RiskFactor сработавший фактор риска
+type RiskFactor struct {
+	Code        string  `json:"code"`
+	Description string  `json:"description"`
+	Weight      int     `json:"weight"`
+	Value       *string `json:"value"`
+}
+
+// CompanyRiskAssessment оценка риска в пакетном запросе riskAssessments
+type CompanyRiskAssessment struct {
+	Ogrn string `json:"ogrn"`
+	// Found false, если компания с таким ОГРН не найдена
+	Found      bool            `json:"found"`
+	Assessment *RiskAssessment `json:"assessment"`
+}
+
+// CompanyRiskFeatures предрассчитанные признаки компании из egrul.company_risk_features
+type CompanyRiskFeatures struct {
+	Ogrn               string
+	AddressCompanies   int
+	DirectorCompanies  int
+	DirectorChanges12m int
+	AddressChanges12m  int
+	ForeignFounders    int
+	UpdatedAt          time.Time
+}
+
+// MassAddress агрегат адреса регистрации из egrul.mass_addresses
+type MassAddress struct {
+	// AddressKey fias:<ФИАС> или addr:<нормализованный адрес>
+	AddressKey     string  `json:"addressKey"`
+	FiasID         *string `json:"fiasId"`
+	FullAddress    string  `json:"fullAddress"`
+	RegionCode     *string `json:"regionCode"`
+	CompaniesCount int     `json:"companiesCount"`
+	ActiveCount    int     `json:"activeCount"`
+	// IsMass количество компаний достигло порога risk.mass_address_threshold
+	IsMass    bool      `json:"isMass"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// MassDirector агрегат руководителя из egrul.mass_directors
+type MassDirector struct {
+	Inn            string `json:"inn"`
+	Name           string `json:"name"`
+	CompaniesCount int    `json:"companiesCount"`
+	ActiveCount    int    `json:"activeCount"`
+	// IsMass количество компаний достигло порога risk.mass_director_threshold
+	IsMass    bool      `json:"isMass"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
@@ -0,0 +1,7 @@
+package model
+
+// OidcLoginStart начало входа через SSO
+type OidcLoginStart struct {
+	AuthorizationURL string `json:"authorizationUrl"`
+	State            string `json:"state"`
+}
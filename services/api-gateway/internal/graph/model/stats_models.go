@@ -0,0 +1,194 @@
+package model
+
+import "time"
+
+// TopOwnerSortField поле сортировки топа владельцев
+type TopOwnerSortField string
+
+const (
+	TopOwnerSortFieldCompaniesCount TopOwnerSortField = "COMPANIES_COUNT"
+	TopOwnerSortFieldAvgShare       TopOwnerSortField = "AVG_SHARE"
+)
+
+// IsValid проверяет допустимость значения
+func (e TopOwnerSortField) IsValid() bool {
+	switch e {
+	case TopOwnerSortFieldCompaniesCount, TopOwnerSortFieldAvgShare:
+		return true
+	}
+	return false
+}
+
+// TopOwner владелец долей с количеством компаний, в которых он участвует
+type TopOwner struct {
+	OwnerType       FounderType `json:"ownerType"`
+	Ogrn            *string     `json:"ogrn"`
+	Inn             *string     `json:"inn"`
+	Name            string      `json:"name"`
+	CompaniesCount  int         `json:"companiesCount"`
+	AvgSharePercent *float64    `json:"avgSharePercent"`
+}
+
+// OwnershipTypeStatistics распределение долей по типам учредителей
+type OwnershipTypeStatistics struct {
+	OwnerType       FounderType `json:"ownerType"`
+	SharesCount     int         `json:"sharesCount"`
+	CompaniesCount  int         `json:"companiesCount"`
+	AvgSharePercent *float64    `json:"avgSharePercent"`
+}
+
+// LegalFormStatistics распределение компаний по организационно-правовым формам
+type LegalFormStatistics struct {
+	OpfCode         string   `json:"opfCode"`
+	OpfName         string   `json:"opfName"`
+	CompaniesCount  int      `json:"companiesCount"`
+	ActiveCount     int      `json:"activeCount"`
+	LiquidatedCount int      `json:"liquidatedCount"`
+	TotalCapital    *float64 `json:"totalCapital"`
+	AvgCapital      *float64 `json:"avgCapital"`
+}
+
+// ChangesByMonthPoint количество записей ГРН за месяц по причине внесения
+type ChangesByMonthPoint struct {
+	Month             string     `json:"month"`
+	EntityType        EntityType `json:"entityType"`
+	ReasonCode        string     `json:"reasonCode"`
+	ReasonDescription string     `json:"reasonDescription"`
+	Count             int        `json:"count"`
+}
+
+// ChangesByDayPoint число изменений одного типа за день по данным change-detection
+type ChangesByDayPoint struct {
+	Date          string     `json:"date"`
+	EntityType    EntityType `json:"entityType"`
+	ChangeType    string     `json:"changeType"`
+	ChangesCount  int        `json:"changesCount"`
+	AffectedCount int        `json:"affectedCount"`
+}
+
+// MostChangedCompany компания с наибольшим числом изменений за месяц
+type MostChangedCompany struct {
+	Month              string `json:"month"`
+	Ogrn               string `json:"ogrn"`
+	CompanyName        string `json:"companyName"`
+	TotalChanges       int    `json:"totalChanges"`
+	SignificantChanges int    `json:"significantChanges"`
+}
+
+// RegionChangesStatistics значимые изменения одного типа в регионе за период
+type RegionChangesStatistics struct {
+	RegionCode    string     `json:"regionCode"`
+	EntityType    EntityType `json:"entityType"`
+	ChangeType    string     `json:"changeType"`
+	ChangesCount  int        `json:"changesCount"`
+	AffectedCount int        `json:"affectedCount"`
+}
+
+// TimeSeriesMetric показатель временного ряда
+type TimeSeriesMetric string
+
+const (
+	TimeSeriesMetricRegistrations TimeSeriesMetric = "REGISTRATIONS"
+	TimeSeriesMetricTerminations  TimeSeriesMetric = "TERMINATIONS"
+	TimeSeriesMetricNetGrowth     TimeSeriesMetric = "NET_GROWTH"
+)
+
+// IsValid проверяет допустимость значения
+func (e TimeSeriesMetric) IsValid() bool {
+	switch e {
+	case TimeSeriesMetricRegistrations, TimeSeriesMetricTerminations, TimeSeriesMetricNetGrowth:
+		return true
+	}
+	return false
+}
+
+// TimeGranularity шаг временного ряда
+type TimeGranularity string
+
+const (
+	TimeGranularityDay   TimeGranularity = "DAY"
+	TimeGranularityWeek  TimeGranularity = "WEEK"
+	TimeGranularityMonth TimeGranularity = "MONTH"
+	TimeGranularityYear  TimeGranularity = "YEAR"
+)
+
+// IsValid проверяет допустимость значения
+func (e TimeGranularity) IsValid() bool {
+	switch e {
+	case TimeGranularityDay, TimeGranularityWeek, TimeGranularityMonth, TimeGranularityYear:
+		return true
+	}
+	return false
+}
+
+// TimeSeriesGroupBy разрез временного ряда
+type TimeSeriesGroupBy string
+
+const (
+	TimeSeriesGroupByRegion       TimeSeriesGroupBy = "REGION"
+	TimeSeriesGroupByOkvedSection TimeSeriesGroupBy = "OKVED_SECTION"
+	TimeSeriesGroupByOpf          TimeSeriesGroupBy = "OPF"
+)
+
+// IsValid проверяет допустимость значения
+func (e TimeSeriesGroupBy) IsValid() bool {
+	switch e {
+	case TimeSeriesGroupByRegion, TimeSeriesGroupByOkvedSection, TimeSeriesGroupByOpf:
+		return true
+	}
+	return false
+}
+
+// GroupedTimeSeriesPoint значение показателя за период в группе разреза
+type GroupedTimeSeriesPoint struct {
+	Period string `json:"period"`
+	// GroupKey код региона, раздела ОКВЭД или ОПФ (nil без разреза)
+	GroupKey  *string `json:"groupKey"`
+	GroupName *string `json:"groupName"`
+	Value     int     `json:"value"`
+}
+
+// CohortGranularity шаг когорт регистрации
+type CohortGranularity string
+
+const (
+	CohortGranularityYear    CohortGranularity = "YEAR"
+	CohortGranularityQuarter CohortGranularity = "QUARTER"
+)
+
+// IsValid проверяет допустимость значения
+func (e CohortGranularity) IsValid() bool {
+	switch e {
+	case CohortGranularityYear, CohortGranularityQuarter:
+		return true
+	}
+	return false
+}
+
+// SurvivalHorizons сроки (в годах), для которых считается доля выживших
+var SurvivalHorizons = []int{1, 2, 3, 5, 10}
+
+// SurvivalCohort когорта зарегистрированных за период и доли выживших
+type SurvivalCohort struct {
+	Cohort        string          `json:"cohort"`
+	EntitiesCount int             `json:"entitiesCount"`
+	ActiveCount   int             `json:"activeCount"`
+	Survival      []*SurvivalRate `json:"survival"`
+}
+
+// SurvivalRate доля субъектов когорты, действующих спустя Years лет после регистрации
+type SurvivalRate struct {
+	Years int `json:"years"`
+	// SurvivedCount и Share nil, если с конца когорты прошло меньше Years лет
+	SurvivedCount *int     `json:"survivedCount"`
+	Share         *float64 `json:"share"`
+}
+
+// SurvivalCohortCounts счетчики когорты из хранилища
+type SurvivalCohortCounts struct {
+	CohortStart time.Time
+	Total       int
+	Active      int
+	// Survived число выживших по срокам SurvivalHorizons (в том же порядке)
+	Survived []int
+}
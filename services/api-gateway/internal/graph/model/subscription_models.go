@@ -13,6 +13,7 @@ type EntitySubscription struct {
 	UserID               string                 `json:"userId"`
 	UserEmail            string                 `json:"-"` // Внутреннее поле для совместимости с БД (не возвращается в API)
 	User                 *User                  `json:"user"`
+	WorkspaceID          *string                `json:"workspaceId,omitempty"`
 	EntityType           EntityType             `json:"entityType"`
 	EntityID             string                 `json:"entityId"`
 	EntityName           string                 `json:"entityName"`
@@ -82,6 +83,7 @@ type CreateSubscriptionInput struct {
 	EntityName           string                    `json:"entityName"`
 	ChangeFilters        *ChangeFiltersInput       `json:"changeFilters,omitempty"`
 	NotificationChannels *NotificationChannelsInput `json:"notificationChannels,omitempty"`
+	WorkspaceID          *string                    `json:"workspaceId,omitempty"`
 }
 
 // ChangeFiltersInput входные данные для фильтров изменений
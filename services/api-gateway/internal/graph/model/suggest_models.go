@@ -0,0 +1,12 @@
+package model
+
+// Suggestion подсказка поисковой строки: компания или ИП
+type Suggestion struct {
+	EntityType EntityType   `json:"entityType"`
+	ID         string       `json:"id"` // ОГРН или ОГРНИП
+	Inn        string       `json:"inn"`
+	Name       string       `json:"name"`
+	Status     EntityStatus `json:"status"`
+	RegionCode *string      `json:"regionCode,omitempty"`
+	Region     *string      `json:"region,omitempty"`
+}
@@ -0,0 +1,14 @@
+package model
+
+// VersionInfo сведения о версии выписки, из которой восстановлена карточка на дату
+type VersionInfo struct {
+	// AsOf запрошенная дата
+	AsOf Date `json:"asOf"`
+	// ExtractDate дата выписки (ДатаВып), из которой взята версия
+	ExtractDate *Date `json:"extractDate"`
+	// VersionDate дата загрузки версии в систему
+	VersionDate Date    `json:"versionDate"`
+	LastGrn     *string `json:"lastGrn"`
+	LastGrnDate *Date   `json:"lastGrnDate"`
+	SourceFile  *string `json:"sourceFile"`
+}
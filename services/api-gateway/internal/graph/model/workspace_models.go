@@ -0,0 +1,92 @@
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+// WorkspaceRole роль участника рабочего пространства
+type WorkspaceRole string
+
+const (
+	WorkspaceRoleOwner  WorkspaceRole = "OWNER"
+	WorkspaceRoleEditor WorkspaceRole = "EDITOR"
+	WorkspaceRoleViewer WorkspaceRole = "VIEWER"
+)
+
+var AllWorkspaceRole = []WorkspaceRole{
+	WorkspaceRoleOwner,
+	WorkspaceRoleEditor,
+	WorkspaceRoleViewer,
+}
+
+func (e WorkspaceRole) IsValid() bool {
+	switch e {
+	case WorkspaceRoleOwner, WorkspaceRoleEditor, WorkspaceRoleViewer:
+		return true
+	}
+	return false
+}
+
+func (e WorkspaceRole) String() string {
+	return string(e)
+}
+
+// DBValue значение роли для хранения в БД (owner, editor, viewer)
+func (e WorkspaceRole) DBValue() string {
+	return strings.ToLower(string(e))
+}
+
+// ParseWorkspaceRole преобразует роль из БД в GraphQL enum
+func ParseWorkspaceRole(s string) WorkspaceRole {
+	return WorkspaceRole(strings.ToUpper(s))
+}
+
+// Workspace рабочее пространство
+type Workspace struct {
+	ID          string        `json:"id"`
+	Name        string        `json:"name"`
+	MyRole      WorkspaceRole `json:"myRole"`
+	MemberCount int           `json:"memberCount"`
+	CreatedAt   time.Time     `json:"createdAt"`
+	UpdatedAt   time.Time     `json:"updatedAt"`
+}
+
+// WorkspaceMember участник рабочего пространства
+type WorkspaceMember struct {
+	UserID               string                `json:"userId"`
+	Email                string                `json:"email"`
+	FirstName            string                `json:"firstName"`
+	LastName             string                `json:"lastName"`
+	Role                 WorkspaceRole         `json:"role"`
+	NotificationChannels *NotificationChannels `json:"notificationChannels"`
+	JoinedAt             time.Time             `json:"joinedAt"`
+}
+
+// EntityNote заметка о компании или ИП
+type EntityNote struct {
+	ID          string     `json:"id"`
+	WorkspaceID string     `json:"workspaceId"`
+	EntityType  EntityType `json:"entityType"`
+	EntityID    string     `json:"entityId"`
+	AuthorID    *string    `json:"authorId,omitempty"`
+	AuthorName  string     `json:"authorName"`
+	Body        string     `json:"body"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	UpdatedAt   time.Time  `json:"updatedAt"`
+}
+
+// AddWorkspaceMemberInput добавление участника по email
+type AddWorkspaceMemberInput struct {
+	WorkspaceID string        `json:"workspaceId"`
+	Email       string        `json:"email"`
+	Role        WorkspaceRole `json:"role"`
+}
+
+// CreateEntityNoteInput новая заметка
+type CreateEntityNoteInput struct {
+	WorkspaceID string     `json:"workspaceId"`
+	EntityType  EntityType `json:"entityType"`
+	EntityID    string     `json:"entityId"`
+	Body        string     `json:"body"`
+}
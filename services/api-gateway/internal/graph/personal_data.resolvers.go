@@ -0,0 +1,137 @@
+package graph
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.56
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/egrul-system/services/api-gateway/internal/auth"
+	"github.com/egrul-system/services/api-gateway/internal/graph/model"
+	"github.com/egrul-system/services/api-gateway/internal/service"
+	"go.uber.org/zap"
+)
+
+// personalDataDownloadURL адрес выгрузки персональных данных ZIP архивом (REST)
+const personalDataDownloadURL = "/api/v1/account/export?format=zip"
+
+// ExportMyData is the resolver for the exportMyData field.
+func (r *queryResolver) ExportMyData(ctx context.Context) (*model.PersonalDataExport, error) {
+	userID, err := r.personalDataUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	export, err := r.PersonalDataService.Export(ctx, userID)
+	if err != nil {
+		if errors.Is(err, service.ErrUserNotFound) {
+			return nil, err
+		}
+		r.Logger.Error("failed to export personal data", zap.Error(err), zap.String("user_id", userID))
+		return nil, fmt.Errorf("failed to export personal data: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := export.WriteJSON(&buf); err != nil {
+		return nil, fmt.Errorf("failed to export personal data: %w", err)
+	}
+
+	r.AuditService.Record(ctx, service.AuditPersonalDataExport("json"))
+	return &model.PersonalDataExport{
+		GeneratedAt: export.GeneratedAt,
+		Data:        buf.String(),
+		DownloadURL: personalDataDownloadURL,
+	}, nil
+}
+
+// DeleteMyAccount is the resolver for the deleteMyAccount field.
+func (r *mutationResolver) DeleteMyAccount(ctx context.Context, password string) (*model.User, error) {
+	userID, err := r.personalDataUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := r.PersonalDataService.RequestDeletion(ctx, userID, auth.GetSessionIDFromContext(ctx), password)
+	if err != nil {
+		if reason := deletionDeniedReason(err); reason != "" {
+			r.AuditService.Record(ctx, service.AuditEvent{
+				Action:     model.AuditActionAccountDeletionRequest,
+				EntityType: service.AuditEntityUser,
+				EntityID:   userID,
+				Details:    map[string]interface{}{"reason": reason},
+			})
+			return nil, err
+		}
+		if errors.Is(err, service.ErrUserNotFound) || errors.Is(err, service.ErrAccountDeletionScheduled) {
+			return nil, err
+		}
+		r.Logger.Error("failed to schedule account deletion", zap.Error(err), zap.String("user_id", userID))
+		return nil, fmt.Errorf("failed to schedule account deletion: %w", err)
+	}
+
+	r.AuditService.Record(ctx, service.AuditEvent{
+		Action:     model.AuditActionAccountDeletionRequest,
+		EntityType: service.AuditEntityUser,
+		EntityID:   user.ID,
+		Success:    true,
+		Details:    map[string]interface{}{"scheduledAt": user.DeletionScheduledAt},
+	})
+	return toGraphQLUser(user), nil
+}
+
+// CancelAccountDeletion is the resolver for the cancelAccountDeletion field.
+func (r *mutationResolver) CancelAccountDeletion(ctx context.Context) (*model.User, error) {
+	userID, err := r.personalDataUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := r.PersonalDataService.CancelDeletion(ctx, userID)
+	if err != nil {
+		if errors.Is(err, service.ErrUserNotFound) || errors.Is(err, service.ErrAccountDeletionNotScheduled) {
+			return nil, err
+		}
+		r.Logger.Error("failed to cancel account deletion", zap.Error(err), zap.String("user_id", userID))
+		return nil, fmt.Errorf("failed to cancel account deletion: %w", err)
+	}
+
+	r.AuditService.Record(ctx, service.AuditEvent{
+		Action:     model.AuditActionAccountDeletionCancel,
+		EntityType: service.AuditEntityUser,
+		EntityID:   user.ID,
+		Success:    true,
+	})
+	return toGraphQLUser(user), nil
+}
+
+// deletionDeniedReason причина отказа в удалении учетной записи для журнала
+// аудита; пусто - ошибка не является отказом
+func deletionDeniedReason(err error) string {
+	switch {
+	case errors.Is(err, service.ErrWrongPassword):
+		return "invalid_password"
+	case errors.Is(err, service.ErrReauthenticationRequired):
+		return "reauthentication_required"
+	}
+	return ""
+}
+
+// personalDataUserID пользователь запроса. Выгрузка и удаление учетной записи
+// недоступны по API ключу, как и в REST (/api/v1/account/export).
+func (r *Resolver) personalDataUserID(ctx context.Context) (string, error) {
+	if r.PersonalDataService == nil {
+		return "", fmt.Errorf("personal data service not configured")
+	}
+	userID := auth.GetUserIDFromContext(ctx)
+	if userID == "" {
+		return "", errAuthenticationRequired
+	}
+	if auth.GetAPIKeyIDFromContext(ctx) != "" {
+		return "", errors.New("personal data cannot be accessed with an api key")
+	}
+	return userID, nil
+}
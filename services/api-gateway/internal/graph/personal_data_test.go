@@ -0,0 +1,30 @@
+package graph
+
+import (
+	"testing"
+
+	"github.com/egrul-system/services/api-gateway/internal/auth"
+	"github.com/egrul-system/services/api-gateway/internal/config"
+	"github.com/egrul-system/services/api-gateway/internal/service"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestPersonalDataResolvers_RejectAPIKeys(t *testing.T) {
+	// Arrange: без репозиториев - отказ должен срабатывать до обращения к БД
+	r := &Resolver{
+		PersonalDataService: service.NewPersonalDataService(nil, nil, nil, nil, nil, config.AuthConfig{}, zap.NewNop()),
+		Logger:              zap.NewNop(),
+	}
+	ctx := apiKeyContext(auth.ScopeReadCompanies, auth.ScopeReadStats, auth.ScopeManageSubscriptions)
+
+	// Act
+	_, exportErr := (&queryResolver{r}).ExportMyData(ctx)
+	_, deleteErr := (&mutationResolver{r}).DeleteMyAccount(ctx, "")
+	_, cancelErr := (&mutationResolver{r}).CancelAccountDeletion(ctx)
+
+	// Assert
+	for _, err := range []error{exportErr, deleteErr, cancelErr} {
+		assert.EqualError(t, err, "personal data cannot be accessed with an api key")
+	}
+}
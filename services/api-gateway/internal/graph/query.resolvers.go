@@ -18,15 +18,86 @@ func (r *Resolver) Query() QueryResolver { return &queryResolver{r} }
 type queryResolver struct{ *Resolver }
 
 // Company is the resolver for the company field.
-func (r *queryResolver) Company(ctx context.Context, ogrn string) (*model.Company, error) {
+func (r *queryResolver) Company(ctx context.Context, ogrn string, asOf *model.Date) (*model.Company, error) {
+	if asOf != nil && !asOf.IsZero() {
+		company, err := r.CompanyService.GetByOGRNAsOf(ctx, ogrn, asOf.Time)
+		if err != nil {
+			r.Logger.Error("failed to get company as of date", zap.String("ogrn", ogrn), zap.Time("as_of", asOf.Time), zap.Error(err))
+			return nil, err
+		}
+		r.recordCompanyView(ctx, company, asOf)
+		return company, nil
+	}
+
 	company, err := r.CompanyService.GetByOGRN(ctx, ogrn)
 	if err != nil {
 		r.Logger.Error("failed to get company by ogrn", zap.String("ogrn", ogrn), zap.Error(err))
 		return nil, err
 	}
+	r.recordCompanyView(ctx, company, nil)
 	return company, nil
 }
 
+// CompanyDiff is the resolver for the companyDiff field.
+func (r *queryResolver) CompanyDiff(ctx context.Context, ogrn string, from model.Date, to model.Date) (*model.CompanyDiff, error) {
+	diff, err := r.CompanyService.Diff(ctx, ogrn, from.Time, to.Time)
+	if err != nil {
+		r.Logger.Error("failed to diff company versions", zap.String("ogrn", ogrn), zap.Time("from", from.Time), zap.Time("to", to.Time), zap.Error(err))
+		return nil, err
+	}
+	return diff, nil
+}
+
+// RiskAssessments is the resolver for the riskAssessments field.
+func (r *queryResolver) RiskAssessments(ctx context.Context, ogrns []string) ([]*model.CompanyRiskAssessment, error) {
+	assessments, err := r.RiskService.AssessByOGRNs(ctx, ogrns)
+	if err != nil {
+		r.Logger.Error("failed to assess companies risk", zap.Int("count", len(ogrns)), zap.Error(err))
+		return nil, err
+	}
+	return assessments, nil
+}
+
+// MassAddresses is the resolver for the massAddresses field.
+func (r *queryResolver) MassAddresses(ctx context.Context, regionCode *string, minCount *int, limit *int, offset *int) ([]*model.MassAddress, error) {
+	addresses, err := r.RiskService.ListMassAddresses(ctx, regionCode, minCount, limit, offset)
+	if err != nil {
+		r.Logger.Error("failed to list mass addresses", zap.Error(err))
+		return nil, err
+	}
+	return addresses, nil
+}
+
+// MassDirectors is the resolver for the massDirectors field.
+func (r *queryResolver) MassDirectors(ctx context.Context, minCount *int, limit *int, offset *int) ([]*model.MassDirector, error) {
+	directors, err := r.RiskService.ListMassDirectors(ctx, minCount, limit, offset)
+	if err != nil {
+		r.Logger.Error("failed to list mass directors", zap.Error(err))
+		return nil, err
+	}
+	return directors, nil
+}
+
+// Person is the resolver for the person field.
+func (r *queryResolver) Person(ctx context.Context, inn string) (*model.PersonProfile, error) {
+	profile, err := r.PersonService.GetProfile(ctx, inn)
+	if err != nil {
+		r.Logger.Error("failed to get person profile", zap.String("inn", inn), zap.Error(err))
+		return nil, err
+	}
+	return profile, nil
+}
+
+// PersonCandidates is the resolver for the personCandidates field.
+func (r *queryResolver) PersonCandidates(ctx context.Context, name string, limit *int) ([]*model.PersonCandidate, error) {
+	candidates, err := r.PersonService.SearchByName(ctx, name, limit)
+	if err != nil {
+		r.Logger.Error("failed to search person candidates", zap.String("name", name), zap.Error(err))
+		return nil, err
+	}
+	return candidates, nil
+}
+
 // CompanyByInn is the resolver for the companyByInn field.
 func (r *queryResolver) CompanyByInn(ctx context.Context, inn string) (*model.Company, error) {
 	company, err := r.CompanyService.GetByINN(ctx, inn)
@@ -34,6 +105,7 @@ func (r *queryResolver) CompanyByInn(ctx context.Context, inn string) (*model.Co
 		r.Logger.Error("failed to get company by inn", zap.String("inn", inn), zap.Error(err))
 		return nil, err
 	}
+	r.recordCompanyView(ctx, company, nil)
 	return company, nil
 }
 
@@ -56,8 +128,18 @@ func (r *queryResolver) SearchCompanies(ctx context.Context, query string, limit
 }
 
 // Entrepreneur is the resolver for the entrepreneur field.
-func (r *queryResolver) Entrepreneur(ctx context.Context, ogrnip string) (*model.Entrepreneur, error) {
+func (r *queryResolver) Entrepreneur(ctx context.Context, ogrnip string, asOf *model.Date) (*model.Entrepreneur, error) {
 	r.Logger.Info("🔍 Entrepreneur query resolver called", zap.String("ogrnip", ogrnip))
+
+	if asOf != nil && !asOf.IsZero() {
+		entrepreneur, err := r.EntrepreneurService.GetByOGRNIPAsOf(ctx, ogrnip, asOf.Time)
+		if err != nil {
+			r.Logger.Error("failed to get entrepreneur as of date", zap.String("ogrnip", ogrnip), zap.Time("as_of", asOf.Time), zap.Error(err))
+			return nil, err
+		}
+		r.recordEntrepreneurView(ctx, entrepreneur, asOf)
+		return entrepreneur, nil
+	}
 	
 	entrepreneur, err := r.EntrepreneurService.GetByOGRNIP(ctx, ogrnip)
 	if err != nil {
@@ -69,6 +151,7 @@ func (r *queryResolver) Entrepreneur(ctx context.Context, ogrnip string) (*model
 		zap.String("ogrnip", ogrnip), 
 		zap.String("name", entrepreneur.FirstName+" "+entrepreneur.LastName))
 	
+	r.recordEntrepreneurView(ctx, entrepreneur, nil)
 	return entrepreneur, nil
 }
 
@@ -79,6 +162,7 @@ func (r *queryResolver) EntrepreneurByInn(ctx context.Context, inn string) (*mod
 		r.Logger.Error("failed to get entrepreneur by inn", zap.String("inn", inn), zap.Error(err))
 		return nil, err
 	}
+	r.recordEntrepreneurView(ctx, entrepreneur, nil)
 	return entrepreneur, nil
 }
 
@@ -109,6 +193,45 @@ func (r *queryResolver) Search(ctx context.Context, query string, limit *int) (*
 	return r.SearchService.Search(ctx, query, l)
 }
 
+// Suggest is the resolver for the suggest field.
+func (r *queryResolver) Suggest(ctx context.Context, prefix string, types []model.EntityType, limit *int) ([]*model.Suggestion, error) {
+	l := 10
+	if limit != nil {
+		l = *limit
+	}
+	return r.SearchService.Suggest(ctx, prefix, types, l)
+}
+
+// CompanySearch is the resolver for the companySearch field.
+func (r *queryResolver) CompanySearch(ctx context.Context, query *string, filter *model.CompanyFilter, limit *int, offset *int) (*model.CompanySearchResult, error) {
+	q, l, o := "", 20, 0
+	if query != nil {
+		q = *query
+	}
+	if limit != nil {
+		l = *limit
+	}
+	if offset != nil {
+		o = *offset
+	}
+	return r.SearchService.SearchCompaniesFaceted(ctx, q, filter, l, o)
+}
+
+// EntrepreneurSearch is the resolver for the entrepreneurSearch field.
+func (r *queryResolver) EntrepreneurSearch(ctx context.Context, query *string, filter *model.EntrepreneurFilter, limit *int, offset *int) (*model.EntrepreneurSearchResult, error) {
+	q, l, o := "", 20, 0
+	if query != nil {
+		q = *query
+	}
+	if limit != nil {
+		l = *limit
+	}
+	if offset != nil {
+		o = *offset
+	}
+	return r.SearchService.SearchEntrepreneursFaceted(ctx, q, filter, l, o)
+}
+
 // Statistics is the resolver for the statistics field.
 func (r *queryResolver) Statistics(ctx context.Context, filter *model.StatsFilter) (*model.Statistics, error) {
 	return r.StatisticsService.GetStatistics(ctx, filter)
@@ -119,6 +242,48 @@ func (r *queryResolver) DashboardStatistics(ctx context.Context, filter *model.S
 	return r.StatisticsService.GetDashboardStatistics(ctx, filter)
 }
 
+// TimeSeries is the resolver for the timeSeries field.
+func (r *queryResolver) TimeSeries(ctx context.Context, metric model.TimeSeriesMetric, granularity *model.TimeGranularity, groupBy *model.TimeSeriesGroupBy, entityType *model.EntityType, filter *model.StatsFilter) ([]*model.GroupedTimeSeriesPoint, error) {
+	r.Logger.Info("getting time series",
+		zap.String("metric", string(metric)),
+		zap.Any("granularity", granularity),
+		zap.Any("groupBy", groupBy),
+		zap.Any("entityType", entityType),
+		zap.Any("filter", filter))
+
+	points, err := r.StatisticsService.GetTimeSeries(ctx, metric, granularity, groupBy, entityType, filter)
+	if err != nil {
+		r.Logger.Error("failed to get time series", zap.Error(err))
+		return nil, err
+	}
+	return points, nil
+}
+
+// SurvivalCohorts is the resolver for the survivalCohorts field.
+func (r *queryResolver) SurvivalCohorts(ctx context.Context, cohortGranularity *model.CohortGranularity, entityType *model.EntityType, filter *model.StatsFilter) ([]*model.SurvivalCohort, error) {
+	r.Logger.Info("getting survival cohorts",
+		zap.Any("cohortGranularity", cohortGranularity),
+		zap.Any("entityType", entityType),
+		zap.Any("filter", filter))
+
+	cohorts, err := r.StatisticsService.GetSurvivalCohorts(ctx, cohortGranularity, entityType, filter)
+	if err != nil {
+		r.Logger.Error("failed to get survival cohorts", zap.Error(err))
+		return nil, err
+	}
+	return cohorts, nil
+}
+
+// OkvedTree is the resolver for the okvedTree field.
+func (r *queryResolver) OkvedTree(ctx context.Context, parent *string) ([]*model.OkvedNode, error) {
+	nodes, err := r.StatisticsService.GetOkvedTree(ctx, parent)
+	if err != nil {
+		r.Logger.Error("failed to get okved tree", zap.Error(err))
+		return nil, err
+	}
+	return nodes, nil
+}
+
 // EntityHistory is the resolver for the entityHistory field.
 func (r *queryResolver) EntityHistory(ctx context.Context, entityType model.EntityType, entityID string, limit *int, offset *int) ([]*model.HistoryRecord, error) {
 	r.Logger.Info("EntityHistory resolver called - DIRECT QUERY", 
@@ -0,0 +1,81 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/parser"
+)
+
+// maxFragmentDepth ограничение вложенности фрагментов верхнего уровня
+const maxFragmentDepth = 10
+
+// topLevelField поле верхнего уровня и тип операции, в которой оно выбрано
+type topLevelField struct {
+	Name      string
+	Operation ast.Operation
+}
+
+// parseTopLevelFields разбирает запрос и возвращает поля верхнего уровня всех
+// операций; фрагменты на верхнем уровне раскрываются
+func parseTopLevelFields(query string) ([]topLevelField, error) {
+	doc, err := parser.ParseQuery(&ast.Source{Input: query})
+	if err != nil {
+		return nil, fmt.Errorf("invalid query: %w", err)
+	}
+
+	var fields []topLevelField
+	for _, op := range doc.Operations {
+		fields, err = appendSelectionFields(fields, doc, op.Operation, op.SelectionSet, 0)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return fields, nil
+}
+
+func appendSelectionFields(fields []topLevelField, doc *ast.QueryDocument, operation ast.Operation, selections ast.SelectionSet, depth int) ([]topLevelField, error) {
+	if depth > maxFragmentDepth {
+		return nil, fmt.Errorf("fragments are nested too deeply")
+	}
+
+	var err error
+	for _, selection := range selections {
+		switch sel := selection.(type) {
+		case *ast.Field:
+			fields = append(fields, topLevelField{Name: sel.Name, Operation: operation})
+		case *ast.InlineFragment:
+			fields, err = appendSelectionFields(fields, doc, operation, sel.SelectionSet, depth+1)
+		case *ast.FragmentSpread:
+			fragment := doc.Fragments.ForName(sel.Name)
+			if fragment == nil {
+				return nil, fmt.Errorf("unknown fragment %s", sel.Name)
+			}
+			fields, err = appendSelectionFields(fields, doc, operation, fragment.SelectionSet, depth+1)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return fields, nil
+}
+
+// fieldHandler обработчик поля верхнего уровня в ManualHandler
+type fieldHandler func(ctx context.Context, req *GraphQLRequest) (*GraphQLResponse, error)
+
+// dispatch вызывает обработчик поля field, только если это поле есть среди
+// разобранных полей запроса. Маршрутизация в execute учитывает operationName
+// и текст запроса вместе с комментариями, поэтому без этой проверки запрос
+// { me { id } } с чужим operationName выполнил бы обработчик другого поля
+// в обход scopes API ключа.
+func (h *ManualHandler) dispatch(ctx context.Context, req *GraphQLRequest, fields []topLevelField, field string, handler fieldHandler) (*GraphQLResponse, error) {
+	for _, f := range fields {
+		if f.Name == field {
+			return handler(ctx, req)
+		}
+	}
+	return &GraphQLResponse{
+		Errors: []GraphQLError{{Message: fmt.Sprintf("operation %s does not match the fields of the query", field)}},
+	}, nil
+}
@@ -0,0 +1,57 @@
+package graph
+
+import (
+	"github.com/egrul-system/services/api-gateway/internal/middleware"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// rateLimitedFieldOperations тип операции для полей поиска и списков.
+// Мутации ограничиваются бюджетом mutation, остальные поля - бюджетом query.
+var rateLimitedFieldOperations = map[string]middleware.Operation{
+	"search":              middleware.OperationSearch,
+	"suggest":             middleware.OperationSearch,
+	"searchCompanies":     middleware.OperationSearch,
+	"searchEntrepreneurs": middleware.OperationSearch,
+	"companySearch":       middleware.OperationSearch,
+	"entrepreneurSearch":  middleware.OperationSearch,
+	"personCandidates":    middleware.OperationSearch,
+
+	"companies":           middleware.OperationList,
+	"entrepreneurs":       middleware.OperationList,
+	"riskAssessments":     middleware.OperationList,
+	"massAddresses":       middleware.OperationList,
+	"massDirectors":       middleware.OperationList,
+	"entityHistory":       middleware.OperationList,
+	"companyFounders":     middleware.OperationList,
+	"relatedCompanies":    middleware.OperationList,
+	"notificationHistory": middleware.OperationList,
+	"users":               middleware.OperationList,
+	"auditLog":            middleware.OperationList,
+
+	"exportMyData": middleware.OperationExport,
+}
+
+// rateLimitOperations типы операций разобранного запроса, каждый по одному
+// разу: запрос с полями разных типов расходует по токену из каждой корзины.
+// execute выполняет только обработчик одного из этих полей (см. dispatch),
+// поэтому выполненное поле всегда оплачено своей корзиной.
+func rateLimitOperations(fields []topLevelField) []middleware.Operation {
+	seen := make(map[middleware.Operation]bool)
+	var ops []middleware.Operation
+	for _, field := range fields {
+		op := middleware.OperationQuery
+		if field.Operation == ast.Mutation {
+			op = middleware.OperationMutation
+		} else if fieldOp, ok := rateLimitedFieldOperations[field.Name]; ok {
+			op = fieldOp
+		}
+		if !seen[op] {
+			seen[op] = true
+			ops = append(ops, op)
+		}
+	}
+	if len(ops) == 0 {
+		ops = append(ops, middleware.OperationQuery)
+	}
+	return ops
+}
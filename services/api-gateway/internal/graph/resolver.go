@@ -3,6 +3,7 @@ package graph
 
 import (
 	"context"
+	"time"
 
 	"github.com/egrul-system/services/api-gateway/internal/auth"
 	"github.com/egrul-system/services/api-gateway/internal/cache"
@@ -40,7 +41,7 @@ type UserRepository interface {
 // FavoriteRepository интерфейс для работы с избранным
 type FavoriteRepository interface {
 	GetByID(ctx context.Context, id string) (*model.Favorite, error)
-	GetByUserID(ctx context.Context, userID string) ([]*model.Favorite, error)
+	GetByUserID(ctx context.Context, userID string, filter postgresql.FavoriteFilter) ([]*model.Favorite, error)
 	HasFavorite(ctx context.Context, userID, entityType, entityID string) (bool, error)
 	Create(ctx context.Context, favorite *model.Favorite) error
 	Update(ctx context.Context, favorite *model.Favorite) error
@@ -49,7 +50,7 @@ type FavoriteRepository interface {
 
 // JWTManager интерфейс для работы с JWT токенами
 type JWTManager interface {
-	Generate(userID, email string) (string, error)
+	Generate(userID, email, role, sessionID string) (string, time.Time, error)
 	Verify(tokenString string) (*auth.Claims, error)
 }
 
@@ -59,6 +60,17 @@ type Resolver struct {
 	EntrepreneurService *service.EntrepreneurService
 	StatisticsService   *service.StatisticsService
 	SearchService       *service.SearchService
+	RiskService         *service.RiskService
+	PersonService       *service.PersonService
+	AccountService      *service.AccountService
+	SessionService      *service.SessionService
+	AdminService        *service.AdminService
+	APIKeyService       *service.APIKeyService
+	SSOService          *service.SSOService
+	WorkspaceService    *service.WorkspaceService
+	FavoriteService     *service.FavoriteService
+	AuditService        *service.AuditService
+	PersonalDataService *service.PersonalDataService
 	SubscriptionRepo    SubscriptionRepository
 	FavoriteRepo        FavoriteRepository
 	UserRepo            UserRepository
@@ -73,6 +85,17 @@ func NewResolver(
 	entrepreneurService *service.EntrepreneurService,
 	statisticsService *service.StatisticsService,
 	searchService *service.SearchService,
+	riskService *service.RiskService,
+	personService *service.PersonService,
+	accountService *service.AccountService,
+	sessionService *service.SessionService,
+	adminService *service.AdminService,
+	apiKeyService *service.APIKeyService,
+	ssoService *service.SSOService,
+	workspaceService *service.WorkspaceService,
+	favoriteService *service.FavoriteService,
+	auditService *service.AuditService,
+	personalDataService *service.PersonalDataService,
 	subscriptionRepo SubscriptionRepository,
 	favoriteRepo FavoriteRepository,
 	userRepo UserRepository,
@@ -85,6 +108,17 @@ func NewResolver(
 		EntrepreneurService: entrepreneurService,
 		StatisticsService:   statisticsService,
 		SearchService:       searchService,
+		RiskService:         riskService,
+		PersonService:       personService,
+		AccountService:      accountService,
+		SessionService:      sessionService,
+		AdminService:        adminService,
+		APIKeyService:       apiKeyService,
+		SSOService:          ssoService,
+		WorkspaceService:    workspaceService,
+		FavoriteService:     favoriteService,
+		AuditService:        auditService,
+		PersonalDataService: personalDataService,
 		SubscriptionRepo:    subscriptionRepo,
 		FavoriteRepo:        favoriteRepo,
 		UserRepo:            userRepo,
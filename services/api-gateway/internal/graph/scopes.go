@@ -0,0 +1,94 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/egrul-system/services/api-gateway/internal/auth"
+)
+
+// fieldScopes scope, необходимый API ключу для поля верхнего уровня.
+// Поля без записи (авторизация, управление ключами и сессиями,
+// администрирование) недоступны по API ключу.
+var fieldScopes = map[string]string{
+	// Компании, ИП, поиск, история и связи
+	"company":             auth.ScopeReadCompanies,
+	"companyByInn":        auth.ScopeReadCompanies,
+	"companyDiff":         auth.ScopeReadCompanies,
+	"riskAssessments":     auth.ScopeReadCompanies,
+	"person":              auth.ScopeReadCompanies,
+	"personCandidates":    auth.ScopeReadCompanies,
+	"massAddresses":       auth.ScopeReadCompanies,
+	"massDirectors":       auth.ScopeReadCompanies,
+	"companies":           auth.ScopeReadCompanies,
+	"searchCompanies":     auth.ScopeReadCompanies,
+	"entrepreneur":        auth.ScopeReadCompanies,
+	"entrepreneurByInn":   auth.ScopeReadCompanies,
+	"entrepreneurs":       auth.ScopeReadCompanies,
+	"searchEntrepreneurs": auth.ScopeReadCompanies,
+	"search":              auth.ScopeReadCompanies,
+	"suggest":             auth.ScopeReadCompanies,
+	"companySearch":       auth.ScopeReadCompanies,
+	"entrepreneurSearch":  auth.ScopeReadCompanies,
+	"okvedTree":           auth.ScopeReadCompanies,
+	"entityHistory":       auth.ScopeReadCompanies,
+	"entityHistoryCount":  auth.ScopeReadCompanies,
+	"companyFounders":     auth.ScopeReadCompanies,
+	"relatedCompanies":    auth.ScopeReadCompanies,
+
+	// Статистика
+	"statistics":          auth.ScopeReadStats,
+	"dashboardStatistics": auth.ScopeReadStats,
+	"timeSeries":          auth.ScopeReadStats,
+	"survivalCohorts":     auth.ScopeReadStats,
+
+	// Подписки и избранное
+	"mySubscriptions":            auth.ScopeManageSubscriptions,
+	"subscription":               auth.ScopeManageSubscriptions,
+	"notificationHistory":        auth.ScopeManageSubscriptions,
+	"hasSubscription":            auth.ScopeManageSubscriptions,
+	"createSubscription":         auth.ScopeManageSubscriptions,
+	"updateSubscriptionFilters":  auth.ScopeManageSubscriptions,
+	"updateSubscriptionChannels": auth.ScopeManageSubscriptions,
+	"deleteSubscription":         auth.ScopeManageSubscriptions,
+	"toggleSubscription":         auth.ScopeManageSubscriptions,
+	"myFavorites":                auth.ScopeManageSubscriptions,
+	"hasFavorite":                auth.ScopeManageSubscriptions,
+	"createFavorite":             auth.ScopeManageSubscriptions,
+	"updateFavoriteNotes":        auth.ScopeManageSubscriptions,
+	"deleteFavorite":             auth.ScopeManageSubscriptions,
+	"myFavoriteFolders":          auth.ScopeManageSubscriptions,
+	"myFavoriteTags":             auth.ScopeManageSubscriptions,
+	"setFavoriteTags":            auth.ScopeManageSubscriptions,
+	"addFavorites":               auth.ScopeManageSubscriptions,
+	"moveFavorites":              auth.ScopeManageSubscriptions,
+	"deleteFavorites":            auth.ScopeManageSubscriptions,
+}
+
+// scopeFreeFields поля, доступные по API ключу с любыми scopes
+var scopeFreeFields = map[string]bool{
+	"me":         true,
+	"__typename": true,
+}
+
+// checkAPIKeyScopes проверяет, что API ключ запроса имеет scopes для всех полей
+// верхнего уровня. Запросы без API ключа не проверяются.
+func checkAPIKeyScopes(ctx context.Context, fields []topLevelField) error {
+	if auth.GetAPIKeyIDFromContext(ctx) == "" {
+		return nil
+	}
+
+	for _, field := range fields {
+		if scopeFreeFields[field.Name] {
+			continue
+		}
+		scope, ok := fieldScopes[field.Name]
+		if !ok {
+			return fmt.Errorf("field %s is not available with an API key", field.Name)
+		}
+		if !auth.HasScope(ctx, scope) {
+			return fmt.Errorf("API key does not have scope %s required for %s", scope, field.Name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package graph
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.56
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/egrul-system/services/api-gateway/internal/auth"
+	"github.com/egrul-system/services/api-gateway/internal/graph/model"
+	"github.com/egrul-system/services/api-gateway/internal/service"
+	"go.uber.org/zap"
+)
+
+// errSSONotConfigured вход через SSO выключен
+var errSSONotConfigured = errors.New("single sign-on is not configured")
+
+// BeginOidcLogin is the resolver for the beginOidcLogin field.
+func (r *mutationResolver) BeginOidcLogin(ctx context.Context, redirectURI *string) (*model.OidcLoginStart, error) {
+	if r.SSOService == nil {
+		return nil, errSSONotConfigured
+	}
+
+	uri := ""
+	if redirectURI != nil {
+		uri = *redirectURI
+	}
+
+	start, err := r.SSOService.Begin(ctx, uri)
+	if err != nil {
+		if errors.Is(err, service.ErrSSORedirectNotAllowed) {
+			return nil, err
+		}
+		r.Logger.Error("failed to begin sso login", zap.Error(err))
+		return nil, fmt.Errorf("failed to begin sso login: %w", err)
+	}
+
+	return &model.OidcLoginStart{
+		AuthorizationURL: start.AuthorizationURL,
+		State:            start.State,
+	}, nil
+}
+
+// CompleteOidcLogin is the resolver for the completeOidcLogin field.
+func (r *mutationResolver) CompleteOidcLogin(ctx context.Context, code string, state string) (*model.AuthResponse, error) {
+	if r.SSOService == nil {
+		return nil, errSSONotConfigured
+	}
+
+	tokens, user, err := r.SSOService.Complete(ctx, code, state, auth.GetClientInfoFromContext(ctx))
+	if err != nil {
+		r.Logger.Warn("sso login failed", zap.Error(err))
+		r.AuditService.Record(ctx, service.AuditEvent{
+			Action:  model.AuditActionLoginFailed,
+			Details: map[string]interface{}{"method": "oidc", "reason": err.Error()},
+		})
+		return nil, err
+	}
+
+	r.AuditService.Record(ctx, service.AuditEvent{
+		Action:    model.AuditActionLogin,
+		UserID:    user.ID,
+		UserEmail: user.Email,
+		Success:   true,
+		Details:   map[string]interface{}{"method": "oidc"},
+	})
+
+	return toAuthResponse(user, tokens), nil
+}
@@ -10,13 +10,13 @@ import (
 )
 
 // ByActivity is the resolver for the byActivity field on Statistics.
-func (r *statisticsResolver) ByActivity(ctx context.Context, obj *model.Statistics, limit *int) ([]*model.ActivityStatistics, error) {
+func (r *statisticsResolver) ByActivity(ctx context.Context, obj *model.Statistics, limit *int, level *model.OkvedLevel, filter *model.StatsFilter) ([]*model.ActivityStatistics, error) {
 	l := 20
 	if limit != nil {
 		l = *limit
 	}
 
-	stats, err := r.StatisticsService.GetActivityStats(ctx, l)
+	stats, err := r.StatisticsService.GetActivityStats(ctx, filter, level, l)
 	if err != nil {
 		r.Logger.Error("failed to get activity stats", zap.Error(err))
 		return nil, err
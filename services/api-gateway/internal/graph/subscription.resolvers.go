@@ -10,6 +10,7 @@ import (
 
 	"github.com/egrul-system/services/api-gateway/internal/auth"
 	"github.com/egrul-system/services/api-gateway/internal/graph/model"
+	"github.com/egrul-system/services/api-gateway/internal/service"
 	"go.uber.org/zap"
 )
 
@@ -35,6 +36,34 @@ func (r *mutationResolver) CreateSubscription(ctx context.Context, input model.C
 		return nil, fmt.Errorf("user not found")
 	}
 
+	// Подписка пространства создается через сервис пространств
+	if input.WorkspaceID != nil {
+		if r.WorkspaceService == nil {
+			return nil, fmt.Errorf("workspace service not configured")
+		}
+		subscription := &model.EntitySubscription{
+			UserEmail:            user.Email,
+			EntityType:           input.EntityType,
+			EntityID:             input.EntityID,
+			EntityName:           input.EntityName,
+			ChangeFilters:        input.ChangeFilters.ToChangeFilters(),
+			NotificationChannels: input.NotificationChannels.ToNotificationChannels(),
+			IsActive:             true,
+		}
+		if err := r.WorkspaceService.AddSubscription(ctx, userID, *input.WorkspaceID, subscription); err != nil {
+			return nil, r.workspaceError("failed to create workspace subscription", err)
+		}
+
+		r.Logger.Info("workspace subscription created",
+			zap.String("id", subscription.ID),
+			zap.String("workspace_id", *input.WorkspaceID),
+			zap.String("user_id", userID),
+			zap.String("entity_id", subscription.EntityID),
+		)
+		r.recordSubscriptionChange(ctx, model.AuditActionSubscriptionCreate, subscription, nil)
+		return subscription, nil
+	}
+
 	// Проверяем, нет ли уже подписки
 	exists, err := r.SubscriptionRepo.HasSubscription(ctx, userID, string(input.EntityType), input.EntityID)
 	if err != nil {
@@ -76,6 +105,7 @@ func (r *mutationResolver) CreateSubscription(ctx context.Context, input model.C
 		zap.String("entity_type", string(subscription.EntityType)),
 		zap.String("entity_id", subscription.EntityID),
 	)
+	r.recordSubscriptionChange(ctx, model.AuditActionSubscriptionCreate, subscription, nil)
 
 	return subscription, nil
 }
@@ -99,6 +129,10 @@ func (r *mutationResolver) UpdateSubscriptionFilters(ctx context.Context, input
 		return nil, fmt.Errorf("subscription not found")
 	}
 
+	if err := r.authorizeSubscription(ctx, subscription, service.WorkspaceRoleEditor); err != nil {
+		return nil, err
+	}
+
 	// Обновляем фильтры
 	subscription.ChangeFilters = input.ChangeFilters.ToChangeFilters()
 
@@ -113,6 +147,7 @@ func (r *mutationResolver) UpdateSubscriptionFilters(ctx context.Context, input
 	r.Logger.Info("subscription filters updated",
 		zap.String("id", subscription.ID),
 	)
+	r.recordSubscriptionChange(ctx, model.AuditActionSubscriptionUpdate, subscription, map[string]interface{}{"changed": "filters"})
 
 	return subscription, nil
 }
@@ -136,6 +171,10 @@ func (r *mutationResolver) UpdateSubscriptionChannels(ctx context.Context, input
 		return nil, fmt.Errorf("subscription not found")
 	}
 
+	if err := r.authorizeSubscription(ctx, subscription, service.WorkspaceRoleEditor); err != nil {
+		return nil, err
+	}
+
 	// Обновляем каналы
 	subscription.NotificationChannels = input.NotificationChannels.ToNotificationChannels()
 
@@ -150,6 +189,7 @@ func (r *mutationResolver) UpdateSubscriptionChannels(ctx context.Context, input
 	r.Logger.Info("subscription channels updated",
 		zap.String("id", subscription.ID),
 	)
+	r.recordSubscriptionChange(ctx, model.AuditActionSubscriptionUpdate, subscription, map[string]interface{}{"changed": "channels"})
 
 	return subscription, nil
 }
@@ -160,6 +200,23 @@ func (r *mutationResolver) DeleteSubscription(ctx context.Context, id string) (b
 		return false, fmt.Errorf("subscription repository not configured")
 	}
 
+	subscription, err := r.SubscriptionRepo.GetByID(ctx, id)
+	if err != nil {
+		r.Logger.Error("failed to get subscription",
+			zap.String("id", id),
+			zap.Error(err),
+		)
+		return false, err
+	}
+
+	if subscription == nil {
+		return false, fmt.Errorf("subscription not found")
+	}
+
+	if err := r.authorizeSubscription(ctx, subscription, service.WorkspaceRoleEditor); err != nil {
+		return false, err
+	}
+
 	if err := r.SubscriptionRepo.Delete(ctx, id); err != nil {
 		r.Logger.Error("failed to delete subscription",
 			zap.String("id", id),
@@ -171,6 +228,7 @@ func (r *mutationResolver) DeleteSubscription(ctx context.Context, id string) (b
 	r.Logger.Info("subscription deleted",
 		zap.String("id", id),
 	)
+	r.recordSubscriptionChange(ctx, model.AuditActionSubscriptionDelete, subscription, nil)
 
 	return true, nil
 }
@@ -194,6 +252,10 @@ func (r *mutationResolver) ToggleSubscription(ctx context.Context, input model.T
 		return nil, fmt.Errorf("subscription not found")
 	}
 
+	if err := r.authorizeSubscription(ctx, subscription, service.WorkspaceRoleEditor); err != nil {
+		return nil, err
+	}
+
 	// Обновляем статус
 	subscription.IsActive = input.IsActive
 
@@ -210,6 +272,7 @@ func (r *mutationResolver) ToggleSubscription(ctx context.Context, input model.T
 		zap.String("id", subscription.ID),
 		zap.Bool("is_active", subscription.IsActive),
 	)
+	r.recordSubscriptionChange(ctx, model.AuditActionSubscriptionUpdate, subscription, map[string]interface{}{"isActive": subscription.IsActive})
 
 	return subscription, nil
 }
@@ -250,6 +313,14 @@ func (r *queryResolver) Subscription(ctx context.Context, id string) (*model.Ent
 		return nil, err
 	}
 
+	if subscription == nil {
+		return nil, nil
+	}
+
+	if err := r.authorizeSubscription(ctx, subscription, service.WorkspaceRoleViewer); err != nil {
+		return nil, err
+	}
+
 	return subscription, nil
 }
 
@@ -259,6 +330,23 @@ func (r *queryResolver) NotificationHistory(ctx context.Context, subscriptionID
 		return nil, fmt.Errorf("subscription repository not configured")
 	}
 
+	subscription, err := r.SubscriptionRepo.GetByID(ctx, subscriptionID)
+	if err != nil {
+		r.Logger.Error("failed to get subscription",
+			zap.String("id", subscriptionID),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	if subscription == nil {
+		return nil, fmt.Errorf("subscription not found")
+	}
+
+	if err := r.authorizeSubscription(ctx, subscription, service.WorkspaceRoleViewer); err != nil {
+		return nil, err
+	}
+
 	l := 20
 	if limit != nil {
 		l = *limit
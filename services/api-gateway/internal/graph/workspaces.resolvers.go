@@ -0,0 +1,356 @@
+package graph
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.56
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/egrul-system/services/api-gateway/internal/auth"
+	"github.com/egrul-system/services/api-gateway/internal/graph/model"
+	"github.com/egrul-system/services/api-gateway/internal/repository/postgresql"
+	"github.com/egrul-system/services/api-gateway/internal/service"
+	"go.uber.org/zap"
+)
+
+// CreateWorkspace is the resolver for the createWorkspace field.
+func (r *mutationResolver) CreateWorkspace(ctx context.Context, name string) (*model.Workspace, error) {
+	userID, err := r.workspaceUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	workspace, err := r.WorkspaceService.Create(ctx, userID, name)
+	if err != nil {
+		return nil, r.workspaceError("failed to create workspace", err)
+	}
+	return toGraphQLWorkspace(workspace), nil
+}
+
+// RenameWorkspace is the resolver for the renameWorkspace field.
+func (r *mutationResolver) RenameWorkspace(ctx context.Context, id string, name string) (*model.Workspace, error) {
+	userID, err := r.workspaceUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	workspace, err := r.WorkspaceService.Rename(ctx, userID, id, name)
+	if err != nil {
+		return nil, r.workspaceError("failed to rename workspace", err)
+	}
+	return toGraphQLWorkspace(workspace), nil
+}
+
+// DeleteWorkspace is the resolver for the deleteWorkspace field.
+func (r *mutationResolver) DeleteWorkspace(ctx context.Context, id string) (bool, error) {
+	userID, err := r.workspaceUserID(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	if err := r.WorkspaceService.Delete(ctx, userID, id); err != nil {
+		return false, r.workspaceError("failed to delete workspace", err)
+	}
+	return true, nil
+}
+
+// AddWorkspaceMember is the resolver for the addWorkspaceMember field.
+func (r *mutationResolver) AddWorkspaceMember(ctx context.Context, input model.AddWorkspaceMemberInput) (*model.WorkspaceMember, error) {
+	userID, err := r.workspaceUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	member, err := r.WorkspaceService.AddMember(ctx, userID, input.WorkspaceID, input.Email, input.Role.DBValue())
+	if err != nil {
+		return nil, r.workspaceError("failed to add workspace member", err)
+	}
+	return toGraphQLWorkspaceMember(member), nil
+}
+
+// SetWorkspaceMemberRole is the resolver for the setWorkspaceMemberRole field.
+func (r *mutationResolver) SetWorkspaceMemberRole(ctx context.Context, workspaceID string, memberID string, role model.WorkspaceRole) (*model.WorkspaceMember, error) {
+	userID, err := r.workspaceUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	member, err := r.WorkspaceService.SetMemberRole(ctx, userID, workspaceID, memberID, role.DBValue())
+	if err != nil {
+		return nil, r.workspaceError("failed to set workspace member role", err)
+	}
+	return toGraphQLWorkspaceMember(member), nil
+}
+
+// RemoveWorkspaceMember is the resolver for the removeWorkspaceMember field.
+func (r *mutationResolver) RemoveWorkspaceMember(ctx context.Context, workspaceID string, memberID string) (bool, error) {
+	userID, err := r.workspaceUserID(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	if err := r.WorkspaceService.RemoveMember(ctx, userID, workspaceID, memberID); err != nil {
+		return false, r.workspaceError("failed to remove workspace member", err)
+	}
+	return true, nil
+}
+
+// UpdateWorkspaceNotificationChannels is the resolver for the updateWorkspaceNotificationChannels field.
+func (r *mutationResolver) UpdateWorkspaceNotificationChannels(ctx context.Context, workspaceID string, notificationChannels model.NotificationChannelsInput) (*model.WorkspaceMember, error) {
+	userID, err := r.workspaceUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	member, err := r.WorkspaceService.SetNotificationChannels(ctx, userID, workspaceID, *notificationChannels.ToNotificationChannels())
+	if err != nil {
+		return nil, r.workspaceError("failed to update workspace notification channels", err)
+	}
+	return toGraphQLWorkspaceMember(member), nil
+}
+
+// CreateEntityNote is the resolver for the createEntityNote field.
+func (r *mutationResolver) CreateEntityNote(ctx context.Context, input model.CreateEntityNoteInput) (*model.EntityNote, error) {
+	userID, err := r.workspaceUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	note, err := r.WorkspaceService.AddNote(ctx, userID, input.WorkspaceID, string(input.EntityType), input.EntityID, input.Body)
+	if err != nil {
+		return nil, r.workspaceError("failed to create entity note", err)
+	}
+	return toGraphQLEntityNote(note), nil
+}
+
+// UpdateEntityNote is the resolver for the updateEntityNote field.
+func (r *mutationResolver) UpdateEntityNote(ctx context.Context, id string, body string) (*model.EntityNote, error) {
+	userID, err := r.workspaceUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	note, err := r.WorkspaceService.UpdateNote(ctx, userID, id, body)
+	if err != nil {
+		return nil, r.workspaceError("failed to update entity note", err)
+	}
+	return toGraphQLEntityNote(note), nil
+}
+
+// DeleteEntityNote is the resolver for the deleteEntityNote field.
+func (r *mutationResolver) DeleteEntityNote(ctx context.Context, id string) (bool, error) {
+	userID, err := r.workspaceUserID(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	if err := r.WorkspaceService.DeleteNote(ctx, userID, id); err != nil {
+		return false, r.workspaceError("failed to delete entity note", err)
+	}
+	return true, nil
+}
+
+// MyWorkspaces is the resolver for the myWorkspaces field.
+func (r *queryResolver) MyWorkspaces(ctx context.Context) ([]*model.Workspace, error) {
+	userID, err := r.workspaceUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	workspaces, err := r.WorkspaceService.List(ctx, userID)
+	if err != nil {
+		return nil, r.workspaceError("failed to list workspaces", err)
+	}
+
+	result := make([]*model.Workspace, len(workspaces))
+	for i, workspace := range workspaces {
+		result[i] = toGraphQLWorkspace(workspace)
+	}
+	return result, nil
+}
+
+// Workspace is the resolver for the workspace field.
+func (r *queryResolver) Workspace(ctx context.Context, id string) (*model.Workspace, error) {
+	userID, err := r.workspaceUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	workspace, err := r.WorkspaceService.Get(ctx, userID, id)
+	if errors.Is(err, service.ErrWorkspaceNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, r.workspaceError("failed to get workspace", err)
+	}
+	return toGraphQLWorkspace(workspace), nil
+}
+
+// WorkspaceMembers is the resolver for the workspaceMembers field.
+func (r *queryResolver) WorkspaceMembers(ctx context.Context, workspaceID string) ([]*model.WorkspaceMember, error) {
+	userID, err := r.workspaceUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	members, err := r.WorkspaceService.Members(ctx, userID, workspaceID)
+	if err != nil {
+		return nil, r.workspaceError("failed to list workspace members", err)
+	}
+
+	result := make([]*model.WorkspaceMember, len(members))
+	for i, member := range members {
+		result[i] = toGraphQLWorkspaceMember(member)
+	}
+	return result, nil
+}
+
+// WorkspaceFavorites is the resolver for the workspaceFavorites field.
+func (r *queryResolver) WorkspaceFavorites(ctx context.Context, workspaceID string) ([]*model.Favorite, error) {
+	userID, err := r.workspaceUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	favorites, err := r.WorkspaceService.Favorites(ctx, userID, workspaceID)
+	if err != nil {
+		return nil, r.workspaceError("failed to get workspace favorites", err)
+	}
+	return favorites, nil
+}
+
+// WorkspaceSubscriptions is the resolver for the workspaceSubscriptions field.
+func (r *queryResolver) WorkspaceSubscriptions(ctx context.Context, workspaceID string) ([]*model.EntitySubscription, error) {
+	userID, err := r.workspaceUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	subscriptions, err := r.WorkspaceService.Subscriptions(ctx, userID, workspaceID)
+	if err != nil {
+		return nil, r.workspaceError("failed to get workspace subscriptions", err)
+	}
+	return subscriptions, nil
+}
+
+// EntityNotes is the resolver for the entityNotes field.
+func (r *queryResolver) EntityNotes(ctx context.Context, workspaceID string, entityType model.EntityType, entityID string) ([]*model.EntityNote, error) {
+	userID, err := r.workspaceUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	notes, err := r.WorkspaceService.Notes(ctx, userID, workspaceID, string(entityType), entityID)
+	if err != nil {
+		return nil, r.workspaceError("failed to get entity notes", err)
+	}
+
+	result := make([]*model.EntityNote, len(notes))
+	for i, note := range notes {
+		result[i] = toGraphQLEntityNote(note)
+	}
+	return result, nil
+}
+
+// workspaceUserID пользователь, выполняющий операцию с пространствами
+func (r *Resolver) workspaceUserID(ctx context.Context) (string, error) {
+	if r.WorkspaceService == nil {
+		return "", fmt.Errorf("workspace service not configured")
+	}
+	userID := auth.GetUserIDFromContext(ctx)
+	if userID == "" {
+		return "", errAuthenticationRequired
+	}
+	return userID, nil
+}
+
+// authorizeWorkspaceEntry проверяет роль пользователя в пространстве, которому
+// принадлежит запись избранного или подписка
+func (r *Resolver) authorizeWorkspaceEntry(ctx context.Context, userID, workspaceID, required string) error {
+	if r.WorkspaceService == nil {
+		return fmt.Errorf("workspace service not configured")
+	}
+	if _, err := r.WorkspaceService.Authorize(ctx, userID, workspaceID, required); err != nil {
+		return r.workspaceError("failed to check workspace access", err)
+	}
+	return nil
+}
+
+// authorizeSubscription личная подписка доступна только владельцу, подписка
+// пространства - участникам с ролью не ниже required
+func (r *Resolver) authorizeSubscription(ctx context.Context, subscription *model.EntitySubscription, required string) error {
+	userID := auth.GetUserIDFromContext(ctx)
+	if subscription.WorkspaceID != nil {
+		return r.authorizeWorkspaceEntry(ctx, userID, *subscription.WorkspaceID, required)
+	}
+	if subscription.UserID != userID {
+		return fmt.Errorf("access denied: you can only access your own subscriptions")
+	}
+	return nil
+}
+
+// workspaceError ошибки доступа и валидации возвращаются клиенту как есть,
+// остальные логируются
+func (r *Resolver) workspaceError(msg string, err error) error {
+	for _, known := range []error{
+		service.ErrWorkspaceNotFound,
+		service.ErrWorkspaceForbidden,
+		service.ErrWorkspaceLastOwner,
+		service.ErrWorkspaceMemberExists,
+		service.ErrWorkspaceMemberNotFound,
+		service.ErrWorkspaceEntityExists,
+		service.ErrEntityNoteNotFound,
+		service.ErrInvalidWorkspaceParams,
+	} {
+		if errors.Is(err, known) {
+			return err
+		}
+	}
+	r.Logger.Error(msg, zap.Error(err))
+	return fmt.Errorf("%s: %w", msg, err)
+}
+
+// toGraphQLWorkspace конвертирует postgresql.Workspace в model.Workspace
+func toGraphQLWorkspace(workspace *postgresql.Workspace) *model.Workspace {
+	return &model.Workspace{
+		ID:          workspace.ID,
+		Name:        workspace.Name,
+		MyRole:      model.ParseWorkspaceRole(workspace.Role),
+		MemberCount: workspace.MemberCount,
+		CreatedAt:   workspace.CreatedAt,
+		UpdatedAt:   workspace.UpdatedAt,
+	}
+}
+
+// toGraphQLWorkspaceMember конвертирует postgresql.WorkspaceMember в model.WorkspaceMember
+func toGraphQLWorkspaceMember(member *postgresql.WorkspaceMember) *model.WorkspaceMember {
+	channels := member.NotificationChannels
+	return &model.WorkspaceMember{
+		UserID:               member.UserID,
+		Email:                member.Email,
+		FirstName:            member.FirstName,
+		LastName:             member.LastName,
+		Role:                 model.ParseWorkspaceRole(member.Role),
+		NotificationChannels: &channels,
+		JoinedAt:             member.JoinedAt,
+	}
+}
+
+// toGraphQLEntityNote конвертирует postgresql.EntityNote в model.EntityNote
+func toGraphQLEntityNote(note *postgresql.EntityNote) *model.EntityNote {
+	return &model.EntityNote{
+		ID:          note.ID,
+		WorkspaceID: note.WorkspaceID,
+		EntityType:  model.EntityType(strings.ToUpper(note.EntityType)),
+		EntityID:    note.EntityID,
+		AuthorID:    note.AuthorID,
+		AuthorName:  strings.TrimSpace(note.AuthorName),
+		Body:        note.Body,
+		CreatedAt:   note.CreatedAt,
+		UpdatedAt:   note.UpdatedAt,
+	}
+}
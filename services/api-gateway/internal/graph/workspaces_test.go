@@ -0,0 +1,138 @@
+package graph
+
+import (
+	"context"
+	"testing"
+
+	"github.com/egrul-system/services/api-gateway/internal/auth"
+	"github.com/egrul-system/services/api-gateway/internal/graph/model"
+	"github.com/egrul-system/services/api-gateway/internal/repository/postgresql"
+	"github.com/egrul-system/services/api-gateway/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// memberStore пространство ws-1 с ролями участников; остальные методы
+// хранилища тестам не нужны
+type memberStore struct {
+	service.WorkspaceStore
+	roles map[string]string
+}
+
+func (s *memberStore) GetForMember(_ context.Context, workspaceID, userID string) (*postgresql.Workspace, error) {
+	role, ok := s.roles[userID]
+	if workspaceID != "ws-1" || !ok {
+		return nil, nil
+	}
+	return &postgresql.Workspace{ID: workspaceID, Role: role}, nil
+}
+
+func newWorkspaceResolver() *Resolver {
+	store := &memberStore{roles: map[string]string{
+		"editor": service.WorkspaceRoleEditor,
+		"viewer": service.WorkspaceRoleViewer,
+	}}
+	return &Resolver{
+		WorkspaceService: service.NewWorkspaceService(store, nil, nil, nil, zap.NewNop()),
+		Logger:           zap.NewNop(),
+	}
+}
+
+func userContext(userID string) context.Context {
+	return context.WithValue(context.Background(), auth.UserIDKey, userID)
+}
+
+func TestResolver_AuthorizeWorkspaceEntry(t *testing.T) {
+	tests := []struct {
+		name     string
+		userID   string
+		required string
+		wantErr  error
+	}{
+		{"editor can change entries", "editor", service.WorkspaceRoleEditor, nil},
+		{"viewer can read entries", "viewer", service.WorkspaceRoleViewer, nil},
+		{"viewer cannot change entries", "viewer", service.WorkspaceRoleEditor, service.ErrWorkspaceForbidden},
+		{"non-member is denied", "stranger", service.WorkspaceRoleViewer, service.ErrWorkspaceNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			r := newWorkspaceResolver()
+
+			// Act
+			err := r.authorizeWorkspaceEntry(userContext(tt.userID), tt.userID, "ws-1", tt.required)
+
+			// Assert
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestResolver_AuthorizeSubscription(t *testing.T) {
+	workspaceID := "ws-1"
+
+	tests := []struct {
+		name         string
+		userID       string
+		subscription *model.EntitySubscription
+		required     string
+		wantErr      bool
+	}{
+		{
+			name:         "own personal subscription",
+			userID:       "user-1",
+			subscription: &model.EntitySubscription{ID: "sub-1", UserID: "user-1"},
+			required:     service.WorkspaceRoleEditor,
+		},
+		{
+			name:         "personal subscription of another user",
+			userID:       "user-2",
+			subscription: &model.EntitySubscription{ID: "sub-1", UserID: "user-1"},
+			required:     service.WorkspaceRoleViewer,
+			wantErr:      true,
+		},
+		{
+			name:         "workspace subscription changed by editor",
+			userID:       "editor",
+			subscription: &model.EntitySubscription{ID: "sub-2", UserID: "viewer", WorkspaceID: &workspaceID},
+			required:     service.WorkspaceRoleEditor,
+		},
+		{
+			name:         "workspace subscription changed by viewer",
+			userID:       "viewer",
+			subscription: &model.EntitySubscription{ID: "sub-2", UserID: "viewer", WorkspaceID: &workspaceID},
+			required:     service.WorkspaceRoleEditor,
+			wantErr:      true,
+		},
+		{
+			name:         "workspace subscription read by non-member",
+			userID:       "user-1",
+			subscription: &model.EntitySubscription{ID: "sub-2", UserID: "user-1", WorkspaceID: &workspaceID},
+			required:     service.WorkspaceRoleViewer,
+			wantErr:      true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			r := newWorkspaceResolver()
+
+			// Act
+			err := r.authorizeSubscription(userContext(tt.userID), tt.subscription, tt.required)
+
+			// Assert
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package middleware
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/egrul-system/services/api-gateway/internal/auth"
+	"github.com/egrul-system/services/api-gateway/internal/cache"
+	"github.com/egrul-system/services/api-gateway/internal/config"
+	"go.uber.org/zap"
+)
+
+// Operation тип операции; у каждого типа своя корзина токенов
+type Operation string
+
+const (
+	OperationSearch   Operation = "search"
+	OperationList     Operation = "list"
+	OperationExport   Operation = "export"
+	OperationMutation Operation = "mutation"
+	OperationQuery    Operation = "query"
+)
+
+// rateLimitKeyPrefix префикс ключей Redis для корзин токенов
+const rateLimitKeyPrefix = "ratelimit:"
+
+// RateLimiter ограничивает частоту запросов по token bucket в Redis. Корзина
+// выбирается по API ключу, пользователю или IP клиента, бюджет - по роли.
+// При недоступности Redis запросы пропускаются.
+type RateLimiter struct {
+	cache  cache.Cache
+	cfg    config.RateLimitConfig
+	logger *zap.Logger
+}
+
+// NewRateLimiter создает новый ограничитель частоты запросов
+func NewRateLimiter(cache cache.Cache, cfg config.RateLimitConfig, logger *zap.Logger) *RateLimiter {
+	return &RateLimiter{
+		cache:  cache,
+		cfg:    cfg,
+		logger: logger.Named("rate_limiter"),
+	}
+}
+
+// Allow забирает токен операции op для клиента запроса. clientIP используется
+// для анонимных запросов. Возвращает false и время ожидания, если лимит исчерпан.
+func (l *RateLimiter) Allow(ctx context.Context, op Operation, clientIP string) (bool, time.Duration) {
+	if l == nil || !l.cfg.Enabled {
+		return true, 0
+	}
+
+	subject, role := rateLimitSubject(ctx, clientIP)
+	bucket := bucketFor(l.budgetsFor(role), op)
+	if bucket.PerMinute <= 0 {
+		return true, 0
+	}
+	burst := bucket.Burst
+	if burst <= 0 {
+		burst = 1
+	}
+
+	key := rateLimitKeyPrefix + string(op) + ":" + subject
+	allowed, retryAfter, err := l.cache.TakeToken(ctx, key, float64(bucket.PerMinute)/60, burst)
+	if err != nil {
+		l.logger.Warn("rate limit check failed", zap.String("key", key), zap.Error(err))
+		return true, 0
+	}
+	if !allowed {
+		l.logger.Info("rate limit exceeded",
+			zap.String("operation", string(op)),
+			zap.String("subject", subject),
+			zap.Duration("retry_after", retryAfter),
+		)
+	}
+	return allowed, retryAfter
+}
+
+// Middleware создает HTTP middleware, ограничивающий операцию op. Отвечает 429
+// с заголовком Retry-After. Для учета пользователя должен стоять после
+// JWTManager.Middleware.
+func (l *RateLimiter) Middleware(op Operation) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if allowed, retryAfter := l.Allow(r.Context(), op, auth.ClientIP(r)); !allowed {
+				SetRetryAfter(w, retryAfter)
+				http.Error(w, "Rate limit exceeded", http.StatusTooManyRequests)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// SetRetryAfter выставляет заголовок Retry-After в целых секундах (не меньше 1)
+func SetRetryAfter(w http.ResponseWriter, retryAfter time.Duration) {
+	w.Header().Set("Retry-After", strconv.Itoa(RetryAfterSeconds(retryAfter)))
+}
+
+// RetryAfterSeconds время ожидания в целых секундах с округлением вверх
+func RetryAfterSeconds(retryAfter time.Duration) int {
+	seconds := int(math.Ceil(retryAfter.Seconds()))
+	if seconds < 1 {
+		seconds = 1
+	}
+	return seconds
+}
+
+// budgetsFor бюджеты роли; пустая роль без пользователя - анонимный запрос
+func (l *RateLimiter) budgetsFor(role string) config.RateLimitBudgets {
+	switch role {
+	case "":
+		return l.cfg.Anonymous
+	case auth.RoleAdmin:
+		return l.cfg.Admin
+	case auth.RoleAnalyst:
+		return l.cfg.Analyst
+	default:
+		return l.cfg.User
+	}
+}
+
+// rateLimitSubject идентификатор корзины и роль клиента. У запросов с API
+// ключом отдельная корзина, не общая с браузерными сессиями владельца.
+func rateLimitSubject(ctx context.Context, clientIP string) (subject, role string) {
+	if keyID := auth.GetAPIKeyIDFromContext(ctx); keyID != "" {
+		return "key:" + keyID, roleOrUser(auth.GetRoleFromContext(ctx))
+	}
+	if userID := auth.GetUserIDFromContext(ctx); userID != "" {
+		return "user:" + userID, roleOrUser(auth.GetRoleFromContext(ctx))
+	}
+	return "ip:" + clientIP, ""
+}
+
+// roleOrUser токены без роли считаются ролью user
+func roleOrUser(role string) string {
+	if role == "" {
+		return auth.RoleUser
+	}
+	return role
+}
+
+func bucketFor(budgets config.RateLimitBudgets, op Operation) config.RateLimitBucket {
+	switch op {
+	case OperationSearch:
+		return budgets.Search
+	case OperationList:
+		return budgets.List
+	case OperationExport:
+		return budgets.Export
+	case OperationMutation:
+		return budgets.Mutation
+	default:
+		return budgets.Query
+	}
+}
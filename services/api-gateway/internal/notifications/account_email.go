@@ -0,0 +1,76 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"go.uber.org/zap"
+)
+
+// Типы писем по учетной записи
+const (
+	AccountEmailVerification  = "email_verification"
+	AccountEmailPasswordReset = "password_reset"
+)
+
+// AccountEmailEvent событие для notification-service: письмо со ссылкой
+// подтверждения email или сброса пароля
+type AccountEmailEvent struct {
+	Type      string    `json:"type"`
+	Email     string    `json:"email"`
+	FirstName string    `json:"first_name"`
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// AccountEmailProducer публикует письма по учетной записи в Kafka
+type AccountEmailProducer struct {
+	writer *kafka.Writer
+	logger *zap.Logger
+}
+
+// NewAccountEmailProducer создает producer для топика писем по учетной записи
+func NewAccountEmailProducer(brokers []string, topic string, logger *zap.Logger) *AccountEmailProducer {
+	return &AccountEmailProducer{
+		writer: &kafka.Writer{
+			Addr:         kafka.TCP(brokers...),
+			Topic:        topic,
+			Balancer:     &kafka.Hash{}, // Письма одного адресата попадают в одну партицию
+			RequiredAcks: kafka.RequireOne,
+			MaxAttempts:  3,
+			ErrorLogger: kafka.LoggerFunc(func(msg string, args ...interface{}) {
+				logger.Error(fmt.Sprintf(msg, args...))
+			}),
+		},
+		logger: logger.Named("account_email_producer"),
+	}
+}
+
+// Send отправляет событие письма; ключ сообщения - email получателя
+func (p *AccountEmailProducer) Send(ctx context.Context, event *AccountEmailEvent) error {
+	value, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal account email event: %w", err)
+	}
+
+	if err := p.writer.WriteMessages(ctx, kafka.Message{
+		Key:   []byte(event.Email),
+		Value: value,
+	}); err != nil {
+		return fmt.Errorf("failed to write account email event: %w", err)
+	}
+
+	p.logger.Debug("account email event sent",
+		zap.String("type", event.Type),
+		zap.String("email", event.Email),
+	)
+	return nil
+}
+
+// Close закрывает Kafka writer
+func (p *AccountEmailProducer) Close() error {
+	return p.writer.Close()
+}
@@ -361,15 +361,35 @@ func (h *Hub) shutdown() {
 	h.logger.Info("Notification Hub stopped")
 }
 
-// GetStats возвращает статистику Hub
-func (h *Hub) GetStats() map[string]interface{} {
+// HubStats статистика Hub
+type HubStats struct {
+	TotalClients   int
+	MaxClients     int
+	BufferSize     int
+	BroadcastQueue int
+}
+
+// Stats возвращает статистику Hub
+func (h *Hub) Stats() HubStats {
 	h.mu.RLock()
 	defer h.mu.RUnlock()
 
+	return HubStats{
+		TotalClients:   len(h.clients),
+		MaxClients:     h.config.MaxClients,
+		BufferSize:     h.config.BufferSize,
+		BroadcastQueue: len(h.broadcast),
+	}
+}
+
+// GetStats возвращает статистику Hub
+func (h *Hub) GetStats() map[string]interface{} {
+	stats := h.Stats()
+
 	return map[string]interface{}{
-		"total_clients":    len(h.clients),
-		"max_clients":      h.config.MaxClients,
-		"buffer_size":      h.config.BufferSize,
-		"broadcast_queue":  len(h.broadcast),
+		"total_clients":   stats.TotalClients,
+		"max_clients":     stats.MaxClients,
+		"buffer_size":     stats.BufferSize,
+		"broadcast_queue": stats.BroadcastQueue,
 	}
 }
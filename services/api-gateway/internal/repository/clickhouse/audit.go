@@ -0,0 +1,178 @@
+package clickhouse
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/egrul-system/services/api-gateway/internal/graph/model"
+	"go.uber.org/zap"
+)
+
+// AuditRecord запись журнала аудита для вставки
+type AuditRecord struct {
+	Time          time.Time
+	UserID        string
+	UserEmail     string
+	APIKeyID      string
+	IPAddress     string
+	UserAgent     string
+	Action        string
+	EntityType    string
+	EntityID      string
+	Success       bool
+	Details       string
+	RetentionDays int
+}
+
+// AuditLogFilter фильтр выборки журнала аудита; пустые поля не ограничивают выборку
+type AuditLogFilter struct {
+	UserID string
+	Action string
+	From   *time.Time
+	// To верхняя граница (не включительно)
+	To *time.Time
+}
+
+// AuditRepository репозиторий журнала аудита (egrul.audit_log)
+type AuditRepository struct {
+	client *Client
+	logger *zap.Logger
+}
+
+// NewAuditRepository создает новый репозиторий журнала аудита
+func NewAuditRepository(client *Client, logger *zap.Logger) *AuditRepository {
+	return &AuditRepository{
+		client: client,
+		logger: logger.Named("audit_repo"),
+	}
+}
+
+// InsertBatch записывает пачку событий одним INSERT
+func (r *AuditRepository) InsertBatch(ctx context.Context, records []AuditRecord) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	batch, err := r.client.conn.PrepareBatch(ctx, `
+		INSERT INTO egrul.audit_log (
+			event_time, user_id, user_email, api_key_id, ip_address, user_agent,
+			action, entity_type, entity_id, success, details, retention_days
+		)`)
+	if err != nil {
+		return fmt.Errorf("prepare audit batch: %w", err)
+	}
+
+	for _, rec := range records {
+		var success uint8
+		if rec.Success {
+			success = 1
+		}
+		details := rec.Details
+		if details == "" {
+			details = "{}"
+		}
+		if err := batch.Append(
+			rec.Time, rec.UserID, rec.UserEmail, rec.APIKeyID, rec.IPAddress, rec.UserAgent,
+			rec.Action, rec.EntityType, rec.EntityID, success, details, uint16(rec.RetentionDays),
+		); err != nil {
+			_ = batch.Abort()
+			return fmt.Errorf("append audit record: %w", err)
+		}
+	}
+
+	if err := batch.Send(); err != nil {
+		return fmt.Errorf("send audit batch: %w", err)
+	}
+	return nil
+}
+
+// List возвращает страницу журнала (новые события первыми) и общее число записей
+func (r *AuditRepository) List(ctx context.Context, filter AuditLogFilter, limit, offset int) ([]*model.AuditLogEntry, int, error) {
+	conditions := []string{"1 = 1"}
+	var args []interface{}
+	if filter.UserID != "" {
+		conditions = append(conditions, "user_id = ?")
+		args = append(args, filter.UserID)
+	}
+	if filter.Action != "" {
+		conditions = append(conditions, "action = ?")
+		args = append(args, filter.Action)
+	}
+	if filter.From != nil {
+		conditions = append(conditions, "event_time >= ?")
+		args = append(args, *filter.From)
+	}
+	if filter.To != nil {
+		conditions = append(conditions, "event_time < ?")
+		args = append(args, *filter.To)
+	}
+	where := strings.Join(conditions, " AND ")
+
+	var total uint64
+	if err := r.client.conn.QueryRow(ctx, "SELECT count() FROM egrul.audit_log WHERE "+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("count audit log: %w", err)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT
+			toString(event_id), event_time, user_id, user_email, api_key_id, ip_address, user_agent,
+			action, entity_type, entity_id, success, details
+		FROM egrul.audit_log
+		WHERE %s
+		ORDER BY event_time DESC
+		LIMIT %d OFFSET %d
+	`, where, limit, offset)
+
+	rows, err := r.client.conn.Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("list audit log: %w", err)
+	}
+	defer rows.Close()
+
+	result := make([]*model.AuditLogEntry, 0, limit)
+	for rows.Next() {
+		var (
+			entry                                  model.AuditLogEntry
+			action                                 string
+			success                                uint8
+			userID, userEmail, apiKeyID, ipAddress string
+			userAgent, entityType, entityID        string
+			details                                string
+		)
+		if err := rows.Scan(
+			&entry.ID, &entry.Time, &userID, &userEmail, &apiKeyID, &ipAddress, &userAgent,
+			&action, &entityType, &entityID, &success, &details,
+		); err != nil {
+			return nil, 0, fmt.Errorf("scan audit record: %w", err)
+		}
+
+		entry.Action = model.AuditAction(action)
+		entry.Success = success == 1
+		entry.UserID = nonEmptyString(userID)
+		entry.UserEmail = nonEmptyString(userEmail)
+		entry.APIKeyID = nonEmptyString(apiKeyID)
+		entry.IPAddress = nonEmptyString(ipAddress)
+		entry.UserAgent = nonEmptyString(userAgent)
+		entry.EntityType = nonEmptyString(entityType)
+		entry.EntityID = nonEmptyString(entityID)
+		if details != "" && details != "{}" {
+			entry.Details = &details
+		}
+		result = append(result, &entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("iterate audit log: %w", err)
+	}
+
+	return result, int(total), nil
+}
+
+// nonEmptyString указатель на строку или nil для пустой строки
+func nonEmptyString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
@@ -0,0 +1,323 @@
+package clickhouse
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/egrul-system/services/api-gateway/internal/graph/model"
+	"go.uber.org/zap"
+)
+
+// Агрегаты изменений (миграции 012 и 024) создаются в базе default рядом с company_changes.
+// Регион и ОКВЭД в них не хранятся (кроме region_code в changes_by_region_mv), поэтому
+// при таком фильтре изменения берутся из company_changes / entrepreneur_changes.
+
+// GetChangesByDay возвращает число изменений по дням и типам из
+// company_changes_stats_mv и entrepreneur_changes_stats_mv.
+// Пустой changeTypes означает все типы.
+func (r *StatisticsRepository) GetChangesByDay(
+	ctx context.Context,
+	filter *model.StatsFilter,
+	changeTypes []string,
+	significantOnly bool,
+	from, to time.Time,
+) ([]*model.ChangesByDayPoint, error) {
+	typeCondition, typeArgs := changeTypesCondition(changeTypes)
+
+	var significant uint8
+	if significantOnly {
+		significant = 1
+	}
+
+	var query string
+	var args []interface{}
+
+	if entityFilter := entityOnlyFilter(filter); entityFilter != nil {
+		source, sourceArgs := filteredChangesSource(entityFilter)
+		query = fmt.Sprintf(`
+			SELECT
+				change_date,
+				entity_type,
+				change_type,
+				uniqExact(change_id) AS changes_count,
+				uniqExact(entity_id) AS affected_count
+			FROM %s
+			WHERE change_date >= ? AND change_date <= ?
+			  AND (is_significant = 1 OR ? = 0)
+			  AND %s
+			GROUP BY change_date, entity_type, change_type
+			ORDER BY change_date, entity_type, changes_count DESC
+		`, source, typeCondition)
+
+		args = append(sourceArgs, from, to, significant)
+		args = append(args, typeArgs...)
+	} else {
+		query = fmt.Sprintf(`
+			SELECT change_date, entity_type, change_type, changes_count, affected_count
+			FROM (
+				SELECT
+					change_date,
+					'company' AS entity_type,
+					change_type,
+					sum(changes_count) AS changes_count,
+					uniqMerge(affected_companies_count) AS affected_count
+				FROM default.company_changes_stats_mv
+				WHERE change_date >= ? AND change_date <= ?
+				  AND (is_significant = 1 OR ? = 0)
+				  AND %[1]s
+				GROUP BY change_date, change_type
+
+				UNION ALL
+
+				SELECT
+					change_date,
+					'entrepreneur' AS entity_type,
+					change_type,
+					sum(changes_count) AS changes_count,
+					uniqMerge(affected_entrepreneurs_count) AS affected_count
+				FROM default.entrepreneur_changes_stats_mv
+				WHERE change_date >= ? AND change_date <= ?
+				  AND (is_significant = 1 OR ? = 0)
+				  AND %[1]s
+				GROUP BY change_date, change_type
+			)
+			ORDER BY change_date, entity_type, changes_count DESC
+		`, typeCondition)
+
+		args = []interface{}{from, to, significant}
+		args = append(args, typeArgs...)
+		args = append(args, from, to, significant)
+		args = append(args, typeArgs...)
+	}
+
+	rows, err := r.client.conn.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("get changes by day: %w", err)
+	}
+	defer rows.Close()
+
+	result := make([]*model.ChangesByDayPoint, 0)
+	for rows.Next() {
+		var date time.Time
+		var entityType, changeType string
+		var changesCount, affectedCount uint64
+
+		if err := rows.Scan(&date, &entityType, &changeType, &changesCount, &affectedCount); err != nil {
+			return nil, fmt.Errorf("scan changes by day point: %w", err)
+		}
+
+		result = append(result, &model.ChangesByDayPoint{
+			Date:          date.Format("2006-01-02"),
+			EntityType:    model.EntityType(strings.ToUpper(entityType)),
+			ChangeType:    changeType,
+			ChangesCount:  int(changesCount),
+			AffectedCount: int(affectedCount),
+		})
+	}
+
+	return result, nil
+}
+
+// GetMostChangedCompanies возвращает компании с наибольшим числом изменений за месяц
+func (r *StatisticsRepository) GetMostChangedCompanies(ctx context.Context, filter *model.StatsFilter, month time.Time, limit int) ([]*model.MostChangedCompany, error) {
+	var query string
+	var args []interface{}
+
+	if entityFilter := entityOnlyFilter(filter); entityFilter != nil {
+		source, sourceArgs := filteredChangesSource(entityFilter)
+		query = `
+			SELECT
+				toStartOfMonth(change_date) AS detected_month,
+				entity_id AS ogrn,
+				any(entity_name) AS company_name,
+				uniqExact(change_id) AS total_changes,
+				uniqExactIf(change_id, is_significant = 1) AS significant_changes
+			FROM ` + source + `
+			WHERE entity_type = 'company'
+			  AND toStartOfMonth(change_date) = toStartOfMonth(?)
+			GROUP BY detected_month, ogrn
+			ORDER BY total_changes DESC, significant_changes DESC, ogrn
+			LIMIT ?
+		`
+		args = append(sourceArgs, month, limit)
+	} else {
+		query = `
+			SELECT
+				detected_month,
+				ogrn,
+				any(company_name) AS company_name,
+				sum(total_changes) AS total_changes,
+				sum(significant_changes_count) AS significant_changes
+			FROM default.company_most_changes_mv
+			WHERE detected_month = toStartOfMonth(?)
+			GROUP BY detected_month, ogrn
+			ORDER BY total_changes DESC, significant_changes DESC, ogrn
+			LIMIT ?
+		`
+		args = []interface{}{month, limit}
+	}
+
+	rows, err := r.client.conn.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("get most changed companies: %w", err)
+	}
+	defer rows.Close()
+
+	result := make([]*model.MostChangedCompany, 0)
+	for rows.Next() {
+		var detectedMonth time.Time
+		var ogrn, companyName string
+		var totalChanges, significantChanges uint64
+
+		if err := rows.Scan(&detectedMonth, &ogrn, &companyName, &totalChanges, &significantChanges); err != nil {
+			return nil, fmt.Errorf("scan most changed company: %w", err)
+		}
+
+		result = append(result, &model.MostChangedCompany{
+			Month:              detectedMonth.Format("2006-01-02"),
+			Ogrn:               ogrn,
+			CompanyName:        companyName,
+			TotalChanges:       int(totalChanges),
+			SignificantChanges: int(significantChanges),
+		})
+	}
+
+	r.logger.Debug("GetMostChangedCompanies completed",
+		zap.Time("month", month),
+		zap.Int("count", len(result)),
+	)
+	return result, nil
+}
+
+// GetSignificantChangesByRegion возвращает значимые изменения по регионам и типам за период.
+// Изменения без региона (сохраненные до миграции 024) не учитываются.
+func (r *StatisticsRepository) GetSignificantChangesByRegion(
+	ctx context.Context,
+	filter *model.StatsFilter,
+	changeTypes []string,
+	from, to time.Time,
+) ([]*model.RegionChangesStatistics, error) {
+	typeCondition, typeArgs := changeTypesCondition(changeTypes)
+	region := filterRegion(filter)
+
+	var query string
+	var args []interface{}
+
+	if filterOkved(filter) != "" {
+		source, sourceArgs := filteredChangesSource(entityOnlyFilter(filter))
+		query = fmt.Sprintf(`
+			SELECT
+				region_code,
+				entity_type,
+				change_type,
+				uniqExact(change_id) AS changes_count,
+				uniqExact(entity_id) AS affected_count
+			FROM %s
+			WHERE is_significant = 1
+			  AND change_date >= toStartOfMonth(?) AND change_date <= ?
+			  AND region_code != ''
+			  AND %s
+			GROUP BY region_code, entity_type, change_type
+			ORDER BY region_code, entity_type, changes_count DESC
+		`, source, typeCondition)
+
+		args = append(sourceArgs, from, to)
+		args = append(args, typeArgs...)
+	} else {
+		query = fmt.Sprintf(`
+			SELECT
+				region_code,
+				entity_type,
+				change_type,
+				sum(changes_count) AS changes_count,
+				uniqMerge(affected_entities_count) AS affected_count
+			FROM default.changes_by_region_mv
+			WHERE change_month >= toStartOfMonth(?) AND change_month <= ?
+			  AND region_code != ''
+			  AND (region_code = ? OR ? = '')
+			  AND %s
+			GROUP BY region_code, entity_type, change_type
+			ORDER BY region_code, entity_type, changes_count DESC
+		`, typeCondition)
+
+		args = []interface{}{from, to, region, region}
+		args = append(args, typeArgs...)
+	}
+
+	rows, err := r.client.conn.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("get significant changes by region: %w", err)
+	}
+	defer rows.Close()
+
+	result := make([]*model.RegionChangesStatistics, 0)
+	for rows.Next() {
+		var regionCode, entityType, changeType string
+		var changesCount, affectedCount uint64
+
+		if err := rows.Scan(&regionCode, &entityType, &changeType, &changesCount, &affectedCount); err != nil {
+			return nil, fmt.Errorf("scan region changes statistics: %w", err)
+		}
+
+		result = append(result, &model.RegionChangesStatistics{
+			RegionCode:    regionCode,
+			EntityType:    model.EntityType(strings.ToUpper(entityType)),
+			ChangeType:    changeType,
+			ChangesCount:  int(changesCount),
+			AffectedCount: int(affectedCount),
+		})
+	}
+
+	return result, nil
+}
+
+// filteredChangesSource объединяет изменения компаний и ИП, подходящих под фильтр
+// по региону и ОКВЭД, в подзапрос с общими колонками
+func filteredChangesSource(filter *model.StatsFilter) (string, []interface{}) {
+	companies, companyArgs := filteredCompaniesSubquery(filter)
+	entrepreneurs, entrepreneurArgs := filteredEntrepreneursSubquery(filter)
+
+	source := `(
+		SELECT
+			toDate(detected_at) AS change_date,
+			'company' AS entity_type,
+			ogrn AS entity_id,
+			company_name AS entity_name,
+			region_code,
+			change_type,
+			change_id,
+			is_significant
+		FROM default.company_changes
+		WHERE ogrn GLOBAL IN (` + companies + `)
+
+		UNION ALL
+
+		SELECT
+			toDate(detected_at) AS change_date,
+			'entrepreneur' AS entity_type,
+			ogrnip AS entity_id,
+			full_name AS entity_name,
+			region_code,
+			change_type,
+			change_id,
+			is_significant
+		FROM default.entrepreneur_changes
+		WHERE ogrnip GLOBAL IN (` + entrepreneurs + `)
+	)`
+
+	return source, append(companyArgs, entrepreneurArgs...)
+}
+
+// changeTypesCondition строит условие на change_type; пустой список не ограничивает выборку
+func changeTypesCondition(changeTypes []string) (string, []interface{}) {
+	if len(changeTypes) == 0 {
+		return "1 = 1", nil
+	}
+	args := make([]interface{}, len(changeTypes))
+	for i, t := range changeTypes {
+		args[i] = t
+	}
+	return "change_type IN (?" + strings.Repeat(", ?", len(changeTypes)-1) + ")", args
+}
@@ -860,9 +860,11 @@ func (r *CompanyRepository) buildWhereClause(filter *model.CompanyFilter) (strin
 		conditions = append(conditions, "region ILIKE ?")
 		args = append(args, "%"+*filter.Region+"%")
 	}
-	if filter.Okved != nil && *filter.Okved != "" {
-		conditions = append(conditions, "(okved_main_code = ? OR has(okved_additional, ?))")
-		args = append(args, *filter.Okved, *filter.Okved)
+	if filter.Okved != nil && strings.TrimSpace(*filter.Okved) != "" {
+		// Префикс кода ("62" включает "62.01") или буква раздела, основной или дополнительный ОКВЭД
+		cond, okvedArgs := okvedPrefixCondition(strings.ToUpper(strings.TrimSpace(*filter.Okved)), companyKey)
+		conditions = append(conditions, cond)
+		args = append(args, okvedArgs...)
 	}
 	// Фильтрация по текстовому статусу (старый вариант, оставляем для обратной совместимости)
 	if filter.Status != nil {
@@ -914,6 +916,10 @@ func (r *CompanyRepository) buildWhereClause(filter *model.CompanyFilter) (strin
 		conditions = append(conditions, "capital_amount <= ?")
 		args = append(args, *filter.CapitalMax)
 	}
+	if filter.OpfCode != nil && *filter.OpfCode != "" {
+		conditions = append(conditions, "opf_code = ?")
+		args = append(args, *filter.OpfCode)
+	}
 	if filter.IsBankrupt != nil {
 		if *filter.IsBankrupt {
 			conditions = append(conditions, "is_bankrupt = 1")
@@ -600,9 +600,11 @@ func (r *EntrepreneurRepository) buildWhereClause(filter *model.EntrepreneurFilt
 		conditions = append(conditions, "region ILIKE ?")
 		args = append(args, "%"+*filter.Region+"%")
 	}
-	if filter.Okved != nil && *filter.Okved != "" {
-		conditions = append(conditions, "(okved_main_code = ? OR has(okved_additional, ?))")
-		args = append(args, *filter.Okved, *filter.Okved)
+	if filter.Okved != nil && strings.TrimSpace(*filter.Okved) != "" {
+		// Префикс кода ("62" включает "62.01") или буква раздела, основной или дополнительный ОКВЭД
+		cond, okvedArgs := okvedPrefixCondition(strings.ToUpper(strings.TrimSpace(*filter.Okved)), entrepreneurKey)
+		conditions = append(conditions, cond)
+		args = append(args, okvedArgs...)
 	}
 	// Фильтрация по текстовому статусу (старый вариант, оставляем для обратной совместимости)
 	if filter.Status != nil {
@@ -0,0 +1,186 @@
+package clickhouse
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/egrul-system/services/api-gateway/internal/graph/model"
+	"go.uber.org/zap"
+)
+
+// ExportColumn колонка выгрузки: публичное имя, заголовок и SQL выражение
+type ExportColumn struct {
+	Name   string
+	Header string
+	Expr   string
+}
+
+// CompanyExportColumns доступные колонки выгрузки компаний (в порядке по умолчанию)
+var CompanyExportColumns = []ExportColumn{
+	{"ogrn", "ОГРН", "ogrn"},
+	{"inn", "ИНН", "inn"},
+	{"kpp", "КПП", "kpp"},
+	{"fullName", "Полное наименование", "full_name"},
+	{"shortName", "Краткое наименование", "short_name"},
+	{"legalForm", "ОПФ", "opf_name"},
+	{"status", "Статус", "status"},
+	{"statusCode", "Код статуса", "status_code"},
+	{"registrationDate", "Дата регистрации", "registration_date"},
+	{"terminationDate", "Дата прекращения", "termination_date"},
+	{"regionCode", "Код региона", "region_code"},
+	{"region", "Регион", "region"},
+	{"address", "Адрес", "full_address"},
+	{"mainActivityCode", "ОКВЭД", "okved_main_code"},
+	{"mainActivityName", "Наименование ОКВЭД", "okved_main_name"},
+	{"capital", "Уставный капитал", "capital_amount"},
+	{"director", "Руководитель", "trim(concat(ifNull(head_last_name, ''), ' ', ifNull(head_first_name, ''), ' ', ifNull(head_middle_name, '')))"},
+	{"directorInn", "ИНН руководителя", "head_inn"},
+	{"directorPosition", "Должность руководителя", "head_position"},
+	{"foundersCount", "Учредителей", "founders_count"},
+	{"isBankrupt", "Банкротство", "is_bankrupt"},
+	{"isLiquidating", "Ликвидация", "is_liquidating"},
+	{"email", "Email", "email"},
+	{"extractDate", "Дата выписки", "extract_date"},
+}
+
+// EntrepreneurExportColumns доступные колонки выгрузки ИП (в порядке по умолчанию)
+var EntrepreneurExportColumns = []ExportColumn{
+	{"ogrnip", "ОГРНИП", "ogrnip"},
+	{"inn", "ИНН", "inn"},
+	{"fullName", "ФИО", "trim(concat(last_name, ' ', first_name, ' ', ifNull(middle_name, '')))"},
+	{"status", "Статус", "status"},
+	{"statusCode", "Код статуса", "status_code"},
+	{"registrationDate", "Дата регистрации", "registration_date"},
+	{"terminationDate", "Дата прекращения", "termination_date"},
+	{"regionCode", "Код региона", "region_code"},
+	{"region", "Регион", "region"},
+	{"address", "Адрес", "full_address"},
+	{"mainActivityCode", "ОКВЭД", "okved_main_code"},
+	{"mainActivityName", "Наименование ОКВЭД", "okved_main_name"},
+	{"citizenship", "Гражданство", "citizenship_country_name"},
+	{"isBankrupt", "Банкротство", "is_bankrupt"},
+	{"email", "Email", "email"},
+	{"extractDate", "Дата выписки", "extract_date"},
+}
+
+// ResolveExportColumns выбирает колонки по публичным именам. Пустой список означает все колонки.
+func ResolveExportColumns(available []ExportColumn, names []string) ([]ExportColumn, error) {
+	if len(names) == 0 {
+		return available, nil
+	}
+
+	byName := make(map[string]ExportColumn, len(available))
+	for _, c := range available {
+		byName[c.Name] = c
+	}
+
+	columns := make([]ExportColumn, 0, len(names))
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		c, ok := byName[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown export column: %s", name)
+		}
+		columns = append(columns, c)
+	}
+	if len(columns) == 0 {
+		return available, nil
+	}
+	return columns, nil
+}
+
+// ExportRowFunc вызывается для каждой выгружаемой строки
+type ExportRowFunc func(values []string) error
+
+// Export потоково выгружает компании по фильтру, не загружая результат в память целиком
+func (r *CompanyRepository) Export(ctx context.Context, filter *model.CompanyFilter, columns []ExportColumn, limit int, fn ExportRowFunc) (int, error) {
+	var founderCondition string
+	var founderArgs []interface{}
+	whereFilter := filter
+	if filter != nil && filter.FounderName != nil && strings.TrimSpace(*filter.FounderName) != "" {
+		pattern := "%" + escapeLike(strings.TrimSpace(*filter.FounderName)) + "%"
+		founderCondition = `ogrn IN (
+			SELECT company_ogrn FROM egrul.founders FINAL
+			WHERE founder_name ILIKE ? OR founder_last_name ILIKE ?
+			   OR founder_first_name ILIKE ? OR founder_middle_name ILIKE ?
+		)`
+		founderArgs = []interface{}{pattern, pattern, pattern, pattern}
+
+		filterCopy := *filter
+		filterCopy.FounderName = nil
+		whereFilter = &filterCopy
+	}
+
+	whereClause, args := r.buildWhereClause(whereFilter)
+	if founderCondition != "" {
+		if whereClause == "" {
+			whereClause = "WHERE " + founderCondition
+		} else {
+			whereClause += " AND " + founderCondition
+		}
+		args = append(args, founderArgs...)
+	}
+
+	return exportQuery(ctx, r.client, r.logger, "egrul.companies", "ogrn", whereClause, args, columns, limit, fn)
+}
+
+// Export потоково выгружает ИП по фильтру, не загружая результат в память целиком
+func (r *EntrepreneurRepository) Export(ctx context.Context, filter *model.EntrepreneurFilter, columns []ExportColumn, limit int, fn ExportRowFunc) (int, error) {
+	whereClause, args := r.buildWhereClause(filter)
+	return exportQuery(ctx, r.client, r.logger, "egrul.entrepreneurs", "ogrnip", whereClause, args, columns, limit, fn)
+}
+
+// exportQuery выполняет запрос выгрузки и передает строки по мере чтения блоков из ClickHouse.
+// Все колонки приводятся к строке на стороне ClickHouse, чтобы сканирование было единообразным.
+func exportQuery(ctx context.Context, client *Client, logger *zap.Logger, table, orderBy, whereClause string, args []interface{}, columns []ExportColumn, limit int, fn ExportRowFunc) (int, error) {
+	selectExprs := make([]string, len(columns))
+	for i, c := range columns {
+		selectExprs[i] = fmt.Sprintf("ifNull(toString(%s), '')", c.Expr)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s FROM %s FINAL
+		%s
+		ORDER BY %s
+		LIMIT %d
+	`, strings.Join(selectExprs, ", "), table, whereClause, orderBy, limit)
+
+	rows, err := client.conn.Query(ctx, query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("export query: %w", err)
+	}
+	defer rows.Close()
+
+	values := make([]string, len(columns))
+	dest := make([]interface{}, len(columns))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+
+	count := 0
+	for rows.Next() {
+		if err := rows.Scan(dest...); err != nil {
+			return count, fmt.Errorf("scan export row: %w", err)
+		}
+		if err := fn(values); err != nil {
+			return count, err
+		}
+		count++
+	}
+	if err := rows.Err(); err != nil {
+		return count, fmt.Errorf("iterate export rows: %w", err)
+	}
+
+	logger.Debug("export completed", zap.String("table", table), zap.Int("rows", count))
+	return count, nil
+}
+
+// escapeLike экранирует спецсимволы шаблона LIKE
+func escapeLike(s string) string {
+	s = strings.ReplaceAll(s, "%", "\\%")
+	return strings.ReplaceAll(s, "_", "\\_")
+}
@@ -0,0 +1,190 @@
+package clickhouse
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/egrul-system/services/api-gateway/internal/graph/model"
+	"go.uber.org/zap"
+)
+
+// massAddressKeyExpr вычисляет ключ адреса компании: код ФИАС, а при его отсутствии
+// нормализованную строку адреса. Должно совпадать с ADDRESS_KEY_EXPR в
+// infrastructure/scripts/refresh-risk-features.sh, которым заполняется egrul.mass_addresses.
+const massAddressKeyExpr = `if(ifNull(fias_id, '') != '', concat('fias:', fias_id), ` +
+	`concat('addr:', trimBoth(replaceRegexpAll(lowerUTF8(ifNull(full_address, '')), '[^0-9a-zа-яё]+', ' '))))`
+
+const massAddressColumns = `address_key, fias_id, full_address, region_code, companies_count, active_count, updated_at`
+
+const massDirectorColumns = `director_inn, director_name, companies_count, active_count, updated_at`
+
+// GetMassAddressByCompany возвращает агрегат по адресу регистрации компании.
+// Возвращает nil, если у компании нет адреса или агрегаты еще не рассчитаны.
+func (r *RiskRepository) GetMassAddressByCompany(ctx context.Context, ogrn string) (*model.MassAddress, error) {
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM egrul.mass_addresses FINAL
+		WHERE address_key GLOBAL IN (
+			SELECT %s FROM egrul.companies FINAL WHERE ogrn = ?
+		)
+		LIMIT 1
+	`, massAddressColumns, massAddressKeyExpr)
+
+	addresses, err := r.queryMassAddresses(ctx, query, ogrn)
+	if err != nil {
+		return nil, err
+	}
+	if len(addresses) == 0 {
+		return nil, nil
+	}
+	return addresses[0], nil
+}
+
+// ListMassAddresses возвращает адреса, по которым зарегистрировано не меньше minCount
+// компаний, по убыванию числа компаний
+func (r *RiskRepository) ListMassAddresses(ctx context.Context, regionCode *string, minCount, limit, offset int) ([]*model.MassAddress, error) {
+	conditions := []string{"companies_count >= ?"}
+	args := []interface{}{minCount}
+	if regionCode != nil && *regionCode != "" {
+		conditions = append(conditions, "region_code = ?")
+		args = append(args, *regionCode)
+	}
+	args = append(args, limit, offset)
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM egrul.mass_addresses FINAL
+		WHERE %s
+		ORDER BY companies_count DESC, address_key
+		LIMIT ? OFFSET ?
+	`, massAddressColumns, strings.Join(conditions, " AND "))
+
+	addresses, err := r.queryMassAddresses(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	r.logger.Debug("ListMassAddresses completed",
+		zap.Int("min_count", minCount),
+		zap.Int("count", len(addresses)),
+	)
+	return addresses, nil
+}
+
+// GetMassDirector возвращает агрегат по руководителю с ИНН inn.
+// Возвращает nil, если руководитель не найден.
+func (r *RiskRepository) GetMassDirector(ctx context.Context, inn string) (*model.MassDirector, error) {
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM egrul.mass_directors FINAL
+		WHERE director_inn = ?
+		LIMIT 1
+	`, massDirectorColumns)
+
+	directors, err := r.queryMassDirectors(ctx, query, inn)
+	if err != nil {
+		return nil, err
+	}
+	if len(directors) == 0 {
+		return nil, nil
+	}
+	return directors[0], nil
+}
+
+// ListMassDirectors возвращает руководителей не менее minCount компаний,
+// по убыванию числа компаний
+func (r *RiskRepository) ListMassDirectors(ctx context.Context, minCount, limit, offset int) ([]*model.MassDirector, error) {
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM egrul.mass_directors FINAL
+		WHERE companies_count >= ?
+		ORDER BY companies_count DESC, director_inn
+		LIMIT ? OFFSET ?
+	`, massDirectorColumns)
+
+	directors, err := r.queryMassDirectors(ctx, query, minCount, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	r.logger.Debug("ListMassDirectors completed",
+		zap.Int("min_count", minCount),
+		zap.Int("count", len(directors)),
+	)
+	return directors, nil
+}
+
+func (r *RiskRepository) queryMassAddresses(ctx context.Context, query string, args ...interface{}) ([]*model.MassAddress, error) {
+	rows, err := r.client.conn.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query mass addresses: %w", err)
+	}
+	defer rows.Close()
+
+	var addresses []*model.MassAddress
+	for rows.Next() {
+		var (
+			addressKey, fullAddress, regionCode string
+			fiasID                              sql.NullString
+			companiesCount, activeCount         uint32
+			updatedAt                           time.Time
+		)
+		if err := rows.Scan(&addressKey, &fiasID, &fullAddress, &regionCode, &companiesCount, &activeCount, &updatedAt); err != nil {
+			return nil, fmt.Errorf("scan mass address row: %w", err)
+		}
+		address := &model.MassAddress{
+			AddressKey:     addressKey,
+			FullAddress:    fullAddress,
+			CompaniesCount: int(companiesCount),
+			ActiveCount:    int(activeCount),
+			UpdatedAt:      updatedAt,
+		}
+		if fiasID.Valid && fiasID.String != "" {
+			address.FiasID = &fiasID.String
+		}
+		if regionCode != "" {
+			address.RegionCode = &regionCode
+		}
+		addresses = append(addresses, address)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate mass address rows: %w", err)
+	}
+
+	return addresses, nil
+}
+
+func (r *RiskRepository) queryMassDirectors(ctx context.Context, query string, args ...interface{}) ([]*model.MassDirector, error) {
+	rows, err := r.client.conn.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query mass directors: %w", err)
+	}
+	defer rows.Close()
+
+	var directors []*model.MassDirector
+	for rows.Next() {
+		var (
+			inn, name                   string
+			companiesCount, activeCount uint32
+			updatedAt                   time.Time
+		)
+		if err := rows.Scan(&inn, &name, &companiesCount, &activeCount, &updatedAt); err != nil {
+			return nil, fmt.Errorf("scan mass director row: %w", err)
+		}
+		directors = append(directors, &model.MassDirector{
+			Inn:            inn,
+			Name:           name,
+			CompaniesCount: int(companiesCount),
+			ActiveCount:    int(activeCount),
+			UpdatedAt:      updatedAt,
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate mass director rows: %w", err)
+	}
+
+	return directors, nil
+}
@@ -0,0 +1,60 @@
+package clickhouse
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/egrul-system/services/api-gateway/internal/graph/model"
+)
+
+// GetOkvedChildren возвращает дочерние узлы справочника ОКВЭД2 (миграция 026).
+// Пустой parentCode - разделы A-U. Справочник не шардируется, поэтому читается
+// из локальной таблицы.
+func (r *StatisticsRepository) GetOkvedChildren(ctx context.Context, parentCode string) ([]*model.OkvedNode, error) {
+	query := `
+		SELECT
+			code,
+			name,
+			parent_code,
+			level,
+			section,
+			code IN (
+				SELECT parent_code FROM egrul.okved_classifier_local FINAL WHERE parent_code != ''
+			) AS has_children
+		FROM egrul.okved_classifier_local FINAL
+		WHERE parent_code = ?
+		ORDER BY code
+	`
+
+	rows, err := r.client.conn.Query(ctx, query, parentCode)
+	if err != nil {
+		return nil, fmt.Errorf("get okved children: %w", err)
+	}
+	defer rows.Close()
+
+	result := []*model.OkvedNode{}
+	for rows.Next() {
+		var code, name, parent, level, section string
+		var hasChildren bool
+		if err := rows.Scan(&code, &name, &parent, &level, &section, &hasChildren); err != nil {
+			return nil, fmt.Errorf("scan okved node: %w", err)
+		}
+
+		node := &model.OkvedNode{
+			Code:        code,
+			Name:        name,
+			Level:       model.OkvedLevel(level),
+			Section:     section,
+			HasChildren: hasChildren,
+		}
+		if parent != "" {
+			node.ParentCode = &parent
+		}
+		if node.Name == "" && node.Level == model.OkvedLevelSection {
+			node.Name = model.OkvedSectionName(code)
+		}
+		result = append(result, node)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,380 @@
+package clickhouse
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/egrul-system/services/api-gateway/internal/graph/model"
+	"go.uber.org/zap"
+)
+
+// PersonRepository репозиторий ролей физических лиц: руководителей (head_* в companies)
+// и учредителей (founders, ownership_graph)
+type PersonRepository struct {
+	client *Client
+	logger *zap.Logger
+}
+
+// NewPersonRepository создает новый репозиторий физических лиц
+func NewPersonRepository(client *Client, logger *zap.Logger) *PersonRepository {
+	return &PersonRepository{
+		client: client,
+		logger: logger.Named("person_repo"),
+	}
+}
+
+// headFullNameExpr ФИО руководителя одной строкой
+const headFullNameExpr = `trimBoth(concat(ifNull(head_last_name, ''), ' ', ifNull(head_first_name, ''), ' ', ifNull(head_middle_name, '')))`
+
+// GetDirectorships возвращает компании, в которых лицо с ИНН inn является руководителем
+func (r *PersonRepository) GetDirectorships(ctx context.Context, inn string, limit int) ([]*model.PersonDirectorship, string, error) {
+	query := fmt.Sprintf(`
+		SELECT ogrn, inn, full_name, status, head_position, head_position_code, %s AS head_name
+		FROM egrul.companies FINAL
+		WHERE head_inn = ?
+		ORDER BY status = 'active' DESC, full_name
+		LIMIT ?
+	`, headFullNameExpr)
+
+	rows, err := r.client.conn.Query(ctx, query, inn, limit)
+	if err != nil {
+		return nil, "", fmt.Errorf("query directorships: %w", err)
+	}
+	defer rows.Close()
+
+	var (
+		result []*model.PersonDirectorship
+		name   string
+	)
+	for rows.Next() {
+		var (
+			ogrn, companyInn, fullName, status, headName string
+			position, positionCode                       sql.NullString
+		)
+		if err := rows.Scan(&ogrn, &companyInn, &fullName, &status, &position, &positionCode, &headName); err != nil {
+			return nil, "", fmt.Errorf("scan directorship row: %w", err)
+		}
+		d := &model.PersonDirectorship{
+			Ogrn:        ogrn,
+			CompanyName: fullName,
+			Status:      parseRawStatus(status),
+		}
+		if companyInn != "" {
+			d.Inn = &companyInn
+		}
+		if position.Valid {
+			d.Position = &position.String
+		}
+		if positionCode.Valid {
+			d.PositionCode = &positionCode.String
+		}
+		if name == "" {
+			name = headName
+		}
+		result = append(result, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, "", fmt.Errorf("iterate directorship rows: %w", err)
+	}
+
+	return result, name, nil
+}
+
+// GetFoundings возвращает компании, в которых лицо с ИНН inn является учредителем
+func (r *PersonRepository) GetFoundings(ctx context.Context, inn string, limit int) ([]*model.PersonFounding, string, error) {
+	query := `
+		SELECT
+			f.company_ogrn, ifNull(f.company_inn, ''), ifNull(f.company_name, ''),
+			ifNull(c.status, 'unknown'), f.share_percent, f.share_nominal_value, f.founder_name
+		FROM (
+			SELECT company_ogrn, company_inn, company_name, share_percent, share_nominal_value, founder_name
+			FROM egrul.founders FINAL
+			WHERE founder_inn = ? AND founder_type = 'person'
+		) AS f
+		GLOBAL LEFT JOIN (
+			SELECT ogrn, status FROM egrul.companies FINAL
+			WHERE ogrn GLOBAL IN (SELECT company_ogrn FROM egrul.founders WHERE founder_inn = ?)
+		) AS c ON f.company_ogrn = c.ogrn
+		ORDER BY f.share_percent DESC NULLS LAST, f.company_name
+		LIMIT ?
+		SETTINGS join_use_nulls = 1
+	`
+
+	rows, err := r.client.conn.Query(ctx, query, inn, inn, limit)
+	if err != nil {
+		return nil, "", fmt.Errorf("query foundings: %w", err)
+	}
+	defer rows.Close()
+
+	var (
+		result []*model.PersonFounding
+		name   string
+	)
+	for rows.Next() {
+		var (
+			ogrn, companyInn, companyName, status, founderName string
+			sharePercent, shareNominal                         sql.NullFloat64
+		)
+		if err := rows.Scan(&ogrn, &companyInn, &companyName, &status, &sharePercent, &shareNominal, &founderName); err != nil {
+			return nil, "", fmt.Errorf("scan founding row: %w", err)
+		}
+		f := &model.PersonFounding{
+			Ogrn:        ogrn,
+			CompanyName: companyName,
+			Status:      parseRawStatus(status),
+		}
+		if companyInn != "" {
+			f.Inn = &companyInn
+		}
+		if sharePercent.Valid {
+			f.SharePercent = &sharePercent.Float64
+		}
+		if shareNominal.Valid {
+			f.ShareNominalValue = &shareNominal.Float64
+		}
+		if name == "" {
+			name = founderName
+		}
+		result = append(result, f)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, "", fmt.Errorf("iterate founding rows: %w", err)
+	}
+
+	return result, name, nil
+}
+
+// GetDirectorVersions возвращает все сохраненные версии карточек компаний, в которых
+// лицо когда-либо было руководителем, с признаком, руководит ли оно в этой версии
+func (r *PersonRepository) GetDirectorVersions(ctx context.Context, inn string) ([]*model.PersonRolePoint, error) {
+	query := `
+		SELECT ogrn, full_name, extract_date, ifNull(head_inn, '') = ? AS present, head_position
+		FROM egrul.companies_versions FINAL
+		WHERE ogrn GLOBAL IN (SELECT ogrn FROM egrul.companies_versions WHERE head_inn = ?)
+		ORDER BY ogrn, extract_date
+	`
+
+	rows, err := r.client.conn.Query(ctx, query, inn, inn)
+	if err != nil {
+		return nil, fmt.Errorf("query director versions: %w", err)
+	}
+	defer rows.Close()
+
+	var points []*model.PersonRolePoint
+	for rows.Next() {
+		var (
+			ogrn, fullName string
+			extractDate    time.Time
+			present        bool
+			position       sql.NullString
+		)
+		if err := rows.Scan(&ogrn, &fullName, &extractDate, &present, &position); err != nil {
+			return nil, fmt.Errorf("scan director version row: %w", err)
+		}
+		point := &model.PersonRolePoint{
+			Ogrn:        ogrn,
+			CompanyName: fullName,
+			Date:        model.Date{Time: extractDate},
+			Present:     present,
+		}
+		if position.Valid {
+			point.Position = &position.String
+		}
+		points = append(points, point)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate director version rows: %w", err)
+	}
+
+	return points, nil
+}
+
+// GetFounderVersions возвращает составы учредителей по версиям для компаний, в которых
+// лицо когда-либо было учредителем, с признаком его присутствия в составе
+func (r *PersonRepository) GetFounderVersions(ctx context.Context, inn string) ([]*model.PersonRolePoint, error) {
+	query := `
+		SELECT
+			company_ogrn,
+			any(ifNull(company_name, '')),
+			version_date,
+			max(founder_inn = ?) AS present,
+			maxIf(share_percent, founder_inn = ?)
+		FROM egrul.founders_versions FINAL
+		WHERE company_ogrn GLOBAL IN (
+			SELECT company_ogrn FROM egrul.founders_versions
+			WHERE founder_inn = ? AND founder_type = 'person'
+		)
+		GROUP BY company_ogrn, version_date
+		ORDER BY company_ogrn, version_date
+	`
+
+	rows, err := r.client.conn.Query(ctx, query, inn, inn, inn)
+	if err != nil {
+		return nil, fmt.Errorf("query founder versions: %w", err)
+	}
+	defer rows.Close()
+
+	var points []*model.PersonRolePoint
+	for rows.Next() {
+		var (
+			ogrn, companyName string
+			versionDate       time.Time
+			present           bool
+			sharePercent      sql.NullFloat64
+		)
+		if err := rows.Scan(&ogrn, &companyName, &versionDate, &present, &sharePercent); err != nil {
+			return nil, fmt.Errorf("scan founder version row: %w", err)
+		}
+		point := &model.PersonRolePoint{
+			Ogrn:        ogrn,
+			CompanyName: companyName,
+			Date:        model.Date{Time: versionDate},
+			Present:     present,
+		}
+		if sharePercent.Valid {
+			point.SharePercent = &sharePercent.Float64
+		}
+		points = append(points, point)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate founder version rows: %w", err)
+	}
+
+	return points, nil
+}
+
+// GetOwnershipRoles возвращает периоды владения лица из графа владения
+func (r *PersonRepository) GetOwnershipRoles(ctx context.Context, inn string) ([]*model.PersonRole, error) {
+	query := `
+		SELECT target_ogrn, ifNull(target_name, ''), share_percent, start_date, end_date, is_active
+		FROM egrul.ownership_graph FINAL
+		WHERE owner_inn = ? AND owner_type = 'person'
+		ORDER BY start_date NULLS LAST, target_ogrn
+	`
+
+	rows, err := r.client.conn.Query(ctx, query, inn)
+	if err != nil {
+		return nil, fmt.Errorf("query ownership roles: %w", err)
+	}
+	defer rows.Close()
+
+	var roles []*model.PersonRole
+	for rows.Next() {
+		var (
+			ogrn, name         string
+			sharePercent       sql.NullFloat64
+			startDate, endDate sql.NullTime
+			isActive           uint8
+		)
+		if err := rows.Scan(&ogrn, &name, &sharePercent, &startDate, &endDate, &isActive); err != nil {
+			return nil, fmt.Errorf("scan ownership role row: %w", err)
+		}
+		role := &model.PersonRole{
+			Role:        model.PersonRoleTypeFounder,
+			Ogrn:        ogrn,
+			CompanyName: name,
+			IsCurrent:   isActive == 1,
+			Source:      model.PersonRoleSourceOwnership,
+		}
+		if sharePercent.Valid {
+			role.SharePercent = &sharePercent.Float64
+		}
+		if startDate.Valid {
+			role.StartDate = model.NewDate(startDate.Time)
+		}
+		if endDate.Valid {
+			role.EndDate = model.NewDate(endDate.Time)
+		}
+		roles = append(roles, role)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate ownership role rows: %w", err)
+	}
+
+	return roles, nil
+}
+
+// SearchCandidates ищет физических лиц по началу ФИО среди руководителей, учредителей
+// и ИП. Однофамильцы различаются по ИНН; лица без ИНН не возвращаются.
+func (r *PersonRepository) SearchCandidates(ctx context.Context, name string, limit int) ([]*model.PersonCandidate, error) {
+	pattern := escapeLike(strings.ToLower(strings.Join(strings.Fields(name), " "))) + "%"
+
+	query := fmt.Sprintf(`
+		SELECT
+			inn,
+			anyHeavy(person_name) AS name,
+			toUInt32(sum(is_director)) AS director_count,
+			toUInt32(sum(is_founder)) AS founder_count,
+			max(is_entrepreneur) AS is_entrepreneur,
+			groupUniqArrayIf(5)(region, region != '') AS regions,
+			groupUniqArrayIf(3)(company, company != '') AS companies
+		FROM (
+			SELECT ifNull(head_inn, '') AS inn, %s AS person_name,
+				1 AS is_director, 0 AS is_founder, 0 AS is_entrepreneur,
+				ifNull(region_code, '') AS region, full_name AS company
+			FROM egrul.companies FINAL
+			WHERE ifNull(head_inn, '') != '' AND lowerUTF8(%s) LIKE ?
+			UNION ALL
+			SELECT founder_inn, founder_name, 0, 1, 0, '', ifNull(company_name, '')
+			FROM egrul.founders FINAL
+			WHERE founder_type = 'person' AND founder_inn != '' AND lowerUTF8(founder_name) LIKE ?
+			UNION ALL
+			SELECT inn, trimBoth(concat(last_name, ' ', first_name, ' ', ifNull(middle_name, ''))), 0, 0, 1,
+				ifNull(region_code, ''), ''
+			FROM egrul.entrepreneurs FINAL
+			WHERE inn != '' AND lowerUTF8(trimBoth(concat(last_name, ' ', first_name, ' ', ifNull(middle_name, '')))) LIKE ?
+		)
+		GROUP BY inn
+		ORDER BY director_count + founder_count DESC, inn
+		LIMIT ?
+	`, headFullNameExpr, headFullNameExpr)
+
+	rows, err := r.client.conn.Query(ctx, query, pattern, pattern, pattern, limit)
+	if err != nil {
+		return nil, fmt.Errorf("query person candidates: %w", err)
+	}
+	defer rows.Close()
+
+	var candidates []*model.PersonCandidate
+	for rows.Next() {
+		var (
+			inn, personName             string
+			directorCount, founderCount uint32
+			isEntrepreneur              uint8
+			regions, companies          []string
+		)
+		if err := rows.Scan(&inn, &personName, &directorCount, &founderCount, &isEntrepreneur, &regions, &companies); err != nil {
+			return nil, fmt.Errorf("scan person candidate row: %w", err)
+		}
+		candidates = append(candidates, &model.PersonCandidate{
+			Inn:             inn,
+			Name:            personName,
+			DirectorCount:   int(directorCount),
+			FounderCount:    int(founderCount),
+			IsEntrepreneur:  isEntrepreneur == 1,
+			RegionCodes:     regions,
+			SampleCompanies: companies,
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate person candidate rows: %w", err)
+	}
+
+	r.logger.Debug("SearchCandidates completed",
+		zap.String("name", name),
+		zap.Int("count", len(candidates)),
+	)
+	return candidates, nil
+}
+
+// parseRawStatus переводит значение колонки status в EntityStatus
+func parseRawStatus(status string) model.EntityStatus {
+	s := model.EntityStatus(strings.ToUpper(status))
+	if !s.IsValid() {
+		return model.EntityStatusUnknown
+	}
+	return s
+}
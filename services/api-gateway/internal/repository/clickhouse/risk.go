@@ -0,0 +1,82 @@
+package clickhouse
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/egrul-system/services/api-gateway/internal/graph/model"
+	"go.uber.org/zap"
+)
+
+// RiskRepository репозиторий предрассчитанных признаков оценки риска
+type RiskRepository struct {
+	client *Client
+	logger *zap.Logger
+}
+
+// NewRiskRepository создает новый репозиторий признаков риска
+func NewRiskRepository(client *Client, logger *zap.Logger) *RiskRepository {
+	return &RiskRepository{
+		client: client,
+		logger: logger.Named("risk_repo"),
+	}
+}
+
+// GetFeatures возвращает признаки по списку ОГРН. Компании без рассчитанных
+// признаков в результат не попадают.
+func (r *RiskRepository) GetFeatures(ctx context.Context, ogrns []string) (map[string]*model.CompanyRiskFeatures, error) {
+	result := make(map[string]*model.CompanyRiskFeatures, len(ogrns))
+	if len(ogrns) == 0 {
+		return result, nil
+	}
+
+	placeholders := make([]string, len(ogrns))
+	args := make([]interface{}, len(ogrns))
+	for i, ogrn := range ogrns {
+		placeholders[i] = "?"
+		args[i] = ogrn
+	}
+
+	query := fmt.Sprintf(`
+		SELECT
+			ogrn, address_companies, director_companies,
+			director_changes_12m, address_changes_12m, foreign_founders, updated_at
+		FROM egrul.company_risk_features FINAL
+		WHERE ogrn IN (%s)
+	`, strings.Join(placeholders, ","))
+
+	rows, err := r.client.conn.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query risk features: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			ogrn                                string
+			addressCompanies, directorCompanies uint32
+			directorChanges, addressChanges     uint16
+			foreignFounders                     uint16
+			updatedAt                           time.Time
+		)
+		if err := rows.Scan(&ogrn, &addressCompanies, &directorCompanies, &directorChanges, &addressChanges, &foreignFounders, &updatedAt); err != nil {
+			return nil, fmt.Errorf("scan risk features row: %w", err)
+		}
+		result[ogrn] = &model.CompanyRiskFeatures{
+			Ogrn:               ogrn,
+			AddressCompanies:   int(addressCompanies),
+			DirectorCompanies:  int(directorCompanies),
+			DirectorChanges12m: int(directorChanges),
+			AddressChanges12m:  int(addressChanges),
+			ForeignFounders:    int(foreignFounders),
+			UpdatedAt:          updatedAt,
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate risk features rows: %w", err)
+	}
+
+	return result, nil
+}
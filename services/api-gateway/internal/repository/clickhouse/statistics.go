@@ -3,6 +3,7 @@ package clickhouse
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/egrul-system/services/api-gateway/internal/graph/model"
@@ -41,7 +42,7 @@ func (r *StatisticsRepository) GetStatistics(ctx context.Context, filter *model.
 	}
 
 	// Статистика регистраций
-	if err := r.getRegistrationStats(ctx, stats); err != nil {
+	if err := r.getRegistrationStats(ctx, stats, filter); err != nil {
 		return nil, err
 	}
 
@@ -54,32 +55,23 @@ func (r *StatisticsRepository) GetStatistics(ctx context.Context, filter *model.
 }
 
 func (r *StatisticsRepository) getCompanyCounts(ctx context.Context, stats *model.Statistics, filter *model.StatsFilter) error {
-	// Проверяем нужна ли фильтрация по ОКВЭД
-	hasOkvedFilter := filter != nil && filter.Okved != nil && *filter.Okved != ""
-
 	var query string
 	var args []interface{}
 
-	if hasOkvedFilter {
-		// Если установлен ОКВЭД фильтр, используем прямой запрос к таблице
+	if needsRawStats(filter) {
+		// ОКВЭД и дата регистрации есть только в исходной таблице
 		// Логика статусов согласно company-status-badge.tsx:
 		// - active: нет termination_date И код НЕ в списке недействующих
 		// - liquidated: есть termination_date ИЛИ код в списке недействующих
+		where, whereArgs := entityFilterSQL(filter, companyKey, "registration_date")
 		query = `
 			SELECT
 				count() as total,
-				countIf(termination_date IS NULL AND status_code NOT IN ('101', '105', '106', '107', '113', '114', '115', '116', '117', '701', '702', '801', '802')) as active,
-				countIf(termination_date IS NOT NULL OR status_code IN ('101', '105', '106', '107', '113', '114', '115', '116', '117', '701', '702', '801', '802')) as liquidated
-			FROM egrul.companies_local FINAL
-			WHERE (okved_main_code = ? OR has(okved_additional, ?))
-		`
-		args = append(args, *filter.Okved, *filter.Okved)
-
-		// Добавляем регион если указан
-		if filter.RegionCode != nil && *filter.RegionCode != "" {
-			query += " AND region_code = ?"
-			args = append(args, *filter.RegionCode)
-		}
+				countIf(termination_date IS NULL AND status_code NOT IN (` + liquidatedStatusCodes + `)) as active,
+				countIf(termination_date IS NOT NULL OR status_code IN (` + liquidatedStatusCodes + `)) as liquidated
+			FROM egrul.companies FINAL
+			WHERE ` + where
+		args = whereArgs
 	} else {
 		// Используем Materialized View для быстрой агрегации
 		query = `
@@ -91,9 +83,9 @@ func (r *StatisticsRepository) getCompanyCounts(ctx context.Context, stats *mode
 		`
 
 		// Добавляем регион если указан
-		if filter != nil && filter.RegionCode != nil && *filter.RegionCode != "" {
+		if region := filterRegion(filter); region != "" {
 			query += " WHERE region_code = ?"
-			args = append(args, *filter.RegionCode)
+			args = append(args, region)
 		}
 	}
 
@@ -112,32 +104,23 @@ func (r *StatisticsRepository) getCompanyCounts(ctx context.Context, stats *mode
 }
 
 func (r *StatisticsRepository) getEntrepreneurCounts(ctx context.Context, stats *model.Statistics, filter *model.StatsFilter) error {
-	// Проверяем нужна ли фильтрация по ОКВЭД
-	hasOkvedFilter := filter != nil && filter.Okved != nil && *filter.Okved != ""
-
 	var query string
 	var args []interface{}
 
-	if hasOkvedFilter {
-		// Если установлен ОКВЭД фильтр, используем прямой запрос к таблице
+	if needsRawStats(filter) {
+		// ОКВЭД и дата регистрации есть только в исходной таблице
 		// Логика статусов согласно entrepreneur-status-badge.tsx (миграция 013):
 		// - active: НЕТ termination_date И НЕТ status_code (NULL)
 		// - liquidated: ЕСТЬ termination_date ИЛИ ЕСТЬ любой status_code
+		where, whereArgs := entityFilterSQL(filter, entrepreneurKey, "registration_date")
 		query = `
 			SELECT
 				count() as total,
 				countIf(termination_date IS NULL AND status_code IS NULL) as active,
 				countIf(termination_date IS NOT NULL OR status_code IS NOT NULL) as liquidated
-			FROM egrul.entrepreneurs_local FINAL
-			WHERE (okved_main_code = ? OR has(okved_additional, ?))
-		`
-		args = append(args, *filter.Okved, *filter.Okved)
-
-		// Добавляем регион если указан
-		if filter.RegionCode != nil && *filter.RegionCode != "" {
-			query += " AND region_code = ?"
-			args = append(args, *filter.RegionCode)
-		}
+			FROM egrul.entrepreneurs FINAL
+			WHERE ` + where
+		args = whereArgs
 	} else {
 		// Используем Materialized View для быстрой агрегации
 		query = `
@@ -149,9 +132,9 @@ func (r *StatisticsRepository) getEntrepreneurCounts(ctx context.Context, stats
 		`
 
 		// Добавляем регион если указан
-		if filter != nil && filter.RegionCode != nil && *filter.RegionCode != "" {
+		if region := filterRegion(filter); region != "" {
 			query += " WHERE region_code = ?"
-			args = append(args, *filter.RegionCode)
+			args = append(args, region)
 		}
 	}
 
@@ -169,43 +152,61 @@ func (r *StatisticsRepository) getEntrepreneurCounts(ctx context.Context, stats
 	return nil
 }
 
-func (r *StatisticsRepository) getRegistrationStats(ctx context.Context, stats *model.Statistics) error {
+// getRegistrationStats считает регистрации за сегодня/месяц/год с учетом региона и ОКВЭД,
+// а при заданном периоде фильтра - и регистрации за период
+func (r *StatisticsRepository) getRegistrationStats(ctx context.Context, stats *model.Statistics, filter *model.StatsFilter) error {
 	now := time.Now()
 	today := now.Truncate(24 * time.Hour)
 	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
 	yearStart := time.Date(now.Year(), 1, 1, 0, 0, 0, 0, now.Location())
+	periodFrom, periodTo := periodOrDefault(filter, time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC))
 
-	query := `
-		SELECT 
+	// Период фильтра считается отдельной колонкой, а не сужает today/month/year
+	companyWhere, companyArgs := entityFilterSQL(filter, companyKey, "")
+	entrepreneurWhere, entrepreneurArgs := entityFilterSQL(filter, entrepreneurKey, "")
+
+	query := fmt.Sprintf(`
+		SELECT
 			countIf(registration_date >= ?) as today,
 			countIf(registration_date >= ?) as month,
-			countIf(registration_date >= ?) as year
+			countIf(registration_date >= ?) as year,
+			countIf(registration_date >= ? AND registration_date <= ?) as period
 		FROM (
 			SELECT registration_date FROM egrul.companies FINAL
-			WHERE registration_date IS NOT NULL
+			WHERE registration_date IS NOT NULL AND %s
 			UNION ALL
 			SELECT registration_date FROM egrul.entrepreneurs FINAL
-			WHERE registration_date IS NOT NULL
+			WHERE registration_date IS NOT NULL AND %s
 		)
-	`
+	`, companyWhere, entrepreneurWhere)
+
+	args := []interface{}{today, monthStart, yearStart, periodFrom, periodTo}
+	args = append(args, companyArgs...)
+	args = append(args, entrepreneurArgs...)
+
+	row := r.client.conn.QueryRow(ctx, query, args...)
 
-	row := r.client.conn.QueryRow(ctx, query, today, monthStart, yearStart)
-	
-	var todayCount, monthCount, yearCount uint64
-	if err := row.Scan(&todayCount, &monthCount, &yearCount); err != nil {
+	var todayCount, monthCount, yearCount, periodCount uint64
+	if err := row.Scan(&todayCount, &monthCount, &yearCount, &periodCount); err != nil {
 		return fmt.Errorf("get registration stats: %w", err)
 	}
 
 	stats.RegisteredToday = int(todayCount)
 	stats.RegisteredThisMonth = int(monthCount)
 	stats.RegisteredThisYear = int(yearCount)
+	if filterHasPeriod(filter) {
+		period := int(periodCount)
+		stats.RegisteredInPeriod = &period
+	}
 
 	return nil
 }
 
 func (r *StatisticsRepository) getRegionStats(ctx context.Context, stats *model.Statistics, filter *model.StatsFilter) error {
+	where, args := entityFilterSQL(filter, companyKey, "registration_date")
+
 	query := `
-		SELECT 
+		SELECT
 			region_code,
 			any(region) as region_name,
 			count() as total,
@@ -213,12 +214,13 @@ func (r *StatisticsRepository) getRegionStats(ctx context.Context, stats *model.
 			countIf(status = 'liquidated') as liquidated
 		FROM egrul.companies FINAL
 		WHERE region_code IS NOT NULL AND region_code != ''
+		  AND ` + where + `
 		GROUP BY region_code
 		ORDER BY total DESC
 		LIMIT 20
 	`
 
-	rows, err := r.client.conn.Query(ctx, query)
+	rows, err := r.client.conn.Query(ctx, query, args...)
 	if err != nil {
 		return fmt.Errorf("get region stats: %w", err)
 	}
@@ -245,21 +247,75 @@ func (r *StatisticsRepository) getRegionStats(ctx context.Context, stats *model.
 	return nil
 }
 
-// GetActivityStats получает статистику по видам деятельности
-func (r *StatisticsRepository) GetActivityStats(ctx context.Context, limit int) ([]*model.ActivityStatistics, error) {
-	query := `
-		SELECT 
-			okved_main_code,
-			any(okved_main_name) as okved_name,
-			count() as companies_count
-		FROM egrul.companies FINAL
-		WHERE okved_main_code IS NOT NULL AND okved_main_code != ''
-		GROUP BY okved_main_code
-		ORDER BY companies_count DESC
+// GetActivityStats получает статистику по видам деятельности (компании и ИП).
+// level сворачивает основной код до уровня иерархии (nil - точный код), наименования
+// берутся из справочника okved_dict. Префикс ОКВЭД из фильтра ограничивает основной код,
+// регион и период применяются как обычно.
+func (r *StatisticsRepository) GetActivityStats(ctx context.Context, filter *model.StatsFilter, level *model.OkvedLevel, limit int) ([]*model.ActivityStatistics, error) {
+	var base *model.StatsFilter
+	if filter != nil {
+		f := *filter
+		f.Okved = nil
+		base = &f
+	}
+
+	keyExpr := "okved_main_code"
+	nameExpr := "any(okved_main_name)"
+	if level != nil {
+		if *level == model.OkvedLevelSection {
+			keyExpr = okvedSectionExpr("okved_main_code")
+		} else {
+			keyExpr = fmt.Sprintf("if(length(okved_main_code) >= %[1]d, substring(okved_main_code, 1, %[1]d), '')", level.CodeLength())
+		}
+		nameExpr = "anyIf(okved_main_name, okved_main_code = okved_code)"
+	}
+
+	part := func(table, keyColumn string, isCompany int) (string, []interface{}) {
+		where, args := entityFilterSQL(base, keyColumn, "registration_date")
+		if okved := filterOkved(filter); okved != "" {
+			if isOkvedSection(okved) {
+				where += " AND " + okvedSectionExpr("okved_main_code") + " = ?"
+			} else {
+				where += " AND startsWith(okved_main_code, ?)"
+			}
+			args = append(args, okved)
+		}
+		return fmt.Sprintf(`
+			SELECT assumeNotNull(okved_main_code) AS okved_main_code, okved_main_name, %d AS is_company
+			FROM %s FINAL
+			WHERE okved_main_code IS NOT NULL AND okved_main_code != ''
+			  AND %s`, isCompany, table, where), args
+	}
+	companies, args := part("egrul.companies", companyKey, 1)
+	entrepreneurs, entrepreneurArgs := part("egrul.entrepreneurs", entrepreneurKey, 0)
+	args = append(args, entrepreneurArgs...)
+	args = append(args, limit)
+
+	query := fmt.Sprintf(`
+		SELECT
+			okved_code,
+			if(dictGetOrDefault('egrul.okved_dict', 'name', tuple(okved_code), '') != '',
+			   dictGetOrDefault('egrul.okved_dict', 'name', tuple(okved_code), ''),
+			   ifNull(data_name, '')) AS okved_name,
+			companies_count,
+			entrepreneurs_count
+		FROM (
+			SELECT
+				%s AS okved_code,
+				%s AS data_name,
+				countIf(is_company = 1) AS companies_count,
+				countIf(is_company = 0) AS entrepreneurs_count
+			FROM (%s
+				UNION ALL%s
+			)
+			GROUP BY okved_code
+			HAVING okved_code != ''
+		)
+		ORDER BY companies_count + entrepreneurs_count DESC, okved_code
 		LIMIT ?
-	`
+	`, keyExpr, nameExpr, companies, entrepreneurs)
 
-	rows, err := r.client.conn.Query(ctx, query, limit)
+	rows, err := r.client.conn.Query(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("get activity stats: %w", err)
 	}
@@ -268,17 +324,20 @@ func (r *StatisticsRepository) GetActivityStats(ctx context.Context, limit int)
 	var result []*model.ActivityStatistics
 	for rows.Next() {
 		var code, name string
-		var count uint64
-		
-		if err := rows.Scan(&code, &name, &count); err != nil {
+		var companiesCount, entrepreneursCount uint64
+
+		if err := rows.Scan(&code, &name, &companiesCount, &entrepreneursCount); err != nil {
 			return nil, fmt.Errorf("scan activity stats: %w", err)
 		}
+		if name == "" && isOkvedSection(code) {
+			name = model.OkvedSectionName(code)
+		}
 
 		result = append(result, &model.ActivityStatistics{
 			OkvedCode:          code,
 			OkvedName:          name,
-			CompaniesCount:     int(count),
-			EntrepreneursCount: 0,
+			CompaniesCount:     int(companiesCount),
+			EntrepreneursCount: int(entrepreneursCount),
 		})
 	}
 
@@ -293,17 +352,13 @@ func (r *StatisticsRepository) GetRegistrationsByMonth(
 	filter *model.StatsFilter,
 ) ([]*model.TimeSeriesPoint, error) {
 	// Устанавливаем дефолтные значения если не указаны
-	var from, to time.Time
+	// Явные аргументы приоритетнее периода из фильтра; по умолчанию - последний год
+	from, to := periodOrDefault(filter, time.Now().AddDate(-1, 0, 0))
 	if dateFrom != nil {
 		from = *dateFrom
-	} else {
-		// По умолчанию - последний год
-		from = time.Now().AddDate(-1, 0, 0)
 	}
 	if dateTo != nil {
 		to = *dateTo
-	} else {
-		to = time.Now()
 	}
 
 	// Проверяем нужна ли фильтрация по региону или ОКВЭД
@@ -336,7 +391,7 @@ func (r *StatisticsRepository) GetRegistrationsByMonth(
 						FROM egrul.companies_local FINAL
 						WHERE registration_date IS NOT NULL
 						  AND (region_code = ? OR ? = '')
-						  AND ((okved_main_code = ? OR has(okved_additional, ?)) OR ? = '')
+						  AND ((startsWith(okved_main_code, ?) OR ogrn IN (SELECT ogrn FROM egrul.companies_okved_additional_local WHERE startsWith(okved_code, ?))) OR ? = '')
 						  AND registration_date >= ?
 						  AND registration_date <= ?
 						GROUP BY registration_month
@@ -356,7 +411,7 @@ func (r *StatisticsRepository) GetRegistrationsByMonth(
 							count() as count
 						FROM egrul.companies_local FINAL
 						WHERE (region_code = ? OR ? = '')
-						  AND ((okved_main_code = ? OR has(okved_additional, ?)) OR ? = '')
+						  AND ((startsWith(okved_main_code, ?) OR ogrn IN (SELECT ogrn FROM egrul.companies_okved_additional_local WHERE startsWith(okved_code, ?))) OR ? = '')
 						  AND (
 							  (termination_date IS NOT NULL AND termination_date >= ? AND termination_date <= ?)
 							  OR (termination_date IS NULL AND status_code IN ('101', '105', '106', '107', '113', '114', '115', '116', '117', '701', '702', '801', '802') AND extract_date >= ? AND extract_date <= ?)
@@ -384,7 +439,7 @@ func (r *StatisticsRepository) GetRegistrationsByMonth(
 						FROM egrul.entrepreneurs_local FINAL
 						WHERE registration_date IS NOT NULL
 						  AND (region_code = ? OR ? = '')
-						  AND ((okved_main_code = ? OR has(okved_additional, ?)) OR ? = '')
+						  AND ((startsWith(okved_main_code, ?) OR ogrnip IN (SELECT ogrnip FROM egrul.entrepreneurs_okved_additional_local WHERE startsWith(okved_code, ?))) OR ? = '')
 						  AND registration_date >= ?
 						  AND registration_date <= ?
 						GROUP BY registration_month
@@ -396,7 +451,7 @@ func (r *StatisticsRepository) GetRegistrationsByMonth(
 						FROM egrul.entrepreneurs_local FINAL
 						WHERE termination_date IS NOT NULL
 						  AND (region_code = ? OR ? = '')
-						  AND ((okved_main_code = ? OR has(okved_additional, ?)) OR ? = '')
+						  AND ((startsWith(okved_main_code, ?) OR ogrnip IN (SELECT ogrnip FROM egrul.entrepreneurs_okved_additional_local WHERE startsWith(okved_code, ?))) OR ? = '')
 						  AND termination_date >= ?
 						  AND termination_date <= ?
 						GROUP BY termination_month
@@ -422,7 +477,7 @@ func (r *StatisticsRepository) GetRegistrationsByMonth(
 						FROM egrul.companies_local FINAL
 						WHERE registration_date IS NOT NULL
 						  AND (region_code = ? OR ? = '')
-						  AND ((okved_main_code = ? OR has(okved_additional, ?)) OR ? = '')
+						  AND ((startsWith(okved_main_code, ?) OR ogrn IN (SELECT ogrn FROM egrul.companies_okved_additional_local WHERE startsWith(okved_code, ?))) OR ? = '')
 						  AND registration_date >= ?
 						  AND registration_date <= ?
 						GROUP BY registration_month
@@ -433,7 +488,7 @@ func (r *StatisticsRepository) GetRegistrationsByMonth(
 						FROM egrul.entrepreneurs_local FINAL
 						WHERE registration_date IS NOT NULL
 						  AND (region_code = ? OR ? = '')
-						  AND ((okved_main_code = ? OR has(okved_additional, ?)) OR ? = '')
+						  AND ((startsWith(okved_main_code, ?) OR ogrnip IN (SELECT ogrnip FROM egrul.entrepreneurs_okved_additional_local WHERE startsWith(okved_code, ?))) OR ? = '')
 						  AND registration_date >= ?
 						  AND registration_date <= ?
 						GROUP BY registration_month
@@ -460,7 +515,7 @@ func (r *StatisticsRepository) GetRegistrationsByMonth(
 							count() as count
 						FROM egrul.companies_local FINAL
 						WHERE (region_code = ? OR ? = '')
-						  AND ((okved_main_code = ? OR has(okved_additional, ?)) OR ? = '')
+						  AND ((startsWith(okved_main_code, ?) OR ogrn IN (SELECT ogrn FROM egrul.companies_okved_additional_local WHERE startsWith(okved_code, ?))) OR ? = '')
 						  AND (
 							  (termination_date IS NOT NULL AND termination_date >= ? AND termination_date <= ?)
 							  OR (termination_date IS NULL AND status_code IN ('101', '105', '106', '107', '113', '114', '115', '116', '117', '701', '702', '801', '802') AND extract_date >= ? AND extract_date <= ?)
@@ -473,7 +528,7 @@ func (r *StatisticsRepository) GetRegistrationsByMonth(
 						FROM egrul.entrepreneurs_local FINAL
 						WHERE termination_date IS NOT NULL
 						  AND (region_code = ? OR ? = '')
-						  AND ((okved_main_code = ? OR has(okved_additional, ?)) OR ? = '')
+						  AND ((startsWith(okved_main_code, ?) OR ogrnip IN (SELECT ogrnip FROM egrul.entrepreneurs_okved_additional_local WHERE startsWith(okved_code, ?))) OR ? = '')
 						  AND termination_date >= ?
 						  AND termination_date <= ?
 						GROUP BY termination_month
@@ -642,43 +697,89 @@ func (r *StatisticsRepository) GetRegistrationsByMonth(
 	return result, nil
 }
 
-// GetRegionHeatmap возвращает статистику для ВСЕХ регионов (для тепловой карты)
-func (r *StatisticsRepository) GetRegionHeatmap(ctx context.Context) ([]*model.RegionStatistics, error) {
-	// Объединяем статистику компаний и ИП используя MV
-	query := `
-		WITH
-			companies_stats AS (
-				SELECT
-					region_code,
-					any(region) as region_name,
-					countMerge(count) as companies_count,
-					countMergeIf(count, status = 'active') as active_companies,
-					countMergeIf(count, status = 'liquidated') as liquidated_companies
-				FROM egrul.stats_companies_by_region
-				GROUP BY region_code
-			),
-			entrepreneurs_stats AS (
-				SELECT
-					region_code,
-					countMerge(count) as entrepreneurs_count
-				FROM egrul.stats_entrepreneurs_by_region
-				WHERE status = 'active'
-				GROUP BY region_code
-			)
-		SELECT
-			c.region_code,
-			c.region_name,
-			c.companies_count,
-			coalesce(e.entrepreneurs_count, 0) as entrepreneurs_count,
-			c.active_companies,
-			c.liquidated_companies
-		FROM companies_stats c
-		LEFT JOIN entrepreneurs_stats e ON c.region_code = e.region_code
-		WHERE c.region_code IS NOT NULL AND c.region_code != ''
-		ORDER BY c.region_code
-	`
+// GetRegionHeatmap возвращает статистику для ВСЕХ регионов (для тепловой карты).
+// Фильтр по ОКВЭД или периоду регистрации считается по исходным таблицам.
+func (r *StatisticsRepository) GetRegionHeatmap(ctx context.Context, filter *model.StatsFilter) ([]*model.RegionStatistics, error) {
+	var query string
+	var args []interface{}
 
-	rows, err := r.client.conn.Query(ctx, query)
+	if needsRawStats(filter) {
+		where, whereArgs := entityFilterSQL(filter, companyKey, "registration_date")
+		query = `
+			WITH
+				companies_stats AS (
+					SELECT
+						ifNull(region_code, '') as region_code,
+						any(ifNull(region, '')) as region_name,
+						count() as companies_count,
+						countIf(termination_date IS NULL AND status_code NOT IN (` + liquidatedStatusCodes + `)) as active_companies,
+						countIf(termination_date IS NOT NULL OR status_code IN (` + liquidatedStatusCodes + `)) as liquidated_companies
+					FROM egrul.companies FINAL
+					WHERE ` + where + `
+					GROUP BY region_code
+				),
+				entrepreneurs_stats AS (
+					SELECT
+						ifNull(region_code, '') as region_code,
+						countIf(termination_date IS NULL AND status_code IS NULL) as entrepreneurs_count
+					FROM egrul.entrepreneurs FINAL
+					WHERE ` + where + `
+					GROUP BY region_code
+				)
+			SELECT
+				c.region_code,
+				c.region_name,
+				c.companies_count,
+				coalesce(e.entrepreneurs_count, 0) as entrepreneurs_count,
+				c.active_companies,
+				c.liquidated_companies
+			FROM companies_stats c
+			LEFT JOIN entrepreneurs_stats e ON c.region_code = e.region_code
+			WHERE c.region_code != ''
+			ORDER BY c.region_code
+		`
+		args = append(whereArgs, whereArgs...)
+	} else {
+		// Объединяем статистику компаний и ИП используя MV
+		region := filterRegion(filter)
+		query = `
+			WITH
+				companies_stats AS (
+					SELECT
+						region_code,
+						any(region) as region_name,
+						countMerge(count) as companies_count,
+						countMergeIf(count, status = 'active') as active_companies,
+						countMergeIf(count, status = 'liquidated') as liquidated_companies
+					FROM egrul.stats_companies_by_region
+					WHERE (region_code = ? OR ? = '')
+					GROUP BY region_code
+				),
+				entrepreneurs_stats AS (
+					SELECT
+						region_code,
+						countMerge(count) as entrepreneurs_count
+					FROM egrul.stats_entrepreneurs_by_region
+					WHERE status = 'active'
+					  AND (region_code = ? OR ? = '')
+					GROUP BY region_code
+				)
+			SELECT
+				c.region_code,
+				c.region_name,
+				c.companies_count,
+				coalesce(e.entrepreneurs_count, 0) as entrepreneurs_count,
+				c.active_companies,
+				c.liquidated_companies
+			FROM companies_stats c
+			LEFT JOIN entrepreneurs_stats e ON c.region_code = e.region_code
+			WHERE c.region_code IS NOT NULL AND c.region_code != ''
+			ORDER BY c.region_code
+		`
+		args = []interface{}{region, region, region, region}
+	}
+
+	rows, err := r.client.conn.Query(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("get region heatmap: %w", err)
 	}
@@ -706,3 +807,355 @@ func (r *StatisticsRepository) GetRegionHeatmap(ctx context.Context) ([]*model.R
 	return result, nil
 }
 
+// founderTypeRawValues возвращает значения founder_type в хранилище, соответствующие типу учредителя
+func founderTypeRawValues(t model.FounderType) []string {
+	switch t {
+	case model.FounderTypePerson:
+		return []string{"person", "physical_person", "физическое лицо"}
+	case model.FounderTypeRussianCompany:
+		return []string{"russian_company", "юридическое лицо"}
+	case model.FounderTypeForeignCompany:
+		return []string{"foreign_company", "иностранное юридическое лицо"}
+	case model.FounderTypePublicEntity:
+		return []string{"public_entity", "публичное образование"}
+	case model.FounderTypeFund:
+		return []string{"fund", "фонд"}
+	default:
+		return []string{strings.ToLower(string(t))}
+	}
+}
+
+// GetTopOwners возвращает владельцев с наибольшим числом компаний (или средней долей)
+// по агрегату stats_top_owners, а при фильтре по компаниям - по founders
+func (r *StatisticsRepository) GetTopOwners(
+	ctx context.Context,
+	filter *model.StatsFilter,
+	ownerType *model.FounderType,
+	sortBy model.TopOwnerSortField,
+	order model.SortOrder,
+	limit int,
+) ([]*model.TopOwner, error) {
+	source := "egrul.stats_top_owners"
+	countExpr := "countMerge(owned_companies_count)"
+	avgExpr := "avgMerge(avg_share_percent)"
+	var args []interface{}
+	if hasEntityFilter(filter) {
+		sub, subArgs := filteredCompaniesSubquery(filter)
+		source = `(
+			SELECT
+				founder_type AS owner_type,
+				ifNull(founder_ogrn, '') AS owner_id,
+				founder_inn AS owner_inn,
+				founder_name AS owner_name,
+				share_percent
+			FROM egrul.founders FINAL
+			WHERE company_ogrn GLOBAL IN (` + sub + `)
+		)`
+		countExpr = "count()"
+		avgExpr = "avg(share_percent)"
+		args = append(args, subArgs...)
+	}
+
+	where := "owner_name != ''"
+	if ownerType != nil {
+		raw := founderTypeRawValues(*ownerType)
+		where += " AND owner_type IN (?" + strings.Repeat(", ?", len(raw)-1) + ")"
+		for _, v := range raw {
+			args = append(args, v)
+		}
+	}
+
+	orderExpr := "companies_count"
+	if sortBy == model.TopOwnerSortFieldAvgShare {
+		orderExpr = "avg_share_percent"
+	}
+	direction := "DESC"
+	if order == model.SortOrderAsc {
+		direction = "ASC"
+	}
+	args = append(args, limit)
+
+	query := fmt.Sprintf(`
+		SELECT
+			owner_type,
+			owner_id,
+			owner_inn,
+			owner_name,
+			%s as companies_count,
+			CAST(%s AS Nullable(Float64)) as avg_share_percent
+		FROM %s
+		WHERE %s
+		GROUP BY owner_type, owner_id, owner_inn, owner_name
+		ORDER BY %s %s NULLS LAST, owner_name
+		LIMIT ?
+	`, countExpr, avgExpr, source, where, orderExpr, direction)
+
+	rows, err := r.client.conn.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("get top owners: %w", err)
+	}
+	defer rows.Close()
+
+	result := make([]*model.TopOwner, 0)
+	for rows.Next() {
+		var ownerTypeRaw, ownerID, ownerINN, ownerName string
+		var companiesCount uint64
+		var avgShare *float64
+
+		if err := rows.Scan(&ownerTypeRaw, &ownerID, &ownerINN, &ownerName, &companiesCount, &avgShare); err != nil {
+			return nil, fmt.Errorf("scan top owner: %w", err)
+		}
+
+		owner := &model.TopOwner{
+			OwnerType:       model.ParseFounderType(ownerTypeRaw),
+			Name:            ownerName,
+			CompaniesCount:  int(companiesCount),
+			AvgSharePercent: avgShare,
+		}
+		if ownerID != "" {
+			owner.Ogrn = &ownerID
+		}
+		if ownerINN != "" {
+			owner.Inn = &ownerINN
+		}
+		result = append(result, owner)
+	}
+
+	return result, nil
+}
+
+// GetOwnershipByType возвращает распределение долей по типам учредителей
+// по агрегату stats_ownership_by_type, а при фильтре по компаниям - по founders
+func (r *StatisticsRepository) GetOwnershipByType(ctx context.Context, filter *model.StatsFilter) ([]*model.OwnershipTypeStatistics, error) {
+	query := `
+		SELECT
+			owner_type,
+			countMerge(count) as shares_count,
+			uniqMerge(companies) as companies_count,
+			CAST(avgMerge(avg_share_percent) AS Nullable(Float64)) as avg_share_percent
+		FROM egrul.stats_ownership_by_type
+		GROUP BY owner_type
+		ORDER BY shares_count DESC
+	`
+	var args []interface{}
+	if hasEntityFilter(filter) {
+		sub, subArgs := filteredCompaniesSubquery(filter)
+		query = `
+			SELECT
+				founder_type as owner_type,
+				count() as shares_count,
+				uniq(company_ogrn) as companies_count,
+				CAST(avg(share_percent) AS Nullable(Float64)) as avg_share_percent
+			FROM egrul.founders FINAL
+			WHERE company_ogrn GLOBAL IN (` + sub + `)
+			GROUP BY owner_type
+			ORDER BY shares_count DESC
+		`
+		args = subArgs
+	}
+
+	rows, err := r.client.conn.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("get ownership by type: %w", err)
+	}
+	defer rows.Close()
+
+	// Разные написания одного типа в исходных данных сводим в одну строку
+	byType := make(map[model.FounderType]*model.OwnershipTypeStatistics)
+	result := make([]*model.OwnershipTypeStatistics, 0)
+	for rows.Next() {
+		var ownerTypeRaw string
+		var sharesCount, companiesCount uint64
+		var avgShare *float64
+
+		if err := rows.Scan(&ownerTypeRaw, &sharesCount, &companiesCount, &avgShare); err != nil {
+			return nil, fmt.Errorf("scan ownership type statistics: %w", err)
+		}
+
+		ownerType := model.ParseFounderType(ownerTypeRaw)
+		item, ok := byType[ownerType]
+		if !ok {
+			item = &model.OwnershipTypeStatistics{OwnerType: ownerType}
+			byType[ownerType] = item
+			result = append(result, item)
+		}
+		if avgShare != nil {
+			// Средняя доля взвешивается по числу долей
+			total := float64(item.SharesCount+int(sharesCount))
+			merged := *avgShare * float64(sharesCount) / total
+			if item.AvgSharePercent != nil {
+				merged += *item.AvgSharePercent * float64(item.SharesCount) / total
+			}
+			item.AvgSharePercent = &merged
+		}
+		item.SharesCount += int(sharesCount)
+		// uniq по компаниям не складывается точно; для разных написаний типа допускаем приближение
+		item.CompaniesCount += int(companiesCount)
+	}
+
+	return result, nil
+}
+
+// GetLegalForms возвращает распределение компаний по ОПФ.
+// Регион берется из агрегата stats_companies_by_opf, ОКВЭД и период - из companies.
+func (r *StatisticsRepository) GetLegalForms(ctx context.Context, filter *model.StatsFilter, limit int) ([]*model.LegalFormStatistics, error) {
+	var query string
+	var args []interface{}
+
+	if needsRawStats(filter) {
+		where, whereArgs := entityFilterSQL(filter, companyKey, "registration_date")
+		query = `
+			SELECT
+				ifNull(opf_code, '') as opf_code,
+				any(ifNull(opf_name, '')) as opf_name,
+				count() as companies_count,
+				countIf(termination_date IS NULL AND status_code NOT IN (` + liquidatedStatusCodes + `)) as active_count,
+				countIf(termination_date IS NOT NULL OR status_code IN (` + liquidatedStatusCodes + `)) as liquidated_count,
+				CAST(sum(capital_amount) AS Nullable(Float64)) as total_capital,
+				CAST(avg(capital_amount) AS Nullable(Float64)) as avg_capital
+			FROM egrul.companies FINAL
+			WHERE opf_code IS NOT NULL AND opf_code != ''
+			  AND ` + where + `
+			GROUP BY opf_code
+			ORDER BY companies_count DESC, opf_code
+			LIMIT ?
+		`
+		args = append(whereArgs, limit)
+	} else {
+		region := filterRegion(filter)
+		query = `
+			SELECT
+				opf_code,
+				any(opf_name) as opf_name,
+				countMerge(count) as companies_count,
+				countMergeIf(count, status = 'active') as active_count,
+				countMergeIf(count, status = 'liquidated') as liquidated_count,
+				CAST(sumMerge(total_capital) AS Nullable(Float64)) as total_capital,
+				CAST(avgMerge(avg_capital) AS Nullable(Float64)) as avg_capital
+			FROM egrul.stats_companies_by_opf
+			WHERE (region_code = ? OR ? = '')
+			GROUP BY opf_code
+			ORDER BY companies_count DESC, opf_code
+			LIMIT ?
+		`
+		args = []interface{}{region, region, limit}
+	}
+
+	rows, err := r.client.conn.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("get legal forms: %w", err)
+	}
+	defer rows.Close()
+
+	result := make([]*model.LegalFormStatistics, 0)
+	for rows.Next() {
+		var opfCode, opfName string
+		var companiesCount, activeCount, liquidatedCount uint64
+		var totalCapital, avgCapital *float64
+
+		if err := rows.Scan(&opfCode, &opfName, &companiesCount, &activeCount, &liquidatedCount, &totalCapital, &avgCapital); err != nil {
+			return nil, fmt.Errorf("scan legal form statistics: %w", err)
+		}
+
+		result = append(result, &model.LegalFormStatistics{
+			OpfCode:         opfCode,
+			OpfName:         opfName,
+			CompaniesCount:  int(companiesCount),
+			ActiveCount:     int(activeCount),
+			LiquidatedCount: int(liquidatedCount),
+			TotalCapital:    totalCapital,
+			AvgCapital:      avgCapital,
+		})
+	}
+
+	return result, nil
+}
+
+// GetChangesByMonth возвращает число записей ГРН по месяцам и причинам внесения.
+// Период фильтра относится к дате записи; при фильтре по региону или ОКВЭД
+// записи берутся из company_history для подходящих компаний и ИП.
+func (r *StatisticsRepository) GetChangesByMonth(
+	ctx context.Context,
+	filter *model.StatsFilter,
+	entityType *model.EntityType,
+	reasonCode *string,
+) ([]*model.ChangesByMonthPoint, error) {
+	// По умолчанию - последний год
+	from, to := periodOrDefault(filter, time.Now().AddDate(-1, 0, 0))
+
+	var rawEntityType, rawReasonCode string
+	if entityType != nil {
+		rawEntityType = strings.ToLower(string(*entityType))
+	}
+	if reasonCode != nil {
+		rawReasonCode = *reasonCode
+	}
+
+	source := "egrul.stats_changes_by_month"
+	countExpr := "countMerge(count)"
+	var args []interface{}
+
+	// Регион и ОКВЭД есть только у самих компаний и ИП, период при этом
+	// относится к дате записи, а не к дате регистрации
+	if entityFilter := entityOnlyFilter(filter); entityFilter != nil {
+		companies, companyArgs := filteredCompaniesSubquery(entityFilter)
+		entrepreneurs, entrepreneurArgs := filteredEntrepreneursSubquery(entityFilter)
+		source = `(
+			SELECT
+				entity_type,
+				toStartOfMonth(grn_date) AS change_month,
+				ifNull(reason_code, '') AS reason_code,
+				ifNull(reason_description, '') AS reason_description
+			FROM egrul.company_history
+			WHERE (entity_type = 'company' AND entity_id GLOBAL IN (` + companies + `))
+			   OR (entity_type = 'entrepreneur' AND entity_id GLOBAL IN (` + entrepreneurs + `))
+		)`
+		countExpr = "count()"
+		args = append(args, companyArgs...)
+		args = append(args, entrepreneurArgs...)
+	}
+	args = append(args, from, to, rawEntityType, rawEntityType, rawReasonCode, rawReasonCode)
+
+	query := fmt.Sprintf(`
+		SELECT
+			change_month,
+			entity_type,
+			reason_code,
+			any(reason_description) as reason_description,
+			%s as changes_count
+		FROM %s
+		WHERE change_month >= toStartOfMonth(?)
+		  AND change_month <= ?
+		  AND (entity_type = ? OR ? = '')
+		  AND (reason_code = ? OR ? = '')
+		GROUP BY change_month, entity_type, reason_code
+		ORDER BY change_month, entity_type, changes_count DESC
+	`, countExpr, source)
+
+	rows, err := r.client.conn.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("get changes by month: %w", err)
+	}
+	defer rows.Close()
+
+	result := make([]*model.ChangesByMonthPoint, 0)
+	for rows.Next() {
+		var month time.Time
+		var rowEntityType, rowReasonCode, reasonDescription string
+		var count uint64
+
+		if err := rows.Scan(&month, &rowEntityType, &rowReasonCode, &reasonDescription, &count); err != nil {
+			return nil, fmt.Errorf("scan changes by month point: %w", err)
+		}
+
+		result = append(result, &model.ChangesByMonthPoint{
+			Month:             month.Format("2006-01-02"),
+			EntityType:        model.EntityType(strings.ToUpper(rowEntityType)),
+			ReasonCode:        rowReasonCode,
+			ReasonDescription: reasonDescription,
+			Count:             int(count),
+		})
+	}
+
+	return result, nil
+}
@@ -0,0 +1,171 @@
+package clickhouse
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/egrul-system/services/api-gateway/internal/graph/model"
+)
+
+// Агрегаты stats_* хранят только регион, поэтому фильтр по ОКВЭД или периоду
+// регистрации выполняется запросом к исходным таблицам.
+
+// liquidatedStatusCodes коды статусов недействующих юрлиц (как в MV миграции 017)
+const liquidatedStatusCodes = `'101', '105', '106', '107', '113', '114', '115', '116', '117', '701', '702', '801', '802'`
+
+// companyTerminationDateExpr дата прекращения юрлица: termination_date, а для
+// недействующих статусов без даты - дата выписки
+const companyTerminationDateExpr = `COALESCE(termination_date, multiIf(status_code IN (` + liquidatedStatusCodes + `), extract_date, NULL))`
+
+func filterRegion(filter *model.StatsFilter) string {
+	if filter == nil || filter.RegionCode == nil {
+		return ""
+	}
+	return strings.TrimSpace(*filter.RegionCode)
+}
+
+func filterOkved(filter *model.StatsFilter) string {
+	if filter == nil || filter.Okved == nil {
+		return ""
+	}
+	return strings.ToUpper(strings.TrimSpace(*filter.Okved))
+}
+
+func filterHasPeriod(filter *model.StatsFilter) bool {
+	return filter != nil && (filter.DateFrom != nil || filter.DateTo != nil)
+}
+
+// needsRawStats сообщает, что фильтр нельзя применить к агрегатам stats_*
+func needsRawStats(filter *model.StatsFilter) bool {
+	return filterOkved(filter) != "" || filterHasPeriod(filter)
+}
+
+// hasEntityFilter сообщает, что фильтр ограничивает набор компаний/ИП
+func hasEntityFilter(filter *model.StatsFilter) bool {
+	return filterRegion(filter) != "" || needsRawStats(filter)
+}
+
+// Ключевые колонки субъектов: по ним фильтр по ОКВЭД находит дополнительные коды
+const (
+	companyKey      = "ogrn"
+	entrepreneurKey = "ogrnip"
+)
+
+// okvedPrefixCondition условие на код ОКВЭД по префиксу (основной или дополнительный).
+// Префикс "62" охватывает "62.01", "62.02.1" и т.д., буква раздела ("J") - все его классы.
+// Дополнительные коды берутся из *_okved_additional: массив okved_additional при
+// импорте не заполняется. keyColumn - companyKey или entrepreneurKey.
+func okvedPrefixCondition(okved, keyColumn string) (string, []interface{}) {
+	additionalTable := "egrul.companies_okved_additional"
+	if keyColumn == entrepreneurKey {
+		additionalTable = "egrul.entrepreneurs_okved_additional"
+	}
+
+	mainCond, additionalCond := "startsWith(ifNull(okved_main_code, ''), ?)", "startsWith(okved_code, ?)"
+	if isOkvedSection(okved) {
+		mainCond = okvedSectionExpr("okved_main_code") + " = ?"
+		additionalCond = okvedSectionExpr("okved_code") + " = ?"
+	}
+
+	return fmt.Sprintf("(%[1]s OR %[2]s GLOBAL IN (SELECT %[2]s FROM %[3]s WHERE %[4]s))",
+			mainCond, keyColumn, additionalTable, additionalCond),
+		[]interface{}{okved, okved}
+}
+
+// entityFilterSQL строит условие WHERE для companies/entrepreneurs по региону,
+// префиксу ОКВЭД и периоду по dateColumn (пустой dateColumn - период не применяется).
+// keyColumn определяет таблицу дополнительных ОКВЭД. Без ограничений возвращает "1 = 1".
+func entityFilterSQL(filter *model.StatsFilter, keyColumn, dateColumn string) (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+
+	if region := filterRegion(filter); region != "" {
+		conditions = append(conditions, "region_code = ?")
+		args = append(args, region)
+	}
+	if okved := filterOkved(filter); okved != "" {
+		cond, okvedArgs := okvedPrefixCondition(okved, keyColumn)
+		conditions = append(conditions, cond)
+		args = append(args, okvedArgs...)
+	}
+	if dateColumn != "" && filter != nil {
+		if filter.DateFrom != nil {
+			conditions = append(conditions, dateColumn+" >= ?")
+			args = append(args, filter.DateFrom.Time)
+		}
+		if filter.DateTo != nil {
+			conditions = append(conditions, dateColumn+" <= ?")
+			args = append(args, filter.DateTo.Time)
+		}
+	}
+
+	if len(conditions) == 0 {
+		return "1 = 1", nil
+	}
+	return strings.Join(conditions, " AND "), args
+}
+
+// entityOnlyFilter оставляет в фильтре только регион и ОКВЭД - для источников, где
+// период относится к дате события, а не к дате регистрации. Возвращает nil без ограничений.
+func entityOnlyFilter(filter *model.StatsFilter) *model.StatsFilter {
+	if filterRegion(filter) == "" && filterOkved(filter) == "" {
+		return nil
+	}
+	return &model.StatsFilter{RegionCode: filter.RegionCode, Okved: filter.Okved}
+}
+
+// filteredCompaniesSubquery подзапрос ОГРН компаний, подходящих под фильтр
+// (период - по дате регистрации); используется с GLOBAL IN
+func filteredCompaniesSubquery(filter *model.StatsFilter) (string, []interface{}) {
+	where, args := entityFilterSQL(filter, companyKey, "registration_date")
+	return "SELECT ogrn FROM egrul.companies FINAL WHERE " + where, args
+}
+
+// filteredEntrepreneursSubquery подзапрос ОГРНИП предпринимателей, подходящих под фильтр
+func filteredEntrepreneursSubquery(filter *model.StatsFilter) (string, []interface{}) {
+	where, args := entityFilterSQL(filter, entrepreneurKey, "registration_date")
+	return "SELECT ogrnip FROM egrul.entrepreneurs FINAL WHERE " + where, args
+}
+
+// periodOrDefault возвращает период фильтра, подставляя defaultFrom и текущую дату
+func periodOrDefault(filter *model.StatsFilter, defaultFrom time.Time) (time.Time, time.Time) {
+	from, to := defaultFrom, time.Now()
+	if filter != nil && filter.DateFrom != nil {
+		from = filter.DateFrom.Time
+	}
+	if filter != nil && filter.DateTo != nil {
+		to = filter.DateTo.Time
+	}
+	return from, to
+}
+
+// okvedSectionExpr SQL-выражение раздела ОКВЭД (A-U) по коду в column; пустая строка для пустого кода
+func okvedSectionExpr(column string) string {
+	class := fmt.Sprintf("toUInt8OrZero(substring(ifNull(%s, ''), 1, 2))", column)
+	parts := make([]string, 0, len(model.OkvedSections)*2+1)
+	for _, s := range model.OkvedSections {
+		parts = append(parts, fmt.Sprintf("%s BETWEEN %d AND %d", class, s.From, s.To), "'"+s.Code+"'")
+	}
+	parts = append(parts, "''")
+	return "multiIf(" + strings.Join(parts, ", ") + ")"
+}
+
+// isOkvedSection сообщает, что код - буква раздела ОКВЭД
+func isOkvedSection(code string) bool {
+	return model.OkvedSectionName(code) != ""
+}
+
+// periodTruncExpr SQL-выражение начала периода для даты в column
+func periodTruncExpr(granularity model.TimeGranularity, column string) string {
+	switch granularity {
+	case model.TimeGranularityDay:
+		return "toDate(" + column + ")"
+	case model.TimeGranularityWeek:
+		return "toMonday(" + column + ")"
+	case model.TimeGranularityYear:
+		return "toStartOfYear(" + column + ")"
+	default:
+		return "toStartOfMonth(" + column + ")"
+	}
+}
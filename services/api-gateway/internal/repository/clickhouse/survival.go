@@ -0,0 +1,95 @@
+package clickhouse
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/egrul-system/services/api-gateway/internal/graph/model"
+)
+
+// GetSurvivalCohorts возвращает счетчики когорт регистрации из stats_survival_cohorts.
+// Префикс ОКВЭД применяется к основному коду, период фильтра - к дате регистрации.
+func (r *StatisticsRepository) GetSurvivalCohorts(
+	ctx context.Context,
+	granularity model.CohortGranularity,
+	entityType *model.EntityType,
+	filter *model.StatsFilter,
+) ([]*model.SurvivalCohortCounts, error) {
+	cohortExpr := "cohort_quarter"
+	if granularity == model.CohortGranularityYear {
+		cohortExpr = "toStartOfYear(cohort_quarter)"
+	}
+
+	conditions := []string{"1 = 1"}
+	var args []interface{}
+	if entityType != nil {
+		conditions = append(conditions, "entity_type = ?")
+		args = append(args, strings.ToLower(string(*entityType)))
+	}
+	if region := filterRegion(filter); region != "" {
+		conditions = append(conditions, "region_code = ?")
+		args = append(args, region)
+	}
+	if okved := filterOkved(filter); okved != "" {
+		conditions = append(conditions, "startsWith(okved_main_code, ?)")
+		args = append(args, okved)
+	}
+	if filter != nil && filter.DateFrom != nil {
+		conditions = append(conditions, "cohort_quarter >= toStartOfQuarter(?)")
+		args = append(args, filter.DateFrom.Time)
+	}
+	if filter != nil && filter.DateTo != nil {
+		conditions = append(conditions, "cohort_quarter <= ?")
+		args = append(args, filter.DateTo.Time)
+	}
+
+	// Выжившие через N лет: действующие и прекращенные не ранее чем через N лет
+	survived := make([]string, len(model.SurvivalHorizons))
+	for i, years := range model.SurvivalHorizons {
+		survived[i] = fmt.Sprintf("sumIf(entities_count, is_terminated = 0 OR lifetime_years >= %d)", years)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT
+			%s AS cohort,
+			sum(entities_count) AS total,
+			sumIf(entities_count, is_terminated = 0) AS active,
+			[%s] AS survived
+		FROM egrul.stats_survival_cohorts
+		WHERE %s
+		GROUP BY cohort
+		ORDER BY cohort
+	`, cohortExpr, strings.Join(survived, ", "), strings.Join(conditions, " AND "))
+
+	rows, err := r.client.conn.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("get survival cohorts: %w", err)
+	}
+	defer rows.Close()
+
+	result := make([]*model.SurvivalCohortCounts, 0)
+	for rows.Next() {
+		var cohort time.Time
+		var total, active uint64
+		var survivedCounts []uint64
+
+		if err := rows.Scan(&cohort, &total, &active, &survivedCounts); err != nil {
+			return nil, fmt.Errorf("scan survival cohort: %w", err)
+		}
+
+		counts := &model.SurvivalCohortCounts{
+			CohortStart: cohort,
+			Total:       int(total),
+			Active:      int(active),
+			Survived:    make([]int, len(survivedCounts)),
+		}
+		for i, c := range survivedCounts {
+			counts.Survived[i] = int(c)
+		}
+		result = append(result, counts)
+	}
+
+	return result, nil
+}
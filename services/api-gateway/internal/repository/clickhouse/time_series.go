@@ -0,0 +1,166 @@
+package clickhouse
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/egrul-system/services/api-gateway/internal/graph/model"
+	"go.uber.org/zap"
+)
+
+// GetTimeSeries возвращает ряд регистраций или прекращений (metric) с шагом granularity
+// в разрезе groupBy за период [from, to]. Без разреза и фильтра по компаниям месячные
+// и годовые ряды берутся из stats_registrations_by_month / stats_terminations_by_month,
+// иначе - из companies и entrepreneurs. Разрез по ОПФ строится только по компаниям.
+func (r *StatisticsRepository) GetTimeSeries(
+	ctx context.Context,
+	metric model.TimeSeriesMetric,
+	granularity model.TimeGranularity,
+	groupBy *model.TimeSeriesGroupBy,
+	entityType *model.EntityType,
+	filter *model.StatsFilter,
+	from, to time.Time,
+) ([]*model.GroupedTimeSeriesPoint, error) {
+	if metric != model.TimeSeriesMetricRegistrations && metric != model.TimeSeriesMetricTerminations {
+		return nil, fmt.Errorf("unsupported time series metric: %s", metric)
+	}
+
+	var query string
+	var args []interface{}
+
+	useAggregates := groupBy == nil && entityOnlyFilter(filter) == nil &&
+		(granularity == model.TimeGranularityMonth || granularity == model.TimeGranularityYear)
+
+	if useAggregates {
+		table, monthColumn := "egrul.stats_registrations_by_month", "registration_month"
+		if metric == model.TimeSeriesMetricTerminations {
+			table, monthColumn = "egrul.stats_terminations_by_month", "termination_month"
+		}
+		var rawEntityType string
+		if entityType != nil {
+			rawEntityType = strings.ToLower(string(*entityType))
+		}
+
+		query = fmt.Sprintf(`
+			SELECT
+				%[1]s AS period,
+				'' AS group_key,
+				'' AS group_name,
+				countMerge(count) AS value
+			FROM %[2]s
+			WHERE %[3]s >= toStartOfMonth(?) AND %[3]s <= ?
+			  AND (entity_type = ? OR ? = '')
+			GROUP BY period
+			ORDER BY period
+		`, periodTruncExpr(granularity, monthColumn), table, monthColumn)
+		args = []interface{}{from, to, rawEntityType, rawEntityType}
+	} else {
+		var parts []string
+		includeCompanies := entityType == nil || *entityType == model.EntityTypeCompany
+		includeEntrepreneurs := (entityType == nil || *entityType == model.EntityTypeEntrepreneur) &&
+			(groupBy == nil || *groupBy != model.TimeSeriesGroupByOpf)
+
+		if includeCompanies {
+			where, whereArgs := entityFilterSQL(entityOnlyFilter(filter), companyKey, "")
+			dateExpr := "registration_date"
+			if metric == model.TimeSeriesMetricTerminations {
+				dateExpr = companyTerminationDateExpr
+			}
+			parts = append(parts, timeSeriesPart("egrul.companies", dateExpr, granularity, groupBy, where))
+			args = append(args, from, to)
+			args = append(args, whereArgs...)
+		}
+		if includeEntrepreneurs {
+			where, whereArgs := entityFilterSQL(entityOnlyFilter(filter), entrepreneurKey, "")
+			dateExpr := "registration_date"
+			if metric == model.TimeSeriesMetricTerminations {
+				dateExpr = "termination_date"
+			}
+			parts = append(parts, timeSeriesPart("egrul.entrepreneurs", dateExpr, granularity, groupBy, where))
+			args = append(args, from, to)
+			args = append(args, whereArgs...)
+		}
+		if len(parts) == 0 {
+			return []*model.GroupedTimeSeriesPoint{}, nil
+		}
+
+		query = `
+			SELECT
+				period,
+				group_key,
+				anyIf(group_name, group_name != '') AS group_name,
+				sum(value) AS value
+			FROM (` + strings.Join(parts, "\n\t\t\t\tUNION ALL\n") + `)
+			GROUP BY period, group_key
+			ORDER BY period, value DESC, group_key
+		`
+	}
+
+	rows, err := r.client.conn.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("get time series: %w", err)
+	}
+	defer rows.Close()
+
+	result := make([]*model.GroupedTimeSeriesPoint, 0)
+	for rows.Next() {
+		var period time.Time
+		var groupKey, groupName string
+		var value uint64
+
+		if err := rows.Scan(&period, &groupKey, &groupName, &value); err != nil {
+			return nil, fmt.Errorf("scan time series point: %w", err)
+		}
+
+		point := &model.GroupedTimeSeriesPoint{
+			Period: period.Format("2006-01-02"),
+			Value:  int(value),
+		}
+		if groupBy != nil {
+			if *groupBy == model.TimeSeriesGroupByOkvedSection {
+				groupName = model.OkvedSectionName(groupKey)
+			}
+			point.GroupKey = &groupKey
+			point.GroupName = &groupName
+		}
+		result = append(result, point)
+	}
+
+	r.logger.Debug("GetTimeSeries completed",
+		zap.String("metric", string(metric)),
+		zap.String("granularity", string(granularity)),
+		zap.Bool("aggregates", useAggregates),
+		zap.Int("points", len(result)),
+	)
+	return result, nil
+}
+
+// timeSeriesPart подзапрос ряда по одной таблице; параметры: from, to, затем аргументы where
+func timeSeriesPart(table, dateExpr string, granularity model.TimeGranularity, groupBy *model.TimeSeriesGroupBy, where string) string {
+	keyExpr, nameExpr := "''", "''"
+	if groupBy != nil {
+		switch *groupBy {
+		case model.TimeSeriesGroupByRegion:
+			keyExpr, nameExpr = "ifNull(region_code, '')", "ifNull(region, '')"
+		case model.TimeSeriesGroupByOkvedSection:
+			keyExpr = okvedSectionExpr("okved_main_code")
+		case model.TimeSeriesGroupByOpf:
+			keyExpr, nameExpr = "ifNull(opf_code, '')", "ifNull(opf_name, '')"
+		}
+	}
+
+	return fmt.Sprintf(`
+				SELECT
+					%[1]s AS period,
+					%[2]s AS group_key,
+					any(%[3]s) AS group_name,
+					count() AS value
+				FROM %[4]s FINAL
+				WHERE %[5]s IS NOT NULL
+				  AND %[5]s >= ? AND %[5]s <= ?
+				  AND %[6]s
+				GROUP BY period, group_key`,
+		periodTruncExpr(granularity, "assumeNotNull("+dateExpr+")"), keyExpr, nameExpr, table, dateExpr, where)
+}
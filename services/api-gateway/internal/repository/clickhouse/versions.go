@@ -0,0 +1,251 @@
+package clickhouse
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/egrul-system/services/api-gateway/internal/graph/model"
+	"go.uber.org/zap"
+)
+
+// GetByOGRNAsOf возвращает версию карточки компании, действовавшую на дату asOf:
+// последнюю сохраненную версию с датой выписки не позже asOf.
+// Возвращает nil, если такой версии нет.
+func (r *CompanyRepository) GetByOGRNAsOf(ctx context.Context, ogrn string, asOf time.Time) (*model.Company, error) {
+	query := `
+		SELECT * FROM egrul.companies_versions FINAL
+		WHERE ogrn = ? AND extract_date <= ?
+		ORDER BY extract_date DESC, version_date DESC
+		LIMIT 1
+	`
+
+	rows, err := r.client.conn.Query(ctx, query, ogrn, asOf)
+	if err != nil {
+		return nil, fmt.Errorf("query company version: %w", err)
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		var row companyRow
+		if err := rows.ScanStruct(&row); err != nil {
+			return nil, fmt.Errorf("scan company version row: %w", err)
+		}
+		// Дополнительные ОКВЭД в отдельной таблице хранятся только для текущей версии,
+		// для исторической берем их из JSON самой версии
+		if len(row.OkvedAdditional) == 0 {
+			row.OkvedAdditional, row.OkvedAdditionalNames = parseAdditionalActivities(row.AdditionalActivities)
+		}
+		company := row.toModel()
+		company.VersionInfo = newVersionInfo(asOf, row.ExtractDate, row.VersionDate, row.LastGrn, row.LastGrnDate, row.SourceFile)
+		return company, nil
+	}
+
+	return nil, nil
+}
+
+// GetByOGRNIPAsOf возвращает версию карточки ИП, действовавшую на дату asOf.
+// Возвращает nil, если такой версии нет.
+func (r *EntrepreneurRepository) GetByOGRNIPAsOf(ctx context.Context, ogrnip string, asOf time.Time) (*model.Entrepreneur, error) {
+	query := `
+		SELECT * FROM egrul.entrepreneurs_versions FINAL
+		WHERE ogrnip = ? AND extract_date <= ?
+		ORDER BY extract_date DESC, version_date DESC
+		LIMIT 1
+	`
+
+	rows, err := r.client.conn.Query(ctx, query, ogrnip, asOf)
+	if err != nil {
+		return nil, fmt.Errorf("query entrepreneur version: %w", err)
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		var row entrepreneurRow
+		if err := rows.ScanStruct(&row); err != nil {
+			return nil, fmt.Errorf("scan entrepreneur version row: %w", err)
+		}
+		if len(row.OkvedAdditional) == 0 {
+			row.OkvedAdditional, row.OkvedAdditionalNames = parseAdditionalActivities(row.AdditionalActivities)
+		}
+		entrepreneur := row.toModel()
+		entrepreneur.VersionInfo = newVersionInfo(asOf, row.ExtractDate, row.VersionDate, row.LastGrn, row.LastGrnDate, row.SourceFile)
+		return entrepreneur, nil
+	}
+
+	return nil, nil
+}
+
+// GetByCompanyOGRNAsOf получает состав учредителей, загруженный вместе с версией
+// карточки от versionDate (последний сохраненный состав не позже этой даты)
+func (r *FounderRepository) GetByCompanyOGRNAsOf(ctx context.Context, ogrn string, versionDate time.Time, limit, offset int) ([]*model.Founder, error) {
+	query := `
+		SELECT
+			id, company_ogrn, company_inn, company_name,
+			founder_type, founder_ogrn, founder_inn, founder_name,
+			founder_last_name, founder_first_name, founder_middle_name,
+			founder_country, founder_citizenship,
+			share_nominal_value, share_percent
+		FROM egrul.founders_versions FINAL
+		WHERE company_ogrn = ?
+		  AND version_date = (
+			SELECT max(version_date) FROM egrul.founders_versions
+			WHERE company_ogrn = ? AND version_date <= ?
+		  )
+		ORDER BY share_percent DESC NULLS LAST, founder_name
+		LIMIT ? OFFSET ?
+	`
+
+	rows, err := r.client.conn.Query(ctx, query, ogrn, ogrn, versionDate, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("query founders version: %w", err)
+	}
+	defer rows.Close()
+
+	var founders []*model.Founder
+	for rows.Next() {
+		var row founderRow
+		if err := rows.Scan(
+			&row.ID,
+			&row.CompanyOgrn,
+			&row.CompanyInn,
+			&row.CompanyName,
+			&row.FounderType,
+			&row.FounderOgrn,
+			&row.FounderInn,
+			&row.FounderName,
+			&row.FounderLastName,
+			&row.FounderFirstName,
+			&row.FounderMiddleName,
+			&row.FounderCountry,
+			&row.FounderCitizenship,
+			&row.ShareNominalValue,
+			&row.SharePercent,
+		); err != nil {
+			return nil, fmt.Errorf("scan founder version row: %w", err)
+		}
+		founders = append(founders, row.toModel())
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate founder version rows: %w", err)
+	}
+
+	r.logger.Debug("GetByCompanyOGRNAsOf completed",
+		zap.String("ogrn", ogrn),
+		zap.Time("version_date", versionDate),
+		zap.Int("count", len(founders)),
+	)
+	return founders, nil
+}
+
+// newVersionInfo описывает версию выписки, из которой восстановлена карточка
+func newVersionInfo(asOf time.Time, extractDate sql.NullTime, versionDate time.Time, lastGrn sql.NullString, lastGrnDate sql.NullTime, sourceFile sql.NullString) *model.VersionInfo {
+	info := &model.VersionInfo{
+		AsOf:        model.Date{Time: asOf},
+		VersionDate: model.Date{Time: versionDate},
+	}
+	if extractDate.Valid {
+		info.ExtractDate = model.NewDate(extractDate.Time)
+	}
+	if lastGrn.Valid {
+		info.LastGrn = &lastGrn.String
+	}
+	if lastGrnDate.Valid {
+		info.LastGrnDate = model.NewDate(lastGrnDate.Time)
+	}
+	if sourceFile.Valid {
+		info.SourceFile = &sourceFile.String
+	}
+	return info
+}
+
+// parseAdditionalActivities разбирает JSON массив дополнительных ОКВЭД
+// вида [{"code": "...", "name": "..."}] из колонки additional_activities
+func parseAdditionalActivities(raw sql.NullString) ([]string, []string) {
+	if !raw.Valid || raw.String == "" {
+		return nil, nil
+	}
+
+	var items []struct {
+		Code string `json:"code"`
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal([]byte(raw.String), &items); err != nil {
+		return nil, nil
+	}
+
+	codes := make([]string, 0, len(items))
+	names := make([]string, 0, len(items))
+	for _, item := range items {
+		if item.Code == "" {
+			continue
+		}
+		codes = append(codes, item.Code)
+		names = append(names, item.Name)
+	}
+	return codes, names
+}
+
+// GetByEntityOGRNAsOf получает лицензии в составе, загруженном вместе с версией
+// карточки от versionDate (последний сохраненный состав не позже этой даты)
+func (r *LicenseRepository) GetByEntityOGRNAsOf(ctx context.Context, ogrn string, versionDate time.Time) ([]*model.License, error) {
+	query := `
+		SELECT * FROM egrul.licenses_versions FINAL
+		WHERE entity_ogrn = ?
+		  AND version_date = (
+			SELECT max(version_date) FROM egrul.licenses_versions
+			WHERE entity_ogrn = ? AND version_date <= ?
+		  )
+		ORDER BY start_date DESC NULLS LAST
+	`
+
+	rows, err := r.client.conn.Query(ctx, query, ogrn, ogrn, versionDate)
+	if err != nil {
+		return nil, fmt.Errorf("query licenses version: %w", err)
+	}
+	defer rows.Close()
+
+	var licenses []*model.License
+	for rows.Next() {
+		var row licenseRow
+		if err := rows.ScanStruct(&row); err != nil {
+			return nil, fmt.Errorf("scan license version row: %w", err)
+		}
+		licenses = append(licenses, row.toModel())
+	}
+
+	return licenses, rows.Err()
+}
+
+// GetByCompanyOGRNAsOf получает филиалы в составе, загруженном вместе с версией
+// карточки от versionDate (последний сохраненный состав не позже этой даты)
+func (r *BranchRepository) GetByCompanyOGRNAsOf(ctx context.Context, ogrn string, versionDate time.Time) ([]*model.Branch, error) {
+	query := `
+		SELECT * FROM egrul.branches_versions FINAL
+		WHERE company_ogrn = ?
+		  AND version_date = (
+			SELECT max(version_date) FROM egrul.branches_versions
+			WHERE company_ogrn = ? AND version_date <= ?
+		  )
+		ORDER BY branch_type, branch_name NULLS LAST
+	`
+
+	rows, err := r.client.conn.Query(ctx, query, ogrn, ogrn, versionDate)
+	if err != nil {
+		return nil, fmt.Errorf("query branches version: %w", err)
+	}
+	defer rows.Close()
+
+	var branches []*model.Branch
+	for rows.Next() {
+		var row branchRow
+		if err := rows.ScanStruct(&row); err != nil {
+			return nil, fmt.Errorf("scan branch version row: %w", err)
+		}
+		branches = append(branches, row.toModel())
+	}
+
+	return branches, rows.Err()
+}
@@ -6,7 +6,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"strings"
 	"time"
 
 	"github.com/egrul-system/services/api-gateway/internal/graph/model"
@@ -30,114 +29,8 @@ func NewESCompanyRepository(client *elasticsearch.Client, logger *zap.Logger) *E
 
 // Search выполняет полнотекстовый поиск компаний в Elasticsearch
 func (r *ESCompanyRepository) Search(ctx context.Context, query string, limit, offset int) ([]*model.Company, error) {
-	// Построение multi-match запроса с boost
-	searchQuery := map[string]interface{}{
-		"query": map[string]interface{}{
-			"bool": map[string]interface{}{
-				"should": []map[string]interface{}{
-					// Exact match по ОГРН (boost 100)
-					{
-						"term": map[string]interface{}{
-							"ogrn": map[string]interface{}{
-								"value": query,
-								"boost": 100,
-							},
-						},
-					},
-					// Exact match по ИНН (boost 100)
-					{
-						"term": map[string]interface{}{
-							"inn": map[string]interface{}{
-								"value": query,
-								"boost": 100,
-							},
-						},
-					},
-					// Морфологический поиск по полному наименованию (boost 10)
-					{
-						"match": map[string]interface{}{
-							"full_name": map[string]interface{}{
-								"query": query,
-								"boost": 10,
-							},
-						},
-					},
-					// Морфологический поиск по краткому наименованию (boost 5)
-					{
-						"match": map[string]interface{}{
-							"short_name": map[string]interface{}{
-								"query": query,
-								"boost": 5,
-							},
-						},
-					},
-					// Поиск по ФИО руководителя (boost 3)
-					{
-						"match": map[string]interface{}{
-							"head_last_name": map[string]interface{}{
-								"query": query,
-								"boost": 3,
-							},
-						},
-					},
-					{
-						"match": map[string]interface{}{
-							"head_first_name": map[string]interface{}{
-								"query": query,
-								"boost": 3,
-							},
-						},
-					},
-				},
-			},
-		},
-		"from": offset,
-		"size": limit,
-	}
-
-	// Сериализация запроса
-	var buf bytes.Buffer
-	if err := json.NewEncoder(&buf).Encode(searchQuery); err != nil {
-		return nil, fmt.Errorf("encode search query: %w", err)
-	}
-
-	r.logger.Debug("Elasticsearch search query",
-		zap.String("query", query),
-		zap.Int("limit", limit),
-		zap.Int("offset", offset),
-		zap.String("body", buf.String()))
-
-	// Выполнение запроса
-	res, err := r.client.Search(
-		r.client.Search.WithContext(ctx),
-		r.client.Search.WithIndex("egrul_companies"),
-		r.client.Search.WithBody(&buf),
-		r.client.Search.WithTrackTotalHits(true),
-	)
-	if err != nil {
-		return nil, fmt.Errorf("elasticsearch search request failed: %w", err)
-	}
-	defer res.Body.Close()
-
-	if res.IsError() {
-		bodyBytes, _ := io.ReadAll(res.Body)
-		r.logger.Error("Elasticsearch search error",
-			zap.String("status", res.Status()),
-			zap.String("response", string(bodyBytes)))
-		return nil, fmt.Errorf("elasticsearch returned error: %s", res.Status())
-	}
-
-	// Парсинг ответа
-	companies, err := r.parseSearchResponse(res.Body)
-	if err != nil {
-		return nil, fmt.Errorf("parse search response: %w", err)
-	}
-
-	r.logger.Info("Elasticsearch search completed",
-		zap.String("query", query),
-		zap.Int("results", len(companies)))
-
-	return companies, nil
+	companies, _, err := r.SearchWithTotalAndFilters(ctx, query, nil, limit, offset)
+	return companies, err
 }
 
 // SearchWithTotal выполняет поиск и возвращает компании с общим количеством найденных
@@ -147,141 +40,10 @@ func (r *ESCompanyRepository) SearchWithTotal(ctx context.Context, query string,
 
 // SearchWithTotalAndFilters выполняет поиск с фильтрами и возвращает компании с общим количеством
 func (r *ESCompanyRepository) SearchWithTotalAndFilters(ctx context.Context, query string, filter *model.CompanyFilter, limit, offset int) ([]*model.Company, int, error) {
-	// Build bool query with should (for text search) and must (for filters)
-	boolQuery := map[string]interface{}{}
-
-	// Если query задан, добавляем текстовый поиск
-	if query != "" {
-		boolQuery["should"] = []map[string]interface{}{
-			// Exact match по ОГРН (boost 100)
-			{
-				"term": map[string]interface{}{
-					"ogrn": map[string]interface{}{
-						"value": query,
-						"boost": 100,
-					},
-				},
-			},
-			// Exact match по ИНН (boost 100)
-			{
-				"term": map[string]interface{}{
-					"inn": map[string]interface{}{
-						"value": query,
-						"boost": 100,
-					},
-				},
-			},
-			// Морфологический поиск по полному наименованию (boost 10)
-			{
-				"match": map[string]interface{}{
-					"full_name": map[string]interface{}{
-						"query": query,
-						"boost": 10,
-					},
-				},
-			},
-			// Морфологический поиск по краткому наименованию (boost 5)
-			{
-				"match": map[string]interface{}{
-					"short_name": map[string]interface{}{
-						"query": query,
-						"boost": 5,
-					},
-				},
-			},
-			// Поиск по ФИО руководителя (boost 3)
-			{
-				"multi_match": map[string]interface{}{
-					"query":  query,
-					"fields": []string{"head_last_name", "head_first_name", "head_middle_name"},
-					"boost":  3,
-				},
-			},
-		}
-		boolQuery["minimum_should_match"] = 1
-	}
-
-	// Add filters if provided
-	if filter != nil {
-		mustClauses := []map[string]interface{}{}
-
-		if filter.RegionCode != nil && *filter.RegionCode != "" {
-			mustClauses = append(mustClauses, map[string]interface{}{
-				"term": map[string]interface{}{
-					"region_code": *filter.RegionCode,
-				},
-			})
-		}
-
-		if filter.Status != nil {
-			mustClauses = append(mustClauses, map[string]interface{}{
-				"term": map[string]interface{}{
-					// Статусы в ES хранятся в lowercase
-					"status": strings.ToLower(string(*filter.Status)),
-				},
-			})
-		}
-
-		if filter.StatusIn != nil && len(filter.StatusIn) > 0 {
-			statuses := make([]string, len(filter.StatusIn))
-			for i, s := range filter.StatusIn {
-				// Статусы в ES хранятся в lowercase
-				statuses[i] = strings.ToLower(string(s))
-			}
-			mustClauses = append(mustClauses, map[string]interface{}{
-				"terms": map[string]interface{}{
-					"status": statuses,
-				},
-			})
-		}
-
-		if filter.Okved != nil && *filter.Okved != "" {
-			mustClauses = append(mustClauses, map[string]interface{}{
-				"bool": map[string]interface{}{
-					"should": []map[string]interface{}{
-						{
-							"prefix": map[string]interface{}{
-								"okved_main_code": *filter.Okved,
-							},
-						},
-						{
-							"prefix": map[string]interface{}{
-								"okved_additional": *filter.Okved,
-							},
-						},
-					},
-					"minimum_should_match": 1,
-				},
-			})
-		}
-
-		// Date range filters for registration_date
-		if filter.RegisteredAfter != nil || filter.RegisteredBefore != nil {
-			rangeClause := map[string]interface{}{}
-			if filter.RegisteredAfter != nil {
-				rangeClause["gte"] = filter.RegisteredAfter.Time.Format("2006-01-02")
-			}
-			if filter.RegisteredBefore != nil {
-				rangeClause["lte"] = filter.RegisteredBefore.Time.Format("2006-01-02")
-			}
-			mustClauses = append(mustClauses, map[string]interface{}{
-				"range": map[string]interface{}{
-					"registration_date": rangeClause,
-				},
-			})
-		}
-
-		if len(mustClauses) > 0 {
-			boolQuery["must"] = mustClauses
-		}
-	}
-
 	searchQuery := map[string]interface{}{
-		"query": map[string]interface{}{
-			"bool": boolQuery,
-		},
-		"from": offset,
-		"size": limit,
+		"query": rankedQuery(companyTextQuery(query), filterQueries(companyFilterClauses(filter), "")),
+		"from":  offset,
+		"size":  limit,
 	}
 
 	var buf bytes.Buffer
@@ -465,3 +227,4 @@ func (r *ESCompanyRepository) GetByINN(ctx context.Context, inn string) (*model.
 func (r *ESCompanyRepository) List(ctx context.Context, filter *model.CompanyFilter, pagination *model.Pagination, sort *model.CompanySort) ([]*model.Company, int, error) {
 	return nil, 0, fmt.Errorf("List not supported in Elasticsearch repository, use ClickHouse instead")
 }
+
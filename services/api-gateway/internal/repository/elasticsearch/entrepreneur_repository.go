@@ -6,7 +6,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"strings"
 	"time"
 
 	"github.com/egrul-system/services/api-gateway/internal/graph/model"
@@ -30,115 +29,8 @@ func NewESEntrepreneurRepository(client *elasticsearch.Client, logger *zap.Logge
 
 // Search выполняет полнотекстовый поиск ИП в Elasticsearch
 func (r *ESEntrepreneurRepository) Search(ctx context.Context, query string, limit, offset int) ([]*model.Entrepreneur, error) {
-	// Построение multi-match запроса с boost
-	searchQuery := map[string]interface{}{
-		"query": map[string]interface{}{
-			"bool": map[string]interface{}{
-				"should": []map[string]interface{}{
-					// Exact match по ОГРНИП (boost 100)
-					{
-						"term": map[string]interface{}{
-							"ogrnip": map[string]interface{}{
-								"value": query,
-								"boost": 100,
-							},
-						},
-					},
-					// Exact match по ИНН (boost 100)
-					{
-						"term": map[string]interface{}{
-							"inn": map[string]interface{}{
-								"value": query,
-								"boost": 100,
-							},
-						},
-					},
-					// Морфологический поиск по полному имени (boost 10)
-					{
-						"match": map[string]interface{}{
-							"full_name": map[string]interface{}{
-								"query": query,
-								"boost": 10,
-							},
-						},
-					},
-					// Поиск по фамилии (boost 8)
-					{
-						"match": map[string]interface{}{
-							"last_name": map[string]interface{}{
-								"query": query,
-								"boost": 8,
-							},
-						},
-					},
-					// Поиск по имени (boost 5)
-					{
-						"match": map[string]interface{}{
-							"first_name": map[string]interface{}{
-								"query": query,
-								"boost": 5,
-							},
-						},
-					},
-					// Поиск по отчеству (boost 3)
-					{
-						"match": map[string]interface{}{
-							"middle_name": map[string]interface{}{
-								"query": query,
-								"boost": 3,
-							},
-						},
-					},
-				},
-			},
-		},
-		"from": offset,
-		"size": limit,
-	}
-
-	// Сериализация запроса
-	var buf bytes.Buffer
-	if err := json.NewEncoder(&buf).Encode(searchQuery); err != nil {
-		return nil, fmt.Errorf("encode search query: %w", err)
-	}
-
-	r.logger.Debug("Elasticsearch search query",
-		zap.String("query", query),
-		zap.Int("limit", limit),
-		zap.Int("offset", offset),
-		zap.String("body", buf.String()))
-
-	// Выполнение запроса
-	res, err := r.client.Search(
-		r.client.Search.WithContext(ctx),
-		r.client.Search.WithIndex("egrul_entrepreneurs"),
-		r.client.Search.WithBody(&buf),
-		r.client.Search.WithTrackTotalHits(true),
-	)
-	if err != nil {
-		return nil, fmt.Errorf("elasticsearch search request failed: %w", err)
-	}
-	defer res.Body.Close()
-
-	if res.IsError() {
-		bodyBytes, _ := io.ReadAll(res.Body)
-		r.logger.Error("Elasticsearch search error",
-			zap.String("status", res.Status()),
-			zap.String("response", string(bodyBytes)))
-		return nil, fmt.Errorf("elasticsearch returned error: %s", res.Status())
-	}
-
-	// Парсинг ответа
-	entrepreneurs, err := r.parseSearchResponse(res.Body)
-	if err != nil {
-		return nil, fmt.Errorf("parse search response: %w", err)
-	}
-
-	r.logger.Info("Elasticsearch search completed",
-		zap.String("query", query),
-		zap.Int("results", len(entrepreneurs)))
-
-	return entrepreneurs, nil
+	entrepreneurs, _, err := r.SearchWithTotalAndFilters(ctx, query, nil, limit, offset)
+	return entrepreneurs, err
 }
 
 // parseSearchResponse парсит ответ Elasticsearch в модели Entrepreneur
@@ -250,123 +142,10 @@ func (r *ESEntrepreneurRepository) SearchWithTotal(ctx context.Context, query st
 
 // SearchWithTotalAndFilters выполняет поиск с фильтрами и возвращает ИП с общим количеством
 func (r *ESEntrepreneurRepository) SearchWithTotalAndFilters(ctx context.Context, query string, filter *model.EntrepreneurFilter, limit, offset int) ([]*model.Entrepreneur, int, error) {
-	// Build bool query with should (for text search) and must (for filters)
-	boolQuery := map[string]interface{}{}
-
-	// Если query задан, добавляем текстовый поиск
-	if query != "" {
-		boolQuery["should"] = []map[string]interface{}{
-			// Exact match по ОГРНИП (boost 100)
-			{
-				"term": map[string]interface{}{
-					"ogrnip": map[string]interface{}{
-						"value": query,
-						"boost": 100,
-					},
-				},
-			},
-			// Exact match по ИНН (boost 100)
-			{
-				"term": map[string]interface{}{
-					"inn": map[string]interface{}{
-						"value": query,
-						"boost": 100,
-					},
-				},
-			},
-			// Морфологический поиск по ФИО (boost 10)
-			{
-				"multi_match": map[string]interface{}{
-					"query":  query,
-					"fields": []string{"last_name^3", "first_name^2", "middle_name"},
-					"boost":  10,
-				},
-			},
-		}
-		boolQuery["minimum_should_match"] = 1
-	}
-
-	// Add filters if provided
-	if filter != nil {
-		mustClauses := []map[string]interface{}{}
-
-		if filter.RegionCode != nil && *filter.RegionCode != "" {
-			mustClauses = append(mustClauses, map[string]interface{}{
-				"term": map[string]interface{}{
-					"region_code": *filter.RegionCode,
-				},
-			})
-		}
-
-		if filter.Status != nil {
-			mustClauses = append(mustClauses, map[string]interface{}{
-				"term": map[string]interface{}{
-					// Статусы в ES хранятся в lowercase
-					"status": strings.ToLower(string(*filter.Status)),
-				},
-			})
-		}
-
-		if filter.StatusIn != nil && len(filter.StatusIn) > 0 {
-			statuses := make([]string, len(filter.StatusIn))
-			for i, s := range filter.StatusIn {
-				// Статусы в ES хранятся в lowercase
-				statuses[i] = strings.ToLower(string(s))
-			}
-			mustClauses = append(mustClauses, map[string]interface{}{
-				"terms": map[string]interface{}{
-					"status": statuses,
-				},
-			})
-		}
-
-		if filter.Okved != nil && *filter.Okved != "" {
-			mustClauses = append(mustClauses, map[string]interface{}{
-				"bool": map[string]interface{}{
-					"should": []map[string]interface{}{
-						{
-							"prefix": map[string]interface{}{
-								"okved_main_code": *filter.Okved,
-							},
-						},
-						{
-							"prefix": map[string]interface{}{
-								"okved_additional": *filter.Okved,
-							},
-						},
-					},
-					"minimum_should_match": 1,
-				},
-			})
-		}
-
-		// Date range filters for registration_date
-		if filter.RegisteredAfter != nil || filter.RegisteredBefore != nil {
-			rangeClause := map[string]interface{}{}
-			if filter.RegisteredAfter != nil {
-				rangeClause["gte"] = filter.RegisteredAfter.Time.Format("2006-01-02")
-			}
-			if filter.RegisteredBefore != nil {
-				rangeClause["lte"] = filter.RegisteredBefore.Time.Format("2006-01-02")
-			}
-			mustClauses = append(mustClauses, map[string]interface{}{
-				"range": map[string]interface{}{
-					"registration_date": rangeClause,
-				},
-			})
-		}
-
-		if len(mustClauses) > 0 {
-			boolQuery["must"] = mustClauses
-		}
-	}
-
 	searchQuery := map[string]interface{}{
-		"query": map[string]interface{}{
-			"bool": boolQuery,
-		},
-		"from": offset,
-		"size": limit,
+		"query": rankedQuery(entrepreneurTextQuery(query), filterQueries(entrepreneurFilterClauses(filter), "")),
+		"from":  offset,
+		"size":  limit,
 	}
 
 	var buf bytes.Buffer
@@ -433,3 +212,4 @@ func (r *ESEntrepreneurRepository) parseSearchResponseWithTotal(body io.Reader)
 
 	return entrepreneurs, esResponse.Hits.Total.Value, nil
 }
+
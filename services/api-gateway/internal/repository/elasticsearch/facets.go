@@ -0,0 +1,511 @@
+package elasticsearch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/egrul-system/services/api-gateway/internal/graph/model"
+	"github.com/elastic/go-elasticsearch/v8"
+	"go.uber.org/zap"
+)
+
+// Имена фасетов (совпадают с именами агрегаций в запросе)
+const (
+	facetStatus           = "status"
+	facetRegion           = "region"
+	facetOkvedSection     = "okvedSection"
+	facetOkvedMain        = "okvedMain"
+	facetOpf              = "opf"
+	facetRegistrationYear = "registrationYear"
+	facetCapital          = "capital"
+)
+
+// facetClause условие фильтра и фасет, который оно сужает. Агрегация фасета
+// строится по всем условиям, кроме своих, чтобы выбор значения не обнулял
+// счетчики остальных значений того же фасета.
+type facetClause struct {
+	facet  string
+	clause map[string]interface{}
+}
+
+// facetSpec описание агрегации фасета. labelField - поле _source, из которого
+// берется подпись значения (первый документ корзины)
+type facetSpec struct {
+	name       string
+	agg        map[string]interface{}
+	labelField string
+}
+
+// capitalRanges диапазоны уставного капитала для фасета capital
+var capitalRanges = []map[string]interface{}{
+	{"key": "lt_10k", "to": 10000},
+	{"key": "10k_100k", "from": 10000, "to": 100000},
+	{"key": "100k_1m", "from": 100000, "to": 1000000},
+	{"key": "1m_10m", "from": 1000000, "to": 10000000},
+	{"key": "10m_100m", "from": 10000000, "to": 100000000},
+	{"key": "gte_100m", "from": 100000000},
+}
+
+func termsFacet(name, field string, size int, labelField string) facetSpec {
+	return facetSpec{
+		name: name,
+		agg: map[string]interface{}{
+			"terms": map[string]interface{}{
+				"field": field,
+				"size":  size,
+			},
+		},
+		labelField: labelField,
+	}
+}
+
+func registrationYearFacet() facetSpec {
+	return facetSpec{
+		name: facetRegistrationYear,
+		agg: map[string]interface{}{
+			"date_histogram": map[string]interface{}{
+				"field":             "registration_date",
+				"calendar_interval": "year",
+				"format":            "yyyy",
+				"min_doc_count":     1,
+				"order":             map[string]interface{}{"_key": "desc"},
+			},
+		},
+	}
+}
+
+// companyFacets фасеты поиска компаний
+func companyFacets() []facetSpec {
+	return []facetSpec{
+		termsFacet(facetStatus, "status", 10, ""),
+		termsFacet(facetRegion, "region_code", 100, "region"),
+		termsFacet(facetOkvedSection, "okved_section", 25, ""),
+		termsFacet(facetOkvedMain, "okved_main_code", 20, "okved_main_name"),
+		termsFacet(facetOpf, "opf_code", 20, "opf_short_name"),
+		registrationYearFacet(),
+		{
+			name: facetCapital,
+			agg: map[string]interface{}{
+				"range": map[string]interface{}{
+					"field":  "capital_amount",
+					"ranges": capitalRanges,
+				},
+			},
+		},
+	}
+}
+
+// entrepreneurFacets фасеты поиска ИП: без ОПФ и уставного капитала
+func entrepreneurFacets() []facetSpec {
+	return []facetSpec{
+		termsFacet(facetStatus, "status", 10, ""),
+		termsFacet(facetRegion, "region_code", 100, "region"),
+		termsFacet(facetOkvedSection, "okved_section", 25, ""),
+		termsFacet(facetOkvedMain, "okved_main_code", 20, "okved_main_name"),
+		registrationYearFacet(),
+	}
+}
+
+// filterQueries возвращает запросы условий, пропуская условия фасета exclude
+func filterQueries(clauses []facetClause, exclude string) []map[string]interface{} {
+	queries := make([]map[string]interface{}, 0, len(clauses))
+	for _, c := range clauses {
+		if exclude != "" && c.facet == exclude {
+			continue
+		}
+		queries = append(queries, c.clause)
+	}
+	return queries
+}
+
+// commonFilterClauses условия, общие для компаний и ИП: регион, статус, ОКВЭД
+// и дата регистрации
+func commonFilterClauses(regionCode *string, status *model.EntityStatus, statusIn []model.EntityStatus, okved *string, registeredAfter, registeredBefore *model.Date) []facetClause {
+	var clauses []facetClause
+
+	if regionCode != nil && *regionCode != "" {
+		clauses = append(clauses, facetClause{facetRegion, map[string]interface{}{
+			"term": map[string]interface{}{
+				"region_code": *regionCode,
+			},
+		}})
+	}
+
+	if status != nil {
+		clauses = append(clauses, facetClause{facetStatus, map[string]interface{}{
+			"term": map[string]interface{}{
+				// Статусы в ES хранятся в lowercase
+				"status": strings.ToLower(string(*status)),
+			},
+		}})
+	}
+
+	if len(statusIn) > 0 {
+		statuses := make([]string, len(statusIn))
+		for i, s := range statusIn {
+			// Статусы в ES хранятся в lowercase
+			statuses[i] = strings.ToLower(string(s))
+		}
+		clauses = append(clauses, facetClause{facetStatus, map[string]interface{}{
+			"terms": map[string]interface{}{
+				"status": statuses,
+			},
+		}})
+	}
+
+	if okved != nil && *okved != "" {
+		code := strings.ToUpper(*okved)
+		if model.OkvedSectionName(code) != "" {
+			// Буква раздела ОКВЭД фильтрует по разделу основного вида деятельности
+			clauses = append(clauses, facetClause{facetOkvedSection, map[string]interface{}{
+				"term": map[string]interface{}{
+					"okved_section": code,
+				},
+			}})
+		} else {
+			clauses = append(clauses, facetClause{facetOkvedMain, map[string]interface{}{
+				"bool": map[string]interface{}{
+					"should": []map[string]interface{}{
+						{
+							"prefix": map[string]interface{}{
+								"okved_main_code": code,
+							},
+						},
+						{
+							"prefix": map[string]interface{}{
+								"okved_additional": code,
+							},
+						},
+					},
+					"minimum_should_match": 1,
+				},
+			}})
+		}
+	}
+
+	if registeredAfter != nil || registeredBefore != nil {
+		rangeClause := map[string]interface{}{}
+		if registeredAfter != nil {
+			rangeClause["gte"] = registeredAfter.Time.Format("2006-01-02")
+		}
+		if registeredBefore != nil {
+			rangeClause["lte"] = registeredBefore.Time.Format("2006-01-02")
+		}
+		clauses = append(clauses, facetClause{facetRegistrationYear, map[string]interface{}{
+			"range": map[string]interface{}{
+				"registration_date": rangeClause,
+			},
+		}})
+	}
+
+	return clauses
+}
+
+// buildFacetedQuery собирает запрос с фасетами. Фильтры вынесены в post_filter,
+// чтобы агрегации видели весь результат текстового поиска; каждая агрегация
+// фасета оборачивается в filter со всеми условиями, кроме собственных.
+func buildFacetedQuery(textQuery map[string]interface{}, clauses []facetClause, facets []facetSpec, limit, offset int) map[string]interface{} {
+	aggs := make(map[string]interface{}, len(facets))
+	for _, f := range facets {
+		bucketAgg := make(map[string]interface{}, len(f.agg)+1)
+		for k, v := range f.agg {
+			bucketAgg[k] = v
+		}
+		if f.labelField != "" {
+			bucketAgg["aggs"] = map[string]interface{}{
+				"label": map[string]interface{}{
+					"top_hits": map[string]interface{}{
+						"size":    1,
+						"_source": []string{f.labelField},
+					},
+				},
+			}
+		}
+		aggs[f.name] = map[string]interface{}{
+			"filter": map[string]interface{}{
+				"bool": map[string]interface{}{
+					"filter": filterQueries(clauses, f.name),
+				},
+			},
+			"aggs": map[string]interface{}{
+				"buckets": bucketAgg,
+			},
+		}
+	}
+
+	searchQuery := map[string]interface{}{
+		"query": rankedQuery(textQuery, nil),
+		"aggs":  aggs,
+		"from":  offset,
+		"size":  limit,
+	}
+	if len(clauses) > 0 {
+		searchQuery["post_filter"] = map[string]interface{}{
+			"bool": map[string]interface{}{
+				"filter": filterQueries(clauses, ""),
+			},
+		}
+	}
+	return searchQuery
+}
+
+// executeFacetedSearch выполняет запрос с фасетами и декодирует ответ в result.
+// result должен содержать поля hits и aggregations.
+func executeFacetedSearch(ctx context.Context, client *elasticsearch.Client, logger *zap.Logger, index string, searchQuery map[string]interface{}, result interface{}) error {
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(searchQuery); err != nil {
+		return fmt.Errorf("encode faceted search query: %w", err)
+	}
+
+	res, err := client.Search(
+		client.Search.WithContext(ctx),
+		client.Search.WithIndex(index),
+		client.Search.WithBody(&buf),
+		client.Search.WithTrackTotalHits(true),
+	)
+	if err != nil {
+		return fmt.Errorf("elasticsearch faceted search request failed: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		bodyBytes, _ := io.ReadAll(res.Body)
+		logger.Error("Elasticsearch faceted search error",
+			zap.String("status", res.Status()),
+			zap.String("response", string(bodyBytes)))
+		return fmt.Errorf("elasticsearch returned error: %s", res.Status())
+	}
+
+	if err := json.NewDecoder(res.Body).Decode(result); err != nil {
+		return fmt.Errorf("decode faceted search response: %w", err)
+	}
+	return nil
+}
+
+// esFacetAggregation ответ агрегации фасета (filter + вложенная buckets)
+type esFacetAggregation struct {
+	Buckets struct {
+		Buckets []esFacetBucket `json:"buckets"`
+	} `json:"buckets"`
+}
+
+type esFacetBucket struct {
+	Key         json.RawMessage `json:"key"`
+	KeyAsString string          `json:"key_as_string"`
+	DocCount    int             `json:"doc_count"`
+	From        *float64        `json:"from"`
+	To          *float64        `json:"to"`
+	Label       struct {
+		Hits struct {
+			Hits []struct {
+				Source map[string]interface{} `json:"_source"`
+			} `json:"hits"`
+		} `json:"hits"`
+	} `json:"label"`
+}
+
+// key строковое значение ключа корзины: key_as_string для гистограмм,
+// иначе сам ключ
+func (b *esFacetBucket) key() string {
+	if b.KeyAsString != "" {
+		return b.KeyAsString
+	}
+	var s string
+	if err := json.Unmarshal(b.Key, &s); err == nil {
+		return s
+	}
+	var n float64
+	if err := json.Unmarshal(b.Key, &n); err == nil {
+		return strconv.FormatFloat(n, 'f', -1, 64)
+	}
+	return string(b.Key)
+}
+
+// label подпись значения из документа корзины
+func (b *esFacetBucket) label(field string) *string {
+	if field == "" || len(b.Label.Hits.Hits) == 0 {
+		return nil
+	}
+	if s, ok := b.Label.Hits.Hits[0].Source[field].(string); ok && s != "" {
+		return &s
+	}
+	return nil
+}
+
+// parseFacets преобразует агрегации ответа в фасеты
+func parseFacets(aggregations map[string]esFacetAggregation, facets []facetSpec) *model.SearchFacets {
+	result := &model.SearchFacets{}
+	for _, f := range facets {
+		agg := aggregations[f.name]
+		buckets := make([]*model.FacetBucket, 0, len(agg.Buckets.Buckets))
+		for i := range agg.Buckets.Buckets {
+			b := &agg.Buckets.Buckets[i]
+			bucket := &model.FacetBucket{
+				Key:   b.key(),
+				Label: b.label(f.labelField),
+				Count: b.DocCount,
+				From:  b.From,
+				To:    b.To,
+			}
+			switch f.name {
+			case facetStatus:
+				// Значения приводятся к enum EntityStatus, как в остальном API
+				bucket.Key = string(model.ParseEntityStatus(bucket.Key))
+			case facetOkvedSection:
+				if name := model.OkvedSectionName(bucket.Key); name != "" {
+					bucket.Label = &name
+				}
+			case facetCapital:
+				if bucket.Count == 0 {
+					continue
+				}
+			}
+			buckets = append(buckets, bucket)
+		}
+
+		switch f.name {
+		case facetStatus:
+			result.Status = mergeFacetBuckets(buckets)
+		case facetRegion:
+			result.Region = buckets
+		case facetOkvedSection:
+			result.OkvedSection = buckets
+		case facetOkvedMain:
+			result.OkvedMain = buckets
+		case facetOpf:
+			result.Opf = buckets
+		case facetRegistrationYear:
+			result.RegistrationYear = buckets
+		case facetCapital:
+			result.Capital = buckets
+		}
+	}
+	return result
+}
+
+// mergeFacetBuckets складывает корзины с одинаковым ключом (несколько
+// статусов ES могут соответствовать одному значению enum)
+func mergeFacetBuckets(buckets []*model.FacetBucket) []*model.FacetBucket {
+	merged := make([]*model.FacetBucket, 0, len(buckets))
+	byKey := make(map[string]*model.FacetBucket, len(buckets))
+	for _, b := range buckets {
+		if existing, ok := byKey[b.Key]; ok {
+			existing.Count += b.Count
+			continue
+		}
+		byKey[b.Key] = b
+		merged = append(merged, b)
+	}
+	return merged
+}
+
+// companyFilterClauses условия фильтра компаний
+func companyFilterClauses(filter *model.CompanyFilter) []facetClause {
+	if filter == nil {
+		return nil
+	}
+
+	clauses := commonFilterClauses(filter.RegionCode, filter.Status, filter.StatusIn, filter.Okved, filter.RegisteredAfter, filter.RegisteredBefore)
+
+	if filter.OpfCode != nil && *filter.OpfCode != "" {
+		clauses = append(clauses, facetClause{facetOpf, map[string]interface{}{
+			"term": map[string]interface{}{
+				"opf_code": *filter.OpfCode,
+			},
+		}})
+	}
+
+	if filter.CapitalMin != nil || filter.CapitalMax != nil {
+		rangeClause := map[string]interface{}{}
+		if filter.CapitalMin != nil {
+			rangeClause["gte"] = *filter.CapitalMin
+		}
+		if filter.CapitalMax != nil {
+			rangeClause["lte"] = *filter.CapitalMax
+		}
+		clauses = append(clauses, facetClause{facetCapital, map[string]interface{}{
+			"range": map[string]interface{}{
+				"capital_amount": rangeClause,
+			},
+		}})
+	}
+
+	return clauses
+}
+
+// entrepreneurFilterClauses условия фильтра ИП
+func entrepreneurFilterClauses(filter *model.EntrepreneurFilter) []facetClause {
+	if filter == nil {
+		return nil
+	}
+	return commonFilterClauses(filter.RegionCode, filter.Status, filter.StatusIn, filter.Okved, filter.RegisteredAfter, filter.RegisteredBefore)
+}
+
+// SearchWithFacets выполняет поиск компаний и считает фасеты по тому же запросу
+func (r *ESCompanyRepository) SearchWithFacets(ctx context.Context, query string, filter *model.CompanyFilter, limit, offset int) (*model.CompanySearchResult, error) {
+	facets := companyFacets()
+	searchQuery := buildFacetedQuery(companyTextQuery(query), companyFilterClauses(filter), facets, limit, offset)
+
+	var esResponse struct {
+		Hits struct {
+			Total struct {
+				Value int `json:"value"`
+			} `json:"total"`
+			Hits []struct {
+				Source esCompanyDocument `json:"_source"`
+			} `json:"hits"`
+		} `json:"hits"`
+		Aggregations map[string]esFacetAggregation `json:"aggregations"`
+	}
+	if err := executeFacetedSearch(ctx, r.client, r.logger, companiesIndex, searchQuery, &esResponse); err != nil {
+		return nil, err
+	}
+
+	companies := make([]*model.Company, 0, len(esResponse.Hits.Hits))
+	for _, hit := range esResponse.Hits.Hits {
+		companies = append(companies, hit.Source.toModel())
+	}
+
+	return &model.CompanySearchResult{
+		Companies:  companies,
+		TotalCount: esResponse.Hits.Total.Value,
+		Facets:     parseFacets(esResponse.Aggregations, facets),
+	}, nil
+}
+
+// SearchWithFacets выполняет поиск ИП и считает фасеты по тому же запросу
+func (r *ESEntrepreneurRepository) SearchWithFacets(ctx context.Context, query string, filter *model.EntrepreneurFilter, limit, offset int) (*model.EntrepreneurSearchResult, error) {
+	facets := entrepreneurFacets()
+	searchQuery := buildFacetedQuery(entrepreneurTextQuery(query), entrepreneurFilterClauses(filter), facets, limit, offset)
+
+	var esResponse struct {
+		Hits struct {
+			Total struct {
+				Value int `json:"value"`
+			} `json:"total"`
+			Hits []struct {
+				Source esEntrepreneurDocument `json:"_source"`
+			} `json:"hits"`
+		} `json:"hits"`
+		Aggregations map[string]esFacetAggregation `json:"aggregations"`
+	}
+	if err := executeFacetedSearch(ctx, r.client, r.logger, entrepreneursIndex, searchQuery, &esResponse); err != nil {
+		return nil, err
+	}
+
+	entrepreneurs := make([]*model.Entrepreneur, 0, len(esResponse.Hits.Hits))
+	for _, hit := range esResponse.Hits.Hits {
+		entrepreneurs = append(entrepreneurs, hit.Source.toModel())
+	}
+
+	return &model.EntrepreneurSearchResult{
+		Entrepreneurs: entrepreneurs,
+		TotalCount:    esResponse.Hits.Total.Value,
+		Facets:        parseFacets(esResponse.Aggregations, facets),
+	}, nil
+}
@@ -0,0 +1,190 @@
+package elasticsearch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/egrul-system/services/api-gateway/internal/graph/model"
+	"github.com/elastic/go-elasticsearch/v8"
+	"go.uber.org/zap"
+)
+
+const (
+	companiesIndex     = "egrul_companies"
+	entrepreneursIndex = "egrul_entrepreneurs"
+
+	// suggestTimeout лимит времени поиска на стороне Elasticsearch: подсказки
+	// должны укладываться в 50 мс, неполный ответ лучше опоздавшего
+	suggestTimeout = 40 * time.Millisecond
+)
+
+// ESSuggestRepository подсказки поисковой строки по полям name_suggest
+// (search_as_you_type), ИНН, ОГРН и ОГРНИП
+type ESSuggestRepository struct {
+	client *elasticsearch.Client
+	logger *zap.Logger
+}
+
+// NewESSuggestRepository создает репозиторий подсказок
+func NewESSuggestRepository(client *elasticsearch.Client, logger *zap.Logger) *ESSuggestRepository {
+	return &ESSuggestRepository{
+		client: client,
+		logger: logger.Named("es_suggest_repo"),
+	}
+}
+
+// esSuggestDocument поля документа, нужные для подсказки
+type esSuggestDocument struct {
+	OGRN       string  `json:"ogrn"`
+	OGRNIP     string  `json:"ogrnip"`
+	INN        string  `json:"inn"`
+	FullName   string  `json:"full_name"`
+	ShortName  *string `json:"short_name"`
+	Status     string  `json:"status"`
+	RegionCode *string `json:"region_code"`
+	Region     *string `json:"region"`
+}
+
+// Suggest ищет подсказки одним запросом по индексам компаний и/или ИП.
+// Префикс из цифр ищется по ИНН, ОГРН и ОГРНИП, иначе - по наименованию.
+// Действующие субъекты поднимаются выше.
+func (r *ESSuggestRepository) Suggest(ctx context.Context, prefix string, includeCompanies, includeEntrepreneurs bool, limit int) ([]*model.Suggestion, error) {
+	var indices []string
+	if includeCompanies {
+		indices = append(indices, companiesIndex)
+	}
+	if includeEntrepreneurs {
+		indices = append(indices, entrepreneursIndex)
+	}
+	if len(indices) == 0 {
+		return []*model.Suggestion{}, nil
+	}
+
+	var match map[string]interface{}
+	if isDigits(prefix) {
+		should := make([]map[string]interface{}, 0, 3)
+		for _, field := range []string{"inn", "ogrn", "ogrnip"} {
+			should = append(should, map[string]interface{}{
+				"prefix": map[string]interface{}{field: prefix},
+			})
+		}
+		match = map[string]interface{}{
+			"bool": map[string]interface{}{
+				"should":               should,
+				"minimum_should_match": 1,
+			},
+		}
+	} else {
+		match = map[string]interface{}{
+			"multi_match": map[string]interface{}{
+				"query":    prefix,
+				"type":     "bool_prefix",
+				"operator": "and",
+				"fields":   []string{"name_suggest", "name_suggest._2gram", "name_suggest._3gram"},
+			},
+		}
+	}
+
+	searchQuery := map[string]interface{}{
+		"size":    limit,
+		"_source": []string{"ogrn", "ogrnip", "inn", "full_name", "short_name", "status", "region_code", "region"},
+		"query": map[string]interface{}{
+			"bool": map[string]interface{}{
+				"must": match,
+				"should": []map[string]interface{}{
+					{
+						"term": map[string]interface{}{
+							"status": map[string]interface{}{
+								"value": "active",
+								"boost": 2,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(searchQuery); err != nil {
+		return nil, fmt.Errorf("encode suggest query: %w", err)
+	}
+
+	res, err := r.client.Search(
+		r.client.Search.WithContext(ctx),
+		r.client.Search.WithIndex(indices...),
+		r.client.Search.WithBody(&buf),
+		r.client.Search.WithTimeout(suggestTimeout),
+		r.client.Search.WithTrackTotalHits(false),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("elasticsearch suggest request failed: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		bodyBytes, _ := io.ReadAll(res.Body)
+		r.logger.Error("Elasticsearch suggest error",
+			zap.String("status", res.Status()),
+			zap.String("response", string(bodyBytes)))
+		return nil, fmt.Errorf("elasticsearch returned error: %s", res.Status())
+	}
+
+	var esResponse struct {
+		TimedOut bool `json:"timed_out"`
+		Hits     struct {
+			Hits []struct {
+				Index  string            `json:"_index"`
+				Source esSuggestDocument `json:"_source"`
+			} `json:"hits"`
+		} `json:"hits"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&esResponse); err != nil {
+		return nil, fmt.Errorf("decode suggest response: %w", err)
+	}
+	if esResponse.TimedOut {
+		r.logger.Debug("Elasticsearch suggest timed out, returning partial results",
+			zap.String("prefix", prefix))
+	}
+
+	suggestions := make([]*model.Suggestion, 0, len(esResponse.Hits.Hits))
+	for _, hit := range esResponse.Hits.Hits {
+		doc := hit.Source
+		suggestion := &model.Suggestion{
+			Inn:        doc.INN,
+			Name:       doc.FullName,
+			Status:     model.ParseEntityStatus(doc.Status),
+			RegionCode: doc.RegionCode,
+			Region:     doc.Region,
+		}
+		// Индекс может быть алиасом с суффиксом версии, поэтому сравниваем по префиксу
+		if strings.HasPrefix(hit.Index, entrepreneursIndex) {
+			suggestion.EntityType = model.EntityTypeEntrepreneur
+			suggestion.ID = doc.OGRNIP
+		} else {
+			suggestion.EntityType = model.EntityTypeCompany
+			suggestion.ID = doc.OGRN
+			if doc.ShortName != nil && *doc.ShortName != "" {
+				suggestion.Name = *doc.ShortName
+			}
+		}
+		suggestions = append(suggestions, suggestion)
+	}
+
+	return suggestions, nil
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if !unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return s != ""
+}
@@ -0,0 +1,278 @@
+package elasticsearch
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// fuzzyPrefixLength число первых символов, которые должны совпасть точно:
+// ограничивает разрастание нечеткого запроса и отсекает случайные совпадения
+const fuzzyPrefixLength = 1
+
+// legalFormPhrases полные наименования организационно-правовых форм,
+// которые пользователи вводят вместе с названием
+var legalFormPhrases = regexp.MustCompile(`(?i)(общество\s+с\s+ограниченной\s+ответственностью|(публичное|непубличное|закрытое|открытое)?\s*акционерное\s+общество)`)
+
+// legalForms сокращения организационно-правовых форм (в нижнем регистре)
+var legalForms = map[string]bool{
+	"ооо": true, "оао": true, "зао": true, "пао": true, "ао": true, "нао": true,
+	"ип": true, "нко": true, "ано": true, "муп": true, "гуп": true, "фгуп": true,
+	"llc": true, "ltd": true, "jsc": true, "pjsc": true,
+}
+
+var quoteReplacer = strings.NewReplacer(
+	"«", " ", "»", " ", "\"", " ", "„", " ", "“", " ", "”", " ", "'", " ",
+)
+
+// normalizeSearchQuery убирает из строки поиска кавычки и организационно-правовую
+// форму: «ООО "Ромашка"» -> «Ромашка». Если кроме формы ничего нет, строка
+// возвращается без кавычек как есть.
+func normalizeSearchQuery(query string) string {
+	unquoted := strings.Join(strings.Fields(quoteReplacer.Replace(query)), " ")
+
+	var words []string
+	for _, w := range strings.Fields(legalFormPhrases.ReplaceAllString(unquoted, " ")) {
+		if !legalForms[strings.ToLower(strings.Trim(w, ".,"))] {
+			words = append(words, w)
+		}
+	}
+	if len(words) == 0 {
+		return unquoted
+	}
+	return strings.Join(words, " ")
+}
+
+// latinToCyrillicMulti многобуквенные сочетания латиницы, проверяются до одиночных букв
+var latinToCyrillicMulti = []struct{ from, to string }{
+	{"shch", "щ"}, {"sch", "щ"}, {"sh", "ш"}, {"ch", "ч"}, {"zh", "ж"}, {"kh", "х"},
+	{"ts", "ц"}, {"yu", "ю"}, {"ya", "я"}, {"yo", "ё"}, {"ye", "е"},
+}
+
+var latinToCyrillicSingle = map[rune]string{
+	'a': "а", 'b': "б", 'c': "к", 'd': "д", 'e': "е", 'f': "ф", 'g': "г", 'h': "х",
+	'i': "и", 'j': "й", 'k': "к", 'l': "л", 'm': "м", 'n': "н", 'o': "о", 'p': "п",
+	'q': "к", 'r': "р", 's': "с", 't': "т", 'u': "у", 'v': "в", 'w': "в", 'x': "кс",
+	'y': "ы", 'z': "з",
+}
+
+var cyrillicToLatin = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "e", 'ж': "zh",
+	'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o",
+	'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u", 'ф': "f", 'х': "kh", 'ц': "ts",
+	'ч': "ch", 'ш': "sh", 'щ': "shch", 'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "yu",
+	'я': "ya",
+}
+
+// transliterateToCyrillic переводит латиницу в кириллицу ("gazprom" -> "газпром")
+func transliterateToCyrillic(s string) string {
+	s = strings.ToLower(s)
+	var b strings.Builder
+	for i := 0; i < len(s); {
+		if to, n := latinDigraph(s[i:]); n > 0 {
+			b.WriteString(to)
+			i += n
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if c, ok := latinToCyrillicSingle[r]; ok {
+			b.WriteString(c)
+		} else {
+			b.WriteRune(r)
+		}
+		i += size
+	}
+	return b.String()
+}
+
+func latinDigraph(s string) (string, int) {
+	for _, m := range latinToCyrillicMulti {
+		if strings.HasPrefix(s, m.from) {
+			return m.to, len(m.from)
+		}
+	}
+	return "", 0
+}
+
+// transliterateToLatin переводит кириллицу в латиницу ("яндекс" -> "yandeks")
+func transliterateToLatin(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(s) {
+		if l, ok := cyrillicToLatin[r]; ok {
+			b.WriteString(l)
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// searchVariants варианты строки поиска: сама строка и ее транслитерация, если
+// строка написана целиком латиницей или целиком кириллицей
+func searchVariants(query string) []string {
+	var hasLatin, hasCyrillic bool
+	for _, r := range query {
+		switch {
+		case r < unicode.MaxASCII && unicode.IsLetter(r):
+			hasLatin = true
+		case unicode.Is(unicode.Cyrillic, r):
+			hasCyrillic = true
+		}
+	}
+
+	variants := []string{query}
+	var translit string
+	switch {
+	case hasLatin && !hasCyrillic:
+		translit = transliterateToCyrillic(query)
+	case hasCyrillic && !hasLatin:
+		translit = transliterateToLatin(query)
+	}
+	if translit != "" && translit != strings.ToLower(query) {
+		variants = append(variants, translit)
+	}
+	return variants
+}
+
+// fuzzyMatch нечеткий поиск по полям с ограниченным расстоянием редактирования
+// (AUTO: 0 правок до 3 символов, 1 - до 6, дальше 2)
+func fuzzyMatch(query string, fields []string, boost float64) map[string]interface{} {
+	return map[string]interface{}{
+		"multi_match": map[string]interface{}{
+			"query":          query,
+			"fields":         fields,
+			"fuzziness":      "AUTO",
+			"prefix_length":  fuzzyPrefixLength,
+			"max_expansions": 50,
+			"operator":       "and",
+			"boost":          boost,
+		},
+	}
+}
+
+// exactIDMatch точное совпадение по идентификатору (ИНН, ОГРН, ОГРНИП)
+func exactIDMatch(field, query string) map[string]interface{} {
+	return map[string]interface{}{
+		"term": map[string]interface{}{
+			field: map[string]interface{}{
+				"value": query,
+				"boost": 100,
+			},
+		},
+	}
+}
+
+// activeStatusBoost поднимает действующие субъекты выше в выдаче
+func activeStatusBoost() map[string]interface{} {
+	return map[string]interface{}{
+		"term": map[string]interface{}{
+			"status": map[string]interface{}{
+				"value": "active",
+				"boost": 2,
+			},
+		},
+	}
+}
+
+// rankedQuery объединяет текстовый запрос и фильтры: текст и фильтры обязательны,
+// действующий статус только повышает релевантность
+func rankedQuery(textQuery map[string]interface{}, filters []map[string]interface{}) map[string]interface{} {
+	must := make([]map[string]interface{}, 0, len(filters)+1)
+	if textQuery != nil {
+		must = append(must, textQuery)
+	}
+	must = append(must, filters...)
+	if len(must) == 0 {
+		must = append(must, map[string]interface{}{"match_all": map[string]interface{}{}})
+	}
+	return map[string]interface{}{
+		"bool": map[string]interface{}{
+			"must":   must,
+			"should": []map[string]interface{}{activeStatusBoost()},
+		},
+	}
+}
+
+// companyTextQuery текстовый поиск компаний: точное совпадение ОГРН и ИНН,
+// морфологический и нечеткий поиск по наименованиям (с транслитерацией),
+// поиск по ФИО руководителя и учредителям. nil для пустой строки.
+func companyTextQuery(query string) map[string]interface{} {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil
+	}
+
+	should := []map[string]interface{}{
+		exactIDMatch("ogrn", query),
+		exactIDMatch("inn", query),
+	}
+	for _, q := range searchVariants(normalizeSearchQuery(query)) {
+		should = append(should,
+			// Морфологический поиск по наименованиям
+			map[string]interface{}{
+				"multi_match": map[string]interface{}{
+					"query":    q,
+					"fields":   []string{"full_name^2", "short_name", "brand_name"},
+					"operator": "and",
+					"boost":    5,
+				},
+			},
+			// Опечатки: нечеткое совпадение без стемминга
+			fuzzyMatch(q, []string{"full_name.plain^2", "short_name.plain", "brand_name.plain"}, 2),
+			// Руководитель и учредители
+			map[string]interface{}{
+				"multi_match": map[string]interface{}{
+					"query":  q,
+					"fields": []string{"head_last_name^2", "head_first_name", "head_middle_name", "founder_names"},
+					"type":   "cross_fields",
+					"boost":  3,
+				},
+			},
+			fuzzyMatch(q, []string{"head_last_name.plain", "founder_names.plain"}, 1),
+		)
+	}
+
+	return map[string]interface{}{
+		"bool": map[string]interface{}{
+			"should":               should,
+			"minimum_should_match": 1,
+		},
+	}
+}
+
+// entrepreneurTextQuery текстовый поиск ИП: точное совпадение ОГРНИП и ИНН,
+// морфологический и нечеткий поиск по ФИО (с транслитерацией). nil для пустой строки.
+func entrepreneurTextQuery(query string) map[string]interface{} {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil
+	}
+
+	should := []map[string]interface{}{
+		exactIDMatch("ogrnip", query),
+		exactIDMatch("inn", query),
+	}
+	for _, q := range searchVariants(normalizeSearchQuery(query)) {
+		should = append(should,
+			// Морфологический поиск по ФИО
+			map[string]interface{}{
+				"multi_match": map[string]interface{}{
+					"query":  q,
+					"fields": []string{"last_name^3", "first_name^2", "middle_name"},
+					"type":   "cross_fields",
+					"boost":  5,
+				},
+			},
+			// Опечатки: нечеткое совпадение без стемминга
+			fuzzyMatch(q, []string{"full_name.plain", "last_name.plain^2"}, 2),
+		)
+	}
+
+	return map[string]interface{}{
+		"bool": map[string]interface{}{
+			"should":               should,
+			"minimum_should_match": 1,
+		},
+	}
+}
@@ -0,0 +1,22 @@
+package elasticsearch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeSearchQuery_StripsLegalFormAndQuotes(t *testing.T) {
+	assert.Equal(t, "Ромашка", normalizeSearchQuery(`ООО "Ромашка"`))
+	assert.Equal(t, "Газпром", normalizeSearchQuery("ПАО «Газпром»"))
+	assert.Equal(t, "Ромашка", normalizeSearchQuery("Общество с ограниченной ответственностью «Ромашка»"))
+	// Одна только форма не превращается в пустой запрос
+	assert.Equal(t, "ООО", normalizeSearchQuery(`"ООО"`))
+}
+
+func TestSearchVariants_Transliteration(t *testing.T) {
+	assert.Equal(t, []string{"gazprom", "газпром"}, searchVariants("gazprom"))
+	assert.Equal(t, []string{"Щукин", "shchukin"}, searchVariants("Щукин"))
+	assert.Equal(t, []string{"7707083893"}, searchVariants("7707083893"))
+	assert.Equal(t, []string{"Yandex ООО"}, searchVariants("Yandex ООО"))
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/egrul-system/services/api-gateway/internal/graph/model"
 )
@@ -10,6 +11,7 @@ import (
 type CompanyRepository interface {
 	GetByOGRN(ctx context.Context, ogrn string) (*model.Company, error)
 	GetByINN(ctx context.Context, inn string) (*model.Company, error)
+	GetByOGRNAsOf(ctx context.Context, ogrn string, asOf time.Time) (*model.Company, error)
 	List(ctx context.Context, filter *model.CompanyFilter, pagination *model.Pagination, sort *model.CompanySort) ([]*model.Company, int, error)
 	Search(ctx context.Context, query string, limit, offset int) ([]*model.Company, error)
 }
@@ -25,6 +27,7 @@ type EntrepreneurRepository interface {
 // FounderRepository интерфейс для работы с учредителями
 type FounderRepository interface {
 	GetByCompanyOGRN(ctx context.Context, ogrn string, limit, offset int) ([]*model.Founder, error)
+	GetByCompanyOGRNAsOf(ctx context.Context, ogrn string, versionDate time.Time, limit, offset int) ([]*model.Founder, error)
 	GetRelatedCompanies(ctx context.Context, inn string, limit, offset int) ([]string, error)
 	GetCompaniesWithCommonFounders(ctx context.Context, ogrn string, limit, offset int) ([]string, error)
 	GetFounderCompanies(ctx context.Context, ogrn string, limit, offset int) ([]string, error)
@@ -41,11 +44,13 @@ type FounderRepository interface {
 // LicenseRepository интерфейс для работы с лицензиями
 type LicenseRepository interface {
 	GetByEntityOGRN(ctx context.Context, ogrn string) ([]*model.License, error)
+	GetByEntityOGRNAsOf(ctx context.Context, ogrn string, versionDate time.Time) ([]*model.License, error)
 }
 
 // BranchRepository интерфейс для работы с филиалами
 type BranchRepository interface {
 	GetByCompanyOGRN(ctx context.Context, ogrn string) ([]*model.Branch, error)
+	GetByCompanyOGRNAsOf(ctx context.Context, ogrn string, versionDate time.Time) ([]*model.Branch, error)
 }
 
 // StatisticsRepository интерфейс для работы со статистикой
@@ -60,3 +65,23 @@ type HistoryRepository interface {
 	CountByEntityID(ctx context.Context, entityType, entityID string) (int, error)
 	InsertOrUpdate(ctx context.Context, record *model.HistoryRecord, entityType, entityID string, extractDate string, sourceFile string, fileHash string) error
 }
+
+// RiskRepository интерфейс для работы с признаками оценки риска
+// и агрегатами массовой регистрации
+type RiskRepository interface {
+	GetFeatures(ctx context.Context, ogrns []string) (map[string]*model.CompanyRiskFeatures, error)
+	GetMassAddressByCompany(ctx context.Context, ogrn string) (*model.MassAddress, error)
+	ListMassAddresses(ctx context.Context, regionCode *string, minCount, limit, offset int) ([]*model.MassAddress, error)
+	GetMassDirector(ctx context.Context, inn string) (*model.MassDirector, error)
+	ListMassDirectors(ctx context.Context, minCount, limit, offset int) ([]*model.MassDirector, error)
+}
+
+// PersonRepository интерфейс для работы с ролями физических лиц
+type PersonRepository interface {
+	GetDirectorships(ctx context.Context, inn string, limit int) ([]*model.PersonDirectorship, string, error)
+	GetFoundings(ctx context.Context, inn string, limit int) ([]*model.PersonFounding, string, error)
+	GetDirectorVersions(ctx context.Context, inn string) ([]*model.PersonRolePoint, error)
+	GetFounderVersions(ctx context.Context, inn string) ([]*model.PersonRolePoint, error)
+	GetOwnershipRoles(ctx context.Context, inn string) ([]*model.PersonRole, error)
+	SearchCandidates(ctx context.Context, name string, limit int) ([]*model.PersonCandidate, error)
+}
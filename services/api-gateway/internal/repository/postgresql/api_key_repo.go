@@ -0,0 +1,256 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+)
+
+// APIKey API ключ пользователя для машинных клиентов
+type APIKey struct {
+	ID           string
+	UserID       string
+	Name         string
+	KeyPrefix    string
+	Scopes       []string
+	IPAllowlist  []string
+	DailyQuota   *int
+	ExpiresAt    *time.Time
+	CreatedAt    time.Time
+	LastUsedAt   *time.Time
+	RequestCount int64
+	RevokedAt    *time.Time
+}
+
+// IsActive ключ не отозван и не истек
+func (k *APIKey) IsActive(now time.Time) bool {
+	return k.RevokedAt == nil && (k.ExpiresAt == nil || now.Before(*k.ExpiresAt))
+}
+
+// APIKeyOwner владелец ключа - данные, нужные для авторизации запроса
+type APIKeyOwner struct {
+	UserID   string
+	Email    string
+	Role     string
+	IsActive bool
+}
+
+// APIKeyRepository реализация для работы с API ключами
+type APIKeyRepository struct {
+	db     *sql.DB
+	schema string
+	logger *zap.Logger
+}
+
+// NewAPIKeyRepository создает новый экземпляр APIKeyRepository
+func NewAPIKeyRepository(db *sql.DB, schema string, logger *zap.Logger) *APIKeyRepository {
+	return &APIKeyRepository{
+		db:     db,
+		schema: schema,
+		logger: logger,
+	}
+}
+
+// Create сохраняет новый ключ; сам ключ не хранится, только его хеш
+func (r *APIKeyRepository) Create(ctx context.Context, key *APIKey, keyHash string) error {
+	if key.ID == "" {
+		key.ID = uuid.New().String()
+	}
+	key.CreatedAt = time.Now()
+	if key.IPAllowlist == nil {
+		key.IPAllowlist = []string{}
+	}
+
+	query := fmt.Sprintf(`
+		INSERT INTO %s.api_keys (
+			id, user_id, name, key_prefix, key_hash,
+			scopes, ip_allowlist, daily_quota, expires_at, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`, r.schema)
+
+	_, err := r.db.ExecContext(ctx, query,
+		key.ID,
+		key.UserID,
+		key.Name,
+		key.KeyPrefix,
+		keyHash,
+		pq.Array(key.Scopes),
+		pq.Array(key.IPAllowlist),
+		key.DailyQuota,
+		key.ExpiresAt,
+		key.CreatedAt,
+	)
+	if err != nil {
+		r.logger.Error("failed to create api key", zap.Error(err), zap.String("user_id", key.UserID))
+		return fmt.Errorf("failed to create api key: %w", err)
+	}
+
+	r.logger.Info("api key created", zap.String("id", key.ID), zap.String("user_id", key.UserID))
+	return nil
+}
+
+// GetByHash получает ключ и его владельца по хешу ключа
+func (r *APIKeyRepository) GetByHash(ctx context.Context, keyHash string) (*APIKey, *APIKeyOwner, error) {
+	query := fmt.Sprintf(`
+		SELECT
+			k.id, k.user_id, k.name, k.key_prefix, k.scopes, k.ip_allowlist,
+			k.daily_quota, k.expires_at, k.created_at, k.last_used_at,
+			k.request_count, k.revoked_at,
+			u.email, u.role, u.is_active
+		FROM %s.api_keys k
+		JOIN %s.users u ON u.id = k.user_id
+		WHERE k.key_hash = $1
+	`, r.schema, r.schema)
+
+	var owner APIKeyOwner
+	key, err := scanAPIKey(r.db.QueryRowContext(ctx, query, keyHash), &owner.Email, &owner.Role, &owner.IsActive)
+	if err == sql.ErrNoRows {
+		return nil, nil, nil
+	}
+	if err != nil {
+		r.logger.Error("failed to get api key", zap.Error(err))
+		return nil, nil, fmt.Errorf("failed to get api key: %w", err)
+	}
+
+	owner.UserID = key.UserID
+	return key, &owner, nil
+}
+
+// GetActiveByUserID действующие (не отозванные) ключи пользователя, новые первыми
+func (r *APIKeyRepository) GetActiveByUserID(ctx context.Context, userID string) ([]*APIKey, error) {
+	query := fmt.Sprintf(`
+		SELECT
+			id, user_id, name, key_prefix, scopes, ip_allowlist,
+			daily_quota, expires_at, created_at, last_used_at,
+			request_count, revoked_at
+		FROM %s.api_keys
+		WHERE user_id = $1 AND revoked_at IS NULL
+		ORDER BY created_at DESC
+	`, r.schema)
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		r.logger.Error("failed to list api keys", zap.Error(err), zap.String("user_id", userID))
+		return nil, fmt.Errorf("failed to list api keys: %w", err)
+	}
+	defer rows.Close()
+
+	var keys []*APIKey
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan api key: %w", err)
+		}
+		keys = append(keys, key)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate api keys: %w", err)
+	}
+
+	return keys, nil
+}
+
+// CountActiveByUserID число действующих ключей пользователя
+func (r *APIKeyRepository) CountActiveByUserID(ctx context.Context, userID string) (int, error) {
+	query := fmt.Sprintf(`
+		SELECT COUNT(*)
+		FROM %s.api_keys
+		WHERE user_id = $1 AND revoked_at IS NULL
+	`, r.schema)
+
+	var count int
+	if err := r.db.QueryRowContext(ctx, query, userID).Scan(&count); err != nil {
+		r.logger.Error("failed to count api keys", zap.Error(err), zap.String("user_id", userID))
+		return 0, fmt.Errorf("failed to count api keys: %w", err)
+	}
+	return count, nil
+}
+
+// Revoke отзывает ключ пользователя; возвращает false, если ключ не найден
+// или уже отозван
+func (r *APIKeyRepository) Revoke(ctx context.Context, id, userID string) (bool, error) {
+	query := fmt.Sprintf(`
+		UPDATE %s.api_keys
+		SET revoked_at = $1
+		WHERE id = $2 AND user_id = $3 AND revoked_at IS NULL
+	`, r.schema)
+
+	result, err := r.db.ExecContext(ctx, query, time.Now(), id, userID)
+	if err != nil {
+		r.logger.Error("failed to revoke api key", zap.Error(err), zap.String("id", id))
+		return false, fmt.Errorf("failed to revoke api key: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected > 0 {
+		r.logger.Info("api key revoked", zap.String("id", id), zap.String("user_id", userID))
+	}
+	return rowsAffected > 0, nil
+}
+
+// AddUsage добавляет к счетчику запросов ключа count и обновляет время
+// последнего использования
+func (r *APIKeyRepository) AddUsage(ctx context.Context, id string, count int64, lastUsedAt time.Time) error {
+	query := fmt.Sprintf(`
+		UPDATE %s.api_keys
+		SET
+			request_count = request_count + $1,
+			last_used_at = GREATEST(COALESCE(last_used_at, $2), $2)
+		WHERE id = $3
+	`, r.schema)
+
+	if _, err := r.db.ExecContext(ctx, query, count, lastUsedAt, id); err != nil {
+		return fmt.Errorf("failed to update api key usage: %w", err)
+	}
+	return nil
+}
+
+// scanAPIKey сканирует колонки ключа; extra - дополнительные колонки после них
+func scanAPIKey(row rowScanner, extra ...interface{}) (*APIKey, error) {
+	var key APIKey
+	var dailyQuota sql.NullInt64
+	var expiresAt, lastUsedAt, revokedAt sql.NullTime
+
+	dest := []interface{}{
+		&key.ID,
+		&key.UserID,
+		&key.Name,
+		&key.KeyPrefix,
+		pq.Array(&key.Scopes),
+		pq.Array(&key.IPAllowlist),
+		&dailyQuota,
+		&expiresAt,
+		&key.CreatedAt,
+		&lastUsedAt,
+		&key.RequestCount,
+		&revokedAt,
+	}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+
+	if dailyQuota.Valid {
+		quota := int(dailyQuota.Int64)
+		key.DailyQuota = &quota
+	}
+	if expiresAt.Valid {
+		key.ExpiresAt = &expiresAt.Time
+	}
+	if lastUsedAt.Valid {
+		key.LastUsedAt = &lastUsedAt.Time
+	}
+	if revokedAt.Valid {
+		key.RevokedAt = &revokedAt.Time
+	}
+
+	return &key, nil
+}
@@ -0,0 +1,343 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/egrul-system/services/api-gateway/internal/graph/model"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+)
+
+// BulkCheckRepository хранит задания пакетной проверки контрагентов
+type BulkCheckRepository struct {
+	db     *sql.DB
+	schema string
+	logger *zap.Logger
+}
+
+// NewBulkCheckRepository создает новый экземпляр BulkCheckRepository
+func NewBulkCheckRepository(db *sql.DB, schema string, logger *zap.Logger) *BulkCheckRepository {
+	return &BulkCheckRepository{
+		db:     db,
+		schema: schema,
+		logger: logger,
+	}
+}
+
+const bulkCheckColumns = `
+	id, user_id, status, file_name, output_format,
+	identifiers, total_rows, processed_rows, found_rows,
+	error_message, created_at, started_at, finished_at
+`
+
+// Create создает новое задание
+func (r *BulkCheckRepository) Create(ctx context.Context, job *model.BulkCheckJob) error {
+	if job.ID == "" {
+		job.ID = uuid.New().String()
+	}
+	job.Status = model.BulkCheckStatusPending
+	job.TotalRows = len(job.Identifiers)
+	job.CreatedAt = time.Now()
+
+	query := fmt.Sprintf(`
+		INSERT INTO %s.bulk_check_jobs (
+			id, user_id, status, file_name, output_format,
+			identifiers, total_rows, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`, r.schema)
+
+	_, err := r.db.ExecContext(ctx, query,
+		job.ID,
+		job.UserID,
+		string(job.Status),
+		job.FileName,
+		job.OutputFormat,
+		pq.Array(job.Identifiers),
+		job.TotalRows,
+		job.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create bulk check job: %w", err)
+	}
+
+	r.logger.Info("bulk check job created",
+		zap.String("id", job.ID),
+		zap.String("user_id", job.UserID),
+		zap.Int("total_rows", job.TotalRows),
+	)
+
+	return nil
+}
+
+// GetByID получает задание по ID (без результатов)
+func (r *BulkCheckRepository) GetByID(ctx context.Context, id string) (*model.BulkCheckJob, error) {
+	query := fmt.Sprintf(`SELECT %s FROM %s.bulk_check_jobs WHERE id = $1`, bulkCheckColumns, r.schema)
+
+	job, err := scanBulkCheckJob(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bulk check job: %w", err)
+	}
+
+	return job, nil
+}
+
+// GetByUserID получает последние задания пользователя
+func (r *BulkCheckRepository) GetByUserID(ctx context.Context, userID string, limit int) ([]*model.BulkCheckJob, error) {
+	query := fmt.Sprintf(`
+		SELECT %s FROM %s.bulk_check_jobs
+		WHERE user_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2
+	`, bulkCheckColumns, r.schema)
+
+	rows, err := r.db.QueryContext(ctx, query, userID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query bulk check jobs: %w", err)
+	}
+	defer rows.Close()
+
+	var jobs []*model.BulkCheckJob
+	for rows.Next() {
+		job, err := scanBulkCheckJob(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan bulk check job: %w", err)
+		}
+		jobs = append(jobs, job)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating bulk check jobs: %w", err)
+	}
+
+	return jobs, nil
+}
+
+// GetUnfinishedIDs возвращает ID ожидающих заданий (например, прерванных
+// перезапуском сервиса) в порядке создания. Задания в статусе running
+// выполняются другим воркером и не возвращаются; брошенные задания
+// предварительно возвращает в pending ReclaimStale.
+func (r *BulkCheckRepository) GetUnfinishedIDs(ctx context.Context) ([]string, error) {
+	query := fmt.Sprintf(`
+		SELECT id FROM %s.bulk_check_jobs
+		WHERE status = 'pending'
+		ORDER BY created_at
+	`, r.schema)
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query unfinished bulk check jobs: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan bulk check job id: %w", err)
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+// GetResults получает результаты завершенного задания
+func (r *BulkCheckRepository) GetResults(ctx context.Context, id string) ([]*model.BulkCheckResult, error) {
+	query := fmt.Sprintf(`SELECT results FROM %s.bulk_check_jobs WHERE id = $1`, r.schema)
+
+	var raw []byte
+	if err := r.db.QueryRowContext(ctx, query, id).Scan(&raw); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get bulk check results: %w", err)
+	}
+	if len(raw) == 0 {
+		return nil, nil
+	}
+
+	var results []*model.BulkCheckResult
+	if err := json.Unmarshal(raw, &results); err != nil {
+		return nil, fmt.Errorf("failed to decode bulk check results: %w", err)
+	}
+
+	return results, nil
+}
+
+// MarkRunning захватывает задание: переводит его из pending в running.
+// Возвращает false, если задание уже захвачено другим воркером (в том числе
+// другого экземпляра gateway) или завершено.
+func (r *BulkCheckRepository) MarkRunning(ctx context.Context, id string) (bool, error) {
+	query := fmt.Sprintf(`
+		UPDATE %s.bulk_check_jobs
+		SET status = 'running', started_at = NOW(), heartbeat_at = NOW(),
+			processed_rows = 0, found_rows = 0
+		WHERE id = $1 AND status = 'pending'
+	`, r.schema)
+
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to mark bulk check job running: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return rowsAffected > 0, nil
+}
+
+// Release возвращает прерванное задание в статус pending, чтобы его
+// возобновили при следующем запуске
+func (r *BulkCheckRepository) Release(ctx context.Context, id string) error {
+	query := fmt.Sprintf(`
+		UPDATE %s.bulk_check_jobs
+		SET status = 'pending', started_at = NULL, heartbeat_at = NULL, processed_rows = 0, found_rows = 0
+		WHERE id = $1 AND status = 'running'
+	`, r.schema)
+
+	if _, err := r.db.ExecContext(ctx, query, id); err != nil {
+		return fmt.Errorf("failed to release bulk check job: %w", err)
+	}
+	return nil
+}
+
+// ReclaimStale возвращает в pending задания в статусе running, прогресс
+// которых не отмечался дольше timeout: их воркер остановлен без Release
+// (падение или SIGKILL экземпляра). Возвращает количество таких заданий.
+func (r *BulkCheckRepository) ReclaimStale(ctx context.Context, timeout time.Duration) (int, error) {
+	query := fmt.Sprintf(`
+		UPDATE %s.bulk_check_jobs
+		SET status = 'pending', started_at = NULL, heartbeat_at = NULL, processed_rows = 0, found_rows = 0
+		WHERE status = 'running'
+			AND COALESCE(heartbeat_at, started_at, created_at) < NOW() - make_interval(secs => $1)
+	`, r.schema)
+
+	result, err := r.db.ExecContext(ctx, query, timeout.Seconds())
+	if err != nil {
+		return 0, fmt.Errorf("failed to reclaim stale bulk check jobs: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return int(rowsAffected), nil
+}
+
+// UpdateProgress обновляет прогресс выполнения задания и отметку heartbeat_at,
+// по которой ReclaimStale отличает выполняемые задания от брошенных
+func (r *BulkCheckRepository) UpdateProgress(ctx context.Context, id string, processed, found int) error {
+	query := fmt.Sprintf(`
+		UPDATE %s.bulk_check_jobs
+		SET processed_rows = $1, found_rows = $2, heartbeat_at = NOW()
+		WHERE id = $3
+	`, r.schema)
+
+	if _, err := r.db.ExecContext(ctx, query, processed, found, id); err != nil {
+		return fmt.Errorf("failed to update bulk check progress: %w", err)
+	}
+	return nil
+}
+
+// Complete сохраняет результаты и завершает задание
+func (r *BulkCheckRepository) Complete(ctx context.Context, id string, results []*model.BulkCheckResult) error {
+	raw, err := json.Marshal(results)
+	if err != nil {
+		return fmt.Errorf("failed to encode bulk check results: %w", err)
+	}
+
+	found := 0
+	for _, res := range results {
+		if res.Found {
+			found++
+		}
+	}
+
+	query := fmt.Sprintf(`
+		UPDATE %s.bulk_check_jobs
+		SET status = 'completed', results = $1, processed_rows = $2, found_rows = $3,
+			error_message = NULL, finished_at = NOW()
+		WHERE id = $4
+	`, r.schema)
+
+	if _, err := r.db.ExecContext(ctx, query, raw, len(results), found, id); err != nil {
+		return fmt.Errorf("failed to complete bulk check job: %w", err)
+	}
+
+	r.logger.Info("bulk check job completed",
+		zap.String("id", id),
+		zap.Int("rows", len(results)),
+		zap.Int("found", found),
+	)
+
+	return nil
+}
+
+// Fail помечает задание как завершенное с ошибкой
+func (r *BulkCheckRepository) Fail(ctx context.Context, id string, message string) error {
+	query := fmt.Sprintf(`
+		UPDATE %s.bulk_check_jobs
+		SET status = 'failed', error_message = $1, finished_at = NOW()
+		WHERE id = $2
+	`, r.schema)
+
+	if _, err := r.db.ExecContext(ctx, query, message, id); err != nil {
+		return fmt.Errorf("failed to mark bulk check job failed: %w", err)
+	}
+	return nil
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanBulkCheckJob(row rowScanner) (*model.BulkCheckJob, error) {
+	var job model.BulkCheckJob
+	var status string
+	var fileName, errorMessage sql.NullString
+	var startedAt, finishedAt sql.NullTime
+
+	err := row.Scan(
+		&job.ID,
+		&job.UserID,
+		&status,
+		&fileName,
+		&job.OutputFormat,
+		pq.Array(&job.Identifiers),
+		&job.TotalRows,
+		&job.ProcessedRows,
+		&job.FoundRows,
+		&errorMessage,
+		&job.CreatedAt,
+		&startedAt,
+		&finishedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	job.Status = model.BulkCheckStatus(status)
+	if fileName.Valid {
+		job.FileName = &fileName.String
+	}
+	if errorMessage.Valid {
+		job.ErrorMessage = &errorMessage.String
+	}
+	if startedAt.Valid {
+		job.StartedAt = &startedAt.Time
+	}
+	if finishedAt.Valid {
+		job.FinishedAt = &finishedAt.Time
+	}
+
+	return &job, nil
+}
@@ -9,7 +9,7 @@ import (
 
 	"github.com/egrul-system/services/api-gateway/internal/graph/model"
 	"github.com/google/uuid"
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 	"go.uber.org/zap"
 )
 
@@ -33,26 +33,13 @@ func NewFavoriteRepository(db *sql.DB, schema string, logger *zap.Logger) *Favor
 func (r *FavoriteRepository) GetByID(ctx context.Context, id string) (*model.Favorite, error) {
 	query := fmt.Sprintf(`
 		SELECT
-			id, user_id, entity_type, entity_id, entity_name,
-			notes, created_at
+			id, user_id, workspace_id, entity_type, entity_id, entity_name,
+			notes, folder, tags, created_at
 		FROM %s.favorites
 		WHERE id = $1
 	`, r.schema)
 
-	var fav model.Favorite
-	var entityType string
-	var notes sql.NullString
-
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&fav.ID,
-		&fav.UserID,
-		&entityType,
-		&fav.EntityID,
-		&fav.EntityName,
-		&notes,
-		&fav.CreatedAt,
-	)
-
+	fav, err := scanFavorite(r.db.QueryRowContext(ctx, query, id))
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -60,28 +47,125 @@ func (r *FavoriteRepository) GetByID(ctx context.Context, id string) (*model.Fav
 		return nil, fmt.Errorf("failed to get favorite: %w", err)
 	}
 
-	// Конвертируем entityType обратно в uppercase для соответствия GraphQL enum
-	fav.EntityType = model.EntityType(strings.ToUpper(entityType))
+	return fav, nil
+}
 
-	if notes.Valid {
-		fav.Notes = &notes.String
+// FavoriteFilter фильтр личного избранного. Пустая строка в Folder выбирает
+// записи без папки, nil - записи из всех папок.
+type FavoriteFilter struct {
+	Folder *string
+	Tag    *string
+}
+
+// GetByUserID получает личное избранное пользователя (без записей пространств)
+func (r *FavoriteRepository) GetByUserID(ctx context.Context, userID string, filter FavoriteFilter) ([]*model.Favorite, error) {
+	conditions := []string{"user_id = $1", "workspace_id IS NULL"}
+	args := []interface{}{userID}
+
+	if filter.Folder != nil {
+		if *filter.Folder == "" {
+			conditions = append(conditions, "folder IS NULL")
+		} else {
+			args = append(args, *filter.Folder)
+			conditions = append(conditions, fmt.Sprintf("folder = $%d", len(args)))
+		}
+	}
+	if filter.Tag != nil {
+		args = append(args, *filter.Tag)
+		conditions = append(conditions, fmt.Sprintf("$%d = ANY(tags)", len(args)))
 	}
 
-	return &fav, nil
+	query := fmt.Sprintf(`
+		SELECT
+			id, user_id, workspace_id, entity_type, entity_id, entity_name,
+			notes, folder, tags, created_at
+		FROM %s.favorites
+		WHERE %s
+		ORDER BY created_at DESC
+	`, r.schema, strings.Join(conditions, " AND "))
+
+	return r.queryFavorites(ctx, query, args...)
+}
+
+// GetFolders возвращает папки личного избранного с числом записей
+func (r *FavoriteRepository) GetFolders(ctx context.Context, userID string) ([]*model.FavoriteFolder, error) {
+	query := fmt.Sprintf(`
+		SELECT folder, COUNT(*)
+		FROM %s.favorites
+		WHERE user_id = $1 AND workspace_id IS NULL AND folder IS NOT NULL
+		GROUP BY folder
+		ORDER BY folder
+	`, r.schema)
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query favorite folders: %w", err)
+	}
+	defer rows.Close()
+
+	folders := []*model.FavoriteFolder{}
+	for rows.Next() {
+		var folder model.FavoriteFolder
+		if err := rows.Scan(&folder.Name, &folder.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan favorite folder: %w", err)
+		}
+		folders = append(folders, &folder)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating favorite folders: %w", err)
+	}
+
+	return folders, nil
 }
 
-// GetByUserID получает все избранное пользователя
-func (r *FavoriteRepository) GetByUserID(ctx context.Context, userID string) ([]*model.Favorite, error) {
+// GetTags возвращает теги, использованные в личном избранном
+func (r *FavoriteRepository) GetTags(ctx context.Context, userID string) ([]string, error) {
+	query := fmt.Sprintf(`
+		SELECT DISTINCT tag
+		FROM %s.favorites, unnest(tags) AS tag
+		WHERE user_id = $1 AND workspace_id IS NULL
+		ORDER BY tag
+	`, r.schema)
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query favorite tags: %w", err)
+	}
+	defer rows.Close()
+
+	tags := []string{}
+	for rows.Next() {
+		var tag string
+		if err := rows.Scan(&tag); err != nil {
+			return nil, fmt.Errorf("failed to scan favorite tag: %w", err)
+		}
+		tags = append(tags, tag)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating favorite tags: %w", err)
+	}
+
+	return tags, nil
+}
+
+// GetByWorkspaceID получает избранное рабочего пространства
+func (r *FavoriteRepository) GetByWorkspaceID(ctx context.Context, workspaceID string) ([]*model.Favorite, error) {
 	query := fmt.Sprintf(`
 		SELECT
-			id, user_id, entity_type, entity_id, entity_name,
-			notes, created_at
+			id, user_id, workspace_id, entity_type, entity_id, entity_name,
+			notes, folder, tags, created_at
 		FROM %s.favorites
-		WHERE user_id = $1
+		WHERE workspace_id = $1
 		ORDER BY created_at DESC
 	`, r.schema)
 
-	rows, err := r.db.QueryContext(ctx, query, userID)
+	return r.queryFavorites(ctx, query, workspaceID)
+}
+
+func (r *FavoriteRepository) queryFavorites(ctx context.Context, query string, args ...interface{}) ([]*model.Favorite, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query favorites: %w", err)
 	}
@@ -90,31 +174,12 @@ func (r *FavoriteRepository) GetByUserID(ctx context.Context, userID string) ([]
 	var favorites []*model.Favorite
 
 	for rows.Next() {
-		var fav model.Favorite
-		var entityType string
-		var notes sql.NullString
-
-		err := rows.Scan(
-			&fav.ID,
-			&fav.UserID,
-			&entityType,
-			&fav.EntityID,
-			&fav.EntityName,
-			&notes,
-			&fav.CreatedAt,
-		)
+		fav, err := scanFavorite(rows)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan favorite: %w", err)
 		}
 
-		// Конвертируем entityType обратно в uppercase для соответствия GraphQL enum
-		fav.EntityType = model.EntityType(strings.ToUpper(entityType))
-
-		if notes.Valid {
-			fav.Notes = &notes.String
-		}
-
-		favorites = append(favorites, &fav)
+		favorites = append(favorites, fav)
 	}
 
 	if err = rows.Err(); err != nil {
@@ -124,12 +189,12 @@ func (r *FavoriteRepository) GetByUserID(ctx context.Context, userID string) ([]
 	return favorites, nil
 }
 
-// HasFavorite проверяет наличие в избранном
+// HasFavorite проверяет наличие в личном избранном
 func (r *FavoriteRepository) HasFavorite(ctx context.Context, userID, entityType, entityID string) (bool, error) {
 	query := fmt.Sprintf(`
 		SELECT EXISTS(
 			SELECT 1 FROM %s.favorites
-			WHERE user_id = $1 AND entity_type = $2 AND entity_id = $3
+			WHERE user_id = $1 AND entity_type = $2 AND entity_id = $3 AND workspace_id IS NULL
 		)
 	`, r.schema)
 
@@ -145,6 +210,24 @@ func (r *FavoriteRepository) HasFavorite(ctx context.Context, userID, entityType
 	return exists, nil
 }
 
+// HasWorkspaceFavorite проверяет наличие в избранном рабочего пространства
+func (r *FavoriteRepository) HasWorkspaceFavorite(ctx context.Context, workspaceID, entityType, entityID string) (bool, error) {
+	query := fmt.Sprintf(`
+		SELECT EXISTS(
+			SELECT 1 FROM %s.favorites
+			WHERE workspace_id = $1 AND entity_type = $2 AND entity_id = $3
+		)
+	`, r.schema)
+
+	var exists bool
+	err := r.db.QueryRowContext(ctx, query, workspaceID, strings.ToLower(entityType), entityID).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("failed to check workspace favorite existence: %w", err)
+	}
+
+	return exists, nil
+}
+
 // Create создает новое избранное
 func (r *FavoriteRepository) Create(ctx context.Context, favorite *model.Favorite) error {
 	// Генерируем ID если не задан
@@ -156,9 +239,9 @@ func (r *FavoriteRepository) Create(ctx context.Context, favorite *model.Favorit
 
 	query := fmt.Sprintf(`
 		INSERT INTO %s.favorites (
-			id, user_id, entity_type, entity_id, entity_name,
-			notes, created_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7)
+			id, user_id, workspace_id, entity_type, entity_id, entity_name,
+			notes, folder, tags, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
 	`, r.schema)
 
 	// Конвертируем EntityType в lowercase для соответствия check constraint
@@ -167,10 +250,13 @@ func (r *FavoriteRepository) Create(ctx context.Context, favorite *model.Favorit
 	_, err := r.db.ExecContext(ctx, query,
 		favorite.ID,
 		favorite.UserID,
+		favorite.WorkspaceID,
 		entityType,
 		favorite.EntityID,
 		favorite.EntityName,
 		favorite.Notes,
+		favorite.Folder,
+		pq.Array(nonNilTags(favorite.Tags)),
 		favorite.CreatedAt,
 	)
 
@@ -188,16 +274,18 @@ func (r *FavoriteRepository) Create(ctx context.Context, favorite *model.Favorit
 	return nil
 }
 
-// Update обновляет избранное (только notes)
+// Update обновляет заметки, папку и теги избранного
 func (r *FavoriteRepository) Update(ctx context.Context, favorite *model.Favorite) error {
 	query := fmt.Sprintf(`
 		UPDATE %s.favorites
-		SET notes = $1
-		WHERE id = $2
+		SET notes = $1, folder = $2, tags = $3
+		WHERE id = $4
 	`, r.schema)
 
 	result, err := r.db.ExecContext(ctx, query,
 		favorite.Notes,
+		favorite.Folder,
+		pq.Array(nonNilTags(favorite.Tags)),
 		favorite.ID,
 	)
 
@@ -248,3 +336,100 @@ func (r *FavoriteRepository) Delete(ctx context.Context, id string) error {
 
 	return nil
 }
+
+// MoveToFolder переносит записи личного избранного пользователя в папку
+// (nil - убрать из папки). Чужие записи и записи пространств пропускаются.
+func (r *FavoriteRepository) MoveToFolder(ctx context.Context, userID string, ids []string, folder *string) (int, error) {
+	query := fmt.Sprintf(`
+		UPDATE %s.favorites
+		SET folder = $2
+		WHERE user_id = $1 AND workspace_id IS NULL AND id = ANY($3::uuid[])
+	`, r.schema)
+
+	result, err := r.db.ExecContext(ctx, query, userID, folder, pq.Array(ids))
+	if err != nil {
+		return 0, fmt.Errorf("failed to move favorites: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return int(rowsAffected), nil
+}
+
+// DeleteMany удаляет записи личного избранного пользователя.
+// Чужие записи и записи пространств пропускаются.
+func (r *FavoriteRepository) DeleteMany(ctx context.Context, userID string, ids []string) (int, error) {
+	query := fmt.Sprintf(`
+		DELETE FROM %s.favorites
+		WHERE user_id = $1 AND workspace_id IS NULL AND id = ANY($2::uuid[])
+	`, r.schema)
+
+	result, err := r.db.ExecContext(ctx, query, userID, pq.Array(ids))
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete favorites: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	r.logger.Info("favorites deleted",
+		zap.String("user_id", userID),
+		zap.Int64("count", rowsAffected),
+	)
+
+	return int(rowsAffected), nil
+}
+
+// nonNilTags колонка tags NOT NULL: nil записывается как пустой массив
+func nonNilTags(tags []string) []string {
+	if tags == nil {
+		return []string{}
+	}
+	return tags
+}
+
+func scanFavorite(row rowScanner) (*model.Favorite, error) {
+	var fav model.Favorite
+	var workspaceID sql.NullString
+	var entityType string
+	var notes, folder sql.NullString
+
+	err := row.Scan(
+		&fav.ID,
+		&fav.UserID,
+		&workspaceID,
+		&entityType,
+		&fav.EntityID,
+		&fav.EntityName,
+		&notes,
+		&folder,
+		pq.Array(&fav.Tags),
+		&fav.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// Конвертируем entityType обратно в uppercase для соответствия GraphQL enum
+	fav.EntityType = model.EntityType(strings.ToUpper(entityType))
+
+	if workspaceID.Valid {
+		fav.WorkspaceID = &workspaceID.String
+	}
+	if notes.Valid {
+		fav.Notes = &notes.String
+	}
+	if folder.Valid {
+		fav.Folder = &folder.String
+	}
+	if fav.Tags == nil {
+		fav.Tags = []string{}
+	}
+
+	return &fav, nil
+}
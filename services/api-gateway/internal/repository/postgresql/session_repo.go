@@ -0,0 +1,350 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// Причины отзыва сессии
+const (
+	SessionRevokedLogout          = "logout"
+	SessionRevokedByUser          = "revoked"
+	SessionRevokedTokenReuse      = "refresh_token_reuse"
+	SessionRevokedPasswordChanged = "password_changed"
+	SessionRevokedRoleChanged     = "role_changed"
+	SessionRevokedUserDeactivated = "user_deactivated"
+	SessionRevokedAccountDeleted  = "account_deleted"
+)
+
+// Session сессия пользователя (устройство, с которого выполнен вход)
+type Session struct {
+	ID            string
+	UserID        string
+	UserAgent     string
+	IPAddress     string
+	CreatedAt     time.Time
+	LastUsedAt    time.Time
+	ExpiresAt     time.Time
+	RevokedAt     *time.Time
+	RevokedReason *string
+}
+
+// IsActive сессия не отозвана и не истекла
+func (s *Session) IsActive(now time.Time) bool {
+	return s.RevokedAt == nil && now.Before(s.ExpiresAt)
+}
+
+// RefreshToken выданный refresh токен сессии
+type RefreshToken struct {
+	TokenHash string
+	SessionID string
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+}
+
+// SessionRepository хранит сессии пользователей и их refresh токены
+type SessionRepository struct {
+	db     *sql.DB
+	schema string
+	logger *zap.Logger
+}
+
+// NewSessionRepository создает новый экземпляр SessionRepository
+func NewSessionRepository(db *sql.DB, schema string, logger *zap.Logger) *SessionRepository {
+	return &SessionRepository{
+		db:     db,
+		schema: schema,
+		logger: logger,
+	}
+}
+
+const sessionColumns = `
+	id, user_id, user_agent, ip_address,
+	created_at, last_used_at, expires_at, revoked_at, revoked_reason
+`
+
+// Create создает сессию вместе с первым refresh токеном
+func (r *SessionRepository) Create(ctx context.Context, session *Session, tokenHash string) error {
+	if session.ID == "" {
+		session.ID = uuid.New().String()
+	}
+	now := time.Now()
+	session.CreatedAt = now
+	session.LastUsedAt = now
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, fmt.Sprintf(`
+		INSERT INTO %s.user_sessions (
+			id, user_id, user_agent, ip_address, created_at, last_used_at, expires_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`, r.schema),
+		session.ID,
+		session.UserID,
+		nullString(session.UserAgent),
+		nullString(session.IPAddress),
+		session.CreatedAt,
+		session.LastUsedAt,
+		session.ExpiresAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create session: %w", err)
+	}
+
+	if err := r.insertToken(ctx, tx, tokenHash, session.ID, session.ExpiresAt); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit session: %w", err)
+	}
+
+	r.logger.Info("session created", zap.String("id", session.ID), zap.String("user_id", session.UserID))
+	return nil
+}
+
+// GetRefreshToken получает refresh токен по хешу вместе с его сессией
+func (r *SessionRepository) GetRefreshToken(ctx context.Context, tokenHash string) (*RefreshToken, *Session, error) {
+	query := fmt.Sprintf(`
+		SELECT
+			t.token_hash, t.session_id, t.expires_at, t.used_at,
+			s.id, s.user_id, s.user_agent, s.ip_address,
+			s.created_at, s.last_used_at, s.expires_at, s.revoked_at, s.revoked_reason
+		FROM %[1]s.refresh_tokens t
+		JOIN %[1]s.user_sessions s ON s.id = t.session_id
+		WHERE t.token_hash = $1
+	`, r.schema)
+
+	var token RefreshToken
+	var usedAt sql.NullTime
+	row := r.db.QueryRowContext(ctx, query, tokenHash)
+
+	var session Session
+	var userAgent, ipAddress, revokedReason sql.NullString
+	var revokedAt sql.NullTime
+	err := row.Scan(
+		&token.TokenHash, &token.SessionID, &token.ExpiresAt, &usedAt,
+		&session.ID, &session.UserID, &userAgent, &ipAddress,
+		&session.CreatedAt, &session.LastUsedAt, &session.ExpiresAt, &revokedAt, &revokedReason,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get refresh token: %w", err)
+	}
+
+	if usedAt.Valid {
+		token.UsedAt = &usedAt.Time
+	}
+	fillSessionNullable(&session, userAgent, ipAddress, revokedAt, revokedReason)
+	return &token, &session, nil
+}
+
+// Rotate обменивает refresh токен на новый. Возвращает false, если старый токен
+// уже был использован (в том числе параллельным запросом) - это признак кражи.
+func (r *SessionRepository) Rotate(ctx context.Context, session *Session, oldHash, newHash string) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	result, err := tx.ExecContext(ctx, fmt.Sprintf(`
+		UPDATE %s.refresh_tokens SET used_at = $1
+		WHERE token_hash = $2 AND used_at IS NULL
+	`, r.schema), now, oldHash)
+	if err != nil {
+		return false, fmt.Errorf("failed to mark refresh token used: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return false, nil
+	}
+
+	if err := r.insertToken(ctx, tx, newHash, session.ID, session.ExpiresAt); err != nil {
+		return false, err
+	}
+
+	session.LastUsedAt = now
+	_, err = tx.ExecContext(ctx, fmt.Sprintf(`
+		UPDATE %s.user_sessions
+		SET last_used_at = $1, expires_at = $2, user_agent = $3, ip_address = $4
+		WHERE id = $5
+	`, r.schema),
+		session.LastUsedAt,
+		session.ExpiresAt,
+		nullString(session.UserAgent),
+		nullString(session.IPAddress),
+		session.ID,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to update session: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit token rotation: %w", err)
+	}
+	return true, nil
+}
+
+// GetByID получает сессию по ID
+func (r *SessionRepository) GetByID(ctx context.Context, id string) (*Session, error) {
+	query := fmt.Sprintf(`SELECT %s FROM %s.user_sessions WHERE id = $1`, sessionColumns, r.schema)
+
+	session, err := scanSession(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get session: %w", err)
+	}
+	return session, nil
+}
+
+// GetActiveByUserID получает действующие сессии пользователя, последние сверху
+func (r *SessionRepository) GetActiveByUserID(ctx context.Context, userID string) ([]*Session, error) {
+	query := fmt.Sprintf(`
+		SELECT %s FROM %s.user_sessions
+		WHERE user_id = $1 AND revoked_at IS NULL AND expires_at > NOW()
+		ORDER BY last_used_at DESC
+	`, sessionColumns, r.schema)
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query sessions: %w", err)
+	}
+	defer rows.Close()
+
+	var sessions []*Session
+	for rows.Next() {
+		session, err := scanSession(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan session: %w", err)
+		}
+		sessions = append(sessions, session)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating sessions: %w", err)
+	}
+
+	return sessions, nil
+}
+
+// Revoke отзывает сессию; повторный отзыв не меняет исходную причину
+func (r *SessionRepository) Revoke(ctx context.Context, id, reason string) error {
+	query := fmt.Sprintf(`
+		UPDATE %s.user_sessions
+		SET revoked_at = $1, revoked_reason = $2
+		WHERE id = $3 AND revoked_at IS NULL
+	`, r.schema)
+
+	if _, err := r.db.ExecContext(ctx, query, time.Now(), reason, id); err != nil {
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+
+	r.logger.Info("session revoked", zap.String("id", id), zap.String("reason", reason))
+	return nil
+}
+
+// RevokeAllByUserID отзывает все действующие сессии пользователя, кроме exceptID
+// (пустая строка - без исключений), и возвращает ID отозванных сессий
+func (r *SessionRepository) RevokeAllByUserID(ctx context.Context, userID, exceptID, reason string) ([]string, error) {
+	query := fmt.Sprintf(`
+		UPDATE %s.user_sessions
+		SET revoked_at = $1, revoked_reason = $2
+		WHERE user_id = $3 AND revoked_at IS NULL AND id::text <> $4
+		RETURNING id
+	`, r.schema)
+
+	rows, err := r.db.QueryContext(ctx, query, time.Now(), reason, userID, exceptID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan session id: %w", err)
+		}
+		ids = append(ids, id)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating revoked sessions: %w", err)
+	}
+
+	r.logger.Info("user sessions revoked",
+		zap.String("user_id", userID),
+		zap.Int("count", len(ids)),
+		zap.String("reason", reason),
+	)
+	return ids, nil
+}
+
+func (r *SessionRepository) insertToken(ctx context.Context, tx *sql.Tx, tokenHash, sessionID string, expiresAt time.Time) error {
+	_, err := tx.ExecContext(ctx, fmt.Sprintf(`
+		INSERT INTO %s.refresh_tokens (token_hash, session_id, created_at, expires_at)
+		VALUES ($1, $2, $3, $4)
+	`, r.schema), tokenHash, sessionID, time.Now(), expiresAt)
+	if err != nil {
+		return fmt.Errorf("failed to create refresh token: %w", err)
+	}
+	return nil
+}
+
+func scanSession(row rowScanner) (*Session, error) {
+	var session Session
+	var userAgent, ipAddress, revokedReason sql.NullString
+	var revokedAt sql.NullTime
+
+	err := row.Scan(
+		&session.ID,
+		&session.UserID,
+		&userAgent,
+		&ipAddress,
+		&session.CreatedAt,
+		&session.LastUsedAt,
+		&session.ExpiresAt,
+		&revokedAt,
+		&revokedReason,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	fillSessionNullable(&session, userAgent, ipAddress, revokedAt, revokedReason)
+	return &session, nil
+}
+
+func fillSessionNullable(session *Session, userAgent, ipAddress sql.NullString, revokedAt sql.NullTime, revokedReason sql.NullString) {
+	session.UserAgent = userAgent.String
+	session.IPAddress = ipAddress.String
+	if revokedAt.Valid {
+		session.RevokedAt = &revokedAt.Time
+	}
+	if revokedReason.Valid {
+		session.RevokedReason = &revokedReason.String
+	}
+}
+
+// nullString пустую строку записывает как NULL
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
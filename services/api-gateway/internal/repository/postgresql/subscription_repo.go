@@ -34,31 +34,14 @@ func NewSubscriptionRepository(db *sql.DB, schema string, logger *zap.Logger) *S
 func (r *SubscriptionRepository) GetByID(ctx context.Context, id string) (*model.EntitySubscription, error) {
 	query := fmt.Sprintf(`
 		SELECT
-			id, user_id, entity_type, entity_id, entity_name,
+			id, user_id, workspace_id, entity_type, entity_id, entity_name,
 			change_filters, notification_channels, is_active,
 			created_at, updated_at, last_notified_at
 		FROM %s.entity_subscriptions
 		WHERE id = $1
 	`, r.schema)
 
-	var sub model.EntitySubscription
-	var entityType string
-	var lastNotifiedAt sql.NullTime
-
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&sub.ID,
-		&sub.UserID,
-		&entityType,
-		&sub.EntityID,
-		&sub.EntityName,
-		&sub.ChangeFilters,
-		&sub.NotificationChannels,
-		&sub.IsActive,
-		&sub.CreatedAt,
-		&sub.UpdatedAt,
-		&lastNotifiedAt,
-	)
-
+	sub, err := scanSubscription(r.db.QueryRowContext(ctx, query, id))
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -66,29 +49,41 @@ func (r *SubscriptionRepository) GetByID(ctx context.Context, id string) (*model
 		return nil, fmt.Errorf("failed to get subscription: %w", err)
 	}
 
-	// Конвертируем entityType обратно в uppercase для соответствия GraphQL enum
-	sub.EntityType = model.EntityType(strings.ToUpper(entityType))
+	return sub, nil
+}
 
-	if lastNotifiedAt.Valid {
-		sub.LastNotifiedAt = &lastNotifiedAt.Time
-	}
+// GetByUserID получает личные подписки пользователя (без подписок пространств)
+func (r *SubscriptionRepository) GetByUserID(ctx context.Context, userID string) ([]*model.EntitySubscription, error) {
+	query := fmt.Sprintf(`
+		SELECT
+			id, user_id, workspace_id, entity_type, entity_id, entity_name,
+			change_filters, notification_channels, is_active,
+			created_at, updated_at, last_notified_at
+		FROM %s.entity_subscriptions
+		WHERE user_id = $1 AND workspace_id IS NULL
+		ORDER BY created_at DESC
+	`, r.schema)
 
-	return &sub, nil
+	return r.querySubscriptions(ctx, query, userID)
 }
 
-// GetByUserID получает все подписки пользователя
-func (r *SubscriptionRepository) GetByUserID(ctx context.Context, userID string) ([]*model.EntitySubscription, error) {
+// GetByWorkspaceID получает подписки рабочего пространства
+func (r *SubscriptionRepository) GetByWorkspaceID(ctx context.Context, workspaceID string) ([]*model.EntitySubscription, error) {
 	query := fmt.Sprintf(`
 		SELECT
-			id, user_id, entity_type, entity_id, entity_name,
+			id, user_id, workspace_id, entity_type, entity_id, entity_name,
 			change_filters, notification_channels, is_active,
 			created_at, updated_at, last_notified_at
 		FROM %s.entity_subscriptions
-		WHERE user_id = $1
+		WHERE workspace_id = $1
 		ORDER BY created_at DESC
 	`, r.schema)
 
-	rows, err := r.db.QueryContext(ctx, query, userID)
+	return r.querySubscriptions(ctx, query, workspaceID)
+}
+
+func (r *SubscriptionRepository) querySubscriptions(ctx context.Context, query string, args ...interface{}) ([]*model.EntitySubscription, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query subscriptions: %w", err)
 	}
@@ -97,35 +92,12 @@ func (r *SubscriptionRepository) GetByUserID(ctx context.Context, userID string)
 	var subscriptions []*model.EntitySubscription
 
 	for rows.Next() {
-		var sub model.EntitySubscription
-		var entityType string
-		var lastNotifiedAt sql.NullTime
-
-		err := rows.Scan(
-			&sub.ID,
-			&sub.UserID,
-			&entityType,
-			&sub.EntityID,
-			&sub.EntityName,
-			&sub.ChangeFilters,
-			&sub.NotificationChannels,
-			&sub.IsActive,
-			&sub.CreatedAt,
-			&sub.UpdatedAt,
-			&lastNotifiedAt,
-		)
+		sub, err := scanSubscription(rows)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan subscription: %w", err)
 		}
 
-		// Конвертируем entityType обратно в uppercase для соответствия GraphQL enum
-		sub.EntityType = model.EntityType(strings.ToUpper(entityType))
-
-		if lastNotifiedAt.Valid {
-			sub.LastNotifiedAt = &lastNotifiedAt.Time
-		}
-
-		subscriptions = append(subscriptions, &sub)
+		subscriptions = append(subscriptions, sub)
 	}
 
 	if err = rows.Err(); err != nil {
@@ -135,12 +107,12 @@ func (r *SubscriptionRepository) GetByUserID(ctx context.Context, userID string)
 	return subscriptions, nil
 }
 
-// HasSubscription проверяет наличие подписки
+// HasSubscription проверяет наличие личной подписки
 func (r *SubscriptionRepository) HasSubscription(ctx context.Context, userID, entityType, entityID string) (bool, error) {
 	query := fmt.Sprintf(`
 		SELECT EXISTS(
 			SELECT 1 FROM %s.entity_subscriptions
-			WHERE user_id = $1 AND entity_type = $2 AND entity_id = $3
+			WHERE user_id = $1 AND entity_type = $2 AND entity_id = $3 AND workspace_id IS NULL
 		)
 	`, r.schema)
 
@@ -156,6 +128,24 @@ func (r *SubscriptionRepository) HasSubscription(ctx context.Context, userID, en
 	return exists, nil
 }
 
+// HasWorkspaceSubscription проверяет наличие подписки рабочего пространства
+func (r *SubscriptionRepository) HasWorkspaceSubscription(ctx context.Context, workspaceID, entityType, entityID string) (bool, error) {
+	query := fmt.Sprintf(`
+		SELECT EXISTS(
+			SELECT 1 FROM %s.entity_subscriptions
+			WHERE workspace_id = $1 AND entity_type = $2 AND entity_id = $3
+		)
+	`, r.schema)
+
+	var exists bool
+	err := r.db.QueryRowContext(ctx, query, workspaceID, strings.ToLower(entityType), entityID).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("failed to check workspace subscription existence: %w", err)
+	}
+
+	return exists, nil
+}
+
 // Create создает новую подписку
 func (r *SubscriptionRepository) Create(ctx context.Context, subscription *model.EntitySubscription) error {
 	// Генерируем ID если не задан
@@ -169,10 +159,10 @@ func (r *SubscriptionRepository) Create(ctx context.Context, subscription *model
 
 	query := fmt.Sprintf(`
 		INSERT INTO %s.entity_subscriptions (
-			id, user_id, user_email, entity_type, entity_id, entity_name,
+			id, user_id, user_email, workspace_id, entity_type, entity_id, entity_name,
 			change_filters, notification_channels, is_active,
 			created_at, updated_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
 	`, r.schema)
 
 	// Конвертируем EntityType в lowercase для соответствия check constraint
@@ -182,6 +172,7 @@ func (r *SubscriptionRepository) Create(ctx context.Context, subscription *model
 		subscription.ID,
 		subscription.UserID,
 		subscription.UserEmail,
+		subscription.WorkspaceID,
 		entityType,
 		subscription.EntityID,
 		subscription.EntityName,
@@ -351,15 +342,27 @@ type EntitySubscription struct {
 	LastNotifiedAt       *time.Time
 }
 
-// GetActiveSubscriptionsForEntity получает активные подписки для сущности (для Notification Hub)
+// GetActiveSubscriptionsForEntity получает активные подписки для сущности (для Notification Hub).
+// Подписка пространства возвращается для каждого участника с его email и каналами.
 func (r *SubscriptionRepository) GetActiveSubscriptionsForEntity(ctx context.Context, entityType, entityID string) ([]EntitySubscription, error) {
 	query := fmt.Sprintf(`
 		SELECT
-			id, user_email, entity_type, entity_id, entity_name,
-			change_filters, notification_channels, is_active,
-			created_at, updated_at, last_notified_at
-		FROM %s.entity_subscriptions
-		WHERE entity_type = $1 AND entity_id = $2 AND is_active = TRUE
+			s.id, s.user_email, s.entity_type, s.entity_id, s.entity_name,
+			s.change_filters, s.notification_channels, s.is_active,
+			s.created_at, s.updated_at, s.last_notified_at
+		FROM %[1]s.entity_subscriptions s
+		WHERE s.entity_type = $1 AND s.entity_id = $2 AND s.is_active = TRUE
+			AND s.workspace_id IS NULL
+		UNION ALL
+		SELECT
+			s.id, u.email, s.entity_type, s.entity_id, s.entity_name,
+			s.change_filters, m.notification_channels, s.is_active,
+			s.created_at, s.updated_at, s.last_notified_at
+		FROM %[1]s.entity_subscriptions s
+		JOIN %[1]s.workspace_members m ON m.workspace_id = s.workspace_id
+		JOIN %[1]s.users u ON u.id = m.user_id
+		WHERE s.entity_type = $1 AND s.entity_id = $2 AND s.is_active = TRUE
+			AND u.is_active = TRUE
 	`, r.schema)
 
 	entityType = strings.ToLower(entityType)
@@ -552,3 +555,131 @@ func (r *SubscriptionRepository) MarkAllNotificationsAsRead(ctx context.Context,
 
 	return rowsAffected, nil
 }
+
+// SubscriptionStats статистика подписок по типу сущности
+type SubscriptionStats struct {
+	EntityType            string
+	TotalSubscriptions    int
+	ActiveSubscriptions   int
+	InactiveSubscriptions int
+	UniqueUsers           int
+}
+
+// NotificationStats статистика отправки уведомлений по каналу и статусу
+type NotificationStats struct {
+	Channel                string
+	Status                 string
+	TotalCount             int
+	RetriedCount           int
+	AvgDeliveryTimeSeconds *float64
+}
+
+// GetSubscriptionStats статистика подписок по всей системе (представление subscription_stats)
+func (r *SubscriptionRepository) GetSubscriptionStats(ctx context.Context) ([]SubscriptionStats, error) {
+	query := fmt.Sprintf(`
+		SELECT
+			entity_type, total_subscriptions, active_subscriptions,
+			inactive_subscriptions, unique_users
+		FROM %s.subscription_stats
+		ORDER BY entity_type
+	`, r.schema)
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		r.logger.Error("failed to get subscription stats", zap.Error(err))
+		return nil, fmt.Errorf("failed to get subscription stats: %w", err)
+	}
+	defer rows.Close()
+
+	var stats []SubscriptionStats
+	for rows.Next() {
+		var s SubscriptionStats
+		if err := rows.Scan(
+			&s.EntityType,
+			&s.TotalSubscriptions,
+			&s.ActiveSubscriptions,
+			&s.InactiveSubscriptions,
+			&s.UniqueUsers,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan subscription stats: %w", err)
+		}
+		stats = append(stats, s)
+	}
+
+	return stats, rows.Err()
+}
+
+// GetNotificationStats статистика уведомлений по каналам и статусам с момента since
+func (r *SubscriptionRepository) GetNotificationStats(ctx context.Context, since time.Time) ([]NotificationStats, error) {
+	query := fmt.Sprintf(`
+		SELECT
+			channel,
+			status,
+			COUNT(*) AS total_count,
+			COUNT(*) FILTER (WHERE retry_count > 0) AS retried_count,
+			AVG(EXTRACT(EPOCH FROM (sent_at - created_at))) AS avg_delivery_time_seconds
+		FROM %s.notification_log
+		WHERE created_at >= $1
+		GROUP BY channel, status
+		ORDER BY channel, status
+	`, r.schema)
+
+	rows, err := r.db.QueryContext(ctx, query, since)
+	if err != nil {
+		r.logger.Error("failed to get notification stats", zap.Error(err))
+		return nil, fmt.Errorf("failed to get notification stats: %w", err)
+	}
+	defer rows.Close()
+
+	var stats []NotificationStats
+	for rows.Next() {
+		var s NotificationStats
+		var avgDelivery sql.NullFloat64
+		if err := rows.Scan(&s.Channel, &s.Status, &s.TotalCount, &s.RetriedCount, &avgDelivery); err != nil {
+			return nil, fmt.Errorf("failed to scan notification stats: %w", err)
+		}
+		if avgDelivery.Valid {
+			s.AvgDeliveryTimeSeconds = &avgDelivery.Float64
+		}
+		stats = append(stats, s)
+	}
+
+	return stats, rows.Err()
+}
+
+func scanSubscription(row rowScanner) (*model.EntitySubscription, error) {
+	var sub model.EntitySubscription
+	var workspaceID sql.NullString
+	var entityType string
+	var lastNotifiedAt sql.NullTime
+
+	err := row.Scan(
+		&sub.ID,
+		&sub.UserID,
+		&workspaceID,
+		&entityType,
+		&sub.EntityID,
+		&sub.EntityName,
+		&sub.ChangeFilters,
+		&sub.NotificationChannels,
+		&sub.IsActive,
+		&sub.CreatedAt,
+		&sub.UpdatedAt,
+		&lastNotifiedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// Конвертируем entityType обратно в uppercase для соответствия GraphQL enum
+	sub.EntityType = model.EntityType(strings.ToUpper(entityType))
+
+	if workspaceID.Valid {
+		sub.WorkspaceID = &workspaceID.String
+	}
+	if lastNotifiedAt.Valid {
+		sub.LastNotifiedAt = &lastNotifiedAt.Time
+	}
+
+	return &sub, nil
+}
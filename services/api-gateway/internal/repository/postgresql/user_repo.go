@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -17,6 +18,7 @@ type User struct {
 	PasswordHash             string
 	FirstName                string
 	LastName                 string
+	Role                     string
 	IsActive                 bool
 	EmailVerified            bool
 	EmailVerificationToken   *string
@@ -26,8 +28,26 @@ type User struct {
 	CreatedAt                time.Time
 	UpdatedAt                time.Time
 	LastLoginAt              *time.Time
+	// DeletionScheduledAt время удаления учетной записи по запросу пользователя
+	DeletionScheduledAt *time.Time
 }
 
+// UserListFilter фильтр списка пользователей
+type UserListFilter struct {
+	Query    string // подстрока email или имени
+	Role     string
+	IsActive *bool
+}
+
+// userColumns колонки users в порядке сканирования scanUser
+const userColumns = `
+	id, email, password_hash, first_name, last_name,
+	role, is_active, email_verified,
+	email_verification_token, email_verification_expires_at,
+	password_reset_token, password_reset_expires_at,
+	created_at, updated_at, last_login_at,
+	deletion_scheduled_at`
+
 // UserRepository реализация для работы с пользователями
 type UserRepository struct {
 	db     *sql.DB
@@ -47,37 +67,12 @@ func NewUserRepository(db *sql.DB, schema string, logger *zap.Logger) *UserRepos
 // GetByID получает пользователя по ID
 func (r *UserRepository) GetByID(ctx context.Context, id string) (*User, error) {
 	query := fmt.Sprintf(`
-		SELECT
-			id, email, password_hash, first_name, last_name,
-			is_active, email_verified,
-			email_verification_token, email_verification_expires_at,
-			password_reset_token, password_reset_expires_at,
-			created_at, updated_at, last_login_at
+		SELECT `+userColumns+`
 		FROM %s.users
 		WHERE id = $1
 	`, r.schema)
 
-	var user User
-	var emailVerificationToken, passwordResetToken sql.NullString
-	var emailVerificationExpires, passwordResetExpires, lastLoginAt sql.NullTime
-
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&user.ID,
-		&user.Email,
-		&user.PasswordHash,
-		&user.FirstName,
-		&user.LastName,
-		&user.IsActive,
-		&user.EmailVerified,
-		&emailVerificationToken,
-		&emailVerificationExpires,
-		&passwordResetToken,
-		&passwordResetExpires,
-		&user.CreatedAt,
-		&user.UpdatedAt,
-		&lastLoginAt,
-	)
-
+	user, err := scanUser(r.db.QueryRowContext(ctx, query, id))
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -86,86 +81,104 @@ func (r *UserRepository) GetByID(ctx context.Context, id string) (*User, error)
 		return nil, fmt.Errorf("failed to get user: %w", err)
 	}
 
-	// Обработка nullable полей
-	if emailVerificationToken.Valid {
-		user.EmailVerificationToken = &emailVerificationToken.String
-	}
-	if emailVerificationExpires.Valid {
-		user.EmailVerificationExpires = &emailVerificationExpires.Time
-	}
-	if passwordResetToken.Valid {
-		user.PasswordResetToken = &passwordResetToken.String
-	}
-	if passwordResetExpires.Valid {
-		user.PasswordResetExpires = &passwordResetExpires.Time
-	}
-	if lastLoginAt.Valid {
-		user.LastLoginAt = &lastLoginAt.Time
-	}
-
-	return &user, nil
+	return user, nil
 }
 
 // GetByEmail получает пользователя по email
 func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*User, error) {
 	query := fmt.Sprintf(`
-		SELECT
-			id, email, password_hash, first_name, last_name,
-			is_active, email_verified,
-			email_verification_token, email_verification_expires_at,
-			password_reset_token, password_reset_expires_at,
-			created_at, updated_at, last_login_at
+		SELECT `+userColumns+`
 		FROM %s.users
 		WHERE email = $1
 	`, r.schema)
 
-	var user User
-	var emailVerificationToken, passwordResetToken sql.NullString
-	var emailVerificationExpires, passwordResetExpires, lastLoginAt sql.NullTime
+	user, err := scanUser(r.db.QueryRowContext(ctx, query, email))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		r.logger.Error("failed to get user by email", zap.Error(err), zap.String("email", email))
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
 
-	err := r.db.QueryRowContext(ctx, query, email).Scan(
-		&user.ID,
-		&user.Email,
-		&user.PasswordHash,
-		&user.FirstName,
-		&user.LastName,
-		&user.IsActive,
-		&user.EmailVerified,
-		&emailVerificationToken,
-		&emailVerificationExpires,
-		&passwordResetToken,
-		&passwordResetExpires,
-		&user.CreatedAt,
-		&user.UpdatedAt,
-		&lastLoginAt,
-	)
+	return user, nil
+}
+
+// GetByEmailVerificationToken получает пользователя по хешу токена подтверждения email
+func (r *UserRepository) GetByEmailVerificationToken(ctx context.Context, tokenHash string) (*User, error) {
+	return r.getByToken(ctx, "email_verification_token", tokenHash)
+}
+
+// GetByPasswordResetToken получает пользователя по хешу токена сброса пароля
+func (r *UserRepository) GetByPasswordResetToken(ctx context.Context, tokenHash string) (*User, error) {
+	return r.getByToken(ctx, "password_reset_token", tokenHash)
+}
+
+// GetByOIDCSubject получает пользователя, связанного с учетной записью OIDC провайдера
+func (r *UserRepository) GetByOIDCSubject(ctx context.Context, issuer, subject string) (*User, error) {
+	query := fmt.Sprintf(`
+		SELECT `+userColumns+`
+		FROM %s.users
+		WHERE oidc_issuer = $1 AND oidc_subject = $2
+	`, r.schema)
 
+	user, err := scanUser(r.db.QueryRowContext(ctx, query, issuer, subject))
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
 	if err != nil {
-		r.logger.Error("failed to get user by email", zap.Error(err), zap.String("email", email))
+		r.logger.Error("failed to get user by oidc subject", zap.Error(err), zap.String("issuer", issuer))
 		return nil, fmt.Errorf("failed to get user: %w", err)
 	}
 
-	// Обработка nullable полей
-	if emailVerificationToken.Valid {
-		user.EmailVerificationToken = &emailVerificationToken.String
+	return user, nil
+}
+
+// LinkOIDCSubject связывает пользователя с учетной записью OIDC провайдера.
+// Возвращает false, если пользователь уже связан с другой учетной записью.
+func (r *UserRepository) LinkOIDCSubject(ctx context.Context, userID, issuer, subject string) (bool, error) {
+	query := fmt.Sprintf(`
+		UPDATE %s.users
+		SET oidc_issuer = $1, oidc_subject = $2, updated_at = $3
+		WHERE id = $4 AND (oidc_subject IS NULL OR (oidc_issuer = $1 AND oidc_subject = $2))
+	`, r.schema)
+
+	result, err := r.db.ExecContext(ctx, query, issuer, subject, time.Now(), userID)
+	if err != nil {
+		r.logger.Error("failed to link oidc subject", zap.Error(err), zap.String("user_id", userID))
+		return false, fmt.Errorf("failed to link oidc subject: %w", err)
 	}
-	if emailVerificationExpires.Valid {
-		user.EmailVerificationExpires = &emailVerificationExpires.Time
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
 	}
-	if passwordResetToken.Valid {
-		user.PasswordResetToken = &passwordResetToken.String
+
+	if rowsAffected > 0 {
+		r.logger.Info("oidc subject linked", zap.String("user_id", userID), zap.String("issuer", issuer))
 	}
-	if passwordResetExpires.Valid {
-		user.PasswordResetExpires = &passwordResetExpires.Time
+	return rowsAffected > 0, nil
+}
+
+// getByToken ищет пользователя по значению колонки с токеном; column - только
+// константы из методов выше, не пользовательский ввод
+func (r *UserRepository) getByToken(ctx context.Context, column, tokenHash string) (*User, error) {
+	query := fmt.Sprintf(`
+		SELECT `+userColumns+`
+		FROM %s.users
+		WHERE %s = $1
+	`, r.schema, column)
+
+	user, err := scanUser(r.db.QueryRowContext(ctx, query, tokenHash))
+	if err == sql.ErrNoRows {
+		return nil, nil
 	}
-	if lastLoginAt.Valid {
-		user.LastLoginAt = &lastLoginAt.Time
+	if err != nil {
+		r.logger.Error("failed to get user by token", zap.Error(err), zap.String("column", column))
+		return nil, fmt.Errorf("failed to get user: %w", err)
 	}
 
-	return &user, nil
+	return user, nil
 }
 
 // Create создает нового пользователя
@@ -182,11 +195,11 @@ func (r *UserRepository) Create(ctx context.Context, user *User) error {
 	query := fmt.Sprintf(`
 		INSERT INTO %s.users (
 			id, email, password_hash, first_name, last_name,
-			is_active, email_verified,
+			role, is_active, email_verified,
 			email_verification_token, email_verification_expires_at,
 			password_reset_token, password_reset_expires_at,
 			created_at, updated_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
 	`, r.schema)
 
 	_, err := r.db.ExecContext(ctx, query,
@@ -195,6 +208,7 @@ func (r *UserRepository) Create(ctx context.Context, user *User) error {
 		user.PasswordHash,
 		user.FirstName,
 		user.LastName,
+		user.Role,
 		user.IsActive,
 		user.EmailVerified,
 		user.EmailVerificationToken,
@@ -295,6 +309,123 @@ func (r *UserRepository) UpdatePassword(ctx context.Context, userID, passwordHas
 	return nil
 }
 
+// UpdateRole меняет роль пользователя
+func (r *UserRepository) UpdateRole(ctx context.Context, userID, role string) error {
+	query := fmt.Sprintf(`
+		UPDATE %s.users
+		SET role = $1, updated_at = $2
+		WHERE id = $3
+	`, r.schema)
+
+	result, err := r.db.ExecContext(ctx, query, role, time.Now(), userID)
+	if err != nil {
+		r.logger.Error("failed to update role", zap.Error(err), zap.String("user_id", userID))
+		return fmt.Errorf("failed to update role: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("user not found")
+	}
+
+	r.logger.Info("role updated", zap.String("user_id", userID), zap.String("role", role))
+	return nil
+}
+
+// List возвращает страницу пользователей по фильтру и общее число найденных
+func (r *UserRepository) List(ctx context.Context, filter UserListFilter, limit, offset int) ([]*User, int, error) {
+	var conditions []string
+	var args []interface{}
+
+	if filter.Query != "" {
+		args = append(args, "%"+filter.Query+"%")
+		n := len(args)
+		conditions = append(conditions, fmt.Sprintf(
+			"(email ILIKE $%d OR first_name ILIKE $%d OR last_name ILIKE $%d)", n, n, n))
+	}
+	if filter.Role != "" {
+		args = append(args, filter.Role)
+		conditions = append(conditions, fmt.Sprintf("role = $%d", len(args)))
+	}
+	if filter.IsActive != nil {
+		args = append(args, *filter.IsActive)
+		conditions = append(conditions, fmt.Sprintf("is_active = $%d", len(args)))
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int
+	countQuery := fmt.Sprintf(`SELECT COUNT(*) FROM %s.users %s`, r.schema, where)
+	if err := r.db.QueryRowContext(ctx, countQuery, args...).Scan(&total); err != nil {
+		r.logger.Error("failed to count users", zap.Error(err))
+		return nil, 0, fmt.Errorf("failed to count users: %w", err)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT `+userColumns+`
+		FROM %s.users
+		%s
+		ORDER BY created_at DESC
+		LIMIT $%d OFFSET $%d
+	`, r.schema, where, len(args)+1, len(args)+2)
+
+	rows, err := r.db.QueryContext(ctx, query, append(args, limit, offset)...)
+	if err != nil {
+		r.logger.Error("failed to list users", zap.Error(err))
+		return nil, 0, fmt.Errorf("failed to list users: %w", err)
+	}
+	defer rows.Close()
+
+	var users []*User
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan user: %w", err)
+		}
+		users = append(users, user)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("failed to iterate users: %w", err)
+	}
+
+	return users, total, nil
+}
+
+// CountByRole число активных пользователей по ролям
+func (r *UserRepository) CountByRole(ctx context.Context) (map[string]int, error) {
+	query := fmt.Sprintf(`
+		SELECT role, COUNT(*)
+		FROM %s.users
+		WHERE is_active = TRUE
+		GROUP BY role
+	`, r.schema)
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		r.logger.Error("failed to count users by role", zap.Error(err))
+		return nil, fmt.Errorf("failed to count users: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var role string
+		var count int
+		if err := rows.Scan(&role, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan user count: %w", err)
+		}
+		counts[role] = count
+	}
+	return counts, rows.Err()
+}
+
 // UpdateLastLogin обновляет время последнего входа
 func (r *UserRepository) UpdateLastLogin(ctx context.Context, userID string) error {
 	now := time.Now()
@@ -339,3 +470,250 @@ func (r *UserRepository) Delete(ctx context.Context, userID string) error {
 	r.logger.Info("user deleted (soft)", zap.String("user_id", userID))
 	return nil
 }
+
+// ScheduleDeletion назначает удаление учетной записи на время at. Возвращает
+// false, если удаление уже назначено.
+func (r *UserRepository) ScheduleDeletion(ctx context.Context, userID string, at time.Time) (bool, error) {
+	query := fmt.Sprintf(`
+		UPDATE %s.users
+		SET deletion_requested_at = $1, deletion_scheduled_at = $2, updated_at = $1
+		WHERE id = $3 AND deletion_scheduled_at IS NULL
+	`, r.schema)
+
+	result, err := r.db.ExecContext(ctx, query, time.Now(), at, userID)
+	if err != nil {
+		r.logger.Error("failed to schedule user deletion", zap.Error(err), zap.String("user_id", userID))
+		return false, fmt.Errorf("failed to schedule user deletion: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected > 0 {
+		r.logger.Info("user deletion scheduled", zap.String("user_id", userID), zap.Time("at", at))
+	}
+	return rowsAffected > 0, nil
+}
+
+// CancelDeletion отменяет назначенное удаление учетной записи. Возвращает
+// false, если удаление не было назначено.
+func (r *UserRepository) CancelDeletion(ctx context.Context, userID string) (bool, error) {
+	query := fmt.Sprintf(`
+		UPDATE %s.users
+		SET deletion_requested_at = NULL, deletion_scheduled_at = NULL, updated_at = $1
+		WHERE id = $2 AND deletion_scheduled_at IS NOT NULL
+	`, r.schema)
+
+	result, err := r.db.ExecContext(ctx, query, time.Now(), userID)
+	if err != nil {
+		r.logger.Error("failed to cancel user deletion", zap.Error(err), zap.String("user_id", userID))
+		return false, fmt.Errorf("failed to cancel user deletion: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected > 0 {
+		r.logger.Info("user deletion cancelled", zap.String("user_id", userID))
+	}
+	return rowsAffected > 0, nil
+}
+
+// GetDueForDeletion возвращает ID пользователей, срок удаления которых наступил
+func (r *UserRepository) GetDueForDeletion(ctx context.Context, now time.Time, limit int) ([]string, error) {
+	query := fmt.Sprintf(`
+		SELECT id
+		FROM %s.users
+		WHERE deletion_scheduled_at IS NOT NULL AND deletion_scheduled_at <= $1
+		ORDER BY deletion_scheduled_at
+		LIMIT $2
+	`, r.schema)
+
+	rows, err := r.db.QueryContext(ctx, query, now, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get users due for deletion: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan user id: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// Purge окончательно удаляет пользователя, если срок удаления наступил.
+// Личные данные (избранное, подписки, сессии, API ключи, членство в
+// пространствах) удаляются каскадно, журнал уведомлений - по email
+// получателя. Общие записи пространств передаются владельцу пространства,
+// пространства без других участников удаляются, авторство заметок
+// обезличивается. Возвращает false, если удаление отменено или уже выполнено.
+func (r *UserRepository) Purge(ctx context.Context, userID string, now time.Time) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Блокировка строки защищает от одновременной отмены удаления
+	var email string
+	err = tx.QueryRowContext(ctx, fmt.Sprintf(`
+		SELECT email FROM %s.users
+		WHERE id = $1 AND deletion_scheduled_at IS NOT NULL AND deletion_scheduled_at <= $2
+		FOR UPDATE
+	`, r.schema), userID, now).Scan(&email)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to lock user: %w", err)
+	}
+
+	steps := []struct {
+		what  string
+		query string
+		args  []interface{}
+	}{
+		{
+			what: "delete sole-member workspaces",
+			query: `
+				DELETE FROM %[1]s.workspaces w
+				WHERE EXISTS (SELECT 1 FROM %[1]s.workspace_members m WHERE m.workspace_id = w.id AND m.user_id = $1)
+				  AND NOT EXISTS (SELECT 1 FROM %[1]s.workspace_members m WHERE m.workspace_id = w.id AND m.user_id <> $1)`,
+			args: []interface{}{userID},
+		},
+		{
+			// Пространство не остается без владельца: владельцем становится
+			// участник, вступивший раньше других
+			what: "promote workspace owners",
+			query: `
+				UPDATE %[1]s.workspace_members m
+				SET role = 'owner'
+				FROM (
+					SELECT DISTINCT ON (o.workspace_id) o.workspace_id, o.user_id
+					FROM %[1]s.workspace_members o
+					WHERE o.user_id <> $1
+					  AND o.workspace_id IN (
+						SELECT workspace_id FROM %[1]s.workspace_members WHERE user_id = $1 AND role = 'owner')
+					  AND NOT EXISTS (
+						SELECT 1 FROM %[1]s.workspace_members x
+						WHERE x.workspace_id = o.workspace_id AND x.role = 'owner' AND x.user_id <> $1)
+					ORDER BY o.workspace_id, o.joined_at, o.user_id
+				) heir
+				WHERE m.workspace_id = heir.workspace_id AND m.user_id = heir.user_id`,
+			args: []interface{}{userID},
+		},
+		{
+			what: "reassign workspace favorites",
+			query: `
+				UPDATE %[1]s.favorites f
+				SET user_id = (
+					SELECT o.user_id FROM %[1]s.workspace_members o
+					WHERE o.workspace_id = f.workspace_id AND o.role = 'owner' AND o.user_id <> $1
+					ORDER BY o.joined_at, o.user_id LIMIT 1)
+				WHERE f.user_id = $1 AND f.workspace_id IS NOT NULL
+				  AND EXISTS (
+					SELECT 1 FROM %[1]s.workspace_members o
+					WHERE o.workspace_id = f.workspace_id AND o.role = 'owner' AND o.user_id <> $1)`,
+			args: []interface{}{userID},
+		},
+		{
+			what: "reassign workspace subscriptions",
+			query: `
+				UPDATE %[1]s.entity_subscriptions s
+				SET (user_id, user_email) = (
+					SELECT u.id, u.email
+					FROM %[1]s.workspace_members o
+					JOIN %[1]s.users u ON u.id = o.user_id
+					WHERE o.workspace_id = s.workspace_id AND o.role = 'owner' AND o.user_id <> $1
+					ORDER BY o.joined_at, o.user_id LIMIT 1)
+				WHERE s.user_id = $1 AND s.workspace_id IS NOT NULL
+				  AND EXISTS (
+					SELECT 1 FROM %[1]s.workspace_members o
+					WHERE o.workspace_id = s.workspace_id AND o.role = 'owner' AND o.user_id <> $1)`,
+			args: []interface{}{userID},
+		},
+		{
+			what:  "delete notification log",
+			query: `DELETE FROM %[1]s.notification_log WHERE recipient = $1`,
+			args:  []interface{}{email},
+		},
+		{
+			what:  "delete user",
+			query: `DELETE FROM %[1]s.users WHERE id = $1`,
+			args:  []interface{}{userID},
+		},
+	}
+	for _, step := range steps {
+		if _, err := tx.ExecContext(ctx, fmt.Sprintf(step.query, r.schema), step.args...); err != nil {
+			r.logger.Error("failed to purge user", zap.Error(err), zap.String("user_id", userID), zap.String("step", step.what))
+			return false, fmt.Errorf("failed to purge user (%s): %w", step.what, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit user purge: %w", err)
+	}
+
+	r.logger.Info("user purged", zap.String("user_id", userID))
+	return true, nil
+}
+
+// scanUser сканирует строку с колонками userColumns
+func scanUser(row rowScanner) (*User, error) {
+	var user User
+	var emailVerificationToken, passwordResetToken sql.NullString
+	var emailVerificationExpires, passwordResetExpires, lastLoginAt, deletionScheduledAt sql.NullTime
+
+	err := row.Scan(
+		&user.ID,
+		&user.Email,
+		&user.PasswordHash,
+		&user.FirstName,
+		&user.LastName,
+		&user.Role,
+		&user.IsActive,
+		&user.EmailVerified,
+		&emailVerificationToken,
+		&emailVerificationExpires,
+		&passwordResetToken,
+		&passwordResetExpires,
+		&user.CreatedAt,
+		&user.UpdatedAt,
+		&lastLoginAt,
+		&deletionScheduledAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// Обработка nullable полей
+	if emailVerificationToken.Valid {
+		user.EmailVerificationToken = &emailVerificationToken.String
+	}
+	if emailVerificationExpires.Valid {
+		user.EmailVerificationExpires = &emailVerificationExpires.Time
+	}
+	if passwordResetToken.Valid {
+		user.PasswordResetToken = &passwordResetToken.String
+	}
+	if passwordResetExpires.Valid {
+		user.PasswordResetExpires = &passwordResetExpires.Time
+	}
+	if lastLoginAt.Valid {
+		user.LastLoginAt = &lastLoginAt.Time
+	}
+	if deletionScheduledAt.Valid {
+		user.DeletionScheduledAt = &deletionScheduledAt.Time
+	}
+
+	return &user, nil
+}
@@ -0,0 +1,527 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/egrul-system/services/api-gateway/internal/graph/model"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// Workspace рабочее пространство команды
+type Workspace struct {
+	ID        string
+	Name      string
+	CreatedBy *string
+	// Role роль пользователя, для которого загружено пространство
+	Role        string
+	MemberCount int
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// WorkspaceMember участник рабочего пространства
+type WorkspaceMember struct {
+	WorkspaceID          string
+	UserID               string
+	Email                string
+	FirstName            string
+	LastName             string
+	Role                 string
+	NotificationChannels model.NotificationChannels
+	JoinedAt             time.Time
+}
+
+// EntityNote заметка участника пространства о компании или ИП
+type EntityNote struct {
+	ID          string
+	WorkspaceID string
+	EntityType  string
+	EntityID    string
+	// AuthorID nil, если учетная запись автора удалена
+	AuthorID   *string
+	AuthorName string
+	Body       string
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// WorkspaceRepository реализация для работы с рабочими пространствами
+type WorkspaceRepository struct {
+	db     *sql.DB
+	schema string
+	logger *zap.Logger
+}
+
+// NewWorkspaceRepository создает новый экземпляр WorkspaceRepository
+func NewWorkspaceRepository(db *sql.DB, schema string, logger *zap.Logger) *WorkspaceRepository {
+	return &WorkspaceRepository{
+		db:     db,
+		schema: schema,
+		logger: logger,
+	}
+}
+
+// Create создает пространство и добавляет создателя владельцем
+func (r *WorkspaceRepository) Create(ctx context.Context, workspace *Workspace, ownerID, ownerRole string) error {
+	if workspace.ID == "" {
+		workspace.ID = uuid.New().String()
+	}
+	now := time.Now()
+	workspace.CreatedAt = now
+	workspace.UpdatedAt = now
+	workspace.CreatedBy = &ownerID
+	workspace.Role = ownerRole
+	workspace.MemberCount = 1
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, fmt.Sprintf(`
+		INSERT INTO %s.workspaces (id, name, created_by, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`, r.schema), workspace.ID, workspace.Name, ownerID, now, now)
+	if err != nil {
+		return fmt.Errorf("failed to create workspace: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, fmt.Sprintf(`
+		INSERT INTO %s.workspace_members (workspace_id, user_id, role, joined_at)
+		VALUES ($1, $2, $3, $4)
+	`, r.schema), workspace.ID, ownerID, ownerRole, now)
+	if err != nil {
+		return fmt.Errorf("failed to add workspace owner: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit workspace: %w", err)
+	}
+
+	r.logger.Info("workspace created", zap.String("id", workspace.ID), zap.String("owner_id", ownerID))
+	return nil
+}
+
+// GetForMember получает пространство с ролью пользователя; nil, если
+// пространства нет или пользователь не участник
+func (r *WorkspaceRepository) GetForMember(ctx context.Context, workspaceID, userID string) (*Workspace, error) {
+	query := fmt.Sprintf(`
+		SELECT
+			w.id, w.name, w.created_by, m.role,
+			(SELECT COUNT(*) FROM %[1]s.workspace_members c WHERE c.workspace_id = w.id),
+			w.created_at, w.updated_at
+		FROM %[1]s.workspaces w
+		JOIN %[1]s.workspace_members m ON m.workspace_id = w.id
+		WHERE w.id = $1 AND m.user_id = $2
+	`, r.schema)
+
+	workspace, err := scanWorkspace(r.db.QueryRowContext(ctx, query, workspaceID, userID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get workspace: %w", err)
+	}
+	return workspace, nil
+}
+
+// GetByUserID пространства, в которых состоит пользователь
+func (r *WorkspaceRepository) GetByUserID(ctx context.Context, userID string) ([]*Workspace, error) {
+	query := fmt.Sprintf(`
+		SELECT
+			w.id, w.name, w.created_by, m.role,
+			(SELECT COUNT(*) FROM %[1]s.workspace_members c WHERE c.workspace_id = w.id),
+			w.created_at, w.updated_at
+		FROM %[1]s.workspaces w
+		JOIN %[1]s.workspace_members m ON m.workspace_id = w.id
+		WHERE m.user_id = $1
+		ORDER BY w.name
+	`, r.schema)
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query workspaces: %w", err)
+	}
+	defer rows.Close()
+
+	var workspaces []*Workspace
+	for rows.Next() {
+		workspace, err := scanWorkspace(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan workspace: %w", err)
+		}
+		workspaces = append(workspaces, workspace)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating workspaces: %w", err)
+	}
+
+	return workspaces, nil
+}
+
+// Rename переименовывает пространство
+func (r *WorkspaceRepository) Rename(ctx context.Context, workspaceID, name string) error {
+	query := fmt.Sprintf(`UPDATE %s.workspaces SET name = $1 WHERE id = $2`, r.schema)
+
+	result, err := r.db.ExecContext(ctx, query, name, workspaceID)
+	if err != nil {
+		return fmt.Errorf("failed to rename workspace: %w", err)
+	}
+	return requireAffected(result, "workspace", workspaceID)
+}
+
+// Delete удаляет пространство вместе с его избранным, подписками и заметками
+func (r *WorkspaceRepository) Delete(ctx context.Context, workspaceID string) error {
+	query := fmt.Sprintf(`DELETE FROM %s.workspaces WHERE id = $1`, r.schema)
+
+	result, err := r.db.ExecContext(ctx, query, workspaceID)
+	if err != nil {
+		return fmt.Errorf("failed to delete workspace: %w", err)
+	}
+	if err := requireAffected(result, "workspace", workspaceID); err != nil {
+		return err
+	}
+
+	r.logger.Info("workspace deleted", zap.String("id", workspaceID))
+	return nil
+}
+
+// GetMember получает участника пространства; nil, если пользователь не участник
+func (r *WorkspaceRepository) GetMember(ctx context.Context, workspaceID, userID string) (*WorkspaceMember, error) {
+	query := fmt.Sprintf(`
+		SELECT
+			m.workspace_id, m.user_id, u.email, u.first_name, u.last_name,
+			m.role, m.notification_channels, m.joined_at
+		FROM %[1]s.workspace_members m
+		JOIN %[1]s.users u ON u.id = m.user_id
+		WHERE m.workspace_id = $1 AND m.user_id = $2
+	`, r.schema)
+
+	member, err := scanWorkspaceMember(r.db.QueryRowContext(ctx, query, workspaceID, userID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get workspace member: %w", err)
+	}
+	return member, nil
+}
+
+// GetMembers участники пространства: владельцы, затем остальные по времени вступления
+func (r *WorkspaceRepository) GetMembers(ctx context.Context, workspaceID string) ([]*WorkspaceMember, error) {
+	query := fmt.Sprintf(`
+		SELECT
+			m.workspace_id, m.user_id, u.email, u.first_name, u.last_name,
+			m.role, m.notification_channels, m.joined_at
+		FROM %[1]s.workspace_members m
+		JOIN %[1]s.users u ON u.id = m.user_id
+		WHERE m.workspace_id = $1
+		ORDER BY m.role = 'owner' DESC, m.joined_at
+	`, r.schema)
+
+	rows, err := r.db.QueryContext(ctx, query, workspaceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query workspace members: %w", err)
+	}
+	defer rows.Close()
+
+	var members []*WorkspaceMember
+	for rows.Next() {
+		member, err := scanWorkspaceMember(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan workspace member: %w", err)
+		}
+		members = append(members, member)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating workspace members: %w", err)
+	}
+
+	return members, nil
+}
+
+// AddMember добавляет участника; false, если пользователь уже состоит в пространстве
+func (r *WorkspaceRepository) AddMember(ctx context.Context, workspaceID, userID, role string) (bool, error) {
+	query := fmt.Sprintf(`
+		INSERT INTO %s.workspace_members (workspace_id, user_id, role)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (workspace_id, user_id) DO NOTHING
+	`, r.schema)
+
+	result, err := r.db.ExecContext(ctx, query, workspaceID, userID, role)
+	if err != nil {
+		return false, fmt.Errorf("failed to add workspace member: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if affected > 0 {
+		r.logger.Info("workspace member added",
+			zap.String("workspace_id", workspaceID),
+			zap.String("user_id", userID),
+			zap.String("role", role),
+		)
+	}
+	return affected > 0, nil
+}
+
+// UpdateMemberRole меняет роль участника
+func (r *WorkspaceRepository) UpdateMemberRole(ctx context.Context, workspaceID, userID, role string) error {
+	query := fmt.Sprintf(`
+		UPDATE %s.workspace_members SET role = $1
+		WHERE workspace_id = $2 AND user_id = $3
+	`, r.schema)
+
+	result, err := r.db.ExecContext(ctx, query, role, workspaceID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to update workspace member role: %w", err)
+	}
+	return requireAffected(result, "workspace member", userID)
+}
+
+// UpdateMemberChannels меняет каналы уведомлений участника
+func (r *WorkspaceRepository) UpdateMemberChannels(ctx context.Context, workspaceID, userID string, channels model.NotificationChannels) error {
+	query := fmt.Sprintf(`
+		UPDATE %s.workspace_members SET notification_channels = $1
+		WHERE workspace_id = $2 AND user_id = $3
+	`, r.schema)
+
+	result, err := r.db.ExecContext(ctx, query, channels, workspaceID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to update workspace member channels: %w", err)
+	}
+	return requireAffected(result, "workspace member", userID)
+}
+
+// RemoveMember исключает участника из пространства
+func (r *WorkspaceRepository) RemoveMember(ctx context.Context, workspaceID, userID string) error {
+	query := fmt.Sprintf(`
+		DELETE FROM %s.workspace_members
+		WHERE workspace_id = $1 AND user_id = $2
+	`, r.schema)
+
+	result, err := r.db.ExecContext(ctx, query, workspaceID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to remove workspace member: %w", err)
+	}
+	if err := requireAffected(result, "workspace member", userID); err != nil {
+		return err
+	}
+
+	r.logger.Info("workspace member removed",
+		zap.String("workspace_id", workspaceID),
+		zap.String("user_id", userID),
+	)
+	return nil
+}
+
+// CountMembersWithRole число участников пространства с ролью
+func (r *WorkspaceRepository) CountMembersWithRole(ctx context.Context, workspaceID, role string) (int, error) {
+	query := fmt.Sprintf(`
+		SELECT COUNT(*) FROM %s.workspace_members
+		WHERE workspace_id = $1 AND role = $2
+	`, r.schema)
+
+	var count int
+	if err := r.db.QueryRowContext(ctx, query, workspaceID, role).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count workspace members: %w", err)
+	}
+	return count, nil
+}
+
+// CreateNote сохраняет заметку
+func (r *WorkspaceRepository) CreateNote(ctx context.Context, note *EntityNote) error {
+	if note.ID == "" {
+		note.ID = uuid.New().String()
+	}
+	now := time.Now()
+	note.CreatedAt = now
+	note.UpdatedAt = now
+	note.EntityType = strings.ToLower(note.EntityType)
+
+	query := fmt.Sprintf(`
+		INSERT INTO %s.entity_notes (
+			id, workspace_id, entity_type, entity_id, author_id, body, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`, r.schema)
+
+	_, err := r.db.ExecContext(ctx, query,
+		note.ID,
+		note.WorkspaceID,
+		note.EntityType,
+		note.EntityID,
+		note.AuthorID,
+		note.Body,
+		note.CreatedAt,
+		note.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create entity note: %w", err)
+	}
+
+	r.logger.Info("entity note created",
+		zap.String("id", note.ID),
+		zap.String("workspace_id", note.WorkspaceID),
+		zap.String("entity_id", note.EntityID),
+	)
+	return nil
+}
+
+// GetNote получает заметку по ID; nil, если не найдена
+func (r *WorkspaceRepository) GetNote(ctx context.Context, id string) (*EntityNote, error) {
+	query := fmt.Sprintf(`
+		SELECT
+			n.id, n.workspace_id, n.entity_type, n.entity_id, n.author_id,
+			COALESCE(u.first_name || ' ' || u.last_name, ''), n.body, n.created_at, n.updated_at
+		FROM %[1]s.entity_notes n
+		LEFT JOIN %[1]s.users u ON u.id = n.author_id
+		WHERE n.id = $1
+	`, r.schema)
+
+	note, err := scanEntityNote(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get entity note: %w", err)
+	}
+	return note, nil
+}
+
+// GetNotes заметки пространства о сущности, новые первыми
+func (r *WorkspaceRepository) GetNotes(ctx context.Context, workspaceID, entityType, entityID string) ([]*EntityNote, error) {
+	query := fmt.Sprintf(`
+		SELECT
+			n.id, n.workspace_id, n.entity_type, n.entity_id, n.author_id,
+			COALESCE(u.first_name || ' ' || u.last_name, ''), n.body, n.created_at, n.updated_at
+		FROM %[1]s.entity_notes n
+		LEFT JOIN %[1]s.users u ON u.id = n.author_id
+		WHERE n.workspace_id = $1 AND n.entity_type = $2 AND n.entity_id = $3
+		ORDER BY n.created_at DESC
+	`, r.schema)
+
+	rows, err := r.db.QueryContext(ctx, query, workspaceID, strings.ToLower(entityType), entityID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query entity notes: %w", err)
+	}
+	defer rows.Close()
+
+	var notes []*EntityNote
+	for rows.Next() {
+		note, err := scanEntityNote(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan entity note: %w", err)
+		}
+		notes = append(notes, note)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating entity notes: %w", err)
+	}
+
+	return notes, nil
+}
+
+// UpdateNote меняет текст заметки
+func (r *WorkspaceRepository) UpdateNote(ctx context.Context, id, body string) error {
+	query := fmt.Sprintf(`UPDATE %s.entity_notes SET body = $1 WHERE id = $2`, r.schema)
+
+	result, err := r.db.ExecContext(ctx, query, body, id)
+	if err != nil {
+		return fmt.Errorf("failed to update entity note: %w", err)
+	}
+	return requireAffected(result, "entity note", id)
+}
+
+// DeleteNote удаляет заметку
+func (r *WorkspaceRepository) DeleteNote(ctx context.Context, id string) error {
+	query := fmt.Sprintf(`DELETE FROM %s.entity_notes WHERE id = $1`, r.schema)
+
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete entity note: %w", err)
+	}
+	return requireAffected(result, "entity note", id)
+}
+
+func scanWorkspace(row rowScanner) (*Workspace, error) {
+	var w Workspace
+	var createdBy sql.NullString
+
+	if err := row.Scan(
+		&w.ID,
+		&w.Name,
+		&createdBy,
+		&w.Role,
+		&w.MemberCount,
+		&w.CreatedAt,
+		&w.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	if createdBy.Valid {
+		w.CreatedBy = &createdBy.String
+	}
+	return &w, nil
+}
+
+func scanWorkspaceMember(row rowScanner) (*WorkspaceMember, error) {
+	var m WorkspaceMember
+	if err := row.Scan(
+		&m.WorkspaceID,
+		&m.UserID,
+		&m.Email,
+		&m.FirstName,
+		&m.LastName,
+		&m.Role,
+		&m.NotificationChannels,
+		&m.JoinedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+func scanEntityNote(row rowScanner) (*EntityNote, error) {
+	var n EntityNote
+	var authorID sql.NullString
+
+	if err := row.Scan(
+		&n.ID,
+		&n.WorkspaceID,
+		&n.EntityType,
+		&n.EntityID,
+		&authorID,
+		&n.AuthorName,
+		&n.Body,
+		&n.CreatedAt,
+		&n.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	if authorID.Valid {
+		n.AuthorID = &authorID.String
+	}
+	return &n, nil
+}
+
+// requireAffected ошибка, если запрос не затронул ни одной строки
+func requireAffected(result sql.Result, what, id string) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("%s not found: %s", what, id)
+	}
+	return nil
+}
@@ -0,0 +1,188 @@
+// Package rest содержит REST обработчики API Gateway, не покрываемые GraphQL
+package rest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/egrul-system/services/api-gateway/internal/auth"
+	"github.com/egrul-system/services/api-gateway/internal/graph/model"
+	"github.com/egrul-system/services/api-gateway/internal/service"
+	"github.com/egrul-system/services/api-gateway/internal/spreadsheet"
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+// BulkCheckHandler обрабатывает REST запросы пакетной проверки контрагентов
+type BulkCheckHandler struct {
+	svc         *service.BulkCheckService
+	maxFileSize int64
+	logger      *zap.Logger
+}
+
+// NewBulkCheckHandler создает новый обработчик пакетной проверки
+func NewBulkCheckHandler(svc *service.BulkCheckService, maxFileSize int64, logger *zap.Logger) *BulkCheckHandler {
+	if maxFileSize <= 0 {
+		maxFileSize = 10 << 20
+	}
+	return &BulkCheckHandler{
+		svc:         svc,
+		maxFileSize: maxFileSize,
+		logger:      logger.Named("bulk_check_handler"),
+	}
+}
+
+// Routes регистрирует маршруты обработчика
+func (h *BulkCheckHandler) Routes(r chi.Router) {
+	r.Post("/bulk-check", h.Upload)
+	r.Get("/bulk-check", h.List)
+	r.Get("/bulk-check/{id}", h.Status)
+	r.Get("/bulk-check/{id}/result", h.Download)
+}
+
+// Upload принимает multipart файл (поле "file") и создает задание.
+// Параметр format (csv|xlsx) задает формат отчета по умолчанию.
+func (h *BulkCheckHandler) Upload(w http.ResponseWriter, r *http.Request) {
+	userID := auth.GetUserIDFromContext(r.Context())
+	if userID == "" {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, h.maxFileSize+1<<20)
+	if err := r.ParseMultipartForm(h.maxFileSize); err != nil {
+		http.Error(w, "Invalid multipart form: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		http.Error(w, "Missing file", http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, h.maxFileSize+1))
+	if err != nil {
+		http.Error(w, "Failed to read file", http.StatusBadRequest)
+		return
+	}
+	if int64(len(data)) > h.maxFileSize {
+		http.Error(w, fmt.Sprintf("File is too large (max %d bytes)", h.maxFileSize), http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	format, err := spreadsheet.ParseFormat(r.FormValue("format"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	job, err := h.svc.Submit(r.Context(), userID, header.Filename, data, format)
+	if err != nil {
+		if errors.Is(err, service.ErrBulkCheckQueueFull) {
+			w.Header().Set("Retry-After", "60")
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+		h.logger.Warn("failed to submit bulk check job", zap.String("user_id", userID), zap.Error(err))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Location", "/api/v1/bulk-check/"+job.ID)
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(job)
+}
+
+// List возвращает последние задания пользователя
+func (h *BulkCheckHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID := auth.GetUserIDFromContext(r.Context())
+	if userID == "" {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	jobs, err := h.svc.ListJobs(r.Context(), userID, limit)
+	if err != nil {
+		h.logger.Error("failed to list bulk check jobs", zap.String("user_id", userID), zap.Error(err))
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(jobs)
+}
+
+// Status возвращает состояние задания
+func (h *BulkCheckHandler) Status(w http.ResponseWriter, r *http.Request) {
+	userID := auth.GetUserIDFromContext(r.Context())
+	if userID == "" {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	job, err := h.svc.GetJob(r.Context(), userID, chi.URLParam(r, "id"))
+	if err != nil {
+		h.logger.Error("failed to get bulk check job", zap.Error(err))
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	if job == nil {
+		http.Error(w, "Job not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(job)
+}
+
+// Download отдает отчет завершенного задания.
+// Параметр format (csv|xlsx) переопределяет формат, выбранный при загрузке.
+func (h *BulkCheckHandler) Download(w http.ResponseWriter, r *http.Request) {
+	userID := auth.GetUserIDFromContext(r.Context())
+	if userID == "" {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	job, err := h.svc.GetJob(r.Context(), userID, chi.URLParam(r, "id"))
+	if err != nil {
+		h.logger.Error("failed to get bulk check job", zap.Error(err))
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	if job == nil {
+		http.Error(w, "Job not found", http.StatusNotFound)
+		return
+	}
+	if job.Status != model.BulkCheckStatusCompleted {
+		http.Error(w, "Job is not completed yet: "+string(job.Status), http.StatusConflict)
+		return
+	}
+
+	formatName := r.URL.Query().Get("format")
+	if formatName == "" {
+		formatName = job.OutputFormat
+	}
+	format, err := spreadsheet.ParseFormat(formatName)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", format.ContentType())
+	w.Header().Set("Content-Disposition",
+		fmt.Sprintf(`attachment; filename="bulk-check-%s.%s"`, job.ID, format.Extension()))
+
+	if err := h.svc.WriteReport(r.Context(), job, w, format); err != nil {
+		// Заголовки уже отправлены, остается только залогировать
+		h.logger.Error("failed to write bulk check report", zap.String("job_id", job.ID), zap.Error(err))
+	}
+}
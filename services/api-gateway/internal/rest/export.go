@@ -0,0 +1,341 @@
+package rest
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/egrul-system/services/api-gateway/internal/auth"
+	"github.com/egrul-system/services/api-gateway/internal/graph/model"
+	"github.com/egrul-system/services/api-gateway/internal/service"
+	"github.com/egrul-system/services/api-gateway/internal/spreadsheet"
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+// exportFlushEvery как часто (в строках) принудительно отправлять данные клиенту
+const exportFlushEvery = 1000
+
+// ExportHandler обрабатывает потоковые выгрузки списков компаний и ИП
+type ExportHandler struct {
+	svc    *service.ExportService
+	audit  *service.AuditService
+	logger *zap.Logger
+}
+
+// NewExportHandler создает новый обработчик выгрузок; audit может быть nil
+func NewExportHandler(svc *service.ExportService, audit *service.AuditService, logger *zap.Logger) *ExportHandler {
+	return &ExportHandler{
+		svc:    svc,
+		audit:  audit,
+		logger: logger.Named("export_handler"),
+	}
+}
+
+// Routes регистрирует маршруты обработчика
+func (h *ExportHandler) Routes(r chi.Router) {
+	r.Get("/export/companies", h.ExportCompanies)
+	r.Get("/export/entrepreneurs", h.ExportEntrepreneurs)
+}
+
+// ExportCompanies выгружает компании. Фильтр передается query-параметрами с именами
+// полей CompanyFilter, колонки — параметром columns через запятую.
+func (h *ExportHandler) ExportCompanies(w http.ResponseWriter, r *http.Request) {
+	req, ok := h.parseRequest(w, r)
+	if !ok {
+		return
+	}
+
+	filter, err := parseCompanyFilterQuery(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	plan, err := h.svc.PlanCompanies(r.Context(), req)
+	if !h.checkPlan(w, err) {
+		return
+	}
+
+	out := h.prepareStream(w, plan, "companies")
+	rows, err := h.svc.ExportCompanies(r.Context(), plan, filter, out)
+	h.recordExport(r, "companies", plan, rows, err)
+	if err != nil {
+		h.handleStreamError(w, out, err)
+	}
+}
+
+// ExportEntrepreneurs выгружает ИП. Фильтр передается query-параметрами с именами
+// полей EntrepreneurFilter, колонки — параметром columns через запятую.
+func (h *ExportHandler) ExportEntrepreneurs(w http.ResponseWriter, r *http.Request) {
+	req, ok := h.parseRequest(w, r)
+	if !ok {
+		return
+	}
+
+	filter, err := parseEntrepreneurFilterQuery(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	plan, err := h.svc.PlanEntrepreneurs(r.Context(), req)
+	if !h.checkPlan(w, err) {
+		return
+	}
+
+	out := h.prepareStream(w, plan, "entrepreneurs")
+	rows, err := h.svc.ExportEntrepreneurs(r.Context(), plan, filter, out)
+	h.recordExport(r, "entrepreneurs", plan, rows, err)
+	if err != nil {
+		h.handleStreamError(w, out, err)
+	}
+}
+
+// recordExport записывает выгрузку в журнал аудита вместе с параметрами запроса
+func (h *ExportHandler) recordExport(r *http.Request, list string, plan *service.ExportPlan, rows int, err error) {
+	h.audit.Record(r.Context(), service.AuditEvent{
+		Action:     model.AuditActionExport,
+		EntityType: service.AuditEntityExport,
+		EntityID:   list,
+		Success:    err == nil,
+		Details: map[string]interface{}{
+			"format": plan.Request.Format,
+			"rows":   rows,
+			"query":  r.URL.RawQuery,
+		},
+	})
+}
+
+func (h *ExportHandler) parseRequest(w http.ResponseWriter, r *http.Request) (service.ExportRequest, bool) {
+	userID := auth.GetUserIDFromContext(r.Context())
+	if userID == "" {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return service.ExportRequest{}, false
+	}
+
+	q := r.URL.Query()
+	format, err := spreadsheet.ParseFormat(q.Get("format"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return service.ExportRequest{}, false
+	}
+
+	limit := 0
+	if v := q.Get("limit"); v != "" {
+		limit, err = strconv.Atoi(v)
+		if err != nil || limit < 0 {
+			http.Error(w, "invalid limit", http.StatusBadRequest)
+			return service.ExportRequest{}, false
+		}
+	}
+
+	return service.ExportRequest{
+		UserID:  userID,
+		Columns: splitList(q.Get("columns")),
+		Format:  format,
+		Limit:   limit,
+	}, true
+}
+
+func (h *ExportHandler) checkPlan(w http.ResponseWriter, err error) bool {
+	if err == nil {
+		return true
+	}
+	if errors.Is(err, service.ErrExportQuotaExceeded) {
+		http.Error(w, err.Error(), http.StatusTooManyRequests)
+		return false
+	}
+	if errors.Is(err, service.ErrExportQuotaUnavailable) {
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return false
+	}
+	http.Error(w, err.Error(), http.StatusBadRequest)
+	return false
+}
+
+// prepareStream выставляет заголовки ответа и снимает write timeout сервера,
+// так как выгрузка может длиться дольше обычного запроса
+func (h *ExportHandler) prepareStream(w http.ResponseWriter, plan *service.ExportPlan, name string) *streamWriter {
+	rc := http.NewResponseController(w)
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil {
+		h.logger.Debug("cannot reset write deadline for export", zap.Error(err))
+	}
+
+	filename := fmt.Sprintf("%s-%s.%s", name, time.Now().Format("20060102-150405"), plan.Request.Format.Extension())
+	w.Header().Set("Content-Type", plan.Request.Format.ContentType())
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	w.Header().Set("X-Export-Row-Limit", strconv.Itoa(plan.Limit))
+
+	return &streamWriter{w: w, rc: rc}
+}
+
+func (h *ExportHandler) handleStreamError(w http.ResponseWriter, out *streamWriter, err error) {
+	if out.written == 0 {
+		http.Error(w, "Export failed", http.StatusInternalServerError)
+		return
+	}
+	// Часть файла уже отправлена, статус изменить нельзя — клиент получит обрезанный файл
+	h.logger.Error("export interrupted", zap.Int64("bytes_written", out.written), zap.Error(err))
+}
+
+// streamWriter периодически сбрасывает буферы ответа, чтобы данные уходили клиенту по мере чтения
+type streamWriter struct {
+	w       http.ResponseWriter
+	rc      *http.ResponseController
+	written int64
+	writes  int
+}
+
+func (s *streamWriter) Write(p []byte) (int, error) {
+	n, err := s.w.Write(p)
+	s.written += int64(n)
+	s.writes++
+	if s.writes%exportFlushEvery == 0 {
+		_ = s.rc.Flush()
+	}
+	return n, err
+}
+
+func splitList(s string) []string {
+	if s == "" {
+		return nil
+	}
+	parts := strings.Split(s, ",")
+	result := make([]string, 0, len(parts))
+	for _, p := range parts {
+		if p = strings.TrimSpace(p); p != "" {
+			result = append(result, p)
+		}
+	}
+	return result
+}
+
+// queryParser разбирает типизированные query-параметры, накапливая первую ошибку
+type queryParser struct {
+	q   url.Values
+	err error
+}
+
+func (p *queryParser) str(name string) *string {
+	if v := strings.TrimSpace(p.q.Get(name)); v != "" {
+		return &v
+	}
+	return nil
+}
+
+func (p *queryParser) boolean(name string) *bool {
+	v := p.q.Get(name)
+	if v == "" {
+		return nil
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil && p.err == nil {
+		p.err = fmt.Errorf("invalid %s: %s", name, v)
+	}
+	return &b
+}
+
+func (p *queryParser) float(name string) *float64 {
+	v := p.q.Get(name)
+	if v == "" {
+		return nil
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil && p.err == nil {
+		p.err = fmt.Errorf("invalid %s: %s", name, v)
+	}
+	return &f
+}
+
+func (p *queryParser) date(name string) *model.Date {
+	v := p.q.Get(name)
+	if v == "" {
+		return nil
+	}
+	t, err := time.Parse("2006-01-02", v)
+	if err != nil && p.err == nil {
+		p.err = fmt.Errorf("invalid %s (expected YYYY-MM-DD): %s", name, v)
+	}
+	return &model.Date{Time: t}
+}
+
+func (p *queryParser) status(name string) *model.EntityStatus {
+	v := p.q.Get(name)
+	if v == "" {
+		return nil
+	}
+	s := model.EntityStatus(strings.ToUpper(v))
+	if !s.IsValid() && p.err == nil {
+		p.err = fmt.Errorf("invalid %s: %s", name, v)
+	}
+	return &s
+}
+
+func (p *queryParser) statuses(name string) []model.EntityStatus {
+	var result []model.EntityStatus
+	for _, v := range splitList(p.q.Get(name)) {
+		s := model.EntityStatus(strings.ToUpper(v))
+		if !s.IsValid() && p.err == nil {
+			p.err = fmt.Errorf("invalid %s: %s", name, v)
+		}
+		result = append(result, s)
+	}
+	return result
+}
+
+func parseCompanyFilterQuery(q url.Values) (*model.CompanyFilter, error) {
+	p := &queryParser{q: q}
+	filter := &model.CompanyFilter{
+		Inn:              p.str("inn"),
+		Ogrn:             p.str("ogrn"),
+		Name:             p.str("name"),
+		RegionCode:       p.str("regionCode"),
+		Region:           p.str("region"),
+		Okved:            p.str("okved"),
+		Status:           p.status("status"),
+		StatusIn:         p.statuses("statusIn"),
+		StatusCode:       p.str("statusCode"),
+		StatusCodeIn:     splitList(q.Get("statusCodeIn")),
+		RegisteredAfter:  p.date("registeredAfter"),
+		RegisteredBefore: p.date("registeredBefore"),
+		TerminatedAfter:  p.date("terminatedAfter"),
+		TerminatedBefore: p.date("terminatedBefore"),
+		CapitalMin:       p.float("capitalMin"),
+		CapitalMax:       p.float("capitalMax"),
+		OpfCode:          p.str("opfCode"),
+		IsBankrupt:       p.boolean("isBankrupt"),
+		IsLiquidating:    p.boolean("isLiquidating"),
+		HasDirector:      p.boolean("hasDirector"),
+		FounderName:      p.str("founderName"),
+	}
+	return filter, p.err
+}
+
+func parseEntrepreneurFilterQuery(q url.Values) (*model.EntrepreneurFilter, error) {
+	p := &queryParser{q: q}
+	filter := &model.EntrepreneurFilter{
+		Inn:              p.str("inn"),
+		Ogrnip:           p.str("ogrnip"),
+		Name:             p.str("name"),
+		LastName:         p.str("lastName"),
+		FirstName:        p.str("firstName"),
+		RegionCode:       p.str("regionCode"),
+		Region:           p.str("region"),
+		Okved:            p.str("okved"),
+		Status:           p.status("status"),
+		StatusIn:         p.statuses("statusIn"),
+		StatusCode:       p.str("statusCode"),
+		StatusCodeIn:     splitList(q.Get("statusCodeIn")),
+		RegisteredAfter:  p.date("registeredAfter"),
+		RegisteredBefore: p.date("registeredBefore"),
+		TerminatedAfter:  p.date("terminatedAfter"),
+		TerminatedBefore: p.date("terminatedBefore"),
+		IsBankrupt:       p.boolean("isBankrupt"),
+	}
+	return filter, p.err
+}
@@ -0,0 +1,107 @@
+package rest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/egrul-system/services/api-gateway/internal/auth"
+	"github.com/egrul-system/services/api-gateway/internal/service"
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+// FavoriteImportHandler обрабатывает импорт списков контрагентов в избранное
+type FavoriteImportHandler struct {
+	svc         *service.FavoriteService
+	audit       *service.AuditService
+	maxFileSize int64
+	logger      *zap.Logger
+}
+
+// NewFavoriteImportHandler создает новый обработчик импорта избранного; audit может быть nil
+func NewFavoriteImportHandler(svc *service.FavoriteService, audit *service.AuditService, maxFileSize int64, logger *zap.Logger) *FavoriteImportHandler {
+	if maxFileSize <= 0 {
+		maxFileSize = 10 << 20
+	}
+	return &FavoriteImportHandler{
+		svc:         svc,
+		audit:       audit,
+		maxFileSize: maxFileSize,
+		logger:      logger.Named("favorite_import_handler"),
+	}
+}
+
+// Routes регистрирует маршруты обработчика
+func (h *FavoriteImportHandler) Routes(r chi.Router) {
+	r.Post("/favorites/import", h.Import)
+}
+
+// Import принимает multipart файл CSV/XLSX (поле "file") со списком ИНН/ОГРН/ОГРНИП
+// и добавляет найденных контрагентов в личное избранное. Поля формы: folder,
+// tags (через запятую), subscribe (true - подписаться на изменения).
+func (h *FavoriteImportHandler) Import(w http.ResponseWriter, r *http.Request) {
+	userID := auth.GetUserIDFromContext(r.Context())
+	if userID == "" {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, h.maxFileSize+1<<20)
+	if err := r.ParseMultipartForm(h.maxFileSize); err != nil {
+		http.Error(w, "Invalid multipart form: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		http.Error(w, "Missing file", http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, h.maxFileSize+1))
+	if err != nil {
+		http.Error(w, "Failed to read file", http.StatusBadRequest)
+		return
+	}
+	if int64(len(data)) > h.maxFileSize {
+		http.Error(w, fmt.Sprintf("File is too large (max %d bytes)", h.maxFileSize), http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	opts := service.FavoriteImportOptions{}
+	if folder := r.FormValue("folder"); folder != "" {
+		opts.Folder = &folder
+	}
+	if tags := r.FormValue("tags"); tags != "" {
+		opts.Tags = strings.Split(tags, ",")
+	}
+	if subscribe := r.FormValue("subscribe"); subscribe != "" {
+		opts.Subscribe, err = strconv.ParseBool(subscribe)
+		if err != nil {
+			http.Error(w, "subscribe must be true or false", http.StatusBadRequest)
+			return
+		}
+	}
+
+	result, err := h.svc.ImportFile(r.Context(), userID, data, opts)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidFavoriteParams) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		h.logger.Error("failed to import favorites", zap.String("user_id", userID), zap.Error(err))
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	h.audit.Record(r.Context(), service.AuditFavoriteImport(result, "file"))
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
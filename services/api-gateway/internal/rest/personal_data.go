@@ -0,0 +1,93 @@
+package rest
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/egrul-system/services/api-gateway/internal/auth"
+	"github.com/egrul-system/services/api-gateway/internal/service"
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+// PersonalDataHandler обрабатывает выгрузку пользователем своих данных
+type PersonalDataHandler struct {
+	svc    *service.PersonalDataService
+	audit  *service.AuditService
+	logger *zap.Logger
+}
+
+// NewPersonalDataHandler создает новый обработчик выгрузки персональных данных; audit может быть nil
+func NewPersonalDataHandler(svc *service.PersonalDataService, audit *service.AuditService, logger *zap.Logger) *PersonalDataHandler {
+	return &PersonalDataHandler{
+		svc:    svc,
+		audit:  audit,
+		logger: logger.Named("personal_data_handler"),
+	}
+}
+
+// Routes регистрирует маршруты обработчика
+func (h *PersonalDataHandler) Routes(r chi.Router) {
+	r.Get("/account/export", h.Export)
+}
+
+// Export отдает данные пользователя файлом: format=zip (по умолчанию) - архив
+// с JSON файлом на раздел, format=json - один JSON документ. Недоступно по API ключу.
+func (h *PersonalDataHandler) Export(w http.ResponseWriter, r *http.Request) {
+	userID := auth.GetUserIDFromContext(r.Context())
+	if userID == "" {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	if auth.GetAPIKeyIDFromContext(r.Context()) != "" {
+		http.Error(w, "Personal data cannot be exported with an API key", http.StatusForbidden)
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = "zip"
+	}
+	if format != "zip" && format != "json" {
+		http.Error(w, "format must be zip or json", http.StatusBadRequest)
+		return
+	}
+
+	export, err := h.svc.Export(r.Context(), userID)
+	if err != nil {
+		if errors.Is(err, service.ErrUserNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		h.logger.Error("failed to export personal data", zap.Error(err), zap.String("user_id", userID))
+		http.Error(w, "Failed to export personal data", http.StatusInternalServerError)
+		return
+	}
+
+	// Выгрузка собирается целиком до ответа, чтобы ошибка не оборвала файл
+	var buf bytes.Buffer
+	contentType := "application/zip"
+	if format == "json" {
+		contentType = "application/json"
+		err = export.WriteJSON(&buf)
+	} else {
+		err = export.WriteZIP(&buf)
+	}
+	if err != nil {
+		h.logger.Error("failed to write personal data export", zap.Error(err), zap.String("user_id", userID))
+		http.Error(w, "Failed to export personal data", http.StatusInternalServerError)
+		return
+	}
+
+	h.audit.Record(r.Context(), service.AuditPersonalDataExport(format))
+
+	filename := fmt.Sprintf("personal-data-%s.%s", export.GeneratedAt.Format("20060102-150405"), format)
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	w.Header().Set("Cache-Control", "no-store")
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		h.logger.Warn("failed to send personal data export", zap.Error(err), zap.String("user_id", userID))
+	}
+}
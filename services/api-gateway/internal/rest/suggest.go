@@ -0,0 +1,73 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/egrul-system/services/api-gateway/internal/graph/model"
+	"github.com/egrul-system/services/api-gateway/internal/service"
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+// SuggestHandler отдает подсказки поисковой строки (REST-аналог GraphQL suggest)
+type SuggestHandler struct {
+	svc    *service.SearchService
+	logger *zap.Logger
+}
+
+// NewSuggestHandler создает новый обработчик подсказок
+func NewSuggestHandler(svc *service.SearchService, logger *zap.Logger) *SuggestHandler {
+	return &SuggestHandler{
+		svc:    svc,
+		logger: logger.Named("suggest_handler"),
+	}
+}
+
+// Routes регистрирует маршруты обработчика
+func (h *SuggestHandler) Routes(r chi.Router) {
+	r.Get("/suggest", h.Suggest)
+}
+
+// Suggest возвращает подсказки. Параметры: q - префикс, types - COMPANY и/или
+// ENTREPRENEUR через запятую, limit - число подсказок.
+func (h *SuggestHandler) Suggest(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	prefix := q.Get("q")
+	if strings.TrimSpace(prefix) == "" {
+		http.Error(w, "query parameter 'q' is required", http.StatusBadRequest)
+		return
+	}
+
+	var types []model.EntityType
+	for _, t := range splitList(q.Get("types")) {
+		entityType := model.EntityType(strings.ToUpper(t))
+		if entityType != model.EntityTypeCompany && entityType != model.EntityTypeEntrepreneur {
+			http.Error(w, "invalid types: "+t, http.StatusBadRequest)
+			return
+		}
+		types = append(types, entityType)
+	}
+
+	limit := 0
+	if v := q.Get("limit"); v != "" {
+		l, err := strconv.Atoi(v)
+		if err != nil {
+			http.Error(w, "invalid limit", http.StatusBadRequest)
+			return
+		}
+		limit = l
+	}
+
+	suggestions, err := h.svc.Suggest(r.Context(), prefix, types, limit)
+	if err != nil {
+		h.logger.Error("failed to get suggestions", zap.String("prefix", prefix), zap.Error(err))
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(suggestions)
+}
@@ -0,0 +1,262 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/egrul-system/services/api-gateway/internal/auth"
+	"github.com/egrul-system/services/api-gateway/internal/config"
+	"github.com/egrul-system/services/api-gateway/internal/notifications"
+	"github.com/egrul-system/services/api-gateway/internal/repository/postgresql"
+	"go.uber.org/zap"
+)
+
+var (
+	// ErrInvalidAccountToken токен подтверждения или сброса не найден либо истек
+	ErrInvalidAccountToken = errors.New("token is invalid or expired")
+	// ErrEmailAlreadyVerified email пользователя уже подтвержден
+	ErrEmailAlreadyVerified = errors.New("email is already verified")
+	// ErrWrongPassword текущий пароль указан неверно
+	ErrWrongPassword = errors.New("current password is incorrect")
+	// ErrUserNotFound пользователь не найден или отключен
+	ErrUserNotFound = errors.New("user not found")
+)
+
+// secretTokenBytes длина случайных токенов (ссылки из писем, refresh токены) в байтах
+const secretTokenBytes = 32
+
+// AccountService подтверждение email, сброс и смена пароля. В БД хранится
+// только SHA-256 хеш токена, сам токен уходит пользователю в письме.
+type AccountService struct {
+	userRepo *postgresql.UserRepository
+	sessions *SessionService
+	producer *notifications.AccountEmailProducer
+	cfg      config.AuthConfig
+	logger   *zap.Logger
+}
+
+// NewAccountService создает новый сервис учетных записей
+func NewAccountService(
+	userRepo *postgresql.UserRepository,
+	sessions *SessionService,
+	producer *notifications.AccountEmailProducer,
+	cfg config.AuthConfig,
+	logger *zap.Logger,
+) *AccountService {
+	if cfg.EmailVerificationTTL <= 0 {
+		cfg.EmailVerificationTTL = 24 * time.Hour
+	}
+	if cfg.PasswordResetTTL <= 0 {
+		cfg.PasswordResetTTL = time.Hour
+	}
+
+	return &AccountService{
+		userRepo: userRepo,
+		sessions: sessions,
+		producer: producer,
+		cfg:      cfg,
+		logger:   logger.Named("account_service"),
+	}
+}
+
+// RequestEmailVerification выпускает новый токен подтверждения email и отправляет
+// письмо; предыдущий токен перестает действовать
+func (s *AccountService) RequestEmailVerification(ctx context.Context, userID string) error {
+	user, err := s.activeUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user.EmailVerified {
+		return ErrEmailAlreadyVerified
+	}
+
+	token, tokenHash, err := newSecretToken()
+	if err != nil {
+		return err
+	}
+	expiresAt := time.Now().Add(s.cfg.EmailVerificationTTL)
+
+	user.EmailVerificationToken = &tokenHash
+	user.EmailVerificationExpires = &expiresAt
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return err
+	}
+
+	return s.sendEmail(ctx, notifications.AccountEmailVerification, user, token, expiresAt)
+}
+
+// VerifyEmail подтверждает email по токену из письма
+func (s *AccountService) VerifyEmail(ctx context.Context, token string) error {
+	user, err := s.userRepo.GetByEmailVerificationToken(ctx, hashSecretToken(token))
+	if err != nil {
+		return err
+	}
+	if user == nil || !user.IsActive || tokenExpired(user.EmailVerificationExpires) {
+		return ErrInvalidAccountToken
+	}
+
+	user.EmailVerified = true
+	user.EmailVerificationToken = nil
+	user.EmailVerificationExpires = nil
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return err
+	}
+
+	s.logger.Info("email verified", zap.String("user_id", user.ID))
+	return nil
+}
+
+// RequestPasswordReset отправляет письмо со ссылкой сброса пароля. Для неизвестного
+// адреса ошибка не возвращается, чтобы по ответу нельзя было перебирать аккаунты.
+func (s *AccountService) RequestPasswordReset(ctx context.Context, email string) error {
+	user, err := s.userRepo.GetByEmail(ctx, email)
+	if err != nil {
+		return err
+	}
+	if user == nil || !user.IsActive {
+		s.logger.Info("password reset requested for unknown or disabled account")
+		return nil
+	}
+
+	token, tokenHash, err := newSecretToken()
+	if err != nil {
+		return err
+	}
+	expiresAt := time.Now().Add(s.cfg.PasswordResetTTL)
+
+	user.PasswordResetToken = &tokenHash
+	user.PasswordResetExpires = &expiresAt
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return err
+	}
+
+	return s.sendEmail(ctx, notifications.AccountEmailPasswordReset, user, token, expiresAt)
+}
+
+// ResetPassword задает новый пароль по токену из письма. Переход по ссылке из
+// письма подтверждает владение адресом, поэтому email заодно считается
+// подтвержденным: так перенесенные пользователи без пароля получают доступ.
+// Все сессии пользователя отзываются.
+func (s *AccountService) ResetPassword(ctx context.Context, token, newPassword string) error {
+	if err := auth.ValidatePassword(newPassword); err != nil {
+		return err
+	}
+
+	user, err := s.userRepo.GetByPasswordResetToken(ctx, hashSecretToken(token))
+	if err != nil {
+		return err
+	}
+	if user == nil || !user.IsActive || tokenExpired(user.PasswordResetExpires) {
+		return ErrInvalidAccountToken
+	}
+
+	passwordHash, err := auth.HashPassword(newPassword)
+	if err != nil {
+		return fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	if !user.EmailVerified {
+		user.EmailVerified = true
+		user.EmailVerificationToken = nil
+		user.EmailVerificationExpires = nil
+		if err := s.userRepo.Update(ctx, user); err != nil {
+			return err
+		}
+	}
+	// UpdatePassword также сбрасывает токен, повторно ссылкой воспользоваться нельзя
+	if err := s.userRepo.UpdatePassword(ctx, user.ID, passwordHash); err != nil {
+		return err
+	}
+
+	if err := s.sessions.RevokeAll(ctx, user.ID, "", postgresql.SessionRevokedPasswordChanged); err != nil {
+		s.logger.Error("failed to revoke sessions after password reset", zap.Error(err), zap.String("user_id", user.ID))
+	}
+
+	s.logger.Info("password reset", zap.String("user_id", user.ID))
+	return nil
+}
+
+// ChangePassword меняет пароль авторизованного пользователя после проверки текущего
+// и завершает все его сессии, кроме текущей
+func (s *AccountService) ChangePassword(ctx context.Context, userID, sessionID, currentPassword, newPassword string) error {
+	user, err := s.activeUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !auth.CheckPassword(currentPassword, user.PasswordHash) {
+		return ErrWrongPassword
+	}
+	if err := auth.ValidatePassword(newPassword); err != nil {
+		return err
+	}
+
+	passwordHash, err := auth.HashPassword(newPassword)
+	if err != nil {
+		return fmt.Errorf("failed to hash password: %w", err)
+	}
+	if err := s.userRepo.UpdatePassword(ctx, user.ID, passwordHash); err != nil {
+		return err
+	}
+
+	if err := s.sessions.RevokeAll(ctx, user.ID, sessionID, postgresql.SessionRevokedPasswordChanged); err != nil {
+		s.logger.Error("failed to revoke sessions after password change", zap.Error(err), zap.String("user_id", user.ID))
+	}
+
+	s.logger.Info("password changed", zap.String("user_id", user.ID))
+	return nil
+}
+
+func (s *AccountService) activeUser(ctx context.Context, userID string) (*postgresql.User, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil || !user.IsActive {
+		return nil, ErrUserNotFound
+	}
+	return user, nil
+}
+
+func (s *AccountService) sendEmail(ctx context.Context, emailType string, user *postgresql.User, token string, expiresAt time.Time) error {
+	err := s.producer.Send(ctx, &notifications.AccountEmailEvent{
+		Type:      emailType,
+		Email:     user.Email,
+		FirstName: user.FirstName,
+		Token:     token,
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		s.logger.Error("failed to send account email",
+			zap.Error(err),
+			zap.String("type", emailType),
+			zap.String("user_id", user.ID),
+		)
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	return nil
+}
+
+// newSecretToken генерирует случайный токен для клиента и его хеш для БД
+func newSecretToken() (token, tokenHash string, err error) {
+	buf := make([]byte, secretTokenBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", fmt.Errorf("failed to generate token: %w", err)
+	}
+	token = hex.EncodeToString(buf)
+	return token, hashSecretToken(token), nil
+}
+
+// hashSecretToken хеш токена, под которым он хранится в БД
+func hashSecretToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func tokenExpired(expiresAt *time.Time) bool {
+	return expiresAt == nil || time.Now().After(*expiresAt)
+}
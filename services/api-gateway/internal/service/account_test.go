@@ -0,0 +1,32 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSecretToken_StoresOnlyHash(t *testing.T) {
+	// Act
+	token, tokenHash, err := newSecretToken()
+	require.NoError(t, err)
+	other, _, err := newSecretToken()
+	require.NoError(t, err)
+
+	// Assert: в письмо уходит сам токен, в БД - его хеш, по которому токен ищется
+	assert.Len(t, token, secretTokenBytes*2)
+	assert.NotEqual(t, token, tokenHash)
+	assert.Equal(t, tokenHash, hashSecretToken(token))
+	assert.NotEqual(t, token, other)
+}
+
+func TestTokenExpired(t *testing.T) {
+	past := time.Now().Add(-time.Minute)
+	future := time.Now().Add(time.Minute)
+
+	assert.True(t, tokenExpired(nil))
+	assert.True(t, tokenExpired(&past))
+	assert.False(t, tokenExpired(&future))
+}
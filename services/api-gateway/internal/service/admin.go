@@ -0,0 +1,183 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/egrul-system/services/api-gateway/internal/auth"
+	"github.com/egrul-system/services/api-gateway/internal/notifications"
+	"github.com/egrul-system/services/api-gateway/internal/repository/postgresql"
+	"go.uber.org/zap"
+)
+
+var (
+	// ErrInvalidRole неизвестная роль
+	ErrInvalidRole = errors.New("invalid role")
+	// ErrCannotModifySelf администратор пытается понизить или отключить сам себя
+	ErrCannotModifySelf = errors.New("administrators cannot change their own role or deactivate themselves")
+)
+
+const (
+	defaultUserListLimit = 50
+	maxUserListLimit     = 200
+
+	// notificationStatsWindow период статистики уведомлений
+	notificationStatsWindow = 30 * 24 * time.Hour
+)
+
+// SystemStats статистика по всей системе для администраторов
+type SystemStats struct {
+	UsersByRole        map[string]int
+	Subscriptions      []postgresql.SubscriptionStats
+	Notifications      []postgresql.NotificationStats
+	NotificationsSince time.Time
+	// NotificationHub nil, если Notification Hub выключен
+	NotificationHub *notifications.HubStats
+}
+
+// AdminService администрирование пользователей и системная статистика.
+// Права проверяются на уровне GraphQL (@hasRole) и HTTP (auth.RequireRole).
+type AdminService struct {
+	userRepo         *postgresql.UserRepository
+	subscriptionRepo *postgresql.SubscriptionRepository
+	sessions         *SessionService
+	hub              *notifications.Hub
+	logger           *zap.Logger
+}
+
+// NewAdminService создает новый сервис администрирования; hub может быть nil
+func NewAdminService(
+	userRepo *postgresql.UserRepository,
+	subscriptionRepo *postgresql.SubscriptionRepository,
+	sessions *SessionService,
+	hub *notifications.Hub,
+	logger *zap.Logger,
+) *AdminService {
+	return &AdminService{
+		userRepo:         userRepo,
+		subscriptionRepo: subscriptionRepo,
+		sessions:         sessions,
+		hub:              hub,
+		logger:           logger.Named("admin_service"),
+	}
+}
+
+// ListUsers страница пользователей по фильтру и общее число найденных
+func (s *AdminService) ListUsers(ctx context.Context, filter postgresql.UserListFilter, limit, offset int) ([]*postgresql.User, int, error) {
+	if filter.Role != "" && !auth.IsValidRole(filter.Role) {
+		return nil, 0, ErrInvalidRole
+	}
+	if limit <= 0 {
+		limit = defaultUserListLimit
+	}
+	if limit > maxUserListLimit {
+		limit = maxUserListLimit
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	return s.userRepo.List(ctx, filter, limit, offset)
+}
+
+// SetUserRole меняет роль пользователя. Сессии пользователя отзываются, чтобы
+// новая роль попала в токены при следующем входе.
+func (s *AdminService) SetUserRole(ctx context.Context, adminID, userID, role string) (*postgresql.User, error) {
+	if !auth.IsValidRole(role) {
+		return nil, ErrInvalidRole
+	}
+	if adminID == userID {
+		return nil, ErrCannotModifySelf
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+	if user.Role == role {
+		return user, nil
+	}
+
+	if err := s.userRepo.UpdateRole(ctx, userID, role); err != nil {
+		return nil, err
+	}
+	if err := s.sessions.RevokeAll(ctx, userID, "", postgresql.SessionRevokedRoleChanged); err != nil {
+		s.logger.Error("failed to revoke sessions after role change", zap.Error(err), zap.String("user_id", userID))
+	}
+
+	s.logger.Info("user role changed",
+		zap.String("admin_id", adminID),
+		zap.String("user_id", userID),
+		zap.String("old_role", user.Role),
+		zap.String("new_role", role),
+	)
+
+	user.Role = role
+	return user, nil
+}
+
+// DeactivateUser отключает учетную запись и завершает все ее сессии
+func (s *AdminService) DeactivateUser(ctx context.Context, adminID, userID string) (*postgresql.User, error) {
+	if adminID == userID {
+		return nil, ErrCannotModifySelf
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+	if !user.IsActive {
+		return user, nil
+	}
+
+	if err := s.userRepo.Delete(ctx, userID); err != nil {
+		return nil, err
+	}
+	if err := s.sessions.RevokeAll(ctx, userID, "", postgresql.SessionRevokedUserDeactivated); err != nil {
+		s.logger.Error("failed to revoke sessions after deactivation", zap.Error(err), zap.String("user_id", userID))
+	}
+
+	s.logger.Info("user deactivated", zap.String("admin_id", adminID), zap.String("user_id", userID))
+
+	user.IsActive = false
+	return user, nil
+}
+
+// SystemStats статистика пользователей, подписок и уведомлений
+func (s *AdminService) SystemStats(ctx context.Context) (*SystemStats, error) {
+	usersByRole, err := s.userRepo.CountByRole(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	subscriptions, err := s.subscriptionRepo.GetSubscriptionStats(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	since := time.Now().Add(-notificationStatsWindow)
+	notificationStats, err := s.subscriptionRepo.GetNotificationStats(ctx, since)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &SystemStats{
+		UsersByRole:        usersByRole,
+		Subscriptions:      subscriptions,
+		Notifications:      notificationStats,
+		NotificationsSince: since,
+	}
+	if s.hub != nil {
+		hubStats := s.hub.Stats()
+		stats.NotificationHub = &hubStats
+	}
+
+	return stats, nil
+}
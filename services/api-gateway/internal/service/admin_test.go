@@ -0,0 +1,29 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/egrul-system/services/api-gateway/internal/repository/postgresql"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestAdminService_RejectsInvalidChangesBeforeQueryingDB(t *testing.T) {
+	// Arrange: без репозиториев - проверки должны срабатывать до обращения к БД
+	svc := NewAdminService(nil, nil, nil, nil, zap.NewNop())
+	ctx := context.Background()
+
+	// Act & Assert
+	_, err := svc.SetUserRole(ctx, "admin-1", "user-1", "superuser")
+	assert.ErrorIs(t, err, ErrInvalidRole)
+
+	_, err = svc.SetUserRole(ctx, "admin-1", "admin-1", "user")
+	assert.ErrorIs(t, err, ErrCannotModifySelf)
+
+	_, err = svc.DeactivateUser(ctx, "admin-1", "admin-1")
+	assert.ErrorIs(t, err, ErrCannotModifySelf)
+
+	_, _, err = svc.ListUsers(ctx, postgresql.UserListFilter{Role: "root"}, 10, 0)
+	assert.ErrorIs(t, err, ErrInvalidRole)
+}
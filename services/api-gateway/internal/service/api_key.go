@@ -0,0 +1,370 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/egrul-system/services/api-gateway/internal/auth"
+	"github.com/egrul-system/services/api-gateway/internal/cache"
+	"github.com/egrul-system/services/api-gateway/internal/graph/model"
+	"github.com/egrul-system/services/api-gateway/internal/repository/postgresql"
+	"go.uber.org/zap"
+)
+
+var (
+	// ErrAPIKeyNotFound ключ не найден, уже отозван или принадлежит другому пользователю
+	ErrAPIKeyNotFound = errors.New("api key not found")
+	// ErrTooManyAPIKeys достигнут лимит действующих ключей пользователя
+	ErrTooManyAPIKeys = fmt.Errorf("too many api keys: at most %d active keys per user", maxAPIKeysPerUser)
+	// ErrInvalidAPIKeyParams некорректные параметры нового ключа
+	ErrInvalidAPIKeyParams = errors.New("invalid api key parameters")
+)
+
+const (
+	// apiKeyPrefix префикс ключей: по нему ключ узнается в логах и сканерах секретов
+	apiKeyPrefix = "egrul_"
+	// apiKeyDisplayLength длина начала ключа, которое хранится для отображения
+	apiKeyDisplayLength = len(apiKeyPrefix) + 8
+	maxAPIKeysPerUser   = 20
+	maxAPIKeyNameLength = 100
+
+	// apiKeyUsageFlushInterval период записи счетчиков запросов в БД
+	apiKeyUsageFlushInterval = 30 * time.Second
+	// apiKeyQuotaKeyPrefix префикс ключей Redis для суточных счетчиков квот
+	apiKeyQuotaKeyPrefix = "auth:api_key_quota:"
+)
+
+// CreateAPIKeyParams параметры нового API ключа
+type CreateAPIKeyParams struct {
+	Name        string
+	Scopes      []string
+	IPAllowlist []string
+	DailyQuota  *int
+	ExpiresAt   *time.Time
+}
+
+type apiKeyUsage struct {
+	count      int64
+	lastUsedAt time.Time
+}
+
+// APIKeyService API ключи машинных клиентов. Ключ показывается один раз при
+// создании, в БД хранится его SHA-256 хеш. Счетчики запросов копятся в памяти
+// и записываются в БД пачками, суточные квоты считаются в Redis.
+type APIKeyService struct {
+	repo   *postgresql.APIKeyRepository
+	cache  cache.Cache
+	audit  *AuditService
+	logger *zap.Logger
+
+	mu    sync.Mutex
+	usage map[string]*apiKeyUsage
+}
+
+// NewAPIKeyService создает новый сервис API ключей
+func NewAPIKeyService(repo *postgresql.APIKeyRepository, cache cache.Cache, logger *zap.Logger) *APIKeyService {
+	return &APIKeyService{
+		repo:   repo,
+		cache:  cache,
+		logger: logger.Named("api_key_service"),
+		usage:  make(map[string]*apiKeyUsage),
+	}
+}
+
+// UseAuditLog включает запись использования ключей в журнал аудита
+func (s *APIKeyService) UseAuditLog(audit *AuditService) {
+	s.audit = audit
+}
+
+// Run периодически записывает счетчики запросов в БД до отмены ctx
+func (s *APIKeyService) Run(ctx context.Context) {
+	ticker := time.NewTicker(apiKeyUsageFlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.flushUsage(ctx)
+		case <-ctx.Done():
+			// Последняя запись при остановке
+			flushCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			s.flushUsage(flushCtx)
+			cancel()
+			return
+		}
+	}
+}
+
+// Create выпускает новый ключ и возвращает его вместе с сохраненной записью
+func (s *APIKeyService) Create(ctx context.Context, userID string, params CreateAPIKeyParams) (string, *postgresql.APIKey, error) {
+	key, err := newAPIKeyRecord(userID, params, time.Now())
+	if err != nil {
+		return "", nil, err
+	}
+
+	count, err := s.repo.CountActiveByUserID(ctx, userID)
+	if err != nil {
+		return "", nil, err
+	}
+	if count >= maxAPIKeysPerUser {
+		return "", nil, ErrTooManyAPIKeys
+	}
+
+	secret, _, err := newSecretToken()
+	if err != nil {
+		return "", nil, err
+	}
+	rawKey := apiKeyPrefix + secret
+	key.KeyPrefix = rawKey[:apiKeyDisplayLength]
+
+	if err := s.repo.Create(ctx, key, hashSecretToken(rawKey)); err != nil {
+		return "", nil, err
+	}
+
+	return rawKey, key, nil
+}
+
+// List действующие ключи пользователя с учетом еще не записанных в БД запросов
+func (s *APIKeyService) List(ctx context.Context, userID string) ([]*postgresql.APIKey, error) {
+	keys, err := s.repo.GetActiveByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, key := range keys {
+		if pending, ok := s.usage[key.ID]; ok {
+			key.RequestCount += pending.count
+			if key.LastUsedAt == nil || pending.lastUsedAt.After(*key.LastUsedAt) {
+				lastUsedAt := pending.lastUsedAt
+				key.LastUsedAt = &lastUsedAt
+			}
+		}
+	}
+	return keys, nil
+}
+
+// Revoke отзывает ключ пользователя
+func (s *APIKeyService) Revoke(ctx context.Context, userID, id string) error {
+	revoked, err := s.repo.Revoke(ctx, id, userID)
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return ErrAPIKeyNotFound
+	}
+	return nil
+}
+
+// AuthenticateAPIKey реализует auth.APIKeyAuthenticator
+func (s *APIKeyService) AuthenticateAPIKey(ctx context.Context, rawKey string, client auth.ClientInfo) (*auth.APIKeyIdentity, error) {
+	if !strings.HasPrefix(rawKey, apiKeyPrefix) {
+		return nil, auth.ErrInvalidAPIKey
+	}
+
+	key, owner, err := s.repo.GetByHash(ctx, hashSecretToken(rawKey))
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if key == nil || !key.IsActive(now) || !owner.IsActive {
+		return nil, auth.ErrInvalidAPIKey
+	}
+	if !ipAllowed(key.IPAllowlist, client.IPAddress) {
+		s.logger.Warn("api key used from disallowed ip",
+			zap.String("key_id", key.ID),
+			zap.String("ip", client.IPAddress),
+		)
+		s.recordAudit(ctx, key, owner, auth.ErrAPIKeyIPNotAllowed)
+		return nil, auth.ErrAPIKeyIPNotAllowed
+	}
+	if err := s.checkQuota(ctx, key, now); err != nil {
+		s.recordAudit(ctx, key, owner, err)
+		return nil, err
+	}
+
+	s.recordUsage(key.ID, now)
+	s.recordAudit(ctx, key, owner, nil)
+
+	return &auth.APIKeyIdentity{
+		KeyID:  key.ID,
+		UserID: owner.UserID,
+		Email:  owner.Email,
+		Role:   owner.Role,
+		Scopes: key.Scopes,
+	}, nil
+}
+
+// checkQuota учитывает запрос в суточном счетчике ключа. При недоступности
+// Redis запрос пропускается.
+func (s *APIKeyService) checkQuota(ctx context.Context, key *postgresql.APIKey, now time.Time) error {
+	if key.DailyQuota == nil {
+		return nil
+	}
+
+	counterKey := apiKeyQuotaKeyPrefix + key.ID + ":" + now.UTC().Format("2006-01-02")
+	count, err := s.cache.Incr(ctx, counterKey, 25*time.Hour)
+	if err != nil {
+		s.logger.Warn("api key quota check failed", zap.String("key_id", key.ID), zap.Error(err))
+		return nil
+	}
+	if count > int64(*key.DailyQuota) {
+		return auth.ErrAPIKeyQuotaExceeded
+	}
+	return nil
+}
+
+// recordAudit записывает использование ключа (или отказ) в журнал аудита
+func (s *APIKeyService) recordAudit(ctx context.Context, key *postgresql.APIKey, owner *postgresql.APIKeyOwner, err error) {
+	event := AuditEvent{
+		Action:     model.AuditActionAPIKeyUse,
+		UserID:     owner.UserID,
+		UserEmail:  owner.Email,
+		APIKeyID:   key.ID,
+		EntityType: AuditEntityAPIKey,
+		EntityID:   key.ID,
+		Success:    err == nil,
+	}
+	if err != nil {
+		event.Details = map[string]interface{}{"reason": err.Error()}
+	}
+	s.audit.Record(ctx, event)
+}
+
+func (s *APIKeyService) recordUsage(keyID string, now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	u, ok := s.usage[keyID]
+	if !ok {
+		u = &apiKeyUsage{}
+		s.usage[keyID] = u
+	}
+	u.count++
+	u.lastUsedAt = now
+}
+
+func (s *APIKeyService) flushUsage(ctx context.Context) {
+	s.mu.Lock()
+	pending := s.usage
+	s.usage = make(map[string]*apiKeyUsage, len(pending))
+	s.mu.Unlock()
+
+	for keyID, u := range pending {
+		if err := s.repo.AddUsage(ctx, keyID, u.count, u.lastUsedAt); err != nil {
+			s.logger.Error("failed to flush api key usage", zap.String("key_id", keyID), zap.Error(err))
+			// Вернем счетчик, чтобы записать его в следующий раз
+			s.mu.Lock()
+			if cur, ok := s.usage[keyID]; ok {
+				cur.count += u.count
+				if u.lastUsedAt.After(cur.lastUsedAt) {
+					cur.lastUsedAt = u.lastUsedAt
+				}
+			} else {
+				s.usage[keyID] = u
+			}
+			s.mu.Unlock()
+		}
+	}
+}
+
+// newAPIKeyRecord проверяет параметры и собирает запись нового ключа
+func newAPIKeyRecord(userID string, params CreateAPIKeyParams, now time.Time) (*postgresql.APIKey, error) {
+	name := strings.TrimSpace(params.Name)
+	if name == "" || len(name) > maxAPIKeyNameLength {
+		return nil, fmt.Errorf("%w: name must be 1-%d characters", ErrInvalidAPIKeyParams, maxAPIKeyNameLength)
+	}
+
+	if len(params.Scopes) == 0 {
+		return nil, fmt.Errorf("%w: at least one scope is required", ErrInvalidAPIKeyParams)
+	}
+	scopes := make([]string, 0, len(params.Scopes))
+	seen := make(map[string]bool, len(params.Scopes))
+	for _, scope := range params.Scopes {
+		if !auth.IsValidScope(scope) {
+			return nil, fmt.Errorf("%w: unknown scope %q", ErrInvalidAPIKeyParams, scope)
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			scopes = append(scopes, scope)
+		}
+	}
+
+	allowlist, err := normalizeIPAllowlist(params.IPAllowlist)
+	if err != nil {
+		return nil, err
+	}
+
+	if params.DailyQuota != nil && *params.DailyQuota <= 0 {
+		return nil, fmt.Errorf("%w: daily quota must be positive", ErrInvalidAPIKeyParams)
+	}
+	if params.ExpiresAt != nil && !params.ExpiresAt.After(now) {
+		return nil, fmt.Errorf("%w: expiry must be in the future", ErrInvalidAPIKeyParams)
+	}
+
+	return &postgresql.APIKey{
+		UserID:      userID,
+		Name:        name,
+		Scopes:      scopes,
+		IPAllowlist: allowlist,
+		DailyQuota:  params.DailyQuota,
+		ExpiresAt:   params.ExpiresAt,
+	}, nil
+}
+
+// normalizeIPAllowlist проверяет адреса и подсети; одиночный IP сохраняется как есть
+func normalizeIPAllowlist(entries []string) ([]string, error) {
+	result := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if strings.Contains(entry, "/") {
+			_, ipNet, err := net.ParseCIDR(entry)
+			if err != nil {
+				return nil, fmt.Errorf("%w: invalid CIDR %q", ErrInvalidAPIKeyParams, entry)
+			}
+			result = append(result, ipNet.String())
+			continue
+		}
+		ip := net.ParseIP(entry)
+		if ip == nil {
+			return nil, fmt.Errorf("%w: invalid IP address %q", ErrInvalidAPIKeyParams, entry)
+		}
+		result = append(result, ip.String())
+	}
+	return result, nil
+}
+
+// ipAllowed проверяет IP клиента по списку адресов и подсетей; пустой список
+// разрешает любой адрес
+func ipAllowed(allowlist []string, ipAddress string) bool {
+	if len(allowlist) == 0 {
+		return true
+	}
+
+	ip := net.ParseIP(ipAddress)
+	if ip == nil {
+		return false
+	}
+	for _, entry := range allowlist {
+		if _, ipNet, err := net.ParseCIDR(entry); err == nil {
+			if ipNet.Contains(ip) {
+				return true
+			}
+			continue
+		}
+		if allowed := net.ParseIP(entry); allowed != nil && allowed.Equal(ip) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,53 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/egrul-system/services/api-gateway/internal/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIPAllowed(t *testing.T) {
+	allowlist := []string{"10.0.0.0/8", "192.168.1.15", "2001:db8::/32"}
+
+	assert.True(t, ipAllowed(nil, "203.0.113.7"))
+	assert.True(t, ipAllowed(allowlist, "10.20.30.40"))
+	assert.True(t, ipAllowed(allowlist, "192.168.1.15"))
+	assert.True(t, ipAllowed(allowlist, "2001:db8::1"))
+	assert.False(t, ipAllowed(allowlist, "192.168.1.16"))
+	assert.False(t, ipAllowed(allowlist, ""))
+}
+
+func TestNewAPIKeyRecord(t *testing.T) {
+	now := time.Now()
+	past := now.Add(-time.Hour)
+	quota := 0
+
+	// Arrange & Act: дубли scopes схлопываются, адреса нормализуются
+	key, err := newAPIKeyRecord("user-1", CreateAPIKeyParams{
+		Name:        "  ETL  ",
+		Scopes:      []string{auth.ScopeReadCompanies, auth.ScopeReadCompanies, auth.ScopeReadStats},
+		IPAllowlist: []string{" 10.1.2.3/8 ", "", "::ffff:192.168.0.1"},
+	}, now)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "ETL", key.Name)
+	assert.Equal(t, []string{auth.ScopeReadCompanies, auth.ScopeReadStats}, key.Scopes)
+	assert.Equal(t, []string{"10.0.0.0/8", "192.168.0.1"}, key.IPAllowlist)
+
+	invalid := []CreateAPIKeyParams{
+		{Name: "", Scopes: []string{auth.ScopeReadStats}},
+		{Name: "no scopes"},
+		{Name: "bad scope", Scopes: []string{"write:everything"}},
+		{Name: "bad ip", Scopes: []string{auth.ScopeReadStats}, IPAllowlist: []string{"10.0.0.300"}},
+		{Name: "bad quota", Scopes: []string{auth.ScopeReadStats}, DailyQuota: &quota},
+		{Name: "expired", Scopes: []string{auth.ScopeReadStats}, ExpiresAt: &past},
+	}
+	for _, params := range invalid {
+		_, err := newAPIKeyRecord("user-1", params, now)
+		assert.ErrorIs(t, err, ErrInvalidAPIKeyParams, params.Name)
+	}
+}
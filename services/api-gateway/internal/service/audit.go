@@ -0,0 +1,296 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/egrul-system/services/api-gateway/internal/auth"
+	"github.com/egrul-system/services/api-gateway/internal/config"
+	"github.com/egrul-system/services/api-gateway/internal/graph/model"
+	"github.com/egrul-system/services/api-gateway/internal/repository/clickhouse"
+	"go.uber.org/zap"
+)
+
+// ErrInvalidAuditFilter возвращается при некорректном фильтре журнала аудита
+var ErrInvalidAuditFilter = errors.New("invalid audit log filter")
+
+const (
+	defaultAuditLogLimit = 50
+	maxAuditLogLimit     = 500
+	// maxAuditFieldLength ограничивает длину строковых полей события (User-Agent и т.п.)
+	maxAuditFieldLength = 512
+)
+
+// Типы объектов журнала аудита
+const (
+	AuditEntityCompany      = "company"
+	AuditEntityEntrepreneur = "entrepreneur"
+	AuditEntitySubscription = "subscription"
+	AuditEntityFavorite     = "favorite"
+	AuditEntityAPIKey       = "api_key"
+	AuditEntityExport       = "export"
+	AuditEntityUser         = "user"
+)
+
+// AuditEvent событие журнала аудита. Пользователь, API ключ и данные клиента
+// берутся из контекста запроса, если не заданы явно.
+type AuditEvent struct {
+	Action     model.AuditAction
+	UserID     string
+	UserEmail  string
+	APIKeyID   string
+	EntityType string
+	EntityID   string
+	Success    bool
+	Details    map[string]interface{}
+}
+
+// AuditService журнал аудита действий пользователей. События копятся в памяти
+// и записываются в ClickHouse пачками фоновым Run; запись события не блокирует
+// запрос, при переполнении буфера событие отбрасывается.
+type AuditService struct {
+	repo   *clickhouse.AuditRepository
+	cfg    config.AuditConfig
+	logger *zap.Logger
+
+	mu      sync.Mutex
+	pending []clickhouse.AuditRecord
+	dropped int
+	flushCh chan struct{}
+}
+
+// NewAuditService создает новый сервис журнала аудита
+func NewAuditService(repo *clickhouse.AuditRepository, cfg config.AuditConfig, logger *zap.Logger) *AuditService {
+	if cfg.BufferSize <= 0 {
+		cfg.BufferSize = 10000
+	}
+	if cfg.BatchSize <= 0 || cfg.BatchSize > cfg.BufferSize {
+		cfg.BatchSize = min(500, cfg.BufferSize)
+	}
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = 5 * time.Second
+	}
+	if cfg.RetentionDays <= 0 {
+		cfg.RetentionDays = 365
+	}
+	return &AuditService{
+		repo:    repo,
+		cfg:     cfg,
+		logger:  logger.Named("audit_service"),
+		flushCh: make(chan struct{}, 1),
+	}
+}
+
+// Run записывает накопленные события по таймеру или при заполнении пачки до отмены ctx
+func (s *AuditService) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.cfg.FlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.flush(ctx)
+		case <-s.flushCh:
+			s.flush(ctx)
+		case <-ctx.Done():
+			// Последняя запись при остановке
+			flushCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			s.flush(flushCtx)
+			cancel()
+			return
+		}
+	}
+}
+
+// Record ставит событие в очередь записи. Безопасно для nil сервиса
+// (журнал выключен).
+func (s *AuditService) Record(ctx context.Context, event AuditEvent) {
+	if s == nil {
+		return
+	}
+
+	record := clickhouse.AuditRecord{
+		Time:          time.Now(),
+		UserID:        event.UserID,
+		UserEmail:     truncateAuditField(event.UserEmail),
+		APIKeyID:      event.APIKeyID,
+		Action:        string(event.Action),
+		EntityType:    event.EntityType,
+		EntityID:      truncateAuditField(event.EntityID),
+		Success:       event.Success,
+		RetentionDays: s.cfg.RetentionDays,
+	}
+	if record.UserID == "" {
+		record.UserID = auth.GetUserIDFromContext(ctx)
+	}
+	if record.UserEmail == "" {
+		record.UserEmail = auth.GetEmailFromContext(ctx)
+	}
+	if record.APIKeyID == "" {
+		record.APIKeyID = auth.GetAPIKeyIDFromContext(ctx)
+	}
+	client := auth.GetClientInfoFromContext(ctx)
+	record.IPAddress = client.IPAddress
+	record.UserAgent = truncateAuditField(client.UserAgent)
+	if len(event.Details) > 0 {
+		if details, err := json.Marshal(event.Details); err == nil {
+			record.Details = string(details)
+		}
+	}
+
+	s.mu.Lock()
+	if len(s.pending) >= s.cfg.BufferSize {
+		s.dropped++
+		s.mu.Unlock()
+		return
+	}
+	s.pending = append(s.pending, record)
+	full := len(s.pending) >= s.cfg.BatchSize
+	s.mu.Unlock()
+
+	if full {
+		select {
+		case s.flushCh <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// AuditFavoriteImport событие пакетного добавления в избранное (список
+// идентификаторов или файл)
+func AuditFavoriteImport(result *model.FavoriteImportResult, source string) AuditEvent {
+	return AuditEvent{
+		Action:     model.AuditActionFavoriteCreate,
+		EntityType: AuditEntityFavorite,
+		Success:    true,
+		Details: map[string]interface{}{
+			"source":     source,
+			"total":      result.Total,
+			"added":      result.Added,
+			"subscribed": result.Subscribed,
+		},
+	}
+}
+
+// AuditPersonalDataExport событие выгрузки пользователем своих данных
+func AuditPersonalDataExport(format string) AuditEvent {
+	return AuditEvent{
+		Action:     model.AuditActionExport,
+		EntityType: AuditEntityExport,
+		EntityID:   "personal_data",
+		Success:    true,
+		Details:    map[string]interface{}{"format": format},
+	}
+}
+
+// List возвращает страницу журнала аудита для администратора
+func (s *AuditService) List(ctx context.Context, filter *model.AuditLogFilter, limit, offset int) (*model.AuditLogList, error) {
+	repoFilter, err := auditRepoFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = defaultAuditLogLimit
+	}
+	if limit > maxAuditLogLimit {
+		limit = maxAuditLogLimit
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	items, total, err := s.repo.List(ctx, repoFilter, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	return &model.AuditLogList{Items: items, Total: total}, nil
+}
+
+// flush записывает накопленные события пачками по BatchSize
+func (s *AuditService) flush(ctx context.Context) {
+	s.mu.Lock()
+	pending := s.pending
+	dropped := s.dropped
+	s.pending = nil
+	s.dropped = 0
+	s.mu.Unlock()
+
+	if dropped > 0 {
+		s.logger.Warn("audit buffer overflow, events dropped", zap.Int("dropped", dropped))
+	}
+
+	for start := 0; start < len(pending); start += s.cfg.BatchSize {
+		end := min(start+s.cfg.BatchSize, len(pending))
+		if err := s.repo.InsertBatch(ctx, pending[start:end]); err != nil {
+			s.logger.Error("failed to write audit log", zap.Int("events", len(pending)-start), zap.Error(err))
+			s.requeue(pending[start:])
+			return
+		}
+	}
+}
+
+// requeue возвращает незаписанные события в начало буфера, чтобы записать их
+// в следующий раз; не поместившиеся в буфер отбрасываются
+func (s *AuditService) requeue(records []clickhouse.AuditRecord) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	room := s.cfg.BufferSize - len(s.pending)
+	if room <= 0 {
+		s.dropped += len(records)
+		return
+	}
+	if len(records) > room {
+		s.dropped += len(records) - room
+		records = records[:room]
+	}
+	s.pending = append(append(make([]clickhouse.AuditRecord, 0, len(records)+len(s.pending)), records...), s.pending...)
+}
+
+// auditRepoFilter проверяет фильтр и переводит даты в полуинтервал [from, to)
+func auditRepoFilter(filter *model.AuditLogFilter) (clickhouse.AuditLogFilter, error) {
+	var result clickhouse.AuditLogFilter
+	if filter == nil {
+		return result, nil
+	}
+
+	if filter.UserID != nil {
+		result.UserID = *filter.UserID
+	}
+	if filter.Action != nil {
+		if !filter.Action.IsValid() {
+			return result, fmt.Errorf("%w: unknown action %s", ErrInvalidAuditFilter, *filter.Action)
+		}
+		result.Action = string(*filter.Action)
+	}
+	if filter.DateFrom != nil {
+		from := filter.DateFrom.Time
+		result.From = &from
+	}
+	if filter.DateTo != nil {
+		// dateTo включительно: до начала следующего дня
+		to := filter.DateTo.Time.AddDate(0, 0, 1)
+		result.To = &to
+	}
+	if result.From != nil && result.To != nil && !result.From.Before(*result.To) {
+		return result, fmt.Errorf("%w: dateFrom must not be after dateTo", ErrInvalidAuditFilter)
+	}
+	return result, nil
+}
+
+// truncateAuditField обрезает строку до maxAuditFieldLength байт по границе символа
+func truncateAuditField(s string) string {
+	if len(s) <= maxAuditFieldLength {
+		return s
+	}
+	cut := maxAuditFieldLength
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut]
+}
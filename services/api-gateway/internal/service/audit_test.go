@@ -0,0 +1,92 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/egrul-system/services/api-gateway/internal/auth"
+	"github.com/egrul-system/services/api-gateway/internal/config"
+	"github.com/egrul-system/services/api-gateway/internal/graph/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestAuditService_RecordTakesIdentityFromContext(t *testing.T) {
+	// Arrange
+	svc := NewAuditService(nil, config.AuditConfig{RetentionDays: 30}, zap.NewNop())
+	ctx := context.WithValue(context.Background(), auth.UserIDKey, "user-1")
+	ctx = context.WithValue(ctx, auth.EmailKey, "user@example.com")
+	ctx = context.WithValue(ctx, auth.APIKeyIDKey, "key-1")
+	ctx = context.WithValue(ctx, auth.ClientInfoKey, auth.ClientInfo{IPAddress: "10.0.0.1", UserAgent: "curl/8.0"})
+
+	// Act
+	svc.Record(ctx, AuditEvent{
+		Action:     model.AuditActionCompanyView,
+		EntityType: AuditEntityCompany,
+		EntityID:   "1027700132195",
+		Success:    true,
+		Details:    map[string]interface{}{"asOf": "2024-01-01"},
+	})
+
+	// Assert
+	require.Len(t, svc.pending, 1)
+	rec := svc.pending[0]
+	assert.Equal(t, "user-1", rec.UserID)
+	assert.Equal(t, "user@example.com", rec.UserEmail)
+	assert.Equal(t, "key-1", rec.APIKeyID)
+	assert.Equal(t, "10.0.0.1", rec.IPAddress)
+	assert.Equal(t, "curl/8.0", rec.UserAgent)
+	assert.Equal(t, "COMPANY_VIEW", rec.Action)
+	assert.Equal(t, `{"asOf":"2024-01-01"}`, rec.Details)
+	assert.Equal(t, 30, rec.RetentionDays)
+}
+
+func TestAuditService_RecordDropsEventsWhenBufferIsFull(t *testing.T) {
+	// Arrange
+	svc := NewAuditService(nil, config.AuditConfig{BufferSize: 2, BatchSize: 2}, zap.NewNop())
+
+	// Act: буфер на 2 события, третье отбрасывается без блокировки
+	for i := 0; i < 3; i++ {
+		svc.Record(context.Background(), AuditEvent{Action: model.AuditActionLoginFailed, UserEmail: "a@example.com"})
+	}
+
+	// Assert
+	assert.Len(t, svc.pending, 2)
+	assert.Equal(t, 1, svc.dropped)
+	assert.Len(t, svc.flushCh, 1, "заполненная пачка должна разбудить запись")
+}
+
+func TestAuditService_NilServiceIgnoresEvents(t *testing.T) {
+	var svc *AuditService
+
+	assert.NotPanics(t, func() {
+		svc.Record(context.Background(), AuditEvent{Action: model.AuditActionLogin})
+	})
+}
+
+func TestAuditService_ListRejectsInvalidFilter(t *testing.T) {
+	// Arrange: без репозитория - проверка должна срабатывать до обращения к ClickHouse
+	svc := NewAuditService(nil, config.AuditConfig{}, zap.NewNop())
+	unknown := model.AuditAction("DROP_TABLE")
+	from := model.NewDate(time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC))
+	to := model.NewDate(time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC))
+
+	// Act & Assert
+	_, err := svc.List(context.Background(), &model.AuditLogFilter{Action: &unknown}, 10, 0)
+	assert.ErrorIs(t, err, ErrInvalidAuditFilter)
+
+	_, err = svc.List(context.Background(), &model.AuditLogFilter{DateFrom: from, DateTo: to}, 10, 0)
+	assert.ErrorIs(t, err, ErrInvalidAuditFilter)
+}
+
+func TestAuditRepoFilter_DateToIsInclusive(t *testing.T) {
+	day := model.NewDate(time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC))
+
+	filter, err := auditRepoFilter(&model.AuditLogFilter{DateFrom: day, DateTo: day})
+
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC), *filter.From)
+	assert.Equal(t, time.Date(2026, 3, 11, 0, 0, 0, 0, time.UTC), *filter.To)
+}
@@ -0,0 +1,547 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/egrul-system/services/api-gateway/internal/config"
+	"github.com/egrul-system/services/api-gateway/internal/graph/model"
+	"github.com/egrul-system/services/api-gateway/internal/repository/postgresql"
+	"github.com/egrul-system/services/api-gateway/internal/spreadsheet"
+	"go.uber.org/zap"
+)
+
+// ErrBulkCheckQueueFull возвращается, когда очередь заданий переполнена
+var ErrBulkCheckQueueFull = errors.New("bulk check queue is full, try again later")
+
+// bulkCheckRowTimeout ограничивает время проверки одного контрагента
+const bulkCheckRowTimeout = 30 * time.Second
+
+// bulkCheckProgressStep как часто (в строках) сохранять прогресс задания
+const bulkCheckProgressStep = 25
+
+// bulkCheckLatestChanges сколько последних записей истории включать в отчет
+const bulkCheckLatestChanges = 3
+
+// bulkCheckLookupFailed ошибка строки отчета, если контрагента не удалось проверить
+const bulkCheckLookupFailed = "lookup failed"
+
+// BulkCheckHeader заголовок отчета пакетной проверки
+var BulkCheckHeader = []string{
+	"Входное значение", "Тип", "Найден", "ОГРН/ОГРНИП", "ИНН", "Наименование",
+	"Статус", "Код статуса", "Ликвидация", "Банкротство", "Реорганизация",
+	"Руководитель", "Дата регистрации", "Возраст (дней)",
+	"Массовый адрес", "Компаний по адресу", "Последние изменения",
+	"Риск (балл)", "Уровень риска", "Факторы риска", "Ошибка",
+}
+
+// BulkCheckService выполняет пакетную проверку контрагентов пулом воркеров
+type BulkCheckService struct {
+	jobRepo             *postgresql.BulkCheckRepository
+	companyService      *CompanyService
+	entrepreneurService *EntrepreneurService
+	riskService         *RiskService
+	cfg                 config.BulkCheckConfig
+	queue               chan string
+	logger              *zap.Logger
+}
+
+// NewBulkCheckService создает новый сервис пакетной проверки
+func NewBulkCheckService(
+	jobRepo *postgresql.BulkCheckRepository,
+	companyService *CompanyService,
+	entrepreneurService *EntrepreneurService,
+	riskService *RiskService,
+	cfg config.BulkCheckConfig,
+	logger *zap.Logger,
+) *BulkCheckService {
+	if cfg.Workers <= 0 {
+		cfg.Workers = 1
+	}
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = 100
+	}
+	if cfg.MassAddressThreshold <= 0 {
+		cfg.MassAddressThreshold = 10
+	}
+	if cfg.StaleJobTimeout <= 0 {
+		cfg.StaleJobTimeout = 30 * time.Minute
+	}
+
+	return &BulkCheckService{
+		jobRepo:             jobRepo,
+		companyService:      companyService,
+		entrepreneurService: entrepreneurService,
+		riskService:         riskService,
+		cfg:                 cfg,
+		queue:               make(chan string, cfg.QueueSize),
+		logger:              logger.Named("bulk_check_service"),
+	}
+}
+
+// Run запускает воркеры и возобновляет незавершенные задания. Блокируется до
+// отмены ctx и завершения воркеров: прерванные задания к этому моменту
+// возвращены в pending.
+func (s *BulkCheckService) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	for i := 0; i < s.cfg.Workers; i++ {
+		wg.Add(1)
+		go func(n int) {
+			defer wg.Done()
+			s.worker(ctx, n)
+		}(i)
+	}
+
+	// Задания в running без отметки прогресса дольше StaleJobTimeout остались
+	// от экземпляра, который упал или был убит до Release
+	reclaimed, err := s.jobRepo.ReclaimStale(ctx, s.cfg.StaleJobTimeout)
+	if err != nil {
+		s.logger.Error("failed to reclaim stale bulk check jobs", zap.Error(err))
+	} else if reclaimed > 0 {
+		s.logger.Warn("reclaimed stale bulk check jobs", zap.Int("count", reclaimed))
+	}
+
+	ids, err := s.jobRepo.GetUnfinishedIDs(ctx)
+	if err != nil {
+		s.logger.Error("failed to load unfinished bulk check jobs", zap.Error(err))
+	}
+	for _, id := range ids {
+		select {
+		case s.queue <- id:
+		case <-ctx.Done():
+			return
+		}
+	}
+	if len(ids) > 0 {
+		s.logger.Info("resumed unfinished bulk check jobs", zap.Int("count", len(ids)))
+	}
+
+	<-ctx.Done()
+}
+
+// Submit разбирает загруженный файл, создает задание и ставит его в очередь
+func (s *BulkCheckService) Submit(ctx context.Context, userID, fileName string, data []byte, outputFormat spreadsheet.Format) (*model.BulkCheckJob, error) {
+	rows, err := spreadsheet.ReadRows(data)
+	if err != nil {
+		return nil, fmt.Errorf("parse file: %w", err)
+	}
+
+	identifiers := ExtractIdentifiers(rows)
+	if len(identifiers) == 0 {
+		return nil, fmt.Errorf("file contains no INN/OGRN values")
+	}
+	if s.cfg.MaxRows > 0 && len(identifiers) > s.cfg.MaxRows {
+		return nil, fmt.Errorf("too many rows: %d (max %d)", len(identifiers), s.cfg.MaxRows)
+	}
+
+	job := &model.BulkCheckJob{
+		UserID:       userID,
+		OutputFormat: string(outputFormat),
+		Identifiers:  identifiers,
+	}
+	if fileName != "" {
+		job.FileName = &fileName
+	}
+
+	if err := s.jobRepo.Create(ctx, job); err != nil {
+		return nil, err
+	}
+
+	select {
+	case s.queue <- job.ID:
+	default:
+		if err := s.jobRepo.Fail(ctx, job.ID, ErrBulkCheckQueueFull.Error()); err != nil {
+			s.logger.Error("failed to mark bulk check job failed", zap.String("job_id", job.ID), zap.Error(err))
+		}
+		return nil, ErrBulkCheckQueueFull
+	}
+
+	return job, nil
+}
+
+// GetJob возвращает задание пользователя (nil, если не найдено или принадлежит другому пользователю)
+func (s *BulkCheckService) GetJob(ctx context.Context, userID, jobID string) (*model.BulkCheckJob, error) {
+	job, err := s.jobRepo.GetByID(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+	if job == nil || job.UserID != userID {
+		return nil, nil
+	}
+	return job, nil
+}
+
+// ListJobs возвращает последние задания пользователя
+func (s *BulkCheckService) ListJobs(ctx context.Context, userID string, limit int) ([]*model.BulkCheckJob, error) {
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+	return s.jobRepo.GetByUserID(ctx, userID, limit)
+}
+
+// WriteReport записывает результаты завершенного задания в указанном формате
+func (s *BulkCheckService) WriteReport(ctx context.Context, job *model.BulkCheckJob, w io.Writer, format spreadsheet.Format) error {
+	results, err := s.jobRepo.GetResults(ctx, job.ID)
+	if err != nil {
+		return err
+	}
+
+	sw, err := spreadsheet.NewWriter(w, format)
+	if err != nil {
+		return err
+	}
+	if err := sw.WriteRow(BulkCheckHeader); err != nil {
+		return err
+	}
+	for _, res := range results {
+		if err := sw.WriteRow(bulkCheckRow(res)); err != nil {
+			return err
+		}
+	}
+	return sw.Close()
+}
+
+// worker обрабатывает задания из очереди
+func (s *BulkCheckService) worker(ctx context.Context, n int) {
+	logger := s.logger.With(zap.Int("worker", n))
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case id := <-s.queue:
+			if err := s.process(ctx, id); err != nil {
+				if ctx.Err() != nil {
+					// Остановка сервиса: задание остается pending или будет
+					// возвращено в pending по истечении StaleJobTimeout
+					logger.Warn("bulk check job interrupted", zap.String("job_id", id), zap.Error(err))
+					continue
+				}
+				logger.Error("bulk check job failed", zap.String("job_id", id), zap.Error(err))
+				if ferr := s.jobRepo.Fail(context.Background(), id, err.Error()); ferr != nil {
+					logger.Error("failed to mark bulk check job failed", zap.String("job_id", id), zap.Error(ferr))
+				}
+			}
+		}
+	}
+}
+
+// process выполняет одно задание
+func (s *BulkCheckService) process(ctx context.Context, jobID string) error {
+	job, err := s.jobRepo.GetByID(ctx, jobID)
+	if err != nil {
+		return err
+	}
+	if job == nil || job.Status == model.BulkCheckStatusCompleted || job.Status == model.BulkCheckStatusFailed {
+		return nil
+	}
+
+	claimed, err := s.jobRepo.MarkRunning(ctx, jobID)
+	if err != nil {
+		return err
+	}
+	if !claimed {
+		// Задание уже выполняет другой воркер (повторная постановка в очередь
+		// при возобновлении или другой экземпляр gateway)
+		s.logger.Debug("bulk check job already claimed", zap.String("job_id", jobID))
+		return nil
+	}
+
+	start := time.Now()
+	results := make([]*model.BulkCheckResult, 0, len(job.Identifiers))
+	found := 0
+	for i, identifier := range job.Identifiers {
+		if ctx.Err() != nil {
+			// Задание возвращается в pending и будет возобновлено при следующем запуске
+			if err := s.jobRepo.Release(context.Background(), jobID); err != nil {
+				s.logger.Error("failed to release bulk check job", zap.String("job_id", jobID), zap.Error(err))
+			}
+			return nil
+		}
+
+		res := s.checkOne(ctx, jobID, identifier)
+		if res.Found {
+			found++
+		}
+		results = append(results, res)
+
+		if (i+1)%bulkCheckProgressStep == 0 {
+			if err := s.jobRepo.UpdateProgress(ctx, jobID, i+1, found); err != nil {
+				s.logger.Warn("failed to update bulk check progress", zap.String("job_id", jobID), zap.Error(err))
+			}
+		}
+	}
+
+	if err := s.jobRepo.Complete(ctx, jobID, results); err != nil {
+		return err
+	}
+
+	s.logger.Info("bulk check job processed",
+		zap.String("job_id", jobID),
+		zap.Int("rows", len(results)),
+		zap.Int("found", found),
+		zap.Duration("duration", time.Since(start)),
+	)
+	return nil
+}
+
+// checkOne проверяет одного контрагента по ИНН/ОГРН/ОГРНИП. Отчет получает
+// только bulkCheckLookupFailed, подробности ошибки пишутся в лог.
+func (s *BulkCheckService) checkOne(ctx context.Context, jobID, identifier string) *model.BulkCheckResult {
+	ctx, cancel := context.WithTimeout(ctx, bulkCheckRowTimeout)
+	defer cancel()
+
+	res := &model.BulkCheckResult{Input: identifier}
+
+	var company *model.Company
+	var entrepreneur *model.Entrepreneur
+	var err error
+
+	switch len(identifier) {
+	case 10:
+		company, err = s.companyService.GetByINN(ctx, identifier)
+	case 13:
+		company, err = s.companyService.GetByOGRN(ctx, identifier)
+	case 12:
+		entrepreneur, err = s.entrepreneurService.GetByINN(ctx, identifier)
+	case 15:
+		entrepreneur, err = s.entrepreneurService.GetByOGRNIP(ctx, identifier)
+	}
+	if err != nil {
+		s.logger.Warn("failed to look up bulk check identifier",
+			zap.String("job_id", jobID),
+			zap.String("identifier", identifier),
+			zap.Error(err),
+		)
+		res.Error = bulkCheckLookupFailed
+		return res
+	}
+
+	switch {
+	case company != nil:
+		s.annotateCompany(ctx, res, company)
+	case entrepreneur != nil:
+		s.annotateEntrepreneur(ctx, res, entrepreneur)
+	}
+
+	return res
+}
+
+func (s *BulkCheckService) annotateCompany(ctx context.Context, res *model.BulkCheckResult, c *model.Company) {
+	res.EntityType = "company"
+	res.Found = true
+	res.Ogrn = c.Ogrn
+	res.Inn = c.Inn
+	res.Name = c.FullName
+	res.Status = c.Status.String()
+	if c.StatusCode != nil {
+		res.StatusCode = *c.StatusCode
+	}
+	res.IsLiquidating = c.IsLiquidating || c.Status == model.EntityStatusLiquidating
+	res.IsBankrupt = c.IsBankrupt || c.Status == model.EntityStatusBankrupt
+	res.IsReorganizing = c.IsReorganizing || c.Status == model.EntityStatusReorganizing
+	if c.Director != nil {
+		res.Director = formatPersonName(c.Director.LastName, c.Director.FirstName, c.Director.MiddleName)
+		if c.Director.Position != nil && *c.Director.Position != "" {
+			res.Director += " (" + *c.Director.Position + ")"
+		}
+	}
+	setRegistrationAge(res, c.RegistrationDate)
+
+	count, err := s.companyService.CountCompaniesWithCommonAddress(ctx, c.Ogrn, s.cfg.MassAddressThreshold)
+	if err != nil {
+		s.logger.Warn("failed to count companies with common address", zap.String("ogrn", c.Ogrn), zap.Error(err))
+	} else {
+		res.SameAddressCount = count
+		res.MassAddress = count >= s.cfg.MassAddressThreshold
+	}
+
+	history, err := s.companyService.GetHistory(ctx, c.Ogrn, bulkCheckLatestChanges, 0)
+	if err != nil {
+		s.logger.Warn("failed to get company history", zap.String("ogrn", c.Ogrn), zap.Error(err))
+	}
+	res.LatestChanges = formatHistory(history)
+
+	if s.riskService != nil {
+		risk, err := s.riskService.Assess(ctx, c)
+		if err != nil {
+			s.logger.Warn("failed to assess company risk", zap.String("ogrn", c.Ogrn), zap.Error(err))
+		} else if risk != nil {
+			res.RiskScore = &risk.Score
+			res.RiskLevel = string(risk.Level)
+			for _, f := range risk.Factors {
+				res.RiskFactors = append(res.RiskFactors, f.Description)
+			}
+		}
+	}
+}
+
+func (s *BulkCheckService) annotateEntrepreneur(ctx context.Context, res *model.BulkCheckResult, e *model.Entrepreneur) {
+	res.EntityType = "entrepreneur"
+	res.Found = true
+	res.Ogrn = e.Ogrnip
+	res.Inn = e.Inn
+	res.Name = formatPersonName(e.LastName, e.FirstName, e.MiddleName)
+	res.Status = e.Status.String()
+	if e.StatusCode != nil {
+		res.StatusCode = *e.StatusCode
+	}
+	res.IsLiquidating = e.Status == model.EntityStatusLiquidating
+	res.IsBankrupt = e.IsBankrupt || e.Status == model.EntityStatusBankrupt
+	setRegistrationAge(res, e.RegistrationDate)
+
+	history, err := s.entrepreneurService.GetHistory(ctx, e.Ogrnip, bulkCheckLatestChanges, 0)
+	if err != nil {
+		s.logger.Warn("failed to get entrepreneur history", zap.String("ogrnip", e.Ogrnip), zap.Error(err))
+	}
+	res.LatestChanges = formatHistory(history)
+}
+
+// ExtractIdentifiers извлекает ИНН/ОГРН/ОГРНИП из строк таблицы.
+// Из каждой строки берется первая ячейка, похожая на идентификатор;
+// строки без идентификатора (например, заголовок) пропускаются, дубликаты удаляются.
+func ExtractIdentifiers(rows [][]string) []string {
+	seen := make(map[string]bool)
+	var identifiers []string
+	for _, row := range rows {
+		for _, cell := range row {
+			id := normalizeIdentifier(cell)
+			if id == "" {
+				continue
+			}
+			if !seen[id] {
+				seen[id] = true
+				identifiers = append(identifiers, id)
+			}
+			break
+		}
+	}
+	return identifiers
+}
+
+// normalizeIdentifier приводит значение ячейки к ИНН/ОГРН/ОГРНИП или возвращает пустую строку
+func normalizeIdentifier(cell string) string {
+	cell = strings.TrimSpace(cell)
+	if cell == "" {
+		return ""
+	}
+
+	// Excel сохраняет длинные числа в экспоненциальной записи
+	if strings.ContainsAny(cell, "eE") {
+		if f, err := strconv.ParseFloat(cell, 64); err == nil {
+			cell = strconv.FormatFloat(f, 'f', 0, 64)
+		}
+	}
+
+	var sb strings.Builder
+	for _, ch := range cell {
+		switch {
+		case ch >= '0' && ch <= '9':
+			sb.WriteRune(ch)
+		case ch == ' ' || ch == '-' || ch == '\u00a0' || ch == '\'':
+			// разделители разрядов и апостроф текстового формата Excel
+		default:
+			return ""
+		}
+	}
+
+	id := sb.String()
+	switch len(id) {
+	case 10, 12, 13, 15:
+		return id
+	default:
+		return ""
+	}
+}
+
+func setRegistrationAge(res *model.BulkCheckResult, regDate *model.Date) {
+	if regDate == nil || regDate.IsZero() {
+		return
+	}
+	t := regDate.Time
+	res.RegistrationDate = &t
+	res.RegistrationAgeDays = int(time.Since(t).Hours() / 24)
+}
+
+func formatPersonName(lastName, firstName string, middleName *string) string {
+	parts := []string{lastName, firstName}
+	if middleName != nil {
+		parts = append(parts, *middleName)
+	}
+	return strings.TrimSpace(strings.Join(strings.Fields(strings.Join(parts, " ")), " "))
+}
+
+func formatHistory(records []*model.HistoryRecord) []string {
+	changes := make([]string, 0, len(records))
+	for _, rec := range records {
+		desc := rec.Grn
+		if rec.ReasonDescription != nil && *rec.ReasonDescription != "" {
+			desc = *rec.ReasonDescription
+		}
+		changes = append(changes, rec.Date.Format("2006-01-02")+": "+desc)
+	}
+	return changes
+}
+
+// bulkCheckRow преобразует результат проверки в строку отчета
+func bulkCheckRow(r *model.BulkCheckResult) []string {
+	yesNo := func(v bool) string {
+		if v {
+			return "да"
+		}
+		return "нет"
+	}
+
+	entityType := ""
+	switch r.EntityType {
+	case "company":
+		entityType = "ЮЛ"
+	case "entrepreneur":
+		entityType = "ИП"
+	}
+
+	regDate, age := "", ""
+	if r.RegistrationDate != nil {
+		regDate = r.RegistrationDate.Format("2006-01-02")
+		age = strconv.Itoa(r.RegistrationAgeDays)
+	}
+
+	if !r.Found {
+		return []string{r.Input, entityType, yesNo(false), "", "", "", "", "", "", "", "", "", "", "", "", "", "", "", "", "", r.Error}
+	}
+
+	riskScore := ""
+	if r.RiskScore != nil {
+		riskScore = strconv.Itoa(*r.RiskScore)
+	}
+
+	return []string{
+		r.Input,
+		entityType,
+		yesNo(true),
+		r.Ogrn,
+		r.Inn,
+		r.Name,
+		r.Status,
+		r.StatusCode,
+		yesNo(r.IsLiquidating),
+		yesNo(r.IsBankrupt),
+		yesNo(r.IsReorganizing),
+		r.Director,
+		regDate,
+		age,
+		yesNo(r.MassAddress),
+		strconv.Itoa(r.SameAddressCount),
+		strings.Join(r.LatestChanges, "; "),
+		riskScore,
+		r.RiskLevel,
+		strings.Join(r.RiskFactors, "; "),
+		r.Error,
+	}
+}
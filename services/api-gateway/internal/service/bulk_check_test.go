@@ -0,0 +1,30 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/egrul-system/services/api-gateway/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+func TestBulkCheckService_CheckOneHidesLookupErrors(t *testing.T) {
+	// Arrange
+	companyRepo := new(MockCompanyRepository)
+	companyRepo.On("GetByINN", mock.Anything, "7707083893").
+		Return(nil, errors.New("clickhouse: code: 241, message: memory limit exceeded"))
+	companies := NewCompanyService(companyRepo, nil, nil, nil, nil, zap.NewNop())
+	svc := NewBulkCheckService(nil, companies, nil, nil, config.BulkCheckConfig{}, zap.NewNop())
+
+	// Act
+	res := svc.checkOne(context.Background(), "job-1", "7707083893")
+
+	// Assert
+	assert.False(t, res.Found)
+	assert.Equal(t, "7707083893", res.Input)
+	assert.Equal(t, "lookup failed", res.Error)
+	companyRepo.AssertExpectations(t)
+}
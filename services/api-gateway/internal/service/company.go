@@ -3,14 +3,21 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/egrul-system/services/api-gateway/internal/graph/model"
 	"github.com/egrul-system/services/api-gateway/internal/repository"
 	"github.com/egrul-system/services/api-gateway/internal/repository/clickhouse"
+	"github.com/egrul-system/services/shared/pkg/diff"
 	"go.uber.org/zap"
 )
 
+// ErrVersionNotAvailable возвращается, когда сущность существовала на запрошенную дату,
+// но сохраненной версии карточки на эту дату нет (версии копятся с момента включения хранения)
+var ErrVersionNotAvailable = errors.New("no stored version for the requested date")
+
 // CompanyService сервис для работы с компаниями
 type CompanyService struct {
 	companyRepo  repository.CompanyRepository
@@ -18,6 +25,7 @@ type CompanyService struct {
 	licenseRepo  repository.LicenseRepository
 	branchRepo   repository.BranchRepository
 	historyRepo  repository.HistoryRepository
+	comparator   *diff.Comparator
 	logger       *zap.Logger
 }
 
@@ -36,6 +44,7 @@ func NewCompanyService(
 		licenseRepo:  licenseRepo,
 		branchRepo:   branchRepo,
 		historyRepo:  historyRepo,
+		comparator:   diff.NewComparator(logger.Named("company_diff"), nil),
 		logger:       logger.Named("company_service"),
 	}
 }
@@ -45,6 +54,39 @@ func (s *CompanyService) GetByOGRN(ctx context.Context, ogrn string) (*model.Com
 	return s.companyRepo.GetByOGRN(ctx, ogrn)
 }
 
+// GetByOGRNAsOf восстанавливает карточку компании на дату asOf по сохраненным версиям.
+// Возвращает nil, если компания на эту дату еще не была зарегистрирована.
+func (s *CompanyService) GetByOGRNAsOf(ctx context.Context, ogrn string, asOf time.Time) (*model.Company, error) {
+	company, err := s.companyRepo.GetByOGRNAsOf(ctx, ogrn, asOf)
+	if err != nil || company != nil {
+		return company, err
+	}
+
+	current, err := s.companyRepo.GetByOGRN(ctx, ogrn)
+	if err != nil || current == nil {
+		return nil, err
+	}
+	if current.RegistrationDate != nil && current.RegistrationDate.After(asOf) {
+		return nil, nil
+	}
+	return nil, fmt.Errorf("company %s as of %s: %w", ogrn, asOf.Format("2006-01-02"), ErrVersionNotAvailable)
+}
+
+// GetFoundersAsOf получает учредителей в составе, соответствующем версии карточки.
+// Для текущей карточки (без VersionInfo) возвращает актуальный состав.
+func (s *CompanyService) GetFoundersAsOf(ctx context.Context, company *model.Company, limit, offset int) ([]*model.Founder, error) {
+	if company.VersionInfo == nil {
+		return s.GetFounders(ctx, company.Ogrn, limit, offset)
+	}
+	if company.FoundersCount == 0 {
+		return []*model.Founder{}, nil
+	}
+	if limit <= 0 {
+		limit = 100
+	}
+	return s.founderRepo.GetByCompanyOGRNAsOf(ctx, company.Ogrn, company.VersionInfo.VersionDate.Time, limit, offset)
+}
+
 // GetByINN получает компанию по ИНН
 func (s *CompanyService) GetByINN(ctx context.Context, inn string) (*model.Company, error) {
 	return s.companyRepo.GetByINN(ctx, inn)
@@ -349,6 +391,15 @@ func (s *CompanyService) GetCompaniesWithCommonAddress(ctx context.Context, ogrn
 	return companies, nil
 }
 
+// CountCompaniesWithCommonAddress возвращает количество других компаний по адресу регистрации (не более limit)
+func (s *CompanyService) CountCompaniesWithCommonAddress(ctx context.Context, ogrn string, limit int) (int, error) {
+	ogrns, err := s.founderRepo.GetCompaniesWithCommonAddress(ctx, ogrn, limit, 0)
+	if err != nil {
+		return 0, err
+	}
+	return len(ogrns), nil
+}
+
 // GetCommonAddressDetails получает детальную информацию об общем адресе между двумя компаниями
 func (s *CompanyService) GetCommonAddressDetails(ctx context.Context, ogrn1, ogrn2 string) (*model.Address, error) {
 	return s.founderRepo.GetCommonAddressDetails(ctx, ogrn1, ogrn2)
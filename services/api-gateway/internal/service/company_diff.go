@@ -0,0 +1,277 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/egrul-system/services/api-gateway/internal/graph/model"
+	"github.com/egrul-system/services/shared/pkg/diff"
+	"go.uber.org/zap"
+)
+
+// maxDiffFounders ограничение на число учредителей, загружаемых для сравнения версий
+const maxDiffFounders = 1000
+
+// Diff сравнивает версии карточки компании, действовавшие на даты from и to.
+// Сравнение выполняется тем же diff.Comparator, что и в change-detection-service,
+// поэтому состав и значимость изменений совпадают с потоком событий.
+// Возвращает nil, если компания не найдена на дату to.
+func (s *CompanyService) Diff(ctx context.Context, ogrn string, from, to time.Time) (*model.CompanyDiff, error) {
+	if from.After(to) {
+		return nil, fmt.Errorf("from date %s is after to date %s", from.Format("2006-01-02"), to.Format("2006-01-02"))
+	}
+
+	newVersion, err := s.GetByOGRNAsOf(ctx, ogrn, to)
+	if err != nil || newVersion == nil {
+		return nil, err
+	}
+	oldVersion, err := s.GetByOGRNAsOf(ctx, ogrn, from)
+	if err != nil {
+		return nil, err
+	}
+	if oldVersion == nil {
+		return nil, fmt.Errorf("company %s was not registered on %s", ogrn, from.Format("2006-01-02"))
+	}
+
+	oldSnapshot, oldFounders, err := s.diffSnapshot(ctx, oldVersion)
+	if err != nil {
+		return nil, err
+	}
+	newSnapshot, newFounders, err := s.diffSnapshot(ctx, newVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := s.comparator.CompareCompany(oldSnapshot, newSnapshot)
+	changes = append(changes, s.comparator.CompareAttributes(oldSnapshot, newSnapshot)...)
+
+	result := &model.CompanyDiff{
+		Ogrn:       ogrn,
+		From:       oldVersion.VersionInfo,
+		To:         newVersion.VersionInfo,
+		HasChanges: len(changes) > 0,
+		Changes:    []*model.DiffChange{},
+		Founders:   []*model.FounderDiff{},
+		Activities: &model.SetDiff{Added: []*model.DiffItem{}, Removed: []*model.DiffItem{}},
+		Licenses:   &model.SetDiff{Added: []*model.DiffItem{}, Removed: []*model.DiffItem{}},
+		Branches:   &model.SetDiff{Added: []*model.DiffItem{}, Removed: []*model.DiffItem{}},
+	}
+
+	activityNames := activityNames(oldVersion, newVersion)
+	for _, change := range changes {
+		switch change.Field {
+		case "founder_added", "founder_removed", "founder_share":
+			founder := newFounders[change.Key]
+			if founder == nil {
+				founder = oldFounders[change.Key]
+			}
+			result.Founders = append(result.Founders, toFounderDiff(change, founder))
+		case "activity_added":
+			result.Activities.Added = append(result.Activities.Added, toDiffItem(change, activityNames[change.Key]))
+		case "activity_removed":
+			result.Activities.Removed = append(result.Activities.Removed, toDiffItem(change, activityNames[change.Key]))
+		case "license_added":
+			result.Licenses.Added = append(result.Licenses.Added, toDiffItem(change, change.Name))
+		case "license_removed":
+			result.Licenses.Removed = append(result.Licenses.Removed, toDiffItem(change, change.Name))
+		case "branch_added":
+			result.Branches.Added = append(result.Branches.Added, toDiffItem(change, change.Name))
+		case "branch_removed":
+			result.Branches.Removed = append(result.Branches.Removed, toDiffItem(change, change.Name))
+		default:
+			result.Changes = append(result.Changes, toDiffChange(change))
+		}
+	}
+
+	s.logger.Debug("company versions compared",
+		zap.String("ogrn", ogrn),
+		zap.Time("from", from),
+		zap.Time("to", to),
+		zap.Int("changes_count", len(changes)),
+	)
+
+	return result, nil
+}
+
+// diffSnapshot собирает версию карточки для сравнения: учредители, лицензии и филиалы
+// берутся в составе, загруженном вместе с этой версией
+func (s *CompanyService) diffSnapshot(ctx context.Context, company *model.Company) (*diff.Company, map[string]*model.Founder, error) {
+	founders, err := s.GetFoundersAsOf(ctx, company, maxDiffFounders, 0)
+	if err != nil {
+		return nil, nil, fmt.Errorf("get founders for diff: %w", err)
+	}
+
+	var licenses []*model.License
+	var branches []*model.Branch
+	if company.VersionInfo != nil {
+		versionDate := company.VersionInfo.VersionDate.Time
+		if licenses, err = s.licenseRepo.GetByEntityOGRNAsOf(ctx, company.Ogrn, versionDate); err != nil {
+			return nil, nil, fmt.Errorf("get licenses for diff: %w", err)
+		}
+		if branches, err = s.branchRepo.GetByCompanyOGRNAsOf(ctx, company.Ogrn, versionDate); err != nil {
+			return nil, nil, fmt.Errorf("get branches for diff: %w", err)
+		}
+	}
+
+	snapshot := &diff.Company{
+		OGRN:            company.Ogrn,
+		INN:             company.Inn,
+		KPP:             derefString(company.Kpp),
+		FullName:        company.FullName,
+		ShortName:       derefString(company.ShortName),
+		Status:          string(company.Status),
+		Email:           derefString(company.Email),
+		LicensesCount:   company.LicensesCount,
+		BranchesCount:   company.BranchesCount,
+		AdditionalOKVED: []string{},
+	}
+
+	if d := company.Director; d != nil {
+		snapshot.DirectorFullName = strings.TrimSpace(strings.Join([]string{d.LastName, d.FirstName, derefString(d.MiddleName)}, " "))
+		snapshot.DirectorINN = derefString(d.Inn)
+		snapshot.DirectorPosition = derefString(d.Position)
+	}
+	if a := company.Address; a != nil {
+		snapshot.Address = diff.Address{
+			Full:       derefString(a.FullAddress),
+			PostalCode: derefString(a.PostalCode),
+			Region:     derefString(a.Region),
+			City:       derefString(a.City),
+			Street:     derefString(a.Street),
+			House:      derefString(a.House),
+		}
+	}
+	if company.Capital != nil {
+		snapshot.AuthorizedCapital = company.Capital.Amount
+		snapshot.CapitalCurrency = company.Capital.Currency
+	}
+	if company.MainActivity != nil {
+		snapshot.MainOKVED = company.MainActivity.Code
+	}
+	for _, activity := range company.Activities {
+		if !activity.IsMain {
+			snapshot.AdditionalOKVED = append(snapshot.AdditionalOKVED, activity.Code)
+		}
+	}
+
+	byKey := make(map[string]*model.Founder, len(founders))
+	for _, f := range founders {
+		founder := diff.Founder{
+			FullName: f.Name,
+			INN:      derefString(f.Inn),
+			OGRN:     derefString(f.Ogrn),
+		}
+		if f.ShareNominalValue != nil {
+			founder.ShareAmount = *f.ShareNominalValue
+		}
+		if f.SharePercent != nil {
+			founder.SharePercent = *f.SharePercent
+		}
+		snapshot.Founders = append(snapshot.Founders, founder)
+		byKey[founder.Key()] = f
+	}
+
+	// Поэлементно сравниваем только полностью сохраненные составы; если версия
+	// загружена до начала хранения лицензий/филиалов, сравниваются количества
+	if len(licenses) == company.LicensesCount {
+		snapshot.Licenses = make([]diff.Item, 0, len(licenses))
+		for _, l := range licenses {
+			snapshot.Licenses = append(snapshot.Licenses, diff.Item{Key: l.Number, Name: derefString(l.Activity)})
+		}
+	}
+	if len(branches) == company.BranchesCount {
+		snapshot.Branches = make([]diff.Item, 0, len(branches))
+		for _, b := range branches {
+			snapshot.Branches = append(snapshot.Branches, branchItem(b))
+		}
+	}
+
+	return snapshot, byKey, nil
+}
+
+// branchItem ключ филиала — КПП, а при его отсутствии адрес
+func branchItem(b *model.Branch) diff.Item {
+	item := diff.Item{Key: derefString(b.Kpp), Name: derefString(b.Name)}
+	if b.Address != nil && b.Address.FullAddress != nil {
+		if item.Key == "" {
+			item.Key = *b.Address.FullAddress
+		}
+		if item.Name == "" {
+			item.Name = *b.Address.FullAddress
+		}
+	}
+	return item
+}
+
+// activityNames собирает наименования ОКВЭД обеих версий
+func activityNames(versions ...*model.Company) map[string]string {
+	names := make(map[string]string)
+	for _, company := range versions {
+		for _, activity := range company.Activities {
+			if activity.Name != nil {
+				names[activity.Code] = *activity.Name
+			}
+		}
+	}
+	return names
+}
+
+func toDiffChange(change diff.Change) *model.DiffChange {
+	result := &model.DiffChange{
+		Field:         change.Field,
+		ChangeType:    string(change.Type),
+		IsSignificant: change.IsSignificant,
+		Description:   change.Description,
+	}
+	if change.OldValue != "" {
+		result.OldValue = &change.OldValue
+	}
+	if change.NewValue != "" {
+		result.NewValue = &change.NewValue
+	}
+	return result
+}
+
+func toFounderDiff(change diff.Change, founder *model.Founder) *model.FounderDiff {
+	result := &model.FounderDiff{
+		Name:            change.Name,
+		OldSharePercent: change.OldShare,
+		NewSharePercent: change.NewShare,
+		IsSignificant:   change.IsSignificant,
+	}
+	if founder != nil {
+		result.Inn = founder.Inn
+		result.Ogrn = founder.Ogrn
+	}
+
+	switch change.Type {
+	case diff.ChangeTypeFounderAdded:
+		result.Change = model.FounderChangeKindAdded
+	case diff.ChangeTypeFounderRemoved:
+		result.Change = model.FounderChangeKindRemoved
+	default:
+		result.Change = model.FounderChangeKindShareChanged
+	}
+	if change.OldShare != nil && change.NewShare != nil {
+		delta := *change.NewShare - *change.OldShare
+		result.ShareDelta = &delta
+	}
+	return result
+}
+
+func toDiffItem(change diff.Change, name string) *model.DiffItem {
+	item := &model.DiffItem{Key: change.Key, IsSignificant: change.IsSignificant}
+	if name != "" {
+		item.Name = &name
+	}
+	return item
+}
+
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/egrul-system/services/api-gateway/internal/graph/model"
 	"github.com/stretchr/testify/assert"
@@ -36,6 +37,14 @@ func (m *MockCompanyRepository) GetByINN(ctx context.Context, inn string) (*mode
 	return args.Get(0).(*model.Company), args.Error(1)
 }
 
+func (m *MockCompanyRepository) GetByOGRNAsOf(ctx context.Context, ogrn string, asOf time.Time) (*model.Company, error) {
+	args := m.Called(ctx, ogrn, asOf)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Company), args.Error(1)
+}
+
 func (m *MockCompanyRepository) List(ctx context.Context, filter *model.CompanyFilter, pagination *model.Pagination, sort *model.CompanySort) ([]*model.Company, int, error) {
 	args := m.Called(ctx, filter, pagination, sort)
 	return args.Get(0).([]*model.Company), args.Int(1), args.Error(2)
@@ -62,6 +71,14 @@ func (m *MockFounderRepository) GetByCompanyOGRN(ctx context.Context, ogrn strin
 	return args.Get(0).([]*model.Founder), args.Error(1)
 }
 
+func (m *MockFounderRepository) GetByCompanyOGRNAsOf(ctx context.Context, ogrn string, versionDate time.Time, limit, offset int) ([]*model.Founder, error) {
+	args := m.Called(ctx, ogrn, versionDate, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.Founder), args.Error(1)
+}
+
 func (m *MockFounderRepository) GetRelatedCompanies(ctx context.Context, inn string, limit, offset int) ([]string, error) {
 	args := m.Called(ctx, inn, limit, offset)
 	if args.Get(0) == nil {
@@ -163,6 +180,14 @@ func (m *MockLicenseRepository) GetByEntityOGRN(ctx context.Context, ogrn string
 	return args.Get(0).([]*model.License), args.Error(1)
 }
 
+func (m *MockLicenseRepository) GetByEntityOGRNAsOf(ctx context.Context, ogrn string, versionDate time.Time) ([]*model.License, error) {
+	args := m.Called(ctx, ogrn, versionDate)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.License), args.Error(1)
+}
+
 // MockBranchRepository мок для BranchRepository
 type MockBranchRepository struct {
 	mock.Mock
@@ -176,6 +201,14 @@ func (m *MockBranchRepository) GetByCompanyOGRN(ctx context.Context, ogrn string
 	return args.Get(0).([]*model.Branch), args.Error(1)
 }
 
+func (m *MockBranchRepository) GetByCompanyOGRNAsOf(ctx context.Context, ogrn string, versionDate time.Time) ([]*model.Branch, error) {
+	args := m.Called(ctx, ogrn, versionDate)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.Branch), args.Error(1)
+}
+
 // MockHistoryRepository мок для HistoryRepository
 type MockHistoryRepository struct {
 	mock.Mock
@@ -431,3 +464,166 @@ func TestCompanyService_GetHistoryCount_Success(t *testing.T) {
 	assert.Equal(t, 42, result)
 	mockHistoryRepo.AssertExpectations(t)
 }
+
+func TestCompanyService_GetByOGRNAsOf_StoredVersion(t *testing.T) {
+	// Arrange
+	mockCompanyRepo := new(MockCompanyRepository)
+	logger := zap.NewNop()
+
+	asOf := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+	expected := &model.Company{
+		Ogrn:        "1234567890123",
+		FullName:    "ООО СТАРОЕ НАИМЕНОВАНИЕ",
+		VersionInfo: &model.VersionInfo{AsOf: model.Date{Time: asOf}},
+	}
+
+	mockCompanyRepo.On("GetByOGRNAsOf", mock.Anything, "1234567890123", asOf).Return(expected, nil)
+
+	service := NewCompanyService(mockCompanyRepo, nil, nil, nil, nil, logger)
+
+	// Act
+	result, err := service.GetByOGRNAsOf(context.Background(), "1234567890123", asOf)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, expected, result)
+	mockCompanyRepo.AssertExpectations(t)
+}
+
+func TestCompanyService_GetByOGRNAsOf_NotRegisteredYet(t *testing.T) {
+	// Arrange
+	mockCompanyRepo := new(MockCompanyRepository)
+	logger := zap.NewNop()
+
+	asOf := time.Date(2010, 1, 1, 0, 0, 0, 0, time.UTC)
+	current := &model.Company{
+		Ogrn:             "1234567890123",
+		RegistrationDate: model.NewDate(time.Date(2015, 3, 10, 0, 0, 0, 0, time.UTC)),
+	}
+
+	mockCompanyRepo.On("GetByOGRNAsOf", mock.Anything, "1234567890123", asOf).Return(nil, nil)
+	mockCompanyRepo.On("GetByOGRN", mock.Anything, "1234567890123").Return(current, nil)
+
+	service := NewCompanyService(mockCompanyRepo, nil, nil, nil, nil, logger)
+
+	// Act
+	result, err := service.GetByOGRNAsOf(context.Background(), "1234567890123", asOf)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Nil(t, result)
+	mockCompanyRepo.AssertExpectations(t)
+}
+
+func TestCompanyService_GetByOGRNAsOf_VersionNotAvailable(t *testing.T) {
+	// Arrange
+	mockCompanyRepo := new(MockCompanyRepository)
+	logger := zap.NewNop()
+
+	asOf := time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+	current := &model.Company{
+		Ogrn:             "1234567890123",
+		RegistrationDate: model.NewDate(time.Date(2015, 3, 10, 0, 0, 0, 0, time.UTC)),
+	}
+
+	mockCompanyRepo.On("GetByOGRNAsOf", mock.Anything, "1234567890123", asOf).Return(nil, nil)
+	mockCompanyRepo.On("GetByOGRN", mock.Anything, "1234567890123").Return(current, nil)
+
+	service := NewCompanyService(mockCompanyRepo, nil, nil, nil, nil, logger)
+
+	// Act
+	result, err := service.GetByOGRNAsOf(context.Background(), "1234567890123", asOf)
+
+	// Assert
+	assert.ErrorIs(t, err, ErrVersionNotAvailable)
+	assert.Nil(t, result)
+	mockCompanyRepo.AssertExpectations(t)
+}
+
+func TestCompanyService_GetFoundersAsOf_UsesVersionDate(t *testing.T) {
+	// Arrange
+	mockFounderRepo := new(MockFounderRepository)
+	logger := zap.NewNop()
+
+	versionDate := time.Date(2020, 5, 20, 0, 0, 0, 0, time.UTC)
+	company := &model.Company{
+		Ogrn:          "1234567890123",
+		FoundersCount: 1,
+		VersionInfo:   &model.VersionInfo{VersionDate: model.Date{Time: versionDate}},
+	}
+	founders := []*model.Founder{
+		{Type: model.FounderTypePerson, Name: "Бывший Учредитель"},
+	}
+
+	mockFounderRepo.On("GetByCompanyOGRNAsOf", mock.Anything, "1234567890123", versionDate, 100, 0).Return(founders, nil)
+
+	service := NewCompanyService(nil, mockFounderRepo, nil, nil, nil, logger)
+
+	// Act
+	result, err := service.GetFoundersAsOf(context.Background(), company, 0, 0)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, founders, result)
+	mockFounderRepo.AssertExpectations(t)
+}
+
+func TestCompanyService_Diff_FoundersAndLicenses(t *testing.T) {
+	// Arrange
+	mockCompanyRepo := new(MockCompanyRepository)
+	mockFounderRepo := new(MockFounderRepository)
+	mockLicenseRepo := new(MockLicenseRepository)
+	mockBranchRepo := new(MockBranchRepository)
+	logger := zap.NewNop()
+
+	from := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	oldVersionDate := time.Date(2019, 12, 1, 0, 0, 0, 0, time.UTC)
+	newVersionDate := time.Date(2020, 12, 1, 0, 0, 0, 0, time.UTC)
+
+	oldCompany := &model.Company{
+		Ogrn:          "1234567890123",
+		Status:        model.EntityStatusActive,
+		FoundersCount: 1,
+		LicensesCount: 1,
+		VersionInfo:   &model.VersionInfo{VersionDate: model.Date{Time: oldVersionDate}},
+	}
+	newCompany := &model.Company{
+		Ogrn:          "1234567890123",
+		Status:        model.EntityStatusActive,
+		FoundersCount: 1,
+		LicensesCount: 0,
+		VersionInfo:   &model.VersionInfo{VersionDate: model.Date{Time: newVersionDate}},
+	}
+
+	oldShare, newShare := 100.0, 40.0
+	mockCompanyRepo.On("GetByOGRNAsOf", mock.Anything, "1234567890123", from).Return(oldCompany, nil)
+	mockCompanyRepo.On("GetByOGRNAsOf", mock.Anything, "1234567890123", to).Return(newCompany, nil)
+	mockFounderRepo.On("GetByCompanyOGRNAsOf", mock.Anything, "1234567890123", oldVersionDate, maxDiffFounders, 0).
+		Return([]*model.Founder{{Name: "Иванов", Inn: stringPtr("770000000001"), SharePercent: &oldShare}}, nil)
+	mockFounderRepo.On("GetByCompanyOGRNAsOf", mock.Anything, "1234567890123", newVersionDate, maxDiffFounders, 0).
+		Return([]*model.Founder{{Name: "Иванов", Inn: stringPtr("770000000001"), SharePercent: &newShare}}, nil)
+	mockLicenseRepo.On("GetByEntityOGRNAsOf", mock.Anything, "1234567890123", oldVersionDate).
+		Return([]*model.License{{Number: "Л041-01", Activity: stringPtr("Медицинская деятельность")}}, nil)
+	mockLicenseRepo.On("GetByEntityOGRNAsOf", mock.Anything, "1234567890123", newVersionDate).
+		Return([]*model.License{}, nil)
+	mockBranchRepo.On("GetByCompanyOGRNAsOf", mock.Anything, "1234567890123", mock.Anything).
+		Return([]*model.Branch{}, nil)
+
+	service := NewCompanyService(mockCompanyRepo, mockFounderRepo, mockLicenseRepo, mockBranchRepo, nil, logger)
+
+	// Act
+	result, err := service.Diff(context.Background(), "1234567890123", from, to)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.True(t, result.HasChanges)
+	assert.Empty(t, result.Changes)
+	assert.Len(t, result.Founders, 1)
+	assert.Equal(t, model.FounderChangeKindShareChanged, result.Founders[0].Change)
+	assert.Equal(t, -60.0, *result.Founders[0].ShareDelta)
+	assert.True(t, result.Founders[0].IsSignificant)
+	assert.Len(t, result.Licenses.Removed, 1)
+	assert.Equal(t, "Л041-01", result.Licenses.Removed[0].Key)
+	mockCompanyRepo.AssertExpectations(t)
+}
@@ -2,6 +2,8 @@ package service
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"github.com/egrul-system/services/api-gateway/internal/graph/model"
 	"github.com/egrul-system/services/api-gateway/internal/repository/clickhouse"
@@ -36,6 +38,24 @@ func (s *EntrepreneurService) GetByOGRNIP(ctx context.Context, ogrnip string) (*
 	return s.entrepreneurRepo.GetByOGRNIP(ctx, ogrnip)
 }
 
+// GetByOGRNIPAsOf восстанавливает карточку ИП на дату asOf по сохраненным версиям.
+// Возвращает nil, если ИП на эту дату еще не был зарегистрирован.
+func (s *EntrepreneurService) GetByOGRNIPAsOf(ctx context.Context, ogrnip string, asOf time.Time) (*model.Entrepreneur, error) {
+	entrepreneur, err := s.entrepreneurRepo.GetByOGRNIPAsOf(ctx, ogrnip, asOf)
+	if err != nil || entrepreneur != nil {
+		return entrepreneur, err
+	}
+
+	current, err := s.entrepreneurRepo.GetByOGRNIP(ctx, ogrnip)
+	if err != nil || current == nil {
+		return nil, err
+	}
+	if current.RegistrationDate != nil && current.RegistrationDate.After(asOf) {
+		return nil, nil
+	}
+	return nil, fmt.Errorf("entrepreneur %s as of %s: %w", ogrnip, asOf.Format("2006-01-02"), ErrVersionNotAvailable)
+}
+
 // GetByINN получает ИП по ИНН
 func (s *EntrepreneurService) GetByINN(ctx context.Context, inn string) (*model.Entrepreneur, error) {
 	return s.entrepreneurRepo.GetByINN(ctx, inn)
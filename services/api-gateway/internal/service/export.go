@@ -0,0 +1,221 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/egrul-system/services/api-gateway/internal/cache"
+	"github.com/egrul-system/services/api-gateway/internal/config"
+	"github.com/egrul-system/services/api-gateway/internal/graph/model"
+	"github.com/egrul-system/services/api-gateway/internal/repository/clickhouse"
+	"github.com/egrul-system/services/api-gateway/internal/spreadsheet"
+	sharedMetrics "github.com/egrul-system/services/shared/pkg/observability/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+var (
+	// ErrExportQuotaExceeded возвращается, когда дневной лимит строк пользователя исчерпан
+	ErrExportQuotaExceeded = errors.New("daily export row quota exceeded")
+	// ErrExportQuotaUnavailable счетчик квоты недоступен (Redis); выгрузка
+	// без учета квоты не выполняется
+	ErrExportQuotaUnavailable = errors.New("export quota is temporarily unavailable")
+)
+
+// exportQuotaTTL время жизни суточного счетчика квоты с запасом на смену суток
+const exportQuotaTTL = 25 * time.Hour
+
+// ExportRequest параметры выгрузки
+type ExportRequest struct {
+	UserID  string
+	Columns []string
+	Format  spreadsheet.Format
+	Limit   int
+}
+
+// ExportService потоковая выгрузка списков компаний и ИП в CSV/XLSX
+type ExportService struct {
+	companyRepo      *clickhouse.CompanyRepository
+	entrepreneurRepo *clickhouse.EntrepreneurRepository
+	cache            cache.Cache
+	cfg              config.ExportConfig
+	logger           *zap.Logger
+}
+
+// NewExportService создает новый сервис выгрузки
+func NewExportService(
+	companyRepo *clickhouse.CompanyRepository,
+	entrepreneurRepo *clickhouse.EntrepreneurRepository,
+	cache cache.Cache,
+	cfg config.ExportConfig,
+	logger *zap.Logger,
+) *ExportService {
+	return &ExportService{
+		companyRepo:      companyRepo,
+		entrepreneurRepo: entrepreneurRepo,
+		cache:            cache,
+		cfg:              cfg,
+		logger:           logger.Named("export_service"),
+	}
+}
+
+// ExportPlan подготовленная выгрузка: проверенные колонки и итоговый лимит
+// строк. При дневной квоте Limit строк уже зарезервирован в счетчике;
+// невыгруженный остаток возвращает Export*.
+type ExportPlan struct {
+	Request ExportRequest
+	Columns []clickhouse.ExportColumn
+	Limit   int
+
+	// quotaKey счетчик, в котором зарезервирован Limit; пусто - квота не ведется
+	quotaKey string
+}
+
+// PlanCompanies проверяет параметры выгрузки компаний до начала записи ответа
+func (s *ExportService) PlanCompanies(ctx context.Context, req ExportRequest) (*ExportPlan, error) {
+	return s.plan(ctx, req, clickhouse.CompanyExportColumns)
+}
+
+// PlanEntrepreneurs проверяет параметры выгрузки ИП до начала записи ответа
+func (s *ExportService) PlanEntrepreneurs(ctx context.Context, req ExportRequest) (*ExportPlan, error) {
+	return s.plan(ctx, req, clickhouse.EntrepreneurExportColumns)
+}
+
+// ExportCompanies выполняет выгрузку компаний
+func (s *ExportService) ExportCompanies(ctx context.Context, plan *ExportPlan, filter *model.CompanyFilter, w io.Writer) (int, error) {
+	return s.run(ctx, plan, w, sharedMetrics.CompanyExportsTotal, func(fn clickhouse.ExportRowFunc) (int, error) {
+		return s.companyRepo.Export(ctx, filter, plan.Columns, plan.Limit, fn)
+	})
+}
+
+// ExportEntrepreneurs выполняет выгрузку ИП
+func (s *ExportService) ExportEntrepreneurs(ctx context.Context, plan *ExportPlan, filter *model.EntrepreneurFilter, w io.Writer) (int, error) {
+	return s.run(ctx, plan, w, sharedMetrics.EntrepreneurExportsTotal, func(fn clickhouse.ExportRowFunc) (int, error) {
+		return s.entrepreneurRepo.Export(ctx, filter, plan.Columns, plan.Limit, fn)
+	})
+}
+
+func (s *ExportService) plan(ctx context.Context, req ExportRequest, available []clickhouse.ExportColumn) (*ExportPlan, error) {
+	columns, err := clickhouse.ResolveExportColumns(available, req.Columns)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := s.cfg.MaxRows
+	if req.Limit > 0 && (limit <= 0 || req.Limit < limit) {
+		limit = req.Limit
+	}
+
+	if s.cfg.DailyRowQuota <= 0 {
+		if limit <= 0 {
+			return nil, fmt.Errorf("export row limit is not configured")
+		}
+		return &ExportPlan{Request: req, Columns: columns, Limit: limit}, nil
+	}
+
+	if limit <= 0 || limit > s.cfg.DailyRowQuota {
+		limit = s.cfg.DailyRowQuota
+	}
+	key := quotaKey(req.UserID, time.Now())
+	limit, err = s.reserveQuota(ctx, key, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ExportPlan{Request: req, Columns: columns, Limit: limit, quotaKey: key}, nil
+}
+
+// reserveQuota резервирует до limit строк одним IncrBy, поэтому параллельные
+// выгрузки не могут вместе превысить квоту. Возвращает зарезервированное
+// число строк: при частично исчерпанной квоте - ее остаток.
+func (s *ExportService) reserveQuota(ctx context.Context, key string, limit int) (int, error) {
+	if s.cache == nil {
+		return 0, ErrExportQuotaUnavailable
+	}
+	total, err := s.cache.IncrBy(ctx, key, int64(limit), exportQuotaTTL)
+	if err != nil {
+		s.logger.Error("failed to reserve export quota", zap.String("key", key), zap.Error(err))
+		return 0, ErrExportQuotaUnavailable
+	}
+
+	over := int(total) - s.cfg.DailyRowQuota
+	if over <= 0 {
+		return limit, nil
+	}
+	if over > limit {
+		over = limit
+	}
+	s.refundQuota(ctx, key, over)
+	if over == limit {
+		return 0, ErrExportQuotaExceeded
+	}
+	return limit - over, nil
+}
+
+// run пишет выгрузку в w. Выгруженные строки остаются списанными с квоты в
+// любом случае, в том числе при ошибке или обрыве соединения: клиент уже
+// получил их. Невыгруженный остаток резерва возвращается.
+func (s *ExportService) run(ctx context.Context, plan *ExportPlan, w io.Writer, exports prometheus.Counter, export func(clickhouse.ExportRowFunc) (int, error)) (count int, err error) {
+	start := time.Now()
+	defer func() {
+		// ctx запроса может быть уже отменен - счетчик обновляется отдельно
+		if plan.quotaKey != "" {
+			s.refundQuota(context.WithoutCancel(ctx), plan.quotaKey, plan.Limit-count)
+		}
+	}()
+
+	sw, err := spreadsheet.NewWriter(w, plan.Request.Format)
+	if err != nil {
+		return 0, err
+	}
+
+	header := make([]string, len(plan.Columns))
+	for i, c := range plan.Columns {
+		header[i] = c.Header
+	}
+	if err := sw.WriteRow(header); err != nil {
+		return 0, err
+	}
+
+	count, err = export(sw.WriteRow)
+	if err != nil {
+		s.logger.Error("export failed",
+			zap.String("user_id", plan.Request.UserID),
+			zap.Int("rows", count),
+			zap.Error(err),
+		)
+		return count, err
+	}
+	if err := sw.Close(); err != nil {
+		return count, err
+	}
+
+	exports.Inc()
+
+	s.logger.Info("export completed",
+		zap.String("user_id", plan.Request.UserID),
+		zap.String("format", string(plan.Request.Format)),
+		zap.Int("rows", count),
+		zap.Duration("duration", time.Since(start)),
+	)
+	return count, nil
+}
+
+// quotaKey ключ счетчика выгруженных строк пользователя за сутки now (UTC,
+// как суточная квота API ключей)
+func quotaKey(userID string, now time.Time) string {
+	return fmt.Sprintf("export:quota:%s:%s", userID, now.UTC().Format("2006-01-02"))
+}
+
+// refundQuota возвращает в квоту rows зарезервированных строк
+func (s *ExportService) refundQuota(ctx context.Context, key string, rows int) {
+	if rows <= 0 {
+		return
+	}
+	if _, err := s.cache.IncrBy(ctx, key, -int64(rows), exportQuotaTTL); err != nil {
+		s.logger.Warn("failed to refund export quota", zap.String("key", key), zap.Int("rows", rows), zap.Error(err))
+	}
+}
@@ -0,0 +1,118 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/egrul-system/services/api-gateway/internal/config"
+	"github.com/egrul-system/services/api-gateway/internal/repository/clickhouse"
+	"github.com/egrul-system/services/api-gateway/internal/spreadsheet"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// counterCache кэш в памяти, поддерживающий только счетчики; err имитирует
+// недоступность Redis
+type counterCache struct {
+	counters map[string]int64
+	err      error
+}
+
+func (c *counterCache) Get(_ context.Context, key string, dest interface{}) (bool, error) {
+	v, ok := c.counters[key]
+	if ok {
+		*dest.(*int) = int(v)
+	}
+	return ok, nil
+}
+
+func (c *counterCache) Set(context.Context, string, interface{}, time.Duration) error { return nil }
+func (c *counterCache) Delete(context.Context, string) error                          { return nil }
+
+func (c *counterCache) Incr(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	return c.IncrBy(ctx, key, 1, ttl)
+}
+
+func (c *counterCache) IncrBy(_ context.Context, key string, n int64, _ time.Duration) (int64, error) {
+	if c.err != nil {
+		return 0, c.err
+	}
+	c.counters[key] += n
+	return c.counters[key], nil
+}
+
+func (c *counterCache) TakeToken(context.Context, string, float64, int) (bool, time.Duration, error) {
+	return true, 0, nil
+}
+
+func (c *counterCache) Close() error { return nil }
+
+func newQuotaExportService(cache *counterCache, quota int) *ExportService {
+	return NewExportService(nil, nil, cache, config.ExportConfig{MaxRows: 100, DailyRowQuota: quota}, zap.NewNop())
+}
+
+func TestExportService_ChargesQuotaForInterruptedExport(t *testing.T) {
+	// Arrange: клиент оборвал соединение после двух строк
+	cache := &counterCache{counters: map[string]int64{}}
+	svc := newQuotaExportService(cache, 1000)
+	plan, err := svc.plan(context.Background(), ExportRequest{UserID: "user-1", Format: spreadsheet.FormatCSV}, clickhouse.CompanyExportColumns)
+	require.NoError(t, err)
+	require.Equal(t, 100, plan.Limit)
+	ctx, cancel := context.WithCancel(context.Background())
+	counter := prometheus.NewCounter(prometheus.CounterOpts{Name: "test_exports_total"})
+
+	// Act
+	count, err := svc.run(ctx, plan, &bytes.Buffer{}, counter, func(fn clickhouse.ExportRowFunc) (int, error) {
+		for i := 0; i < 2; i++ {
+			if err := fn([]string{"1027700132195"}); err != nil {
+				return i, err
+			}
+		}
+		cancel()
+		return 2, errors.New("client disconnected")
+	})
+
+	// Assert: выгруженные строки списаны с квоты, остаток резерва возвращен
+	require.Error(t, err)
+	assert.Equal(t, 2, count)
+	assert.Equal(t, int64(2), cache.counters[quotaKey("user-1", time.Now())])
+}
+
+func TestExportService_ReservesQuotaForParallelExports(t *testing.T) {
+	// Arrange: квота 150 строк, каждая выгрузка просит до 100
+	cache := &counterCache{counters: map[string]int64{}}
+	svc := newQuotaExportService(cache, 150)
+	req := ExportRequest{UserID: "user-1", Format: spreadsheet.FormatCSV}
+	ctx := context.Background()
+
+	// Act: три выгрузки планируются до того, как первая начала писать строки
+	first, err := svc.plan(ctx, req, clickhouse.CompanyExportColumns)
+	require.NoError(t, err)
+	second, err := svc.plan(ctx, req, clickhouse.CompanyExportColumns)
+	require.NoError(t, err)
+	_, thirdErr := svc.plan(ctx, req, clickhouse.CompanyExportColumns)
+
+	// Assert
+	assert.Equal(t, 100, first.Limit)
+	assert.Equal(t, 50, second.Limit, "вторая выгрузка получает остаток квоты")
+	assert.ErrorIs(t, thirdErr, ErrExportQuotaExceeded)
+	assert.Equal(t, int64(150), cache.counters[quotaKey("user-1", time.Now())], "отклоненный резерв возвращен")
+}
+
+func TestExportService_RejectsExportWhenQuotaUnavailable(t *testing.T) {
+	// Arrange
+	cache := &counterCache{counters: map[string]int64{}, err: errors.New("redis: connection refused")}
+	svc := newQuotaExportService(cache, 1000)
+
+	// Act
+	plan, err := svc.plan(context.Background(), ExportRequest{UserID: "user-1", Format: spreadsheet.FormatCSV}, clickhouse.CompanyExportColumns)
+
+	// Assert
+	assert.Nil(t, plan)
+	assert.ErrorIs(t, err, ErrExportQuotaUnavailable)
+}
@@ -0,0 +1,429 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/egrul-system/services/api-gateway/internal/graph/model"
+	"github.com/egrul-system/services/api-gateway/internal/repository/postgresql"
+	"github.com/egrul-system/services/api-gateway/internal/spreadsheet"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// ErrInvalidFavoriteParams возвращается при некорректной папке, тегах или списке записей
+var ErrInvalidFavoriteParams = errors.New("invalid favorite parameters")
+
+const (
+	maxFavoriteFolderLength = 100
+	maxFavoriteTagLength    = 50
+	maxFavoriteTags         = 20
+	// maxFavoriteImportItems ограничивает пакетное добавление и импорт файла
+	maxFavoriteImportItems = 1000
+	// maxFavoriteBatchIDs ограничивает перенос и удаление одним запросом
+	maxFavoriteBatchIDs = 1000
+)
+
+// FavoriteImportOptions параметры пакетного добавления в избранное
+type FavoriteImportOptions struct {
+	Folder *string
+	Tags   []string
+	// Subscribe создает подписку на изменения для каждой найденной записи
+	Subscribe bool
+}
+
+// FavoriteStore хранилище личного избранного. Реализуется
+// postgresql.FavoriteRepository.
+type FavoriteStore interface {
+	GetByUserID(ctx context.Context, userID string, filter postgresql.FavoriteFilter) ([]*model.Favorite, error)
+	GetFolders(ctx context.Context, userID string) ([]*model.FavoriteFolder, error)
+	GetTags(ctx context.Context, userID string) ([]string, error)
+	HasFavorite(ctx context.Context, userID, entityType, entityID string) (bool, error)
+	Create(ctx context.Context, favorite *model.Favorite) error
+	Update(ctx context.Context, favorite *model.Favorite) error
+	MoveToFolder(ctx context.Context, userID string, ids []string, folder *string) (int, error)
+	DeleteMany(ctx context.Context, userID string, ids []string) (int, error)
+}
+
+// FavoriteService управляет папками и тегами личного избранного и пакетными
+// операциями над ним
+type FavoriteService struct {
+	repo                FavoriteStore
+	subscriptionRepo    *postgresql.SubscriptionRepository
+	userRepo            *postgresql.UserRepository
+	companyService      *CompanyService
+	entrepreneurService *EntrepreneurService
+	logger              *zap.Logger
+}
+
+// NewFavoriteService создает новый сервис избранного
+func NewFavoriteService(
+	repo FavoriteStore,
+	subscriptionRepo *postgresql.SubscriptionRepository,
+	userRepo *postgresql.UserRepository,
+	companyService *CompanyService,
+	entrepreneurService *EntrepreneurService,
+	logger *zap.Logger,
+) *FavoriteService {
+	return &FavoriteService{
+		repo:                repo,
+		subscriptionRepo:    subscriptionRepo,
+		userRepo:            userRepo,
+		companyService:      companyService,
+		entrepreneurService: entrepreneurService,
+		logger:              logger.Named("favorite_service"),
+	}
+}
+
+// List личное избранное с фильтром по папке ("" - без папки) и тегу
+func (s *FavoriteService) List(ctx context.Context, userID string, folder, tag *string) ([]*model.Favorite, error) {
+	var filter postgresql.FavoriteFilter
+	if folder != nil {
+		normalized := strings.TrimSpace(*folder)
+		filter.Folder = &normalized
+	}
+	if tag != nil {
+		normalized := normalizeFavoriteTag(*tag)
+		filter.Tag = &normalized
+	}
+	return s.repo.GetByUserID(ctx, userID, filter)
+}
+
+// Folders папки личного избранного
+func (s *FavoriteService) Folders(ctx context.Context, userID string) ([]*model.FavoriteFolder, error) {
+	return s.repo.GetFolders(ctx, userID)
+}
+
+// Tags теги личного избранного
+func (s *FavoriteService) Tags(ctx context.Context, userID string) ([]string, error) {
+	return s.repo.GetTags(ctx, userID)
+}
+
+// SetTags заменяет теги записи. Проверку доступа к записи выполняет вызывающий.
+func (s *FavoriteService) SetTags(ctx context.Context, favorite *model.Favorite, tags []string) error {
+	normalized, err := NormalizeFavoriteTags(tags)
+	if err != nil {
+		return err
+	}
+	favorite.Tags = normalized
+	return s.repo.Update(ctx, favorite)
+}
+
+// Move переносит записи личного избранного в папку (nil или "" - убрать из папки)
+func (s *FavoriteService) Move(ctx context.Context, userID string, ids []string, folder *string) (int, error) {
+	if err := validateFavoriteIDs(ids); err != nil {
+		return 0, err
+	}
+	normalized, err := NormalizeFavoriteFolder(folder)
+	if err != nil {
+		return 0, err
+	}
+	return s.repo.MoveToFolder(ctx, userID, ids, normalized)
+}
+
+// DeleteMany удаляет записи личного избранного
+func (s *FavoriteService) DeleteMany(ctx context.Context, userID string, ids []string) (int, error) {
+	if err := validateFavoriteIDs(ids); err != nil {
+		return 0, err
+	}
+	return s.repo.DeleteMany(ctx, userID, ids)
+}
+
+// ImportFile добавляет в избранное контрагентов из CSV/XLSX файла: из каждой
+// строки берется первая ячейка с ИНН/ОГРН/ОГРНИП
+func (s *FavoriteService) ImportFile(ctx context.Context, userID string, data []byte, opts FavoriteImportOptions) (*model.FavoriteImportResult, error) {
+	rows, err := spreadsheet.ReadRows(data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: parse file: %v", ErrInvalidFavoriteParams, err)
+	}
+
+	identifiers := ExtractIdentifiers(rows)
+	if len(identifiers) == 0 {
+		return nil, fmt.Errorf("%w: file contains no INN/OGRN values", ErrInvalidFavoriteParams)
+	}
+	return s.AddMany(ctx, userID, identifiers, opts)
+}
+
+// AddMany добавляет в личное избранное контрагентов по списку ИНН/ОГРН/ОГРНИП.
+// Идентификаторы разрешаются через реестр; уже добавленные записи не
+// изменяются, но при opts.Subscribe на них тоже оформляется подписка.
+func (s *FavoriteService) AddMany(ctx context.Context, userID string, identifiers []string, opts FavoriteImportOptions) (*model.FavoriteImportResult, error) {
+	if len(identifiers) == 0 {
+		return nil, fmt.Errorf("%w: identifiers are required", ErrInvalidFavoriteParams)
+	}
+	if len(identifiers) > maxFavoriteImportItems {
+		return nil, fmt.Errorf("%w: too many identifiers: %d (max %d)", ErrInvalidFavoriteParams, len(identifiers), maxFavoriteImportItems)
+	}
+
+	folder, err := NormalizeFavoriteFolder(opts.Folder)
+	if err != nil {
+		return nil, err
+	}
+	tags, err := NormalizeFavoriteTags(opts.Tags)
+	if err != nil {
+		return nil, err
+	}
+
+	// Email нужен для записи подписки
+	var userEmail string
+	if opts.Subscribe {
+		user, err := s.userRepo.GetByID(ctx, userID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get user: %w", err)
+		}
+		if user == nil {
+			return nil, ErrUserNotFound
+		}
+		userEmail = user.Email
+	}
+
+	result := &model.FavoriteImportResult{Items: make([]*model.FavoriteImportItem, 0, len(identifiers))}
+	seen := make(map[string]bool)
+
+	for _, input := range identifiers {
+		item := &model.FavoriteImportItem{Input: input}
+		result.Items = append(result.Items, item)
+
+		identifier := normalizeIdentifier(input)
+		if identifier == "" {
+			item.Status = model.FavoriteImportStatusInvalid
+			continue
+		}
+
+		entityType, entityID, entityName, err := s.resolveEntity(ctx, identifier)
+		if err != nil {
+			s.logger.Warn("failed to resolve favorite identifier", zap.String("identifier", identifier), zap.Error(err))
+			item.Status = model.FavoriteImportStatusFailed
+			item.Error = importError(favoriteImportLookupFailed)
+			continue
+		}
+		if entityID == "" {
+			item.Status = model.FavoriteImportStatusNotFound
+			continue
+		}
+		item.EntityType = &entityType
+		item.EntityID = &entityID
+		item.EntityName = &entityName
+
+		// ИНН и ОГРН одной компании в списке дают одну запись
+		key := string(entityType) + ":" + entityID
+		if seen[key] {
+			item.Status = model.FavoriteImportStatusExists
+			continue
+		}
+		seen[key] = true
+
+		if err := s.addOne(ctx, userID, item, folder, tags); err != nil {
+			s.logger.Warn("failed to add imported favorite",
+				zap.String("user_id", userID),
+				zap.String("entity_id", entityID),
+				zap.Error(err),
+			)
+			item.Status = model.FavoriteImportStatusFailed
+			item.Error = importError(favoriteImportAddFailed)
+			continue
+		}
+
+		if opts.Subscribe {
+			subscribed, err := s.subscribe(ctx, userID, userEmail, entityType, entityID, entityName)
+			if err != nil {
+				s.logger.Warn("failed to subscribe imported favorite",
+					zap.String("user_id", userID),
+					zap.String("entity_id", entityID),
+					zap.Error(err),
+				)
+				item.Error = importError(favoriteImportSubscriptionFailed)
+			}
+			item.Subscribed = subscribed
+		}
+	}
+
+	for _, item := range result.Items {
+		switch item.Status {
+		case model.FavoriteImportStatusAdded:
+			result.Added++
+		case model.FavoriteImportStatusExists:
+			result.Existing++
+		case model.FavoriteImportStatusNotFound:
+			result.NotFound++
+		case model.FavoriteImportStatusInvalid:
+			result.Invalid++
+		case model.FavoriteImportStatusFailed:
+			result.Failed++
+		}
+		if item.Subscribed {
+			result.Subscribed++
+		}
+	}
+	result.Total = len(result.Items)
+
+	s.logger.Info("favorites imported",
+		zap.String("user_id", userID),
+		zap.Int("total", result.Total),
+		zap.Int("added", result.Added),
+		zap.Int("subscribed", result.Subscribed),
+	)
+
+	return result, nil
+}
+
+// addOne создает запись избранного или отмечает, что она уже есть
+func (s *FavoriteService) addOne(ctx context.Context, userID string, item *model.FavoriteImportItem, folder *string, tags []string) error {
+	exists, err := s.repo.HasFavorite(ctx, userID, string(*item.EntityType), *item.EntityID)
+	if err != nil {
+		return err
+	}
+	if exists {
+		item.Status = model.FavoriteImportStatusExists
+		return nil
+	}
+
+	favorite := &model.Favorite{
+		UserID:     userID,
+		EntityType: *item.EntityType,
+		EntityID:   *item.EntityID,
+		EntityName: *item.EntityName,
+		Folder:     folder,
+		Tags:       tags,
+	}
+	if err := s.repo.Create(ctx, favorite); err != nil {
+		return err
+	}
+
+	item.Status = model.FavoriteImportStatusAdded
+	item.FavoriteID = &favorite.ID
+	return nil
+}
+
+// subscribe создает личную подписку с настройками по умолчанию; false - подписка уже была
+func (s *FavoriteService) subscribe(ctx context.Context, userID, userEmail string, entityType model.EntityType, entityID, entityName string) (bool, error) {
+	exists, err := s.subscriptionRepo.HasSubscription(ctx, userID, string(entityType), entityID)
+	if err != nil || exists {
+		return false, err
+	}
+
+	var filters *model.ChangeFiltersInput
+	var channels *model.NotificationChannelsInput
+	subscription := &model.EntitySubscription{
+		UserID:               userID,
+		UserEmail:            userEmail,
+		EntityType:           entityType,
+		EntityID:             entityID,
+		EntityName:           entityName,
+		ChangeFilters:        filters.ToChangeFilters(),
+		NotificationChannels: channels.ToNotificationChannels(),
+		IsActive:             true,
+	}
+	if err := s.subscriptionRepo.Create(ctx, subscription); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// resolveEntity находит компанию или ИП по ИНН/ОГРН/ОГРНИП. Пустой entityID -
+// контрагент не найден в реестре.
+func (s *FavoriteService) resolveEntity(ctx context.Context, identifier string) (model.EntityType, string, string, error) {
+	switch len(identifier) {
+	case 10, 13:
+		var company *model.Company
+		var err error
+		if len(identifier) == 10 {
+			company, err = s.companyService.GetByINN(ctx, identifier)
+		} else {
+			company, err = s.companyService.GetByOGRN(ctx, identifier)
+		}
+		if err != nil || company == nil {
+			return "", "", "", err
+		}
+		name := company.FullName
+		if company.ShortName != nil && *company.ShortName != "" {
+			name = *company.ShortName
+		}
+		return model.EntityTypeCompany, company.Ogrn, name, nil
+
+	case 12, 15:
+		var entrepreneur *model.Entrepreneur
+		var err error
+		if len(identifier) == 12 {
+			entrepreneur, err = s.entrepreneurService.GetByINN(ctx, identifier)
+		} else {
+			entrepreneur, err = s.entrepreneurService.GetByOGRNIP(ctx, identifier)
+		}
+		if err != nil || entrepreneur == nil {
+			return "", "", "", err
+		}
+		return model.EntityTypeEntrepreneur, entrepreneur.Ogrnip,
+			formatPersonName(entrepreneur.LastName, entrepreneur.FirstName, entrepreneur.MiddleName), nil
+	}
+	return "", "", "", nil
+}
+
+// NormalizeFavoriteFolder обрезает пробелы; пустая папка означает "без папки"
+func NormalizeFavoriteFolder(folder *string) (*string, error) {
+	if folder == nil {
+		return nil, nil
+	}
+	name := strings.TrimSpace(*folder)
+	if name == "" {
+		return nil, nil
+	}
+	if utf8.RuneCountInString(name) > maxFavoriteFolderLength {
+		return nil, fmt.Errorf("%w: folder must be at most %d characters", ErrInvalidFavoriteParams, maxFavoriteFolderLength)
+	}
+	return &name, nil
+}
+
+// NormalizeFavoriteTags приводит теги к нижнему регистру, удаляет пустые и повторы
+func NormalizeFavoriteTags(tags []string) ([]string, error) {
+	normalized := make([]string, 0, len(tags))
+	seen := make(map[string]bool)
+	for _, tag := range tags {
+		tag = normalizeFavoriteTag(tag)
+		if tag == "" || seen[tag] {
+			continue
+		}
+		if utf8.RuneCountInString(tag) > maxFavoriteTagLength {
+			return nil, fmt.Errorf("%w: tag must be at most %d characters", ErrInvalidFavoriteParams, maxFavoriteTagLength)
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	if len(normalized) > maxFavoriteTags {
+		return nil, fmt.Errorf("%w: at most %d tags are allowed", ErrInvalidFavoriteParams, maxFavoriteTags)
+	}
+	return normalized, nil
+}
+
+func normalizeFavoriteTag(tag string) string {
+	return strings.ToLower(strings.Join(strings.Fields(tag), " "))
+}
+
+func validateFavoriteIDs(ids []string) error {
+	if len(ids) == 0 {
+		return fmt.Errorf("%w: ids are required", ErrInvalidFavoriteParams)
+	}
+	if len(ids) > maxFavoriteBatchIDs {
+		return fmt.Errorf("%w: too many ids: %d (max %d)", ErrInvalidFavoriteParams, len(ids), maxFavoriteBatchIDs)
+	}
+	for _, id := range ids {
+		if _, err := uuid.Parse(id); err != nil {
+			return fmt.Errorf("%w: invalid id %q", ErrInvalidFavoriteParams, id)
+		}
+	}
+	return nil
+}
+
+// Ошибки отдельных записей импорта. Клиент получает только эти сообщения,
+// подробности ошибок БД и реестра пишутся в лог.
+const (
+	favoriteImportLookupFailed       = "lookup failed"
+	favoriteImportAddFailed          = "add failed"
+	favoriteImportSubscriptionFailed = "subscription failed"
+)
+
+func importError(msg string) *string {
+	return &msg
+}
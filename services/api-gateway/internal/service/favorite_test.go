@@ -0,0 +1,176 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/egrul-system/services/api-gateway/internal/graph/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestNormalizeFavoriteTags(t *testing.T) {
+	tags, err := NormalizeFavoriteTags([]string{" Поставщики ", "поставщики", "", "ТОП  10", "  "})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"поставщики", "топ 10"}, tags)
+
+	_, err = NormalizeFavoriteTags([]string{strings.Repeat("t", maxFavoriteTagLength+1)})
+	assert.ErrorIs(t, err, ErrInvalidFavoriteParams)
+
+	tooMany := make([]string, maxFavoriteTags+1)
+	for i := range tooMany {
+		tooMany[i] = strings.Repeat("t", i+1)
+	}
+	_, err = NormalizeFavoriteTags(tooMany)
+	assert.ErrorIs(t, err, ErrInvalidFavoriteParams)
+}
+
+func TestNormalizeFavoriteFolder(t *testing.T) {
+	folder, err := NormalizeFavoriteFolder(nil)
+	require.NoError(t, err)
+	assert.Nil(t, folder)
+
+	blank := "   "
+	folder, err = NormalizeFavoriteFolder(&blank)
+	require.NoError(t, err)
+	assert.Nil(t, folder, "пустая папка означает запись без папки")
+
+	name := "  Контрагенты 2026 "
+	folder, err = NormalizeFavoriteFolder(&name)
+	require.NoError(t, err)
+	assert.Equal(t, "Контрагенты 2026", *folder)
+
+	long := strings.Repeat("п", maxFavoriteFolderLength+1)
+	_, err = NormalizeFavoriteFolder(&long)
+	assert.ErrorIs(t, err, ErrInvalidFavoriteParams)
+}
+
+func TestFavoriteService_RejectsInvalidParamsBeforeQueryingDB(t *testing.T) {
+	// Arrange: без репозиториев - проверки должны срабатывать до обращения к БД
+	svc := NewFavoriteService(nil, nil, nil, nil, nil, zap.NewNop())
+	ctx := context.Background()
+
+	// Act & Assert
+	_, err := svc.Move(ctx, "user-1", nil, nil)
+	assert.ErrorIs(t, err, ErrInvalidFavoriteParams)
+
+	_, err = svc.DeleteMany(ctx, "user-1", []string{"not-a-uuid"})
+	assert.ErrorIs(t, err, ErrInvalidFavoriteParams)
+
+	_, err = svc.AddMany(ctx, "user-1", make([]string, maxFavoriteImportItems+1), FavoriteImportOptions{})
+	assert.ErrorIs(t, err, ErrInvalidFavoriteParams)
+
+	_, err = svc.ImportFile(ctx, "user-1", []byte("Наименование;Комментарий\nООО Ромашка;нет ИНН\n"), FavoriteImportOptions{})
+	assert.ErrorIs(t, err, ErrInvalidFavoriteParams)
+}
+
+func TestFavoriteService_AddManyMarksInvalidIdentifiers(t *testing.T) {
+	// Arrange: значения не похожи на ИНН/ОГРН - реестр и БД не запрашиваются
+	svc := NewFavoriteService(nil, nil, nil, nil, nil, zap.NewNop())
+
+	// Act
+	result, err := svc.AddMany(context.Background(), "user-1", []string{"abc", "12345", "7707083893-x"}, FavoriteImportOptions{})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.Total)
+	assert.Equal(t, 3, result.Invalid)
+	assert.Zero(t, result.Added)
+	for _, item := range result.Items {
+		assert.Equal(t, model.FavoriteImportStatusInvalid, item.Status)
+	}
+}
+
+// memFavoriteStore личное избранное в памяти; методы, не нужные AddMany,
+// не реализованы
+type memFavoriteStore struct {
+	FavoriteStore
+	favorites map[string]*model.Favorite
+	createErr map[string]error
+}
+
+func newMemFavoriteStore() *memFavoriteStore {
+	return &memFavoriteStore{favorites: map[string]*model.Favorite{}, createErr: map[string]error{}}
+}
+
+func (m *memFavoriteStore) HasFavorite(_ context.Context, userID, entityType, entityID string) (bool, error) {
+	_, ok := m.favorites[userID+":"+entityType+":"+entityID]
+	return ok, nil
+}
+
+func (m *memFavoriteStore) Create(_ context.Context, favorite *model.Favorite) error {
+	if err := m.createErr[favorite.EntityID]; err != nil {
+		return err
+	}
+	favorite.ID = fmt.Sprintf("fav-%d", len(m.favorites)+1)
+	m.favorites[favorite.UserID+":"+string(favorite.EntityType)+":"+favorite.EntityID] = favorite
+	return nil
+}
+
+func TestFavoriteService_AddMany(t *testing.T) {
+	// Arrange
+	companyRepo := new(MockCompanyRepository)
+	sber := &model.Company{Ogrn: "1027700132195", Inn: "7707083893", FullName: "ПАО СБЕРБАНК"}
+	gazprom := &model.Company{Ogrn: "1027700070518", Inn: "7736050003", FullName: "ПАО ГАЗПРОМ"}
+	broken := &model.Company{Ogrn: "1027739609391", Inn: "7702070139", FullName: "БАНК ВТБ (ПАО)"}
+	companyRepo.On("GetByINN", mock.Anything, "7707083893").Return(sber, nil)
+	companyRepo.On("GetByOGRN", mock.Anything, "1027700132195").Return(sber, nil)
+	companyRepo.On("GetByINN", mock.Anything, "7736050003").Return(gazprom, nil)
+	companyRepo.On("GetByINN", mock.Anything, "7702070139").Return(broken, nil)
+	companyRepo.On("GetByINN", mock.Anything, "7700000001").Return(nil, nil)
+	companyRepo.On("GetByINN", mock.Anything, "7700000002").Return(nil, errors.New("clickhouse: dial tcp 10.0.0.5:9000: connection refused"))
+
+	store := newMemFavoriteStore()
+	store.favorites["user-1:COMPANY:1027700070518"] = &model.Favorite{ID: "fav-0", UserID: "user-1"}
+	store.createErr["1027739609391"] = errors.New(`pq: duplicate key value violates unique constraint "favorites_user_entity_key"`)
+
+	companies := NewCompanyService(companyRepo, nil, nil, nil, nil, zap.NewNop())
+	svc := NewFavoriteService(store, nil, nil, companies, nil, zap.NewNop())
+
+	// Act
+	result, err := svc.AddMany(context.Background(), "user-1", []string{
+		"7707083893",    // новая запись
+		"1027700132195", // ОГРН той же компании
+		"7736050003",    // уже в избранном
+		"7700000001",    // нет в реестре
+		"7700000002",    // ошибка реестра
+		"7702070139",    // ошибка БД
+	}, FavoriteImportOptions{})
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, result.Items, 6)
+
+	added := result.Items[0]
+	assert.Equal(t, model.FavoriteImportStatusAdded, added.Status)
+	require.NotNil(t, added.FavoriteID)
+	assert.Equal(t, "ПАО СБЕРБАНК", store.favorites["user-1:COMPANY:1027700132195"].EntityName)
+
+	assert.Equal(t, model.FavoriteImportStatusExists, result.Items[1].Status, "ИНН и ОГРН одной компании дают одну запись")
+	assert.Nil(t, result.Items[1].FavoriteID)
+	assert.Equal(t, model.FavoriteImportStatusExists, result.Items[2].Status)
+	assert.Equal(t, model.FavoriteImportStatusNotFound, result.Items[3].Status)
+
+	lookupFailed := result.Items[4]
+	assert.Equal(t, model.FavoriteImportStatusFailed, lookupFailed.Status)
+	require.NotNil(t, lookupFailed.Error)
+	assert.Equal(t, "lookup failed", *lookupFailed.Error)
+
+	addFailed := result.Items[5]
+	assert.Equal(t, model.FavoriteImportStatusFailed, addFailed.Status)
+	require.NotNil(t, addFailed.Error)
+	assert.Equal(t, "add failed", *addFailed.Error)
+
+	assert.Equal(t, 6, result.Total)
+	assert.Equal(t, 1, result.Added)
+	assert.Equal(t, 2, result.Existing)
+	assert.Equal(t, 1, result.NotFound)
+	assert.Equal(t, 2, result.Failed)
+	assert.Len(t, store.favorites, 2)
+	companyRepo.AssertExpectations(t)
+}
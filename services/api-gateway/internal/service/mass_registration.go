@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/egrul-system/services/api-gateway/internal/graph/model"
+)
+
+// Ограничения выборки списков массовых адресов и руководителей
+const (
+	defaultMassListLimit = 50
+	maxMassListLimit     = 500
+)
+
+// MassAddress возвращает агрегат по адресу регистрации компании.
+// Возвращает nil, если адреса нет или агрегаты еще не рассчитаны.
+func (s *RiskService) MassAddress(ctx context.Context, company *model.Company) (*model.MassAddress, error) {
+	if company == nil || company.Address == nil {
+		return nil, nil
+	}
+	if derefString(company.Address.FiasID) == "" && derefString(company.Address.FullAddress) == "" {
+		return nil, nil
+	}
+
+	address, err := s.repo.GetMassAddressByCompany(ctx, company.Ogrn)
+	if err != nil {
+		return nil, fmt.Errorf("get mass address: %w", err)
+	}
+	if address != nil {
+		address.IsMass = address.CompaniesCount >= s.cfg.MassAddressThreshold
+	}
+	return address, nil
+}
+
+// MassDirector возвращает агрегат по руководителю. Возвращает nil, если ИНН
+// руководителя неизвестен или агрегаты еще не рассчитаны.
+func (s *RiskService) MassDirector(ctx context.Context, director *model.Person) (*model.MassDirector, error) {
+	if director == nil || derefString(director.Inn) == "" {
+		return nil, nil
+	}
+
+	massDirector, err := s.repo.GetMassDirector(ctx, *director.Inn)
+	if err != nil {
+		return nil, fmt.Errorf("get mass director: %w", err)
+	}
+	if massDirector != nil {
+		massDirector.IsMass = massDirector.CompaniesCount >= s.cfg.MassDirectorThreshold
+	}
+	return massDirector, nil
+}
+
+// ListMassAddresses возвращает адреса массовой регистрации. Если minCount не задан,
+// используется порог risk.mass_address_threshold.
+func (s *RiskService) ListMassAddresses(ctx context.Context, regionCode *string, minCount, limit, offset *int) ([]*model.MassAddress, error) {
+	threshold := massMinCount(minCount, s.cfg.MassAddressThreshold)
+	l, o := massListPage(limit, offset)
+
+	addresses, err := s.repo.ListMassAddresses(ctx, regionCode, threshold, l, o)
+	if err != nil {
+		return nil, fmt.Errorf("list mass addresses: %w", err)
+	}
+	for _, a := range addresses {
+		a.IsMass = a.CompaniesCount >= s.cfg.MassAddressThreshold
+	}
+	if addresses == nil {
+		addresses = []*model.MassAddress{}
+	}
+	return addresses, nil
+}
+
+// ListMassDirectors возвращает массовых руководителей. Если minCount не задан,
+// используется порог risk.mass_director_threshold.
+func (s *RiskService) ListMassDirectors(ctx context.Context, minCount, limit, offset *int) ([]*model.MassDirector, error) {
+	threshold := massMinCount(minCount, s.cfg.MassDirectorThreshold)
+	l, o := massListPage(limit, offset)
+
+	directors, err := s.repo.ListMassDirectors(ctx, threshold, l, o)
+	if err != nil {
+		return nil, fmt.Errorf("list mass directors: %w", err)
+	}
+	for _, d := range directors {
+		d.IsMass = d.CompaniesCount >= s.cfg.MassDirectorThreshold
+	}
+	if directors == nil {
+		directors = []*model.MassDirector{}
+	}
+	return directors, nil
+}
+
+func massMinCount(minCount *int, threshold int) int {
+	if minCount != nil && *minCount > 0 {
+		return *minCount
+	}
+	return threshold
+}
+
+func massListPage(limit, offset *int) (int, int) {
+	l := defaultMassListLimit
+	if limit != nil && *limit > 0 {
+		l = *limit
+	}
+	if l > maxMassListLimit {
+		l = maxMassListLimit
+	}
+	o := 0
+	if offset != nil && *offset > 0 {
+		o = *offset
+	}
+	return l, o
+}
@@ -0,0 +1,199 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/egrul-system/services/api-gateway/internal/graph/model"
+	"github.com/egrul-system/services/api-gateway/internal/repository"
+	"go.uber.org/zap"
+)
+
+// ErrInvalidPersonINN ИНН физического лица должен состоять из 12 цифр
+var ErrInvalidPersonINN = errors.New("person inn must be 12 digits")
+
+// Ограничения выборок профиля и поиска физических лиц
+const (
+	maxPersonRoles           = 500
+	defaultPersonCandidates  = 20
+	maxPersonCandidates      = 100
+	minPersonSearchNameRunes = 3
+)
+
+// PersonService собирает роли физического лица по ИНН: руководство, участие,
+// статус ИП и историю ролей по сохраненным версиям
+type PersonService struct {
+	repo             repository.PersonRepository
+	entrepreneurRepo repository.EntrepreneurRepository
+	logger           *zap.Logger
+}
+
+// NewPersonService создает новый сервис физических лиц
+func NewPersonService(repo repository.PersonRepository, entrepreneurRepo repository.EntrepreneurRepository, logger *zap.Logger) *PersonService {
+	return &PersonService{
+		repo:             repo,
+		entrepreneurRepo: entrepreneurRepo,
+		logger:           logger.Named("person_service"),
+	}
+}
+
+// GetProfile возвращает профиль лица по ИНН. Профиль возвращается и для ИНН,
+// не найденного ни в одном реестре, — с пустыми списками ролей.
+func (s *PersonService) GetProfile(ctx context.Context, inn string) (*model.PersonProfile, error) {
+	inn = strings.TrimSpace(inn)
+	if len(inn) != 12 || strings.Trim(inn, "0123456789") != "" {
+		return nil, ErrInvalidPersonINN
+	}
+
+	directorships, directorName, err := s.repo.GetDirectorships(ctx, inn, maxPersonRoles)
+	if err != nil {
+		return nil, fmt.Errorf("get directorships: %w", err)
+	}
+	foundings, founderName, err := s.repo.GetFoundings(ctx, inn, maxPersonRoles)
+	if err != nil {
+		return nil, fmt.Errorf("get foundings: %w", err)
+	}
+	entrepreneur, err := s.entrepreneurRepo.GetByINN(ctx, inn)
+	if err != nil {
+		return nil, fmt.Errorf("get entrepreneur: %w", err)
+	}
+
+	directorPoints, err := s.repo.GetDirectorVersions(ctx, inn)
+	if err != nil {
+		return nil, fmt.Errorf("get director versions: %w", err)
+	}
+	founderPoints, err := s.repo.GetFounderVersions(ctx, inn)
+	if err != nil {
+		return nil, fmt.Errorf("get founder versions: %w", err)
+	}
+	ownership, err := s.repo.GetOwnershipRoles(ctx, inn)
+	if err != nil {
+		return nil, fmt.Errorf("get ownership roles: %w", err)
+	}
+
+	history := buildPersonRoles(model.PersonRoleTypeDirector, directorPoints)
+	history = append(history, buildPersonRoles(model.PersonRoleTypeFounder, founderPoints)...)
+	history = append(history, ownership...)
+	sortPersonRoles(history)
+
+	profile := &model.PersonProfile{
+		Inn:             inn,
+		Directorships:   directorships,
+		Foundings:       foundings,
+		Entrepreneur:    entrepreneur,
+		HistoricalRoles: history,
+	}
+	if profile.Directorships == nil {
+		profile.Directorships = []*model.PersonDirectorship{}
+	}
+	if profile.Foundings == nil {
+		profile.Foundings = []*model.PersonFounding{}
+	}
+
+	switch {
+	case entrepreneur != nil:
+		name := strings.TrimSpace(strings.Join([]string{entrepreneur.LastName, entrepreneur.FirstName, derefString(entrepreneur.MiddleName)}, " "))
+		profile.Name = &name
+	case directorName != "":
+		profile.Name = &directorName
+	case founderName != "":
+		profile.Name = &founderName
+	}
+
+	s.logger.Debug("person profile built",
+		zap.String("inn", inn),
+		zap.Int("directorships", len(profile.Directorships)),
+		zap.Int("foundings", len(profile.Foundings)),
+		zap.Int("history", len(profile.HistoricalRoles)),
+	)
+	return profile, nil
+}
+
+// SearchByName возвращает кандидатов с ФИО, начинающимся с name, различая однофамильцев по ИНН
+func (s *PersonService) SearchByName(ctx context.Context, name string, limit *int) ([]*model.PersonCandidate, error) {
+	name = strings.TrimSpace(name)
+	if len([]rune(name)) < minPersonSearchNameRunes {
+		return nil, fmt.Errorf("name must be at least %d characters", minPersonSearchNameRunes)
+	}
+
+	l := defaultPersonCandidates
+	if limit != nil && *limit > 0 {
+		l = *limit
+	}
+	if l > maxPersonCandidates {
+		l = maxPersonCandidates
+	}
+
+	candidates, err := s.repo.SearchCandidates(ctx, name, l)
+	if err != nil {
+		return nil, fmt.Errorf("search person candidates: %w", err)
+	}
+	if candidates == nil {
+		candidates = []*model.PersonCandidate{}
+	}
+	return candidates, nil
+}
+
+// buildPersonRoles сворачивает последовательные версии карточек каждой компании
+// (points упорядочены по ОГРН и дате) в периоды, когда лицо занимало роль.
+// Период заканчивается датой первой версии без лица; если лицо есть в последней
+// версии, период считается действующим.
+func buildPersonRoles(role model.PersonRoleType, points []*model.PersonRolePoint) []*model.PersonRole {
+	var (
+		roles   []*model.PersonRole
+		current *model.PersonRole
+	)
+	for i, p := range points {
+		if current != nil && current.Ogrn != p.Ogrn {
+			current = nil
+		}
+		switch {
+		case p.Present && current == nil:
+			start := p.Date
+			current = &model.PersonRole{
+				Role:        role,
+				Ogrn:        p.Ogrn,
+				CompanyName: p.CompanyName,
+				StartDate:   &start,
+				Source:      model.PersonRoleSourceVersions,
+			}
+			roles = append(roles, current)
+		case !p.Present && current != nil:
+			end := p.Date
+			current.EndDate = &end
+			current = nil
+		}
+		if current != nil {
+			// Должность и доля — по последней версии периода
+			current.Position = p.Position
+			current.SharePercent = p.SharePercent
+			if p.CompanyName != "" {
+				current.CompanyName = p.CompanyName
+			}
+			if i == len(points)-1 || points[i+1].Ogrn != p.Ogrn {
+				current.IsCurrent = true
+			}
+		}
+	}
+	return roles
+}
+
+// sortPersonRoles упорядочивает роли: сначала действующие, затем по дате начала (новые выше)
+func sortPersonRoles(roles []*model.PersonRole) {
+	sort.SliceStable(roles, func(i, j int) bool {
+		a, b := roles[i], roles[j]
+		if a.IsCurrent != b.IsCurrent {
+			return a.IsCurrent
+		}
+		switch {
+		case a.StartDate == nil:
+			return false
+		case b.StartDate == nil:
+			return true
+		}
+		return a.StartDate.After(b.StartDate.Time)
+	})
+}
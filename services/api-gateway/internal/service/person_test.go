@@ -0,0 +1,53 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/egrul-system/services/api-gateway/internal/graph/model"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func rolePoint(ogrn string, date string, present bool) *model.PersonRolePoint {
+	t, _ := time.Parse("2006-01-02", date)
+	return &model.PersonRolePoint{Ogrn: ogrn, CompanyName: "ООО " + ogrn, Date: model.Date{Time: t}, Present: present}
+}
+
+func TestBuildPersonRoles_Intervals(t *testing.T) {
+	// Arrange: в компании A лицо руководило с 2020 по 2022 и вернулось в 2024,
+	// в компании B руководит по последней версии
+	points := []*model.PersonRolePoint{
+		rolePoint("A", "2019-01-10", false),
+		rolePoint("A", "2020-03-01", true),
+		rolePoint("A", "2021-05-01", true),
+		rolePoint("A", "2022-07-15", false),
+		rolePoint("A", "2024-02-01", true),
+		rolePoint("B", "2023-01-01", true),
+	}
+
+	// Act
+	roles := buildPersonRoles(model.PersonRoleTypeDirector, points)
+
+	// Assert
+	assert.Len(t, roles, 3)
+	assert.Equal(t, "A", roles[0].Ogrn)
+	assert.Equal(t, "2020-03-01", roles[0].StartDate.Format("2006-01-02"))
+	assert.Equal(t, "2022-07-15", roles[0].EndDate.Format("2006-01-02"))
+	assert.False(t, roles[0].IsCurrent)
+	assert.Equal(t, "2024-02-01", roles[1].StartDate.Format("2006-01-02"))
+	assert.Nil(t, roles[1].EndDate)
+	assert.True(t, roles[1].IsCurrent)
+	assert.Equal(t, "B", roles[2].Ogrn)
+	assert.True(t, roles[2].IsCurrent)
+	assert.Equal(t, model.PersonRoleSourceVersions, roles[2].Source)
+}
+
+func TestPersonService_GetProfile_InvalidINN(t *testing.T) {
+	service := NewPersonService(nil, nil, zap.NewNop())
+
+	_, err := service.GetProfile(context.Background(), "7707083893")
+
+	assert.ErrorIs(t, err, ErrInvalidPersonINN)
+}
@@ -0,0 +1,365 @@
+package service
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/egrul-system/services/api-gateway/internal/auth"
+	"github.com/egrul-system/services/api-gateway/internal/config"
+	"github.com/egrul-system/services/api-gateway/internal/graph/model"
+	"github.com/egrul-system/services/api-gateway/internal/repository/postgresql"
+	"go.uber.org/zap"
+)
+
+var (
+	// ErrAccountDeletionScheduled удаление учетной записи уже назначено
+	ErrAccountDeletionScheduled = errors.New("account deletion is already scheduled")
+	// ErrAccountDeletionNotScheduled удаление учетной записи не назначено
+	ErrAccountDeletionNotScheduled = errors.New("account deletion is not scheduled")
+	// ErrReauthenticationRequired пользователь без пароля должен заново войти
+	// через SSO перед удалением учетной записи
+	ErrReauthenticationRequired = errors.New("sign in again to confirm account deletion")
+)
+
+const (
+	// accountPurgeInterval как часто проверять учетные записи, срок удаления которых наступил
+	accountPurgeInterval = time.Hour
+	// accountPurgeBatch сколько учетных записей удалять за один проход
+	accountPurgeBatch = 100
+	// notificationExportPage размер страницы при выгрузке журнала уведомлений
+	notificationExportPage = 500
+)
+
+// PersonalDataExport выгрузка данных, которые система хранит о пользователе
+type PersonalDataExport struct {
+	GeneratedAt   time.Time                   `json:"generatedAt"`
+	Profile       PersonalDataProfile         `json:"profile"`
+	Favorites     []*model.Favorite           `json:"favorites"`
+	Subscriptions []*model.EntitySubscription `json:"subscriptions"`
+	Notifications []PersonalDataNotification  `json:"notifications"`
+	Workspaces    []PersonalDataWorkspace     `json:"workspaces"`
+}
+
+// PersonalDataProfile профиль пользователя в выгрузке
+type PersonalDataProfile struct {
+	ID                  string     `json:"id"`
+	Email               string     `json:"email"`
+	FirstName           string     `json:"firstName"`
+	LastName            string     `json:"lastName"`
+	Role                string     `json:"role"`
+	IsActive            bool       `json:"isActive"`
+	EmailVerified       bool       `json:"emailVerified"`
+	CreatedAt           time.Time  `json:"createdAt"`
+	UpdatedAt           time.Time  `json:"updatedAt"`
+	LastLoginAt         *time.Time `json:"lastLoginAt,omitempty"`
+	DeletionScheduledAt *time.Time `json:"deletionScheduledAt,omitempty"`
+}
+
+// PersonalDataNotification уведомление из журнала уведомлений пользователя
+type PersonalDataNotification struct {
+	ID         string     `json:"id"`
+	EntityType string     `json:"entityType"`
+	EntityID   string     `json:"entityId"`
+	EntityName string     `json:"entityName"`
+	ChangeType string     `json:"changeType"`
+	FieldName  string     `json:"fieldName,omitempty"`
+	OldValue   string     `json:"oldValue,omitempty"`
+	NewValue   string     `json:"newValue,omitempty"`
+	DetectedAt time.Time  `json:"detectedAt"`
+	IsRead     bool       `json:"isRead"`
+	ReadAt     *time.Time `json:"readAt,omitempty"`
+	CreatedAt  time.Time  `json:"createdAt"`
+}
+
+// PersonalDataWorkspace членство пользователя в рабочем пространстве
+type PersonalDataWorkspace struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Role string `json:"role"`
+}
+
+// PersonalDataService выгрузка данных пользователя и удаление учетной записи
+// по его запросу. Удаление выполняется фоновым Run после льготного периода,
+// в течение которого запрос можно отменить.
+type PersonalDataService struct {
+	userRepo         *postgresql.UserRepository
+	favoriteRepo     *postgresql.FavoriteRepository
+	subscriptionRepo *postgresql.SubscriptionRepository
+	workspaceRepo    *postgresql.WorkspaceRepository
+	sessions         *SessionService
+	audit            *AuditService
+	cfg              config.AuthConfig
+	logger           *zap.Logger
+}
+
+// NewPersonalDataService создает новый сервис персональных данных
+func NewPersonalDataService(
+	userRepo *postgresql.UserRepository,
+	favoriteRepo *postgresql.FavoriteRepository,
+	subscriptionRepo *postgresql.SubscriptionRepository,
+	workspaceRepo *postgresql.WorkspaceRepository,
+	sessions *SessionService,
+	cfg config.AuthConfig,
+	logger *zap.Logger,
+) *PersonalDataService {
+	if cfg.AccountDeletionGracePeriod <= 0 {
+		cfg.AccountDeletionGracePeriod = 30 * 24 * time.Hour
+	}
+	if cfg.AccountDeletionReauthWindow <= 0 {
+		cfg.AccountDeletionReauthWindow = 10 * time.Minute
+	}
+
+	return &PersonalDataService{
+		userRepo:         userRepo,
+		favoriteRepo:     favoriteRepo,
+		subscriptionRepo: subscriptionRepo,
+		workspaceRepo:    workspaceRepo,
+		sessions:         sessions,
+		cfg:              cfg,
+		logger:           logger.Named("personal_data_service"),
+	}
+}
+
+// UseAuditLog включает запись удаления учетных записей в журнал аудита
+func (s *PersonalDataService) UseAuditLog(audit *AuditService) {
+	s.audit = audit
+}
+
+// Export собирает профиль, личное избранное, личные подписки, журнал
+// уведомлений и членство в пространствах пользователя
+func (s *PersonalDataService) Export(ctx context.Context, userID string) (*PersonalDataExport, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+
+	export := &PersonalDataExport{
+		GeneratedAt: time.Now().UTC(),
+		Profile: PersonalDataProfile{
+			ID:                  user.ID,
+			Email:               user.Email,
+			FirstName:           user.FirstName,
+			LastName:            user.LastName,
+			Role:                user.Role,
+			IsActive:            user.IsActive,
+			EmailVerified:       user.EmailVerified,
+			CreatedAt:           user.CreatedAt,
+			UpdatedAt:           user.UpdatedAt,
+			LastLoginAt:         user.LastLoginAt,
+			DeletionScheduledAt: user.DeletionScheduledAt,
+		},
+		Favorites:     []*model.Favorite{},
+		Subscriptions: []*model.EntitySubscription{},
+		Notifications: []PersonalDataNotification{},
+		Workspaces:    []PersonalDataWorkspace{},
+	}
+
+	favorites, err := s.favoriteRepo.GetByUserID(ctx, user.ID, postgresql.FavoriteFilter{})
+	if err != nil {
+		return nil, err
+	}
+	export.Favorites = append(export.Favorites, favorites...)
+
+	subscriptions, err := s.subscriptionRepo.GetByUserID(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	export.Subscriptions = append(export.Subscriptions, subscriptions...)
+
+	for offset := 0; ; offset += notificationExportPage {
+		entries, err := s.subscriptionRepo.GetNotificationHistoryByEmail(ctx, user.Email, notificationExportPage, offset)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range entries {
+			export.Notifications = append(export.Notifications, PersonalDataNotification{
+				ID:         e.ID,
+				EntityType: e.EntityType,
+				EntityID:   e.EntityID,
+				EntityName: e.EntityName,
+				ChangeType: e.ChangeType,
+				FieldName:  e.FieldName,
+				OldValue:   e.OldValue,
+				NewValue:   e.NewValue,
+				DetectedAt: e.DetectedAt,
+				IsRead:     e.IsRead,
+				ReadAt:     e.ReadAt,
+				CreatedAt:  e.CreatedAt,
+			})
+		}
+		if len(entries) < notificationExportPage {
+			break
+		}
+	}
+
+	workspaces, err := s.workspaceRepo.GetByUserID(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, w := range workspaces {
+		export.Workspaces = append(export.Workspaces, PersonalDataWorkspace{ID: w.ID, Name: w.Name, Role: w.Role})
+	}
+
+	return export, nil
+}
+
+// WriteJSON записывает выгрузку одним JSON документом
+func (e *PersonalDataExport) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(e)
+}
+
+// WriteZIP записывает выгрузку ZIP архивом: по JSON файлу на раздел
+func (e *PersonalDataExport) WriteZIP(w io.Writer) error {
+	zw := zip.NewWriter(w)
+	files := []struct {
+		name string
+		data interface{}
+	}{
+		{"profile.json", e.Profile},
+		{"favorites.json", e.Favorites},
+		{"subscriptions.json", e.Subscriptions},
+		{"notifications.json", e.Notifications},
+		{"workspaces.json", e.Workspaces},
+	}
+	for _, f := range files {
+		fw, err := zw.CreateHeader(&zip.FileHeader{Name: f.name, Method: zip.Deflate, Modified: e.GeneratedAt})
+		if err != nil {
+			return fmt.Errorf("failed to add %s: %w", f.name, err)
+		}
+		enc := json.NewEncoder(fw)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(f.data); err != nil {
+			return fmt.Errorf("failed to write %s: %w", f.name, err)
+		}
+	}
+	return zw.Close()
+}
+
+// RequestDeletion назначает удаление учетной записи по истечении льготного
+// периода после проверки пароля. У пользователей SSO без пароля подтверждением
+// служит свежий вход: сессия sessionID должна быть открыта не раньше чем
+// AccountDeletionReauthWindow назад (обновление токенов сессию не продлевает).
+func (s *PersonalDataService) RequestDeletion(ctx context.Context, userID, sessionID, password string) (*postgresql.User, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil || !user.IsActive {
+		return nil, ErrUserNotFound
+	}
+	if user.PasswordHash != "" {
+		if !auth.CheckPassword(password, user.PasswordHash) {
+			return nil, ErrWrongPassword
+		}
+	} else if err := s.checkRecentSignIn(ctx, user.ID, sessionID); err != nil {
+		return nil, err
+	}
+
+	at := time.Now().Add(s.cfg.AccountDeletionGracePeriod)
+	scheduled, err := s.userRepo.ScheduleDeletion(ctx, user.ID, at)
+	if err != nil {
+		return nil, err
+	}
+	if !scheduled {
+		return nil, ErrAccountDeletionScheduled
+	}
+
+	user.DeletionScheduledAt = &at
+	return user, nil
+}
+
+// checkRecentSignIn проверяет, что сессия открыта входом в пределах
+// AccountDeletionReauthWindow
+func (s *PersonalDataService) checkRecentSignIn(ctx context.Context, userID, sessionID string) error {
+	session, err := s.sessions.Get(ctx, userID, sessionID)
+	if errors.Is(err, ErrSessionNotFound) {
+		return ErrReauthenticationRequired
+	}
+	if err != nil {
+		return err
+	}
+	if time.Since(session.CreatedAt) > s.cfg.AccountDeletionReauthWindow {
+		return ErrReauthenticationRequired
+	}
+	return nil
+}
+
+// CancelDeletion отменяет назначенное удаление учетной записи
+func (s *PersonalDataService) CancelDeletion(ctx context.Context, userID string) (*postgresql.User, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+
+	cancelled, err := s.userRepo.CancelDeletion(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !cancelled {
+		return nil, ErrAccountDeletionNotScheduled
+	}
+
+	user.DeletionScheduledAt = nil
+	return user, nil
+}
+
+// Run удаляет учетные записи, срок удаления которых наступил, до отмены ctx
+func (s *PersonalDataService) Run(ctx context.Context) {
+	ticker := time.NewTicker(accountPurgeInterval)
+	defer ticker.Stop()
+
+	s.purgeDue(ctx)
+	for {
+		select {
+		case <-ticker.C:
+			s.purgeDue(ctx)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// purgeDue удаляет учетные записи, срок удаления которых наступил. Сессии
+// отзываются до удаления, чтобы выданные access токены перестали действовать.
+func (s *PersonalDataService) purgeDue(ctx context.Context) {
+	now := time.Now()
+	ids, err := s.userRepo.GetDueForDeletion(ctx, now, accountPurgeBatch)
+	if err != nil {
+		s.logger.Error("failed to get accounts due for deletion", zap.Error(err))
+		return
+	}
+
+	for _, id := range ids {
+		if err := s.sessions.RevokeAll(ctx, id, "", postgresql.SessionRevokedAccountDeleted); err != nil {
+			s.logger.Error("failed to revoke sessions before account deletion", zap.Error(err), zap.String("user_id", id))
+			continue
+		}
+		purged, err := s.userRepo.Purge(ctx, id, now)
+		if err != nil {
+			s.logger.Error("failed to delete account", zap.Error(err), zap.String("user_id", id))
+			continue
+		}
+		if purged {
+			s.audit.Record(ctx, AuditEvent{
+				Action:     model.AuditActionAccountDelete,
+				UserID:     id,
+				EntityType: AuditEntityUser,
+				EntityID:   id,
+				Success:    true,
+			})
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/egrul-system/services/api-gateway/internal/config"
+	"github.com/egrul-system/services/api-gateway/internal/graph/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestPersonalDataExport_WriteZIPHasFilePerSection(t *testing.T) {
+	// Arrange
+	export := &PersonalDataExport{
+		GeneratedAt: time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC),
+		Profile:     PersonalDataProfile{ID: "user-1", Email: "user@example.com"},
+		Favorites: []*model.Favorite{
+			{ID: "fav-1", EntityType: model.EntityTypeCompany, EntityID: "1027700132195", Tags: []string{"банк"}},
+		},
+		Subscriptions: []*model.EntitySubscription{},
+		Notifications: []PersonalDataNotification{{ID: "n-1", ChangeType: "status"}},
+		Workspaces:    []PersonalDataWorkspace{},
+	}
+
+	// Act
+	var buf bytes.Buffer
+	require.NoError(t, export.WriteZIP(&buf))
+
+	// Assert
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+	files := make(map[string][]byte)
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		require.NoError(t, err)
+		data, err := io.ReadAll(rc)
+		rc.Close()
+		require.NoError(t, err)
+		files[f.Name] = data
+	}
+	assert.Len(t, files, 5)
+
+	var profile PersonalDataProfile
+	require.NoError(t, json.Unmarshal(files["profile.json"], &profile))
+	assert.Equal(t, "user@example.com", profile.Email)
+
+	var favorites []map[string]interface{}
+	require.NoError(t, json.Unmarshal(files["favorites.json"], &favorites))
+	require.Len(t, favorites, 1)
+	assert.Equal(t, "1027700132195", favorites[0]["entityId"])
+
+	assert.JSONEq(t, "[]", string(files["subscriptions.json"]), "пустой раздел - пустой массив, а не null")
+}
+
+func TestNewPersonalDataService_DefaultGracePeriod(t *testing.T) {
+	svc := NewPersonalDataService(nil, nil, nil, nil, nil, config.AuthConfig{}, zap.NewNop())
+
+	assert.Equal(t, 30*24*time.Hour, svc.cfg.AccountDeletionGracePeriod)
+}
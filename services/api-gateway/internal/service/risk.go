@@ -0,0 +1,222 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/egrul-system/services/api-gateway/internal/config"
+	"github.com/egrul-system/services/api-gateway/internal/graph/model"
+	"github.com/egrul-system/services/api-gateway/internal/repository"
+	"go.uber.org/zap"
+)
+
+// Коды факторов риска
+const (
+	RiskFactorLiquidating             = "LIQUIDATING"
+	RiskFactorBankrupt                = "BANKRUPT"
+	RiskFactorReorganizing            = "REORGANIZING"
+	RiskFactorYoungCompany            = "YOUNG_COMPANY"
+	RiskFactorMinimalCapital          = "MINIMAL_CAPITAL"
+	RiskFactorMassAddress             = "MASS_ADDRESS"
+	RiskFactorMassDirector            = "MASS_DIRECTOR"
+	RiskFactorFrequentDirectorChanges = "FREQUENT_DIRECTOR_CHANGES"
+	RiskFactorFrequentAddressChanges  = "FREQUENT_ADDRESS_CHANGES"
+	RiskFactorForeignFounders         = "FOREIGN_FOUNDERS"
+	RiskFactorNoDirector              = "NO_DIRECTOR"
+)
+
+// maxRiskScore верхняя граница балла риска
+const maxRiskScore = 100
+
+// MaxRiskBatchSize максимальное число ОГРН в одном пакетном запросе оценки
+const MaxRiskBatchSize = 100
+
+// RiskService рассчитывает оценку риска контрагента по карточке и
+// предрассчитанным в ClickHouse признакам
+type RiskService struct {
+	repo        repository.RiskRepository
+	companyRepo repository.CompanyRepository
+	cfg         config.RiskConfig
+	now         func() time.Time
+	logger      *zap.Logger
+}
+
+// NewRiskService создает новый сервис оценки риска
+func NewRiskService(repo repository.RiskRepository, companyRepo repository.CompanyRepository, cfg config.RiskConfig, logger *zap.Logger) *RiskService {
+	if cfg.YoungCompanyMonths <= 0 {
+		cfg.YoungCompanyMonths = 6
+	}
+	if cfg.MassAddressThreshold <= 0 {
+		cfg.MassAddressThreshold = 10
+	}
+	if cfg.MassDirectorThreshold <= 0 {
+		cfg.MassDirectorThreshold = 10
+	}
+	if cfg.FrequentChangesThreshold <= 0 {
+		cfg.FrequentChangesThreshold = 2
+	}
+	if cfg.HighLevelScore <= 0 {
+		cfg.HighLevelScore = 60
+	}
+	if cfg.MediumLevelScore <= 0 {
+		cfg.MediumLevelScore = 30
+	}
+
+	return &RiskService{
+		repo:        repo,
+		companyRepo: companyRepo,
+		cfg:         cfg,
+		now:         time.Now,
+		logger:      logger.Named("risk_service"),
+	}
+}
+
+// Assess рассчитывает оценку риска одной компании
+func (s *RiskService) Assess(ctx context.Context, company *model.Company) (*model.RiskAssessment, error) {
+	assessments, err := s.AssessBatch(ctx, []*model.Company{company})
+	if err != nil {
+		return nil, err
+	}
+	return assessments[company.Ogrn], nil
+}
+
+// AssessBatch рассчитывает оценки риска для списка компаний одним запросом признаков.
+// Результат индексирован по ОГРН.
+func (s *RiskService) AssessBatch(ctx context.Context, companies []*model.Company) (map[string]*model.RiskAssessment, error) {
+	ogrns := make([]string, 0, len(companies))
+	for _, c := range companies {
+		if c != nil {
+			ogrns = append(ogrns, c.Ogrn)
+		}
+	}
+
+	features, err := s.repo.GetFeatures(ctx, ogrns)
+	if err != nil {
+		return nil, fmt.Errorf("get risk features: %w", err)
+	}
+
+	result := make(map[string]*model.RiskAssessment, len(ogrns))
+	for _, c := range companies {
+		if c != nil {
+			result[c.Ogrn] = s.assess(c, features[c.Ogrn])
+		}
+	}
+	return result, nil
+}
+
+// AssessByOGRNs загружает компании по списку ОГРН и оценивает их пакетно.
+// Порядок результата совпадает с порядком ogrns; ненайденные помечаются Found=false.
+func (s *RiskService) AssessByOGRNs(ctx context.Context, ogrns []string) ([]*model.CompanyRiskAssessment, error) {
+	if len(ogrns) > MaxRiskBatchSize {
+		return nil, fmt.Errorf("too many ogrns: %d (max %d)", len(ogrns), MaxRiskBatchSize)
+	}
+
+	companies := make([]*model.Company, 0, len(ogrns))
+	for _, ogrn := range ogrns {
+		company, err := s.companyRepo.GetByOGRN(ctx, ogrn)
+		if err != nil {
+			return nil, fmt.Errorf("get company %s: %w", ogrn, err)
+		}
+		if company != nil {
+			companies = append(companies, company)
+		}
+	}
+
+	assessments, err := s.AssessBatch(ctx, companies)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*model.CompanyRiskAssessment, 0, len(ogrns))
+	for _, ogrn := range ogrns {
+		assessment := assessments[ogrn]
+		result = append(result, &model.CompanyRiskAssessment{
+			Ogrn:       ogrn,
+			Found:      assessment != nil,
+			Assessment: assessment,
+		})
+	}
+	return result, nil
+}
+
+// assess применяет правила к карточке и признакам. Признаки могут отсутствовать,
+// тогда зависящие от них факторы не срабатывают.
+func (s *RiskService) assess(c *model.Company, f *model.CompanyRiskFeatures) *model.RiskAssessment {
+	w := s.cfg.Weights
+	a := &model.RiskAssessment{Factors: []*model.RiskFactor{}}
+
+	add := func(code, description string, weight int, value string) {
+		if weight <= 0 {
+			return
+		}
+		factor := &model.RiskFactor{Code: code, Description: description, Weight: weight}
+		if value != "" {
+			factor.Value = &value
+		}
+		a.Factors = append(a.Factors, factor)
+		a.Score += weight
+	}
+
+	if c.IsLiquidating || c.Status == model.EntityStatusLiquidating {
+		add(RiskFactorLiquidating, "Компания в процессе ликвидации", w.Liquidating, "")
+	}
+	if c.IsBankrupt || c.Status == model.EntityStatusBankrupt {
+		add(RiskFactorBankrupt, "Процедура банкротства", w.Bankrupt, derefString(c.BankruptcyStage))
+	}
+	if c.IsReorganizing || c.Status == model.EntityStatusReorganizing {
+		add(RiskFactorReorganizing, "Компания в процессе реорганизации", w.Reorganizing, "")
+	}
+	if c.RegistrationDate != nil && c.RegistrationDate.After(s.now().AddDate(0, -s.cfg.YoungCompanyMonths, 0)) {
+		add(RiskFactorYoungCompany, fmt.Sprintf("Зарегистрирована менее %d мес. назад", s.cfg.YoungCompanyMonths),
+			w.YoungCompany, c.RegistrationDate.Format("2006-01-02"))
+	}
+	if c.Capital != nil && c.Capital.Amount > 0 && c.Capital.Amount <= s.cfg.MinimalCapital {
+		add(RiskFactorMinimalCapital, "Минимальный уставный капитал", w.MinimalCapital,
+			strconv.FormatFloat(c.Capital.Amount, 'f', 2, 64))
+	}
+	if c.Director == nil || (c.Director.LastName == "" && c.Director.FirstName == "") {
+		add(RiskFactorNoDirector, "Нет сведений о руководителе", w.NoDirector, "")
+	}
+
+	if f != nil {
+		updatedAt := f.UpdatedAt
+		a.FeaturesUpdatedAt = &updatedAt
+
+		if f.AddressCompanies >= s.cfg.MassAddressThreshold {
+			add(RiskFactorMassAddress, "Адрес массовой регистрации", w.MassAddress,
+				fmt.Sprintf("%d компаний по адресу", f.AddressCompanies))
+		}
+		if f.DirectorCompanies >= s.cfg.MassDirectorThreshold {
+			add(RiskFactorMassDirector, "Массовый руководитель", w.MassDirector,
+				fmt.Sprintf("руководитель %d компаний", f.DirectorCompanies))
+		}
+		if f.DirectorChanges12m >= s.cfg.FrequentChangesThreshold {
+			add(RiskFactorFrequentDirectorChanges, "Частая смена руководителя", w.FrequentDirectorChanges,
+				fmt.Sprintf("%d смен за 12 мес.", f.DirectorChanges12m))
+		}
+		if f.AddressChanges12m >= s.cfg.FrequentChangesThreshold {
+			add(RiskFactorFrequentAddressChanges, "Частая смена адреса", w.FrequentAddressChanges,
+				fmt.Sprintf("%d смен за 12 мес.", f.AddressChanges12m))
+		}
+		if f.ForeignFounders > 0 {
+			add(RiskFactorForeignFounders, "Иностранные учредители", w.ForeignFounders,
+				strconv.Itoa(f.ForeignFounders))
+		}
+	}
+
+	if a.Score > maxRiskScore {
+		a.Score = maxRiskScore
+	}
+	switch {
+	case a.Score >= s.cfg.HighLevelScore:
+		a.Level = model.RiskLevelHigh
+	case a.Score >= s.cfg.MediumLevelScore:
+		a.Level = model.RiskLevelMedium
+	default:
+		a.Level = model.RiskLevelLow
+	}
+
+	return a
+}
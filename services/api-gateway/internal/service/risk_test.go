@@ -0,0 +1,177 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/egrul-system/services/api-gateway/internal/config"
+	"github.com/egrul-system/services/api-gateway/internal/graph/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+// MockRiskRepository мок для RiskRepository
+type MockRiskRepository struct {
+	mock.Mock
+}
+
+func (m *MockRiskRepository) GetFeatures(ctx context.Context, ogrns []string) (map[string]*model.CompanyRiskFeatures, error) {
+	args := m.Called(ctx, ogrns)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[string]*model.CompanyRiskFeatures), args.Error(1)
+}
+
+func (m *MockRiskRepository) GetMassAddressByCompany(ctx context.Context, ogrn string) (*model.MassAddress, error) {
+	args := m.Called(ctx, ogrn)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.MassAddress), args.Error(1)
+}
+
+func (m *MockRiskRepository) ListMassAddresses(ctx context.Context, regionCode *string, minCount, limit, offset int) ([]*model.MassAddress, error) {
+	args := m.Called(ctx, regionCode, minCount, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.MassAddress), args.Error(1)
+}
+
+func (m *MockRiskRepository) GetMassDirector(ctx context.Context, inn string) (*model.MassDirector, error) {
+	args := m.Called(ctx, inn)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.MassDirector), args.Error(1)
+}
+
+func (m *MockRiskRepository) ListMassDirectors(ctx context.Context, minCount, limit, offset int) ([]*model.MassDirector, error) {
+	args := m.Called(ctx, minCount, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.MassDirector), args.Error(1)
+}
+
+func testRiskConfig() config.RiskConfig {
+	return config.RiskConfig{
+		Weights: config.RiskWeightsConfig{
+			Liquidating:             40,
+			YoungCompany:            15,
+			MinimalCapital:          10,
+			MassAddress:             25,
+			FrequentDirectorChanges: 15,
+			NoDirector:              20,
+		},
+		YoungCompanyMonths:       6,
+		MinimalCapital:           10000,
+		MassAddressThreshold:     10,
+		MassDirectorThreshold:    10,
+		FrequentChangesThreshold: 2,
+		MediumLevelScore:         30,
+		HighLevelScore:           60,
+	}
+}
+
+func TestRiskService_Assess_FactorsAndLevel(t *testing.T) {
+	// Arrange
+	mockRepo := new(MockRiskRepository)
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	company := &model.Company{
+		Ogrn:             "1234567890123",
+		Status:           model.EntityStatusActive,
+		RegistrationDate: model.NewDate(now.AddDate(0, -2, 0)),
+		Capital:          &model.Money{Amount: 10000, Currency: "RUB"},
+		Director:         &model.Person{LastName: "Иванов", FirstName: "Иван"},
+	}
+	features := map[string]*model.CompanyRiskFeatures{
+		"1234567890123": {Ogrn: "1234567890123", AddressCompanies: 412, DirectorChanges12m: 1, UpdatedAt: now},
+	}
+	mockRepo.On("GetFeatures", mock.Anything, []string{"1234567890123"}).Return(features, nil)
+
+	service := NewRiskService(mockRepo, nil, testRiskConfig(), zap.NewNop())
+	service.now = func() time.Time { return now }
+
+	// Act
+	result, err := service.Assess(context.Background(), company)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, 50, result.Score)
+	assert.Equal(t, model.RiskLevelMedium, result.Level)
+	codes := make([]string, len(result.Factors))
+	for i, f := range result.Factors {
+		codes[i] = f.Code
+	}
+	assert.Equal(t, []string{RiskFactorYoungCompany, RiskFactorMinimalCapital, RiskFactorMassAddress}, codes)
+	assert.NotNil(t, result.FeaturesUpdatedAt)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestRiskService_Assess_WithoutFeatures(t *testing.T) {
+	// Arrange
+	mockRepo := new(MockRiskRepository)
+	company := &model.Company{
+		Ogrn:          "1234567890123",
+		Status:        model.EntityStatusLiquidating,
+		IsLiquidating: true,
+	}
+	mockRepo.On("GetFeatures", mock.Anything, []string{"1234567890123"}).Return(map[string]*model.CompanyRiskFeatures{}, nil)
+
+	service := NewRiskService(mockRepo, nil, testRiskConfig(), zap.NewNop())
+
+	// Act
+	result, err := service.Assess(context.Background(), company)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, 60, result.Score)
+	assert.Equal(t, model.RiskLevelHigh, result.Level)
+	assert.Nil(t, result.FeaturesUpdatedAt)
+	assert.Len(t, result.Factors, 2)
+}
+
+func TestRiskService_MassDirector(t *testing.T) {
+	// Arrange
+	mockRepo := new(MockRiskRepository)
+	inn := "771234567890"
+	mockRepo.On("GetMassDirector", mock.Anything, inn).Return(&model.MassDirector{Inn: inn, CompaniesCount: 57, ActiveCount: 12}, nil)
+
+	service := NewRiskService(mockRepo, nil, testRiskConfig(), zap.NewNop())
+
+	// Act
+	result, err := service.MassDirector(context.Background(), &model.Person{LastName: "Иванов", Inn: &inn})
+	noInn, noInnErr := service.MassDirector(context.Background(), &model.Person{LastName: "Петров"})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.True(t, result.IsMass)
+	assert.Equal(t, 12, result.ActiveCount)
+	assert.NoError(t, noInnErr)
+	assert.Nil(t, noInn)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestRiskService_ListMassAddresses_DefaultThreshold(t *testing.T) {
+	// Arrange
+	mockRepo := new(MockRiskRepository)
+	region := "77"
+	addresses := []*model.MassAddress{{AddressKey: "fias:abc", CompaniesCount: 412, ActiveCount: 380}}
+	mockRepo.On("ListMassAddresses", mock.Anything, &region, 10, defaultMassListLimit, 0).Return(addresses, nil)
+
+	service := NewRiskService(mockRepo, nil, testRiskConfig(), zap.NewNop())
+
+	// Act
+	result, err := service.ListMassAddresses(context.Background(), &region, nil, nil, nil)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Len(t, result, 1)
+	assert.True(t, result[0].IsMass)
+	mockRepo.AssertExpectations(t)
+}
@@ -2,16 +2,35 @@ package service
 
 import (
 	"context"
+	"errors"
+	"strings"
 	"sync"
+	"unicode/utf8"
 
 	"github.com/egrul-system/services/api-gateway/internal/graph/model"
+	"github.com/egrul-system/services/api-gateway/internal/repository/elasticsearch"
 	"go.uber.org/zap"
 )
 
+const (
+	// suggestMinPrefix минимальная длина префикса для подсказок
+	suggestMinPrefix = 2
+	// suggestMaxLimit максимальное число подсказок
+	suggestMaxLimit = 20
+	// facetedSearchMaxLimit максимальный размер страницы поиска с фасетами
+	facetedSearchMaxLimit = 100
+)
+
+// ErrFacetedSearchUnavailable поиск с фасетами требует Elasticsearch
+var ErrFacetedSearchUnavailable = errors.New("faceted search requires elasticsearch")
+
 // SearchService сервис для универсального поиска
 type SearchService struct {
 	companyService     *CompanyService
 	entrepreneurService *EntrepreneurService
+	suggestRepo        *elasticsearch.ESSuggestRepository
+	esCompanyRepo      *elasticsearch.ESCompanyRepository
+	esEntrepreneurRepo *elasticsearch.ESEntrepreneurRepository
 	logger             *zap.Logger
 }
 
@@ -19,11 +38,17 @@ type SearchService struct {
 func NewSearchService(
 	companyService *CompanyService,
 	entrepreneurService *EntrepreneurService,
+	suggestRepo *elasticsearch.ESSuggestRepository,
+	esCompanyRepo *elasticsearch.ESCompanyRepository,
+	esEntrepreneurRepo *elasticsearch.ESEntrepreneurRepository,
 	logger *zap.Logger,
 ) *SearchService {
 	return &SearchService{
 		companyService:     companyService,
 		entrepreneurService: entrepreneurService,
+		suggestRepo:        suggestRepo,
+		esCompanyRepo:      esCompanyRepo,
+		esEntrepreneurRepo: esEntrepreneurRepo,
 		logger:             logger.Named("search_service"),
 	}
 }
@@ -80,3 +105,125 @@ func (s *SearchService) Search(ctx context.Context, query string, limit int) (*m
 	return result, nil
 }
 
+
+// Suggest возвращает подсказки для поисковой строки. Без Elasticsearch (suggestRepo == nil)
+// или при его ошибке используется обычный поиск - он медленнее, но подсказки не пропадают.
+// Пустой types - компании и ИП.
+func (s *SearchService) Suggest(ctx context.Context, prefix string, types []model.EntityType, limit int) ([]*model.Suggestion, error) {
+	prefix = strings.TrimSpace(prefix)
+	if utf8.RuneCountInString(prefix) < suggestMinPrefix {
+		return []*model.Suggestion{}, nil
+	}
+	if limit <= 0 {
+		limit = 10
+	}
+	if limit > suggestMaxLimit {
+		limit = suggestMaxLimit
+	}
+
+	includeCompanies, includeEntrepreneurs := len(types) == 0, len(types) == 0
+	for _, t := range types {
+		switch t {
+		case model.EntityTypeCompany:
+			includeCompanies = true
+		case model.EntityTypeEntrepreneur:
+			includeEntrepreneurs = true
+		}
+	}
+
+	if s.suggestRepo != nil {
+		suggestions, err := s.suggestRepo.Suggest(ctx, prefix, includeCompanies, includeEntrepreneurs, limit)
+		if err == nil {
+			return suggestions, nil
+		}
+		s.logger.Warn("elasticsearch suggest failed, falling back to search", zap.Error(err))
+	}
+
+	result, err := s.Search(ctx, prefix, limit)
+	if err != nil {
+		return nil, err
+	}
+	return suggestionsFromSearch(result, includeCompanies, includeEntrepreneurs, limit), nil
+}
+
+// suggestionsFromSearch преобразует результат обычного поиска в подсказки
+func suggestionsFromSearch(result *model.SearchResult, includeCompanies, includeEntrepreneurs bool, limit int) []*model.Suggestion {
+	suggestions := make([]*model.Suggestion, 0, limit)
+	if includeCompanies {
+		for _, c := range result.Companies {
+			name := c.FullName
+			if c.ShortName != nil && *c.ShortName != "" {
+				name = *c.ShortName
+			}
+			suggestion := &model.Suggestion{
+				EntityType: model.EntityTypeCompany,
+				ID:         c.Ogrn,
+				Inn:        c.Inn,
+				Name:       name,
+				Status:     c.Status,
+			}
+			if c.Address != nil {
+				suggestion.RegionCode = c.Address.RegionCode
+				suggestion.Region = c.Address.Region
+			}
+			suggestions = append(suggestions, suggestion)
+		}
+	}
+	if includeEntrepreneurs {
+		for _, e := range result.Entrepreneurs {
+			name := e.LastName + " " + e.FirstName
+			if e.MiddleName != nil && *e.MiddleName != "" {
+				name += " " + *e.MiddleName
+			}
+			suggestion := &model.Suggestion{
+				EntityType: model.EntityTypeEntrepreneur,
+				ID:         e.Ogrnip,
+				Inn:        e.Inn,
+				Name:       name,
+				Status:     e.Status,
+			}
+			if e.Address != nil {
+				suggestion.RegionCode = e.Address.RegionCode
+				suggestion.Region = e.Address.Region
+			}
+			suggestions = append(suggestions, suggestion)
+		}
+	}
+	if len(suggestions) > limit {
+		suggestions = suggestions[:limit]
+	}
+	return suggestions
+}
+
+// SearchCompaniesFaceted ищет компании и возвращает фасеты (статус, регион, ОКВЭД,
+// ОПФ, год регистрации, капитал). Счетчики фасета учитывают остальные фильтры.
+func (s *SearchService) SearchCompaniesFaceted(ctx context.Context, query string, filter *model.CompanyFilter, limit, offset int) (*model.CompanySearchResult, error) {
+	if s.esCompanyRepo == nil {
+		return nil, ErrFacetedSearchUnavailable
+	}
+	limit, offset = facetedPage(limit, offset)
+	return s.esCompanyRepo.SearchWithFacets(ctx, strings.TrimSpace(query), filter, limit, offset)
+}
+
+// SearchEntrepreneursFaceted ищет ИП и возвращает фасеты (статус, регион, ОКВЭД,
+// год регистрации)
+func (s *SearchService) SearchEntrepreneursFaceted(ctx context.Context, query string, filter *model.EntrepreneurFilter, limit, offset int) (*model.EntrepreneurSearchResult, error) {
+	if s.esEntrepreneurRepo == nil {
+		return nil, ErrFacetedSearchUnavailable
+	}
+	limit, offset = facetedPage(limit, offset)
+	return s.esEntrepreneurRepo.SearchWithFacets(ctx, strings.TrimSpace(query), filter, limit, offset)
+}
+
+func facetedPage(limit, offset int) (int, int) {
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > facetedSearchMaxLimit {
+		limit = facetedSearchMaxLimit
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return limit, offset
+}
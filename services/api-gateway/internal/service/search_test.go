@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/egrul-system/services/api-gateway/internal/graph/model"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestSuggest_ShortPrefixReturnsEmpty(t *testing.T) {
+	svc := NewSearchService(nil, nil, nil, nil, nil, zap.NewNop())
+
+	suggestions, err := svc.Suggest(context.Background(), " а ", nil, 10)
+
+	assert.NoError(t, err)
+	assert.Empty(t, suggestions)
+}
+
+func TestSuggestionsFromSearch_FiltersTypesAndLimits(t *testing.T) {
+	shortName := "ООО \"Ромашка\""
+	regionCode := "77"
+	result := &model.SearchResult{
+		Companies: []*model.Company{
+			{Ogrn: "1027700000001", Inn: "7700000001", FullName: "ОБЩЕСТВО С ОГРАНИЧЕННОЙ ОТВЕТСТВЕННОСТЬЮ \"РОМАШКА\"", ShortName: &shortName,
+				Status: model.EntityStatusActive, Address: &model.Address{RegionCode: &regionCode}},
+			{Ogrn: "1027700000002", Inn: "7700000002", FullName: "АО \"РОМАШКА-2\"", Status: model.EntityStatusLiquidated},
+		},
+		Entrepreneurs: []*model.Entrepreneur{
+			{Ogrnip: "304770000000001", Inn: "770000000001", LastName: "Ромашкин", FirstName: "Иван"},
+		},
+	}
+
+	companiesOnly := suggestionsFromSearch(result, true, false, 10)
+	assert.Len(t, companiesOnly, 2)
+	assert.Equal(t, shortName, companiesOnly[0].Name)
+	assert.Equal(t, &regionCode, companiesOnly[0].RegionCode)
+	assert.Equal(t, model.EntityTypeCompany, companiesOnly[1].EntityType)
+
+	all := suggestionsFromSearch(result, true, true, 10)
+	assert.Len(t, all, 3)
+	assert.Equal(t, "Ромашкин Иван", all[2].Name)
+	assert.Equal(t, "304770000000001", all[2].ID)
+
+	assert.Len(t, suggestionsFromSearch(result, true, true, 1), 1)
+}
+
+func TestSearchCompaniesFaceted_RequiresElasticsearch(t *testing.T) {
+	svc := NewSearchService(nil, nil, nil, nil, nil, zap.NewNop())
+
+	_, err := svc.SearchCompaniesFaceted(context.Background(), "ромашка", nil, 20, 0)
+	assert.ErrorIs(t, err, ErrFacetedSearchUnavailable)
+
+	_, err = svc.SearchEntrepreneursFaceted(context.Background(), "ромашкин", nil, 20, 0)
+	assert.ErrorIs(t, err, ErrFacetedSearchUnavailable)
+}
@@ -0,0 +1,241 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/egrul-system/services/api-gateway/internal/auth"
+	"github.com/egrul-system/services/api-gateway/internal/cache"
+	"github.com/egrul-system/services/api-gateway/internal/config"
+	"github.com/egrul-system/services/api-gateway/internal/repository/postgresql"
+	"go.uber.org/zap"
+)
+
+var (
+	// ErrInvalidRefreshToken refresh токен не найден, истек или сессия отозвана
+	ErrInvalidRefreshToken = errors.New("refresh token is invalid or expired")
+	// ErrRefreshTokenReused предъявлен уже использованный refresh токен
+	ErrRefreshTokenReused = errors.New("refresh token has already been used, session revoked")
+	// ErrSessionNotFound сессия не найдена или принадлежит другому пользователю
+	ErrSessionNotFound = errors.New("session not found")
+)
+
+// revokedSessionKeyPrefix префикс ключей Redis для отозванных сессий
+const revokedSessionKeyPrefix = "auth:revoked_session:"
+
+// AuthTokens пара токенов сессии
+type AuthTokens struct {
+	SessionID        string
+	AccessToken      string
+	AccessExpiresAt  time.Time
+	RefreshToken     string
+	RefreshExpiresAt time.Time
+}
+
+// SessionService сессии пользователей: короткоживущие access JWT и одноразовые
+// refresh токены с ротацией. Отозванные сессии попадают в denylist в Redis на
+// время жизни access токена, который проверяет JWT middleware.
+type SessionService struct {
+	sessionRepo *postgresql.SessionRepository
+	userRepo    *postgresql.UserRepository
+	jwtManager  *auth.JWTManager
+	cache       cache.Cache
+	cfg         config.AuthConfig
+	logger      *zap.Logger
+}
+
+// NewSessionService создает новый сервис сессий
+func NewSessionService(
+	sessionRepo *postgresql.SessionRepository,
+	userRepo *postgresql.UserRepository,
+	jwtManager *auth.JWTManager,
+	cache cache.Cache,
+	cfg config.AuthConfig,
+	logger *zap.Logger,
+) *SessionService {
+	if cfg.RefreshTokenTTL <= 0 {
+		cfg.RefreshTokenTTL = 30 * 24 * time.Hour
+	}
+
+	return &SessionService{
+		sessionRepo: sessionRepo,
+		userRepo:    userRepo,
+		jwtManager:  jwtManager,
+		cache:       cache,
+		cfg:         cfg,
+		logger:      logger.Named("session_service"),
+	}
+}
+
+// CreateSession открывает сессию после входа или регистрации
+func (s *SessionService) CreateSession(ctx context.Context, user *postgresql.User, client auth.ClientInfo) (*AuthTokens, error) {
+	refreshToken, refreshHash, err := newSecretToken()
+	if err != nil {
+		return nil, err
+	}
+
+	session := &postgresql.Session{
+		UserID:    user.ID,
+		UserAgent: client.UserAgent,
+		IPAddress: client.IPAddress,
+		ExpiresAt: time.Now().Add(s.cfg.RefreshTokenTTL),
+	}
+	if err := s.sessionRepo.Create(ctx, session, refreshHash); err != nil {
+		return nil, err
+	}
+
+	return s.issueTokens(user, session, refreshToken)
+}
+
+// Refresh обменивает refresh токен на новую пару токенов. Повторное предъявление
+// уже обмененного токена отзывает сессию: токеном воспользовался кто-то еще.
+func (s *SessionService) Refresh(ctx context.Context, refreshToken string, client auth.ClientInfo) (*AuthTokens, *postgresql.User, error) {
+	tokenHash := hashSecretToken(refreshToken)
+	token, session, err := s.sessionRepo.GetRefreshToken(ctx, tokenHash)
+	if err != nil {
+		return nil, nil, err
+	}
+	if token == nil {
+		return nil, nil, ErrInvalidRefreshToken
+	}
+
+	now := time.Now()
+	if token.UsedAt != nil {
+		s.revokeReused(ctx, session)
+		return nil, nil, ErrRefreshTokenReused
+	}
+	if !session.IsActive(now) || now.After(token.ExpiresAt) {
+		return nil, nil, ErrInvalidRefreshToken
+	}
+
+	user, err := s.userRepo.GetByID(ctx, session.UserID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if user == nil || !user.IsActive {
+		return nil, nil, ErrInvalidRefreshToken
+	}
+
+	newToken, newHash, err := newSecretToken()
+	if err != nil {
+		return nil, nil, err
+	}
+	session.ExpiresAt = now.Add(s.cfg.RefreshTokenTTL)
+	session.UserAgent = client.UserAgent
+	session.IPAddress = client.IPAddress
+
+	rotated, err := s.sessionRepo.Rotate(ctx, session, tokenHash, newHash)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !rotated {
+		// Параллельный запрос успел обменять этот же токен
+		s.revokeReused(ctx, session)
+		return nil, nil, ErrRefreshTokenReused
+	}
+
+	tokens, err := s.issueTokens(user, session, newToken)
+	if err != nil {
+		return nil, nil, err
+	}
+	return tokens, user, nil
+}
+
+// ListSessions действующие сессии пользователя
+func (s *SessionService) ListSessions(ctx context.Context, userID string) ([]*postgresql.Session, error) {
+	return s.sessionRepo.GetActiveByUserID(ctx, userID)
+}
+
+// Get действующая сессия пользователя
+func (s *SessionService) Get(ctx context.Context, userID, sessionID string) (*postgresql.Session, error) {
+	if sessionID == "" {
+		return nil, ErrSessionNotFound
+	}
+	session, err := s.sessionRepo.GetByID(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if session == nil || session.UserID != userID || !session.IsActive(time.Now()) {
+		return nil, ErrSessionNotFound
+	}
+	return session, nil
+}
+
+// Revoke отзывает сессию пользователя (logout или завершение сеанса на другом устройстве)
+func (s *SessionService) Revoke(ctx context.Context, userID, sessionID, reason string) error {
+	session, err := s.sessionRepo.GetByID(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+	if session == nil || session.UserID != userID {
+		return ErrSessionNotFound
+	}
+	if session.RevokedAt != nil {
+		return nil
+	}
+
+	if err := s.sessionRepo.Revoke(ctx, sessionID, reason); err != nil {
+		return err
+	}
+	s.denySession(ctx, sessionID)
+	return nil
+}
+
+// RevokeAll отзывает все сессии пользователя, кроме exceptSessionID (пусто - все)
+func (s *SessionService) RevokeAll(ctx context.Context, userID, exceptSessionID, reason string) error {
+	ids, err := s.sessionRepo.RevokeAllByUserID(ctx, userID, exceptSessionID, reason)
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		s.denySession(ctx, id)
+	}
+	return nil
+}
+
+// IsRevoked реализует auth.SessionDenylist
+func (s *SessionService) IsRevoked(ctx context.Context, sessionID string) (bool, error) {
+	var revoked bool
+	found, err := s.cache.Get(ctx, revokedSessionKeyPrefix+sessionID, &revoked)
+	if err != nil {
+		s.logger.Warn("session denylist check failed", zap.String("session_id", sessionID), zap.Error(err))
+		return false, err
+	}
+	return found && revoked, nil
+}
+
+func (s *SessionService) issueTokens(user *postgresql.User, session *postgresql.Session, refreshToken string) (*AuthTokens, error) {
+	accessToken, accessExpiresAt, err := s.jwtManager.Generate(user.ID, user.Email, user.Role, session.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate token: %w", err)
+	}
+
+	return &AuthTokens{
+		SessionID:        session.ID,
+		AccessToken:      accessToken,
+		AccessExpiresAt:  accessExpiresAt,
+		RefreshToken:     refreshToken,
+		RefreshExpiresAt: session.ExpiresAt,
+	}, nil
+}
+
+func (s *SessionService) revokeReused(ctx context.Context, session *postgresql.Session) {
+	s.logger.Warn("refresh token reuse detected, revoking session",
+		zap.String("session_id", session.ID),
+		zap.String("user_id", session.UserID),
+	)
+	if err := s.sessionRepo.Revoke(ctx, session.ID, postgresql.SessionRevokedTokenReuse); err != nil {
+		s.logger.Error("failed to revoke session", zap.String("session_id", session.ID), zap.Error(err))
+		return
+	}
+	s.denySession(ctx, session.ID)
+}
+
+// denySession запрещает уже выданные access токены сессии до их истечения
+func (s *SessionService) denySession(ctx context.Context, sessionID string) {
+	if err := s.cache.Set(ctx, revokedSessionKeyPrefix+sessionID, true, s.jwtManager.TokenDuration()); err != nil {
+		s.logger.Warn("failed to add session to denylist", zap.String("session_id", sessionID), zap.Error(err))
+	}
+}
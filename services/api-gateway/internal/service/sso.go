@@ -0,0 +1,323 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/egrul-system/services/api-gateway/internal/auth"
+	"github.com/egrul-system/services/api-gateway/internal/cache"
+	"github.com/egrul-system/services/api-gateway/internal/config"
+	"github.com/egrul-system/services/api-gateway/internal/repository/postgresql"
+	"go.uber.org/zap"
+)
+
+var (
+	// ErrInvalidSSOState вход не начат, уже завершен или state истек
+	ErrInvalidSSOState = errors.New("sso login state is invalid or expired")
+	// ErrSSORedirectNotAllowed адрес возврата не входит в список разрешенных
+	ErrSSORedirectNotAllowed = errors.New("redirect uri is not allowed")
+	// ErrSSOEmailRequired провайдер не передал email пользователя
+	ErrSSOEmailRequired = errors.New("identity provider did not return an email")
+	// ErrSSOAccountConflict email занят учетной записью, которую нельзя связать с SSO
+	ErrSSOAccountConflict = errors.New("an account with this email already exists and cannot be linked to single sign-on")
+)
+
+// ssoStateKeyPrefix префикс ключей Redis для незавершенных входов через SSO
+const ssoStateKeyPrefix = "auth:oidc_state:"
+
+// ssoLoginState параметры начатого входа, нужные для его завершения
+type ssoLoginState struct {
+	Nonce        string `json:"nonce"`
+	CodeVerifier string `json:"code_verifier"`
+	RedirectURI  string `json:"redirect_uri"`
+}
+
+// SSOLoginStart ссылка на страницу входа провайдера и state для проверки
+// на странице возврата
+type SSOLoginStart struct {
+	AuthorizationURL string
+	State            string
+}
+
+// SSOService вход через корпоративный OpenID Connect провайдер (authorization
+// code + PKCE). Пользователи связываются с провайдером по (issuer, subject) и
+// создаются при первом входе; роль может назначаться по группам провайдера.
+type SSOService struct {
+	provider   *auth.OIDCProvider
+	userRepo   *postgresql.UserRepository
+	sessions   *SessionService
+	cache      cache.Cache
+	cfg        config.OIDCConfig
+	groupRoles map[string]string
+	logger     *zap.Logger
+}
+
+// NewSSOService создает новый сервис SSO
+func NewSSOService(
+	provider *auth.OIDCProvider,
+	userRepo *postgresql.UserRepository,
+	sessions *SessionService,
+	cache cache.Cache,
+	cfg config.OIDCConfig,
+	logger *zap.Logger,
+) (*SSOService, error) {
+	groupRoles, err := parseGroupRoles(cfg.GroupRoles)
+	if err != nil {
+		return nil, err
+	}
+	if len(cfg.RedirectURLs) == 0 {
+		return nil, errors.New("oidc: at least one redirect url is required")
+	}
+	if cfg.StateTTL <= 0 {
+		cfg.StateTTL = 10 * time.Minute
+	}
+	if cfg.GroupsClaim == "" {
+		cfg.GroupsClaim = "groups"
+	}
+
+	return &SSOService{
+		provider:   provider,
+		userRepo:   userRepo,
+		sessions:   sessions,
+		cache:      cache,
+		cfg:        cfg,
+		groupRoles: groupRoles,
+		logger:     logger.Named("sso_service"),
+	}, nil
+}
+
+// Begin начинает вход: сохраняет state, nonce и PKCE verifier и возвращает
+// ссылку на провайдера. Пустой redirectURI - первый из разрешенных.
+func (s *SSOService) Begin(ctx context.Context, redirectURI string) (*SSOLoginStart, error) {
+	if redirectURI == "" {
+		redirectURI = s.cfg.RedirectURLs[0]
+	}
+	if !s.redirectAllowed(redirectURI) {
+		return nil, ErrSSORedirectNotAllowed
+	}
+
+	state, _, err := newSecretToken()
+	if err != nil {
+		return nil, err
+	}
+	nonce, _, err := newSecretToken()
+	if err != nil {
+		return nil, err
+	}
+	verifier, err := auth.NewPKCEVerifier()
+	if err != nil {
+		return nil, err
+	}
+
+	authURL, err := s.provider.AuthCodeURL(ctx, redirectURI, state, nonce, verifier)
+	if err != nil {
+		return nil, err
+	}
+
+	loginState := ssoLoginState{Nonce: nonce, CodeVerifier: verifier, RedirectURI: redirectURI}
+	if err := s.cache.Set(ctx, ssoStateKeyPrefix+state, loginState, s.cfg.StateTTL); err != nil {
+		return nil, fmt.Errorf("failed to save sso state: %w", err)
+	}
+
+	return &SSOLoginStart{AuthorizationURL: authURL, State: state}, nil
+}
+
+// Complete завершает вход по коду авторизации с страницы возврата: проверяет
+// ID токен, находит или создает пользователя и открывает сессию
+func (s *SSOService) Complete(ctx context.Context, code, state string, client auth.ClientInfo) (*AuthTokens, *postgresql.User, error) {
+	if code == "" || state == "" {
+		return nil, nil, ErrInvalidSSOState
+	}
+
+	var loginState ssoLoginState
+	found, err := s.cache.Get(ctx, ssoStateKeyPrefix+state, &loginState)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load sso state: %w", err)
+	}
+	if !found {
+		return nil, nil, ErrInvalidSSOState
+	}
+	// state одноразовый
+	if err := s.cache.Delete(ctx, ssoStateKeyPrefix+state); err != nil {
+		s.logger.Warn("failed to delete sso state", zap.Error(err))
+	}
+
+	idToken, err := s.provider.Exchange(ctx, code, loginState.RedirectURI, loginState.CodeVerifier)
+	if err != nil {
+		return nil, nil, err
+	}
+	identity, err := s.provider.VerifyIDToken(ctx, idToken, loginState.Nonce)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	user, err := s.resolveUser(ctx, identity)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !user.IsActive {
+		return nil, nil, errors.New("user account is disabled")
+	}
+
+	if err := s.userRepo.UpdateLastLogin(ctx, user.ID); err != nil {
+		s.logger.Error("failed to update last login", zap.Error(err), zap.String("user_id", user.ID))
+	}
+
+	tokens, err := s.sessions.CreateSession(ctx, user, client)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	s.logger.Info("user logged in via sso",
+		zap.String("user_id", user.ID),
+		zap.String("issuer", identity.Issuer),
+		zap.String("role", user.Role),
+	)
+	return tokens, user, nil
+}
+
+// resolveUser пользователь, связанный с учетной записью провайдера. При первом
+// входе связывает существующего пользователя с тем же подтвержденным email или
+// создает нового. Роль синхронизируется с группами, если задано соответствие.
+func (s *SSOService) resolveUser(ctx context.Context, identity *auth.OIDCIdentity) (*postgresql.User, error) {
+	groupRole := roleForGroups(s.groupRoles, identity.StringList(s.cfg.GroupsClaim))
+
+	user, err := s.userRepo.GetByOIDCSubject(ctx, identity.Issuer, identity.Subject)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		user, err = s.linkOrCreateUser(ctx, identity, groupRole)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(s.groupRoles) > 0 {
+		if groupRole == "" {
+			groupRole = auth.RoleUser
+		}
+		if user.Role != groupRole {
+			s.syncRole(ctx, user, groupRole)
+		}
+	}
+
+	return user, nil
+}
+
+func (s *SSOService) linkOrCreateUser(ctx context.Context, identity *auth.OIDCIdentity, groupRole string) (*postgresql.User, error) {
+	if identity.Email == "" {
+		return nil, ErrSSOEmailRequired
+	}
+
+	existing, err := s.userRepo.GetByEmail(ctx, identity.Email)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		if !s.cfg.LinkByEmail || !identity.EmailVerified {
+			return nil, ErrSSOAccountConflict
+		}
+		linked, err := s.userRepo.LinkOIDCSubject(ctx, existing.ID, identity.Issuer, identity.Subject)
+		if err != nil {
+			return nil, err
+		}
+		if !linked {
+			return nil, ErrSSOAccountConflict
+		}
+		return existing, nil
+	}
+
+	role := groupRole
+	if role == "" {
+		role = auth.RoleUser
+	}
+	// Без пароля: password_hash пустой, вход по паролю невозможен
+	user := &postgresql.User{
+		Email:         identity.Email,
+		FirstName:     identity.GivenName,
+		LastName:      identity.FamilyName,
+		Role:          role,
+		IsActive:      true,
+		EmailVerified: identity.EmailVerified,
+	}
+	if err := s.userRepo.Create(ctx, user); err != nil {
+		return nil, err
+	}
+	if _, err := s.userRepo.LinkOIDCSubject(ctx, user.ID, identity.Issuer, identity.Subject); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("user created via sso", zap.String("user_id", user.ID), zap.String("role", role))
+	return user, nil
+}
+
+// syncRole назначает роль из групп провайдера; остальные сессии пользователя
+// отзываются, как при смене роли администратором. Ошибка не прерывает вход.
+func (s *SSOService) syncRole(ctx context.Context, user *postgresql.User, role string) {
+	if err := s.userRepo.UpdateRole(ctx, user.ID, role); err != nil {
+		s.logger.Error("failed to sync role from sso groups", zap.Error(err), zap.String("user_id", user.ID))
+		return
+	}
+	if err := s.sessions.RevokeAll(ctx, user.ID, "", postgresql.SessionRevokedRoleChanged); err != nil {
+		s.logger.Error("failed to revoke sessions after role change", zap.Error(err), zap.String("user_id", user.ID))
+	}
+
+	s.logger.Info("user role synced from sso groups",
+		zap.String("user_id", user.ID),
+		zap.String("old_role", user.Role),
+		zap.String("new_role", role),
+	)
+	user.Role = role
+}
+
+func (s *SSOService) redirectAllowed(redirectURI string) bool {
+	for _, allowed := range s.cfg.RedirectURLs {
+		if allowed == redirectURI {
+			return true
+		}
+	}
+	return false
+}
+
+// parseGroupRoles разбирает соответствие "group=role;group=role". Имя группы
+// может содержать "=" (DN из LDAP), поэтому роль отделяется по последнему "=".
+func parseGroupRoles(spec string) (map[string]string, error) {
+	result := make(map[string]string)
+	for _, entry := range strings.Split(spec, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		i := strings.LastIndex(entry, "=")
+		if i <= 0 {
+			return nil, fmt.Errorf("oidc: invalid group role mapping %q, expected group=role", entry)
+		}
+		group := strings.TrimSpace(entry[:i])
+		role := strings.TrimSpace(entry[i+1:])
+		if !auth.IsValidRole(role) {
+			return nil, fmt.Errorf("oidc: unknown role %q for group %q", role, group)
+		}
+		result[group] = role
+	}
+	return result, nil
+}
+
+// roleForGroups старшая роль среди групп пользователя; пустая строка, если ни
+// одна группа не сопоставлена роли
+func roleForGroups(groupRoles map[string]string, groups []string) string {
+	best := ""
+	for _, group := range groups {
+		role, ok := groupRoles[group]
+		if !ok {
+			continue
+		}
+		if best == "" || !auth.HasRole(best, role) {
+			best = role
+		}
+	}
+	return best
+}
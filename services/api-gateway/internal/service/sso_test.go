@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/egrul-system/services/api-gateway/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestParseGroupRoles(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    string
+		want    map[string]string
+		wantErr bool
+	}{
+		{name: "empty", spec: "", want: map[string]string{}},
+		{
+			name: "several groups",
+			spec: "egrul-admins=admin; egrul-analysts=analyst;",
+			want: map[string]string{"egrul-admins": "admin", "egrul-analysts": "analyst"},
+		},
+		{
+			name: "ldap dn",
+			spec: "cn=analysts,ou=groups,dc=corp=analyst",
+			want: map[string]string{"cn=analysts,ou=groups,dc=corp": "analyst"},
+		},
+		{name: "unknown role", spec: "egrul-admins=root", wantErr: true},
+		{name: "missing role", spec: "egrul-admins", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseGroupRoles(tt.spec)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRoleForGroups(t *testing.T) {
+	groupRoles := map[string]string{
+		"egrul-users":    "user",
+		"egrul-analysts": "analyst",
+		"egrul-admins":   "admin",
+	}
+
+	assert.Equal(t, "", roleForGroups(groupRoles, nil))
+	assert.Equal(t, "", roleForGroups(groupRoles, []string{"accounting"}))
+	assert.Equal(t, "analyst", roleForGroups(groupRoles, []string{"egrul-users", "egrul-analysts"}))
+	// Старшая роль не зависит от порядка групп
+	assert.Equal(t, "admin", roleForGroups(groupRoles, []string{"egrul-admins", "egrul-analysts"}))
+	assert.Equal(t, "admin", roleForGroups(groupRoles, []string{"egrul-analysts", "egrul-admins"}))
+}
+
+func TestSSOService_BeginRejectsUnknownRedirect(t *testing.T) {
+	// Arrange: без провайдера - адрес возврата проверяется до обращения к нему
+	svc, err := NewSSOService(nil, nil, nil, nil, config.OIDCConfig{
+		RedirectURLs: []string{"http://localhost:3000/auth/sso/callback"},
+	}, zap.NewNop())
+	require.NoError(t, err)
+
+	// Act
+	_, err = svc.Begin(context.Background(), "https://evil.example.com/callback")
+
+	// Assert
+	assert.ErrorIs(t, err, ErrSSORedirectNotAllowed)
+}
+
+func TestNewSSOService_RejectsInvalidGroupRoles(t *testing.T) {
+	_, err := NewSSOService(nil, nil, nil, nil, config.OIDCConfig{
+		RedirectURLs: []string{"http://localhost:3000/auth/sso/callback"},
+		GroupRoles:   "egrul-admins=superuser",
+	}, zap.NewNop())
+	assert.Error(t, err)
+}
@@ -3,22 +3,43 @@ package service
 import (
 	"context"
 	"fmt"
+	"regexp"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/egrul-system/services/api-gateway/internal/graph/model"
-	"github.com/egrul-system/services/api-gateway/internal/repository/clickhouse"
 	"go.uber.org/zap"
 )
 
+// StatisticsStore витрины статистики. Реализуется clickhouse.StatisticsRepository;
+// в тестах подменяется, чтобы проверять лимиты и передачу фильтров без ClickHouse.
+type StatisticsStore interface {
+	GetStatistics(ctx context.Context, filter *model.StatsFilter) (*model.Statistics, error)
+	GetActivityStats(ctx context.Context, filter *model.StatsFilter, level *model.OkvedLevel, limit int) ([]*model.ActivityStatistics, error)
+	GetOkvedChildren(ctx context.Context, parentCode string) ([]*model.OkvedNode, error)
+	GetRegistrationsByMonth(ctx context.Context, dateFrom, dateTo *time.Time, entityType *model.EntityType, filter *model.StatsFilter) ([]*model.TimeSeriesPoint, error)
+	GetRegionHeatmap(ctx context.Context, filter *model.StatsFilter) ([]*model.RegionStatistics, error)
+	GetTopOwners(ctx context.Context, filter *model.StatsFilter, ownerType *model.FounderType, sortBy model.TopOwnerSortField, order model.SortOrder, limit int) ([]*model.TopOwner, error)
+	GetOwnershipByType(ctx context.Context, filter *model.StatsFilter) ([]*model.OwnershipTypeStatistics, error)
+	GetLegalForms(ctx context.Context, filter *model.StatsFilter, limit int) ([]*model.LegalFormStatistics, error)
+	GetChangesByMonth(ctx context.Context, filter *model.StatsFilter, entityType *model.EntityType, reasonCode *string) ([]*model.ChangesByMonthPoint, error)
+	GetChangesByDay(ctx context.Context, filter *model.StatsFilter, changeTypes []string, significantOnly bool, from, to time.Time) ([]*model.ChangesByDayPoint, error)
+	GetMostChangedCompanies(ctx context.Context, filter *model.StatsFilter, month time.Time, limit int) ([]*model.MostChangedCompany, error)
+	GetSignificantChangesByRegion(ctx context.Context, filter *model.StatsFilter, changeTypes []string, from, to time.Time) ([]*model.RegionChangesStatistics, error)
+	GetSurvivalCohorts(ctx context.Context, granularity model.CohortGranularity, entityType *model.EntityType, filter *model.StatsFilter) ([]*model.SurvivalCohortCounts, error)
+	GetTimeSeries(ctx context.Context, metric model.TimeSeriesMetric, granularity model.TimeGranularity, groupBy *model.TimeSeriesGroupBy, entityType *model.EntityType, filter *model.StatsFilter, from, to time.Time) ([]*model.GroupedTimeSeriesPoint, error)
+}
+
 // StatisticsService сервис для работы со статистикой
 type StatisticsService struct {
-	statsRepo *clickhouse.StatisticsRepository
+	statsRepo StatisticsStore
 	logger    *zap.Logger
 }
 
 // NewStatisticsService создает новый сервис статистики
 func NewStatisticsService(
-	statsRepo *clickhouse.StatisticsRepository,
+	statsRepo StatisticsStore,
 	logger *zap.Logger,
 ) *StatisticsService {
 	return &StatisticsService{
@@ -32,12 +53,31 @@ func (s *StatisticsService) GetStatistics(ctx context.Context, filter *model.Sta
 	return s.statsRepo.GetStatistics(ctx, filter)
 }
 
-// GetActivityStats получает статистику по видам деятельности
-func (s *StatisticsService) GetActivityStats(ctx context.Context, limit int) ([]*model.ActivityStatistics, error) {
+// GetActivityStats получает статистику по видам деятельности, свернутую до уровня level
+// (nil - по точному коду)
+func (s *StatisticsService) GetActivityStats(ctx context.Context, filter *model.StatsFilter, level *model.OkvedLevel, limit int) ([]*model.ActivityStatistics, error) {
 	if limit <= 0 {
 		limit = 20
 	}
-	return s.statsRepo.GetActivityStats(ctx, limit)
+	if level != nil && !level.IsValid() {
+		return nil, fmt.Errorf("invalid level: %s", *level)
+	}
+	return s.statsRepo.GetActivityStats(ctx, filter, level, limit)
+}
+
+// okvedCodePattern код ОКВЭД2 (62, 62.0, 62.01, 62.02.1, 62.02.11) или буква раздела
+var okvedCodePattern = regexp.MustCompile(`^([A-U]|\d{2}(\.\d{1,2}){0,2})$`)
+
+// GetOkvedTree возвращает дочерние узлы справочника ОКВЭД2; без parent - разделы
+func (s *StatisticsService) GetOkvedTree(ctx context.Context, parent *string) ([]*model.OkvedNode, error) {
+	parentCode := ""
+	if parent != nil {
+		parentCode = strings.ToUpper(strings.TrimSpace(*parent))
+	}
+	if parentCode != "" && !okvedCodePattern.MatchString(parentCode) {
+		return nil, fmt.Errorf("invalid parent: %s", *parent)
+	}
+	return s.statsRepo.GetOkvedChildren(ctx, parentCode)
 }
 
 // GetDashboardStatistics получает расширенную статистику для дашборда
@@ -74,6 +114,266 @@ func (s *StatisticsService) GetRegistrationsByMonth(ctx context.Context, dateFro
 }
 
 // GetRegionHeatmap получает статистику для всех регионов (тепловая карта)
-func (s *StatisticsService) GetRegionHeatmap(ctx context.Context) ([]*model.RegionStatistics, error) {
-	return s.statsRepo.GetRegionHeatmap(ctx)
+func (s *StatisticsService) GetRegionHeatmap(ctx context.Context, filter *model.StatsFilter) ([]*model.RegionStatistics, error) {
+	return s.statsRepo.GetRegionHeatmap(ctx, filter)
+}
+
+// GetTopOwners получает топ владельцев по числу компаний или средней доле
+func (s *StatisticsService) GetTopOwners(ctx context.Context, filter *model.StatsFilter, ownerType *model.FounderType, sortBy *model.TopOwnerSortField, order *model.SortOrder, limit *int) ([]*model.TopOwner, error) {
+	field := model.TopOwnerSortFieldCompaniesCount
+	if sortBy != nil {
+		if !sortBy.IsValid() {
+			return nil, fmt.Errorf("invalid sortBy: %s", *sortBy)
+		}
+		field = *sortBy
+	}
+	direction := model.SortOrderDesc
+	if order != nil {
+		direction = *order
+	}
+	if ownerType != nil && !ownerType.IsValid() {
+		return nil, fmt.Errorf("invalid ownerType: %s", *ownerType)
+	}
+
+	l := 20
+	if limit != nil && *limit > 0 {
+		l = *limit
+	}
+	if l > 100 {
+		l = 100
+	}
+
+	return s.statsRepo.GetTopOwners(ctx, filter, ownerType, field, direction, l)
+}
+
+// GetOwnershipByType получает распределение долей по типам учредителей
+func (s *StatisticsService) GetOwnershipByType(ctx context.Context, filter *model.StatsFilter) ([]*model.OwnershipTypeStatistics, error) {
+	return s.statsRepo.GetOwnershipByType(ctx, filter)
+}
+
+// GetLegalForms получает распределение компаний по организационно-правовым формам
+func (s *StatisticsService) GetLegalForms(ctx context.Context, filter *model.StatsFilter, limit *int) ([]*model.LegalFormStatistics, error) {
+	l := 30
+	if limit != nil && *limit > 0 {
+		l = *limit
+	}
+	return s.statsRepo.GetLegalForms(ctx, filter, l)
+}
+
+// GetChangesByMonth получает число изменений реестра по месяцам и причинам
+func (s *StatisticsService) GetChangesByMonth(ctx context.Context, filter *model.StatsFilter, entityType *model.EntityType, reasonCode *string) ([]*model.ChangesByMonthPoint, error) {
+	return s.statsRepo.GetChangesByMonth(ctx, filter, entityType, reasonCode)
+}
+
+// GetChangesByDay получает число изменений по дням и типам.
+// Период - из аргументов, затем из фильтра, по умолчанию последние 30 дней.
+func (s *StatisticsService) GetChangesByDay(ctx context.Context, filter *model.StatsFilter, types []string, significantOnly *bool, dateFrom, dateTo *string) ([]*model.ChangesByDayPoint, error) {
+	from, to, err := parseDateRange(dateFrom, dateTo, filter, time.Now().AddDate(0, 0, -30))
+	if err != nil {
+		return nil, err
+	}
+	return s.statsRepo.GetChangesByDay(ctx, filter, types, significantOnly != nil && *significantOnly, from, to)
+}
+
+// GetMostChangedCompanies получает компании с наибольшим числом изменений за месяц.
+// По умолчанию - текущий месяц.
+func (s *StatisticsService) GetMostChangedCompanies(ctx context.Context, filter *model.StatsFilter, month *string, limit *int) ([]*model.MostChangedCompany, error) {
+	m := time.Now()
+	if month != nil && *month != "" {
+		parsed, err := time.Parse("2006-01-02", *month)
+		if err != nil {
+			return nil, fmt.Errorf("invalid month format: %w", err)
+		}
+		m = parsed
+	}
+
+	l := 20
+	if limit != nil && *limit > 0 {
+		l = *limit
+	}
+	if l > 100 {
+		l = 100
+	}
+
+	return s.statsRepo.GetMostChangedCompanies(ctx, filter, m, l)
+}
+
+// GetSignificantChangesByRegion получает значимые изменения по регионам.
+// Период - из аргументов, затем из фильтра, по умолчанию последние 12 месяцев.
+func (s *StatisticsService) GetSignificantChangesByRegion(ctx context.Context, types []string, dateFrom, dateTo *string, filter *model.StatsFilter) ([]*model.RegionChangesStatistics, error) {
+	from, to, err := parseDateRange(dateFrom, dateTo, filter, time.Now().AddDate(-1, 0, 0))
+	if err != nil {
+		return nil, err
+	}
+
+	return s.statsRepo.GetSignificantChangesByRegion(ctx, filter, types, from, to)
+}
+
+// parseDateRange разбирает границы периода (YYYY-MM-DD). Незаданные границы берутся
+// из периода фильтра, иначе - defaultFrom и сегодня. Начало позже конца - ошибка.
+func parseDateRange(dateFrom, dateTo *string, filter *model.StatsFilter, defaultFrom time.Time) (time.Time, time.Time, error) {
+	from, to := defaultFrom, time.Now()
+	if filter != nil && filter.DateFrom != nil {
+		from = filter.DateFrom.Time
+	}
+	if filter != nil && filter.DateTo != nil {
+		to = filter.DateTo.Time
+	}
+
+	if dateFrom != nil && *dateFrom != "" {
+		parsed, err := time.Parse("2006-01-02", *dateFrom)
+		if err != nil {
+			return from, to, fmt.Errorf("invalid dateFrom format: %w", err)
+		}
+		from = parsed
+	}
+
+	if dateTo != nil && *dateTo != "" {
+		parsed, err := time.Parse("2006-01-02", *dateTo)
+		if err != nil {
+			return from, to, fmt.Errorf("invalid dateTo format: %w", err)
+		}
+		to = parsed
+	}
+
+	if from.After(to) {
+		return from, to, fmt.Errorf("dateFrom %s is after dateTo %s", from.Format("2006-01-02"), to.Format("2006-01-02"))
+	}
+
+	return from, to, nil
+}
+
+// GetTimeSeries получает ряд показателя с шагом granularity в разрезе groupBy.
+// Период - из фильтра, по умолчанию зависит от шага (30 дней, полгода, год, 10 лет).
+func (s *StatisticsService) GetTimeSeries(
+	ctx context.Context,
+	metric model.TimeSeriesMetric,
+	granularity *model.TimeGranularity,
+	groupBy *model.TimeSeriesGroupBy,
+	entityType *model.EntityType,
+	filter *model.StatsFilter,
+) ([]*model.GroupedTimeSeriesPoint, error) {
+	if !metric.IsValid() {
+		return nil, fmt.Errorf("invalid metric: %s", metric)
+	}
+	step := model.TimeGranularityMonth
+	if granularity != nil {
+		if !granularity.IsValid() {
+			return nil, fmt.Errorf("invalid granularity: %s", *granularity)
+		}
+		step = *granularity
+	}
+	if groupBy != nil && !groupBy.IsValid() {
+		return nil, fmt.Errorf("invalid groupBy: %s", *groupBy)
+	}
+
+	defaultFrom := time.Now().AddDate(-1, 0, 0)
+	switch step {
+	case model.TimeGranularityDay:
+		defaultFrom = time.Now().AddDate(0, 0, -30)
+	case model.TimeGranularityWeek:
+		defaultFrom = time.Now().AddDate(0, -6, 0)
+	case model.TimeGranularityYear:
+		defaultFrom = time.Now().AddDate(-10, 0, 0)
+	}
+	from, to, err := parseDateRange(nil, nil, filter, defaultFrom)
+	if err != nil {
+		return nil, err
+	}
+
+	if metric != model.TimeSeriesMetricNetGrowth {
+		return s.statsRepo.GetTimeSeries(ctx, metric, step, groupBy, entityType, filter, from, to)
+	}
+
+	registrations, err := s.statsRepo.GetTimeSeries(ctx, model.TimeSeriesMetricRegistrations, step, groupBy, entityType, filter, from, to)
+	if err != nil {
+		return nil, err
+	}
+	terminations, err := s.statsRepo.GetTimeSeries(ctx, model.TimeSeriesMetricTerminations, step, groupBy, entityType, filter, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return netGrowthSeries(registrations, terminations), nil
+}
+
+// netGrowthSeries вычитает ряд прекращений из ряда регистраций по совпадающим
+// периоду и группе; точки, которые есть только в одном ряду, сохраняются
+func netGrowthSeries(registrations, terminations []*model.GroupedTimeSeriesPoint) []*model.GroupedTimeSeriesPoint {
+	key := func(p *model.GroupedTimeSeriesPoint) string {
+		if p.GroupKey == nil {
+			return p.Period
+		}
+		return p.Period + "|" + *p.GroupKey
+	}
+
+	result := make([]*model.GroupedTimeSeriesPoint, 0, len(registrations))
+	index := make(map[string]*model.GroupedTimeSeriesPoint, len(registrations))
+	for _, p := range registrations {
+		point := *p
+		result = append(result, &point)
+		index[key(p)] = &point
+	}
+	for _, p := range terminations {
+		if point, ok := index[key(p)]; ok {
+			point.Value -= p.Value
+			continue
+		}
+		point := *p
+		point.Value = -p.Value
+		result = append(result, &point)
+		index[key(p)] = &point
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Period < result[j].Period
+	})
+	return result
+}
+
+// GetSurvivalCohorts получает доли выживших по когортам регистрации (по умолчанию по годам)
+func (s *StatisticsService) GetSurvivalCohorts(ctx context.Context, granularity *model.CohortGranularity, entityType *model.EntityType, filter *model.StatsFilter) ([]*model.SurvivalCohort, error) {
+	step := model.CohortGranularityYear
+	if granularity != nil {
+		if !granularity.IsValid() {
+			return nil, fmt.Errorf("invalid cohortGranularity: %s", *granularity)
+		}
+		step = *granularity
+	}
+
+	counts, err := s.statsRepo.GetSurvivalCohorts(ctx, step, entityType, filter)
+	if err != nil {
+		return nil, err
+	}
+	return buildSurvivalCohorts(counts, step, time.Now()), nil
+}
+
+// buildSurvivalCohorts переводит счетчики в доли. Срок учитывается, только если
+// он истек для всей когорты: от конца когорты до now прошло не меньше N лет.
+func buildSurvivalCohorts(counts []*model.SurvivalCohortCounts, granularity model.CohortGranularity, now time.Time) []*model.SurvivalCohort {
+	result := make([]*model.SurvivalCohort, 0, len(counts))
+	for _, c := range counts {
+		cohortEnd := c.CohortStart.AddDate(0, 3, 0)
+		if granularity == model.CohortGranularityYear {
+			cohortEnd = c.CohortStart.AddDate(1, 0, 0)
+		}
+
+		cohort := &model.SurvivalCohort{
+			Cohort:        c.CohortStart.Format("2006-01-02"),
+			EntitiesCount: c.Total,
+			ActiveCount:   c.Active,
+			Survival:      make([]*model.SurvivalRate, 0, len(model.SurvivalHorizons)),
+		}
+		for i, years := range model.SurvivalHorizons {
+			rate := &model.SurvivalRate{Years: years}
+			if i < len(c.Survived) && c.Total > 0 && !cohortEnd.AddDate(years, 0, 0).After(now) {
+				survived := c.Survived[i]
+				share := float64(survived) / float64(c.Total)
+				rate.SurvivedCount = &survived
+				rate.Share = &share
+			}
+			cohort.Survival = append(cohort.Survival, rate)
+		}
+		result = append(result, cohort)
+	}
+	return result
 }
@@ -0,0 +1,436 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/egrul-system/services/api-gateway/internal/graph/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// statsStore запоминает аргументы последнего запроса к витринам; остальные
+// методы хранилища тестам не нужны
+type statsStore struct {
+	StatisticsStore
+	filter    *model.StatsFilter
+	ownerType *model.FounderType
+	sortBy    model.TopOwnerSortField
+	order     model.SortOrder
+	limit     int
+	calls     int
+
+	types       []string
+	significant bool
+	from, to    time.Time
+	month       time.Time
+}
+
+func (s *statsStore) GetTopOwners(_ context.Context, filter *model.StatsFilter, ownerType *model.FounderType, sortBy model.TopOwnerSortField, order model.SortOrder, limit int) ([]*model.TopOwner, error) {
+	s.calls++
+	s.filter, s.ownerType, s.sortBy, s.order, s.limit = filter, ownerType, sortBy, order, limit
+	return nil, nil
+}
+
+func (s *statsStore) GetOwnershipByType(_ context.Context, filter *model.StatsFilter) ([]*model.OwnershipTypeStatistics, error) {
+	s.calls++
+	s.filter = filter
+	return nil, nil
+}
+
+func (s *statsStore) GetLegalForms(_ context.Context, filter *model.StatsFilter, limit int) ([]*model.LegalFormStatistics, error) {
+	s.calls++
+	s.filter, s.limit = filter, limit
+	return nil, nil
+}
+
+func (s *statsStore) GetChangesByMonth(_ context.Context, filter *model.StatsFilter, _ *model.EntityType, _ *string) ([]*model.ChangesByMonthPoint, error) {
+	s.calls++
+	s.filter = filter
+	return nil, nil
+}
+
+func (s *statsStore) GetChangesByDay(_ context.Context, filter *model.StatsFilter, changeTypes []string, significantOnly bool, from, to time.Time) ([]*model.ChangesByDayPoint, error) {
+	s.calls++
+	s.filter, s.types, s.significant, s.from, s.to = filter, changeTypes, significantOnly, from, to
+	return nil, nil
+}
+
+func (s *statsStore) GetMostChangedCompanies(_ context.Context, filter *model.StatsFilter, month time.Time, limit int) ([]*model.MostChangedCompany, error) {
+	s.calls++
+	s.filter, s.month, s.limit = filter, month, limit
+	return nil, nil
+}
+
+func (s *statsStore) GetSignificantChangesByRegion(_ context.Context, filter *model.StatsFilter, changeTypes []string, from, to time.Time) ([]*model.RegionChangesStatistics, error) {
+	s.calls++
+	s.filter, s.types, s.from, s.to = filter, changeTypes, from, to
+	return nil, nil
+}
+
+func day(s string) time.Time {
+	t, _ := time.Parse("2006-01-02", s)
+	return t
+}
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func seriesPoint(period, group string, value int) *model.GroupedTimeSeriesPoint {
+	return &model.GroupedTimeSeriesPoint{Period: period, GroupKey: &group, Value: value}
+}
+
+func TestNetGrowthSeries_MergesByPeriodAndGroup(t *testing.T) {
+	// Arrange: в марте в регионе 77 были только ликвидации
+	registrations := []*model.GroupedTimeSeriesPoint{
+		seriesPoint("2024-01-01", "77", 10),
+		seriesPoint("2024-02-01", "77", 5),
+		seriesPoint("2024-02-01", "50", 3),
+	}
+	terminations := []*model.GroupedTimeSeriesPoint{
+		seriesPoint("2024-01-01", "77", 4),
+		seriesPoint("2024-03-01", "77", 2),
+	}
+
+	// Act
+	result := netGrowthSeries(registrations, terminations)
+
+	// Assert
+	assert.Len(t, result, 4)
+	assert.Equal(t, 6, result[0].Value)
+	assert.Equal(t, 5, result[1].Value)
+	assert.Equal(t, 3, result[2].Value)
+	assert.Equal(t, "2024-03-01", result[3].Period)
+	assert.Equal(t, -2, result[3].Value)
+	// Исходные ряды не изменяются
+	assert.Equal(t, 10, registrations[0].Value)
+}
+
+func TestBuildSurvivalCohorts_SkipsUnexpiredHorizons(t *testing.T) {
+	// Arrange: когорта 2020 года, сейчас середина 2024 - истекли сроки 1, 2 и 3 года
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	counts := []*model.SurvivalCohortCounts{{
+		CohortStart: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+		Total:       200,
+		Active:      120,
+		Survived:    []int{180, 160, 150, 130, 120},
+	}}
+
+	// Act
+	result := buildSurvivalCohorts(counts, model.CohortGranularityYear, now)
+
+	// Assert
+	assert.Len(t, result, 1)
+	assert.Equal(t, "2020-01-01", result[0].Cohort)
+	assert.Len(t, result[0].Survival, len(model.SurvivalHorizons))
+	assert.InDelta(t, 0.9, *result[0].Survival[0].Share, 1e-9)
+	assert.Equal(t, 150, *result[0].Survival[2].SurvivedCount)
+	assert.Nil(t, result[0].Survival[3].Share)
+	assert.Nil(t, result[0].Survival[4].SurvivedCount)
+}
+
+func TestOkvedCodePattern(t *testing.T) {
+	for _, code := range []string{"J", "62", "62.0", "62.01", "62.02.1", "62.02.11"} {
+		assert.True(t, okvedCodePattern.MatchString(code), code)
+	}
+	for _, code := range []string{"6", "62.", "62.02.111", "Z", "62,01"} {
+		assert.False(t, okvedCodePattern.MatchString(code), code)
+	}
+}
+
+func TestStatisticsService_GetTopOwnersClampsLimit(t *testing.T) {
+	tests := []struct {
+		name  string
+		limit *int
+		want  int
+	}{
+		{"default", nil, 20},
+		{"non-positive falls back to default", intPtr(0), 20},
+		{"within range", intPtr(50), 50},
+		{"above maximum", intPtr(1000), 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			store := &statsStore{}
+			svc := NewStatisticsService(store, zap.NewNop())
+
+			// Act
+			_, err := svc.GetTopOwners(context.Background(), nil, nil, nil, nil, tt.limit)
+
+			// Assert
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, store.limit)
+		})
+	}
+}
+
+func TestStatisticsService_GetTopOwnersPassesFilterAndSort(t *testing.T) {
+	// Arrange
+	store := &statsStore{}
+	svc := NewStatisticsService(store, zap.NewNop())
+	filter := &model.StatsFilter{RegionCode: stringPtr("77")}
+	ownerType := model.FounderTypePerson
+	sortBy := model.TopOwnerSortFieldAvgShare
+	order := model.SortOrderAsc
+
+	// Act
+	_, err := svc.GetTopOwners(context.Background(), filter, &ownerType, &sortBy, &order, nil)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Same(t, filter, store.filter)
+	assert.Equal(t, &ownerType, store.ownerType)
+	assert.Equal(t, model.TopOwnerSortFieldAvgShare, store.sortBy)
+	assert.Equal(t, model.SortOrderAsc, store.order)
+}
+
+func TestStatisticsService_GetTopOwnersDefaultsToCompaniesCountDesc(t *testing.T) {
+	// Arrange
+	store := &statsStore{}
+	svc := NewStatisticsService(store, zap.NewNop())
+
+	// Act
+	_, err := svc.GetTopOwners(context.Background(), nil, nil, nil, nil, nil)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, model.TopOwnerSortFieldCompaniesCount, store.sortBy)
+	assert.Equal(t, model.SortOrderDesc, store.order)
+	assert.Nil(t, store.ownerType)
+}
+
+func TestStatisticsService_GetTopOwnersRejectsInvalidEnums(t *testing.T) {
+	sortBy := model.TopOwnerSortField("NAME")
+	ownerType := model.FounderType("ALIEN")
+
+	tests := []struct {
+		name      string
+		sortBy    *model.TopOwnerSortField
+		ownerType *model.FounderType
+	}{
+		{"invalid sortBy", &sortBy, nil},
+		{"invalid ownerType", nil, &ownerType},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			store := &statsStore{}
+			svc := NewStatisticsService(store, zap.NewNop())
+
+			// Act
+			_, err := svc.GetTopOwners(context.Background(), nil, tt.ownerType, tt.sortBy, nil, nil)
+
+			// Assert
+			assert.Error(t, err)
+			assert.Zero(t, store.calls)
+		})
+	}
+}
+
+func TestStatisticsService_GetLegalFormsDefaultsLimit(t *testing.T) {
+	tests := []struct {
+		name  string
+		limit *int
+		want  int
+	}{
+		{"default", nil, 30},
+		{"non-positive falls back to default", intPtr(-5), 30},
+		{"explicit", intPtr(10), 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			store := &statsStore{}
+			svc := NewStatisticsService(store, zap.NewNop())
+
+			// Act
+			_, err := svc.GetLegalForms(context.Background(), nil, tt.limit)
+
+			// Assert
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, store.limit)
+		})
+	}
+}
+
+func TestStatisticsService_PassesFilterToOwnershipAndChangeStats(t *testing.T) {
+	filter := &model.StatsFilter{RegionCode: stringPtr("50"), Okved: stringPtr("62")}
+
+	tests := []struct {
+		name string
+		call func(svc *StatisticsService) error
+	}{
+		{"ownership by type", func(svc *StatisticsService) error {
+			_, err := svc.GetOwnershipByType(context.Background(), filter)
+			return err
+		}},
+		{"legal forms", func(svc *StatisticsService) error {
+			_, err := svc.GetLegalForms(context.Background(), filter, nil)
+			return err
+		}},
+		{"changes by month", func(svc *StatisticsService) error {
+			_, err := svc.GetChangesByMonth(context.Background(), filter, nil, stringPtr("P13014"))
+			return err
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			store := &statsStore{}
+			svc := NewStatisticsService(store, zap.NewNop())
+
+			// Act
+			err := tt.call(svc)
+
+			// Assert
+			require.NoError(t, err)
+			assert.Equal(t, 1, store.calls)
+			assert.Same(t, filter, store.filter)
+		})
+	}
+}
+
+func TestStatisticsService_GetChangesByDayDefaultsToLast30Days(t *testing.T) {
+	// Arrange
+	store := &statsStore{}
+	svc := NewStatisticsService(store, zap.NewNop())
+	significant := true
+
+	// Act
+	_, err := svc.GetChangesByDay(context.Background(), nil, []string{"director"}, &significant, nil, nil)
+
+	// Assert
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().AddDate(0, 0, -30), store.from, time.Minute)
+	assert.WithinDuration(t, time.Now(), store.to, time.Minute)
+	assert.Equal(t, []string{"director"}, store.types)
+	assert.True(t, store.significant)
+}
+
+func TestStatisticsService_ChangePeriodPrefersArgumentsOverFilter(t *testing.T) {
+	// Arrange: граница из аргумента важнее фильтра, незаданная - берется из фильтра
+	store := &statsStore{}
+	svc := NewStatisticsService(store, zap.NewNop())
+	filter := &model.StatsFilter{
+		DateFrom: &model.Date{Time: day("2024-01-01")},
+		DateTo:   &model.Date{Time: day("2024-06-30")},
+	}
+
+	// Act
+	_, err := svc.GetChangesByDay(context.Background(), filter, nil, nil, stringPtr("2024-03-01"), nil)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, day("2024-03-01"), store.from)
+	assert.Equal(t, day("2024-06-30"), store.to)
+	assert.Same(t, filter, store.filter)
+	assert.False(t, store.significant)
+}
+
+func TestStatisticsService_ChangePeriodValidation(t *testing.T) {
+	tests := []struct {
+		name     string
+		dateFrom *string
+		dateTo   *string
+		filter   *model.StatsFilter
+	}{
+		{"malformed dateFrom", stringPtr("01.03.2024"), nil, nil},
+		{"malformed dateTo", nil, stringPtr("2024-13-01"), nil},
+		{"dateFrom after dateTo", stringPtr("2024-05-01"), stringPtr("2024-04-01"), nil},
+		{"dateFrom after filter dateTo", stringPtr("2024-05-01"), nil, &model.StatsFilter{
+			DateTo: &model.Date{Time: day("2024-04-01")},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			store := &statsStore{}
+			svc := NewStatisticsService(store, zap.NewNop())
+
+			// Act
+			_, byDayErr := svc.GetChangesByDay(context.Background(), tt.filter, nil, nil, tt.dateFrom, tt.dateTo)
+			_, byRegionErr := svc.GetSignificantChangesByRegion(context.Background(), nil, tt.dateFrom, tt.dateTo, tt.filter)
+
+			// Assert
+			assert.Error(t, byDayErr)
+			assert.Error(t, byRegionErr)
+			assert.Zero(t, store.calls)
+		})
+	}
+}
+
+func TestStatisticsService_GetMostChangedCompaniesClampsLimit(t *testing.T) {
+	tests := []struct {
+		name  string
+		limit *int
+		want  int
+	}{
+		{"default", nil, 20},
+		{"non-positive falls back to default", intPtr(-1), 20},
+		{"within range", intPtr(100), 100},
+		{"above maximum", intPtr(101), 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			store := &statsStore{}
+			svc := NewStatisticsService(store, zap.NewNop())
+
+			// Act
+			_, err := svc.GetMostChangedCompanies(context.Background(), nil, nil, tt.limit)
+
+			// Assert
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, store.limit)
+		})
+	}
+}
+
+func TestStatisticsService_GetMostChangedCompaniesMonth(t *testing.T) {
+	// Arrange
+	store := &statsStore{}
+	svc := NewStatisticsService(store, zap.NewNop())
+	filter := &model.StatsFilter{RegionCode: stringPtr("77")}
+
+	// Act
+	_, defaultErr := svc.GetMostChangedCompanies(context.Background(), filter, nil, nil)
+	defaultMonth := store.month
+	_, explicitErr := svc.GetMostChangedCompanies(context.Background(), filter, stringPtr("2024-02-01"), nil)
+	_, invalidErr := svc.GetMostChangedCompanies(context.Background(), filter, stringPtr("2024-02"), nil)
+
+	// Assert
+	require.NoError(t, defaultErr)
+	require.NoError(t, explicitErr)
+	assert.WithinDuration(t, time.Now(), defaultMonth, time.Minute)
+	assert.Equal(t, day("2024-02-01"), store.month)
+	assert.Same(t, filter, store.filter)
+	assert.Error(t, invalidErr)
+	assert.Equal(t, 2, store.calls)
+}
+
+func TestStatisticsService_GetSignificantChangesByRegionDefaultsToLastYear(t *testing.T) {
+	// Arrange
+	store := &statsStore{}
+	svc := NewStatisticsService(store, zap.NewNop())
+	filter := &model.StatsFilter{Okved: stringPtr("62")}
+
+	// Act
+	_, err := svc.GetSignificantChangesByRegion(context.Background(), []string{"director", "address"}, nil, nil, filter)
+
+	// Assert
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().AddDate(-1, 0, 0), store.from, time.Minute)
+	assert.WithinDuration(t, time.Now(), store.to, time.Minute)
+	assert.Equal(t, []string{"director", "address"}, store.types)
+	assert.Same(t, filter, store.filter)
+}
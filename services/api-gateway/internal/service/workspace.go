@@ -0,0 +1,469 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/egrul-system/services/api-gateway/internal/graph/model"
+	"github.com/egrul-system/services/api-gateway/internal/repository/postgresql"
+	"go.uber.org/zap"
+)
+
+// Роли участников рабочего пространства: старшая роль включает права младших
+const (
+	WorkspaceRoleViewer = "viewer"
+	WorkspaceRoleEditor = "editor"
+	WorkspaceRoleOwner  = "owner"
+)
+
+var workspaceRoleRank = map[string]int{
+	WorkspaceRoleViewer: 1,
+	WorkspaceRoleEditor: 2,
+	WorkspaceRoleOwner:  3,
+}
+
+var (
+	// ErrWorkspaceNotFound пространства нет или пользователь в нем не состоит
+	ErrWorkspaceNotFound = errors.New("workspace not found")
+	// ErrWorkspaceForbidden роли участника недостаточно для операции
+	ErrWorkspaceForbidden = errors.New("insufficient workspace role")
+	// ErrWorkspaceLastOwner у пространства должен остаться хотя бы один владелец
+	ErrWorkspaceLastOwner = errors.New("workspace must have at least one owner")
+	// ErrWorkspaceMemberExists пользователь уже состоит в пространстве
+	ErrWorkspaceMemberExists = errors.New("user is already a workspace member")
+	// ErrWorkspaceMemberNotFound участник не найден
+	ErrWorkspaceMemberNotFound = errors.New("workspace member not found")
+	// ErrWorkspaceEntityExists компания или ИП уже есть в избранном или подписках пространства
+	ErrWorkspaceEntityExists = errors.New("entity is already in the workspace")
+	// ErrEntityNoteNotFound заметка не найдена или недоступна пользователю
+	ErrEntityNoteNotFound = errors.New("note not found")
+	// ErrInvalidWorkspaceParams некорректные параметры пространства, участника или заметки
+	ErrInvalidWorkspaceParams = errors.New("invalid workspace parameters")
+)
+
+const (
+	maxWorkspaceNameLength = 100
+	maxEntityNoteLength    = 5000
+)
+
+// WorkspaceStore хранилище пространств, участников и заметок. Реализуется
+// postgresql.WorkspaceRepository; в тестах подменяется, чтобы проверять
+// права участников без БД.
+type WorkspaceStore interface {
+	Create(ctx context.Context, workspace *postgresql.Workspace, ownerID, ownerRole string) error
+	GetForMember(ctx context.Context, workspaceID, userID string) (*postgresql.Workspace, error)
+	GetByUserID(ctx context.Context, userID string) ([]*postgresql.Workspace, error)
+	Rename(ctx context.Context, workspaceID, name string) error
+	Delete(ctx context.Context, workspaceID string) error
+	GetMember(ctx context.Context, workspaceID, userID string) (*postgresql.WorkspaceMember, error)
+	GetMembers(ctx context.Context, workspaceID string) ([]*postgresql.WorkspaceMember, error)
+	AddMember(ctx context.Context, workspaceID, userID, role string) (bool, error)
+	UpdateMemberRole(ctx context.Context, workspaceID, userID, role string) error
+	UpdateMemberChannels(ctx context.Context, workspaceID, userID string, channels model.NotificationChannels) error
+	RemoveMember(ctx context.Context, workspaceID, userID string) error
+	CountMembersWithRole(ctx context.Context, workspaceID, role string) (int, error)
+	CreateNote(ctx context.Context, note *postgresql.EntityNote) error
+	GetNote(ctx context.Context, id string) (*postgresql.EntityNote, error)
+	GetNotes(ctx context.Context, workspaceID, entityType, entityID string) ([]*postgresql.EntityNote, error)
+	UpdateNote(ctx context.Context, id, body string) error
+	DeleteNote(ctx context.Context, id string) error
+}
+
+// WorkspaceService рабочие пространства команд: участники с ролями, общие
+// избранное и подписки, заметки о компаниях и ИП. Уведомления по подпискам
+// пространства рассылаются участникам по их каналам (см. notification-service).
+type WorkspaceService struct {
+	repo             WorkspaceStore
+	favoriteRepo     *postgresql.FavoriteRepository
+	subscriptionRepo *postgresql.SubscriptionRepository
+	userRepo         *postgresql.UserRepository
+	logger           *zap.Logger
+}
+
+// NewWorkspaceService создает новый сервис рабочих пространств
+func NewWorkspaceService(
+	repo WorkspaceStore,
+	favoriteRepo *postgresql.FavoriteRepository,
+	subscriptionRepo *postgresql.SubscriptionRepository,
+	userRepo *postgresql.UserRepository,
+	logger *zap.Logger,
+) *WorkspaceService {
+	return &WorkspaceService{
+		repo:             repo,
+		favoriteRepo:     favoriteRepo,
+		subscriptionRepo: subscriptionRepo,
+		userRepo:         userRepo,
+		logger:           logger.Named("workspace_service"),
+	}
+}
+
+// IsValidWorkspaceRole проверяет, что роль участника известна
+func IsValidWorkspaceRole(role string) bool {
+	_, ok := workspaceRoleRank[role]
+	return ok
+}
+
+// workspaceRoleAllows роль role не младше required
+func workspaceRoleAllows(role, required string) bool {
+	rank, ok := workspaceRoleRank[role]
+	return ok && rank >= workspaceRoleRank[required]
+}
+
+// Authorize проверяет, что пользователь состоит в пространстве с ролью не
+// ниже required, и возвращает пространство
+func (s *WorkspaceService) Authorize(ctx context.Context, userID, workspaceID, required string) (*postgresql.Workspace, error) {
+	if userID == "" || workspaceID == "" {
+		return nil, ErrWorkspaceNotFound
+	}
+
+	workspace, err := s.repo.GetForMember(ctx, workspaceID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if workspace == nil {
+		return nil, ErrWorkspaceNotFound
+	}
+	if !workspaceRoleAllows(workspace.Role, required) {
+		return nil, ErrWorkspaceForbidden
+	}
+	return workspace, nil
+}
+
+// Create создает пространство; создатель становится владельцем
+func (s *WorkspaceService) Create(ctx context.Context, userID, name string) (*postgresql.Workspace, error) {
+	name, err := normalizeWorkspaceName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	workspace := &postgresql.Workspace{Name: name}
+	if err := s.repo.Create(ctx, workspace, userID, WorkspaceRoleOwner); err != nil {
+		return nil, err
+	}
+	return workspace, nil
+}
+
+// List пространства пользователя
+func (s *WorkspaceService) List(ctx context.Context, userID string) ([]*postgresql.Workspace, error) {
+	return s.repo.GetByUserID(ctx, userID)
+}
+
+// Get пространство, в котором состоит пользователь
+func (s *WorkspaceService) Get(ctx context.Context, userID, workspaceID string) (*postgresql.Workspace, error) {
+	return s.Authorize(ctx, userID, workspaceID, WorkspaceRoleViewer)
+}
+
+// Rename переименовывает пространство (владелец)
+func (s *WorkspaceService) Rename(ctx context.Context, userID, workspaceID, name string) (*postgresql.Workspace, error) {
+	name, err := normalizeWorkspaceName(name)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.Authorize(ctx, userID, workspaceID, WorkspaceRoleOwner); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Rename(ctx, workspaceID, name); err != nil {
+		return nil, err
+	}
+	return s.repo.GetForMember(ctx, workspaceID, userID)
+}
+
+// Delete удаляет пространство с его избранным, подписками и заметками (владелец)
+func (s *WorkspaceService) Delete(ctx context.Context, userID, workspaceID string) error {
+	if _, err := s.Authorize(ctx, userID, workspaceID, WorkspaceRoleOwner); err != nil {
+		return err
+	}
+	return s.repo.Delete(ctx, workspaceID)
+}
+
+// Members участники пространства
+func (s *WorkspaceService) Members(ctx context.Context, userID, workspaceID string) ([]*postgresql.WorkspaceMember, error) {
+	if _, err := s.Authorize(ctx, userID, workspaceID, WorkspaceRoleViewer); err != nil {
+		return nil, err
+	}
+	return s.repo.GetMembers(ctx, workspaceID)
+}
+
+// AddMember добавляет зарегистрированного пользователя по email (владелец)
+func (s *WorkspaceService) AddMember(ctx context.Context, userID, workspaceID, email, role string) (*postgresql.WorkspaceMember, error) {
+	if !IsValidWorkspaceRole(role) {
+		return nil, fmt.Errorf("%w: unknown role %q", ErrInvalidWorkspaceParams, role)
+	}
+	if _, err := s.Authorize(ctx, userID, workspaceID, WorkspaceRoleOwner); err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.GetByEmail(ctx, strings.TrimSpace(email))
+	if err != nil {
+		return nil, err
+	}
+	if user == nil || !user.IsActive {
+		return nil, fmt.Errorf("%w: no active user with this email", ErrInvalidWorkspaceParams)
+	}
+
+	added, err := s.repo.AddMember(ctx, workspaceID, user.ID, role)
+	if err != nil {
+		return nil, err
+	}
+	if !added {
+		return nil, ErrWorkspaceMemberExists
+	}
+	return s.repo.GetMember(ctx, workspaceID, user.ID)
+}
+
+// SetMemberRole меняет роль участника (владелец). Последнего владельца
+// понизить нельзя.
+func (s *WorkspaceService) SetMemberRole(ctx context.Context, userID, workspaceID, memberID, role string) (*postgresql.WorkspaceMember, error) {
+	if !IsValidWorkspaceRole(role) {
+		return nil, fmt.Errorf("%w: unknown role %q", ErrInvalidWorkspaceParams, role)
+	}
+	if _, err := s.Authorize(ctx, userID, workspaceID, WorkspaceRoleOwner); err != nil {
+		return nil, err
+	}
+
+	member, err := s.repo.GetMember(ctx, workspaceID, memberID)
+	if err != nil {
+		return nil, err
+	}
+	if member == nil {
+		return nil, ErrWorkspaceMemberNotFound
+	}
+	if member.Role == role {
+		return member, nil
+	}
+	if member.Role == WorkspaceRoleOwner {
+		if err := s.ensureAnotherOwner(ctx, workspaceID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.repo.UpdateMemberRole(ctx, workspaceID, memberID, role); err != nil {
+		return nil, err
+	}
+	member.Role = role
+	return member, nil
+}
+
+// RemoveMember исключает участника (владелец) или выходит из пространства
+// (сам участник). Последний владелец выйти не может - только удалить пространство.
+func (s *WorkspaceService) RemoveMember(ctx context.Context, userID, workspaceID, memberID string) error {
+	required := WorkspaceRoleOwner
+	if memberID == userID {
+		required = WorkspaceRoleViewer
+	}
+	if _, err := s.Authorize(ctx, userID, workspaceID, required); err != nil {
+		return err
+	}
+
+	member, err := s.repo.GetMember(ctx, workspaceID, memberID)
+	if err != nil {
+		return err
+	}
+	if member == nil {
+		return ErrWorkspaceMemberNotFound
+	}
+	if member.Role == WorkspaceRoleOwner {
+		if err := s.ensureAnotherOwner(ctx, workspaceID); err != nil {
+			return err
+		}
+	}
+
+	return s.repo.RemoveMember(ctx, workspaceID, memberID)
+}
+
+// SetNotificationChannels каналы, по которым пользователь получает
+// уведомления по подпискам пространства
+func (s *WorkspaceService) SetNotificationChannels(ctx context.Context, userID, workspaceID string, channels model.NotificationChannels) (*postgresql.WorkspaceMember, error) {
+	if _, err := s.Authorize(ctx, userID, workspaceID, WorkspaceRoleViewer); err != nil {
+		return nil, err
+	}
+	if err := s.repo.UpdateMemberChannels(ctx, workspaceID, userID, channels); err != nil {
+		return nil, err
+	}
+	return s.repo.GetMember(ctx, workspaceID, userID)
+}
+
+// Favorites избранное пространства
+func (s *WorkspaceService) Favorites(ctx context.Context, userID, workspaceID string) ([]*model.Favorite, error) {
+	if _, err := s.Authorize(ctx, userID, workspaceID, WorkspaceRoleViewer); err != nil {
+		return nil, err
+	}
+	return s.favoriteRepo.GetByWorkspaceID(ctx, workspaceID)
+}
+
+// AddFavorite добавляет запись в избранное пространства (редактор)
+func (s *WorkspaceService) AddFavorite(ctx context.Context, userID, workspaceID string, favorite *model.Favorite) error {
+	if _, err := s.Authorize(ctx, userID, workspaceID, WorkspaceRoleEditor); err != nil {
+		return err
+	}
+
+	exists, err := s.favoriteRepo.HasWorkspaceFavorite(ctx, workspaceID, string(favorite.EntityType), favorite.EntityID)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return ErrWorkspaceEntityExists
+	}
+
+	favorite.UserID = userID
+	favorite.WorkspaceID = &workspaceID
+	return s.favoriteRepo.Create(ctx, favorite)
+}
+
+// Subscriptions подписки пространства
+func (s *WorkspaceService) Subscriptions(ctx context.Context, userID, workspaceID string) ([]*model.EntitySubscription, error) {
+	if _, err := s.Authorize(ctx, userID, workspaceID, WorkspaceRoleViewer); err != nil {
+		return nil, err
+	}
+	return s.subscriptionRepo.GetByWorkspaceID(ctx, workspaceID)
+}
+
+// AddSubscription создает подписку пространства (редактор). Каналы подписки
+// не используются для рассылки: каждый участник получает уведомления по своим.
+func (s *WorkspaceService) AddSubscription(ctx context.Context, userID, workspaceID string, subscription *model.EntitySubscription) error {
+	if _, err := s.Authorize(ctx, userID, workspaceID, WorkspaceRoleEditor); err != nil {
+		return err
+	}
+
+	exists, err := s.subscriptionRepo.HasWorkspaceSubscription(ctx, workspaceID, string(subscription.EntityType), subscription.EntityID)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return ErrWorkspaceEntityExists
+	}
+
+	subscription.UserID = userID
+	subscription.WorkspaceID = &workspaceID
+	return s.subscriptionRepo.Create(ctx, subscription)
+}
+
+// Notes заметки пространства о компании или ИП
+func (s *WorkspaceService) Notes(ctx context.Context, userID, workspaceID, entityType, entityID string) ([]*postgresql.EntityNote, error) {
+	if _, err := s.Authorize(ctx, userID, workspaceID, WorkspaceRoleViewer); err != nil {
+		return nil, err
+	}
+	return s.repo.GetNotes(ctx, workspaceID, entityType, entityID)
+}
+
+// AddNote добавляет заметку о компании или ИП (редактор)
+func (s *WorkspaceService) AddNote(ctx context.Context, userID, workspaceID, entityType, entityID, body string) (*postgresql.EntityNote, error) {
+	body, err := normalizeNoteBody(body)
+	if err != nil {
+		return nil, err
+	}
+	if entityID == "" {
+		return nil, fmt.Errorf("%w: entity id is required", ErrInvalidWorkspaceParams)
+	}
+	if _, err := s.Authorize(ctx, userID, workspaceID, WorkspaceRoleEditor); err != nil {
+		return nil, err
+	}
+
+	note := &postgresql.EntityNote{
+		WorkspaceID: workspaceID,
+		EntityType:  entityType,
+		EntityID:    entityID,
+		AuthorID:    &userID,
+		Body:        body,
+	}
+	if err := s.repo.CreateNote(ctx, note); err != nil {
+		return nil, err
+	}
+	return s.repo.GetNote(ctx, note.ID)
+}
+
+// UpdateNote меняет текст заметки (только автор, пока он редактор пространства)
+func (s *WorkspaceService) UpdateNote(ctx context.Context, userID, noteID, body string) (*postgresql.EntityNote, error) {
+	body, err := normalizeNoteBody(body)
+	if err != nil {
+		return nil, err
+	}
+
+	note, _, err := s.noteForMember(ctx, userID, noteID)
+	if err != nil {
+		return nil, err
+	}
+	if note.AuthorID == nil || *note.AuthorID != userID {
+		return nil, ErrWorkspaceForbidden
+	}
+	if _, err := s.Authorize(ctx, userID, note.WorkspaceID, WorkspaceRoleEditor); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.UpdateNote(ctx, noteID, body); err != nil {
+		return nil, err
+	}
+	return s.repo.GetNote(ctx, noteID)
+}
+
+// DeleteNote удаляет заметку (автор или владелец пространства)
+func (s *WorkspaceService) DeleteNote(ctx context.Context, userID, noteID string) error {
+	note, workspace, err := s.noteForMember(ctx, userID, noteID)
+	if err != nil {
+		return err
+	}
+	isAuthor := note.AuthorID != nil && *note.AuthorID == userID
+	if !isAuthor && workspace.Role != WorkspaceRoleOwner {
+		return ErrWorkspaceForbidden
+	}
+
+	return s.repo.DeleteNote(ctx, noteID)
+}
+
+// noteForMember заметка и пространство, если пользователь в нем состоит.
+// Чужие заметки неотличимы от несуществующих.
+func (s *WorkspaceService) noteForMember(ctx context.Context, userID, noteID string) (*postgresql.EntityNote, *postgresql.Workspace, error) {
+	note, err := s.repo.GetNote(ctx, noteID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if note == nil {
+		return nil, nil, ErrEntityNoteNotFound
+	}
+
+	workspace, err := s.Authorize(ctx, userID, note.WorkspaceID, WorkspaceRoleViewer)
+	if errors.Is(err, ErrWorkspaceNotFound) {
+		return nil, nil, ErrEntityNoteNotFound
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return note, workspace, nil
+}
+
+func (s *WorkspaceService) ensureAnotherOwner(ctx context.Context, workspaceID string) error {
+	owners, err := s.repo.CountMembersWithRole(ctx, workspaceID, WorkspaceRoleOwner)
+	if err != nil {
+		return err
+	}
+	if owners <= 1 {
+		return ErrWorkspaceLastOwner
+	}
+	return nil
+}
+
+func normalizeWorkspaceName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", fmt.Errorf("%w: name is required", ErrInvalidWorkspaceParams)
+	}
+	if utf8.RuneCountInString(name) > maxWorkspaceNameLength {
+		return "", fmt.Errorf("%w: name must be at most %d characters", ErrInvalidWorkspaceParams, maxWorkspaceNameLength)
+	}
+	return name, nil
+}
+
+func normalizeNoteBody(body string) (string, error) {
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return "", fmt.Errorf("%w: note text is required", ErrInvalidWorkspaceParams)
+	}
+	if utf8.RuneCountInString(body) > maxEntityNoteLength {
+		return "", fmt.Errorf("%w: note must be at most %d characters", ErrInvalidWorkspaceParams, maxEntityNoteLength)
+	}
+	return body, nil
+}
@@ -0,0 +1,173 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/egrul-system/services/api-gateway/internal/repository/postgresql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestWorkspaceRoleAllows(t *testing.T) {
+	tests := []struct {
+		role     string
+		required string
+		want     bool
+	}{
+		{WorkspaceRoleOwner, WorkspaceRoleEditor, true},
+		{WorkspaceRoleEditor, WorkspaceRoleEditor, true},
+		{WorkspaceRoleViewer, WorkspaceRoleEditor, false},
+		{WorkspaceRoleEditor, WorkspaceRoleOwner, false},
+		{WorkspaceRoleViewer, WorkspaceRoleViewer, true},
+		{"admin", WorkspaceRoleViewer, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.role+"_"+tt.required, func(t *testing.T) {
+			assert.Equal(t, tt.want, workspaceRoleAllows(tt.role, tt.required))
+		})
+	}
+}
+
+func TestWorkspaceService_RejectsInvalidParamsBeforeQueryingDB(t *testing.T) {
+	// Arrange: без репозиториев - проверки должны срабатывать до обращения к БД
+	svc := NewWorkspaceService(nil, nil, nil, nil, zap.NewNop())
+	ctx := context.Background()
+
+	// Act & Assert
+	_, err := svc.Create(ctx, "user-1", "   ")
+	assert.ErrorIs(t, err, ErrInvalidWorkspaceParams)
+
+	_, err = svc.Create(ctx, "user-1", strings.Repeat("я", maxWorkspaceNameLength+1))
+	assert.ErrorIs(t, err, ErrInvalidWorkspaceParams)
+
+	_, err = svc.AddMember(ctx, "user-1", "ws-1", "user@example.com", "admin")
+	assert.ErrorIs(t, err, ErrInvalidWorkspaceParams)
+
+	_, err = svc.SetMemberRole(ctx, "user-1", "ws-1", "user-2", "")
+	assert.ErrorIs(t, err, ErrInvalidWorkspaceParams)
+
+	_, err = svc.AddNote(ctx, "user-1", "ws-1", "company", "1027700132195", "\n\t")
+	assert.ErrorIs(t, err, ErrInvalidWorkspaceParams)
+
+	_, err = svc.Authorize(ctx, "", "ws-1", WorkspaceRoleViewer)
+	assert.ErrorIs(t, err, ErrWorkspaceNotFound)
+}
+
+// fakeWorkspaceStore одно пространство ws-1 с ролями участников в памяти;
+// методы, не нужные тестам, не реализованы
+type fakeWorkspaceStore struct {
+	WorkspaceStore
+	roles   map[string]string
+	removed []string
+}
+
+func (f *fakeWorkspaceStore) GetForMember(_ context.Context, workspaceID, userID string) (*postgresql.Workspace, error) {
+	role, ok := f.roles[userID]
+	if workspaceID != "ws-1" || !ok {
+		return nil, nil
+	}
+	return &postgresql.Workspace{ID: workspaceID, Name: "Команда", Role: role}, nil
+}
+
+func (f *fakeWorkspaceStore) GetMember(_ context.Context, workspaceID, userID string) (*postgresql.WorkspaceMember, error) {
+	role, ok := f.roles[userID]
+	if workspaceID != "ws-1" || !ok {
+		return nil, nil
+	}
+	return &postgresql.WorkspaceMember{WorkspaceID: workspaceID, UserID: userID, Role: role}, nil
+}
+
+func (f *fakeWorkspaceStore) CountMembersWithRole(_ context.Context, _ string, role string) (int, error) {
+	count := 0
+	for _, r := range f.roles {
+		if r == role {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (f *fakeWorkspaceStore) UpdateMemberRole(_ context.Context, _ string, userID, role string) error {
+	f.roles[userID] = role
+	return nil
+}
+
+func (f *fakeWorkspaceStore) RemoveMember(_ context.Context, _ string, userID string) error {
+	delete(f.roles, userID)
+	f.removed = append(f.removed, userID)
+	return nil
+}
+
+func newFakeWorkspaceService(roles map[string]string) (*WorkspaceService, *fakeWorkspaceStore) {
+	store := &fakeWorkspaceStore{roles: roles}
+	return NewWorkspaceService(store, nil, nil, nil, zap.NewNop()), store
+}
+
+func TestWorkspaceService_Authorize(t *testing.T) {
+	tests := []struct {
+		name     string
+		userID   string
+		required string
+		wantErr  error
+	}{
+		{"owner can manage", "owner", WorkspaceRoleOwner, nil},
+		{"editor can edit", "editor", WorkspaceRoleEditor, nil},
+		{"viewer can read", "viewer", WorkspaceRoleViewer, nil},
+		{"viewer cannot edit", "viewer", WorkspaceRoleEditor, ErrWorkspaceForbidden},
+		{"editor cannot manage", "editor", WorkspaceRoleOwner, ErrWorkspaceForbidden},
+		{"non-member is not told the workspace exists", "stranger", WorkspaceRoleViewer, ErrWorkspaceNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			svc, _ := newFakeWorkspaceService(map[string]string{
+				"owner":  WorkspaceRoleOwner,
+				"editor": WorkspaceRoleEditor,
+				"viewer": WorkspaceRoleViewer,
+			})
+
+			// Act
+			workspace, err := svc.Authorize(context.Background(), tt.userID, "ws-1", tt.required)
+
+			// Assert
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, workspace)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "ws-1", workspace.ID)
+		})
+	}
+}
+
+func TestWorkspaceService_ProtectsLastOwner(t *testing.T) {
+	// Arrange
+	svc, store := newFakeWorkspaceService(map[string]string{
+		"owner":  WorkspaceRoleOwner,
+		"viewer": WorkspaceRoleViewer,
+	})
+	ctx := context.Background()
+
+	// Act & Assert: единственный владелец не может понизить себя или выйти
+	_, err := svc.SetMemberRole(ctx, "owner", "ws-1", "owner", WorkspaceRoleEditor)
+	assert.ErrorIs(t, err, ErrWorkspaceLastOwner)
+
+	err = svc.RemoveMember(ctx, "owner", "ws-1", "owner")
+	assert.ErrorIs(t, err, ErrWorkspaceLastOwner)
+	assert.Empty(t, store.removed)
+	assert.Equal(t, WorkspaceRoleOwner, store.roles["owner"])
+
+	// Act & Assert: после назначения второго владельца первый может выйти
+	_, err = svc.SetMemberRole(ctx, "owner", "ws-1", "viewer", WorkspaceRoleOwner)
+	require.NoError(t, err)
+
+	err = svc.RemoveMember(ctx, "owner", "ws-1", "owner")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"owner"}, store.removed)
+}
@@ -0,0 +1,139 @@
+// Package spreadsheet содержит потоковые читатели и писатели табличных форматов (CSV, XLSX)
+package spreadsheet
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Format формат табличного файла
+type Format string
+
+const (
+	// FormatCSV CSV с разделителем ";" (по умолчанию открывается в Excel с русской локалью)
+	FormatCSV Format = "csv"
+	// FormatXLSX Office Open XML
+	FormatXLSX Format = "xlsx"
+)
+
+// ParseFormat разбирает название формата, по умолчанию CSV
+func ParseFormat(s string) (Format, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "csv":
+		return FormatCSV, nil
+	case "xlsx":
+		return FormatXLSX, nil
+	default:
+		return "", fmt.Errorf("unsupported format: %s", s)
+	}
+}
+
+// ContentType возвращает MIME-тип формата
+func (f Format) ContentType() string {
+	if f == FormatXLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// Extension возвращает расширение файла без точки
+func (f Format) Extension() string {
+	return string(f)
+}
+
+// Writer построчно записывает таблицу
+type Writer interface {
+	// WriteRow записывает одну строку
+	WriteRow(cells []string) error
+	// Close завершает документ и сбрасывает буферы (не закрывает нижележащий io.Writer)
+	Close() error
+}
+
+// NewWriter создает писателя для указанного формата
+func NewWriter(w io.Writer, format Format) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return NewCSVWriter(w), nil
+	case FormatXLSX:
+		return NewXLSXWriter(w)
+	default:
+		return nil, fmt.Errorf("unsupported format: %s", format)
+	}
+}
+
+// CSVWriter пишет CSV с BOM, чтобы Excel корректно распознал UTF-8
+type CSVWriter struct {
+	w           io.Writer
+	csv         *csv.Writer
+	wroteHeader bool
+}
+
+// NewCSVWriter создает CSV писателя
+func NewCSVWriter(w io.Writer) *CSVWriter {
+	cw := csv.NewWriter(w)
+	cw.Comma = ';'
+	return &CSVWriter{w: w, csv: cw}
+}
+
+// WriteRow реализует Writer.WriteRow
+func (c *CSVWriter) WriteRow(cells []string) error {
+	if !c.wroteHeader {
+		c.wroteHeader = true
+		if _, err := c.w.Write([]byte("\xEF\xBB\xBF")); err != nil {
+			return err
+		}
+	}
+	if err := c.csv.Write(cells); err != nil {
+		return err
+	}
+	// Сбрасываем буфер построчно, чтобы данные сразу уходили клиенту
+	c.csv.Flush()
+	return c.csv.Error()
+}
+
+// Close реализует Writer.Close
+func (c *CSVWriter) Close() error {
+	c.csv.Flush()
+	return c.csv.Error()
+}
+
+// ReadRows читает все строки таблицы. Формат определяется по содержимому:
+// ZIP-архив считается XLSX, всё остальное — CSV с автоопределением разделителя.
+func ReadRows(data []byte) ([][]string, error) {
+	if bytes.HasPrefix(data, []byte("PK\x03\x04")) {
+		return readXLSX(data)
+	}
+	return readCSV(data)
+}
+
+// readCSV читает CSV, определяя разделитель по первой строке
+func readCSV(data []byte) ([][]string, error) {
+	data = bytes.TrimPrefix(data, []byte("\xEF\xBB\xBF"))
+
+	firstLine := data
+	if idx := bytes.IndexByte(data, '\n'); idx >= 0 {
+		firstLine = data[:idx]
+	}
+
+	r := csv.NewReader(bytes.NewReader(data))
+	r.FieldsPerRecord = -1
+	r.LazyQuotes = true
+	r.TrimLeadingSpace = true
+	switch {
+	case bytes.Count(firstLine, []byte(";")) > 0:
+		r.Comma = ';'
+	case bytes.Count(firstLine, []byte("\t")) > 0:
+		r.Comma = '\t'
+	default:
+		r.Comma = ','
+	}
+
+	rows, err := r.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("read csv: %w", err)
+	}
+	return rows, nil
+}
@@ -0,0 +1,45 @@
+package spreadsheet
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestXLSXWriter_RoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewXLSXWriter(&buf)
+	require.NoError(t, err)
+
+	require.NoError(t, w.WriteRow([]string{"ИНН", "Название"}))
+	require.NoError(t, w.WriteRow([]string{"7707083893", `ПАО "Сбербанк" & <Co>`}))
+	require.NoError(t, w.WriteRow([]string{"", "пропуск"}))
+	require.NoError(t, w.Close())
+
+	rows, err := ReadRows(buf.Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"ИНН", "Название"},
+		{"7707083893", `ПАО "Сбербанк" & <Co>`},
+		{"", "пропуск"},
+	}, rows)
+}
+
+func TestReadRows_CSVDetectsDelimiter(t *testing.T) {
+	rows, err := ReadRows([]byte("\xEF\xBB\xBFinn;name\n7707083893;Сбербанк\n"))
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{"inn", "name"}, {"7707083893", "Сбербанк"}}, rows)
+
+	rows, err = ReadRows([]byte("1027700132195,x\n"))
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{"1027700132195", "x"}}, rows)
+}
+
+func TestColumnName(t *testing.T) {
+	assert.Equal(t, "A", columnName(0))
+	assert.Equal(t, "Z", columnName(25))
+	assert.Equal(t, "AA", columnName(26))
+	assert.Equal(t, 27, columnIndex("AB12"))
+}
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/egrul/change-detection-service/internal/auth"
 	"github.com/egrul/change-detection-service/internal/config"
 	"github.com/egrul/change-detection-service/internal/detector"
 	"github.com/egrul/change-detection-service/internal/handler"
@@ -118,8 +119,12 @@ func main() {
 
 	// Маршруты
 	r.Get("/health", h.HandleHealth)
-	r.Get("/stats", h.HandleStats)
-	r.Post("/detect", h.HandleDetect)
+	r.Group(func(r chi.Router) {
+		// Служебные эндпоинты: только администраторы и сервисный токен
+		r.Use(middleware.RequireRole(cfg.Auth.JWTSecretKey, cfg.Auth.ServiceToken, auth.RoleAdmin, logger))
+		r.Get("/stats", h.HandleStats)
+		r.Post("/detect", h.HandleDetect)
+	})
 	r.Get("/company/{ogrn}/changes", h.HandleGetCompanyChanges)
 	r.Get("/entrepreneur/{ogrnip}/changes", h.HandleGetEntrepreneurChanges)
 	r.Get("/changes/recent", h.HandleGetRecentChanges)
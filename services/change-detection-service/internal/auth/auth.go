@@ -0,0 +1,66 @@
+// Package auth проверка доступа к служебным эндпоинтам: JWT, выданный
+// api-gateway (HS256, общий секрет), с ролью в claims, либо сервисный токен
+// для скриптов и межсервисных вызовов.
+package auth
+
+import (
+	"crypto/subtle"
+	"errors"
+	"time"
+
+	"github.com/egrul-system/services/shared/pkg/roles"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Роли пользователей (общие с api-gateway): старшая включает права младших
+const (
+	RoleUser    = roles.User
+	RoleAnalyst = roles.Analyst
+	RoleAdmin   = roles.Admin
+)
+
+var (
+	// ErrInvalidToken токен поврежден, подписан другим ключом или истек
+	ErrInvalidToken = errors.New("invalid or expired token")
+)
+
+// Claims claims JWT, которые нужны сервису
+type Claims struct {
+	UserID string `json:"user_id"`
+	Role   string `json:"role,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// HasRole проверяет, что роль не ниже требуемой; пустая роль считается user
+func HasRole(role, required string) bool {
+	return roles.HasRole(role, required)
+}
+
+// VerifyToken проверяет подпись HS256 и срок действия JWT
+func VerifyToken(token, secretKey string, now time.Time) (*Claims, error) {
+	claims := &Claims{}
+	parsed, err := jwt.ParseWithClaims(
+		token,
+		claims,
+		func(*jwt.Token) (interface{}, error) {
+			return []byte(secretKey), nil
+		},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithExpirationRequired(),
+		jwt.WithTimeFunc(func() time.Time { return now }),
+	)
+	if err != nil || !parsed.Valid {
+		return nil, ErrInvalidToken
+	}
+
+	return claims, nil
+}
+
+// ValidServiceToken сравнивает сервисный токен за постоянное время;
+// пустой ожидаемый токен означает, что сервисный доступ выключен
+func ValidServiceToken(token, expected string) bool {
+	if expected == "" || token == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(token), []byte(expected)) == 1
+}
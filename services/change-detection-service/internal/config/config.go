@@ -13,6 +13,7 @@ type Config struct {
 	Server     ServerConfig
 	ClickHouse ClickHouseConfig
 	Kafka      KafkaConfig
+	Auth       AuthConfig
 	Log        LogConfig
 }
 
@@ -44,6 +45,15 @@ type KafkaConfig struct {
 	EntrepreneurChangesTopic string
 }
 
+// AuthConfig доступ к служебным эндпоинтам (/detect, /stats)
+type AuthConfig struct {
+	// JWTSecretKey общий с api-gateway секрет подписи JWT
+	JWTSecretKey string
+	// ServiceToken токен для скриптов и межсервисных вызовов (заголовок
+	// X-Service-Token); пустой - доступ только по JWT администратора
+	ServiceToken string
+}
+
 // LogConfig конфигурация логирования
 type LogConfig struct {
 	Level  string
@@ -85,6 +95,10 @@ func LoadConfig() (*Config, error) {
 			CompanyChangesTopic:     v.GetString("KAFKA_COMPANY_CHANGES_TOPIC"),
 			EntrepreneurChangesTopic: v.GetString("KAFKA_ENTREPRENEUR_CHANGES_TOPIC"),
 		},
+		Auth: AuthConfig{
+			JWTSecretKey: v.GetString("JWT_SECRET_KEY"),
+			ServiceToken: v.GetString("SERVICE_TOKEN"),
+		},
 		Log: LogConfig{
 			Level:  v.GetString("LOG_LEVEL"),
 			Format: v.GetString("LOG_FORMAT"),
@@ -122,6 +136,10 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("KAFKA_COMPANY_CHANGES_TOPIC", "company-changes")
 	v.SetDefault("KAFKA_ENTREPRENEUR_CHANGES_TOPIC", "entrepreneur-changes")
 
+	// Auth
+	v.SetDefault("JWT_SECRET_KEY", "CHANGE_ME_IN_PRODUCTION_MIN_32_CHARS")
+	v.SetDefault("SERVICE_TOKEN", "")
+
 	// Log
 	v.SetDefault("LOG_LEVEL", "info")
 	v.SetDefault("LOG_FORMAT", "json")
@@ -2,8 +2,10 @@ package middleware
 
 import (
 	"net/http"
+	"strings"
 	"time"
 
+	"github.com/egrul/change-detection-service/internal/auth"
 	"go.uber.org/zap"
 )
 
@@ -49,7 +51,7 @@ func CORS() func(http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			w.Header().Set("Access-Control-Allow-Origin", "*")
 			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Service-Token")
 
 			// Обрабатываем preflight запросы
 			if r.Method == "OPTIONS" {
@@ -82,3 +84,41 @@ func Recovery(logger *zap.Logger) func(http.Handler) http.Handler {
 		})
 	}
 }
+
+// RequireRole middleware пропускает запросы с сервисным токеном в заголовке
+// X-Service-Token либо с JWT api-gateway, роль в котором не ниже required.
+// Отзыв сессий (denylist api-gateway) здесь не проверяется: access токены
+// короткоживущие.
+func RequireRole(secretKey, serviceToken, required string, logger *zap.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if auth.ValidServiceToken(r.Header.Get("X-Service-Token"), serviceToken) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok || token == "" || secretKey == "" {
+				http.Error(w, "Authentication required", http.StatusUnauthorized)
+				return
+			}
+
+			claims, err := auth.VerifyToken(token, secretKey, time.Now())
+			if err != nil {
+				http.Error(w, "Invalid or expired token", http.StatusUnauthorized)
+				return
+			}
+			if !auth.HasRole(claims.Role, required) {
+				logger.Warn("access denied",
+					zap.String("path", r.URL.Path),
+					zap.String("user_id", claims.UserID),
+					zap.String("role", claims.Role),
+				)
+				http.Error(w, "Forbidden", http.StatusForbidden)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/egrul/notification-service/internal/channels"
 	"github.com/egrul/notification-service/internal/config"
 	"github.com/egrul/notification-service/internal/consumer"
+	"github.com/egrul/notification-service/internal/model"
 	pgRepo "github.com/egrul/notification-service/internal/repository/postgresql"
 	"github.com/egrul/notification-service/internal/service"
 	_ "github.com/lib/pq"
@@ -80,6 +81,10 @@ func main() {
 	if err != nil {
 		logger.Fatal("Failed to load email templates", zap.Error(err))
 	}
+	accountTemplates, err := loadAccountEmailTemplates()
+	if err != nil {
+		logger.Fatal("Failed to load account email templates", zap.Error(err))
+	}
 	logger.Info("Email templates loaded successfully")
 
 	// Инициализация Email channel
@@ -90,12 +95,13 @@ func main() {
 		Password:      cfg.SMTP.Password,
 		From:          cfg.SMTP.From,
 		FromName:      cfg.SMTP.FromName,
+		AppBaseURL:    cfg.SMTP.AppBaseURL,
 		TLS:           cfg.SMTP.TLS,
 		MaxRetries:    3,
 		RetryInterval: 5 * time.Second,
 		DryRun:        cfg.SMTP.DryRun,
 	}
-	emailChannel := channels.NewEmailChannel(emailConfig, htmlTemplate, textTemplate, logger)
+	emailChannel := channels.NewEmailChannel(emailConfig, htmlTemplate, textTemplate, accountTemplates, logger)
 	defer emailChannel.Close()
 	logger.Info("Email channel initialized",
 		zap.String("smtp_host", cfg.SMTP.Host),
@@ -109,15 +115,17 @@ func main() {
 		subscriptionRepo,
 		notificationLogRepo,
 		emailChannel,
+		emailChannel,
 		logger,
 	)
 
 	// Инициализация Kafka consumer
 	consumerConfig := consumer.ConsumerConfig{
-		Brokers:           cfg.Kafka.Brokers,
-		CompanyTopic:      cfg.Kafka.CompanyChangesTopic,
-		EntrepreneurTopic: cfg.Kafka.EntrepreneurChangesTopic,
-		GroupID:           cfg.Kafka.ConsumerGroup,
+		Brokers:            cfg.Kafka.Brokers,
+		CompanyTopic:       cfg.Kafka.CompanyChangesTopic,
+		EntrepreneurTopic:  cfg.Kafka.EntrepreneurChangesTopic,
+		AccountEmailsTopic: cfg.Kafka.AccountEmailsTopic,
+		GroupID:            cfg.Kafka.ConsumerGroup,
 	}
 	kafkaConsumer := consumer.NewKafkaConsumer(consumerConfig, notificationService, logger)
 	defer kafkaConsumer.Close()
@@ -125,6 +133,7 @@ func main() {
 		zap.Strings("brokers", cfg.Kafka.Brokers),
 		zap.String("company_topic", cfg.Kafka.CompanyChangesTopic),
 		zap.String("entrepreneur_topic", cfg.Kafka.EntrepreneurChangesTopic),
+		zap.String("account_emails_topic", cfg.Kafka.AccountEmailsTopic),
 		zap.String("consumer_group", cfg.Kafka.ConsumerGroup),
 	)
 
@@ -195,3 +204,34 @@ func loadEmailTemplates() (*template.Template, *template.Template, error) {
 
 	return htmlTemplate, textTemplate, nil
 }
+
+// loadAccountEmailTemplates загружает шаблоны писем по учетной записи
+func loadAccountEmailTemplates() (map[string]channels.AccountEmailTemplates, error) {
+	files := []struct {
+		emailType string
+		name      string
+		subject   string
+	}{
+		{model.AccountEmailVerification, "account_email_verification", "Подтверждение адреса электронной почты"},
+		{model.AccountEmailPasswordReset, "account_password_reset", "Сброс пароля"},
+	}
+
+	templates := make(map[string]channels.AccountEmailTemplates, len(files))
+	for _, f := range files {
+		htmlTemplate, err := template.ParseFiles("internal/templates/" + f.name + ".html")
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse HTML template %s: %w", f.name, err)
+		}
+		textTemplate, err := template.ParseFiles("internal/templates/" + f.name + ".txt")
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse text template %s: %w", f.name, err)
+		}
+		templates[f.emailType] = channels.AccountEmailTemplates{
+			Subject: f.subject,
+			HTML:    htmlTemplate,
+			Text:    textTemplate,
+		}
+	}
+
+	return templates, nil
+}
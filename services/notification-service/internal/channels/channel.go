@@ -17,3 +17,9 @@ type NotificationChannel interface {
 	// Close закрывает соединения и освобождает ресурсы
 	Close() error
 }
+
+// AccountEmailSender отправляет письма по учетной записи (подтверждение email,
+// сброс пароля); в отличие от уведомлений не привязаны к подписке
+type AccountEmailSender interface {
+	SendAccountEmail(ctx context.Context, event *model.AccountEmailEvent) error
+}
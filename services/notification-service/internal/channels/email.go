@@ -5,6 +5,8 @@ import (
 	"context"
 	"fmt"
 	"html/template"
+	"net/url"
+	"strings"
 	"time"
 
 	"github.com/egrul/notification-service/internal/model"
@@ -12,17 +14,26 @@ import (
 	"gopkg.in/gomail.v2"
 )
 
+// AccountEmailTemplates шаблоны письма по учетной записи одного типа
+type AccountEmailTemplates struct {
+	Subject string
+	HTML    *template.Template
+	Text    *template.Template
+}
+
 // EmailChannel реализация канала Email через SMTP
 type EmailChannel struct {
-	dialer        *gomail.Dialer
-	from          string
-	fromName      string
-	htmlTemplate  *template.Template
-	textTemplate  *template.Template
-	logger        *zap.Logger
-	maxRetries    int
-	retryInterval time.Duration
-	dryRun        bool // Режим логирования без отправки
+	dialer           *gomail.Dialer
+	from             string
+	fromName         string
+	appBaseURL       string
+	htmlTemplate     *template.Template
+	textTemplate     *template.Template
+	accountTemplates map[string]AccountEmailTemplates
+	logger           *zap.Logger
+	maxRetries       int
+	retryInterval    time.Duration
+	dryRun           bool // Режим логирования без отправки
 }
 
 // EmailConfig конфигурация Email канала
@@ -33,6 +44,7 @@ type EmailConfig struct {
 	Password      string
 	From          string
 	FromName      string
+	AppBaseURL    string // Адрес веб-приложения для ссылок в письмах
 	TLS           bool
 	MaxRetries    int
 	RetryInterval time.Duration
@@ -40,7 +52,12 @@ type EmailConfig struct {
 }
 
 // NewEmailChannel создает новый экземпляр Email канала
-func NewEmailChannel(cfg EmailConfig, htmlTmpl, textTmpl *template.Template, logger *zap.Logger) *EmailChannel {
+func NewEmailChannel(
+	cfg EmailConfig,
+	htmlTmpl, textTmpl *template.Template,
+	accountTemplates map[string]AccountEmailTemplates,
+	logger *zap.Logger,
+) *EmailChannel {
 	dialer := gomail.NewDialer(cfg.Host, cfg.Port, cfg.Username, cfg.Password)
 
 	if !cfg.TLS {
@@ -55,21 +72,27 @@ func NewEmailChannel(cfg EmailConfig, htmlTmpl, textTmpl *template.Template, log
 		cfg.RetryInterval = 5 * time.Second
 	}
 
+	if cfg.AppBaseURL == "" {
+		cfg.AppBaseURL = "http://localhost:3000"
+	}
+
 	// Логируем режим работы
 	if cfg.DryRun {
 		logger.Warn("Email channel in DRY RUN mode - emails will NOT be sent, only logged")
 	}
 
 	return &EmailChannel{
-		dialer:        dialer,
-		from:          cfg.From,
-		fromName:      cfg.FromName,
-		htmlTemplate:  htmlTmpl,
-		textTemplate:  textTmpl,
-		logger:        logger,
-		maxRetries:    cfg.MaxRetries,
-		retryInterval: cfg.RetryInterval,
-		dryRun:        cfg.DryRun,
+		dialer:           dialer,
+		from:             cfg.From,
+		fromName:         cfg.FromName,
+		appBaseURL:       strings.TrimRight(cfg.AppBaseURL, "/"),
+		htmlTemplate:     htmlTmpl,
+		textTemplate:     textTmpl,
+		accountTemplates: accountTemplates,
+		logger:           logger,
+		maxRetries:       cfg.MaxRetries,
+		retryInterval:    cfg.RetryInterval,
+		dryRun:           cfg.DryRun,
 	}
 }
 
@@ -132,40 +155,98 @@ func (c *EmailChannel) Send(ctx context.Context, notification *model.Notificatio
 		return nil
 	}
 
-	// Реальная отправка с retry механизмом
+	if err := c.deliver(msg, notification.UserEmail); err != nil {
+		return err
+	}
+
+	c.logger.Info("email sent successfully",
+		zap.String("to", notification.UserEmail),
+		zap.String("change_id", notification.ChangeEvent.ChangeID),
+	)
+	return nil
+}
+
+// SendAccountEmail отправляет письмо со ссылкой подтверждения email или сброса пароля
+func (c *EmailChannel) SendAccountEmail(ctx context.Context, event *model.AccountEmailEvent) error {
+	tmpl, ok := c.accountTemplates[event.Type]
+	if !ok {
+		return fmt.Errorf("unknown account email type: %s", event.Type)
+	}
+
+	data := model.AccountEmailData{
+		FirstName: event.FirstName,
+		ActionURL: c.accountActionURL(event),
+		ExpiresAt: event.ExpiresAt.UTC(),
+	}
+
+	htmlBody, err := c.renderTemplate(tmpl.HTML, data)
+	if err != nil {
+		return fmt.Errorf("failed to render HTML template: %w", err)
+	}
+
+	textBody, err := c.renderTemplate(tmpl.Text, data)
+	if err != nil {
+		return fmt.Errorf("failed to render text template: %w", err)
+	}
+
+	msg := gomail.NewMessage()
+	msg.SetHeader("From", fmt.Sprintf("%s <%s>", c.fromName, c.from))
+	msg.SetHeader("To", event.Email)
+	msg.SetHeader("Subject", tmpl.Subject)
+	msg.SetBody("text/plain", textBody)
+	msg.AddAlternative("text/html", htmlBody)
+
+	// DRY RUN режим - только логирование (ссылку видно в логе, удобно при разработке)
+	if c.dryRun {
+		c.logger.Info("🔔 [DRY RUN] Account email (NOT SENT)",
+			zap.String("to", event.Email),
+			zap.String("type", event.Type),
+			zap.String("subject", tmpl.Subject),
+			zap.String("action_url", data.ActionURL),
+		)
+		return nil
+	}
+
+	if err := c.deliver(msg, event.Email); err != nil {
+		return err
+	}
+
+	c.logger.Info("account email sent successfully",
+		zap.String("to", event.Email),
+		zap.String("type", event.Type),
+	)
+	return nil
+}
+
+// deliver отправляет письмо через SMTP с повторами
+func (c *EmailChannel) deliver(msg *gomail.Message, to string) error {
 	var lastErr error
 	for attempt := 1; attempt <= c.maxRetries; attempt++ {
 		c.logger.Debug("attempting to send email",
-			zap.String("to", notification.UserEmail),
+			zap.String("to", to),
 			zap.Int("attempt", attempt),
 			zap.Int("max_retries", c.maxRetries),
 		)
 
-		if err := c.dialer.DialAndSend(msg); err != nil {
-			lastErr = err
-			c.logger.Warn("failed to send email",
-				zap.String("to", notification.UserEmail),
-				zap.Int("attempt", attempt),
-				zap.Error(err),
-			)
+		err := c.dialer.DialAndSend(msg)
+		if err == nil {
+			return nil
+		}
 
-			if attempt < c.maxRetries {
-				// Exponential backoff
-				waitTime := c.retryInterval * time.Duration(attempt)
-				c.logger.Info("retrying after delay",
-					zap.Duration("wait_time", waitTime),
-				)
-				time.Sleep(waitTime)
-				continue
-			}
-		} else {
-			// Успешно отправлено
-			c.logger.Info("email sent successfully",
-				zap.String("to", notification.UserEmail),
-				zap.String("change_id", notification.ChangeEvent.ChangeID),
-				zap.Int("attempt", attempt),
+		lastErr = err
+		c.logger.Warn("failed to send email",
+			zap.String("to", to),
+			zap.Int("attempt", attempt),
+			zap.Error(err),
+		)
+
+		if attempt < c.maxRetries {
+			// Exponential backoff
+			waitTime := c.retryInterval * time.Duration(attempt)
+			c.logger.Info("retrying after delay",
+				zap.Duration("wait_time", waitTime),
 			)
-			return nil
+			time.Sleep(waitTime)
 		}
 	}
 
@@ -186,9 +267,9 @@ func (c *EmailChannel) prepareTemplateData(notification *model.Notification) mod
 	// Формируем URL для карточки сущности
 	var entityURL string
 	if event.EntityType == "company" {
-		entityURL = fmt.Sprintf("http://localhost:3000/company/%s", event.EntityID)
+		entityURL = fmt.Sprintf("%s/company/%s", c.appBaseURL, event.EntityID)
 	} else {
-		entityURL = fmt.Sprintf("http://localhost:3000/entrepreneur/%s", event.EntityID)
+		entityURL = fmt.Sprintf("%s/entrepreneur/%s", c.appBaseURL, event.EntityID)
 	}
 
 	return model.EmailNotificationData{
@@ -202,15 +283,24 @@ func (c *EmailChannel) prepareTemplateData(notification *model.Notification) mod
 		IsSignificant:   event.IsSignificant,
 		DetectedAt:      event.DetectedAt,
 		EntityURL:       entityURL,
-		UnsubscribeURL:  fmt.Sprintf("http://localhost:3000/watchlist?action=unsubscribe&id=%s", notification.SubscriptionID),
-		SettingsURL:     "http://localhost:3000/watchlist",
+		UnsubscribeURL:  fmt.Sprintf("%s/watchlist?action=unsubscribe&id=%s", c.appBaseURL, notification.SubscriptionID),
+		SettingsURL:     c.appBaseURL + "/watchlist",
 		ChangeTypeLabel: model.GetChangeTypeLabel(event.ChangeType),
 		FieldNameLabel:  model.GetFieldNameLabel(event.FieldName),
 	}
 }
 
+// accountActionURL ссылка из письма по учетной записи на страницу веб-приложения
+func (c *EmailChannel) accountActionURL(event *model.AccountEmailEvent) string {
+	path := "/verify-email"
+	if event.Type == model.AccountEmailPasswordReset {
+		path = "/reset-password"
+	}
+	return fmt.Sprintf("%s%s?token=%s", c.appBaseURL, path, url.QueryEscape(event.Token))
+}
+
 // renderTemplate рендерит шаблон с данными
-func (c *EmailChannel) renderTemplate(tmpl *template.Template, data model.EmailNotificationData) (string, error) {
+func (c *EmailChannel) renderTemplate(tmpl *template.Template, data interface{}) (string, error) {
 	var buf bytes.Buffer
 	if err := tmpl.Execute(&buf, data); err != nil {
 		return "", err
@@ -41,19 +41,21 @@ type KafkaConfig struct {
 	Brokers                  []string
 	CompanyChangesTopic      string
 	EntrepreneurChangesTopic string
+	AccountEmailsTopic       string
 	ConsumerGroup            string
 }
 
 // SMTPConfig конфигурация SMTP сервера
 type SMTPConfig struct {
-	Host     string
-	Port     int
-	Username string
-	Password string
-	From     string
-	FromName string
-	TLS      bool
-	DryRun   bool // Режим тестирования - только логировать, не отправлять
+	Host       string
+	Port       int
+	Username   string
+	Password   string
+	From       string
+	FromName   string
+	TLS        bool
+	DryRun     bool   // Режим тестирования - только логировать, не отправлять
+	AppBaseURL string // Адрес веб-приложения для ссылок в письмах
 }
 
 // LogConfig конфигурация логирования
@@ -94,17 +96,19 @@ func LoadConfig() (*Config, error) {
 			Brokers:                  v.GetStringSlice("KAFKA_BROKERS"),
 			CompanyChangesTopic:      v.GetString("KAFKA_COMPANY_CHANGES_TOPIC"),
 			EntrepreneurChangesTopic: v.GetString("KAFKA_ENTREPRENEUR_CHANGES_TOPIC"),
+			AccountEmailsTopic:       v.GetString("KAFKA_ACCOUNT_EMAILS_TOPIC"),
 			ConsumerGroup:            v.GetString("KAFKA_CONSUMER_GROUP"),
 		},
 		SMTP: SMTPConfig{
-			Host:     v.GetString("SMTP_HOST"),
-			Port:     v.GetInt("SMTP_PORT"),
-			Username: v.GetString("SMTP_USERNAME"),
-			Password: v.GetString("SMTP_PASSWORD"),
-			From:     v.GetString("SMTP_FROM"),
-			FromName: v.GetString("SMTP_FROM_NAME"),
-			TLS:      v.GetBool("SMTP_TLS"),
-			DryRun:   v.GetBool("EMAIL_DRY_RUN"),
+			Host:       v.GetString("SMTP_HOST"),
+			Port:       v.GetInt("SMTP_PORT"),
+			Username:   v.GetString("SMTP_USERNAME"),
+			Password:   v.GetString("SMTP_PASSWORD"),
+			From:       v.GetString("SMTP_FROM"),
+			FromName:   v.GetString("SMTP_FROM_NAME"),
+			TLS:        v.GetBool("SMTP_TLS"),
+			DryRun:     v.GetBool("EMAIL_DRY_RUN"),
+			AppBaseURL: v.GetString("APP_BASE_URL"),
 		},
 		Log: LogConfig{
 			Level:  v.GetString("LOG_LEVEL"),
@@ -140,6 +144,7 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("KAFKA_BROKERS", []string{"localhost:9092"})
 	v.SetDefault("KAFKA_COMPANY_CHANGES_TOPIC", "company-changes")
 	v.SetDefault("KAFKA_ENTREPRENEUR_CHANGES_TOPIC", "entrepreneur-changes")
+	v.SetDefault("KAFKA_ACCOUNT_EMAILS_TOPIC", "account-emails")
 	v.SetDefault("KAFKA_CONSUMER_GROUP", "notification-service-group")
 
 	// SMTP
@@ -151,6 +156,7 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("SMTP_FROM_NAME", "ЕГРЮЛ/ЕГРИП Мониторинг")
 	v.SetDefault("SMTP_TLS", false)
 	v.SetDefault("EMAIL_DRY_RUN", false)
+	v.SetDefault("APP_BASE_URL", "http://localhost:3000")
 
 	// Log
 	v.SetDefault("LOG_LEVEL", "info")
@@ -183,6 +189,10 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("kafka entrepreneur changes topic is required")
 	}
 
+	if c.Kafka.AccountEmailsTopic == "" {
+		return fmt.Errorf("kafka account emails topic is required")
+	}
+
 	if c.Kafka.ConsumerGroup == "" {
 		return fmt.Errorf("kafka consumer group is required")
 	}
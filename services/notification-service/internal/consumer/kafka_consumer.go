@@ -16,6 +16,7 @@ import (
 type KafkaConsumer struct {
 	companyReader      *kafka.Reader
 	entrepreneurReader *kafka.Reader
+	accountReader      *kafka.Reader
 	service            *service.NotificationService
 	logger             *zap.Logger
 }
@@ -25,6 +26,7 @@ type ConsumerConfig struct {
 	Brokers                  []string
 	CompanyTopic             string
 	EntrepreneurTopic        string
+	AccountEmailsTopic       string
 	GroupID                  string
 }
 
@@ -62,9 +64,28 @@ func NewKafkaConsumer(cfg ConsumerConfig, svc *service.NotificationService, logg
 		}),
 	})
 
+	// Письма по учетной записи читаются с начала топика: при первом запуске
+	// нельзя терять запросы, отправленные до старта сервиса
+	accountReader := kafka.NewReader(kafka.ReaderConfig{
+		Brokers:        cfg.Brokers,
+		Topic:          cfg.AccountEmailsTopic,
+		GroupID:        cfg.GroupID,
+		MinBytes:       1,
+		MaxBytes:       10e6,
+		CommitInterval: time.Second,
+		StartOffset:    kafka.FirstOffset,
+		Logger: kafka.LoggerFunc(func(msg string, args ...interface{}) {
+			logger.Debug(fmt.Sprintf(msg, args...))
+		}),
+		ErrorLogger: kafka.LoggerFunc(func(msg string, args ...interface{}) {
+			logger.Error(fmt.Sprintf(msg, args...))
+		}),
+	})
+
 	return &KafkaConsumer{
 		companyReader:      companyReader,
 		entrepreneurReader: entrepreneurReader,
+		accountReader:      accountReader,
 		service:            svc,
 		logger:             logger,
 	}
@@ -75,26 +96,36 @@ func (c *KafkaConsumer) Start(ctx context.Context) error {
 	c.logger.Info("Starting Kafka consumer",
 		zap.String("company_topic", c.companyReader.Config().Topic),
 		zap.String("entrepreneur_topic", c.entrepreneurReader.Config().Topic),
+		zap.String("account_emails_topic", c.accountReader.Config().Topic),
 		zap.String("group_id", c.companyReader.Config().GroupID),
 	)
 
-	// Запускаем обработку обоих топиков в отдельных горутинах
-	errChan := make(chan error, 2)
+	// Запускаем обработку топиков в отдельных горутинах
+	errChan := make(chan error, 3)
+
+	go func() {
+		errChan <- c.consumeTopic(ctx, c.companyReader, "company", c.processMessage)
+	}()
 
 	go func() {
-		errChan <- c.consumeTopic(ctx, c.companyReader, "company")
+		errChan <- c.consumeTopic(ctx, c.entrepreneurReader, "entrepreneur", c.processMessage)
 	}()
 
 	go func() {
-		errChan <- c.consumeTopic(ctx, c.entrepreneurReader, "entrepreneur")
+		errChan <- c.consumeTopic(ctx, c.accountReader, "account", c.processAccountMessage)
 	}()
 
 	// Ждем первую ошибку
 	return <-errChan
 }
 
-// consumeTopic обрабатывает сообщения из конкретного топика
-func (c *KafkaConsumer) consumeTopic(ctx context.Context, reader *kafka.Reader, entityType string) error {
+// consumeTopic обрабатывает сообщения из конкретного топика обработчиком handle
+func (c *KafkaConsumer) consumeTopic(
+	ctx context.Context,
+	reader *kafka.Reader,
+	entityType string,
+	handle func(context.Context, kafka.Message) error,
+) error {
 	c.logger.Info("Started consuming topic",
 		zap.String("topic", reader.Config().Topic),
 		zap.String("entity_type", entityType),
@@ -129,7 +160,7 @@ func (c *KafkaConsumer) consumeTopic(ctx context.Context, reader *kafka.Reader,
 			)
 
 			// Обрабатываем сообщение
-			if err := c.processMessage(ctx, msg); err != nil {
+			if err := handle(ctx, msg); err != nil {
 				c.logger.Error("failed to process message",
 					zap.String("topic", reader.Config().Topic),
 					zap.String("key", string(msg.Key)),
@@ -178,9 +209,23 @@ func (c *KafkaConsumer) processMessage(ctx context.Context, msg kafka.Message) e
 	return nil
 }
 
+// processAccountMessage обрабатывает событие письма по учетной записи
+func (c *KafkaConsumer) processAccountMessage(ctx context.Context, msg kafka.Message) error {
+	var event model.AccountEmailEvent
+	if err := json.Unmarshal(msg.Value, &event); err != nil {
+		return fmt.Errorf("failed to unmarshal account email event: %w", err)
+	}
+
+	if err := c.service.ProcessAccountEmail(ctx, &event); err != nil {
+		return fmt.Errorf("failed to process account email: %w", err)
+	}
+
+	return nil
+}
+
 // Close закрывает readers и освобождает ресурсы
 func (c *KafkaConsumer) Close() error {
-	var err1, err2 error
+	var err1, err2, err3 error
 
 	if err1 = c.companyReader.Close(); err1 != nil {
 		c.logger.Error("failed to close company reader", zap.Error(err1))
@@ -190,12 +235,19 @@ func (c *KafkaConsumer) Close() error {
 		c.logger.Error("failed to close entrepreneur reader", zap.Error(err2))
 	}
 
+	if err3 = c.accountReader.Close(); err3 != nil {
+		c.logger.Error("failed to close account emails reader", zap.Error(err3))
+	}
+
 	if err1 != nil {
 		return err1
 	}
 	if err2 != nil {
 		return err2
 	}
+	if err3 != nil {
+		return err3
+	}
 
 	c.logger.Info("Kafka consumer closed successfully")
 	return nil
@@ -205,6 +257,7 @@ func (c *KafkaConsumer) Close() error {
 func (c *KafkaConsumer) Stats() map[string]interface{} {
 	companyStats := c.companyReader.Stats()
 	entrepreneurStats := c.entrepreneurReader.Stats()
+	accountStats := c.accountReader.Stats()
 
 	return map[string]interface{}{
 		"company_consumer": map[string]interface{}{
@@ -219,5 +272,11 @@ func (c *KafkaConsumer) Stats() map[string]interface{} {
 			"bytes":    entrepreneurStats.Bytes,
 			"lag":      entrepreneurStats.Lag,
 		},
+		"account_emails_consumer": map[string]interface{}{
+			"topic":    c.accountReader.Config().Topic,
+			"messages": accountStats.Messages,
+			"bytes":    accountStats.Bytes,
+			"lag":      accountStats.Lag,
+		},
 	}
 }
@@ -0,0 +1,26 @@
+package model
+
+import "time"
+
+// Типы писем по учетной записи (публикует api-gateway)
+const (
+	AccountEmailVerification  = "email_verification"
+	AccountEmailPasswordReset = "password_reset"
+)
+
+// AccountEmailEvent событие письма по учетной записи (из Kafka): подтверждение
+// email или сброс пароля
+type AccountEmailEvent struct {
+	Type      string    `json:"type"`
+	Email     string    `json:"email"`
+	FirstName string    `json:"first_name"`
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// AccountEmailData данные для шаблона письма по учетной записи
+type AccountEmailData struct {
+	FirstName string
+	ActionURL string
+	ExpiresAt time.Time
+}
@@ -8,6 +8,7 @@ import (
 // EntitySubscription представляет подписку пользователя на изменения сущности
 type EntitySubscription struct {
 	ID                   string                 `json:"id"`
+	WorkspaceID          *string                `json:"workspace_id,omitempty"` // Подписка рабочего пространства
 	UserEmail            string                 `json:"user_email"`
 	EntityType           string                 `json:"entity_type"` // "company" или "entrepreneur"
 	EntityID             string                 `json:"entity_id"`   // OGRN или OGRNIP
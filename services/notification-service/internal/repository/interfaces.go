@@ -14,7 +14,7 @@ type SubscriptionRepository interface {
 	// GetByEmail получает все подписки пользователя
 	GetByEmail(ctx context.Context, email string) ([]*model.EntitySubscription, error)
 
-	// GetByEntity получает подписки на конкретную сущность
+	// GetByEntity получает подписки на конкретную сущность (только подтвержденные адреса)
 	GetByEntity(ctx context.Context, entityType, entityID string) ([]*model.EntitySubscription, error)
 
 	// Create создает новую подписку
@@ -41,6 +41,6 @@ type NotificationLogRepository interface {
 	// GetByChangeEvent получает уведомления для конкретного события
 	GetByChangeEvent(ctx context.Context, changeEventID string) ([]*model.Notification, error)
 
-	// CheckDuplicate проверяет, было ли уже отправлено уведомление для данного события и подписки
-	CheckDuplicate(ctx context.Context, subscriptionID, changeEventID string) (bool, error)
+	// CheckDuplicate проверяет, было ли уже отправлено уведомление получателю для данного события и подписки
+	CheckDuplicate(ctx context.Context, subscriptionID, changeEventID, recipient string) (bool, error)
 }
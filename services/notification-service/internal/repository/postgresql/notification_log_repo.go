@@ -216,17 +216,17 @@ func (r *NotificationLogRepository) GetByChangeEvent(ctx context.Context, change
 	return notifications, nil
 }
 
-// CheckDuplicate проверяет, было ли уже отправлено уведомление для данного события и подписки
-func (r *NotificationLogRepository) CheckDuplicate(ctx context.Context, subscriptionID, changeEventID string) (bool, error) {
+// CheckDuplicate проверяет, было ли уже отправлено уведомление получателю для данного события и подписки
+func (r *NotificationLogRepository) CheckDuplicate(ctx context.Context, subscriptionID, changeEventID, recipient string) (bool, error) {
 	query := fmt.Sprintf(`
 		SELECT EXISTS(
 			SELECT 1 FROM %s.notification_log
-			WHERE subscription_id = $1 AND change_event_id = $2 AND status = 'sent'
+			WHERE subscription_id = $1 AND change_event_id = $2 AND recipient = $3 AND status = 'sent'
 		)
 	`, r.schema)
 
 	var exists bool
-	err := r.db.QueryRowContext(ctx, query, subscriptionID, changeEventID).Scan(&exists)
+	err := r.db.QueryRowContext(ctx, query, subscriptionID, changeEventID, recipient).Scan(&exists)
 	if err != nil {
 		return false, fmt.Errorf("failed to check duplicate: %w", err)
 	}
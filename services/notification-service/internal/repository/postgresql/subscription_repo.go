@@ -150,15 +150,31 @@ func (r *SubscriptionRepository) GetByEmail(ctx context.Context, email string) (
 	return subscriptions, nil
 }
 
-// GetByEntity получает подписки на конкретную сущность
+// GetByEntity получает подписки на конкретную сущность. Возвращаются только
+// подписки активных пользователей с подтвержденным email; адрес берется из users.
+// Подписка рабочего пространства возвращается по одной записи на каждого
+// участника - с его адресом и каналами из workspace_members.
 func (r *SubscriptionRepository) GetByEntity(ctx context.Context, entityType, entityID string) ([]*model.EntitySubscription, error) {
 	query := fmt.Sprintf(`
 		SELECT
-			id, user_email, entity_type, entity_id, entity_name,
-			change_filters, notification_channels, is_active,
-			created_at, updated_at, last_notified_at
-		FROM %s.entity_subscriptions
-		WHERE entity_type = $1 AND entity_id = $2 AND is_active = true
+			s.id, s.workspace_id, u.email, s.entity_type, s.entity_id, s.entity_name,
+			s.change_filters, s.notification_channels, s.is_active,
+			s.created_at, s.updated_at, s.last_notified_at
+		FROM %[1]s.entity_subscriptions s
+		JOIN %[1]s.users u ON u.id = s.user_id
+		WHERE s.entity_type = $1 AND s.entity_id = $2 AND s.is_active = true
+			AND s.workspace_id IS NULL
+			AND u.is_active = true AND u.email_verified = true
+		UNION ALL
+		SELECT
+			s.id, s.workspace_id, u.email, s.entity_type, s.entity_id, s.entity_name,
+			s.change_filters, m.notification_channels, s.is_active,
+			s.created_at, s.updated_at, s.last_notified_at
+		FROM %[1]s.entity_subscriptions s
+		JOIN %[1]s.workspace_members m ON m.workspace_id = s.workspace_id
+		JOIN %[1]s.users u ON u.id = m.user_id
+		WHERE s.entity_type = $1 AND s.entity_id = $2 AND s.is_active = true
+			AND u.is_active = true AND u.email_verified = true
 		ORDER BY created_at DESC
 	`, r.schema)
 
@@ -174,9 +190,11 @@ func (r *SubscriptionRepository) GetByEntity(ctx context.Context, entityType, en
 		var sub model.EntitySubscription
 		var changeFiltersJSON, channelsJSON []byte
 		var lastNotifiedAt sql.NullTime
+		var workspaceID sql.NullString
 
 		err := rows.Scan(
 			&sub.ID,
+			&workspaceID,
 			&sub.UserEmail,
 			&sub.EntityType,
 			&sub.EntityID,
@@ -207,6 +225,9 @@ func (r *SubscriptionRepository) GetByEntity(ctx context.Context, entityType, en
 		if lastNotifiedAt.Valid {
 			sub.LastNotifiedAt = &lastNotifiedAt.Time
 		}
+		if workspaceID.Valid {
+			sub.WorkspaceID = &workspaceID.String
+		}
 
 		subscriptions = append(subscriptions, &sub)
 	}
@@ -16,6 +16,7 @@ type NotificationService struct {
 	subscriptionRepo      repository.SubscriptionRepository
 	notificationLogRepo   repository.NotificationLogRepository
 	channels              map[string]channels.NotificationChannel
+	accountEmails         channels.AccountEmailSender
 	logger                *zap.Logger
 }
 
@@ -24,6 +25,7 @@ func NewNotificationService(
 	subscriptionRepo repository.SubscriptionRepository,
 	notificationLogRepo repository.NotificationLogRepository,
 	emailChannel channels.NotificationChannel,
+	accountEmails channels.AccountEmailSender,
 	logger *zap.Logger,
 ) *NotificationService {
 	// Инициализируем map каналов
@@ -34,6 +36,7 @@ func NewNotificationService(
 		subscriptionRepo:    subscriptionRepo,
 		notificationLogRepo: notificationLogRepo,
 		channels:            channelsMap,
+		accountEmails:       accountEmails,
 		logger:              logger,
 	}
 }
@@ -92,6 +95,32 @@ func (s *NotificationService) ProcessChangeEvent(ctx context.Context, event *mod
 	return nil
 }
 
+// ProcessAccountEmail отправляет письмо по учетной записи (подтверждение email,
+// сброс пароля). Такие письма не журналируются: ссылка содержит одноразовый токен.
+func (s *NotificationService) ProcessAccountEmail(ctx context.Context, event *model.AccountEmailEvent) error {
+	if event.Email == "" {
+		return fmt.Errorf("account email event without recipient")
+	}
+
+	s.logger.Info("processing account email",
+		zap.String("type", event.Type),
+		zap.String("user_email", event.Email),
+	)
+
+	if time.Now().After(event.ExpiresAt) {
+		s.logger.Warn("account email token already expired, skipping",
+			zap.String("type", event.Type),
+			zap.String("user_email", event.Email),
+		)
+		return nil
+	}
+
+	if err := s.accountEmails.SendAccountEmail(ctx, event); err != nil {
+		return fmt.Errorf("failed to send account email: %w", err)
+	}
+	return nil
+}
+
 // processSubscription обрабатывает одну подписку
 func (s *NotificationService) processSubscription(
 	ctx context.Context,
@@ -108,7 +137,7 @@ func (s *NotificationService) processSubscription(
 	}
 
 	// Проверяем дубликаты (idempotency)
-	isDuplicate, err := s.notificationLogRepo.CheckDuplicate(ctx, subscription.ID, event.ChangeID)
+	isDuplicate, err := s.notificationLogRepo.CheckDuplicate(ctx, subscription.ID, event.ChangeID, subscription.UserEmail)
 	if err != nil {
 		return fmt.Errorf("failed to check duplicate: %w", err)
 	}
@@ -1,4 +1,4 @@
-package detector
+package diff
 
 import (
 	"math"
@@ -14,6 +14,9 @@ type Classifier struct {
 
 // NewClassifier создает новый экземпляр Classifier
 func NewClassifier(logger *zap.Logger) *Classifier {
+	if logger == nil {
+		logger = zap.NewNop()
+	}
 	return &Classifier{
 		logger: logger,
 	}
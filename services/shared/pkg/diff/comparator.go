@@ -0,0 +1,468 @@
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"go.uber.org/zap"
+)
+
+// Comparator отвечает за сравнение двух версий карточки ЮЛ или ИП
+type Comparator struct {
+	logger     *zap.Logger
+	classifier *Classifier
+}
+
+// NewComparator создает новый экземпляр Comparator
+func NewComparator(logger *zap.Logger, classifier *Classifier) *Comparator {
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	if classifier == nil {
+		classifier = NewClassifier(logger)
+	}
+	return &Comparator{
+		logger:     logger,
+		classifier: classifier,
+	}
+}
+
+// CompareCompany сравнивает старую и новую версии компании.
+// Изменения возвращаются в фиксированном порядке: статус, руководитель, учредители,
+// адрес, капитал, ОКВЭД, лицензии, филиалы; внутри множеств — в порядке исходных списков.
+func (c *Comparator) CompareCompany(old, new *Company) []Change {
+	if old == nil || new == nil {
+		return nil
+	}
+
+	var changes []Change
+
+	if change := c.compareStatus(old.Status, new.Status, ChangeTypeStatus, "Статус изменен"); change != nil {
+		changes = append(changes, *change)
+	}
+	if change := c.compareDirector(old, new); change != nil {
+		changes = append(changes, *change)
+	}
+	changes = append(changes, c.compareFounders(old.Founders, new.Founders)...)
+	if change := c.compareAddress(old.Address, new.Address, ChangeTypeAddress, "Адрес изменен"); change != nil {
+		changes = append(changes, *change)
+	}
+	if change := c.compareCapital(old, new); change != nil {
+		changes = append(changes, *change)
+	}
+	changes = append(changes, compareActivities(old.AdditionalOKVED, new.AdditionalOKVED,
+		ChangeTypeActivityAdded, ChangeTypeActivityRemoved, "Вид деятельности")...)
+	changes = append(changes, c.compareLicenses(old.Licenses, new.Licenses, old.LicensesCount, new.LicensesCount)...)
+	changes = append(changes, c.compareBranches(old, new)...)
+
+	c.logger.Debug("compared companies",
+		zap.String("ogrn", old.OGRN),
+		zap.Int("changes_count", len(changes)),
+	)
+
+	return changes
+}
+
+// CompareAttributes сравнивает реквизиты карточки компании, которые не порождают
+// событий изменений: наименования, КПП, основной ОКВЭД и email
+func (c *Comparator) CompareAttributes(old, new *Company) []Change {
+	if old == nil || new == nil {
+		return nil
+	}
+
+	attributes := []struct {
+		field, label, old, new string
+	}{
+		{"full_name", "Полное наименование", old.FullName, new.FullName},
+		{"short_name", "Сокращенное наименование", old.ShortName, new.ShortName},
+		{"kpp", "КПП", old.KPP, new.KPP},
+		{"main_okved", "Основной ОКВЭД", old.MainOKVED, new.MainOKVED},
+		{"email", "Email", old.Email, new.Email},
+	}
+
+	var changes []Change
+	for _, attr := range attributes {
+		if attr.old == attr.new {
+			continue
+		}
+		changes = append(changes, Change{
+			Type:        ChangeTypeAttribute,
+			Field:       attr.field,
+			OldValue:    marshal(attr.old),
+			NewValue:    marshal(attr.new),
+			Description: fmt.Sprintf("%s изменен: %s → %s", attr.label, attr.old, attr.new),
+		})
+	}
+
+	return changes
+}
+
+// CompareEntrepreneur сравнивает старую и новую версии ИП
+func (c *Comparator) CompareEntrepreneur(old, new *Entrepreneur) []Change {
+	if old == nil || new == nil {
+		return nil
+	}
+
+	var changes []Change
+
+	if change := c.compareStatus(old.Status, new.Status, ChangeTypeIPStatus, "Статус ИП изменен"); change != nil {
+		changes = append(changes, *change)
+	}
+	if change := c.compareAddress(old.Address, new.Address, ChangeTypeIPAddress, "Адрес ИП изменен"); change != nil {
+		changes = append(changes, *change)
+	}
+	changes = append(changes, compareActivities(old.AdditionalOKVED, new.AdditionalOKVED,
+		ChangeTypeIPActivity, ChangeTypeIPActivity, "Вид деятельности ИП")...)
+	changes = append(changes, c.compareIPLicenses(old, new)...)
+
+	c.logger.Debug("compared entrepreneurs",
+		zap.String("ogrnip", old.OGRNIP),
+		zap.Int("changes_count", len(changes)),
+	)
+
+	return changes
+}
+
+// compareStatus сравнивает статусы
+func (c *Comparator) compareStatus(oldStatus, newStatus string, changeType ChangeType, label string) *Change {
+	if oldStatus == newStatus {
+		return nil
+	}
+
+	return &Change{
+		Type:          changeType,
+		Field:         "status",
+		OldValue:      marshal(oldStatus),
+		NewValue:      marshal(newStatus),
+		IsSignificant: c.classifier.IsStatusChangeSignificant(oldStatus, newStatus),
+		Description:   fmt.Sprintf("%s: %s → %s", label, oldStatus, newStatus),
+	}
+}
+
+// compareDirector сравнивает руководителей
+func (c *Comparator) compareDirector(old, new *Company) *Change {
+	// Изменение произошло если изменилось ФИО или ИНН
+	if old.DirectorFullName == new.DirectorFullName && old.DirectorINN == new.DirectorINN {
+		return nil
+	}
+
+	return &Change{
+		Type:  ChangeTypeDirector,
+		Field: "director",
+		OldValue: marshal(map[string]string{
+			"full_name": old.DirectorFullName,
+			"inn":       old.DirectorINN,
+			"position":  old.DirectorPosition,
+		}),
+		NewValue: marshal(map[string]string{
+			"full_name": new.DirectorFullName,
+			"inn":       new.DirectorINN,
+			"position":  new.DirectorPosition,
+		}),
+		IsSignificant: true, // Смена руководителя всегда значима
+		Description:   fmt.Sprintf("Руководитель изменен: %s → %s", old.DirectorFullName, new.DirectorFullName),
+	}
+}
+
+// compareFounders сравнивает составы учредителей по ИНН (ОГРН)
+func (c *Comparator) compareFounders(oldFounders, newFounders []Founder) []Change {
+	var changes []Change
+
+	oldMap := make(map[string]*Founder, len(oldFounders))
+	newMap := make(map[string]*Founder, len(newFounders))
+	for i := range oldFounders {
+		oldMap[oldFounders[i].Key()] = &oldFounders[i]
+	}
+	for i := range newFounders {
+		newMap[newFounders[i].Key()] = &newFounders[i]
+	}
+
+	// Удаленные учредители
+	for i := range oldFounders {
+		founder := &oldFounders[i]
+		if _, exists := newMap[founder.Key()]; exists {
+			continue
+		}
+		share := founder.SharePercent
+		changes = append(changes, Change{
+			Type:          ChangeTypeFounderRemoved,
+			Field:         "founder_removed",
+			OldValue:      marshal(founder),
+			NewValue:      "null",
+			IsSignificant: true,
+			Description:   fmt.Sprintf("Учредитель удален: %s", founder.FullName),
+			Key:           founder.Key(),
+			Name:          founder.FullName,
+			OldShare:      &share,
+		})
+	}
+
+	// Новые учредители
+	for i := range newFounders {
+		founder := &newFounders[i]
+		if _, exists := oldMap[founder.Key()]; exists {
+			continue
+		}
+		share := founder.SharePercent
+		changes = append(changes, Change{
+			Type:          ChangeTypeFounderAdded,
+			Field:         "founder_added",
+			OldValue:      "null",
+			NewValue:      marshal(founder),
+			IsSignificant: true,
+			Description:   fmt.Sprintf("Учредитель добавлен: %s", founder.FullName),
+			Key:           founder.Key(),
+			Name:          founder.FullName,
+			NewShare:      &share,
+		})
+	}
+
+	// Изменение долей
+	for i := range oldFounders {
+		oldFounder := &oldFounders[i]
+		newFounder, ok := newMap[oldFounder.Key()]
+		if !ok || oldFounder.SharePercent == newFounder.SharePercent {
+			continue
+		}
+		oldShare, newShare := oldFounder.SharePercent, newFounder.SharePercent
+		changes = append(changes, Change{
+			Type:          ChangeTypeFounderShare,
+			Field:         "founder_share",
+			OldValue:      marshal(map[string]float64{"share_percent": oldShare}),
+			NewValue:      marshal(map[string]float64{"share_percent": newShare}),
+			IsSignificant: c.classifier.IsShareChangeSignificant(oldShare, newShare),
+			Description:   fmt.Sprintf("Доля учредителя %s изменена: %.2f%% → %.2f%%", oldFounder.FullName, oldShare, newShare),
+			Key:           oldFounder.Key(),
+			Name:          oldFounder.FullName,
+			OldShare:      &oldShare,
+			NewShare:      &newShare,
+		})
+	}
+
+	return changes
+}
+
+// compareAddress сравнивает адреса по полной строке адреса
+func (c *Comparator) compareAddress(old, new Address, changeType ChangeType, label string) *Change {
+	if old.Full == new.Full {
+		return nil
+	}
+
+	return &Change{
+		Type:          changeType,
+		Field:         "address",
+		OldValue:      marshal(old.toMap()),
+		NewValue:      marshal(new.toMap()),
+		IsSignificant: c.classifier.IsAddressChangeSignificant(old.Full, new.Full),
+		Description:   fmt.Sprintf("%s: %s → %s", label, old.Full, new.Full),
+	}
+}
+
+// compareCapital сравнивает уставный капитал
+func (c *Comparator) compareCapital(old, new *Company) *Change {
+	if old.AuthorizedCapital == new.AuthorizedCapital {
+		return nil
+	}
+
+	return &Change{
+		Type:          ChangeTypeCapital,
+		Field:         "authorized_capital",
+		OldValue:      marshal(map[string]interface{}{"amount": old.AuthorizedCapital, "currency": old.CapitalCurrency}),
+		NewValue:      marshal(map[string]interface{}{"amount": new.AuthorizedCapital, "currency": new.CapitalCurrency}),
+		IsSignificant: c.classifier.IsCapitalChangeSignificant(old.AuthorizedCapital, new.AuthorizedCapital),
+		Description:   fmt.Sprintf("Уставный капитал изменен: %.2f → %.2f", old.AuthorizedCapital, new.AuthorizedCapital),
+	}
+}
+
+// compareActivities сравнивает множества дополнительных ОКВЭД
+func compareActivities(oldCodes, newCodes []string, addedType, removedType ChangeType, label string) []Change {
+	var changes []Change
+
+	added, removed := diffStrings(oldCodes, newCodes)
+	for _, okved := range removed {
+		changes = append(changes, Change{
+			Type:        removedType,
+			Field:       "activity_removed",
+			OldValue:    okved,
+			Description: fmt.Sprintf("%s удален: %s", label, okved),
+			Key:         okved,
+		})
+	}
+	for _, okved := range added {
+		changes = append(changes, Change{
+			Type:        addedType,
+			Field:       "activity_added",
+			NewValue:    okved,
+			Description: fmt.Sprintf("%s добавлен: %s", label, okved),
+			Key:         okved,
+		})
+	}
+
+	return changes
+}
+
+// compareLicenses сравнивает лицензии компании: поэлементно, если известны
+// списки обеих версий, иначе по количеству
+func (c *Comparator) compareLicenses(oldItems, newItems []Item, oldCount, newCount int) []Change {
+	if oldItems != nil && newItems != nil {
+		return compareItems(oldItems, newItems, "license", ChangeTypeLicenseAdded, ChangeTypeLicenseRevoked,
+			true, "Лицензия добавлена", "Лицензия прекращена")
+	}
+	if oldCount == newCount {
+		return nil
+	}
+
+	changeType := ChangeTypeLicenseAdded
+	if newCount < oldCount {
+		changeType = ChangeTypeLicenseRevoked
+	}
+
+	return []Change{{
+		Type:          changeType,
+		Field:         "licenses_count",
+		OldValue:      marshal(oldCount),
+		NewValue:      marshal(newCount),
+		IsSignificant: true,
+		Description:   fmt.Sprintf("Количество лицензий изменено: %d → %d", oldCount, newCount),
+	}}
+}
+
+// compareIPLicenses сравнивает лицензии ИП
+func (c *Comparator) compareIPLicenses(old, new *Entrepreneur) []Change {
+	if old.Licenses != nil && new.Licenses != nil {
+		// Для ИП используется общий тип license_added
+		return compareItems(old.Licenses, new.Licenses, "license", ChangeTypeLicenseAdded, ChangeTypeLicenseAdded,
+			true, "Лицензия ИП добавлена", "Лицензия ИП прекращена")
+	}
+	if old.LicensesCount == new.LicensesCount {
+		return nil
+	}
+
+	return []Change{{
+		Type:          ChangeTypeLicenseAdded, // Используем общий тип для ИП
+		Field:         "licenses_count",
+		OldValue:      marshal(old.LicensesCount),
+		NewValue:      marshal(new.LicensesCount),
+		IsSignificant: true,
+		Description:   fmt.Sprintf("Количество лицензий ИП изменено: %d → %d", old.LicensesCount, new.LicensesCount),
+	}}
+}
+
+// compareBranches сравнивает филиалы и представительства компании
+func (c *Comparator) compareBranches(old, new *Company) []Change {
+	if old.Branches != nil && new.Branches != nil {
+		return compareItems(old.Branches, new.Branches, "branch", ChangeTypeBranchAdded, ChangeTypeBranchClosed,
+			false, "Филиал добавлен", "Филиал закрыт")
+	}
+	if old.BranchesCount == new.BranchesCount {
+		return nil
+	}
+
+	changeType := ChangeTypeBranchAdded
+	if new.BranchesCount < old.BranchesCount {
+		changeType = ChangeTypeBranchClosed
+	}
+
+	return []Change{{
+		Type:          changeType,
+		Field:         "branches_count",
+		OldValue:      marshal(old.BranchesCount),
+		NewValue:      marshal(new.BranchesCount),
+		IsSignificant: false,
+		Description:   fmt.Sprintf("Количество филиалов изменено: %d → %d", old.BranchesCount, new.BranchesCount),
+	}}
+}
+
+// compareItems поэлементно сравнивает множества лицензий или филиалов по ключу
+func compareItems(oldItems, newItems []Item, field string, addedType, removedType ChangeType, significant bool, addedLabel, removedLabel string) []Change {
+	var changes []Change
+
+	oldKeys := make([]string, len(oldItems))
+	newKeys := make([]string, len(newItems))
+	names := make(map[string]string, len(oldItems)+len(newItems))
+	for i, item := range oldItems {
+		oldKeys[i] = item.Key
+		names[item.Key] = item.Name
+	}
+	for i, item := range newItems {
+		newKeys[i] = item.Key
+		names[item.Key] = item.Name
+	}
+
+	added, removed := diffStrings(oldKeys, newKeys)
+	for _, key := range removed {
+		changes = append(changes, Change{
+			Type:          removedType,
+			Field:         field + "_removed",
+			OldValue:      key,
+			IsSignificant: significant,
+			Description:   fmt.Sprintf("%s: %s", removedLabel, itemTitle(key, names[key])),
+			Key:           key,
+			Name:          names[key],
+		})
+	}
+	for _, key := range added {
+		changes = append(changes, Change{
+			Type:          addedType,
+			Field:         field + "_added",
+			NewValue:      key,
+			IsSignificant: significant,
+			Description:   fmt.Sprintf("%s: %s", addedLabel, itemTitle(key, names[key])),
+			Key:           key,
+			Name:          names[key],
+		})
+	}
+
+	return changes
+}
+
+// diffStrings возвращает элементы, появившиеся в newValues и исчезнувшие из oldValues,
+// сохраняя порядок исходных списков и пропуская повторы
+func diffStrings(oldValues, newValues []string) (added, removed []string) {
+	oldSet := make(map[string]bool, len(oldValues))
+	newSet := make(map[string]bool, len(newValues))
+	for _, v := range oldValues {
+		oldSet[v] = true
+	}
+	for _, v := range newValues {
+		newSet[v] = true
+	}
+
+	seen := make(map[string]bool)
+	for _, v := range oldValues {
+		if !newSet[v] && !seen[v] {
+			removed = append(removed, v)
+			seen[v] = true
+		}
+	}
+	for _, v := range newValues {
+		if !oldSet[v] && !seen[v] {
+			added = append(added, v)
+			seen[v] = true
+		}
+	}
+	return added, removed
+}
+
+func itemTitle(key, name string) string {
+	if name == "" || name == key {
+		return key
+	}
+	return fmt.Sprintf("%s (%s)", name, key)
+}
+
+func (a Address) toMap() map[string]string {
+	return map[string]string{
+		"full":        a.Full,
+		"postal_code": a.PostalCode,
+		"region":      a.Region,
+		"city":        a.City,
+		"street":      a.Street,
+		"house":       a.House,
+	}
+}
+
+func marshal(v interface{}) string {
+	data, _ := json.Marshal(v)
+	return string(data)
+}
@@ -0,0 +1,67 @@
+package diff
+
+import "testing"
+
+func TestCompareCompany_FoundersAndCounts(t *testing.T) {
+	c := NewComparator(nil, nil)
+
+	old := &Company{
+		OGRN:          "1027700132195",
+		Status:        "ДЕЙСТВУЮЩАЯ",
+		Founders:      []Founder{{FullName: "Иванов", INN: "1", SharePercent: 60}, {FullName: "Петров", INN: "2", SharePercent: 40}},
+		LicensesCount: 1,
+	}
+	new := &Company{
+		OGRN:          "1027700132195",
+		Status:        "ДЕЙСТВУЮЩАЯ",
+		Founders:      []Founder{{FullName: "Иванов", INN: "1", SharePercent: 40}, {FullName: "Сидоров", INN: "3", SharePercent: 60}},
+		LicensesCount: 0,
+	}
+
+	changes := c.CompareCompany(old, new)
+
+	want := []ChangeType{ChangeTypeFounderRemoved, ChangeTypeFounderAdded, ChangeTypeFounderShare, ChangeTypeLicenseRevoked}
+	if len(changes) != len(want) {
+		t.Fatalf("expected %d changes, got %d: %+v", len(want), len(changes), changes)
+	}
+	for i, change := range changes {
+		if change.Type != want[i] {
+			t.Errorf("change %d: expected %s, got %s", i, want[i], change.Type)
+		}
+	}
+
+	share := changes[2]
+	if !share.IsSignificant || *share.OldShare != 60 || *share.NewShare != 40 {
+		t.Errorf("unexpected share change: %+v", share)
+	}
+	if changes[3].Field != "licenses_count" || changes[3].OldValue != "1" || changes[3].NewValue != "0" {
+		t.Errorf("unexpected licenses change: %+v", changes[3])
+	}
+}
+
+func TestCompareCompany_ItemSets(t *testing.T) {
+	c := NewComparator(nil, nil)
+
+	old := &Company{
+		AdditionalOKVED: []string{"62.01", "62.02"},
+		Branches:        []Item{{Key: "770101001", Name: "Филиал в Москве"}},
+	}
+	new := &Company{
+		AdditionalOKVED: []string{"62.02", "63.11"},
+		Branches:        []Item{},
+	}
+
+	changes := c.CompareCompany(old, new)
+	if len(changes) != 3 {
+		t.Fatalf("expected 3 changes, got %d: %+v", len(changes), changes)
+	}
+	if changes[0].Type != ChangeTypeActivityRemoved || changes[0].OldValue != "62.01" {
+		t.Errorf("unexpected change: %+v", changes[0])
+	}
+	if changes[1].Type != ChangeTypeActivityAdded || changes[1].NewValue != "63.11" {
+		t.Errorf("unexpected change: %+v", changes[1])
+	}
+	if changes[2].Type != ChangeTypeBranchClosed || changes[2].Key != "770101001" || changes[2].Name != "Филиал в Москве" {
+		t.Errorf("unexpected change: %+v", changes[2])
+	}
+}
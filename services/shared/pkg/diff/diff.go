@@ -0,0 +1,136 @@
+// Package diff содержит общую логику сравнения версий карточек ЮЛ и ИП.
+// Используется change-detection-service для формирования событий изменений
+// и API Gateway для построчного сравнения сохраненных версий, чтобы оба сервиса
+// одинаково определяли, что и насколько значимо изменилось.
+package diff
+
+// ChangeType тип изменения в данных компании/ИП
+type ChangeType string
+
+const (
+	// Изменения компаний
+	ChangeTypeStatus          ChangeType = "status"           // Изменение статуса (ликвидация, реорганизация)
+	ChangeTypeDirector        ChangeType = "director"         // Смена руководителя
+	ChangeTypeFounderAdded    ChangeType = "founder_added"    // Добавление учредителя
+	ChangeTypeFounderRemoved  ChangeType = "founder_removed"  // Удаление учредителя
+	ChangeTypeFounderShare    ChangeType = "founder_share"    // Изменение доли учредителя
+	ChangeTypeAddress         ChangeType = "address"          // Изменение адреса
+	ChangeTypeCapital         ChangeType = "capital"          // Изменение уставного капитала
+	ChangeTypeActivityAdded   ChangeType = "activity_added"   // Добавление вида деятельности (ОКВЭД)
+	ChangeTypeActivityRemoved ChangeType = "activity_removed" // Удаление вида деятельности
+	ChangeTypeLicenseAdded    ChangeType = "license_added"    // Добавление лицензии
+	ChangeTypeLicenseRevoked  ChangeType = "license_revoked"  // Отзыв лицензии
+	ChangeTypeBranchAdded     ChangeType = "branch_added"     // Добавление филиала
+	ChangeTypeBranchClosed    ChangeType = "branch_closed"    // Закрытие филиала
+
+	// Изменения ИП
+	ChangeTypeIPStatus   ChangeType = "ip_status"   // Изменение статуса ИП
+	ChangeTypeIPAddress  ChangeType = "ip_address"  // Изменение адреса ИП
+	ChangeTypeIPActivity ChangeType = "ip_activity" // Изменение вида деятельности ИП
+
+	// Изменение реквизита карточки (наименование, КПП, основной ОКВЭД, email).
+	// Возвращается только CompareAttributes и в поток событий не попадает.
+	ChangeTypeAttribute ChangeType = "attribute"
+)
+
+// Address адрес в объеме, достаточном для сравнения
+type Address struct {
+	Full       string
+	PostalCode string
+	Region     string
+	City       string
+	Street     string
+	House      string
+}
+
+// Founder учредитель компании
+type Founder struct {
+	FullName     string  `json:"full_name"`     // ФИО/Наименование учредителя
+	INN          string  `json:"inn"`           // ИНН учредителя
+	OGRN         string  `json:"ogrn"`          // ОГРН учредителя (если юр. лицо)
+	ShareAmount  float64 `json:"share_amount"`  // Размер доли (руб)
+	SharePercent float64 `json:"share_percent"` // Доля в процентах
+}
+
+// Key ключ учредителя для сопоставления версий: ИНН, а при его отсутствии ОГРН
+func (f *Founder) Key() string {
+	if f.INN != "" {
+		return f.INN
+	}
+	return f.OGRN
+}
+
+// Item элемент множества (лицензия, филиал) с ключом для сопоставления версий
+type Item struct {
+	Key  string
+	Name string
+}
+
+// Company версия карточки компании для сравнения
+type Company struct {
+	OGRN       string
+	INN        string
+	KPP        string
+	FullName   string
+	ShortName  string
+	RegionCode string
+	Status     string
+	Email      string
+	MainOKVED  string
+
+	DirectorFullName string
+	DirectorINN      string
+	DirectorPosition string
+
+	Address Address
+
+	AuthorizedCapital float64
+	CapitalCurrency   string
+
+	Founders        []Founder
+	AdditionalOKVED []string
+
+	// Если Licenses/Branches равны nil, сравниваются только количества;
+	// если заданы — поэлементно, с событием на каждую добавленную/удаленную запись
+	LicensesCount int
+	Licenses      []Item
+	BranchesCount int
+	Branches      []Item
+}
+
+// Entrepreneur версия карточки ИП для сравнения
+type Entrepreneur struct {
+	OGRNIP     string
+	INN        string
+	FullName   string
+	RegionCode string
+	Status     string
+
+	Address Address
+
+	AdditionalOKVED []string
+
+	LicensesCount int
+	Licenses      []Item
+}
+
+// Change одно изменение между двумя версиями
+type Change struct {
+	Type  ChangeType
+	Field string
+
+	// Старое и новое значения в сериализованном виде (JSON или код ОКВЭД)
+	OldValue string
+	NewValue string
+
+	IsSignificant bool
+	Description   string
+
+	// Key ключ элемента множества (ИНН/ОГРН учредителя, код ОКВЭД, номер лицензии, КПП филиала)
+	Key string
+	// Name отображаемое имя элемента множества
+	Name string
+	// OldShare/NewShare доли учредителя в процентах до и после изменения
+	OldShare *float64
+	NewShare *float64
+}
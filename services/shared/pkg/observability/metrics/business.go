@@ -17,6 +17,11 @@ var (
 		Help: "Total number of company data exports",
 	})
 
+	EntrepreneurExportsTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "egrul_entrepreneur_exports_total",
+		Help: "Total number of entrepreneur data exports",
+	})
+
 	GraphQLQueriesTotal = promauto.NewCounterVec(
 		prometheus.CounterOpts{
 			Name: "egrul_graphql_queries_total",
@@ -0,0 +1,35 @@
+// Package roles роли пользователей, общие для сервисов: api-gateway выдает
+// роль в JWT, остальные сервисы проверяют ее по той же иерархии.
+package roles
+
+// Роли пользователей. Роли упорядочены: старшая включает права младших.
+const (
+	User    = "user"
+	Analyst = "analyst"
+	Admin   = "admin"
+)
+
+var levels = map[string]int{
+	User:    1,
+	Analyst: 2,
+	Admin:   3,
+}
+
+// IsValid проверяет, что роль известна
+func IsValid(role string) bool {
+	_, ok := levels[role]
+	return ok
+}
+
+// HasRole проверяет, что роль не ниже требуемой. Пустая роль (токены,
+// выданные до появления ролей) считается ролью user.
+func HasRole(role, required string) bool {
+	if role == "" {
+		role = User
+	}
+	level, ok := levels[role]
+	if !ok {
+		return false
+	}
+	return level >= levels[required]
+}
@@ -99,6 +99,10 @@ func (r *Reader) ReadCompanies(ctx context.Context, batchSize int, offset int) (
 		return nil, fmt.Errorf("error iterating companies: %w", err)
 	}
 
+	if err := r.attachFounderNames(ctx, companies); err != nil {
+		return nil, err
+	}
+
 	return companies, nil
 }
 
@@ -144,8 +148,59 @@ func (r *Reader) ReadCompaniesUpdatedAfter(ctx context.Context, timestamp time.T
 		}
 		companies = append(companies, company)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating updated companies: %w", err)
+	}
+
+	if err := r.attachFounderNames(ctx, companies); err != nil {
+		return nil, err
+	}
+
+	return companies, nil
+}
+
+// attachFounderNames заполняет наименования/ФИО учредителей пачки компаний
+// (поле founder_names для поиска по учредителям)
+func (r *Reader) attachFounderNames(ctx context.Context, companies []mapper.CompanyRow) error {
+	if len(companies) == 0 {
+		return nil
+	}
 
-	return companies, rows.Err()
+	ogrns := make([]string, len(companies))
+	for i, c := range companies {
+		ogrns[i] = c.OGRN
+	}
+
+	query := `
+		SELECT company_ogrn, groupUniqArray(founder_name)
+		FROM egrul.founders FINAL
+		WHERE company_ogrn IN (?) AND founder_name != ''
+		GROUP BY company_ogrn
+	`
+
+	rows, err := r.conn.Query(ctx, query, ogrns)
+	if err != nil {
+		return fmt.Errorf("failed to query founder names: %w", err)
+	}
+	defer rows.Close()
+
+	names := make(map[string][]string, len(companies))
+	for rows.Next() {
+		var ogrn string
+		var founderNames []string
+		if err := rows.Scan(&ogrn, &founderNames); err != nil {
+			return fmt.Errorf("failed to scan founder names: %w", err)
+		}
+		names[ogrn] = founderNames
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterating founder names: %w", err)
+	}
+
+	for i := range companies {
+		companies[i].FounderNames = names[companies[i].OGRN]
+	}
+	return nil
 }
 
 // ReadEntrepreneurs читает предпринимателей из ClickHouse
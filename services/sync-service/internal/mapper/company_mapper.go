@@ -2,6 +2,7 @@ package mapper
 
 import (
 	"encoding/json"
+	"strings"
 	"time"
 )
 
@@ -32,6 +33,8 @@ type CompanyRow struct {
 	TerminationDate        time.Time `ch:"termination_date"`
 	CapitalAmount          *string   `ch:"capital_amount"`
 	UpdatedAt              time.Time `ch:"updated_at"`
+	// FounderNames наименования/ФИО учредителей, читаются отдельным запросом
+	FounderNames           []string
 }
 
 type CompanyDocument struct {
@@ -39,6 +42,7 @@ type CompanyDocument struct {
 	INN                  string    `json:"inn"`
 	KPP                  string    `json:"kpp,omitempty"`
 	FullName             string    `json:"full_name"`
+	NameSuggest          []string  `json:"name_suggest,omitempty"`
 	ShortName            string    `json:"short_name,omitempty"`
 	BrandName            string    `json:"brand_name,omitempty"`
 	Status               string    `json:"status"`
@@ -48,6 +52,7 @@ type CompanyDocument struct {
 	FullAddress          string    `json:"full_address,omitempty"`
 	Email                string    `json:"email,omitempty"`
 	OKVEDMainCode        string    `json:"okved_main_code,omitempty"`
+	OKVEDSection         string    `json:"okved_section,omitempty"`
 	OKVEDMainName        string    `json:"okved_main_name,omitempty"`
 	OKVEDAdditional      []string  `json:"okved_additional,omitempty"`
 	OKVEDAdditionalNames []string  `json:"okved_additional_names,omitempty"`
@@ -55,6 +60,7 @@ type CompanyDocument struct {
 	HeadFirstName        string    `json:"head_first_name,omitempty"`
 	HeadMiddleName       string    `json:"head_middle_name,omitempty"`
 	HeadINN              string    `json:"head_inn,omitempty"`
+	FounderNames         []string  `json:"founder_names,omitempty"`
 	OPFCode              string    `json:"opf_code,omitempty"`
 	OPFName         string    `json:"opf_name,omitempty"`
 	RegistrationDate     *time.Time `json:"registration_date,omitempty"`
@@ -69,6 +75,7 @@ func MapCompanyToES(row CompanyRow) ([]byte, error) {
 		INN:                  row.INN,
 		KPP:                  row.KPP,
 		FullName:             row.FullName,
+		NameSuggest:          companyNameSuggest(row),
 		ShortName:            row.ShortName,
 		BrandName:            row.BrandName,
 		Status:               row.Status,
@@ -78,6 +85,7 @@ func MapCompanyToES(row CompanyRow) ([]byte, error) {
 		FullAddress:          row.FullAddress,
 		Email:                row.Email,
 		OKVEDMainCode:        row.OKVEDMainCode,
+		OKVEDSection:         okvedSection(row.OKVEDMainCode),
 		OKVEDMainName:        row.OKVEDMainName,
 		OKVEDAdditional:      row.OKVEDAdditional,
 		OKVEDAdditionalNames: row.OKVEDAdditionalNames,
@@ -85,6 +93,7 @@ func MapCompanyToES(row CompanyRow) ([]byte, error) {
 		HeadFirstName:        row.HeadFirstName,
 		HeadMiddleName:       row.HeadMiddleName,
 		HeadINN:              row.HeadINN,
+		FounderNames:         row.FounderNames,
 		OPFCode:              row.OPFCode,
 		OPFName:         row.OPFName,
 		UpdatedAt:            row.UpdatedAt,
@@ -105,3 +114,19 @@ func MapCompanyToES(row CompanyRow) ([]byte, error) {
 
 	return json.Marshal(doc)
 }
+
+// companyNameSuggest варианты наименования для автодополнения (поле name_suggest):
+// краткое, полное и фирменное наименование без повторов
+func companyNameSuggest(row CompanyRow) []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, name := range []string{row.ShortName, row.FullName, row.BrandName} {
+		name = strings.TrimSpace(name)
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+	return names
+}
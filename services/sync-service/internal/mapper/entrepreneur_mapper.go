@@ -37,6 +37,7 @@ type EntrepreneurDocument struct {
 	FirstName            string     `json:"first_name"`
 	MiddleName           string     `json:"middle_name,omitempty"`
 	FullName             string     `json:"full_name"`
+	NameSuggest          []string   `json:"name_suggest,omitempty"`
 	Gender               string     `json:"gender,omitempty"`
 	CitizenshipType      string     `json:"citizenship_type,omitempty"`
 	Status               string     `json:"status"`
@@ -46,6 +47,7 @@ type EntrepreneurDocument struct {
 	FullAddress          string     `json:"full_address,omitempty"`
 	Email                string     `json:"email,omitempty"`
 	OKVEDMainCode        string     `json:"okved_main_code,omitempty"`
+	OKVEDSection         string     `json:"okved_section,omitempty"`
 	OKVEDMainName        string     `json:"okved_main_name,omitempty"`
 	OKVEDAdditional      []string   `json:"okved_additional,omitempty"`
 	OKVEDAdditionalNames []string   `json:"okved_additional_names,omitempty"`
@@ -69,6 +71,7 @@ func MapEntrepreneurToES(row EntrepreneurRow) ([]byte, error) {
 		FirstName:            row.FirstName,
 		MiddleName:           row.MiddleName,
 		FullName:             fullName,
+		NameSuggest:          []string{fullName},
 		Gender:               row.Gender,
 		CitizenshipType:      row.CitizenshipType,
 		Status:               row.Status,
@@ -78,6 +81,7 @@ func MapEntrepreneurToES(row EntrepreneurRow) ([]byte, error) {
 		FullAddress:          row.FullAddress,
 		Email:                row.Email,
 		OKVEDMainCode:        row.OKVEDMainCode,
+		OKVEDSection:         okvedSection(row.OKVEDMainCode),
 		OKVEDMainName:        row.OKVEDMainName,
 		OKVEDAdditional:      row.OKVEDAdditional,
 		OKVEDAdditionalNames: row.OKVEDAdditionalNames,
@@ -0,0 +1,32 @@
+package mapper
+
+import "strconv"
+
+// okvedSectionRanges разделы ОКВЭД2 по диапазонам классов (первые две цифры кода)
+var okvedSectionRanges = []struct {
+	code     string
+	from, to int
+}{
+	{"A", 1, 3}, {"B", 5, 9}, {"C", 10, 33}, {"D", 35, 35}, {"E", 36, 39},
+	{"F", 41, 43}, {"G", 45, 47}, {"H", 49, 53}, {"I", 55, 56}, {"J", 58, 63},
+	{"K", 64, 66}, {"L", 68, 68}, {"M", 69, 75}, {"N", 77, 82}, {"O", 84, 84},
+	{"P", 85, 85}, {"Q", 86, 88}, {"R", 90, 93}, {"S", 94, 96}, {"T", 97, 98},
+	{"U", 99, 99},
+}
+
+// okvedSection буква раздела ОКВЭД2 для кода ("62.01" -> "J"); пусто для некорректного кода
+func okvedSection(code string) string {
+	if len(code) < 2 {
+		return ""
+	}
+	class, err := strconv.Atoi(code[:2])
+	if err != nil {
+		return ""
+	}
+	for _, s := range okvedSectionRanges {
+		if class >= s.from && class <= s.to {
+			return s.code
+		}
+	}
+	return ""
+}